@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels/signal"
+	"github.com/spf13/cobra"
+)
+
+// newChannelCmd creates the `devclaw channel` command for channel-specific
+// maintenance flows (linking, pairing) that don't belong in `serve`.
+func newChannelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "channel",
+		Short: "Channel maintenance commands (linking, pairing)",
+	}
+
+	cmd.AddCommand(newChannelSignalCmd())
+	return cmd
+}
+
+// newChannelSignalCmd groups Signal-specific subcommands.
+func newChannelSignalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signal",
+		Short: "Signal channel commands",
+	}
+
+	link := &cobra.Command{
+		Use:   "link",
+		Short: "Link DevClaw as a new Signal device",
+		Long: `Links DevClaw to an existing Signal account via the signal-cli daemon.
+
+Requires a running signal-cli daemon (signal-cli daemon --tcp). Prints a
+device-link URI to scan with the Signal app (Settings → Linked Devices),
+then waits for the primary device to confirm.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, _, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			deviceName, _ := cmd.Flags().GetString("device-name")
+
+			sig := signal.New(cfg.Channels.Signal, nil)
+			ctx := context.Background()
+			if err := sig.Connect(ctx); err != nil {
+				return fmt.Errorf("connecting to signal-cli daemon: %w", err)
+			}
+			defer sig.Disconnect()
+
+			uri, err := sig.StartLink(ctx)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("Scan this link with the Signal app (Settings → Linked Devices):")
+			fmt.Println()
+			fmt.Println("  " + uri)
+			fmt.Println()
+			fmt.Println("Waiting for confirmation from the primary device...")
+
+			linkCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+			defer cancel()
+			if err := sig.FinishLink(linkCtx, uri, deviceName); err != nil {
+				return err
+			}
+
+			fmt.Println("Linked. Set channels.signal.account in config.yaml and restart `devclaw serve`.")
+			return nil
+		},
+	}
+	link.Flags().String("device-name", "DevClaw", "device name shown in Signal's linked devices list")
+
+	cmd.AddCommand(link)
+	return cmd
+}
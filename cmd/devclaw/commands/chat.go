@@ -41,6 +41,7 @@ Examples:
 	}
 
 	cmd.Flags().StringP("model", "m", "", "override the LLM model")
+	cmd.Flags().Bool("tui", false, "use the full-screen TUI (streaming, tool panes, history search)")
 	return cmd
 }
 
@@ -116,6 +117,11 @@ func runChat(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// ── Full-screen TUI mode ──
+	if tui, _ := cmd.Flags().GetBool("tui"); tui {
+		return runChatTUI(assistant, cfg)
+	}
+
 	// ── Interactive REPL mode ──
 	return runInteractiveChat(assistant, cfg)
 }
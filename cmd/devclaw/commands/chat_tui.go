@@ -0,0 +1,611 @@
+// Package commands – chat_tui.go implements the full-screen bubbletea chat UI
+// (`devclaw chat --tui`): streamed tokens, collapsible tool-call panes,
+// session switching, fuzzy history search, and inline tool approvals.
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot"
+)
+
+// ── Styles ──
+
+var (
+	tuiUserStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Bold(true)
+	tuiBotStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true)
+	tuiToolStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	tuiErrorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	tuiDimStyle       = lipgloss.NewStyle().Faint(true)
+	tuiStatusStyle    = lipgloss.NewStyle().Faint(true)
+	tuiSelectedStyle  = lipgloss.NewStyle().Reverse(true)
+	tuiToolPaneIndent = "    "
+)
+
+// tuiMode selects which interaction the keyboard drives.
+type tuiMode int
+
+const (
+	tuiModeChat tuiMode = iota
+	tuiModeSearch
+	tuiModeSessions
+	tuiModeApproval
+)
+
+// ── Messages from the agent goroutine ──
+
+type tuiDeltaMsg struct{ content string }
+
+type tuiToolUseMsg struct {
+	name string
+	args string
+}
+
+type tuiToolResultMsg struct {
+	name    string
+	output  string
+	isError bool
+}
+
+type tuiRunDoneMsg struct{ response string }
+
+type tuiApprovalMsg struct {
+	tool  string
+	args  map[string]any
+	reply chan bool
+}
+
+// ── Transcript entries ──
+
+type tuiEntryKind int
+
+const (
+	tuiEntryUser tuiEntryKind = iota
+	tuiEntryAssistant
+	tuiEntryTool
+)
+
+// tuiEntry is one block in the transcript. Tool entries carry the call args
+// and (once available) the result, and can be expanded/collapsed.
+type tuiEntry struct {
+	kind     tuiEntryKind
+	text     string // user/assistant text
+	tool     string
+	args     string
+	result   string
+	isError  bool
+	done     bool // tool finished
+	expanded bool
+}
+
+// tuiModel is the bubbletea model for the chat TUI.
+type tuiModel struct {
+	assistant *copilot.Assistant
+	cfg       *copilot.Config
+	session   *copilot.Session
+
+	viewport viewport.Model
+	input    textinput.Model
+	ready    bool
+	width    int
+	height   int
+
+	mode    tuiMode
+	busy    bool
+	entries []tuiEntry
+
+	// events carries messages from the agent goroutine into Update.
+	events chan tea.Msg
+
+	// streamBuf accumulates deltas for the in-flight assistant entry.
+	streamBuf strings.Builder
+
+	// Search state.
+	searchResults []string
+
+	// Session switcher state.
+	sessions   []copilot.SessionMeta
+	sessionSel int
+
+	// Pending tool approval.
+	approval *tuiApprovalMsg
+
+	err error
+}
+
+// runChatTUI starts the full-screen chat interface.
+func runChatTUI(assistant *copilot.Assistant, cfg *copilot.Config) error {
+	events := make(chan tea.Msg, 256)
+
+	// Route tool confirmations through the TUI instead of auto-approving.
+	assistant.ToolExecutor().SetConfirmationRequester(func(sessionID, callerJID, toolName string, args map[string]any) (bool, error) {
+		reply := make(chan bool, 1)
+		events <- tuiApprovalMsg{tool: toolName, args: args, reply: reply}
+		select {
+		case ok := <-reply:
+			return ok, nil
+		case <-time.After(2 * time.Minute):
+			return false, fmt.Errorf("tool approval timed out")
+		}
+	})
+
+	input := textinput.New()
+	input.Placeholder = "Type a message… (Ctrl+F search · Ctrl+S sessions · Ctrl+O tool details · Ctrl+C quit)"
+	input.Prompt = tuiUserStyle.Render("you> ")
+	input.Focus()
+	input.CharLimit = 0
+
+	m := tuiModel{
+		assistant: assistant,
+		cfg:       cfg,
+		session:   assistant.SessionStore().GetOrCreate("cli", "terminal"),
+		input:     input,
+		events:    events,
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// waitEvent blocks until the agent goroutine produces the next event.
+func (m tuiModel) waitEvent() tea.Cmd {
+	return func() tea.Msg { return <-m.events }
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.waitEvent())
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		vpHeight := msg.Height - 3
+		if vpHeight < 1 {
+			vpHeight = 1
+		}
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, vpHeight)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = vpHeight
+		}
+		m.refreshViewport(true)
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case tuiDeltaMsg:
+		m.streamBuf.WriteString(msg.content)
+		m.syncStreamEntry()
+		m.refreshViewport(true)
+		return m, m.waitEvent()
+
+	case tuiToolUseMsg:
+		m.entries = append(m.entries, tuiEntry{kind: tuiEntryTool, tool: msg.name, args: msg.args})
+		m.refreshViewport(true)
+		return m, m.waitEvent()
+
+	case tuiToolResultMsg:
+		// Attach the result to the newest unfinished pane for this tool.
+		for i := len(m.entries) - 1; i >= 0; i-- {
+			e := &m.entries[i]
+			if e.kind == tuiEntryTool && e.tool == msg.name && !e.done {
+				e.result = msg.output
+				e.isError = msg.isError
+				e.done = true
+				break
+			}
+		}
+		m.refreshViewport(true)
+		return m, m.waitEvent()
+
+	case tuiRunDoneMsg:
+		// Replace the streamed buffer with the final sanitized response —
+		// deltas may include internal tags the agent strips from the result.
+		m.streamBuf.Reset()
+		m.setFinalAssistantText(copilot.StripInternalTags(msg.response))
+		m.busy = false
+		m.refreshViewport(true)
+		return m, m.waitEvent()
+
+	case tuiApprovalMsg:
+		m.approval = &msg
+		m.mode = tuiModeApproval
+		m.refreshViewport(true)
+		return m, m.waitEvent()
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// handleKey dispatches keyboard input according to the active mode.
+func (m tuiModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// Global keys.
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case tuiModeApproval:
+		return m.handleApprovalKey(key)
+	case tuiModeSessions:
+		return m.handleSessionsKey(key)
+	case tuiModeSearch:
+		return m.handleSearchKey(msg)
+	}
+
+	// Chat mode.
+	switch key {
+	case "esc":
+		m.input.SetValue("")
+		return m, nil
+
+	case "ctrl+o":
+		// Toggle expansion of all tool panes.
+		anyCollapsed := false
+		for i := range m.entries {
+			if m.entries[i].kind == tuiEntryTool && !m.entries[i].expanded {
+				anyCollapsed = true
+			}
+		}
+		for i := range m.entries {
+			if m.entries[i].kind == tuiEntryTool {
+				m.entries[i].expanded = anyCollapsed
+			}
+		}
+		m.refreshViewport(false)
+		return m, nil
+
+	case "ctrl+s":
+		m.sessions = m.assistant.SessionStore().ListSessions()
+		sort.Slice(m.sessions, func(i, j int) bool {
+			return m.sessions[i].LastActiveAt.After(m.sessions[j].LastActiveAt)
+		})
+		m.sessionSel = 0
+		m.mode = tuiModeSessions
+		return m, nil
+
+	case "ctrl+f":
+		m.mode = tuiModeSearch
+		m.searchResults = nil
+		m.input.SetValue("")
+		m.input.Placeholder = "Search memory… (Enter to search, Esc to cancel)"
+		return m, nil
+
+	case "up", "down", "pgup", "pgdown":
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+
+	case "enter":
+		text := strings.TrimSpace(m.input.Value())
+		if text == "" || m.busy {
+			return m, nil
+		}
+		m.input.SetValue("")
+		m.entries = append(m.entries, tuiEntry{kind: tuiEntryUser, text: text})
+		m.busy = true
+		m.streamBuf.Reset()
+		m.refreshViewport(true)
+		m.startAgentRun(text)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// handleApprovalKey resolves a pending tool confirmation with y/n.
+func (m tuiModel) handleApprovalKey(key string) (tea.Model, tea.Cmd) {
+	if m.approval == nil {
+		m.mode = tuiModeChat
+		return m, nil
+	}
+	switch key {
+	case "y", "Y", "enter":
+		m.approval.reply <- true
+	case "n", "N", "esc":
+		m.approval.reply <- false
+	default:
+		return m, nil
+	}
+	m.approval = nil
+	m.mode = tuiModeChat
+	m.refreshViewport(false)
+	return m, nil
+}
+
+// handleSessionsKey navigates and picks from the session switcher.
+func (m tuiModel) handleSessionsKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "esc":
+		m.mode = tuiModeChat
+	case "up", "k":
+		if m.sessionSel > 0 {
+			m.sessionSel--
+		}
+	case "down", "j":
+		if m.sessionSel < len(m.sessions)-1 {
+			m.sessionSel++
+		}
+	case "enter":
+		if m.sessionSel < len(m.sessions) {
+			meta := m.sessions[m.sessionSel]
+			m.session = m.assistant.SessionStore().GetOrCreate(meta.Channel, meta.ChatID)
+			m.entries = append(m.entries, tuiEntry{
+				kind: tuiEntryAssistant,
+				text: tuiDimStyle.Render(fmt.Sprintf("[switched to session %s]", meta.ID)),
+			})
+		}
+		m.mode = tuiModeChat
+		m.refreshViewport(true)
+	}
+	return m, nil
+}
+
+// handleSearchKey drives the memory search prompt.
+func (m tuiModel) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = tuiModeChat
+		m.searchResults = nil
+		m.input.SetValue("")
+		m.input.Placeholder = "Type a message… (Ctrl+F search · Ctrl+S sessions · Ctrl+O tool details · Ctrl+C quit)"
+		m.refreshViewport(false)
+		return m, nil
+	case "enter":
+		query := strings.TrimSpace(m.input.Value())
+		if query == "" {
+			return m, nil
+		}
+		m.searchResults = m.runMemorySearch(query)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// runMemorySearch queries the SQLite FTS5 memory index.
+func (m *tuiModel) runMemorySearch(query string) []string {
+	store := m.assistant.SQLiteMemory()
+	if store == nil {
+		return []string{tuiDimStyle.Render("(SQLite memory is not enabled)")}
+	}
+	results, err := store.SearchBM25(query, 10)
+	if err != nil {
+		return []string{tuiErrorStyle.Render("search failed: " + err.Error())}
+	}
+	if len(results) == 0 {
+		return []string{tuiDimStyle.Render("(no matches)")}
+	}
+	out := make([]string, 0, len(results))
+	for _, r := range results {
+		text := strings.ReplaceAll(r.Text, "\n", " ")
+		if len(text) > 120 {
+			text = text[:120] + "…"
+		}
+		out = append(out, fmt.Sprintf("%s %s", tuiToolStyle.Render(r.FileID+":"), text))
+	}
+	return out
+}
+
+// startAgentRun launches the agent loop in a goroutine, forwarding progress
+// into the events channel for Update to consume.
+func (m *tuiModel) startAgentRun(text string) {
+	assistant := m.assistant
+	session := m.session
+	events := m.events
+	go func() {
+		prompt := assistant.ComposePrompt(session, text)
+		response, err := assistant.ExecuteAgentStream(context.Background(), prompt, session, text, func(ev copilot.AgentEvent) {
+			switch ev.Type {
+			case "delta":
+				if d, ok := ev.Data.(map[string]string); ok {
+					events <- tuiDeltaMsg{content: d["content"]}
+				}
+			case "tool_use":
+				if d, ok := ev.Data.(map[string]any); ok {
+					name, _ := d["tool"].(string)
+					args, _ := d["input"].(string)
+					events <- tuiToolUseMsg{name: name, args: args}
+				}
+			case "tool_result":
+				if d, ok := ev.Data.(map[string]any); ok {
+					name, _ := d["tool"].(string)
+					output, _ := d["output"].(string)
+					isErr, _ := d["is_error"].(bool)
+					events <- tuiToolResultMsg{name: name, output: output, isError: isErr}
+				}
+			}
+		})
+		if err != nil {
+			events <- tuiRunDoneMsg{response: fmt.Sprintf("Sorry, I encountered an error: %v", err)}
+			return
+		}
+		session.AddMessage(text, response)
+		events <- tuiRunDoneMsg{response: response}
+	}()
+}
+
+// syncStreamEntry mirrors the stream buffer into the trailing assistant entry,
+// creating it on the first delta.
+func (m *tuiModel) syncStreamEntry() {
+	if n := len(m.entries); n > 0 && m.entries[n-1].kind == tuiEntryAssistant && m.busy {
+		m.entries[n-1].text = m.streamBuf.String()
+		return
+	}
+	m.entries = append(m.entries, tuiEntry{kind: tuiEntryAssistant, text: m.streamBuf.String()})
+}
+
+// setFinalAssistantText writes the final response over the streamed entry.
+func (m *tuiModel) setFinalAssistantText(text string) {
+	if n := len(m.entries); n > 0 && m.entries[n-1].kind == tuiEntryAssistant {
+		m.entries[n-1].text = text
+		return
+	}
+	m.entries = append(m.entries, tuiEntry{kind: tuiEntryAssistant, text: text})
+}
+
+// refreshViewport re-renders the transcript into the viewport.
+func (m *tuiModel) refreshViewport(gotoBottom bool) {
+	if !m.ready {
+		return
+	}
+	m.viewport.SetContent(m.renderTranscript())
+	if gotoBottom {
+		m.viewport.GotoBottom()
+	}
+}
+
+// renderTranscript builds the full transcript text.
+func (m *tuiModel) renderTranscript() string {
+	var b strings.Builder
+	for _, e := range m.entries {
+		switch e.kind {
+		case tuiEntryUser:
+			b.WriteString(tuiUserStyle.Render("you> ") + e.text + "\n\n")
+		case tuiEntryAssistant:
+			b.WriteString(tuiBotStyle.Render(m.cfg.Name+"> ") + e.text + "\n\n")
+		case tuiEntryTool:
+			b.WriteString(m.renderToolPane(e))
+		}
+	}
+	return b.String()
+}
+
+// renderToolPane renders one collapsible tool-call pane.
+func (m *tuiModel) renderToolPane(e tuiEntry) string {
+	marker := "▸"
+	status := tuiDimStyle.Render("running…")
+	if e.done {
+		status = tuiDimStyle.Render("done")
+		if e.isError {
+			status = tuiErrorStyle.Render("error")
+		}
+	}
+	if e.expanded {
+		marker = "▾"
+	}
+	header := fmt.Sprintf("%s %s %s %s\n", marker, tuiToolStyle.Render("tool:"), e.tool, status)
+	if !e.expanded {
+		return header + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	if args := strings.TrimSpace(e.args); args != "" && args != "{}" {
+		b.WriteString(tuiToolPaneIndent + tuiDimStyle.Render("args: ") + truncateForPane(args) + "\n")
+	}
+	if e.done {
+		label := "result: "
+		style := tuiDimStyle
+		if e.isError {
+			label = "error: "
+			style = tuiErrorStyle
+		}
+		b.WriteString(tuiToolPaneIndent + style.Render(label) + truncateForPane(e.result) + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// truncateForPane flattens and trims long tool args/results for display.
+func truncateForPane(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ⏎ ")
+	if len(s) > 500 {
+		return s[:500] + "…"
+	}
+	return s
+}
+
+func (m tuiModel) View() string {
+	if !m.ready {
+		return "loading…"
+	}
+
+	switch m.mode {
+	case tuiModeSessions:
+		return m.viewSessions()
+	case tuiModeSearch:
+		return m.viewSearch()
+	case tuiModeApproval:
+		return m.viewApproval()
+	}
+
+	status := tuiStatusStyle.Render(fmt.Sprintf(" %s · session %s · Ctrl+O tool details · Ctrl+F search · Ctrl+S sessions",
+		m.cfg.Name, m.session.ID))
+	if m.busy {
+		status = tuiStatusStyle.Render(" thinking…")
+	}
+	return m.viewport.View() + "\n" + m.input.View() + "\n" + status
+}
+
+// viewSessions renders the session switcher overlay.
+func (m tuiModel) viewSessions() string {
+	var b strings.Builder
+	b.WriteString(tuiBotStyle.Render("Sessions") + tuiDimStyle.Render("  (↑/↓ move · Enter switch · Esc cancel)") + "\n\n")
+	if len(m.sessions) == 0 {
+		b.WriteString(tuiDimStyle.Render("  (no sessions)") + "\n")
+	}
+	for i, s := range m.sessions {
+		line := fmt.Sprintf("  %-30s %4d msgs  last active %s", s.ID, s.MessageCount, s.LastActiveAt.Format("2006-01-02 15:04"))
+		if i == m.sessionSel {
+			line = tuiSelectedStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// viewSearch renders the memory search prompt and results.
+func (m tuiModel) viewSearch() string {
+	var b strings.Builder
+	b.WriteString(tuiBotStyle.Render("Memory search") + tuiDimStyle.Render("  (Enter to search · Esc to close)") + "\n\n")
+	b.WriteString(m.input.View() + "\n\n")
+	for _, r := range m.searchResults {
+		b.WriteString("  " + r + "\n")
+	}
+	return b.String()
+}
+
+// viewApproval renders the pending tool confirmation prompt.
+func (m tuiModel) viewApproval() string {
+	var b strings.Builder
+	b.WriteString(m.viewport.View() + "\n")
+	argsPreview := ""
+	if m.approval != nil {
+		parts := make([]string, 0, len(m.approval.args))
+		for k, v := range m.approval.args {
+			parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+		}
+		sort.Strings(parts)
+		argsPreview = truncateForPane(strings.Join(parts, " "))
+	}
+	tool := ""
+	if m.approval != nil {
+		tool = m.approval.tool
+	}
+	b.WriteString(tuiErrorStyle.Render(fmt.Sprintf(" ⚠ approve tool %q? ", tool)) + tuiDimStyle.Render(argsPreview) + "\n")
+	b.WriteString(tuiStatusStyle.Render(" [y] approve · [n] deny"))
+	return b.String()
+}
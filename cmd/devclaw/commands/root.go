@@ -24,6 +24,7 @@ Examples:
 	// Registra subcomandos.
 	rootCmd.AddCommand(
 		newChatCmd(),
+		newRunCmd(),
 		newServeCmd(),
 		newSetupCmd(),
 		newScheduleCmd(),
@@ -39,6 +40,8 @@ Examples:
 		newCommitCmd(),
 		newHowCmd(),
 		newShellHookCmd(),
+		newChannelCmd(),
+		newTrafficCmd(),
 		newMCPCmd(),
 		NewOAuthCommand(),
 	)
@@ -0,0 +1,233 @@
+// Package commands – run.go implements the `devclaw run` command: a single
+// headless agent run for shell scripts and CI pipelines.
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot"
+	"github.com/spf13/cobra"
+)
+
+// runToolTrace is one tool invocation in the JSON output.
+type runToolTrace struct {
+	Tool    string `json:"tool"`
+	Input   string `json:"input,omitempty"`
+	Output  string `json:"output,omitempty"`
+	IsError bool   `json:"is_error,omitempty"`
+}
+
+// runOutput is the JSON document printed with --json.
+type runOutput struct {
+	Response  string         `json:"response"`
+	Error     string         `json:"error,omitempty"`
+	ToolTrace []runToolTrace `json:"tool_trace"`
+	Usage     runUsage       `json:"usage"`
+	DurationS float64        `json:"duration_seconds"`
+}
+
+// runUsage reports token consumption for the run.
+type runUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// newRunCmd creates the `devclaw run` command.
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <prompt>",
+		Short: "Execute a single agent run headlessly (for scripts and CI)",
+		Long: `Run one agent turn without an interactive session and print the result.
+
+Designed for shell scripts and CI pipelines: no REPL, no colors, deterministic
+exit codes. Piped stdin is appended to the prompt as fenced context.
+
+Exit codes:
+  0  run completed
+  1  configuration or startup error
+  2  agent run failed
+
+Examples:
+  devclaw run "Summarize the failing tests" < test.log
+  devclaw run --json --tool-profile minimal "What changed in HEAD?"
+  devclaw run --model gpt-4o --max-turns 5 "Review this diff" < pr.diff`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRun,
+	}
+
+	cmd.Flags().StringP("model", "m", "", "override the LLM model")
+	cmd.Flags().StringP("workspace", "w", "headless", "session workspace name (isolates history)")
+	cmd.Flags().String("tool-profile", "", "restrict tools to a profile (minimal, coding, messaging, team, full, or custom)")
+	cmd.Flags().Int("max-turns", 0, "limit LLM round-trips (0 = config default)")
+	cmd.Flags().Bool("json", false, "print JSON with response, tool trace, and usage")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "abort the run after this duration")
+	return cmd
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	// ── Load config ──
+	cfg, _, err := resolveConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	if model, _ := cmd.Flags().GetString("model"); model != "" {
+		cfg.Model = model
+	}
+	if maxTurns, _ := cmd.Flags().GetInt("max-turns"); maxTurns > 0 {
+		cfg.Agent.MaxTurns = maxTurns
+	}
+
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	workspace, _ := cmd.Flags().GetString("workspace")
+	profileName, _ := cmd.Flags().GetString("tool-profile")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	// ── Configure logger (errors only — stdout is the result) ──
+	verbose, _ := cmd.Root().PersistentFlags().GetBool("verbose")
+	logLevel := slog.LevelError
+	if verbose {
+		logLevel = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+	// ── Resolve secrets ──
+	copilot.AuditSecrets(cfg, logger)
+	vault := copilot.ResolveAPIKey(cfg, logger)
+
+	if cfg.API.APIKey == "" || copilot.IsEnvReference(cfg.API.APIKey) {
+		return fmt.Errorf("no API key configured. Run: devclaw config vault-set")
+	}
+
+	// ── Resolve tool profile before starting anything ──
+	var profile *copilot.ToolProfile
+	if profileName != "" {
+		profile = copilot.GetProfile(profileName, cfg.Security.ToolGuard.CustomProfiles)
+		if profile == nil {
+			return fmt.Errorf("unknown tool profile %q (available: %s)",
+				profileName, strings.Join(copilot.ListProfiles(cfg.Security.ToolGuard.CustomProfiles), ", "))
+		}
+	}
+
+	// ── Build the prompt (append piped stdin as fenced context) ──
+	message := args[0]
+	if stdinInfo, _ := os.Stdin.Stat(); (stdinInfo.Mode() & os.ModeCharDevice) == 0 {
+		stdinData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		if content := strings.TrimSpace(string(stdinData)); content != "" {
+			message = fmt.Sprintf("%s\n\n```\n%s\n```", message, content)
+		}
+	}
+
+	// Run in a helper so the assistant's deferred Stop executes before the
+	// explicit exit-code-2 path below.
+	runErr := executeHeadlessRun(cfg, vault, logger, profile, message, workspace, jsonOut, timeout)
+	if runErr != nil {
+		// Distinguish run failures from config errors for scripting.
+		os.Exit(2)
+	}
+	return nil
+}
+
+// executeHeadlessRun starts the assistant, performs one agent run, and prints
+// the result. Returns the run error (nil on success); startup errors are
+// printed and also returned so the caller exits non-zero.
+func executeHeadlessRun(cfg *copilot.Config, vault *copilot.Vault, logger *slog.Logger, profile *copilot.ToolProfile, message, workspace string, jsonOut bool, timeout time.Duration) error {
+	// ── Create and start assistant ──
+	assistant := copilot.New(cfg, logger)
+	if vault != nil {
+		assistant.SetVault(vault)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := assistant.Start(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start assistant: %v\n", err)
+		return err
+	}
+	defer assistant.Stop()
+
+	runCtx := ctx
+	if profile != nil {
+		runCtx = copilot.ContextWithToolProfile(ctx, profile)
+	}
+
+	// ── Execute the run, collecting the tool trace ──
+	session := assistant.SessionStore().GetOrCreate("cli", "run-"+workspace)
+	prompt := assistant.ComposePrompt(session, message)
+
+	var trace []runToolTrace
+	start := time.Now()
+	response, runErr := assistant.ExecuteAgentStream(runCtx, prompt, session, message, func(ev copilot.AgentEvent) {
+		switch ev.Type {
+		case "tool_use":
+			if d, ok := ev.Data.(map[string]any); ok {
+				name, _ := d["tool"].(string)
+				input, _ := d["input"].(string)
+				trace = append(trace, runToolTrace{Tool: name, Input: input})
+			}
+		case "tool_result":
+			if d, ok := ev.Data.(map[string]any); ok {
+				name, _ := d["tool"].(string)
+				// Attach to the newest traced call without a result yet.
+				for i := len(trace) - 1; i >= 0; i-- {
+					if trace[i].Tool == name && trace[i].Output == "" && !trace[i].IsError {
+						trace[i].Output, _ = d["output"].(string)
+						trace[i].IsError, _ = d["is_error"].(bool)
+						break
+					}
+				}
+			}
+		}
+	})
+	duration := time.Since(start)
+
+	if runErr == nil {
+		session.AddMessage(message, response)
+	}
+
+	// ── Emit result ──
+	if jsonOut {
+		pu, cu, _ := session.GetTokenUsage()
+		out := runOutput{
+			Response:  copilot.StripInternalTags(response),
+			ToolTrace: trace,
+			Usage:     runUsage{PromptTokens: pu, CompletionTokens: cu, TotalTokens: pu + cu},
+			DurationS: duration.Seconds(),
+		}
+		if runErr != nil {
+			out.Error = runErr.Error()
+		}
+		if out.ToolTrace == nil {
+			out.ToolTrace = []runToolTrace{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(out); err != nil {
+			return fmt.Errorf("encoding output: %w", err)
+		}
+	} else if runErr == nil {
+		fmt.Println(copilot.StripInternalTags(response))
+	}
+
+	if runErr != nil {
+		if !jsonOut {
+			fmt.Fprintf(os.Stderr, "agent run failed: %v\n", runErr)
+		}
+		return runErr
+	}
+	return nil
+}
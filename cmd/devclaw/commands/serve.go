@@ -10,12 +10,17 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jholhewres/devclaw/pkg/devclaw/auth/profiles"
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
 	"github.com/jholhewres/devclaw/pkg/devclaw/channels/discord"
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels/irc"
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels/matrix"
+	signalchan "github.com/jholhewres/devclaw/pkg/devclaw/channels/signal"
 	slackchan "github.com/jholhewres/devclaw/pkg/devclaw/channels/slack"
 	"github.com/jholhewres/devclaw/pkg/devclaw/channels/telegram"
 	"github.com/jholhewres/devclaw/pkg/devclaw/channels/whatsapp"
@@ -44,10 +49,24 @@ Examples:
 	}
 
 	cmd.Flags().StringSlice("channel", nil, "channels to enable (whatsapp, discord, telegram)")
+	cmd.Flags().Bool("stdio", false, "serve a JSON-RPC agent interface on stdin/stdout instead of channels")
+	cmd.Flags().String("data-dir", "", "relocate all state (db, memory, media, skills) under this directory")
 	return cmd
 }
 
 func runServe(cmd *cobra.Command, _ []string) error {
+	// ── Stdio mode: JSON-RPC over stdin/stdout, no channels or gateway ──
+	if stdioMode, _ := cmd.Flags().GetBool("stdio"); stdioMode {
+		return runStdioServe(cmd)
+	}
+
+	// ── Relocate state for containerized deployments ──
+	// Must happen before config loading so path defaults (db, memory,
+	// media, skills) resolve under the mount point.
+	if dataDir, _ := cmd.Flags().GetString("data-dir"); dataDir != "" {
+		paths.SetStateDir(dataDir)
+	}
+
 	// ── Ensure state directories exist ──
 	if err := paths.EnsureStateDirs(); err != nil {
 		return fmt.Errorf("failed to create state directories: %w", err)
@@ -119,6 +138,22 @@ func runServe(cmd *cobra.Command, _ []string) error {
 		} else {
 			logger.Info("WhatsApp channel registered")
 		}
+
+		// Additional named accounts (e.g. "whatsapp#business"). Each gets
+		// its own whatsmeow database so sessions don't collide.
+		for name, acctCfg := range cfg.Channels.WhatsAppAccounts {
+			if acctCfg.DatabasePath == "" {
+				acctCfg.DatabasePath = paths.ResolveDatabasePath("whatsapp-" + name + ".db")
+			}
+			qualified := channels.QualifiedName("whatsapp", name)
+			acct := whatsapp.New(acctCfg, logger)
+			if err := assistant.ChannelManager().RegisterNamed(qualified, acct); err != nil {
+				logger.Error("failed to register WhatsApp account",
+					"account", name, "error", err)
+			} else {
+				logger.Info("WhatsApp account registered", "channel", qualified)
+			}
+		}
 	}
 
 	// Telegram (core channel).
@@ -131,6 +166,40 @@ func runServe(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
+	// Matrix (core channel).
+	if shouldEnable("matrix", channelFilter, false) && cfg.Channels.Matrix.Homeserver != "" && cfg.Channels.Matrix.UserID != "" {
+		mxCfg := cfg.Channels.Matrix
+		if mxCfg.DatabasePath == "" {
+			mxCfg.DatabasePath = paths.ResolveDatabasePath("matrix.db")
+		}
+		mx := matrix.New(mxCfg, logger)
+		if err := assistant.ChannelManager().Register(mx); err != nil {
+			logger.Error("failed to register Matrix", "error", err)
+		} else {
+			logger.Info("Matrix channel registered")
+		}
+	}
+
+	// Signal (via signal-cli daemon).
+	if shouldEnable("signal", channelFilter, false) && cfg.Channels.Signal.Account != "" {
+		sig := signalchan.New(cfg.Channels.Signal, logger)
+		if err := assistant.ChannelManager().Register(sig); err != nil {
+			logger.Error("failed to register Signal", "error", err)
+		} else {
+			logger.Info("Signal channel registered")
+		}
+	}
+
+	// IRC (ops teams).
+	if shouldEnable("irc", channelFilter, false) && cfg.Channels.IRC.Server != "" {
+		ircCh := irc.New(cfg.Channels.IRC, logger)
+		if err := assistant.ChannelManager().Register(ircCh); err != nil {
+			logger.Error("failed to register IRC", "error", err)
+		} else {
+			logger.Info("IRC channel registered")
+		}
+	}
+
 	// Slack (core channel).
 	if shouldEnable("slack", channelFilter, false) && cfg.Channels.Slack.BotToken != "" {
 		sl := slackchan.New(cfg.Channels.Slack, logger)
@@ -222,6 +291,46 @@ func runServe(cmd *cobra.Command, _ []string) error {
 		logger.Info("config watcher started", "path", configPath)
 	}
 
+	// ── Start GitOps sync if enabled ──
+	if cfg.GitOps.Enabled {
+		gitops := copilot.NewGitOpsManager(
+			cfg.GitOps,
+			assistant.ApplyConfigUpdate,
+			func(dir string) error {
+				if err := copyDirTree(dir, paths.ResolveSkillsDir()); err != nil {
+					return fmt.Errorf("syncing skills: %w", err)
+				}
+				if reg := assistant.SkillRegistry(); reg != nil {
+					if _, err := reg.Reload(ctx); err != nil {
+						return fmt.Errorf("reloading skills: %w", err)
+					}
+				}
+				return nil
+			},
+			func(msg string) {
+				channel := cfg.GitOps.NotifyChannel
+				chatID := cfg.GitOps.NotifyChatID
+				if chatID == "" && len(cfg.Access.Owners) > 0 {
+					chatID = cfg.Access.Owners[0]
+				}
+				if channel == "" {
+					channel = "whatsapp"
+				}
+				if chatID == "" {
+					return
+				}
+				sendCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				if err := assistant.ChannelManager().Send(sendCtx, channel, chatID, &channels.OutgoingMessage{Content: msg}); err != nil {
+					logger.Warn("gitops: failed to notify owner", "error", err)
+				}
+			},
+			logger,
+		)
+		go gitops.Start(ctx)
+		logger.Info("gitops sync started", "repo", cfg.GitOps.RepoURL, "branch", cfg.GitOps.Branch)
+	}
+
 	// ── Wait for shutdown ──
 	logger.Info("DevClaw Copilot running. Press Ctrl+C to stop.",
 		"name", cfg.Name,
@@ -235,6 +344,18 @@ func runServe(cmd *cobra.Command, _ []string) error {
 
 	logger.Info("shutdown signal received, stopping...")
 
+	// Flip readiness first so orchestrators (e.g. Kubernetes) stop routing
+	// new traffic, then give in-flight requests a moment to drain. A second
+	// signal skips the drain period.
+	if gw != nil {
+		gw.SetDraining(true)
+		select {
+		case <-time.After(3 * time.Second):
+		case <-sigChan:
+			logger.Warn("second signal received, skipping drain period")
+		}
+	}
+
 	// Graceful shutdown with timeout.
 	done := make(chan struct{})
 	go func() {
@@ -417,6 +538,29 @@ func shouldEnable(name string, filter []string, defaultEnabled bool) bool {
 	return false
 }
 
+// copyDirTree copies src into dst recursively, overwriting existing files.
+// Used by GitOps mode to sync skills from the checkout into the skills dir.
+func copyDirTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
+
 // anySliceToStringSlice converts []any to []string.
 func anySliceToStringSlice(items []any) []string {
 	result := make([]string, 0, len(items))
@@ -0,0 +1,61 @@
+// Package commands – serve_stdio.go implements `devclaw serve --stdio`:
+// the assistant exposed as a JSON-RPC subprocess agent on stdin/stdout.
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot"
+	"github.com/jholhewres/devclaw/pkg/devclaw/stdio"
+	"github.com/spf13/cobra"
+)
+
+// runStdioServe starts the assistant without channels or the HTTP gateway and
+// serves JSON-RPC on stdin/stdout until the parent process closes the pipe.
+// All logging goes to stderr — stdout carries only protocol messages.
+func runStdioServe(cmd *cobra.Command) error {
+	// ── Load config ──
+	cfg, _, err := resolveConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	// ── Configure logger (stderr only — stdout is the protocol stream) ──
+	verbose, _ := cmd.Root().PersistentFlags().GetBool("verbose")
+	logLevel := slog.LevelWarn
+	if verbose || cfg.Logging.Level == "debug" {
+		logLevel = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+	// ── Resolve secrets ──
+	copilot.AuditSecrets(cfg, logger)
+	vault := copilot.ResolveAPIKey(cfg, logger)
+
+	if cfg.API.APIKey == "" || copilot.IsEnvReference(cfg.API.APIKey) {
+		return fmt.Errorf("no API key configured. Run: devclaw config vault-set")
+	}
+
+	// ── Create and start assistant ──
+	assistant := copilot.New(cfg, logger)
+	if vault != nil {
+		assistant.SetVault(vault)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := assistant.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start assistant: %w", err)
+	}
+	defer assistant.Stop()
+
+	server := stdio.NewServer(assistant, logger)
+	if err := server.Run(ctx, os.Stdin, os.Stdout); err != nil {
+		return fmt.Errorf("stdio server: %w", err)
+	}
+	return nil
+}
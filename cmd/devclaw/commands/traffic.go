@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot"
+	"github.com/spf13/cobra"
+)
+
+// newTrafficCmd cria o comando `devclaw traffic` para inspecionar o log de
+// tráfego LLM (requisições/respostas gravadas conforme o nível de privacidade).
+func newTrafficCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "traffic",
+		Short: "Inspect logged LLM traffic",
+		Long: `Inspect LLM request/response records captured by traffic logging.
+Enable it in config.yaml under traffic_log (level: metadata, redacted or full).
+
+Examples:
+  devclaw traffic list
+  devclaw traffic show a1b2c3d4`,
+	}
+
+	cmd.AddCommand(
+		newTrafficListCmd(),
+		newTrafficShowCmd(),
+	)
+
+	return cmd
+}
+
+func newTrafficListCmd() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recent LLM traffic records",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, closeDB, err := openTrafficStore(cmd)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			records, err := store.Recent(limit)
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				fmt.Println("No traffic records. Enable traffic_log in config.yaml.")
+				return nil
+			}
+
+			fmt.Printf("%-10s %-20s %-25s %-6s %8s %8s  %s\n",
+				"ID", "SESSION", "MODEL", "STATUS", "TOKENS", "MS", "WHEN")
+			for _, rec := range records {
+				tokens := rec.PromptTokens + rec.CompletionTokens
+				fmt.Printf("%-10s %-20s %-25s %-6s %8d %8d  %s\n",
+					rec.ID, truncateCol(rec.SessionID, 20), truncateCol(rec.Model, 25),
+					rec.Status, tokens, rec.DurationMs,
+					rec.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "maximum records to show")
+	return cmd
+}
+
+func newTrafficShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show one LLM traffic record in full",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, closeDB, err := openTrafficStore(cmd)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			rec, err := store.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("ID:        %s\n", rec.ID)
+			fmt.Printf("Session:   %s\n", rec.SessionID)
+			fmt.Printf("Provider:  %s\n", rec.Provider)
+			fmt.Printf("Model:     %s\n", rec.Model)
+			fmt.Printf("Status:    %s\n", rec.Status)
+			if rec.Error != "" {
+				fmt.Printf("Error:     %s\n", rec.Error)
+			}
+			fmt.Printf("Tokens:    %d prompt / %d completion\n", rec.PromptTokens, rec.CompletionTokens)
+			fmt.Printf("Duration:  %dms\n", rec.DurationMs)
+			fmt.Printf("When:      %s\n", rec.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+			if rec.Request != "" {
+				fmt.Printf("\n--- Request ---\n%s\n", rec.Request)
+			}
+			if rec.Response != "" {
+				fmt.Printf("\n--- Response ---\n%s\n", rec.Response)
+			}
+			if rec.Request == "" && rec.Response == "" {
+				fmt.Println("\n(no payloads — record captured at metadata level)")
+			}
+			return nil
+		},
+	}
+}
+
+// openTrafficStore opens devclaw.db from the configured path and returns the
+// traffic store with a close function.
+func openTrafficStore(cmd *cobra.Command) (*copilot.TrafficLogStore, func(), error) {
+	cfg, _, err := loadConfig(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbPath := cfg.Database.Effective().SQLite.Path
+	db, err := copilot.OpenDatabase(dbPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening %s: %w", dbPath, err)
+	}
+
+	return copilot.NewTrafficLogStore(db), func() { _ = db.Close() }, nil
+}
+
+// truncateCol trims a value to fit a fixed-width table column.
+func truncateCol(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.TrimSpace(s[:n-1]) + "…"
+}
@@ -6,6 +6,7 @@ package channels
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -24,6 +25,26 @@ const (
 	MessageReaction MessageType = "reaction"
 )
 
+// QualifiedName builds the registration name for a named channel account
+// (e.g. QualifiedName("whatsapp", "business") → "whatsapp#business").
+// An empty account returns the bare channel type, preserving the single-account
+// behavior. The "#" separator is safe because session IDs use ":" as delimiter.
+func QualifiedName(channelType, account string) string {
+	if account == "" {
+		return channelType
+	}
+	return channelType + "#" + account
+}
+
+// SplitAccount splits a (possibly qualified) channel name into channel type
+// and account. For unqualified names the account is empty.
+func SplitAccount(name string) (channelType, account string) {
+	if idx := strings.Index(name, "#"); idx >= 0 {
+		return name[:idx], name[idx+1:]
+	}
+	return name, ""
+}
+
 // Channel defines the interface that every communication channel must implement.
 type Channel interface {
 	// Name returns the channel identifier (e.g. "whatsapp", "discord").
@@ -0,0 +1,403 @@
+// Package irc implements the IRC channel for DevClaw over a plain TCP/TLS
+// socket — no external dependencies, just the classic line protocol.
+//
+// Features:
+//   - TLS and plaintext connections
+//   - NickServ identification
+//   - Channel (room) and private message support, sessions keyed per room
+//   - Trigger-based activation in channels (e.g. "!claw deploy status")
+//   - Message splitting tuned to the 512-byte IRC line limit
+//   - PING/PONG keepalive and automatic reconnect
+package irc
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// Config holds IRC channel configuration.
+type Config struct {
+	// Server is the IRC server address (host:port).
+	Server string `yaml:"server"`
+
+	// UseTLS enables a TLS connection (typical for port 6697).
+	UseTLS bool `yaml:"use_tls"`
+
+	// Nick is the bot's nickname.
+	Nick string `yaml:"nick"`
+
+	// NickServPassword identifies with NickServ after connecting.
+	NickServPassword string `yaml:"nickserv_password"`
+
+	// ServerPassword is the PASS sent at connection time (rare).
+	ServerPassword string `yaml:"server_password"`
+
+	// Channels are the channels to join (e.g. "#incidents").
+	Channels []string `yaml:"channels"`
+
+	// Trigger activates the bot in channels (e.g. "!claw"). Private
+	// messages always get a response. Empty means respond to everything
+	// in joined channels (noisy; not recommended).
+	Trigger string `yaml:"trigger"`
+
+	// RespondToDMs enables responding to private messages.
+	RespondToDMs bool `yaml:"respond_to_dms"`
+}
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		UseTLS:       true,
+		Nick:         "devclaw",
+		Trigger:      "!claw",
+		RespondToDMs: true,
+	}
+}
+
+// maxPrivmsgLen is a conservative payload budget per PRIVMSG. The IRC line
+// limit is 512 bytes including command, target, and CRLF; 400 leaves room
+// for the server-prepended prefix on relay.
+const maxPrivmsgLen = 400
+
+// IRC implements channels.Channel.
+type IRC struct {
+	cfg    Config
+	logger *slog.Logger
+
+	conn   net.Conn
+	connMu sync.Mutex // guards writes to conn
+
+	// messages is the channel for incoming messages forwarded to the assistant.
+	messages chan *channels.IncomingMessage
+
+	// connected tracks connection state.
+	connected atomic.Bool
+
+	// lastMsg tracks the last message timestamp for health.
+	lastMsg atomic.Value // time.Time
+
+	// errorCount tracks consecutive errors.
+	errorCount atomic.Int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New creates a new IRC channel instance.
+func New(cfg Config, logger *slog.Logger) *IRC {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.Nick == "" {
+		cfg.Nick = "devclaw"
+	}
+	return &IRC{
+		cfg:      cfg,
+		logger:   logger.With("component", "irc"),
+		messages: make(chan *channels.IncomingMessage, 256),
+	}
+}
+
+// ---------- Channel Interface ----------
+
+// Name returns "irc".
+func (i *IRC) Name() string { return "irc" }
+
+// Connect dials the server, registers the nick, and starts the read loop.
+func (i *IRC) Connect(ctx context.Context) error {
+	if i.cfg.Server == "" {
+		return fmt.Errorf("irc: server is required")
+	}
+	if i.connected.Load() {
+		return nil
+	}
+
+	i.ctx, i.cancel = context.WithCancel(ctx)
+
+	if err := i.dial(); err != nil {
+		return err
+	}
+
+	go i.readLoop()
+	return nil
+}
+
+// dial establishes the socket and performs IRC registration.
+func (i *IRC) dial() error {
+	var conn net.Conn
+	var err error
+	if i.cfg.UseTLS {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: 15 * time.Second}, "tcp", i.cfg.Server, nil)
+	} else {
+		conn, err = net.DialTimeout("tcp", i.cfg.Server, 15*time.Second)
+	}
+	if err != nil {
+		return fmt.Errorf("irc: connect to %s: %w", i.cfg.Server, err)
+	}
+	i.conn = conn
+
+	if i.cfg.ServerPassword != "" {
+		i.writeLine("PASS " + i.cfg.ServerPassword)
+	}
+	i.writeLine("NICK " + i.cfg.Nick)
+	i.writeLine(fmt.Sprintf("USER %s 0 * :DevClaw agent", i.cfg.Nick))
+
+	i.connected.Store(true)
+	i.logger.Info("irc: connected", "server", i.cfg.Server, "nick", i.cfg.Nick)
+	return nil
+}
+
+// Disconnect sends QUIT and closes the socket.
+func (i *IRC) Disconnect() error {
+	if i.cancel != nil {
+		i.cancel()
+	}
+	if i.connected.Load() && i.conn != nil {
+		i.writeLine("QUIT :shutting down")
+	}
+	i.connected.Store(false)
+	if i.conn != nil {
+		i.conn.Close()
+	}
+	i.logger.Info("irc: disconnected")
+	return nil
+}
+
+// Send sends a message to a channel or nick, splitting to the line limit.
+func (i *IRC) Send(_ context.Context, to string, message *channels.OutgoingMessage) error {
+	if !i.connected.Load() {
+		return channels.ErrChannelDisconnected
+	}
+
+	for _, line := range splitMessage(message.Content, maxPrivmsgLen) {
+		if err := i.writeLine(fmt.Sprintf("PRIVMSG %s :%s", to, line)); err != nil {
+			i.errorCount.Add(1)
+			return fmt.Errorf("irc: send message: %w", err)
+		}
+	}
+	i.errorCount.Store(0)
+	return nil
+}
+
+// Receive returns the incoming message stream.
+func (i *IRC) Receive() <-chan *channels.IncomingMessage {
+	return i.messages
+}
+
+// IsConnected returns true if the channel is connected.
+func (i *IRC) IsConnected() bool {
+	return i.connected.Load()
+}
+
+// Health returns the channel health status.
+func (i *IRC) Health() channels.HealthStatus {
+	var last time.Time
+	if v := i.lastMsg.Load(); v != nil {
+		last = v.(time.Time)
+	}
+	return channels.HealthStatus{
+		Connected:     i.connected.Load(),
+		LastMessageAt: last,
+		ErrorCount:    int(i.errorCount.Load()),
+	}
+}
+
+// ---------- Protocol Handling ----------
+
+// readLoop reads IRC lines, handling keepalive and routing PRIVMSGs.
+// Reconnects with backoff if the connection drops.
+func (i *IRC) readLoop() {
+	for {
+		scanner := bufio.NewScanner(i.conn)
+		scanner.Buffer(make([]byte, 0, 4096), 64*1024)
+
+		for scanner.Scan() {
+			i.handleLine(scanner.Text())
+		}
+
+		i.connected.Store(false)
+		if i.ctx.Err() != nil {
+			return
+		}
+
+		i.errorCount.Add(1)
+		i.logger.Warn("irc: connection lost, reconnecting", "error", scanner.Err())
+
+		select {
+		case <-time.After(10 * time.Second):
+		case <-i.ctx.Done():
+			return
+		}
+		if err := i.dial(); err != nil {
+			i.logger.Error("irc: reconnect failed", "error", err)
+		}
+	}
+}
+
+// handleLine processes a single raw IRC line.
+func (i *IRC) handleLine(line string) {
+	if strings.HasPrefix(line, "PING ") {
+		i.writeLine("PONG " + strings.TrimPrefix(line, "PING "))
+		return
+	}
+
+	prefix, command, params := parseLine(line)
+
+	switch command {
+	case "001": // Welcome — registration complete.
+		if i.cfg.NickServPassword != "" {
+			i.writeLine("PRIVMSG NickServ :IDENTIFY " + i.cfg.NickServPassword)
+		}
+		for _, ch := range i.cfg.Channels {
+			i.writeLine("JOIN " + ch)
+		}
+	case "433": // Nick in use — append underscore and retry.
+		i.cfg.Nick += "_"
+		i.writeLine("NICK " + i.cfg.Nick)
+	case "PRIVMSG":
+		i.handlePrivmsg(prefix, params)
+	}
+}
+
+// handlePrivmsg converts a PRIVMSG into an IncomingMessage, applying
+// trigger filtering for channel messages.
+func (i *IRC) handlePrivmsg(prefix string, params []string) {
+	if len(params) < 2 {
+		return
+	}
+	target, text := params[0], params[1]
+	nick := nickFromPrefix(prefix)
+	if nick == "" || nick == i.cfg.Nick {
+		return
+	}
+
+	isChannel := strings.HasPrefix(target, "#") || strings.HasPrefix(target, "&")
+
+	// chatID is the room for channel messages, the sender for DMs —
+	// this keys sessions per room, and per nick for private chats.
+	chatID := target
+	if !isChannel {
+		if !i.cfg.RespondToDMs {
+			return
+		}
+		chatID = nick
+	}
+
+	if isChannel && i.cfg.Trigger != "" {
+		// Accept "!claw ..." or "devclaw: ..." style activation.
+		switch {
+		case strings.HasPrefix(text, i.cfg.Trigger):
+			text = strings.TrimSpace(strings.TrimPrefix(text, i.cfg.Trigger))
+		case strings.HasPrefix(text, i.cfg.Nick+":") || strings.HasPrefix(text, i.cfg.Nick+","):
+			text = strings.TrimSpace(text[len(i.cfg.Nick)+1:])
+		default:
+			return
+		}
+		if text == "" {
+			return
+		}
+	}
+
+	i.lastMsg.Store(time.Now())
+	i.errorCount.Store(0)
+
+	msg := &channels.IncomingMessage{
+		ID:        fmt.Sprintf("%s-%d", nick, time.Now().UnixNano()),
+		Channel:   "irc",
+		From:      nick,
+		FromName:  nick,
+		ChatID:    chatID,
+		IsGroup:   isChannel,
+		Type:      channels.MessageText,
+		Content:   text,
+		Timestamp: time.Now(),
+	}
+
+	select {
+	case i.messages <- msg:
+	case <-i.ctx.Done():
+	}
+}
+
+// writeLine sends a raw IRC line (CRLF-terminated).
+func (i *IRC) writeLine(line string) error {
+	i.connMu.Lock()
+	defer i.connMu.Unlock()
+	if i.conn == nil {
+		return channels.ErrChannelDisconnected
+	}
+	_, err := i.conn.Write([]byte(line + "\r\n"))
+	return err
+}
+
+// parseLine splits a raw IRC line into prefix, command, and params.
+// The trailing param (after " :") is kept whole.
+func parseLine(line string) (prefix, command string, params []string) {
+	if strings.HasPrefix(line, ":") {
+		if idx := strings.Index(line, " "); idx >= 0 {
+			prefix = line[1:idx]
+			line = line[idx+1:]
+		}
+	}
+
+	var trailing string
+	hasTrailing := false
+	if idx := strings.Index(line, " :"); idx >= 0 {
+		trailing = line[idx+2:]
+		line = line[:idx]
+		hasTrailing = true
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return prefix, "", nil
+	}
+	command = fields[0]
+	params = fields[1:]
+	if hasTrailing {
+		params = append(params, trailing)
+	}
+	return prefix, command, params
+}
+
+// nickFromPrefix extracts the nick from a "nick!user@host" prefix.
+func nickFromPrefix(prefix string) string {
+	if idx := strings.Index(prefix, "!"); idx >= 0 {
+		return prefix[:idx]
+	}
+	return prefix
+}
+
+// splitMessage splits text into IRC-safe lines: newlines become separate
+// messages, and long lines are broken at word boundaries under the limit.
+func splitMessage(text string, limit int) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " ")
+		if line == "" {
+			continue
+		}
+		for len(line) > limit {
+			cut := strings.LastIndex(line[:limit], " ")
+			if cut <= 0 {
+				cut = limit
+			}
+			out = append(out, line[:cut])
+			line = strings.TrimLeft(line[cut:], " ")
+		}
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
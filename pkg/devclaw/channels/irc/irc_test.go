@@ -0,0 +1,62 @@
+package irc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	t.Run("privmsg with trailing", func(t *testing.T) {
+		prefix, command, params := parseLine(":nick!user@host PRIVMSG #incidents :!claw deploy status")
+		if prefix != "nick!user@host" {
+			t.Errorf("expected prefix 'nick!user@host', got %q", prefix)
+		}
+		if command != "PRIVMSG" {
+			t.Errorf("expected command PRIVMSG, got %q", command)
+		}
+		if len(params) != 2 || params[0] != "#incidents" || params[1] != "!claw deploy status" {
+			t.Errorf("unexpected params: %v", params)
+		}
+	})
+
+	t.Run("numeric without prefix colon in trailing", func(t *testing.T) {
+		_, command, params := parseLine(":server 001 devclaw :Welcome to the network")
+		if command != "001" {
+			t.Errorf("expected command 001, got %q", command)
+		}
+		if len(params) != 2 || params[1] != "Welcome to the network" {
+			t.Errorf("unexpected params: %v", params)
+		}
+	})
+}
+
+func TestSplitMessage(t *testing.T) {
+	t.Run("splits on newlines and drops blanks", func(t *testing.T) {
+		lines := splitMessage("first\n\nsecond", 400)
+		if len(lines) != 2 || lines[0] != "first" || lines[1] != "second" {
+			t.Errorf("unexpected lines: %v", lines)
+		}
+	})
+
+	t.Run("breaks long lines at word boundaries", func(t *testing.T) {
+		long := strings.Repeat("word ", 50)
+		lines := splitMessage(long, 100)
+		for _, line := range lines {
+			if len(line) > 100 {
+				t.Errorf("line exceeds limit: %d chars", len(line))
+			}
+		}
+		if joined := strings.Join(lines, " "); joined != strings.TrimSpace(long) {
+			t.Errorf("content lost in split")
+		}
+	})
+}
+
+func TestNickFromPrefix(t *testing.T) {
+	if nick := nickFromPrefix("alice!u@h"); nick != "alice" {
+		t.Errorf("expected 'alice', got %q", nick)
+	}
+	if nick := nickFromPrefix("server.example.org"); nick != "server.example.org" {
+		t.Errorf("expected full prefix back, got %q", nick)
+	}
+}
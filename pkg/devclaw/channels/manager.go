@@ -36,12 +36,23 @@ func NewManager(logger *slog.Logger) *Manager {
 	}
 }
 
-// Register adds a channel. Must be called before Start.
+// Register adds a channel under its own name. Must be called before Start.
 func (m *Manager) Register(ch Channel) error {
+	return m.RegisterNamed(ch.Name(), ch)
+}
+
+// RegisterNamed adds a channel under an explicit registration name, allowing
+// multiple accounts of the same channel type (e.g. "whatsapp#personal" and
+// "whatsapp#business" via QualifiedName). Incoming messages are rewritten to
+// carry the registration name so sessions are keyed per account and replies
+// route back through the same instance.
+func (m *Manager) RegisterNamed(name string, ch Channel) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	name := ch.Name()
+	if name == "" {
+		name = ch.Name()
+	}
 	if _, exists := m.channels[name]; exists {
 		return fmt.Errorf("channel %q already registered", name)
 	}
@@ -83,10 +94,10 @@ func (m *Manager) Start(ctx context.Context) error {
 		// Always start listening — if the channel reconnects later
 		// (e.g. via QR scan in the web UI), messages will flow through.
 		m.listenWg.Add(1)
-		go func(c Channel) {
+		go func(n string, c Channel) {
 			defer m.listenWg.Done()
-			m.listenChannel(c)
-		}(ch)
+			m.listenChannel(n, c)
+		}(name, ch)
 	}
 
 	if connected == 0 {
@@ -265,10 +276,10 @@ func (m *Manager) ConnectChannel(name string) error {
 
 	// Start listening if not already running
 	m.listenWg.Add(1)
-	go func(c Channel) {
+	go func(n string, c Channel) {
 		defer m.listenWg.Done()
-		m.listenChannel(c)
-	}(ch)
+		m.listenChannel(n, c)
+	}(name, ch)
 
 	return nil
 }
@@ -323,7 +334,9 @@ func (m *Manager) ListChannels() []string {
 
 // listenChannel listens for messages from a channel and forwards them
 // to the aggregated stream. Exits when the channel closes or context is cancelled.
-func (m *Manager) listenChannel(ch Channel) {
+// Messages are stamped with the registration name so multi-account instances
+// (e.g. "whatsapp#business") stay distinguishable downstream.
+func (m *Manager) listenChannel(name string, ch Channel) {
 	incoming := ch.Receive()
 	for {
 		select {
@@ -331,6 +344,9 @@ func (m *Manager) listenChannel(ch Channel) {
 			if !ok {
 				return // Channel closed.
 			}
+			if msg.Channel != name {
+				msg.Channel = name
+			}
 			select {
 			case m.messages <- msg:
 			case <-m.ctx.Done():
@@ -0,0 +1,598 @@
+// Package matrix implements the Matrix channel for DevClaw using mautrix-go.
+//
+// Features:
+//   - End-to-end encrypted rooms (Olm/Megolm via mautrix cryptohelper, pure Go)
+//   - Per-room sessions (room ID is the chat ID)
+//   - Send/receive text, images, audio, video, documents
+//   - Encrypted media upload/download
+//   - Typing indicators, read receipts, reactions
+//   - Login via access token or username/password (credentials stored in the
+//     crypto database, so password login only happens once)
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"maunium.net/go/mautrix"
+	"maunium.net/go/mautrix/crypto/attachment"
+	"maunium.net/go/mautrix/crypto/cryptohelper"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+
+	// Registers the "sqlite3-fk-wal" driver used by the crypto store.
+	_ "go.mau.fi/util/dbutil/litestream"
+)
+
+// Config holds Matrix channel configuration.
+type Config struct {
+	// Homeserver is the homeserver URL (e.g. "https://matrix.org").
+	Homeserver string `yaml:"homeserver"`
+
+	// UserID is the full Matrix user ID (e.g. "@devclaw:matrix.org").
+	UserID string `yaml:"user_id"`
+
+	// AccessToken authenticates directly. If empty, Password is used to
+	// log in once and the resulting device credentials are persisted.
+	AccessToken string `yaml:"access_token"`
+
+	// Password is used for initial login when AccessToken is empty.
+	Password string `yaml:"password"`
+
+	// DatabasePath is the SQLite database for crypto state (Olm sessions,
+	// device keys, room state). Defaults to "matrix.db" in the data dir.
+	DatabasePath string `yaml:"database_path"`
+
+	// PickleKey encrypts the stored Olm account. Required for E2EE.
+	PickleKey string `yaml:"pickle_key"`
+
+	// AllowedRooms restricts which room IDs the bot responds in.
+	// Empty means respond in all joined rooms.
+	AllowedRooms []string `yaml:"allowed_rooms"`
+
+	// RespondToGroups enables responding in multi-user rooms.
+	RespondToGroups bool `yaml:"respond_to_groups"`
+
+	// RespondToDMs enables responding in two-person rooms.
+	RespondToDMs bool `yaml:"respond_to_dms"`
+
+	// SendTyping sends typing indicators while processing.
+	SendTyping bool `yaml:"send_typing"`
+
+	// AutoJoin accepts room invites automatically.
+	AutoJoin bool `yaml:"auto_join"`
+}
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		RespondToGroups: true,
+		RespondToDMs:    true,
+		SendTyping:      true,
+		AutoJoin:        true,
+	}
+}
+
+// Matrix implements channels.Channel, channels.MediaChannel,
+// channels.PresenceChannel, and channels.ReactionChannel.
+type Matrix struct {
+	cfg    Config
+	logger *slog.Logger
+
+	client *mautrix.Client
+	crypto *cryptohelper.CryptoHelper
+
+	// messages is the channel for incoming messages forwarded to the assistant.
+	messages chan *channels.IncomingMessage
+
+	// connected tracks connection state.
+	connected atomic.Bool
+
+	// lastMsg tracks the last message timestamp for health.
+	lastMsg atomic.Value // time.Time
+
+	// errorCount tracks consecutive errors.
+	errorCount atomic.Int64
+
+	// startTime filters out events from before this session started.
+	startTime time.Time
+
+	// memberCounts caches joined-member counts per room for DM detection.
+	memberCounts map[id.RoomID]int
+	memberMu     sync.RWMutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New creates a new Matrix channel instance.
+func New(cfg Config, logger *slog.Logger) *Matrix {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Matrix{
+		cfg:          cfg,
+		logger:       logger.With("component", "matrix"),
+		messages:     make(chan *channels.IncomingMessage, 256),
+		memberCounts: make(map[id.RoomID]int),
+	}
+}
+
+// ---------- Channel Interface ----------
+
+// Name returns "matrix".
+func (m *Matrix) Name() string { return "matrix" }
+
+// Connect logs in to the homeserver, initializes E2EE, and starts syncing.
+func (m *Matrix) Connect(ctx context.Context) error {
+	if m.cfg.Homeserver == "" || m.cfg.UserID == "" {
+		return fmt.Errorf("matrix: homeserver and user_id are required")
+	}
+	if m.cfg.AccessToken == "" && m.cfg.Password == "" {
+		return fmt.Errorf("matrix: either access_token or password is required")
+	}
+	if m.cfg.PickleKey == "" {
+		return fmt.Errorf("matrix: pickle_key is required for encryption state")
+	}
+	if m.connected.Load() {
+		return nil
+	}
+
+	m.ctx, m.cancel = context.WithCancel(ctx)
+	m.startTime = time.Now()
+
+	client, err := mautrix.NewClient(m.cfg.Homeserver, id.UserID(m.cfg.UserID), m.cfg.AccessToken)
+	if err != nil {
+		return fmt.Errorf("matrix: create client: %w", err)
+	}
+	m.client = client
+
+	helper, err := cryptohelper.NewCryptoHelper(client, []byte(m.cfg.PickleKey), m.cfg.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("matrix: init crypto helper: %w", err)
+	}
+	if m.cfg.AccessToken == "" {
+		// Credentials are persisted in the crypto database after the
+		// first login, so the password is only used once.
+		helper.LoginAs = &mautrix.ReqLogin{
+			Type:                     mautrix.AuthTypePassword,
+			Identifier:               mautrix.UserIdentifier{Type: mautrix.IdentifierTypeUser, User: m.cfg.UserID},
+			Password:                 m.cfg.Password,
+			InitialDeviceDisplayName: "DevClaw",
+			StoreCredentials:         true,
+		}
+	}
+	if err := helper.Init(m.ctx); err != nil {
+		return fmt.Errorf("matrix: init crypto: %w", err)
+	}
+	client.Crypto = helper
+	m.crypto = helper
+
+	syncer := client.Syncer.(*mautrix.DefaultSyncer)
+	syncer.OnEventType(event.EventMessage, m.handleMessage)
+	if m.cfg.AutoJoin {
+		syncer.OnEventType(event.StateMember, m.handleInvite)
+	}
+
+	m.connected.Store(true)
+	m.logger.Info("matrix: connected", "homeserver", m.cfg.Homeserver, "user", client.UserID)
+
+	go m.syncLoop()
+
+	return nil
+}
+
+// Disconnect stops the sync loop and closes the crypto store.
+func (m *Matrix) Disconnect() error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.connected.Store(false)
+	if m.client != nil {
+		m.client.StopSync()
+	}
+	if m.crypto != nil {
+		if err := m.crypto.Close(); err != nil {
+			m.logger.Warn("matrix: error closing crypto store", "error", err)
+		}
+	}
+	m.logger.Info("matrix: disconnected")
+	return nil
+}
+
+// Send sends a text message to the specified room. Encryption is handled
+// transparently by the crypto helper for E2EE rooms.
+func (m *Matrix) Send(ctx context.Context, to string, message *channels.OutgoingMessage) error {
+	if !m.connected.Load() {
+		return channels.ErrChannelDisconnected
+	}
+
+	content := event.MessageEventContent{
+		MsgType: event.MsgText,
+		Body:    message.Content,
+	}
+	if message.ReplyTo != "" {
+		content.RelatesTo = (&event.RelatesTo{}).SetReplyTo(id.EventID(message.ReplyTo))
+	}
+
+	_, err := m.client.SendMessageEvent(ctx, id.RoomID(to), event.EventMessage, &content)
+	if err != nil {
+		m.errorCount.Add(1)
+		return fmt.Errorf("matrix: send message: %w", err)
+	}
+	m.errorCount.Store(0)
+	return nil
+}
+
+// Receive returns the incoming message stream.
+func (m *Matrix) Receive() <-chan *channels.IncomingMessage {
+	return m.messages
+}
+
+// IsConnected returns true if the channel is connected.
+func (m *Matrix) IsConnected() bool {
+	return m.connected.Load()
+}
+
+// Health returns the channel health status.
+func (m *Matrix) Health() channels.HealthStatus {
+	var last time.Time
+	if v := m.lastMsg.Load(); v != nil {
+		last = v.(time.Time)
+	}
+	return channels.HealthStatus{
+		Connected:     m.connected.Load(),
+		LastMessageAt: last,
+		ErrorCount:    int(m.errorCount.Load()),
+	}
+}
+
+// ---------- MediaChannel Interface ----------
+
+// SendMedia uploads and sends a media message. Media sent to encrypted
+// rooms is encrypted client-side before upload.
+func (m *Matrix) SendMedia(ctx context.Context, to string, media *channels.MediaMessage) error {
+	if !m.connected.Load() {
+		return channels.ErrChannelDisconnected
+	}
+	if len(media.Data) == 0 {
+		return fmt.Errorf("matrix: media data is required")
+	}
+
+	roomID := id.RoomID(to)
+	content := event.MessageEventContent{
+		MsgType: msgTypeFor(media.Type),
+		Body:    media.Filename,
+		Info: &event.FileInfo{
+			MimeType: media.MimeType,
+			Size:     len(media.Data),
+			Width:    int(media.Width),
+			Height:   int(media.Height),
+			Duration: int(media.Duration) * 1000,
+		},
+	}
+	if content.Body == "" {
+		content.Body = string(media.Type)
+	}
+	if media.ReplyTo != "" {
+		content.RelatesTo = (&event.RelatesTo{}).SetReplyTo(id.EventID(media.ReplyTo))
+	}
+
+	encrypted, err := m.client.StateStore.IsEncrypted(ctx, roomID)
+	if err != nil {
+		m.logger.Warn("matrix: failed to check room encryption", "room", roomID, "error", err)
+	}
+
+	if encrypted {
+		file := attachment.NewEncryptedFile()
+		data := make([]byte, len(media.Data))
+		copy(data, media.Data)
+		file.EncryptInPlace(data)
+		resp, err := m.client.UploadBytes(ctx, data, "application/octet-stream")
+		if err != nil {
+			return fmt.Errorf("matrix: upload encrypted media: %w", err)
+		}
+		content.File = &event.EncryptedFileInfo{
+			EncryptedFile: *file,
+			URL:           resp.ContentURI.CUString(),
+		}
+	} else {
+		resp, err := m.client.UploadBytesWithName(ctx, media.Data, media.MimeType, media.Filename)
+		if err != nil {
+			return fmt.Errorf("matrix: upload media: %w", err)
+		}
+		content.URL = resp.ContentURI.CUString()
+	}
+
+	if _, err := m.client.SendMessageEvent(ctx, roomID, event.EventMessage, &content); err != nil {
+		m.errorCount.Add(1)
+		return fmt.Errorf("matrix: send media: %w", err)
+	}
+	m.errorCount.Store(0)
+
+	// Caption goes out as a follow-up text message (classic Matrix style).
+	if media.Caption != "" {
+		return m.Send(ctx, to, &channels.OutgoingMessage{Content: media.Caption})
+	}
+	return nil
+}
+
+// DownloadMedia downloads (and decrypts, if needed) media from an incoming message.
+func (m *Matrix) DownloadMedia(ctx context.Context, msg *channels.IncomingMessage) ([]byte, string, error) {
+	if msg.Media == nil {
+		return nil, "", fmt.Errorf("matrix: message has no media")
+	}
+
+	uri, err := id.ParseContentURI(msg.Media.URL)
+	if err != nil {
+		return nil, "", fmt.Errorf("matrix: invalid media URL: %w", err)
+	}
+
+	data, err := m.client.DownloadBytes(ctx, uri)
+	if err != nil {
+		return nil, "", fmt.Errorf("matrix: %w: %v", channels.ErrMediaDownloadFailed, err)
+	}
+
+	// Encrypted attachments carry their key in the event; the handler
+	// stashes it in MediaKey as JSON-encoded EncryptedFile.
+	if len(msg.Media.MediaKey) > 0 {
+		file := &attachment.EncryptedFile{}
+		if err := json.Unmarshal(msg.Media.MediaKey, file); err != nil {
+			return nil, "", fmt.Errorf("matrix: parse encrypted file info: %w", err)
+		}
+		if err := file.DecryptInPlace(data); err != nil {
+			return nil, "", fmt.Errorf("matrix: decrypt media: %w", err)
+		}
+	}
+
+	return data, msg.Media.MimeType, nil
+}
+
+// ---------- PresenceChannel Interface ----------
+
+// SendTyping sends a typing indicator to the room.
+func (m *Matrix) SendTyping(ctx context.Context, to string) error {
+	if !m.connected.Load() || !m.cfg.SendTyping {
+		return nil
+	}
+	_, err := m.client.UserTyping(ctx, id.RoomID(to), true, 30*time.Second)
+	return err
+}
+
+// SendPresence updates the bot's presence status.
+func (m *Matrix) SendPresence(ctx context.Context, available bool) error {
+	if !m.connected.Load() {
+		return channels.ErrChannelDisconnected
+	}
+	presence := event.PresenceOnline
+	if !available {
+		presence = event.PresenceUnavailable
+	}
+	return m.client.SetPresence(ctx, mautrix.ReqPresence{Presence: presence})
+}
+
+// MarkRead sends a read receipt for the given messages.
+func (m *Matrix) MarkRead(ctx context.Context, chatID string, messageIDs []string) error {
+	if !m.connected.Load() || len(messageIDs) == 0 {
+		return nil
+	}
+	// A receipt on the latest event covers everything before it.
+	return m.client.MarkRead(ctx, id.RoomID(chatID), id.EventID(messageIDs[len(messageIDs)-1]))
+}
+
+// ---------- ReactionChannel Interface ----------
+
+// SendReaction sends an emoji reaction to a message.
+func (m *Matrix) SendReaction(ctx context.Context, chatID, messageID, emoji string) error {
+	if !m.connected.Load() {
+		return channels.ErrChannelDisconnected
+	}
+	_, err := m.client.SendReaction(ctx, id.RoomID(chatID), id.EventID(messageID), emoji)
+	if err != nil {
+		return fmt.Errorf("matrix: send reaction: %w", err)
+	}
+	return nil
+}
+
+// ---------- Sync Loop ----------
+
+// syncLoop runs the mautrix sync loop, restarting on transient errors.
+func (m *Matrix) syncLoop() {
+	for {
+		err := m.client.SyncWithContext(m.ctx)
+		if m.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			m.errorCount.Add(1)
+			m.logger.Error("matrix: sync error, restarting", "error", err)
+		}
+		select {
+		case <-time.After(5 * time.Second):
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// handleInvite auto-joins rooms the bot is invited to.
+func (m *Matrix) handleInvite(ctx context.Context, evt *event.Event) {
+	if evt.GetStateKey() != string(m.client.UserID) {
+		return
+	}
+	if evt.Content.AsMember().Membership != event.MembershipInvite {
+		return
+	}
+	if len(m.cfg.AllowedRooms) > 0 && !m.roomAllowed(evt.RoomID) {
+		m.logger.Info("matrix: ignoring invite to non-allowed room", "room", evt.RoomID)
+		return
+	}
+	if _, err := m.client.JoinRoomByID(ctx, evt.RoomID); err != nil {
+		m.logger.Error("matrix: failed to join room", "room", evt.RoomID, "error", err)
+		return
+	}
+	m.logger.Info("matrix: joined room", "room", evt.RoomID, "inviter", evt.Sender)
+}
+
+// handleMessage converts a decrypted room message into an IncomingMessage.
+func (m *Matrix) handleMessage(ctx context.Context, evt *event.Event) {
+	// Skip our own messages and history from before this session.
+	if evt.Sender == m.client.UserID {
+		return
+	}
+	if time.UnixMilli(evt.Timestamp).Before(m.startTime) {
+		return
+	}
+	if len(m.cfg.AllowedRooms) > 0 && !m.roomAllowed(evt.RoomID) {
+		return
+	}
+
+	content := evt.Content.AsMessage()
+	isGroup := m.isGroupRoom(ctx, evt.RoomID)
+	if isGroup && !m.cfg.RespondToGroups {
+		return
+	}
+	if !isGroup && !m.cfg.RespondToDMs {
+		return
+	}
+
+	msg := &channels.IncomingMessage{
+		ID:        string(evt.ID),
+		Channel:   "matrix",
+		From:      string(evt.Sender),
+		FromName:  localpart(evt.Sender),
+		ChatID:    string(evt.RoomID),
+		IsGroup:   isGroup,
+		Type:      channels.MessageText,
+		Content:   content.Body,
+		Timestamp: time.UnixMilli(evt.Timestamp),
+	}
+	if rel := content.RelatesTo; rel != nil {
+		msg.ReplyTo = string(rel.GetReplyTo())
+	}
+
+	switch content.MsgType {
+	case event.MsgText, event.MsgNotice, event.MsgEmote:
+		// Text fields already set.
+	case event.MsgImage, event.MsgAudio, event.MsgVideo, event.MsgFile:
+		msg.Type = mediaTypeFor(content.MsgType)
+		msg.Media = m.mediaInfoFrom(content, msg.Type)
+	case event.MsgLocation:
+		msg.Type = channels.MessageLocation
+		msg.Location = &channels.LocationInfo{Name: content.Body, URL: content.GeoURI}
+	default:
+		return
+	}
+
+	m.lastMsg.Store(time.Now())
+	m.errorCount.Store(0)
+
+	select {
+	case m.messages <- msg:
+	case <-m.ctx.Done():
+	}
+}
+
+// mediaInfoFrom extracts media details (including E2EE file keys) from
+// a media message event.
+func (m *Matrix) mediaInfoFrom(content *event.MessageEventContent, msgType channels.MessageType) *channels.MediaInfo {
+	info := &channels.MediaInfo{
+		Type:     msgType,
+		Filename: content.Body,
+		Caption:  content.Body,
+	}
+	if fi := content.Info; fi != nil {
+		info.MimeType = fi.MimeType
+		info.FileSize = uint64(fi.Size)
+		info.Width = uint32(fi.Width)
+		info.Height = uint32(fi.Height)
+		info.Duration = uint32(fi.Duration / 1000)
+	}
+	if content.File != nil {
+		// Encrypted attachment: keep the mxc URL and stash the key
+		// material so DownloadMedia can decrypt.
+		info.URL = string(content.File.URL)
+		if keyJSON, err := json.Marshal(content.File.EncryptedFile); err == nil {
+			info.MediaKey = keyJSON
+		}
+	} else {
+		info.URL = string(content.URL)
+	}
+	return info
+}
+
+// isGroupRoom reports whether a room has more than two joined members.
+// Counts are cached per room for the lifetime of the connection.
+func (m *Matrix) isGroupRoom(ctx context.Context, roomID id.RoomID) bool {
+	m.memberMu.RLock()
+	count, ok := m.memberCounts[roomID]
+	m.memberMu.RUnlock()
+
+	if !ok {
+		resp, err := m.client.JoinedMembers(ctx, roomID)
+		if err != nil {
+			m.logger.Warn("matrix: failed to count room members", "room", roomID, "error", err)
+			return true
+		}
+		count = len(resp.Joined)
+		m.memberMu.Lock()
+		m.memberCounts[roomID] = count
+		m.memberMu.Unlock()
+	}
+	return count > 2
+}
+
+// roomAllowed checks the AllowedRooms allowlist.
+func (m *Matrix) roomAllowed(roomID id.RoomID) bool {
+	for _, allowed := range m.cfg.AllowedRooms {
+		if allowed == string(roomID) {
+			return true
+		}
+	}
+	return false
+}
+
+// msgTypeFor maps a DevClaw media type to a Matrix message type.
+func msgTypeFor(t channels.MessageType) event.MessageType {
+	switch t {
+	case channels.MessageImage, channels.MessageSticker:
+		return event.MsgImage
+	case channels.MessageAudio:
+		return event.MsgAudio
+	case channels.MessageVideo:
+		return event.MsgVideo
+	default:
+		return event.MsgFile
+	}
+}
+
+// mediaTypeFor maps a Matrix message type to a DevClaw media type.
+func mediaTypeFor(t event.MessageType) channels.MessageType {
+	switch t {
+	case event.MsgImage:
+		return channels.MessageImage
+	case event.MsgAudio:
+		return channels.MessageAudio
+	case event.MsgVideo:
+		return channels.MessageVideo
+	default:
+		return channels.MessageDocument
+	}
+}
+
+// localpart extracts the local part of a Matrix user ID for display.
+func localpart(userID id.UserID) string {
+	s := strings.TrimPrefix(string(userID), "@")
+	if idx := strings.Index(s, ":"); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
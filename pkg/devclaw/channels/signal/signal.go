@@ -0,0 +1,628 @@
+// Package signal implements the Signal channel for DevClaw by driving the
+// signal-cli daemon over its JSON-RPC interface — no Go dependencies, just
+// a TCP socket to a running `signal-cli daemon --tcp`.
+//
+// Features:
+//   - Send/receive text messages (DMs and groups)
+//   - Attachments in both directions (base64 data URIs out, daemon files in)
+//   - Read receipts and typing indicators
+//   - Emoji reactions
+//   - Device linking flow (see StartLink/FinishLink, used by the CLI)
+package signal
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// Config holds Signal channel configuration.
+type Config struct {
+	// RPCAddress is the TCP address of the signal-cli JSON-RPC daemon
+	// (started with `signal-cli daemon --tcp <addr>`).
+	RPCAddress string `yaml:"rpc_address"`
+
+	// Account is the phone number of the Signal account (e.g. "+15551234567").
+	// Optional when the daemon runs a single account.
+	Account string `yaml:"account"`
+
+	// AttachmentsDir is where signal-cli stores received attachments.
+	// Defaults to ~/.local/share/signal-cli/attachments.
+	AttachmentsDir string `yaml:"attachments_dir"`
+
+	// AllowedNumbers restricts which sender numbers the bot responds to.
+	// Empty means respond to all.
+	AllowedNumbers []string `yaml:"allowed_numbers"`
+
+	// RespondToGroups enables responding in group chats.
+	RespondToGroups bool `yaml:"respond_to_groups"`
+
+	// RespondToDMs enables responding in direct messages.
+	RespondToDMs bool `yaml:"respond_to_dms"`
+
+	// SendReadReceipts marks processed messages as read.
+	SendReadReceipts bool `yaml:"send_read_receipts"`
+
+	// SendTyping sends typing indicators while processing.
+	SendTyping bool `yaml:"send_typing"`
+}
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		RPCAddress:       "127.0.0.1:7583",
+		RespondToGroups:  true,
+		RespondToDMs:     true,
+		SendReadReceipts: true,
+		SendTyping:       true,
+	}
+}
+
+// rpcRequest is a JSON-RPC 2.0 request (newline-delimited framing).
+type rpcRequest struct {
+	JSONRPC string         `json:"jsonrpc"`
+	ID      int64          `json:"id"`
+	Method  string         `json:"method"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// rpcMessage is a JSON-RPC 2.0 response or notification.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("signal-cli rpc error %d: %s", e.Code, e.Message)
+}
+
+// Signal implements channels.Channel, channels.MediaChannel,
+// channels.PresenceChannel, and channels.ReactionChannel.
+type Signal struct {
+	cfg    Config
+	logger *slog.Logger
+
+	// conn is the TCP connection to the signal-cli daemon.
+	conn   net.Conn
+	connMu sync.Mutex // guards writes to conn
+
+	// pending maps request IDs to response channels.
+	pending   map[int64]chan *rpcMessage
+	pendingMu sync.Mutex
+	nextID    atomic.Int64
+
+	// messages is the channel for incoming messages forwarded to the assistant.
+	messages chan *channels.IncomingMessage
+
+	// authors maps message ID (timestamp) → sender number, needed for
+	// reactions and receipts which target (author, timestamp) pairs.
+	authors   map[string]string
+	authorsMu sync.Mutex
+
+	// connected tracks connection state.
+	connected atomic.Bool
+
+	// lastMsg tracks the last message timestamp for health.
+	lastMsg atomic.Value // time.Time
+
+	// errorCount tracks consecutive errors.
+	errorCount atomic.Int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New creates a new Signal channel instance.
+func New(cfg Config, logger *slog.Logger) *Signal {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.RPCAddress == "" {
+		cfg.RPCAddress = "127.0.0.1:7583"
+	}
+	if cfg.AttachmentsDir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			cfg.AttachmentsDir = filepath.Join(home, ".local", "share", "signal-cli", "attachments")
+		}
+	}
+	return &Signal{
+		cfg:      cfg,
+		logger:   logger.With("component", "signal"),
+		pending:  make(map[int64]chan *rpcMessage),
+		messages: make(chan *channels.IncomingMessage, 256),
+		authors:  make(map[string]string),
+	}
+}
+
+// ---------- Channel Interface ----------
+
+// Name returns "signal".
+func (s *Signal) Name() string { return "signal" }
+
+// Connect dials the signal-cli daemon and starts the read loop.
+func (s *Signal) Connect(ctx context.Context) error {
+	if s.connected.Load() {
+		return nil
+	}
+
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	conn, err := net.DialTimeout("tcp", s.cfg.RPCAddress, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("signal: connect to signal-cli daemon at %s: %w", s.cfg.RPCAddress, err)
+	}
+	s.conn = conn
+	s.connected.Store(true)
+
+	go s.readLoop()
+
+	// Verify the daemon responds (and the account is usable).
+	if _, err := s.call(ctx, "version", nil); err != nil {
+		s.connected.Store(false)
+		conn.Close()
+		return fmt.Errorf("signal: daemon handshake failed: %w", err)
+	}
+
+	s.logger.Info("signal: connected", "daemon", s.cfg.RPCAddress, "account", s.cfg.Account)
+	return nil
+}
+
+// Disconnect closes the daemon connection.
+func (s *Signal) Disconnect() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.connected.Store(false)
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.logger.Info("signal: disconnected")
+	return nil
+}
+
+// Send sends a text message to a number or group.
+func (s *Signal) Send(ctx context.Context, to string, message *channels.OutgoingMessage) error {
+	if !s.connected.Load() {
+		return channels.ErrChannelDisconnected
+	}
+
+	params := s.recipientParams(to)
+	params["message"] = message.Content
+	if message.ReplyTo != "" {
+		if ts, author, ok := s.lookupTarget(message.ReplyTo); ok {
+			params["quoteTimestamp"] = ts
+			params["quoteAuthor"] = author
+		}
+	}
+
+	if _, err := s.call(ctx, "send", params); err != nil {
+		s.errorCount.Add(1)
+		return fmt.Errorf("signal: send message: %w", err)
+	}
+	s.errorCount.Store(0)
+	return nil
+}
+
+// Receive returns the incoming message stream.
+func (s *Signal) Receive() <-chan *channels.IncomingMessage {
+	return s.messages
+}
+
+// IsConnected returns true if the channel is connected.
+func (s *Signal) IsConnected() bool {
+	return s.connected.Load()
+}
+
+// Health returns the channel health status.
+func (s *Signal) Health() channels.HealthStatus {
+	var last time.Time
+	if v := s.lastMsg.Load(); v != nil {
+		last = v.(time.Time)
+	}
+	return channels.HealthStatus{
+		Connected:     s.connected.Load(),
+		LastMessageAt: last,
+		ErrorCount:    int(s.errorCount.Load()),
+	}
+}
+
+// ---------- MediaChannel Interface ----------
+
+// SendMedia sends an attachment as a base64 data URI.
+func (s *Signal) SendMedia(ctx context.Context, to string, media *channels.MediaMessage) error {
+	if !s.connected.Load() {
+		return channels.ErrChannelDisconnected
+	}
+	if len(media.Data) == 0 {
+		return fmt.Errorf("signal: media data is required")
+	}
+
+	dataURI := fmt.Sprintf("data:%s;filename=%s;base64,%s",
+		media.MimeType, media.Filename, base64.StdEncoding.EncodeToString(media.Data))
+
+	params := s.recipientParams(to)
+	params["message"] = media.Caption
+	params["attachments"] = []string{dataURI}
+
+	if _, err := s.call(ctx, "send", params); err != nil {
+		s.errorCount.Add(1)
+		return fmt.Errorf("signal: send attachment: %w", err)
+	}
+	s.errorCount.Store(0)
+	return nil
+}
+
+// DownloadMedia reads a received attachment from signal-cli's attachment store.
+func (s *Signal) DownloadMedia(_ context.Context, msg *channels.IncomingMessage) ([]byte, string, error) {
+	if msg.Media == nil || msg.Media.DirectPath == "" {
+		return nil, "", fmt.Errorf("signal: message has no attachment")
+	}
+
+	// DirectPath holds the attachment ID; signal-cli stores the file
+	// under its attachments directory with that name.
+	path := filepath.Join(s.cfg.AttachmentsDir, filepath.Base(msg.Media.DirectPath))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("signal: %w: %v", channels.ErrMediaDownloadFailed, err)
+	}
+	return data, msg.Media.MimeType, nil
+}
+
+// ---------- PresenceChannel Interface ----------
+
+// SendTyping sends a typing indicator.
+func (s *Signal) SendTyping(ctx context.Context, to string) error {
+	if !s.connected.Load() || !s.cfg.SendTyping {
+		return nil
+	}
+	_, err := s.call(ctx, "sendTyping", s.recipientParams(to))
+	return err
+}
+
+// SendPresence is a no-op — signal-cli has no presence concept.
+func (s *Signal) SendPresence(_ context.Context, _ bool) error {
+	return nil
+}
+
+// MarkRead sends read receipts for the given messages.
+func (s *Signal) MarkRead(ctx context.Context, chatID string, messageIDs []string) error {
+	if !s.connected.Load() || !s.cfg.SendReadReceipts {
+		return nil
+	}
+	for _, msgID := range messageIDs {
+		ts, author, ok := s.lookupTarget(msgID)
+		if !ok {
+			continue
+		}
+		params := map[string]any{
+			"recipient":       author,
+			"targetTimestamp": ts,
+		}
+		if s.cfg.Account != "" {
+			params["account"] = s.cfg.Account
+		}
+		if _, err := s.call(ctx, "sendReceipt", params); err != nil {
+			return fmt.Errorf("signal: send receipt: %w", err)
+		}
+	}
+	return nil
+}
+
+// ---------- ReactionChannel Interface ----------
+
+// SendReaction sends an emoji reaction to a message.
+func (s *Signal) SendReaction(ctx context.Context, chatID, messageID, emoji string) error {
+	if !s.connected.Load() {
+		return channels.ErrChannelDisconnected
+	}
+	ts, author, ok := s.lookupTarget(messageID)
+	if !ok {
+		return fmt.Errorf("signal: unknown message %q (reaction targets require author)", messageID)
+	}
+
+	params := s.recipientParams(chatID)
+	params["reaction"] = emoji
+	params["targetAuthor"] = author
+	params["targetTimestamp"] = ts
+
+	if _, err := s.call(ctx, "sendReaction", params); err != nil {
+		return fmt.Errorf("signal: send reaction: %w", err)
+	}
+	return nil
+}
+
+// ---------- Linking Flow ----------
+
+// StartLink asks the daemon for a device-link URI. The URI should be shown
+// to the user (as QR or text) and then passed to FinishLink.
+func (s *Signal) StartLink(ctx context.Context) (string, error) {
+	result, err := s.call(ctx, "startLink", nil)
+	if err != nil {
+		return "", fmt.Errorf("signal: start link: %w", err)
+	}
+	var resp struct {
+		DeviceLinkURI string `json:"deviceLinkUri"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return "", fmt.Errorf("signal: parse link response: %w", err)
+	}
+	return resp.DeviceLinkURI, nil
+}
+
+// FinishLink completes the linking flow after the user scanned the URI.
+// Blocks until the primary device confirms (can take a while).
+func (s *Signal) FinishLink(ctx context.Context, deviceLinkURI, deviceName string) error {
+	_, err := s.call(ctx, "finishLink", map[string]any{
+		"deviceLinkUri": deviceLinkURI,
+		"deviceName":    deviceName,
+	})
+	if err != nil {
+		return fmt.Errorf("signal: finish link: %w", err)
+	}
+	return nil
+}
+
+// ---------- JSON-RPC plumbing ----------
+
+// call performs a JSON-RPC request and waits for the matching response.
+func (s *Signal) call(ctx context.Context, method string, params map[string]any) (json.RawMessage, error) {
+	id := s.nextID.Add(1)
+	if params != nil && s.cfg.Account != "" && params["account"] == nil {
+		params["account"] = s.cfg.Account
+	}
+
+	req := rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	respCh := make(chan *rpcMessage, 1)
+	s.pendingMu.Lock()
+	s.pending[id] = respCh
+	s.pendingMu.Unlock()
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, id)
+		s.pendingMu.Unlock()
+	}()
+
+	s.connMu.Lock()
+	_, err = s.conn.Write(payload)
+	s.connMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write request: %w", err)
+	}
+
+	select {
+	case resp := <-respCh:
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+// readLoop reads newline-delimited JSON-RPC messages from the daemon,
+// routing responses to pending calls and notifications to the handler.
+func (s *Signal) readLoop() {
+	scanner := bufio.NewScanner(s.conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var msg rpcMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			s.logger.Warn("signal: malformed rpc message", "error", err)
+			continue
+		}
+
+		if msg.ID != nil {
+			s.pendingMu.Lock()
+			ch, ok := s.pending[*msg.ID]
+			s.pendingMu.Unlock()
+			if ok {
+				ch <- &msg
+			}
+			continue
+		}
+
+		if msg.Method == "receive" {
+			s.handleEnvelope(msg.Params)
+		}
+	}
+
+	if s.ctx.Err() == nil {
+		s.errorCount.Add(1)
+		s.connected.Store(false)
+		s.logger.Error("signal: daemon connection lost", "error", scanner.Err())
+	}
+}
+
+// envelope mirrors the signal-cli receive notification payload.
+type envelope struct {
+	Envelope struct {
+		Source       string `json:"source"`
+		SourceNumber string `json:"sourceNumber"`
+		SourceName   string `json:"sourceName"`
+		Timestamp    int64  `json:"timestamp"`
+		DataMessage  *struct {
+			Message   string `json:"message"`
+			Timestamp int64  `json:"timestamp"`
+			GroupInfo *struct {
+				GroupID string `json:"groupId"`
+			} `json:"groupInfo"`
+			Quote *struct {
+				ID int64 `json:"id"`
+			} `json:"quote"`
+			Attachments []struct {
+				ContentType string `json:"contentType"`
+				Filename    string `json:"filename"`
+				ID          string `json:"id"`
+				Size        uint64 `json:"size"`
+			} `json:"attachments"`
+		} `json:"dataMessage"`
+	} `json:"envelope"`
+}
+
+// handleEnvelope converts a receive notification into an IncomingMessage.
+func (s *Signal) handleEnvelope(raw json.RawMessage) {
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		s.logger.Warn("signal: malformed envelope", "error", err)
+		return
+	}
+
+	dm := env.Envelope.DataMessage
+	if dm == nil {
+		return // Receipts, typing, sync messages — nothing to route.
+	}
+
+	sender := env.Envelope.SourceNumber
+	if sender == "" {
+		sender = env.Envelope.Source
+	}
+	if len(s.cfg.AllowedNumbers) > 0 && !s.numberAllowed(sender) {
+		return
+	}
+
+	isGroup := dm.GroupInfo != nil
+	if isGroup && !s.cfg.RespondToGroups {
+		return
+	}
+	if !isGroup && !s.cfg.RespondToDMs {
+		return
+	}
+
+	chatID := sender
+	if isGroup {
+		chatID = dm.GroupInfo.GroupID
+	}
+
+	msgID := strconv.FormatInt(dm.Timestamp, 10)
+	s.rememberAuthor(msgID, sender)
+
+	msg := &channels.IncomingMessage{
+		ID:        msgID,
+		Channel:   "signal",
+		From:      sender,
+		FromName:  env.Envelope.SourceName,
+		ChatID:    chatID,
+		IsGroup:   isGroup,
+		Type:      channels.MessageText,
+		Content:   dm.Message,
+		Timestamp: time.UnixMilli(dm.Timestamp),
+	}
+	if dm.Quote != nil {
+		msg.ReplyTo = strconv.FormatInt(dm.Quote.ID, 10)
+	}
+
+	if len(dm.Attachments) > 0 {
+		att := dm.Attachments[0]
+		msg.Type = mediaTypeFor(att.ContentType)
+		msg.Media = &channels.MediaInfo{
+			Type:       msg.Type,
+			MimeType:   att.ContentType,
+			Filename:   att.Filename,
+			FileSize:   att.Size,
+			Caption:    dm.Message,
+			DirectPath: att.ID,
+		}
+	}
+
+	s.lastMsg.Store(time.Now())
+	s.errorCount.Store(0)
+
+	select {
+	case s.messages <- msg:
+	case <-s.ctx.Done():
+	}
+}
+
+// recipientParams builds the recipient/groupId params for an outgoing call.
+// Signal numbers start with "+"; anything else is treated as a group ID.
+func (s *Signal) recipientParams(to string) map[string]any {
+	params := make(map[string]any)
+	if strings.HasPrefix(to, "+") {
+		params["recipient"] = []string{to}
+	} else {
+		params["groupId"] = to
+	}
+	return params
+}
+
+// rememberAuthor records who sent a message so reactions and receipts can
+// target it later. Bounded to avoid unbounded growth on busy accounts.
+func (s *Signal) rememberAuthor(msgID, author string) {
+	s.authorsMu.Lock()
+	defer s.authorsMu.Unlock()
+	if len(s.authors) > 4096 {
+		s.authors = make(map[string]string)
+	}
+	s.authors[msgID] = author
+}
+
+// lookupTarget resolves a message ID into (timestamp, author).
+func (s *Signal) lookupTarget(msgID string) (int64, string, bool) {
+	ts, err := strconv.ParseInt(msgID, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	s.authorsMu.Lock()
+	author, ok := s.authors[msgID]
+	s.authorsMu.Unlock()
+	if !ok {
+		return 0, "", false
+	}
+	return ts, author, true
+}
+
+// numberAllowed checks the AllowedNumbers allowlist.
+func (s *Signal) numberAllowed(number string) bool {
+	for _, allowed := range s.cfg.AllowedNumbers {
+		if allowed == number {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaTypeFor maps a MIME type to a DevClaw media type.
+func mediaTypeFor(mimeType string) channels.MessageType {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return channels.MessageImage
+	case strings.HasPrefix(mimeType, "audio/"):
+		return channels.MessageAudio
+	case strings.HasPrefix(mimeType, "video/"):
+		return channels.MessageVideo
+	default:
+		return channels.MessageDocument
+	}
+}
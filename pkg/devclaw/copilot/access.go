@@ -29,6 +29,7 @@ const (
 	AccessOwner   AccessLevel = "owner"
 	AccessAdmin   AccessLevel = "admin"
 	AccessUser    AccessLevel = "user"
+	AccessGuest   AccessLevel = "guest"
 	AccessBlocked AccessLevel = "blocked"
 	AccessNone    AccessLevel = "none"
 	AccessUnknown AccessLevel = ""
@@ -46,6 +47,10 @@ const (
 
 	// PolicyAsk sends a one-time "request access" message to unknown contacts.
 	PolicyAsk AccessPolicy = "ask"
+
+	// PolicyGuest grants unknown contacts read-only guest access: locked-down
+	// tool profile, capped turns, daily token budget, watermarked replies.
+	PolicyGuest AccessPolicy = "guest"
 )
 
 // AccessConfig holds the access control configuration.
@@ -63,6 +68,13 @@ type AccessConfig struct {
 	// AllowedUsers can interact with the bot.
 	AllowedUsers []string `yaml:"allowed_users"`
 
+	// Guests get read-only demo access (locked-down tools, capped turns,
+	// daily token budget, watermarked replies).
+	Guests []string `yaml:"guests"`
+
+	// Guest configures the limits applied to guest-level contacts.
+	Guest GuestConfig `yaml:"guest"`
+
 	// BlockedUsers are explicitly blocked.
 	BlockedUsers []string `yaml:"blocked_users"`
 
@@ -77,6 +89,13 @@ type AccessConfig struct {
 
 	// PendingMessage is the message sent to unknown contacts when policy is "ask".
 	PendingMessage string `yaml:"pending_message"`
+
+	// ByChannel defines per-channel access overrides, keyed by registration
+	// name (e.g. "whatsapp#business", "telegram"). When a message arrives on
+	// a channel listed here, its config replaces the global lists entirely
+	// for that check. Overrides with an empty DefaultPolicy inherit the
+	// global one.
+	ByChannel map[string]AccessConfig `yaml:"by_channel"`
 }
 
 // DefaultAccessConfig returns the default access control config.
@@ -84,6 +103,7 @@ func DefaultAccessConfig() AccessConfig {
 	return AccessConfig{
 		DefaultPolicy:  PolicyDeny,
 		PendingMessage: "Access not authorized. Please contact an admin to request access.",
+		Guest:          DefaultGuestConfig(),
 	}
 }
 
@@ -118,6 +138,14 @@ type AccessManager struct {
 	// to avoid spamming them.
 	askedOnce map[string]time.Time
 
+	// perChannel holds sub-managers for channels with access overrides
+	// (keyed by registration name, e.g. "whatsapp#business").
+	perChannel map[string]*AccessManager
+
+	// identityResolver, when set, returns all JIDs linked to one person so
+	// access level follows them across channels (see identity.go).
+	identityResolver func(jid string) []string
+
 	mu sync.RWMutex
 }
 
@@ -155,6 +183,12 @@ func NewAccessManager(cfg AccessConfig, logger *slog.Logger) *AccessManager {
 			AddedBy: "config", AddedAt: now,
 		}
 	}
+	for _, jid := range cfg.Guests {
+		am.users[normalizeJID(jid)] = &AccessEntry{
+			JID: normalizeJID(jid), Level: AccessGuest,
+			AddedBy: "config", AddedAt: now,
+		}
+	}
 	for _, jid := range cfg.BlockedUsers {
 		am.users[normalizeJID(jid)] = &AccessEntry{
 			JID: normalizeJID(jid), Level: AccessBlocked,
@@ -174,6 +208,24 @@ func NewAccessManager(cfg AccessConfig, logger *slog.Logger) *AccessManager {
 		}
 	}
 
+	// Build sub-managers for per-channel overrides. Each override is a full
+	// AccessConfig scoped to one registered channel/account.
+	if len(cfg.ByChannel) > 0 {
+		am.perChannel = make(map[string]*AccessManager, len(cfg.ByChannel))
+		for channel, sub := range cfg.ByChannel {
+			if sub.DefaultPolicy == "" {
+				sub.DefaultPolicy = cfg.DefaultPolicy
+			}
+			if sub.PendingMessage == "" {
+				sub.PendingMessage = cfg.PendingMessage
+			}
+			// Nested ByChannel is not supported — drop it to avoid recursion.
+			sub.ByChannel = nil
+			am.perChannel[channel] = NewAccessManager(sub,
+				logger.With("access_channel", channel))
+		}
+	}
+
 	am.logger.Info("access manager initialized",
 		"policy", cfg.DefaultPolicy,
 		"owners", len(cfg.Owners),
@@ -181,6 +233,7 @@ func NewAccessManager(cfg AccessConfig, logger *slog.Logger) *AccessManager {
 		"users", len(cfg.AllowedUsers),
 		"blocked", len(cfg.BlockedUsers),
 		"groups", len(cfg.AllowedGroups),
+		"channel_overrides", len(cfg.ByChannel),
 	)
 
 	return am
@@ -207,11 +260,17 @@ func (am *AccessManager) Check(msg *channels.IncomingMessage) CheckResult {
 	am.mu.RLock()
 	defer am.mu.RUnlock()
 
+	// Delegate to the per-channel override manager if one is configured
+	// for this channel/account.
+	if sub, ok := am.perChannel[msg.Channel]; ok {
+		return sub.Check(msg)
+	}
+
 	from := normalizeJID(msg.From)
 	chatID := normalizeJID(msg.ChatID)
 
-	// 1. Check if sender is explicitly blocked.
-	if entry, ok := am.users[from]; ok && entry.Level == AccessBlocked {
+	// 1. Check if sender is explicitly blocked (on any linked identity).
+	if entry, ok := am.linkedEntry(from); ok && entry.Level == AccessBlocked {
 		return CheckResult{
 			Allowed: false, Level: AccessBlocked,
 			Reason: "user explicitly blocked",
@@ -228,9 +287,9 @@ func (am *AccessManager) Check(msg *channels.IncomingMessage) CheckResult {
 		}
 	}
 
-	// 3. Check if sender has explicit access.
-	if entry, ok := am.users[from]; ok {
-		if entry.Level == AccessOwner || entry.Level == AccessAdmin || entry.Level == AccessUser {
+	// 3. Check if sender has explicit access (directly or via a linked identity).
+	if entry, ok := am.linkedEntry(from); ok {
+		if entry.Level == AccessOwner || entry.Level == AccessAdmin || entry.Level == AccessUser || entry.Level == AccessGuest {
 			return CheckResult{Allowed: true, Level: entry.Level}
 		}
 	}
@@ -248,6 +307,9 @@ func (am *AccessManager) Check(msg *channels.IncomingMessage) CheckResult {
 	case PolicyAllow:
 		return CheckResult{Allowed: true, Level: AccessUser}
 
+	case PolicyGuest:
+		return CheckResult{Allowed: true, Level: AccessGuest}
+
 	case PolicyAsk:
 		// Check if we already asked this contact.
 		if _, asked := am.askedOnce[from]; !asked {
@@ -271,6 +333,57 @@ func (am *AccessManager) Check(msg *channels.IncomingMessage) CheckResult {
 	}
 }
 
+// SetIdentityResolver wires cross-channel identity linking: access checks
+// consider every JID linked to the same person, with blocks taking priority.
+func (am *AccessManager) SetIdentityResolver(resolver func(jid string) []string) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.identityResolver = resolver
+	for _, sub := range am.perChannel {
+		sub.SetIdentityResolver(resolver)
+	}
+}
+
+// linkedEntry returns the strongest access entry among all JIDs linked to
+// from (a block on any linked JID wins). Without a resolver it is a plain
+// lookup. Caller must hold am.mu.
+func (am *AccessManager) linkedEntry(from string) (*AccessEntry, bool) {
+	if am.identityResolver == nil {
+		entry, ok := am.users[from]
+		return entry, ok
+	}
+	var best *AccessEntry
+	for _, jid := range am.identityResolver(from) {
+		entry, ok := am.users[normalizeJID(jid)]
+		if !ok {
+			continue
+		}
+		if entry.Level == AccessBlocked {
+			return entry, true
+		}
+		if best == nil || levelRank(entry.Level) > levelRank(best.Level) {
+			best = entry
+		}
+	}
+	return best, best != nil
+}
+
+// levelRank orders access levels from weakest to strongest.
+func levelRank(level AccessLevel) int {
+	switch level {
+	case AccessOwner:
+		return 4
+	case AccessAdmin:
+		return 3
+	case AccessUser:
+		return 2
+	case AccessGuest:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // MarkAsked records that we sent the "pending" message to a contact.
 func (am *AccessManager) MarkAsked(jid string) {
 	am.mu.Lock()
@@ -369,7 +482,7 @@ func (am *AccessManager) GetLevel(jid string) AccessLevel {
 	am.mu.RLock()
 	defer am.mu.RUnlock()
 
-	if entry, ok := am.users[normalizeJID(jid)]; ok {
+	if entry, ok := am.linkedEntry(normalizeJID(jid)); ok {
 		return entry.Level
 	}
 	return AccessUnknown
@@ -450,6 +563,12 @@ func (am *AccessManager) ApplyConfig(cfg AccessConfig) {
 			AddedBy: "config", AddedAt: now,
 		}
 	}
+	for _, jid := range cfg.Guests {
+		am.users[normalizeJID(jid)] = &AccessEntry{
+			JID: normalizeJID(jid), Level: AccessGuest,
+			AddedBy: "config", AddedAt: now,
+		}
+	}
 	for _, jid := range cfg.BlockedUsers {
 		am.users[normalizeJID(jid)] = &AccessEntry{
 			JID: normalizeJID(jid), Level: AccessBlocked,
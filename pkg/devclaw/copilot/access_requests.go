@@ -0,0 +1,251 @@
+// Package copilot – access_requests.go implements the review queue for the
+// "ask" access policy.
+//
+// With policy "ask", unknown contacts receive a one-time pending message and
+// are otherwise ignored. This queue records each contact (with name and their
+// first message for context) in devclaw.db so admins can review them later via
+// the /requests command and approve or deny with the requester being notified.
+package copilot
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// accessRequestMessageLimit caps how much of the first message is stored.
+const accessRequestMessageLimit = 500
+
+// AccessRequest represents an unknown contact awaiting admin review.
+type AccessRequest struct {
+	ID           string     `json:"id"`
+	UserJID      string     `json:"user_jid"`
+	UserName     string     `json:"user_name"`
+	Channel      string     `json:"channel"`
+	FirstMessage string     `json:"first_message"`
+	Status       string     `json:"status"` // pending, approved, denied
+	ReviewedBy   string     `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// AccessRequestQueue persists access requests from the "ask" policy and
+// handles the approve/deny lifecycle.
+type AccessRequestQueue struct {
+	db        *sql.DB
+	accessMgr *AccessManager
+	logger    *slog.Logger
+	mu        sync.Mutex
+}
+
+// NewAccessRequestQueue creates an access request queue.
+func NewAccessRequestQueue(db *sql.DB, accessMgr *AccessManager, logger *slog.Logger) *AccessRequestQueue {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AccessRequestQueue{
+		db:        db,
+		accessMgr: accessMgr,
+		logger:    logger.With("component", "access_requests"),
+	}
+}
+
+// Add records an access request for an unknown contact. A contact with a
+// pending request is not queued twice, so repeated messages (or re-asks after
+// a restart) stay a single entry.
+func (q *AccessRequestQueue) Add(userJID, userName, channel, firstMessage string) (*AccessRequest, error) {
+	if q.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	norm := normalizeJID(userJID)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Dedupe: one pending request per contact.
+	var existingID string
+	err := q.db.QueryRow(`
+		SELECT id FROM access_requests
+		WHERE user_jid = ? AND status = 'pending'
+	`, norm).Scan(&existingID)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("query access request: %w", err)
+	}
+
+	if len(firstMessage) > accessRequestMessageLimit {
+		firstMessage = firstMessage[:accessRequestMessageLimit] + "…"
+	}
+
+	r := &AccessRequest{
+		ID:           uuid.New().String(),
+		UserJID:      norm,
+		UserName:     userName,
+		Channel:      channel,
+		FirstMessage: firstMessage,
+		Status:       "pending",
+		CreatedAt:    time.Now(),
+	}
+
+	_, err = q.db.Exec(`
+		INSERT INTO access_requests
+		(id, user_jid, user_name, channel, first_message, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, r.ID, r.UserJID, r.UserName, r.Channel, r.FirstMessage, r.Status, r.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("insert access request: %w", err)
+	}
+
+	q.logger.Info("access request queued",
+		"request_id", r.ID,
+		"user_jid", norm,
+		"channel", channel,
+	)
+
+	return r, nil
+}
+
+// ListPending returns all pending access requests, oldest first.
+func (q *AccessRequestQueue) ListPending() ([]*AccessRequest, error) {
+	if q.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	rows, err := q.db.Query(`
+		SELECT id, user_jid, user_name, channel, first_message, status,
+		       reviewed_by, reviewed_at, created_at
+		FROM access_requests
+		WHERE status = 'pending'
+		ORDER BY created_at ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query access requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*AccessRequest
+	for rows.Next() {
+		r := &AccessRequest{}
+		var reviewedAt sql.NullString
+		var createdAt string
+
+		err := rows.Scan(
+			&r.ID, &r.UserJID, &r.UserName, &r.Channel, &r.FirstMessage,
+			&r.Status, &r.ReviewedBy, &reviewedAt, &createdAt,
+		)
+		if err != nil {
+			continue
+		}
+
+		if reviewedAt.Valid && reviewedAt.String != "" {
+			rt, err := time.Parse(time.RFC3339, reviewedAt.String)
+			if err == nil {
+				r.ReviewedAt = &rt
+			}
+		}
+		r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+		requests = append(requests, r)
+	}
+
+	return requests, nil
+}
+
+// Approve approves a pending request (by ID or ID prefix) and grants the
+// contact user-level access. Returns the request so the caller can notify
+// the requester on their channel.
+func (q *AccessRequestQueue) Approve(idOrPrefix, approvedBy string) (*AccessRequest, error) {
+	r, err := q.getPending(idOrPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.accessMgr.Grant(r.UserJID, AccessUser, approvedBy); err != nil {
+		return nil, fmt.Errorf("grant access: %w", err)
+	}
+
+	if err := q.review(r, "approved", approvedBy); err != nil {
+		return nil, err
+	}
+
+	q.logger.Info("access request approved",
+		"request_id", r.ID,
+		"user_jid", r.UserJID,
+		"approved_by", approvedBy,
+	)
+
+	return r, nil
+}
+
+// Deny denies a pending request (by ID or ID prefix). Returns the request so
+// the caller can notify the requester on their channel.
+func (q *AccessRequestQueue) Deny(idOrPrefix, deniedBy string) (*AccessRequest, error) {
+	r, err := q.getPending(idOrPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.review(r, "denied", deniedBy); err != nil {
+		return nil, err
+	}
+
+	q.logger.Info("access request denied",
+		"request_id", r.ID,
+		"user_jid", r.UserJID,
+		"denied_by", deniedBy,
+	)
+
+	return r, nil
+}
+
+// getPending finds a pending request by ID or ID prefix.
+func (q *AccessRequestQueue) getPending(idOrPrefix string) (*AccessRequest, error) {
+	if q.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	r := &AccessRequest{}
+	var createdAt string
+	err := q.db.QueryRow(`
+		SELECT id, user_jid, user_name, channel, first_message, status, created_at
+		FROM access_requests
+		WHERE status = 'pending' AND (id = ? OR id LIKE ?)
+	`, idOrPrefix, idOrPrefix+"%").Scan(
+		&r.ID, &r.UserJID, &r.UserName, &r.Channel, &r.FirstMessage,
+		&r.Status, &createdAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("pending request not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query request: %w", err)
+	}
+
+	r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return r, nil
+}
+
+// review marks a request as reviewed with the given status.
+func (q *AccessRequestQueue) review(r *AccessRequest, status, by string) error {
+	now := time.Now()
+	_, err := q.db.Exec(`
+		UPDATE access_requests
+		SET status = ?, reviewed_by = ?, reviewed_at = ?
+		WHERE id = ?
+	`, status, by, now.Format(time.RFC3339), r.ID)
+	if err != nil {
+		return fmt.Errorf("update request: %w", err)
+	}
+
+	r.Status = status
+	r.ReviewedBy = by
+	r.ReviewedAt = &now
+	return nil
+}
@@ -0,0 +1,110 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testAccessRequestQueue(t *testing.T) (*AccessRequestQueue, *AccessManager) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	am := NewAccessManager(DefaultAccessConfig(), logger)
+	return NewAccessRequestQueue(db, am, logger), am
+}
+
+func TestAccessRequestQueueAddAndList(t *testing.T) {
+	q, _ := testAccessRequestQueue(t)
+
+	r, err := q.Add("5511999@s.whatsapp.net", "Alice", "whatsapp", "hi, can I get access?")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if r == nil || r.Status != "pending" {
+		t.Fatalf("expected pending request, got %+v", r)
+	}
+
+	// Repeated messages from the same contact stay one pending entry.
+	dup, err := q.Add("5511999@s.whatsapp.net", "Alice", "whatsapp", "hello again")
+	if err != nil {
+		t.Fatalf("Add duplicate: %v", err)
+	}
+	if dup != nil {
+		t.Error("second Add for the same contact should be deduped")
+	}
+
+	pending, err := q.ListPending()
+	if err != nil {
+		t.Fatalf("ListPending: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending request, got %d", len(pending))
+	}
+	if pending[0].UserName != "Alice" || pending[0].FirstMessage != "hi, can I get access?" {
+		t.Errorf("request context not stored: %+v", pending[0])
+	}
+}
+
+func TestAccessRequestQueueApprove(t *testing.T) {
+	q, am := testAccessRequestQueue(t)
+
+	r, err := q.Add("5511888@s.whatsapp.net", "Bob", "telegram", "let me in")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// Approve by ID prefix, like the /requests command does.
+	approved, err := q.Approve(r.ID[:8], "owner@s.whatsapp.net")
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if approved.UserJID != r.UserJID || approved.Status != "approved" {
+		t.Errorf("unexpected approved request: %+v", approved)
+	}
+
+	if level := am.GetLevel("5511888@s.whatsapp.net"); level != AccessUser {
+		t.Errorf("approval should grant user access, got %s", level)
+	}
+
+	pending, _ := q.ListPending()
+	if len(pending) != 0 {
+		t.Errorf("approved request should leave the queue, got %d pending", len(pending))
+	}
+
+	// Re-reviewing the same request must fail.
+	if _, err := q.Approve(r.ID, "owner@s.whatsapp.net"); err == nil {
+		t.Error("approving a reviewed request should fail")
+	}
+}
+
+func TestAccessRequestQueueDeny(t *testing.T) {
+	q, am := testAccessRequestQueue(t)
+
+	r, err := q.Add("5511777@s.whatsapp.net", "", "whatsapp", "access please")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	denied, err := q.Deny(r.ID, "admin@s.whatsapp.net")
+	if err != nil {
+		t.Fatalf("Deny: %v", err)
+	}
+	if denied.Status != "denied" {
+		t.Errorf("expected denied status, got %s", denied.Status)
+	}
+
+	if level := am.GetLevel("5511777@s.whatsapp.net"); level != AccessUnknown {
+		t.Errorf("denial should not grant access, got %s", level)
+	}
+
+	if _, err := q.Deny("nonexistent", "admin@s.whatsapp.net"); err == nil {
+		t.Error("denying an unknown request should fail")
+	}
+}
@@ -61,6 +61,16 @@ type AgentConfig struct {
 	// ReflectionEnabled enables periodic budget awareness nudges (default: true).
 	ReflectionEnabled bool `yaml:"reflection_enabled"`
 
+	// SelfCritiqueEnabled extends reflection with a post-run structured
+	// self-critique (what failed, tool mistakes, missing info) persisted to
+	// devclaw.db and searchable via lessons_search. Default: false — it costs
+	// one extra LLM call per run. Requires ReflectionEnabled.
+	SelfCritiqueEnabled bool `yaml:"self_critique_enabled"`
+
+	// LessonsInPrompt injects the workspace's most recent lessons into the
+	// system prompt of future runs (default: false).
+	LessonsInPrompt bool `yaml:"lessons_in_prompt"`
+
 	// MaxCompactionAttempts is how many times to retry after context overflow (default: 3).
 	MaxCompactionAttempts int `yaml:"max_compaction_attempts"`
 
@@ -134,6 +144,10 @@ type AgentRun struct {
 	// intermediate reasoning before tools run.
 	onBeforeToolExec func()
 
+	// onToolCall is called for each tool call right before execution.
+	// Used by streaming consumers (TUI, SSE) to show tool activity live.
+	onToolCall func(name string, argsJSON string)
+
 	// onToolResult is called after each tool execution completes.
 	// Used to auto-send media (e.g. generated images) to the channel.
 	onToolResult func(name string, result ToolResult)
@@ -141,6 +155,14 @@ type AgentRun struct {
 	// loopDetector tracks tool call history and detects repetitive patterns.
 	loopDetector *ToolLoopDetector
 
+	// toolSelector, when set, narrows the tool definitions sent per turn to
+	// the ones relevant to the current message (see tool_selector.go).
+	toolSelector *ToolSelector
+
+	// tracer, when set, records the run's timeline (LLM turns, tool calls,
+	// interrupts) for the /trace command and the runs API (see run_trace.go).
+	tracer *RunTracer
+
 	logger *slog.Logger
 }
 
@@ -203,6 +225,12 @@ func (a *AgentRun) SetOnBeforeToolExec(fn func()) {
 	a.onBeforeToolExec = fn
 }
 
+// SetOnToolCall sets a callback fired for each tool call before it executes.
+// Used by streaming consumers (TUI, SSE) to show tool activity live.
+func (a *AgentRun) SetOnToolCall(fn func(name string, argsJSON string)) {
+	a.onToolCall = fn
+}
+
 // SetOnToolResult sets a callback fired after each tool execution completes.
 // Used to auto-send media (e.g. generated images) to the channel.
 func (a *AgentRun) SetOnToolResult(fn func(name string, result ToolResult)) {
@@ -214,6 +242,17 @@ func (a *AgentRun) SetLoopDetector(d *ToolLoopDetector) {
 	a.loopDetector = d
 }
 
+// SetToolSelector sets the dynamic tool selector for this run. When set,
+// only the tools relevant to the user message are sent to the LLM.
+func (a *AgentRun) SetToolSelector(s *ToolSelector) {
+	a.toolSelector = s
+}
+
+// SetTracer sets the run tracer that records this run's timeline.
+func (a *AgentRun) SetTracer(t *RunTracer) {
+	a.tracer = t
+}
+
 // SetInterruptChannel sets the channel for receiving follow-up user messages
 // during agent execution. Messages received on this channel are injected into
 // the conversation between agent turns, allowing users to steer the agent
@@ -273,6 +312,13 @@ func (a *AgentRun) RunWithUsage(ctx context.Context, systemPrompt string, histor
 		tools = allTools
 	}
 
+	// Dynamic tool selection: with large catalogs, send only the definitions
+	// relevant to this message. Pinned tools and the find_tool escape hatch
+	// always survive the cut.
+	if a.toolSelector != nil {
+		tools = a.toolSelector.Select(runCtx, userMessage, tools)
+	}
+
 	// Limit tools to 128 for OpenAI API compatibility
 	const maxTools = 128
 	if len(tools) > maxTools {
@@ -361,6 +407,9 @@ func (a *AgentRun) RunWithUsage(ctx context.Context, systemPrompt string, histor
 					"count", len(interrupts),
 					"turn", totalTurns,
 				)
+				if a.tracer != nil {
+					a.tracer.RecordInterrupt(len(interrupts))
+				}
 			}
 		}
 
@@ -436,6 +485,9 @@ func (a *AgentRun) RunWithUsage(ctx context.Context, systemPrompt string, histor
 			"prompt_tokens", resp.Usage.PromptTokens,
 			"completion_tokens", resp.Usage.CompletionTokens,
 		)
+		if a.tracer != nil {
+			a.tracer.RecordLLMTurn(resp.ModelUsed, len(resp.ToolCalls), resp.Usage, llmDuration)
+		}
 
 		// ── Strict <think> Parsing ──
 		if strings.Contains(resp.Content, "<think>") && !strings.Contains(resp.Content, "</think>") {
@@ -543,6 +595,18 @@ func (a *AgentRun) RunWithUsage(ctx context.Context, systemPrompt string, histor
 			}
 		}
 
+		// Announce each tool call to streaming consumers before execution.
+		if a.onToolCall != nil {
+			for _, tc := range resp.ToolCalls {
+				a.onToolCall(tc.Function.Name, tc.Function.Arguments)
+			}
+		}
+
+		// Validate args against each tool's schema and attempt a one-shot
+		// repair for malformed calls, instead of wasting the turn on a
+		// guaranteed parse/validation failure.
+		a.repairToolCalls(runCtx, resp.ToolCalls)
+
 		results := a.executor.Execute(runCtx, resp.ToolCalls)
 
 		a.logger.Info("tool calls complete",
@@ -573,8 +637,13 @@ func (a *AgentRun) RunWithUsage(ctx context.Context, systemPrompt string, histor
 				a.loopDetector.RecordToolOutcome(content)
 			}
 
-			// Notify hook (e.g. auto-send media for generate_image).
-			if a.onToolResult != nil && result.Error == nil {
+			if a.tracer != nil {
+				a.tracer.RecordToolResult(result)
+			}
+
+			// Notify hook (e.g. auto-send media, TUI tool panes). Hooks receive
+			// errored results too and decide themselves whether to act on them.
+			if a.onToolResult != nil {
 				a.onToolResult(result.Name, result)
 			}
 		}
@@ -951,9 +1020,15 @@ func (a *AgentRun) buildMessages(systemPrompt string, history []ConversationEntr
 	}
 
 	for _, entry := range history {
+		// Group messages carry sender attribution so the model can tell
+		// participants apart and address them by name.
+		userContent := entry.UserMessage
+		if entry.SenderName != "" {
+			userContent = entry.SenderName + ": " + userContent
+		}
 		messages = append(messages, chatMessage{
 			Role:    "user",
-			Content: entry.UserMessage,
+			Content: userContent,
 		})
 		if entry.AssistantResponse != "" {
 			messages = append(messages, chatMessage{
@@ -1562,12 +1637,15 @@ func (a *AgentRun) summarizeMiddle(ctx context.Context, middle []chatMessage) st
 		},
 	}
 
-	// Make a quick call using the fast model (e.g., flash/haiku equivalent) if available
-	// For now we use the standard model but without tools
+	// Quick call on the utility model when configured (cheaper/faster),
+	// falling back to the main model.
 	sumCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	resp, err := a.llm.CompleteWithFallbackUsingModel(sumCtx, "", prompt, nil)
+	resp, err := a.llm.CompleteWithFallbackUsingModel(sumCtx, a.llm.UtilityModel(), prompt, nil)
+	if err != nil && a.llm.UtilityModel() != "" {
+		resp, err = a.llm.CompleteWithFallbackUsingModel(sumCtx, "", prompt, nil)
+	}
 	if err != nil {
 		a.logger.Warn("compaction summary failed", "error", err)
 		return "Failed to summarize earlier steps due to error."
@@ -20,9 +20,17 @@ type AgentProfileConfig struct {
 	// Instructions override the base system prompt for this agent.
 	Instructions string `yaml:"instructions"`
 
+	// Persona is the voice preset applied when the session has none set
+	// (see personas config). Lets routing pick both the model and the voice.
+	Persona string `yaml:"persona"`
+
 	// Skills are the skill names to enable for this agent.
 	Skills []string `yaml:"skills"`
 
+	// Tools restricts this agent to the listed tools (allowlist; supports
+	// "group:name" and wildcards like "git_*"). Empty = all tools.
+	Tools []string `yaml:"tools"`
+
 	// Channels route messages from these channels to this agent.
 	Channels []string `yaml:"channels"`
 
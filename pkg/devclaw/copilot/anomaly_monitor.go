@@ -0,0 +1,299 @@
+// Package copilot – anomaly_monitor.go implements self-monitoring on top of
+// the metrics collector: it watches LLM error rate, tool failure rate, and
+// average latency over a rolling window of snapshots, and proactively
+// notifies the owner chat with a summary and suggested actions when a
+// threshold is breached. Configured under Routines.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// AnomalyConfig configures the anomaly monitor routine.
+type AnomalyConfig struct {
+	// Enabled turns the monitor on/off. Requires Routines.Metrics.Enabled.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// Channel/ChatID override where alerts are delivered. Empty = the most
+	// recently active session.
+	Channel string `yaml:"channel" json:"channel"`
+	ChatID  string `yaml:"chat_id" json:"chat_id"`
+
+	// WindowSnapshots is how many metrics snapshots form the detection
+	// window (default 5 — five minutes at the default collector interval).
+	WindowSnapshots int `yaml:"window_snapshots" json:"window_snapshots"`
+
+	// MaxErrorRate is the agent-run (LLM) failure rate that triggers an
+	// alert, 0..1 (default 0.3).
+	MaxErrorRate float64 `yaml:"max_error_rate" json:"max_error_rate"`
+
+	// MaxToolFailureRate is the tool-call failure rate that triggers an
+	// alert, 0..1 (default 0.5).
+	MaxToolFailureRate float64 `yaml:"max_tool_failure_rate" json:"max_tool_failure_rate"`
+
+	// MaxAvgLatencyMs is the window-average latency that triggers an alert
+	// (default 20000).
+	MaxAvgLatencyMs int64 `yaml:"max_avg_latency_ms" json:"max_avg_latency_ms"`
+
+	// MinSamples is the minimum number of runs/calls in the window before a
+	// rate is trusted — a single failure should not page the owner
+	// (default 5).
+	MinSamples int64 `yaml:"min_samples" json:"min_samples"`
+
+	// CooldownMinutes is the minimum time between alerts (default 60).
+	CooldownMinutes int `yaml:"cooldown_minutes" json:"cooldown_minutes"`
+}
+
+// DefaultAnomalyConfig returns default configuration (disabled, like the
+// other opt-in routines).
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{
+		Enabled:            false,
+		WindowSnapshots:    5,
+		MaxErrorRate:       0.3,
+		MaxToolFailureRate: 0.5,
+		MaxAvgLatencyMs:    20000,
+		MinSamples:         5,
+		CooldownMinutes:    60,
+	}
+}
+
+// anomalyFinding is one threshold breach detected in the window.
+type anomalyFinding struct {
+	Metric     string
+	Detail     string
+	Suggestion string
+}
+
+// AnomalyMonitor consumes metrics snapshots and alerts the owner on
+// threshold breaches.
+type AnomalyMonitor struct {
+	config    AnomalyConfig
+	assistant *Assistant
+	logger    *slog.Logger
+	cancel    context.CancelFunc
+
+	window    []MetricsSnapshot
+	lastAlert time.Time
+}
+
+// NewAnomalyMonitor creates a new anomaly monitor.
+func NewAnomalyMonitor(cfg AnomalyConfig, assistant *Assistant, logger *slog.Logger) *AnomalyMonitor {
+	defaults := DefaultAnomalyConfig()
+	if cfg.WindowSnapshots <= 1 {
+		cfg.WindowSnapshots = defaults.WindowSnapshots
+	}
+	if cfg.MaxErrorRate <= 0 {
+		cfg.MaxErrorRate = defaults.MaxErrorRate
+	}
+	if cfg.MaxToolFailureRate <= 0 {
+		cfg.MaxToolFailureRate = defaults.MaxToolFailureRate
+	}
+	if cfg.MaxAvgLatencyMs <= 0 {
+		cfg.MaxAvgLatencyMs = defaults.MaxAvgLatencyMs
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = defaults.MinSamples
+	}
+	if cfg.CooldownMinutes <= 0 {
+		cfg.CooldownMinutes = defaults.CooldownMinutes
+	}
+	return &AnomalyMonitor{
+		config:    cfg,
+		assistant: assistant,
+		logger:    logger.With("component", "anomaly-monitor"),
+	}
+}
+
+// Start begins consuming metrics snapshots in a background goroutine.
+func (m *AnomalyMonitor) Start(ctx context.Context) {
+	if !m.config.Enabled {
+		m.logger.Debug("anomaly monitor disabled")
+		return
+	}
+	if m.assistant == nil || m.assistant.metricsCollector == nil {
+		m.logger.Warn("anomaly monitor requires the metrics collector — not started")
+		return
+	}
+
+	mCtx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+
+	snapshots := m.assistant.metricsCollector.Subscribe()
+	m.logger.Info("anomaly monitor started",
+		"window_snapshots", m.config.WindowSnapshots,
+		"max_error_rate", m.config.MaxErrorRate,
+		"max_tool_failure_rate", m.config.MaxToolFailureRate,
+		"max_avg_latency_ms", m.config.MaxAvgLatencyMs,
+	)
+
+	go m.loop(mCtx, snapshots)
+}
+
+// Stop shuts down the anomaly monitor.
+func (m *AnomalyMonitor) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+// loop consumes snapshots and fires alerts. Runs until the context ends.
+func (m *AnomalyMonitor) loop(ctx context.Context, snapshots <-chan MetricsSnapshot) {
+	for {
+		select {
+		case snapshot, ok := <-snapshots:
+			if !ok {
+				return
+			}
+			m.observe(ctx, snapshot)
+		case <-ctx.Done():
+			m.logger.Info("anomaly monitor stopped")
+			return
+		}
+	}
+}
+
+// observe adds a snapshot to the window and alerts on anomalies, honoring
+// the alert cooldown.
+func (m *AnomalyMonitor) observe(ctx context.Context, snapshot MetricsSnapshot) {
+	m.window = append(m.window, snapshot)
+	if len(m.window) > m.config.WindowSnapshots {
+		m.window = m.window[len(m.window)-m.config.WindowSnapshots:]
+	}
+	if len(m.window) < 2 {
+		return
+	}
+
+	findings := detectAnomalies(m.window, m.config)
+	if len(findings) == 0 {
+		return
+	}
+	if time.Since(m.lastAlert) < time.Duration(m.config.CooldownMinutes)*time.Minute {
+		m.logger.Debug("anomaly detected but alert is in cooldown", "findings", len(findings))
+		return
+	}
+
+	text := m.buildAlert(findings)
+	channel, chatID := m.deliveryTarget()
+	if channel == "" || chatID == "" {
+		m.logger.Warn("anomaly alert has no delivery target")
+		return
+	}
+	// Urgent: anomaly alerts indicate the agent itself is degraded.
+	if err := m.assistant.sendProactive(ctx, channel, chatID, text, true); err != nil {
+		m.logger.Warn("anomaly alert delivery failed", "channel", channel, "error", err)
+		return
+	}
+	m.lastAlert = time.Now()
+	m.logger.Info("anomaly alert delivered", "findings", len(findings), "channel", channel)
+}
+
+// detectAnomalies checks the windowed deltas against the configured
+// thresholds. The counters in a snapshot are cumulative, so the window's
+// activity is the difference between its newest and oldest snapshots.
+func detectAnomalies(window []MetricsSnapshot, cfg AnomalyConfig) []anomalyFinding {
+	if len(window) < 2 {
+		return nil
+	}
+	oldest, newest := window[0], window[len(window)-1]
+	var findings []anomalyFinding
+
+	// LLM error rate: failed + timed-out agent runs over total runs.
+	runs := newest.AgentRunsTotal - oldest.AgentRunsTotal
+	runFailures := (newest.AgentRunsFailed - oldest.AgentRunsFailed) +
+		(newest.AgentRunsTimeout - oldest.AgentRunsTimeout)
+	if runs >= cfg.MinSamples {
+		rate := float64(runFailures) / float64(runs)
+		if rate > cfg.MaxErrorRate {
+			findings = append(findings, anomalyFinding{
+				Metric: "LLM error rate",
+				Detail: fmt.Sprintf("%.0f%% of %d agent runs failed (threshold %.0f%%)",
+					rate*100, runs, cfg.MaxErrorRate*100),
+				Suggestion: "Check the provider's status page; consider switching the model or enabling a fallback (`/llm` shows per-model health).",
+			})
+		}
+	}
+
+	// Tool failure rate.
+	calls := newest.ToolCallsTotal - oldest.ToolCallsTotal
+	callFailures := newest.ToolCallsFailed - oldest.ToolCallsFailed
+	if calls >= cfg.MinSamples {
+		rate := float64(callFailures) / float64(calls)
+		if rate > cfg.MaxToolFailureRate {
+			findings = append(findings, anomalyFinding{
+				Metric: "tool failure rate",
+				Detail: fmt.Sprintf("%.0f%% of %d tool calls failed (threshold %.0f%%)",
+					rate*100, calls, cfg.MaxToolFailureRate*100),
+				Suggestion: "Check recent tool errors in the logs; a hung dependency (network, disk, API quota) usually shows up here first.",
+			})
+		}
+	}
+
+	// Average latency across the window (snapshots without samples are 0).
+	var latencySum, latencyCount int64
+	for _, s := range window {
+		if s.LatencyAvgMs > 0 {
+			latencySum += s.LatencyAvgMs
+			latencyCount++
+		}
+	}
+	if latencyCount > 0 {
+		avg := latencySum / latencyCount
+		if avg > cfg.MaxAvgLatencyMs {
+			findings = append(findings, anomalyFinding{
+				Metric: "average latency",
+				Detail: fmt.Sprintf("%.1fs average over the window (threshold %.1fs)",
+					float64(avg)/1000, float64(cfg.MaxAvgLatencyMs)/1000),
+				Suggestion: "The provider may be degraded — consider a faster model or check `prefer_fallback_p95_seconds` to fail over automatically.",
+			})
+		}
+	}
+
+	return findings
+}
+
+// buildAlert renders the owner notification, enriched with per-model health
+// where available.
+func (m *AnomalyMonitor) buildAlert(findings []anomalyFinding) string {
+	var b strings.Builder
+	b.WriteString("🚨 *Anomaly detected*")
+	for _, f := range findings {
+		fmt.Fprintf(&b, "\n\n*%s*: %s\n→ %s", f.Metric, f.Detail, f.Suggestion)
+	}
+
+	if m.assistant != nil && m.assistant.llmClient != nil {
+		if model, until := m.assistant.llmClient.CooldownStatus(); model != "" {
+			fmt.Fprintf(&b, "\n\nNote: %s is rate-limited until %s.", model, until.Format("15:04"))
+		}
+		for _, s := range m.assistant.llmClient.HealthStats() {
+			if s.Requests >= 3 && s.ErrorRate > 0.2 && s.LastError != "" {
+				fmt.Fprintf(&b, "\nModel %s: %.0f%% errors, last: %s", s.Model, s.ErrorRate*100, s.LastError)
+			}
+		}
+	}
+	return b.String()
+}
+
+// deliveryTarget resolves where anomaly alerts go: the config override when
+// set, otherwise the most recently active session.
+func (m *AnomalyMonitor) deliveryTarget() (channel, chatID string) {
+	if m.config.Channel != "" && m.config.ChatID != "" {
+		return m.config.Channel, m.config.ChatID
+	}
+	if m.assistant == nil || m.assistant.workspaceMgr == nil {
+		return "", ""
+	}
+	var latest time.Time
+	for _, info := range m.assistant.workspaceMgr.ListAllSessions() {
+		if info.LastActiveAt.After(latest) && info.Channel != "" && info.ChatID != "" {
+			latest = info.LastActiveAt
+			channel = info.Channel
+			chatID = info.ChatID
+		}
+	}
+	return channel, chatID
+}
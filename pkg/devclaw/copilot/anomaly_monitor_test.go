@@ -0,0 +1,85 @@
+// Package copilot – anomaly_monitor_test.go contains unit tests for the
+// anomaly detection logic.
+package copilot
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func anomalyTestConfig() AnomalyConfig {
+	cfg := DefaultAnomalyConfig()
+	cfg.Enabled = true
+	return cfg
+}
+
+func TestDetectAnomaliesErrorRate(t *testing.T) {
+	window := []MetricsSnapshot{
+		{AgentRunsTotal: 100, AgentRunsFailed: 10},
+		{AgentRunsTotal: 110, AgentRunsFailed: 15, AgentRunsTimeout: 0},
+	}
+	// 5 failures out of 10 runs = 50% > 30% threshold.
+	findings := detectAnomalies(window, anomalyTestConfig())
+	if len(findings) != 1 || findings[0].Metric != "LLM error rate" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+	if !strings.Contains(findings[0].Detail, "50%") {
+		t.Errorf("unexpected detail: %s", findings[0].Detail)
+	}
+}
+
+func TestDetectAnomaliesMinSamples(t *testing.T) {
+	// 2 of 3 runs failed, but 3 < MinSamples — no alert.
+	window := []MetricsSnapshot{
+		{AgentRunsTotal: 0},
+		{AgentRunsTotal: 3, AgentRunsFailed: 2},
+	}
+	if findings := detectAnomalies(window, anomalyTestConfig()); len(findings) != 0 {
+		t.Errorf("expected no findings below min samples, got %+v", findings)
+	}
+}
+
+func TestDetectAnomaliesToolFailures(t *testing.T) {
+	window := []MetricsSnapshot{
+		{ToolCallsTotal: 50, ToolCallsFailed: 5},
+		{ToolCallsTotal: 70, ToolCallsFailed: 18},
+	}
+	// 13 of 20 calls failed = 65% > 50% threshold.
+	findings := detectAnomalies(window, anomalyTestConfig())
+	if len(findings) != 1 || findings[0].Metric != "tool failure rate" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestDetectAnomaliesLatency(t *testing.T) {
+	window := []MetricsSnapshot{
+		{LatencyAvgMs: 25000},
+		{LatencyAvgMs: 31000},
+		{LatencyAvgMs: 0}, // no samples in this interval, excluded
+	}
+	findings := detectAnomalies(window, anomalyTestConfig())
+	if len(findings) != 1 || findings[0].Metric != "average latency" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+	if !strings.Contains(findings[0].Detail, "28.0s") {
+		t.Errorf("unexpected detail: %s", findings[0].Detail)
+	}
+}
+
+func TestDetectAnomaliesHealthy(t *testing.T) {
+	window := []MetricsSnapshot{
+		{AgentRunsTotal: 100, AgentRunsFailed: 2, ToolCallsTotal: 200, ToolCallsFailed: 10, LatencyAvgMs: 1500},
+		{AgentRunsTotal: 120, AgentRunsFailed: 3, ToolCallsTotal: 260, ToolCallsFailed: 12, LatencyAvgMs: 1800},
+	}
+	if findings := detectAnomalies(window, anomalyTestConfig()); len(findings) != 0 {
+		t.Errorf("expected no findings for healthy metrics, got %+v", findings)
+	}
+}
+
+func TestAnomalyMonitorDefaults(t *testing.T) {
+	m := NewAnomalyMonitor(AnomalyConfig{Enabled: true}, nil, slog.Default())
+	if m.config.WindowSnapshots != 5 || m.config.MaxErrorRate != 0.3 || m.config.CooldownMinutes != 60 {
+		t.Errorf("defaults not applied: %+v", m.config)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -82,13 +83,13 @@ func RegisterApplyPatchTool(executor *ToolExecutor) {
 		Type: "function",
 		Function: FunctionDef{
 			Name:        "apply_patch",
-			Description: "Apply a patch to one or more files using the apply_patch format. The input MUST start with '*** Begin Patch' and end with '*** End Patch'. Used for surgical multi-file edits.",
+			Description: "Apply a patch to one or more files. Accepts the apply_patch format ('*** Begin Patch' ... '*** End Patch') or a standard unified diff ('--- a/file' / '+++ b/file' / '@@' hunks). Multi-file patches are applied atomically: if any hunk fails, no file is changed. Used for surgical multi-file edits.",
 			Parameters: mustJSON(map[string]any{
 				"type": "object",
 				"properties": map[string]any{
 					"input": map[string]any{
 						"type":        "string",
-						"description": "Patch content using the *** Begin Patch/End Patch format.",
+						"description": "Patch content using the *** Begin Patch/End Patch format or a unified diff.",
 					},
 					"working_dir": map[string]any{
 						"type":        "string",
@@ -108,6 +109,9 @@ func RegisterApplyPatchTool(executor *ToolExecutor) {
 		if wd, ok := args["working_dir"].(string); ok && wd != "" {
 			cwd = wd
 		}
+		// working_dir is model-controlled and may be absolute — confine it to
+		// the caller's session jail like the other file-writing tools.
+		cwd = executor.ResolveToolPath(ctx, cwd)
 
 		result, err := applyPatch(ctx, input, cwd)
 		if err != nil {
@@ -118,8 +122,22 @@ func RegisterApplyPatchTool(executor *ToolExecutor) {
 	})
 }
 
+// patchOp is one planned filesystem change. All ops are computed before any
+// file is touched so a multi-file patch either applies fully or not at all.
+type patchOp struct {
+	path     string // resolved path within cwd
+	contents string // final contents for writes; unused for deletes
+	delete   bool
+}
+
 func applyPatch(ctx context.Context, input string, cwd string) (*ApplyPatchResult, error) {
-	hunks, err := parsePatchText(input)
+	var hunks []Hunk
+	var err error
+	if looksLikeUnifiedDiff(input) {
+		hunks, err = parseUnifiedDiff(input)
+	} else {
+		hunks, err = parsePatchText(input)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +154,27 @@ func applyPatch(ctx context.Context, input string, cwd string) (*ApplyPatchResul
 	seenModified := make(map[string]bool)
 	seenDeleted := make(map[string]bool)
 
+	// Plan phase: validate every hunk and compute final contents in memory.
+	// The overlay tracks contents written by earlier hunks so later hunks in
+	// the same patch see the planned state, not the on-disk state.
+	var ops []patchOp
+	overlay := make(map[string]string)
+	removed := make(map[string]bool)
+
+	readPlanned := func(path string) (string, error) {
+		if removed[path] {
+			return "", fmt.Errorf("file %s was deleted earlier in the patch", path)
+		}
+		if contents, ok := overlay[path]; ok {
+			return contents, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read file to update %s: %w", path, err)
+		}
+		return string(data), nil
+	}
+
 	for _, hunk := range hunks {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
@@ -147,12 +186,9 @@ func applyPatch(ctx context.Context, input string, cwd string) (*ApplyPatchResul
 			if err != nil {
 				return nil, err
 			}
-			if err := ensureDir(targetPath); err != nil {
-				return nil, fmt.Errorf("ensuring dir for %s: %w", targetPath, err)
-			}
-			if err := os.WriteFile(targetPath, []byte(hunk.Add.Contents), 0644); err != nil {
-				return nil, fmt.Errorf("writing file %s: %w", targetPath, err)
-			}
+			ops = append(ops, patchOp{path: targetPath, contents: hunk.Add.Contents})
+			overlay[targetPath] = hunk.Add.Contents
+			delete(removed, targetPath)
 			recordSummary(summary, seenAdded, "added", hunk.Add.Path)
 
 		case HunkDelete:
@@ -160,9 +196,9 @@ func applyPatch(ctx context.Context, input string, cwd string) (*ApplyPatchResul
 			if err != nil {
 				return nil, err
 			}
-			if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
-				return nil, fmt.Errorf("deleting file %s: %w", targetPath, err)
-			}
+			ops = append(ops, patchOp{path: targetPath, delete: true})
+			removed[targetPath] = true
+			delete(overlay, targetPath)
 			recordSummary(summary, seenDeleted, "deleted", hunk.Delete.Path)
 
 		case HunkUpdate:
@@ -170,7 +206,11 @@ func applyPatch(ctx context.Context, input string, cwd string) (*ApplyPatchResul
 			if err != nil {
 				return nil, err
 			}
-			applied, err := applyUpdateHunk(targetPath, hunk.Update.Chunks)
+			original, err := readPlanned(targetPath)
+			if err != nil {
+				return nil, fmt.Errorf("updating file %s: %w", targetPath, err)
+			}
+			applied, err := applyUpdateChunks(original, hunk.Update.Chunks, targetPath)
 			if err != nil {
 				return nil, fmt.Errorf("updating file %s: %w", targetPath, err)
 			}
@@ -180,32 +220,85 @@ func applyPatch(ctx context.Context, input string, cwd string) (*ApplyPatchResul
 				if err != nil {
 					return nil, err
 				}
-				if err := ensureDir(moveTarget); err != nil {
-					return nil, fmt.Errorf("ensuring dir for %s: %w", moveTarget, err)
-				}
-				if err := os.WriteFile(moveTarget, []byte(applied), 0644); err != nil {
-					return nil, fmt.Errorf("writing moved file %s: %w", moveTarget, err)
-				}
+				ops = append(ops, patchOp{path: moveTarget, contents: applied})
+				overlay[moveTarget] = applied
+				delete(removed, moveTarget)
 				// Remove old file if it's a different path
 				if targetPath != moveTarget {
-					if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
-						return nil, fmt.Errorf("deleting old file %s during move: %w", targetPath, err)
-					}
+					ops = append(ops, patchOp{path: targetPath, delete: true})
+					removed[targetPath] = true
+					delete(overlay, targetPath)
 				}
 				recordSummary(summary, seenModified, "modified", hunk.Update.MovePath)
 			} else {
-				if err := os.WriteFile(targetPath, []byte(applied), 0644); err != nil {
-					return nil, fmt.Errorf("writing updated file %s: %w", targetPath, err)
-				}
+				ops = append(ops, patchOp{path: targetPath, contents: applied})
+				overlay[targetPath] = applied
 				recordSummary(summary, seenModified, "modified", hunk.Update.Path)
 			}
 		}
 	}
 
+	if err := commitPatchOps(ops); err != nil {
+		return nil, err
+	}
+
 	summary.Text = formatSummary(summary)
 	return summary, nil
 }
 
+// commitPatchOps executes the planned changes. Before a file is touched for
+// the first time its original state is snapshotted; any write failure rolls
+// every file back so a partially applied patch never hits disk.
+func commitPatchOps(ops []patchOp) error {
+	type snapshot struct {
+		contents []byte
+		existed  bool
+	}
+	snapshots := make(map[string]snapshot)
+	var order []string
+
+	rollback := func() {
+		// Restore in reverse touch order so moves unwind cleanly.
+		for i := len(order) - 1; i >= 0; i-- {
+			path := order[i]
+			snap := snapshots[path]
+			if snap.existed {
+				os.WriteFile(path, snap.contents, 0644) //nolint:errcheck // best-effort restore
+			} else {
+				os.Remove(path) //nolint:errcheck // best-effort restore
+			}
+		}
+	}
+
+	for _, op := range ops {
+		if _, ok := snapshots[op.path]; !ok {
+			if data, err := os.ReadFile(op.path); err == nil {
+				snapshots[op.path] = snapshot{contents: data, existed: true}
+			} else {
+				snapshots[op.path] = snapshot{existed: false}
+			}
+			order = append(order, op.path)
+		}
+
+		if op.delete {
+			if err := os.Remove(op.path); err != nil && !os.IsNotExist(err) {
+				rollback()
+				return fmt.Errorf("deleting file %s: %w", op.path, err)
+			}
+			continue
+		}
+		if err := ensureDir(op.path); err != nil {
+			rollback()
+			return fmt.Errorf("ensuring dir for %s: %w", op.path, err)
+		}
+		if err := os.WriteFile(op.path, []byte(op.contents), 0644); err != nil {
+			rollback()
+			return fmt.Errorf("writing file %s: %w", op.path, err)
+		}
+	}
+	return nil
+}
+
 func recordSummary(summary *ApplyPatchResult, seen map[string]bool, bucket string, path string) {
 	if seen[path] {
 		return
@@ -507,16 +600,194 @@ func parseUpdateFileChunk(lines []string, lineNumber int, allowMissingContext bo
 }
 
 // ----------------------------------------------------------------------------
-// Applying Updates to Files
+// Unified Diff Parsing
 // ----------------------------------------------------------------------------
 
-func applyUpdateHunk(filePath string, chunks []UpdateFileChunk) (string, error) {
-	content, err := os.ReadFile(filePath)
+// looksLikeUnifiedDiff reports whether the input is a standard unified diff
+// (git diff / diff -u output) rather than the native Begin/End Patch format.
+func looksLikeUnifiedDiff(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	if strings.Contains(trimmed, BeginPatchMarker) {
+		return false
+	}
+	return strings.HasPrefix(trimmed, "diff --git ") ||
+		strings.HasPrefix(trimmed, "--- ") ||
+		strings.Contains(trimmed, "\n--- ")
+}
+
+// parseUnifiedDiff converts a unified diff into the same Hunk representation
+// used by the native format. '--- /dev/null' maps to an Add, '+++ /dev/null'
+// to a Delete, and differing old/new paths to an Update with a move. Hunk
+// line counts are validated against the @@ headers before anything applies.
+func parseUnifiedDiff(input string) ([]Hunk, error) {
+	input = strings.ReplaceAll(strings.TrimSpace(input), "\r\n", "\n")
+	lines := strings.Split(input, "\n")
+
+	var hunks []Hunk
+	i := 0
+	for i < len(lines) {
+		if !strings.HasPrefix(lines[i], "--- ") {
+			// Skip diff --git / index / mode lines between file sections.
+			i++
+			continue
+		}
+		if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+			return nil, fmt.Errorf("invalid unified diff at line %d: '---' header without a matching '+++' header", i+1)
+		}
+		oldPath := parseUnifiedDiffPath(lines[i][4:])
+		newPath := parseUnifiedDiffPath(lines[i+1][4:])
+		if oldPath == "" && newPath == "" {
+			return nil, fmt.Errorf("invalid unified diff at line %d: both sides are /dev/null", i+1)
+		}
+		i += 2
+
+		var chunks []UpdateFileChunk
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@") {
+			oldCount, newCount, err := parseUnifiedHunkHeader(lines[i], i+1)
+			if err != nil {
+				return nil, err
+			}
+			headerLine := i
+			i++
+
+			chunk := UpdateFileChunk{OldLines: []string{}, NewLines: []string{}}
+			gotOld, gotNew := 0, 0
+			for i < len(lines) && (gotOld < oldCount || gotNew < newCount) {
+				line := lines[i]
+				if strings.HasPrefix(line, `\ No newline`) {
+					i++
+					continue
+				}
+				if line == "" {
+					// Tolerate context lines whose leading space was stripped.
+					chunk.OldLines = append(chunk.OldLines, "")
+					chunk.NewLines = append(chunk.NewLines, "")
+					gotOld++
+					gotNew++
+					i++
+					continue
+				}
+				switch line[0] {
+				case ' ':
+					chunk.OldLines = append(chunk.OldLines, line[1:])
+					chunk.NewLines = append(chunk.NewLines, line[1:])
+					gotOld++
+					gotNew++
+				case '-':
+					chunk.OldLines = append(chunk.OldLines, line[1:])
+					gotOld++
+				case '+':
+					chunk.NewLines = append(chunk.NewLines, line[1:])
+					gotNew++
+				default:
+					return nil, fmt.Errorf("invalid unified diff at line %d: unexpected line %q inside hunk", i+1, line)
+				}
+				i++
+			}
+			if gotOld != oldCount || gotNew != newCount {
+				return nil, fmt.Errorf("invalid unified diff at line %d: hunk has %d old / %d new lines but the header declares %d / %d",
+					headerLine+1, gotOld, gotNew, oldCount, newCount)
+			}
+			chunks = append(chunks, chunk)
+		}
+		if len(chunks) == 0 {
+			return nil, fmt.Errorf("invalid unified diff: no @@ hunks for %s", oldPath+newPath)
+		}
+
+		switch {
+		case oldPath == "":
+			// New file: contents are the added lines of all hunks.
+			var contents strings.Builder
+			for _, chunk := range chunks {
+				if len(chunk.OldLines) > 0 {
+					return nil, fmt.Errorf("invalid unified diff: new file %s has removed or context lines", newPath)
+				}
+				for _, line := range chunk.NewLines {
+					contents.WriteString(line)
+					contents.WriteString("\n")
+				}
+			}
+			hunks = append(hunks, Hunk{
+				Kind: HunkAdd,
+				Add:  &AddFileHunk{Path: newPath, Contents: contents.String()},
+			})
+		case newPath == "":
+			hunks = append(hunks, Hunk{
+				Kind:   HunkDelete,
+				Delete: &DeleteFileHunk{Path: oldPath},
+			})
+		default:
+			update := &UpdateFileHunk{Path: oldPath, Chunks: chunks}
+			if oldPath != newPath {
+				update.MovePath = newPath
+			}
+			hunks = append(hunks, Hunk{Kind: HunkUpdate, Update: update})
+		}
+	}
+
+	if len(hunks) == 0 {
+		return nil, errors.New("invalid unified diff: no file headers found")
+	}
+	return hunks, nil
+}
+
+// parseUnifiedDiffPath cleans one side of a '---'/'+++' header: strips the
+// optional timestamp, the a/ or b/ prefix, and maps /dev/null to "".
+func parseUnifiedDiffPath(raw string) string {
+	path := strings.TrimSpace(raw)
+	if idx := strings.IndexByte(path, '\t'); idx >= 0 {
+		path = path[:idx]
+	}
+	if path == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		path = path[2:]
+	}
+	return path
+}
+
+// parseUnifiedHunkHeader extracts the old and new line counts from an
+// '@@ -start,count +start,count @@' header.
+func parseUnifiedHunkHeader(line string, lineNumber int) (oldCount, newCount int, err error) {
+	rest := strings.TrimPrefix(line, "@@")
+	end := strings.Index(rest, "@@")
+	if end < 0 {
+		return 0, 0, fmt.Errorf("invalid unified diff at line %d: malformed hunk header %q", lineNumber, line)
+	}
+	fields := strings.Fields(rest[:end])
+	if len(fields) != 2 || !strings.HasPrefix(fields[0], "-") || !strings.HasPrefix(fields[1], "+") {
+		return 0, 0, fmt.Errorf("invalid unified diff at line %d: malformed hunk header %q", lineNumber, line)
+	}
+	oldCount, err = parseUnifiedRangeCount(fields[0][1:])
+	if err == nil {
+		newCount, err = parseUnifiedRangeCount(fields[1][1:])
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to read file to update %s: %w", filePath, err)
+		return 0, 0, fmt.Errorf("invalid unified diff at line %d: malformed hunk header %q", lineNumber, line)
 	}
+	return oldCount, newCount, nil
+}
+
+// parseUnifiedRangeCount parses the count of a 'start,count' range; the
+// count defaults to 1 when omitted.
+func parseUnifiedRangeCount(r string) (int, error) {
+	countPart := r
+	if idx := strings.IndexByte(r, ','); idx >= 0 {
+		countPart = r[idx+1:]
+	} else if _, err := strconv.Atoi(r); err != nil {
+		return 0, err
+	} else {
+		return 1, nil
+	}
+	return strconv.Atoi(countPart)
+}
+
+// ----------------------------------------------------------------------------
+// Applying Updates to Files
+// ----------------------------------------------------------------------------
 
-	originalContents := string(content)
+func applyUpdateChunks(originalContents string, chunks []UpdateFileChunk, filePath string) (string, error) {
 	// Normalize CRLF
 	originalContents = strings.ReplaceAll(originalContents, "\r\n", "\n")
 	originalLines := strings.Split(originalContents, "\n")
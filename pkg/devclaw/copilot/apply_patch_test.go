@@ -209,3 +209,172 @@ func TestApplyPatch_MovePathTraversalRejected(t *testing.T) {
 		t.Errorf("expected path escapes error, got: %v", err)
 	}
 }
+
+// Unified diff input
+
+func TestApplyPatch_UnifiedDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "file1.txt"), []byte("line 1\nline 2\nline 3\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patch := `--- a/file1.txt
++++ b/file1.txt
+@@ -1,3 +1,3 @@
+ line 1
+-line 2
++new line 2
+ line 3`
+
+	res, err := applyPatch(context.Background(), patch, tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Modified) != 1 || res.Modified[0] != "file1.txt" {
+		t.Errorf("expected 1 modified file, got %v", res.Modified)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "file1.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "line 1\nnew line 2\nline 3\n"
+	if string(content) != expected {
+		t.Errorf("unexpected content: got %q, want %q", string(content), expected)
+	}
+}
+
+func TestApplyPatch_UnifiedDiffAddAndDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "old.txt"), []byte("obsolete\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patch := `diff --git a/new.txt b/new.txt
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++first
++second
+diff --git a/old.txt b/old.txt
+--- a/old.txt
++++ /dev/null
+@@ -1,1 +0,0 @@
+-obsolete`
+
+	res, err := applyPatch(context.Background(), patch, tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Added) != 1 || res.Added[0] != "new.txt" {
+		t.Errorf("expected 1 added file, got %v", res.Added)
+	}
+	if len(res.Deleted) != 1 || res.Deleted[0] != "old.txt" {
+		t.Errorf("expected 1 deleted file, got %v", res.Deleted)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "new.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "first\nsecond\n" {
+		t.Errorf("unexpected content in new.txt: %q", string(content))
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "old.txt")); !os.IsNotExist(err) {
+		t.Error("old.txt should have been deleted")
+	}
+}
+
+func TestApplyPatch_UnifiedDiffBadCounts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "file1.txt"), []byte("line 1\nline 2\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Header claims two old lines but the hunk only has one.
+	patch := `--- a/file1.txt
++++ b/file1.txt
+@@ -1,2 +1,1 @@
+-line 1`
+
+	_, err = applyPatch(context.Background(), patch, tmpDir)
+	if err == nil || !strings.Contains(err.Error(), "header declares") {
+		t.Errorf("expected hunk count validation error, got %v", err)
+	}
+}
+
+// Atomicity
+
+func TestApplyPatch_AtomicRollback(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	original := "keep me\n"
+	err := os.WriteFile(filepath.Join(tmpDir, "file1.txt"), []byte(original), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// First hunk is valid, second targets a file that does not exist.
+	patch := `*** Begin Patch
+*** Update File: file1.txt
+@@
+-keep me
++changed
+*** Update File: missing.txt
+@@
+-nope
++still nope
+*** End Patch`
+
+	_, err = applyPatch(context.Background(), patch, tmpDir)
+	if err == nil {
+		t.Fatal("expected error for missing file, got nil")
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "file1.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != original {
+		t.Errorf("file1.txt should be untouched after failed patch: got %q, want %q", string(content), original)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "missing.txt")); !os.IsNotExist(err) {
+		t.Error("missing.txt should not have been created")
+	}
+}
+
+func TestApplyPatch_LaterHunkSeesEarlierChange(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// An Update following an Add in the same patch must see the added file.
+	patch := `*** Begin Patch
+*** Add File: combo.txt
++step one
+*** Update File: combo.txt
+@@
+-step one
++step two
+*** End Patch`
+
+	res, err := applyPatch(context.Background(), patch, tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Added) != 1 || res.Added[0] != "combo.txt" {
+		t.Errorf("expected combo.txt to be recorded as added, got %v", res.Added)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "combo.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "step two\n" {
+		t.Errorf("unexpected content: %q", string(content))
+	}
+}
@@ -0,0 +1,213 @@
+// Package copilot – archive_extract.go extracts text from zip/tar attachments
+// so users can send a zipped log bundle and ask questions about its contents.
+// Extraction is bounded by entry count, per-file and total size, and nesting
+// depth to keep hostile archives (zip bombs, deep nesting) from exhausting
+// memory.
+package copilot
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Extraction limits for archive attachments.
+const (
+	maxArchiveEntries    = 200              // entries read across the whole archive
+	maxArchiveEntryBytes = 2 * 1024 * 1024  // per extracted file
+	maxArchiveTotalBytes = 10 * 1024 * 1024 // total extracted text
+	maxArchiveDepth      = 2                // nested archives (zip inside zip)
+)
+
+// archiveBudget tracks extraction limits across nested archives.
+type archiveBudget struct {
+	entries int
+	bytes   int
+	skipped int
+}
+
+func (b *archiveBudget) exhausted() bool {
+	return b.entries >= maxArchiveEntries || b.bytes >= maxArchiveTotalBytes
+}
+
+// sniffArchiveType detects archive formats from magic bytes, ignoring the
+// declared MIME type. Returns "zip", "gzip", "tar", or "".
+func sniffArchiveType(data []byte) string {
+	switch {
+	case len(data) >= 4 && bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return "zip"
+	case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+		return "gzip"
+	case len(data) >= 262 && string(data[257:262]) == "ustar":
+		return "tar"
+	default:
+		return ""
+	}
+}
+
+// extractArchiveText extracts readable text from a zip, tar, or tar.gz
+// attachment within the package limits. Returns empty string for
+// unsupported or unreadable archives.
+func extractArchiveText(data []byte, filename string, logger *slog.Logger) string {
+	var b strings.Builder
+	budget := &archiveBudget{}
+
+	if err := walkArchive(data, filename, 1, budget, &b, logger); err != nil {
+		logger.Warn("archive extraction failed", "filename", filename, "error", err)
+		return fmt.Sprintf("[Unable to read archive %s: %v]", filename, err)
+	}
+
+	if b.Len() == 0 {
+		return fmt.Sprintf("[Archive %s contains no extractable text files.]", filename)
+	}
+	if budget.skipped > 0 {
+		fmt.Fprintf(&b, "[%d binary or oversized file(s) skipped.]\n", budget.skipped)
+	}
+	if budget.exhausted() {
+		b.WriteString("[Archive truncated: extraction limits reached.]\n")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// walkArchive dispatches on the sniffed archive format and appends extracted
+// entries to the builder.
+func walkArchive(data []byte, filename string, depth int, budget *archiveBudget, b *strings.Builder, logger *slog.Logger) error {
+	switch sniffArchiveType(data) {
+	case "zip":
+		return walkZip(data, depth, budget, b, logger)
+	case "gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("reading gzip: %w", err)
+		}
+		defer gz.Close()
+		inner, err := io.ReadAll(io.LimitReader(gz, maxArchiveTotalBytes))
+		if err != nil {
+			return fmt.Errorf("decompressing gzip: %w", err)
+		}
+		if sniffArchiveType(inner) == "tar" {
+			return walkTar(inner, depth, budget, b, logger)
+		}
+		// Single gzipped file (e.g. app.log.gz).
+		appendArchiveEntry(strings.TrimSuffix(filename, ".gz"), inner, depth, budget, b, logger)
+		return nil
+	case "tar":
+		return walkTar(data, depth, budget, b, logger)
+	default:
+		return fmt.Errorf("unsupported archive format")
+	}
+}
+
+// walkZip extracts entries from a zip archive.
+func walkZip(data []byte, depth int, budget *archiveBudget, b *strings.Builder, logger *slog.Logger) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("reading zip: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if budget.exhausted() {
+			return nil
+		}
+		if f.FileInfo().IsDir() || !safeArchivePath(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			logger.Debug("skipping unreadable zip entry", "name", f.Name, "error", err)
+			budget.skipped++
+			continue
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxArchiveEntryBytes+1))
+		rc.Close()
+		if err != nil {
+			budget.skipped++
+			continue
+		}
+		appendArchiveEntry(f.Name, content, depth, budget, b, logger)
+	}
+	return nil
+}
+
+// walkTar extracts entries from a tar archive.
+func walkTar(data []byte, depth int, budget *archiveBudget, b *strings.Builder, logger *slog.Logger) error {
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		if budget.exhausted() {
+			return nil
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading tar: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !safeArchivePath(hdr.Name) {
+			continue
+		}
+		content, err := io.ReadAll(io.LimitReader(tr, maxArchiveEntryBytes+1))
+		if err != nil {
+			budget.skipped++
+			continue
+		}
+		appendArchiveEntry(hdr.Name, content, depth, budget, b, logger)
+	}
+}
+
+// appendArchiveEntry adds one extracted file to the output, recursing into
+// nested archives while depth remains.
+func appendArchiveEntry(name string, content []byte, depth int, budget *archiveBudget, b *strings.Builder, logger *slog.Logger) {
+	if len(content) > maxArchiveEntryBytes {
+		budget.skipped++
+		return
+	}
+
+	// Nested archive — recurse within the depth limit.
+	if sniffArchiveType(content) != "" {
+		if depth >= maxArchiveDepth {
+			budget.skipped++
+			return
+		}
+		if err := walkArchive(content, name, depth+1, budget, b, logger); err != nil {
+			logger.Debug("skipping nested archive", "name", name, "error", err)
+			budget.skipped++
+		}
+		return
+	}
+
+	if !archiveEntryIsText(name, content) {
+		budget.skipped++
+		return
+	}
+
+	budget.entries++
+	budget.bytes += len(content)
+	fmt.Fprintf(b, "===== %s (%d bytes) =====\n%s\n\n", name, len(content), strings.TrimSpace(string(content)))
+}
+
+// archiveEntryIsText reports whether an archive entry looks like readable text,
+// by extension first and content sniffing as fallback.
+func archiveEntryIsText(name string, content []byte) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if isPlainText("", ext) {
+		return true
+	}
+	detected := http.DetectContentType(content)
+	return strings.HasPrefix(detected, "text/")
+}
+
+// safeArchivePath rejects entry names that traverse outside the archive root.
+// Entries are never written to disk, but hostile names still get excluded
+// from the extracted text.
+func safeArchivePath(name string) bool {
+	clean := filepath.Clean(name)
+	return !strings.HasPrefix(clean, "..") && !filepath.IsAbs(clean)
+}
@@ -0,0 +1,126 @@
+package copilot
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func buildTestZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip create: %v", err)
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Fatalf("zip write: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func buildTestTarGz(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg,
+		}); err != nil {
+			t.Fatalf("tar header: %v", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("tar write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar close: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestSniffArchiveType(t *testing.T) {
+	zipData := buildTestZip(t, map[string][]byte{"a.txt": []byte("hi")})
+	if got := sniffArchiveType(zipData); got != "zip" {
+		t.Errorf("zip sniff = %q", got)
+	}
+	tgzData := buildTestTarGz(t, map[string][]byte{"a.txt": []byte("hi")})
+	if got := sniffArchiveType(tgzData); got != "gzip" {
+		t.Errorf("gzip sniff = %q", got)
+	}
+	if got := sniffArchiveType([]byte("just text")); got != "" {
+		t.Errorf("text sniff = %q", got)
+	}
+}
+
+func TestExtractArchiveTextZip(t *testing.T) {
+	logger := slog.Default()
+	data := buildTestZip(t, map[string][]byte{
+		"logs/app.log":  []byte("error: something broke"),
+		"binary.dat":    {0x00, 0x01, 0x02, 0x03},
+		"../escape.txt": []byte("outside"),
+		"readme.md":     []byte("# Bundle"),
+	})
+
+	text := extractArchiveText(data, "bundle.zip", logger)
+	if !strings.Contains(text, "logs/app.log") || !strings.Contains(text, "error: something broke") {
+		t.Errorf("missing log content:\n%s", text)
+	}
+	if !strings.Contains(text, "# Bundle") {
+		t.Errorf("missing readme content:\n%s", text)
+	}
+	if strings.Contains(text, "outside") {
+		t.Errorf("traversal entry was extracted:\n%s", text)
+	}
+	if !strings.Contains(text, "skipped") {
+		t.Errorf("expected skipped-files note:\n%s", text)
+	}
+}
+
+func TestExtractArchiveTextTarGz(t *testing.T) {
+	data := buildTestTarGz(t, map[string][]byte{
+		"var/log/syslog": []byte("kernel: oom-killer invoked"),
+	})
+	text := extractArchiveText(data, "logs.tar.gz", slog.Default())
+	if !strings.Contains(text, "oom-killer invoked") {
+		t.Errorf("missing tar.gz content:\n%s", text)
+	}
+}
+
+func TestExtractArchiveTextNestedDepthLimit(t *testing.T) {
+	inner := buildTestZip(t, map[string][]byte{"deep.txt": []byte("level two")})
+	tooDeep := buildTestZip(t, map[string][]byte{"inner.zip": inner})
+	outer := buildTestZip(t, map[string][]byte{"nested.zip": tooDeep, "top.txt": []byte("level zero")})
+
+	text := extractArchiveText(outer, "nested.zip", slog.Default())
+	if !strings.Contains(text, "level zero") {
+		t.Errorf("missing top-level content:\n%s", text)
+	}
+	// Depth limit is 2: the zip-in-zip-in-zip content must not appear.
+	if strings.Contains(text, "level two") {
+		t.Errorf("nested archive extracted beyond depth limit:\n%s", text)
+	}
+}
+
+func TestExtractDocumentTextSniffsArchives(t *testing.T) {
+	// Declared as text/plain, but the payload is a zip — sniffing wins.
+	data := buildTestZip(t, map[string][]byte{"notes.txt": []byte("sniffed content")})
+	text := extractDocumentText(data, "text/plain", "bundle.bin", slog.Default())
+	if !strings.Contains(text, "sniffed content") {
+		t.Errorf("archive sniffing did not kick in:\n%s", text)
+	}
+}
@@ -0,0 +1,264 @@
+// Package copilot – artifacts.go implements the artifact store: named,
+// versioned storage for long generated outputs (code, documents, reports).
+// Instead of chopping a large result into chat chunks, the agent writes it
+// as an artifact and references it with a short message; revisions in later
+// turns create new versions of the same name.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Artifact is one stored version of a named output.
+type Artifact struct {
+	ID        string
+	Name      string
+	Version   int
+	MimeType  string
+	Content   string
+	Size      int64
+	SessionID string
+	CreatedAt time.Time
+}
+
+// ArtifactSummary describes one artifact name across its versions.
+type ArtifactSummary struct {
+	Name          string
+	LatestVersion int
+	Versions      int
+	Size          int64
+	UpdatedAt     time.Time
+}
+
+// ArtifactStore persists artifacts in the central database.
+type ArtifactStore struct {
+	db *sql.DB
+}
+
+// NewArtifactStore creates an artifact store backed by the devclaw database.
+func NewArtifactStore(db *sql.DB) *ArtifactStore {
+	return &ArtifactStore{db: db}
+}
+
+// Save stores content under a name, creating version 1 for new names and
+// the next version for existing ones.
+func (s *ArtifactStore) Save(name, mimeType, content, sessionID string) (*Artifact, error) {
+	if mimeType == "" {
+		mimeType = "text/plain"
+	}
+
+	var version int
+	if err := s.db.QueryRow(
+		`SELECT COALESCE(MAX(version), 0) + 1 FROM artifacts WHERE name = ?`, name,
+	).Scan(&version); err != nil {
+		return nil, fmt.Errorf("determining artifact version: %w", err)
+	}
+
+	artifact := &Artifact{
+		ID:        generateID(),
+		Name:      name,
+		Version:   version,
+		MimeType:  mimeType,
+		Content:   content,
+		Size:      int64(len(content)),
+		SessionID: sessionID,
+		CreatedAt: time.Now(),
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO artifacts (id, name, version, mime_type, content, size, session_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		artifact.ID, artifact.Name, artifact.Version, artifact.MimeType,
+		artifact.Content, artifact.Size, artifact.SessionID,
+		artifact.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("saving artifact: %w", err)
+	}
+	return artifact, nil
+}
+
+// Get returns one version of an artifact; version 0 means latest.
+func (s *ArtifactStore) Get(name string, version int) (*Artifact, error) {
+	query := `SELECT id, name, version, mime_type, content, size, session_id, created_at
+		 FROM artifacts WHERE name = ?`
+	args := []any{name}
+	if version > 0 {
+		query += ` AND version = ?`
+		args = append(args, version)
+	}
+	query += ` ORDER BY version DESC LIMIT 1`
+
+	var a Artifact
+	var createdAt string
+	err := s.db.QueryRow(query, args...).Scan(
+		&a.ID, &a.Name, &a.Version, &a.MimeType, &a.Content, &a.Size, &a.SessionID, &createdAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("artifact %q not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading artifact: %w", err)
+	}
+	a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &a, nil
+}
+
+// List summarizes all artifact names, most recently updated first.
+func (s *ArtifactStore) List() ([]ArtifactSummary, error) {
+	rows, err := s.db.Query(
+		`SELECT name, MAX(version), COUNT(*), MAX(size), MAX(created_at)
+		 FROM artifacts GROUP BY name ORDER BY MAX(created_at) DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("listing artifacts: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []ArtifactSummary
+	for rows.Next() {
+		var s ArtifactSummary
+		var updatedAt string
+		if err := rows.Scan(&s.Name, &s.LatestVersion, &s.Versions, &s.Size, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scanning artifact summary: %w", err)
+		}
+		s.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+// Delete removes all versions of an artifact.
+func (s *ArtifactStore) Delete(name string) error {
+	res, err := s.db.Exec(`DELETE FROM artifacts WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("deleting artifact: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("artifact %q not found", name)
+	}
+	return nil
+}
+
+// artifactURL builds the gateway link for an artifact, or "" when the
+// gateway is disabled.
+func artifactURL(cfg GatewayConfig, name string) string {
+	if !cfg.Enabled {
+		return ""
+	}
+	addr := cfg.Address
+	if addr == "" {
+		addr = ":8085"
+	}
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	return fmt.Sprintf("http://%s/api/artifacts/%s", addr, url.PathEscape(name))
+}
+
+// RegisterArtifactTools registers artifact_write, artifact_read, and
+// artifact_list so the agent can store long outputs out-of-band and keep
+// chat messages short.
+func RegisterArtifactTools(executor *ToolExecutor, store *ArtifactStore, gatewayCfg GatewayConfig, logger *slog.Logger) {
+	executor.Register(
+		MakeToolDefinition("artifact_write", "Store a long generated output (code, document, report) as a named artifact instead of pasting it into chat. Writing to an existing name creates a new version. Reply in chat with a short reference to the artifact. Use artifact_read to retrieve content, or send_document with the returned link to deliver it as a file.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Artifact name, including extension (e.g. report.md, server.go)",
+				},
+				"content": map[string]any{
+					"type":        "string",
+					"description": "Full content of the artifact",
+				},
+				"mime_type": map[string]any{
+					"type":        "string",
+					"description": "Optional MIME type (default text/plain)",
+				},
+			},
+			"required": []string{"name", "content"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			name, _ := args["name"].(string)
+			content, _ := args["content"].(string)
+			mimeType, _ := args["mime_type"].(string)
+			if name == "" || content == "" {
+				return nil, fmt.Errorf("name and content are required")
+			}
+
+			artifact, err := store.Save(name, mimeType, content, "")
+			if err != nil {
+				return nil, err
+			}
+
+			msg := fmt.Sprintf("Artifact %q saved as version %d (%d KB).", artifact.Name, artifact.Version, artifact.Size/1024)
+			if link := artifactURL(gatewayCfg, artifact.Name); link != "" {
+				msg += " Link: " + link
+			}
+			return msg, nil
+		},
+	)
+
+	executor.Register(
+		MakeToolDefinition("artifact_read", "Read a stored artifact by name. Returns the latest version unless a specific version is requested.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Artifact name",
+				},
+				"version": map[string]any{
+					"type":        "number",
+					"description": "Optional version number (default: latest)",
+				},
+			},
+			"required": []string{"name"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			name, _ := args["name"].(string)
+			if name == "" {
+				return nil, fmt.Errorf("name is required")
+			}
+			version := 0
+			if v, ok := args["version"].(float64); ok {
+				version = int(v)
+			}
+
+			artifact, err := store.Get(name, version)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Artifact %q version %d (%s):\n\n%s",
+				artifact.Name, artifact.Version, artifact.MimeType, artifact.Content), nil
+		},
+	)
+
+	executor.Register(
+		MakeToolDefinition("artifact_list", "List stored artifacts with their latest version and size.", map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			summaries, err := store.List()
+			if err != nil {
+				return nil, err
+			}
+			if len(summaries) == 0 {
+				return "No artifacts stored.", nil
+			}
+			var b strings.Builder
+			for _, s := range summaries {
+				fmt.Fprintf(&b, "- %s (v%d, %d version(s), %d KB, updated %s)\n",
+					s.Name, s.LatestVersion, s.Versions, s.Size/1024, s.UpdatedAt.Format("2006-01-02 15:04"))
+			}
+			return b.String(), nil
+		},
+	)
+
+	logger.Debug("registered artifact tools")
+}
@@ -0,0 +1,88 @@
+package copilot
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testArtifactStore(t *testing.T) *ArtifactStore {
+	t.Helper()
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenDatabase: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewArtifactStore(db)
+}
+
+func TestArtifactStoreVersioning(t *testing.T) {
+	store := testArtifactStore(t)
+
+	v1, err := store.Save("report.md", "text/markdown", "# Draft", "sess-1")
+	if err != nil {
+		t.Fatalf("Save v1: %v", err)
+	}
+	if v1.Version != 1 {
+		t.Errorf("expected version 1, got %d", v1.Version)
+	}
+
+	v2, err := store.Save("report.md", "text/markdown", "# Final", "sess-1")
+	if err != nil {
+		t.Fatalf("Save v2: %v", err)
+	}
+	if v2.Version != 2 {
+		t.Errorf("expected version 2, got %d", v2.Version)
+	}
+
+	// Latest wins by default; old versions stay reachable.
+	latest, err := store.Get("report.md", 0)
+	if err != nil {
+		t.Fatalf("Get latest: %v", err)
+	}
+	if latest.Content != "# Final" || latest.Version != 2 {
+		t.Errorf("unexpected latest: %+v", latest)
+	}
+	old, err := store.Get("report.md", 1)
+	if err != nil {
+		t.Fatalf("Get v1: %v", err)
+	}
+	if old.Content != "# Draft" {
+		t.Errorf("unexpected v1 content: %q", old.Content)
+	}
+
+	summaries, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].LatestVersion != 2 || summaries[0].Versions != 2 {
+		t.Errorf("unexpected summaries: %+v", summaries)
+	}
+
+	if err := store.Delete("report.md"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get("report.md", 0); err == nil {
+		t.Error("expected error after delete")
+	}
+}
+
+func TestArtifactStoreNotFound(t *testing.T) {
+	store := testArtifactStore(t)
+	if _, err := store.Get("missing.txt", 0); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not-found error, got %v", err)
+	}
+	if err := store.Delete("missing.txt"); err == nil {
+		t.Error("expected error deleting missing artifact")
+	}
+}
+
+func TestArtifactURL(t *testing.T) {
+	cfg := GatewayConfig{Enabled: true, Address: ":8085"}
+	if got := artifactURL(cfg, "my report.md"); got != "http://localhost:8085/api/artifacts/my%20report.md" {
+		t.Errorf("artifactURL = %q", got)
+	}
+	if got := artifactURL(GatewayConfig{}, "x"); got != "" {
+		t.Errorf("expected empty URL for disabled gateway, got %q", got)
+	}
+}
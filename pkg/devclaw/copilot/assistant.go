@@ -12,6 +12,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +23,7 @@ import (
 	"github.com/jholhewres/devclaw/pkg/devclaw/copilot/security"
 	"github.com/jholhewres/devclaw/pkg/devclaw/database"
 	"github.com/jholhewres/devclaw/pkg/devclaw/media"
+	"github.com/jholhewres/devclaw/pkg/devclaw/notify"
 	"github.com/jholhewres/devclaw/pkg/devclaw/sandbox"
 	"github.com/jholhewres/devclaw/pkg/devclaw/scheduler"
 	"github.com/jholhewres/devclaw/pkg/devclaw/skills"
@@ -52,6 +54,15 @@ type Assistant struct {
 	// approvalMgr manages pending tool approvals for RequireConfirmation tools.
 	approvalMgr *ApprovalManager
 
+	// sudoMgr tracks time-boxed session elevation (/sudo).
+	sudoMgr *SudoManager
+
+	// guestLimiter enforces daily token budgets for guest-level contacts.
+	guestLimiter *GuestLimiter
+
+	// sandboxFS manages per-session scratch jails for file tools.
+	sandboxFS *SandboxFS
+
 	// skillRegistry manages available skills.
 	skillRegistry *skills.Registry
 
@@ -61,6 +72,10 @@ type Assistant struct {
 	// scheduler manages scheduled tasks.
 	scheduler *scheduler.Scheduler
 
+	// notifier routes proactive push alerts (ntfy, Pushover). Nil when
+	// push notifications are disabled or unconfigured.
+	notifier *notify.Router
+
 	// sessionStore manages sessions for the default workspace (backward compat).
 	sessionStore *SessionStore
 
@@ -73,6 +88,13 @@ type Assistant struct {
 	// outputGuard validates outputs before sending.
 	outputGuard *security.OutputGuardrail
 
+	// postProcessors are custom response formatting stages registered by
+	// plugins; they run after the built-in pipeline in sendReply.
+	postProcessors []PostProcessor
+
+	// citations tracks which web sources contributed to each turn.
+	citations *CitationTracker
+
 	// memoryStore provides persistent long-term memory (file-based, always available).
 	memoryStore *memory.FileStore
 
@@ -85,6 +107,9 @@ type Assistant struct {
 	// hookMgr manages lifecycle hooks (16+ events).
 	hookMgr *HookManager
 
+	// events is the typed event bus external subscribers consume via the gateway.
+	events *EventBus
+
 	// heartbeat runs periodic proactive checks (stored for config hot-reload).
 	heartbeat *Heartbeat
 
@@ -109,6 +134,11 @@ type Assistant struct {
 	followupQueues   map[string][]*channels.IncomingMessage
 	followupQueuesMu sync.Mutex
 
+	// queueModeOverrides maps sessionID (channel:chatID) → queue mode set at
+	// runtime via /queue, taking precedence over group policy and channel config.
+	queueModeOverrides   map[string]QueueMode
+	queueModeOverridesMu sync.RWMutex
+
 	// usageTracker records token usage and estimated costs per session.
 	usageTracker *UsageTracker
 
@@ -121,6 +151,16 @@ type Assistant struct {
 	// projectMgr manages registered development projects.
 	projectMgr *ProjectManager
 
+	// toolSelector narrows the tool definitions sent per turn when dynamic
+	// tool selection is enabled (nil = full catalog).
+	toolSelector *ToolSelector
+
+	// worktreeMgr tracks per-session task worktrees for isolated coding edits.
+	worktreeMgr *WorktreeManager
+
+	// codeIndex is the persistent per-project symbol index (nil without devclawDB).
+	codeIndex *CodeIndex
+
 	// devclawDB is the central SQLite database (devclaw.db) shared by the
 	// scheduler, session persistence, and audit logger.
 	devclawDB *sql.DB
@@ -139,9 +179,19 @@ type Assistant struct {
 	// daemonMgr manages background processes (dev servers, watchers, etc.).
 	daemonMgr *DaemonManager
 
+	// triggerMgr watches local events (file changes, port up/down, daemon
+	// exits) and enqueues agent prompts when they fire.
+	triggerMgr *TriggerManager
+
+	// dataAnalyzer holds CSV/Parquet datasets loaded for ad-hoc analysis.
+	dataAnalyzer *DataAnalyzer
+
 	// pluginMgr manages installed plugins (GitHub, Jira, Sentry, etc.).
 	pluginMgr *PluginManager
 
+	// haClient integrates Home Assistant (REST tools + MQTT events).
+	haClient *HAClient
+
 	// userMgr handles multi-user operations when team mode is enabled.
 	userMgr *UserManager
 
@@ -163,6 +213,10 @@ type Assistant struct {
 	// groupPolicyMgr manages group-specific policies and activation modes.
 	groupPolicyMgr *GroupPolicyManager
 
+	// groupArchive stores passively archived group messages (nil without
+	// devclawDB).
+	groupArchive *GroupArchive
+
 	// webhookMgr manages external webhook delivery.
 	webhookMgr *WebhookManager
 
@@ -172,6 +226,55 @@ type Assistant struct {
 	// memoryIndexer performs background memory indexing.
 	memoryIndexer *MemoryIndexer
 
+	// digestRoutine delivers per-workspace activity digests.
+	digestRoutine *DigestRoutine
+
+	// anomalyMonitor alerts the owner on error-rate/latency anomalies.
+	anomalyMonitor *AnomalyMonitor
+
+	// dbMaintenance runs scheduled SQLite maintenance (VACUUM, checkpoints).
+	dbMaintenance *DBMaintenance
+
+	// dndMgr holds proactive messages during do-not-disturb windows.
+	dndMgr *DNDManager
+
+	// presenceTracker records per-chat activity for when-active delivery.
+	presenceTracker *PresenceTracker
+
+	// incidentMgr tracks incidents and runs the paging escalation chain.
+	incidentMgr *IncidentManager
+
+	// runbookStore persists runbook progress (nil without devclawDB).
+	runbookStore *RunbookStore
+
+	// crashStore persists recovered panics (nil without devclawDB).
+	crashStore *CrashStore
+
+	// artifactStore persists long generated outputs (nil without devclawDB).
+	artifactStore *ArtifactStore
+
+	// runTraceStore persists per-run transcripts (nil without devclawDB).
+	runTraceStore *RunTraceStore
+
+	// boot records the startup sequence outcomes (see boot.go).
+	boot *BootSequencer
+
+	// featureFlags resolves experimental-behavior flags per workspace.
+	featureFlags *FeatureFlagManager
+
+	// identityMgr links channel identities to one person (see identity.go).
+	identityMgr *IdentityManager
+
+	// federationMgr delegates tasks to remote DevClaw peers (see federation.go).
+	federationMgr *FederationManager
+
+	// onboardingMgr runs the welcome flow for newly paired contacts (see onboarding.go).
+	onboardingMgr *OnboardingManager
+
+	// accessRequests queues unknown contacts for review under the "ask"
+	// policy (see access_requests.go).
+	accessRequests *AccessRequestQueue
+
 	// mediaSvc provides native media handling (upload, enrich, send).
 	mediaSvc *media.MediaService
 
@@ -206,6 +309,16 @@ func New(cfg *Config, logger *slog.Logger) *Assistant {
 	// Initialize approval manager for RequireConfirmation tools.
 	approvalMgr := NewApprovalManager(logger)
 
+	// Initialize sudo manager for time-boxed session elevation.
+	sudoMgr := NewSudoManager(logger)
+
+	// Initialize guest limiter for read-only demo access.
+	guestLimiter := NewGuestLimiter(cfg.Access.Guest, logger)
+
+	// Initialize per-session file jails (no-op unless enabled in config).
+	sandboxFS := NewSandboxFS(cfg.Security.SandboxFS, logger)
+	te.SetSandboxFS(sandboxFS)
+
 	// Initialize project manager for coding skills.
 	dataDir := filepath.Dir(cfg.Memory.Path)
 	if dataDir == "" || dataDir == "." {
@@ -222,21 +335,49 @@ func New(cfg *Config, logger *slog.Logger) *Assistant {
 		llmClient:      NewLLMClient(cfg, logger),
 		toolExecutor:   te,
 		approvalMgr:    approvalMgr,
+		sudoMgr:        sudoMgr,
+		guestLimiter:   guestLimiter,
+		sandboxFS:      sandboxFS,
 		skillRegistry:  skills.NewRegistry(logger.With("component", "skills")),
 		sessionStore:   NewSessionStore(logger.With("component", "sessions")),
 		promptComposer: NewPromptComposer(cfg),
 		inputGuard:     security.NewInputGuardrail(cfg.Security.MaxInputLength, cfg.Security.RateLimit),
 		outputGuard:    security.NewOutputGuardrail(),
+		citations:      NewCitationTracker(),
 		subagentMgr:    NewSubagentManager(cfg.Subagents, logger),
 		hookMgr:        NewHookManager(logger),
+		events:         NewEventBus(),
 		projectMgr:      projectMgr,
+		worktreeMgr:    NewWorktreeManager(logger),
 		activeRuns:       make(map[string]context.CancelFunc),
 		interruptInboxes: make(map[string]chan string),
 		followupQueues:   make(map[string][]*channels.IncomingMessage),
+		queueModeOverrides: make(map[string]QueueMode),
 		usageTracker:     NewUsageTracker(logger.With("component", "usage")),
+		notifier:         notify.NewRouter(cfg.Notify, logger),
 		logger:           logger,
 	}
 
+	// Re-publish hook events on the event bus so external subscribers see them.
+	a.initEventBridge()
+
+	// Surface workspace model-policy violations (data residency) to the admin.
+	a.llmClient.SetPolicyViolationHandler(func(workspaceID, provider, model string) {
+		a.notifier.Notify(context.Background(), &notify.Alert{
+			Event: "model_policy.violation",
+			Title: "Model policy violation",
+			Message: fmt.Sprintf("Workspace %q blocked model %q (provider %s) per its model policy.",
+				workspaceID, model, provider),
+			Priority: 2,
+		})
+	})
+
+	// Global LLM request scheduler: per-provider concurrency limits, token
+	// budgeting, and fair queuing across sessions.
+	if cfg.RequestQueue.Enabled {
+		a.llmClient.SetScheduler(NewRequestScheduler(cfg.RequestQueue, logger))
+	}
+
 	// Initialize tool loop detection config (detectors are created per-run to avoid races).
 	// Use defaults, then apply user overrides. NewToolLoopDetector normalizes zero-values.
 	a.loopDetectorConfig = cfg.Agent.ToolLoop
@@ -265,6 +406,41 @@ func New(cfg *Config, logger *slog.Logger) *Assistant {
 			}
 			_ = a.channelMgr.Send(a.ctx, channel, chatID, &channels.OutgoingMessage{Content: msg})
 		}
+
+		// Sudo mode: the session is temporarily elevated — skip the prompt
+		// but record the auto-approved execution in the audit log. The grant
+		// only covers the owner who issued /sudo (or another owner); in a
+		// shared group session everyone else still gets the confirmation
+		// prompt, and the attempted bypass is audited.
+		if sudoMgr.IsActive(sessionID) {
+			if sudoMgr.IsActiveFor(sessionID, callerJID) || (a.accessMgr != nil && a.accessMgr.IsOwner(callerJID)) {
+				if audit := toolGuard.SQLiteAudit(); audit != nil {
+					audit.Log(toolName, callerJID, "sudo", true,
+						formatApprovalDescription(toolName, args), "auto-approved (sudo mode)")
+				}
+				return true, nil
+			}
+			if audit := toolGuard.SQLiteAudit(); audit != nil {
+				audit.Log(toolName, callerJID, "sudo", false,
+					formatApprovalDescription(toolName, args), "sudo active for another user — confirmation still required")
+			}
+		}
+
+		// Workspace-level approvers: fan the request out to each approver's
+		// DM and wait for the configured quorum instead of self-approval.
+		if a.workspaceMgr != nil {
+			if _, ws := a.workspaceMgr.GetSessionByID(sessionID); ws != nil && ws.Approvers != nil && len(ws.Approvers.Users) > 0 {
+				sendDM := func(jid, msg string) {
+					channel := ws.Approvers.Channel
+					if channel == "" {
+						channel, _, _ = strings.Cut(sessionID, ":")
+					}
+					_ = a.channelMgr.Send(a.ctx, channel, jid, &channels.OutgoingMessage{Content: msg})
+				}
+				return approvalMgr.RequestQuorum(sessionID, callerJID, toolName, args, ws.Approvers, sendDM, sendMsg)
+			}
+		}
+
 		return approvalMgr.Request(sessionID, callerJID, toolName, args, sendMsg)
 	})
 
@@ -317,6 +493,7 @@ func New(cfg *Config, logger *slog.Logger) *Assistant {
 // Start initializes and starts all subsystems.
 func (a *Assistant) Start(ctx context.Context) error {
 	a.ctx, a.cancel = context.WithCancel(ctx)
+	a.boot = NewBootSequencer(a.logger)
 
 	a.logger.Info("starting DevClaw Copilot",
 		"name", a.config.Name,
@@ -357,12 +534,15 @@ func (a *Assistant) Start(ctx context.Context) error {
 
 	// 0. Initialize memory stores.
 	memDir := filepath.Join(filepath.Dir(a.config.Memory.Path), "memory")
-	memStore, err := memory.NewFileStore(memDir)
-	if err != nil {
-		a.logger.Warn("memory store not available", "error", err)
-	} else {
+	a.boot.Run("memory", nil, func() error {
+		memStore, err := memory.NewFileStore(memDir)
+		if err != nil {
+			a.logger.Warn("memory store not available", "error", err)
+			return err
+		}
 		a.memoryStore = memStore
-	}
+		return nil
+	})
 
 	// 0a. Initialize SQLite memory with FTS5 + vector search (if configured).
 	if a.config.Memory.Type == "sqlite" {
@@ -429,14 +609,29 @@ func (a *Assistant) Start(ctx context.Context) error {
 	// Wire tool executor to prompt composer for dynamic tool list generation.
 	a.promptComposer.SetToolExecutor(a.toolExecutor)
 
+	// Wire project resolver so per-project convention files (AGENT.md,
+	// CLAUDE.md) are discovered when a session is bound to a project.
+	a.promptComposer.SetProjectResolver(func(sessionID string) *Project {
+		return a.projectMgr.ActiveProject(sessionID)
+	})
+
+	// Wire workspace resolver so instruction templates can reference the
+	// session's workspace ({{.Workspace}}).
+	a.promptComposer.SetWorkspaceResolver(func(sessionID string) *Workspace {
+		_, ws := a.workspaceMgr.GetSessionByID(sessionID)
+		return ws
+	})
+
 	// 0c. Open the central devclaw.db and wire all SQLite-backed storage.
 	// Uses the Database Hub for unified access (supports SQLite, PostgreSQL, MySQL).
 	hubConfig := a.config.Database.Effective()
-	dbHub, hubErr := database.NewHub(hubConfig, a.logger.With("component", "database-hub"))
-	if hubErr != nil {
-		a.logger.Error("failed to initialize database hub, falling back to file-based storage",
-			"backend", hubConfig.Backend, "error", hubErr)
-	} else {
+	a.boot.Run("database", nil, func() error {
+		dbHub, hubErr := database.NewHub(hubConfig, a.logger.With("component", "database-hub"))
+		if hubErr != nil {
+			a.logger.Error("failed to initialize database hub, falling back to file-based storage",
+				"backend", hubConfig.Backend, "error", hubErr)
+			return hubErr
+		}
 		a.dbHub = dbHub
 
 		// Run database migrations (creates all tables if needed)
@@ -455,15 +650,26 @@ func (a *Assistant) Start(ctx context.Context) error {
 			dataDir := filepath.Dir(hubConfig.SQLite.Path)
 			MigrateToSQLite(a.devclawDB, dataDir, a.logger.With("component", "migrate"))
 		}
-	}
+		return nil
+	})
 
 	// 0c-1. Session persistence: prefer SQLite, fall back to JSONL.
 	var sessPersister SessionPersister
-	if a.devclawDB != nil {
-		sessPersister = NewSQLiteSessionPersistence(a.devclawDB, a.logger.With("component", "session-persist"))
-		a.sessionStore.SetPersistence(sessPersister)
-		a.logger.Info("session persistence enabled (SQLite)")
-	} else {
+	a.boot.Run("session-persistence", nil, func() error {
+		if a.devclawDB != nil {
+			sessPersister = NewSQLiteSessionPersistence(a.devclawDB, a.logger.With("component", "session-persist"))
+			a.sessionStore.SetPersistence(sessPersister)
+			a.logger.Info("session persistence enabled (SQLite)")
+			a.groupArchive = NewGroupArchive(a.devclawDB, a.logger.With("component", "group-archive"))
+			a.runbookStore = NewRunbookStore(a.devclawDB)
+			a.crashStore = NewCrashStore(a.devclawDB)
+			a.artifactStore = NewArtifactStore(a.devclawDB)
+			a.runTraceStore = NewRunTraceStore(a.devclawDB)
+			if n, err := a.runTraceStore.Purge(a.config.RunTrace.RetentionDays); err == nil && n > 0 {
+				a.logger.Info("purged expired run transcripts", "count", n)
+			}
+			return nil
+		}
 		sessDir := filepath.Join(filepath.Dir(a.config.Memory.Path), "sessions")
 		if sessDir == "" {
 			sessDir = "./data/sessions"
@@ -471,12 +677,13 @@ func (a *Assistant) Start(ctx context.Context) error {
 		sp, err := NewSessionPersistence(sessDir, a.logger.With("component", "session-persist"))
 		if err != nil {
 			a.logger.Warn("session persistence not available", "error", err)
-		} else {
-			sessPersister = sp
-			a.sessionStore.SetPersistence(sessPersister)
-			a.logger.Info("session persistence enabled (JSONL)", "dir", sessDir)
+			return err
 		}
-	}
+		sessPersister = sp
+		a.sessionStore.SetPersistence(sessPersister)
+		a.logger.Info("session persistence enabled (JSONL)", "dir", sessDir)
+		return nil
+	})
 
 	// Propagate persistence to workspace session stores so channel conversations
 	// (WhatsApp, Telegram, etc.) survive container restarts.
@@ -484,6 +691,30 @@ func (a *Assistant) Start(ctx context.Context) error {
 		a.workspaceMgr.SetPersistence(sessPersister)
 	}
 
+	// 0c-1b. Idle-session archival: pruned sessions are summarized into
+	// devclaw.db and memory documents instead of dropped, and restored
+	// transparently when the same chat becomes active again.
+	if a.devclawDB != nil {
+		archiver := NewSessionArchiver(NewSessionArchiveStore(a.devclawDB), a, a.logger)
+		a.sessionStore.SetArchival(archiver.Archive, archiver.Restore)
+		a.workspaceMgr.SetArchival(archiver.Archive, archiver.Restore)
+	}
+
+	// 0c-1a. LLM traffic logging (off by default): record request/response
+	// payloads per the configured privacy level, purging expired records.
+	if a.devclawDB != nil && a.config.TrafficLog.Level != "" && a.config.TrafficLog.Level != TrafficLevelOff {
+		trafficStore := NewTrafficLogStore(a.devclawDB)
+		a.llmClient.SetTrafficRecorder(a.config.TrafficLog.Level, func(rec TrafficRecord) {
+			if _, err := trafficStore.Add(&rec); err != nil {
+				a.logger.Warn("failed to record LLM traffic", "error", err)
+			}
+		})
+		if n, err := trafficStore.Purge(a.config.TrafficLog.RetentionDays); err == nil && n > 0 {
+			a.logger.Info("purged expired LLM traffic records", "count", n)
+		}
+		a.logger.Info("LLM traffic logging enabled", "level", a.config.TrafficLog.Level)
+	}
+
 	// 0c-2. Audit logger: prefer SQLite, fall back to file-based.
 	if a.devclawDB != nil {
 		if guard := a.toolExecutor.Guard(); guard != nil {
@@ -518,6 +749,30 @@ func (a *Assistant) Start(ctx context.Context) error {
 		a.logger.Warn("failed to load pairing tokens", "error", err)
 	}
 
+	// 0c-6a. Onboarding flow for newly paired contacts: welcome sequence,
+	// preference capture, default workspace and the rate-limited trial profile.
+	a.onboardingMgr = NewOnboardingManager(a.devclawDB, a.workspaceMgr, a.logger)
+	if err := a.onboardingMgr.Load(); err != nil {
+		a.logger.Warn("failed to load onboarding states", "error", err)
+	}
+	a.pairingMgr.SetApprovalCallback(a.onboardingMgr.Begin)
+
+	// 0c-6b. Identity linking: many channel JIDs → one person, so access
+	// level and workspace follow the user across channels.
+	a.identityMgr = NewIdentityManager(a.devclawDB, a.logger)
+	if err := a.identityMgr.Load(); err != nil {
+		a.logger.Warn("failed to load identity links", "error", err)
+	}
+	a.accessMgr.SetIdentityResolver(a.identityMgr.LinkedJIDs)
+	a.workspaceMgr.SetIdentityResolver(a.identityMgr.LinkedJIDs)
+
+	// 0c-6c. Review queue for unknown contacts under the "ask" policy.
+	a.accessRequests = NewAccessRequestQueue(a.devclawDB, a.accessMgr, a.logger)
+
+	// 0c-7. Feature flags: experimental behaviors per workspace or rollout
+	// percentage, with runtime overrides persisted in devclaw.db.
+	a.featureFlags = NewFeatureFlagManager(a.config.Features, a.devclawDB, a.logger)
+
 	// 0d. Agent router for specialized profiles.
 	if len(a.config.Agents.Profiles) > 0 {
 		a.agentRouter = NewAgentRouter(a.config.Agents, a.logger)
@@ -534,13 +789,27 @@ func (a *Assistant) Start(ctx context.Context) error {
 			Enabled:  a.config.Hooks.Enabled,
 			Webhooks: a.config.Hooks.Webhooks,
 		}, a.hookMgr, a.logger)
+		a.webhookMgr.SetNotifier(a.notifier)
+		if a.devclawDB != nil {
+			a.webhookMgr.SetDeadLetterStore(NewWebhookDLQStore(a.devclawDB))
+		}
+	}
+
+	// 0g. Federation manager for assistant-to-assistant delegation.
+	if a.config.Federation.Enabled {
+		a.federationMgr = NewFederationManager(a.config.Federation, a.config.Name, a.logger)
+		a.logger.Info("federation enabled", "peers", a.federationMgr.PeerNames())
 	}
 
 	// 1. Register skill loaders and load all skills.
 	a.registerSkillLoaders()
-	if err := a.skillRegistry.LoadAll(a.ctx); err != nil {
-		a.logger.Error("failed to load skills", "error", err)
-	}
+	a.boot.Run("skills", nil, func() error {
+		if err := a.skillRegistry.LoadAll(a.ctx); err != nil {
+			a.logger.Error("failed to load skills", "error", err)
+			return err
+		}
+		return nil
+	})
 
 	// 1b. Initialize skills with sandbox runner.
 	a.initializeSkills()
@@ -592,24 +861,84 @@ func (a *Assistant) Start(ctx context.Context) error {
 	a.registerSystemTools()
 
 	// 2. Start channel manager (non-fatal: webui/gateway can work without channels).
-	if err := a.channelMgr.Start(a.ctx); err != nil {
-		a.logger.Warn("channels not connected yet (will retry in background)", "error", err)
-	}
+	// Connects are retried with backoff — transient network failures at boot
+	// are the common case (container starts before DNS/network is ready).
+	a.boot.RunWithRetry("channels", nil, 3, 2*time.Second, func() error {
+		if err := a.channelMgr.Start(a.ctx); err != nil {
+			a.logger.Warn("channels not connected yet (will retry in background)", "error", err)
+			return err
+		}
+		return nil
+	})
 
 	// 3. Start session pruners for all workspaces.
 	a.workspaceMgr.StartPruners(a.ctx)
 
 	// 4. Start scheduler if created.
 	if a.scheduler != nil {
-		if err := a.scheduler.Start(a.ctx); err != nil {
-			a.logger.Error("failed to start scheduler", "error", err)
+		a.boot.Run("scheduler", nil, func() error {
+			if err := a.scheduler.Start(a.ctx); err != nil {
+				a.logger.Error("failed to start scheduler", "error", err)
+				return err
+			}
+			return nil
+		})
+	} else {
+		a.boot.Disabled("scheduler")
+	}
+
+	// 4d. Start do-not-disturb manager if enabled. Must come before the
+	// proactive routines below so their sends honor quiet windows.
+	if a.config.DND.Enabled {
+		a.dndMgr = NewDNDManager(a.config.DND, a.logger)
+		a.dndMgr.SetSendFunc(func(channel, chatID, content string) error {
+			return a.channelMgr.Send(a.ctx, channel, chatID, &channels.OutgoingMessage{Content: content})
+		})
+		a.dndMgr.Start(a.ctx)
+	}
+
+	// 4e. Start presence tracker (always on; powers when-active delivery).
+	a.presenceTracker = NewPresenceTracker(a.config.Presence, a.logger)
+	a.presenceTracker.SetSendFunc(func(channel, chatID, content string) error {
+		// Released notifications still honor do-not-disturb windows.
+		return a.sendProactive(a.ctx, channel, chatID, content, false)
+	})
+	a.presenceTracker.Start(a.ctx)
+
+	// 4f. Incident manager. Pages bypass do-not-disturb (urgent).
+	a.incidentMgr = NewIncidentManager(a.config.Incidents, a.logger)
+	a.incidentMgr.SetPageFunc(func(target EscalationTarget, inc *Incident) {
+		page := fmt.Sprintf(
+			"🚨 *Incident %s:* %s\nOpened by %s at %s.\nAcknowledge with /incident ack %s",
+			inc.ID, inc.Title, inc.OpenedBy,
+			inc.OpenedAt.Format("15:04"), inc.ID)
+		if err := a.sendProactive(a.ctx, target.Channel, target.ChatID, page, true); err != nil {
+			a.logger.Error("incident page failed",
+				"incident", inc.ID, "target", target.Name, "error", err)
 		}
+	})
+
+	// 4g. Sandbox FS garbage collection (if per-session jails are enabled).
+	if a.sandboxFS != nil && a.sandboxFS.Enabled() {
+		a.sandboxFS.Start(a.ctx, func(dirName string) bool {
+			for _, info := range a.workspaceMgr.ListAllSessions() {
+				if sessionDirName(info.ID) == dirName {
+					return true
+				}
+			}
+			return false
+		})
 	}
 
 	// 5. Start heartbeat if enabled.
 	if a.config.Heartbeat.Enabled {
-		a.heartbeat = NewHeartbeat(a.config.Heartbeat, a, a.logger)
-		a.heartbeat.Start(a.ctx)
+		a.boot.Run("heartbeat", nil, func() error {
+			a.heartbeat = NewHeartbeat(a.config.Heartbeat, a, a.logger)
+			a.heartbeat.Start(a.ctx)
+			return nil
+		})
+	} else {
+		a.boot.Disabled("heartbeat")
 	}
 
 	// 5b. Start metrics collector if enabled.
@@ -657,6 +986,24 @@ func (a *Assistant) Start(ctx context.Context) error {
 		go a.memoryIndexer.Start(a.ctx)
 	}
 
+	// 5c2. Start workspace digest routine if enabled.
+	if a.config.Routines.Digest.Enabled {
+		a.digestRoutine = NewDigestRoutine(a.config.Routines.Digest, a, a.logger)
+		a.digestRoutine.Start(a.ctx)
+	}
+
+	// 5c3. Start anomaly monitor if enabled (fed by the metrics collector).
+	if a.config.Routines.Anomaly.Enabled && a.metricsCollector != nil {
+		a.anomalyMonitor = NewAnomalyMonitor(a.config.Routines.Anomaly, a, a.logger)
+		a.anomalyMonitor.Start(a.ctx)
+	}
+
+	// 5c4. Start scheduled database maintenance if enabled.
+	if a.config.Routines.DBMaintenance.Enabled {
+		a.dbMaintenance = NewDBMaintenance(a.config.Routines.DBMaintenance, a, a.logger)
+		a.dbMaintenance.Start(a.ctx)
+	}
+
 	// 5d. Initialize native media service if enabled.
 	if a.config.NativeMedia.Enabled {
 		// Create media store
@@ -676,6 +1023,8 @@ func (a *Assistant) Start(ctx context.Context) error {
 			TempTTL:         a.config.NativeMedia.Service.TempTTL,
 			CleanupEnabled:  a.config.NativeMedia.Service.CleanupEnabled,
 			CleanupInterval: a.config.NativeMedia.Service.CleanupInterval,
+			Lifecycle:       a.config.NativeMedia.Service.Lifecycle,
+			Scan:            a.config.NativeMedia.Service.Scan,
 		}
 
 		// Get effective media config to check model capabilities
@@ -734,11 +1083,12 @@ func (a *Assistant) Start(ctx context.Context) error {
 		)
 	}
 
-	// 6. Start main message processing loop.
-	go a.messageLoop()
+	// 6. Start main message processing loop. Protected: a panic is captured
+	// as a crash report and the loop restarts instead of silently dying.
+	go a.runProtected("message-loop", a.messageLoop)
 
 	// 6b. Start session watchdog to recover stuck sessions.
-	go a.sessionWatchdog()
+	go a.runProtected("session-watchdog", a.sessionWatchdog)
 
 	// 7. Run BOOT.md if present (gateway startup).
 	// Executes after all channels are connected, with a short delay for stabilization.
@@ -757,7 +1107,18 @@ func (a *Assistant) Start(ctx context.Context) error {
 		}
 	}
 
-	a.logger.Info("DevClaw Copilot started successfully")
+	// 9. Push a startup report to the owner when anything came up degraded,
+	// so a silently broken subsystem (DB, channels) is noticed at boot instead
+	// of when a feature mysteriously misbehaves days later.
+	if a.boot.Degraded() {
+		go a.notifier.Notify(a.ctx, &notify.Alert{
+			Event:   "boot.degraded",
+			Title:   "DevClaw started in degraded mode",
+			Message: a.boot.Report(),
+		})
+	}
+
+	a.logger.Info("DevClaw Copilot started successfully", "degraded", a.boot.Degraded())
 	return nil
 }
 
@@ -823,12 +1184,23 @@ func (a *Assistant) Stop() {
 	}
 
 	// Shut down in reverse initialization order.
+	if a.haClient != nil {
+		a.haClient.Stop()
+	}
+	if a.triggerMgr != nil {
+		a.triggerMgr.Stop()
+	}
 	if a.scheduler != nil {
 		a.scheduler.Stop()
 	}
 	a.channelMgr.Stop()
 	a.skillRegistry.ShutdownAll()
 
+	// Stop the embedded inference backend, if one is running.
+	if a.llmClient != nil {
+		a.llmClient.Close()
+	}
+
 	// Close SQLite memory store.
 	if a.sqliteMemory != nil {
 		if err := a.sqliteMemory.Close(); err != nil {
@@ -970,6 +1342,11 @@ func (a *Assistant) ProjectManager() *ProjectManager {
 	return a.projectMgr
 }
 
+// WorktreeManager returns the task worktree manager.
+func (a *Assistant) WorktreeManager() *WorktreeManager {
+	return a.worktreeMgr
+}
+
 // SetScheduler configures the assistant's scheduler.
 func (a *Assistant) SetScheduler(s *scheduler.Scheduler) {
 	a.scheduler = s
@@ -990,11 +1367,10 @@ func (a *Assistant) handleDrainedMessages(sessionID string, msgs []*channels.Inc
 }
 
 // handleBusySession processes a new message when the session is already running
-// an agent. Behavior depends on the configured queue mode for the channel.
+// an agent. Behavior depends on the effective queue mode for the chat
+// (in-chat override, group policy, or channel config).
 func (a *Assistant) handleBusySession(msg *channels.IncomingMessage, sessionID string, logger *slog.Logger) {
-	a.configMu.RLock()
-	mode := EffectiveQueueMode(a.config.Queue, msg.Channel)
-	a.configMu.RUnlock()
+	mode := a.effectiveQueueModeFor(msg, sessionID)
 
 	logger.Info("session busy, applying queue mode",
 		"session", sessionID,
@@ -1044,7 +1420,19 @@ func (a *Assistant) handleBusySession(msg *channels.IncomingMessage, sessionID s
 				a.channelMgr.SendReaction(a.ctx, msg.Channel, msg.ChatID, msg.ID, "👀")
 				return
 			default:
-				logger.Warn("interrupt inbox full, falling back to followup", "session", sessionID)
+				// Inbox full: collapse the pending steer messages and the new
+				// one into a single summarized instruction so steering context
+				// isn't lost to the followup queue.
+				combined, collapsed := collapseSteerMessages(inbox, enriched)
+				select {
+				case inbox <- combined:
+					logger.Info("interrupt inbox full, collapsed pending steer messages",
+						"session", sessionID, "collapsed", collapsed)
+					a.channelMgr.SendReaction(a.ctx, msg.Channel, msg.ChatID, msg.ID, "👀")
+					return
+				default:
+					logger.Warn("interrupt inbox full, falling back to followup", "session", sessionID)
+				}
 			}
 		}
 
@@ -1070,6 +1458,94 @@ func (a *Assistant) handleBusySession(msg *channels.IncomingMessage, sessionID s
 	}
 }
 
+// hasActiveRuns reports whether any agent run is currently executing.
+func (a *Assistant) hasActiveRuns() bool {
+	a.activeRunsMu.Lock()
+	defer a.activeRunsMu.Unlock()
+	return len(a.activeRuns) > 0
+}
+
+// hasActivitySince reports whether any session other than the heartbeat's own
+// saw activity after t. Used to gate heartbeat ticks on new input.
+func (a *Assistant) hasActivitySince(t time.Time) bool {
+	for _, meta := range a.sessionStore.ListSessions() {
+		if meta.Channel == "heartbeat" {
+			continue
+		}
+		if meta.LastActiveAt.After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagEnabled reports whether a feature flag is on for a workspace, treating
+// an unwired flag manager as "use the built-in default".
+func (a *Assistant) flagEnabled(flag FeatureFlag, workspaceID string) bool {
+	if a.featureFlags == nil {
+		return knownFlags[flag]
+	}
+	return a.featureFlags.Enabled(flag, workspaceID)
+}
+
+// effectiveQueueModeFor resolves the queue mode for a message: in-chat
+// /queue override → group policy queue_mode → channel config → default.
+func (a *Assistant) effectiveQueueModeFor(msg *channels.IncomingMessage, sessionID string) QueueMode {
+	a.queueModeOverridesMu.RLock()
+	override, hasOverride := a.queueModeOverrides[sessionID]
+	a.queueModeOverridesMu.RUnlock()
+	if hasOverride {
+		return override
+	}
+
+	if msg.IsGroup && a.groupPolicyMgr != nil {
+		if mode := a.groupPolicyMgr.GetQueueMode(msg.ChatID); mode != "" {
+			return mode
+		}
+	}
+
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+	return EffectiveQueueMode(a.config.Queue, msg.Channel)
+}
+
+// steerInboxSize returns the interrupt inbox capacity for a channel:
+// per-channel override → global steer_inbox_size → 10.
+func (a *Assistant) steerInboxSize(channel string) int {
+	q := a.config.Queue
+	if n, ok := q.SteerInboxByChannel[channel]; ok && n > 0 {
+		return n
+	}
+	if q.SteerInboxSize > 0 {
+		return q.SteerInboxSize
+	}
+	return 10
+}
+
+// collapseSteerMessages drains the pending steer messages from a full inbox
+// and combines them with the latest one into a single numbered instruction.
+// Returns the combined message and how many were collapsed into it.
+func collapseSteerMessages(inbox chan string, latest string) (string, int) {
+	var pending []string
+	for {
+		select {
+		case m := <-inbox:
+			pending = append(pending, m)
+		default:
+			pending = append(pending, latest)
+			if len(pending) == 1 {
+				return latest, 1
+			}
+			var b strings.Builder
+			fmt.Fprintf(&b, "Multiple follow-up messages arrived while you were working (%d, oldest first). Address them together:\n", len(pending))
+			for i, m := range pending {
+				fmt.Fprintf(&b, "%d. %s\n", i+1, truncateStr(m, 500))
+			}
+			return strings.TrimRight(b.String(), "\n"), len(pending)
+		}
+	}
+}
+
 // enqueueFollowup adds a message to the followup queue with bounds checking.
 func (a *Assistant) enqueueFollowup(msg *channels.IncomingMessage, sessionID string, logger *slog.Logger) {
 	const maxFollowupQueue = 20
@@ -1174,6 +1650,10 @@ func (a *Assistant) messageLoop() {
 // handleMessage processes an individual message following the full flow:
 // access check → command → trigger → workspace → validate → build → execute → validate → send.
 func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
+	// Each message runs in its own goroutine — capture panics so one bad
+	// message can't kill the handler without a trace.
+	defer a.recoverPanic("handle-message")
+
 	start := time.Now()
 	logger := a.logger.With(
 		"channel", msg.Channel,
@@ -1209,15 +1689,26 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 					accessResult = a.accessMgr.Check(msg)
 					logger.Info("access granted via pairing token",
 						"from", msg.From)
+					// Deliver the onboarding welcome right away instead of
+					// waiting for the user's next message.
+					if reply, handled := a.handleOnboarding(msg); handled {
+						a.sendReply(msg, reply)
+					}
 				}
 				return
 			}
 		}
 
-		// If policy is "ask", send a one-time message.
+		// If policy is "ask", send a one-time message and queue the contact
+		// for admin review (/requests).
 		if accessResult.ShouldAsk {
 			a.sendReply(msg, a.accessMgr.PendingMessage())
 			a.accessMgr.MarkAsked(msg.From)
+			if a.accessRequests != nil {
+				if _, err := a.accessRequests.Add(msg.From, msg.FromName, msg.Channel, msg.Content); err != nil {
+					logger.Warn("failed to queue access request", "error", err)
+				}
+			}
 			logger.Info("access pending, sent request message",
 				"from", msg.From)
 		} else {
@@ -1230,6 +1721,39 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 
 	logger.Info("access granted", "level", accessResult.Level)
 
+	a.events.EmitActivity(EventMessageReceived, MakeSessionID(msg.Channel, msg.ChatID), map[string]any{
+		"channel":  msg.Channel,
+		"from":     msg.From,
+		"is_group": msg.IsGroup,
+	})
+
+	// Newly paired contacts go through the onboarding flow before anything else.
+	if reply, handled := a.handleOnboarding(msg); handled {
+		a.sendReply(msg, reply)
+		logger.Info("onboarding step delivered", "from", msg.From)
+		return
+	}
+
+	// Guests have a daily token budget — refuse early when it is exhausted.
+	if accessResult.Level == AccessGuest && !a.guestLimiter.Allow(msg.From) {
+		a.sendReply(msg, "Daily guest usage limit reached. Try again tomorrow.")
+		logger.Info("guest budget exhausted", "from", msg.From)
+		return
+	}
+
+	// Trial contacts (onboarded, not yet upgraded) have their own daily budget.
+	inTrial := accessResult.Level == AccessUser && a.onboardingMgr != nil && a.onboardingMgr.InTrial(msg.From)
+	if inTrial && !a.onboardingMgr.AllowTrial(msg.From) {
+		a.sendReply(msg, "Daily trial usage limit reached. Try again tomorrow, or ask the owner to upgrade your access.")
+		logger.Info("trial budget exhausted", "from", msg.From)
+		return
+	}
+
+	// Record chat activity for presence-aware (when-active) delivery.
+	if a.presenceTracker != nil {
+		a.presenceTracker.Touch(msg.Channel, msg.ChatID)
+	}
+
 	// ── Step 0b: Maintenance mode check ──
 	// Allow commands through, block regular messages.
 	if a.maintenanceMgr != nil && a.maintenanceMgr.IsEnabled() {
@@ -1245,6 +1769,19 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 		}
 	}
 
+	// ── Step 0c: Passive group archiving (opt-in) ──
+	// Groups with archiving enabled record every non-command message into
+	// the searchable per-group archive, including ones the bot will never
+	// answer. This runs before the trigger and processing-lock checks so
+	// silently observed messages are not lost.
+	if msg.IsGroup && a.groupArchive != nil && a.groupPolicyMgr != nil && !IsCommand(msg.Content) {
+		if ac := a.groupPolicyMgr.GetArchive(msg.ChatID); ac != nil {
+			if err := a.groupArchive.Save(msg.ChatID, msg.From, msg.FromName, msg.Content, ac.RetentionDays); err != nil {
+				logger.Warn("group archive save failed", "error", err)
+			}
+		}
+	}
+
 	// ── Step 1: Admin commands ──
 	// Check for /commands BEFORE trigger check (commands always work).
 	if IsCommand(msg.Content) {
@@ -1308,6 +1845,9 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 
 	logger = logger.With("workspace", workspace.ID)
 
+	// Record the sender name for instruction templates ({{.UserName}}).
+	a.promptComposer.SetSessionUser(session.ID, msg.FromName)
+
 	// ── Step 3: Check trigger ──
 	// Use workspace trigger if set, otherwise global.
 	trigger := a.config.Trigger
@@ -1316,6 +1856,19 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 	}
 	triggered := a.matchesTrigger(msg.Content, trigger, msg.IsGroup)
 
+	// Voice notes only carry placeholder text ("[audio]") at this point, so
+	// the check above can't see what was said. Transcribe now and re-evaluate
+	// against the trigger and the spoken wake word.
+	if !triggered && msg.IsGroup && msg.Media != nil && msg.Media.Type == channels.MessageAudio {
+		if enriched, ok := a.evaluateSpokenTrigger(msg, trigger, logger); ok {
+			triggered = true
+			// Reuse the transcript: swap it in as the content and drop the
+			// media reference so Step 4 doesn't transcribe a second time.
+			msg.Content = enriched
+			msg.Media = nil
+		}
+	}
+
 	// ── Step 3a: Group policy check ──
 	// For group messages, check if we should respond based on group policy.
 	if msg.IsGroup && a.groupPolicyMgr != nil {
@@ -1354,6 +1907,13 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 	// Phase 2 (async): media results are injected via interruptCh when ready.
 	userContent, hasMediaPending := a.enrichMessageContentFast(msg, logger)
 
+	// ── Step 4b: Auto-translate for cross-language groups ──
+	// When the group has a language pair configured, the message is translated
+	// into the agent's language; the reply is translated back in Step 10d.
+	if msg.IsGroup {
+		userContent = a.applyInboundTranslation(msg.ChatID, userContent, logger)
+	}
+
 	// ── Step 5: Validate input ──
 	if err := a.inputGuard.Validate(msg.From, userContent); err != nil {
 		logger.Warn("input rejected", "error", err)
@@ -1393,6 +1953,14 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 				modelOverride = agentProfile.Model
 			}
 
+			// Apply the profile's persona as the session default voice.
+			// An explicit /persona choice on the session wins.
+			if agentProfile.Persona != "" && session.GetConfig().Persona == "" {
+				cfg := session.GetConfig()
+				cfg.Persona = agentProfile.Persona
+				session.SetConfig(cfg)
+			}
+
 			// Override prompt if profile has custom instructions.
 			if agentProfile.Instructions != "" {
 				// Replace the base instructions with profile instructions.
@@ -1423,7 +1991,7 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 	// Propagate caller, session, and delivery target through context so
 	// tools get per-request security context without shared mutable state.
 	agentCtx := ContextWithSession(a.ctx, sessionID)
-	agentCtx = ContextWithDelivery(agentCtx, msg.Channel, msg.ChatID)
+	agentCtx = ContextWithDeliveryMessage(agentCtx, msg.Channel, msg.ChatID, msg.ID)
 	agentCtx = ContextWithCaller(agentCtx, accessResult.Level, msg.From)
 
 	// Resolve tool profile for this workspace (workspace can override global).
@@ -1431,6 +1999,30 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 		agentCtx = ContextWithToolProfile(agentCtx, profile)
 	}
 
+	// Agent profile tool allowlist takes precedence over workspace profiles.
+	if agentProfile != nil && len(agentProfile.Tools) > 0 {
+		agentCtx = ContextWithToolProfile(agentCtx, &ToolProfile{
+			Name:        "agent:" + agentProfile.ID,
+			Description: "Tool allowlist from agent profile " + agentProfile.ID,
+			Allow:       agentProfile.Tools,
+		})
+	}
+
+	// Guests always run with the locked-down guest profile, regardless of
+	// workspace or agent profile overrides.
+	if accessResult.Level == AccessGuest {
+		if guestProfile := GetProfile("guest", nil); guestProfile != nil {
+			agentCtx = ContextWithToolProfile(agentCtx, guestProfile)
+		}
+	}
+
+	// Trial contacts run with the trial profile until the owner upgrades them.
+	if inTrial {
+		if trialProfile := GetProfile("trial", nil); trialProfile != nil {
+			agentCtx = ContextWithToolProfile(agentCtx, trialProfile)
+		}
+	}
+
 	// Inject ProgressSender with per-channel cooldown.
 	// WhatsApp doesn't support editing messages, so we rate-limit progress
 	// to avoid flooding the chat with dozens of "still working..." messages.
@@ -1462,6 +2054,14 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 		blockStreamer = NewBlockStreamer(bsCfg, a.channelMgr, msg.Channel, msg.ChatID, msg.ID)
 	}
 
+	// Streaming TTS: synthesize flushed blocks as they are sent so voice
+	// replies start playing before the full response is done.
+	var audioStreamer *ttsStreamer
+	if blockStreamer != nil && a.wantsStreamingTTS(msg) {
+		audioStreamer = a.newTTSStreamerFor(msg)
+		blockStreamer.SetOnFlush(audioStreamer.Enqueue)
+	}
+
 	// Start a typing heartbeat goroutine that re-sends typing indicators
 	// every 8 seconds while the agent is processing. WhatsApp's typing
 	// indicator expires after ~10s, so refreshing keeps it alive.
@@ -1487,8 +2087,23 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 		go a.enrichMediaAsync(a.ctx, msg, sessionID, logger)
 	}
 
+	// In groups, attribute the live message to its sender so the agent knows
+	// who is speaking (history entries carry the same attribution).
+	agentInput := userContent
+	senderName := ""
+	if msg.IsGroup && msg.FromName != "" {
+		senderName = msg.FromName
+		agentInput = senderName + ": " + userContent
+	}
+
+	// Snapshot guest/trial token usage so the run's consumption can be recorded.
+	guestPromptBefore, guestCompletionBefore := 0, 0
+	if accessResult.Level == AccessGuest || inTrial {
+		guestPromptBefore, guestCompletionBefore, _ = session.GetTokenUsage()
+	}
+
 	agentStart := time.Now()
-	response := a.executeAgentWithStream(agentCtx, workspace.ID, session, sessionID, prompt, userContent, blockStreamer, modelOverride)
+	response := a.executeAgentWithStream(agentCtx, workspace.ID, session, sessionID, prompt, agentInput, blockStreamer, modelOverride)
 	logger.Info("agent execution complete",
 		"agent_duration_ms", time.Since(agentStart).Milliseconds(),
 		"response_len", len(response),
@@ -1501,6 +2116,9 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 	if blockStreamer != nil {
 		blockStreamer.Finish()
 	}
+	if audioStreamer != nil {
+		audioStreamer.Finish()
+	}
 
 	// ── Step 9: Validate output ──
 	if err := a.outputGuard.Validate(response); err != nil {
@@ -1509,7 +2127,7 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 	}
 
 	// ── Step 10: Update session ──
-	session.AddMessage(userContent, response)
+	session.AddMessageFrom(senderName, userContent, response)
 
 	// ── Step 10b: Auto-capture memories from this conversation turn ──
 	// Asynchronously extract important facts, preferences, and decisions from
@@ -1523,13 +2141,37 @@ func (a *Assistant) handleMessage(msg *channels.IncomingMessage) {
 	// the background to avoid blocking the user's response delivery.
 	go a.maybeCompactSession(session)
 
+	// ── Step 10d: Auto-translate reply for cross-language groups ──
+	// The session keeps the agent-language reply (stored above); the group
+	// receives the translation — or both versions when dual_post is enabled.
+	if msg.IsGroup {
+		response = a.applyOutboundTranslation(msg.ChatID, response, logger)
+	}
+
+	// ── Step 10e: Guest accounting and watermark ──
+	// Charge the run's tokens against the guest's daily budget and watermark
+	// the reply so demo responses are clearly marked.
+	if accessResult.Level == AccessGuest {
+		promptAfter, completionAfter, _ := session.GetTokenUsage()
+		a.guestLimiter.Record(msg.From, (promptAfter-guestPromptBefore)+(completionAfter-guestCompletionBefore))
+		if wm := a.guestLimiter.Watermark(); wm != "" {
+			response += "\n\n" + wm
+		}
+	} else if inTrial {
+		promptAfter, completionAfter, _ := session.GetTokenUsage()
+		a.onboardingMgr.RecordTrial(msg.From, (promptAfter-guestPromptBefore)+(completionAfter-guestCompletionBefore))
+	}
+
 	// ── Step 11: Send reply (skip if block streamer already sent everything) ──
 	if blockStreamer == nil || !blockStreamer.HasSentBlocks() {
 		a.sendReply(msg, response)
 	}
 
 	// ── Step 11b: TTS — synthesize and send audio if enabled ──
-	a.maybeSendTTS(msg, response)
+	// Skip when the streaming path already delivered audio segments.
+	if audioStreamer == nil || !audioStreamer.SentAudio() {
+		a.maybeSendTTS(msg, response)
+	}
 
 	// React with ✅ to signal processing is complete.
 	a.channelMgr.SendReaction(a.ctx, msg.Channel, msg.ChatID, msg.ID, "✅")
@@ -1665,17 +2307,40 @@ func (a *Assistant) composePromptWithAgent(profile *AgentProfileConfig, ws *Work
 func (a *Assistant) executeAgentWithStream(ctx context.Context, workspaceID string, session *Session, sessionID string, systemPrompt string, userMessage string, streamer *BlockStreamer, modelOverride string) string {
 	runKey := workspaceID + ":" + session.ID
 
+	// If the session has an active task worktree, steer edits into it.
+	if a.worktreeMgr != nil {
+		systemPrompt += a.worktreeMgr.PromptNote(session.ID)
+	}
+
+	// Inject recent self-critique lessons for this workspace (if enabled).
+	systemPrompt += a.lessonsPromptNote(workspaceID)
+
 	// Create interrupt inbox so follow-up messages can be injected mid-run.
-	interruptInbox := make(chan string, 10)
+	// Capacity is configurable globally and per channel (steer_inbox_size).
+	steerChannel, _, _ := strings.Cut(sessionID, ":")
+	interruptInbox := make(chan string, a.steerInboxSize(steerChannel))
 	a.interruptInboxesMu.Lock()
 	a.interruptInboxes[sessionID] = interruptInbox
 	a.interruptInboxesMu.Unlock()
 
 	runCtx, cancel := context.WithCancel(ctx)
 
+	// Attach the workspace model policy so the LLM client enforces it on
+	// every completion in this run, including fallbacks.
+	if ws, ok := a.workspaceMgr.Get(workspaceID); ok && ws.ModelPolicy != nil {
+		runCtx = ContextWithModelPolicy(runCtx, workspaceID, ws.ModelPolicy)
+	}
+
 	// ── Persist active run for restart recovery ──
 	channel, chatID, _ := strings.Cut(sessionID, ":")
 	a.markRunActive(sessionID, channel, chatID, userMessage)
+	runID := generateID()
+	tracer := NewRunTracer(runID, sessionID)
+	a.events.EmitActivity(EventRunStarted, sessionID, map[string]any{
+		"run_id":    runID,
+		"workspace": workspaceID,
+		"channel":   channel,
+	})
 
 	defer func() {
 		// Remove interrupt inbox before releasing the processing lock.
@@ -1701,8 +2366,18 @@ func (a *Assistant) executeAgentWithStream(ctx context.Context, workspaceID stri
 	// prompt. Older history is summarized by session memory if enabled.
 	history := session.RecentHistory(10)
 
-	agent := NewAgentRunWithConfig(a.llmClient, a.toolExecutor, a.config.Agent, a.logger)
+	// Guests run with a capped turn count and no continuation/reflection passes.
+	agentCfg := a.config.Agent
+	if CallerLevelFromContext(ctx) == AccessGuest {
+		agentCfg = a.guestLimiter.CapAgentConfig(agentCfg)
+	}
+
+	agent := NewAgentRunWithConfig(a.llmClient, a.toolExecutor, agentCfg, a.logger)
 	agent.SetModelOverride(modelOverride)
+	agent.SetTracer(tracer)
+	if a.toolSelector != nil && a.flagEnabled(FlagDynamicTools, workspaceID) {
+		agent.SetToolSelector(a.toolSelector)
+	}
 
 	// Wire interrupt channel for live message injection.
 	agent.SetInterruptChannel(interruptInbox)
@@ -1715,12 +2390,67 @@ func (a *Assistant) executeAgentWithStream(ctx context.Context, workspaceID stri
 		agent.SetOnBeforeToolExec(streamer.FlushNow)
 	}
 
+	// Collect tool failures for the post-run self-critique (if enabled).
+	var critic *runCritic
+	if a.config.Agent.ReflectionEnabled && a.config.Agent.SelfCritiqueEnabled && a.devclawDB != nil {
+		critic = newRunCritic()
+	}
+
 	// Wire auto-send media hook for tools that produce files (e.g. generate_image).
 	dt := DeliveryTargetFromContext(ctx)
+	var onToolResult func(name string, result ToolResult)
 	if dt.Channel != "" {
-		agent.SetOnToolResult(a.makeToolResultHook(dt.Channel, dt.ChatID))
+		onToolResult = a.makeToolResultHook(dt.Channel, dt.ChatID)
+	}
+	if critic != nil {
+		next := onToolResult
+		onToolResult = func(name string, result ToolResult) {
+			critic.Record(name, result)
+			if next != nil {
+				next(name, result)
+			}
+		}
+	}
+	// Track web sources so the reply can cite them (if enabled).
+	if a.config.Citations.Enabled {
+		a.citations.BeginTurn(sessionID)
+		next := onToolResult
+		onToolResult = func(name string, result ToolResult) {
+			a.citations.Record(sessionID, name, result.Content)
+			if next != nil {
+				next(name, result)
+			}
+		}
 	}
 
+	// Collect retrieved sources for the fact-check pass (workspace config
+	// or per-session /verify).
+	var verifier *verifySources
+	if a.config.Verify.enabledFor(workspaceID) || session.GetConfig().Verify {
+		verifier = newVerifySources()
+		next := onToolResult
+		onToolResult = func(name string, result ToolResult) {
+			verifier.Record(name, result)
+			if next != nil {
+				next(name, result)
+			}
+		}
+	}
+	// Publish every tool result on the event bus for external subscribers.
+	{
+		next := onToolResult
+		onToolResult = func(name string, result ToolResult) {
+			a.events.EmitActivity(EventToolExecuted, sessionID, map[string]any{
+				"tool":     name,
+				"is_error": result.Error != nil,
+			})
+			if next != nil {
+				next(name, result)
+			}
+		}
+	}
+	agent.SetOnToolResult(onToolResult)
+
 	// Wire tool loop detector (new instance per-run to avoid cross-session races).
 	if a.loopDetectorConfig.Enabled {
 		detector := NewToolLoopDetector(a.loopDetectorConfig, a.logger.With("component", "loop-detect"))
@@ -1734,6 +2464,7 @@ func (a *Assistant) executeAgentWithStream(ctx context.Context, workspaceID stri
 	}
 
 	response, usage, err := agent.RunWithUsage(runCtx, systemPrompt, history, userMessage)
+	a.saveRunTrace(tracer, err)
 	if err != nil {
 		if runCtx.Err() != nil {
 			return "Agent stopped."
@@ -1746,6 +2477,21 @@ func (a *Assistant) executeAgentWithStream(ctx context.Context, workspaceID stri
 		session.AddTokenUsage(usage.PromptTokens, usage.CompletionTokens)
 	}
 
+	// Fact-check the draft against retrieved sources before it goes out.
+	// Synchronous by design: flagging unsupported claims is the point.
+	if verifier != nil {
+		response = a.verifyDraft(ctx, response, verifier.Sources())
+	}
+
+	if a.config.Citations.Enabled {
+		response = a.appendCitations(sessionID, response)
+	}
+
+	// Post-run self-critique runs in background so it never delays the reply.
+	if critic != nil {
+		go a.reflectOnRun(workspaceID, sessionID, userMessage, response, critic.Failures())
+	}
+
 	return response
 }
 
@@ -1762,6 +2508,11 @@ func (a *Assistant) executeAgent(ctx context.Context, workspaceID string, sessio
 		cancel()
 	}()
 
+	// Attach the workspace model policy so the LLM client enforces it.
+	if ws, ok := a.workspaceMgr.Get(workspaceID); ok && ws.ModelPolicy != nil {
+		runCtx = ContextWithModelPolicy(runCtx, workspaceID, ws.ModelPolicy)
+	}
+
 	a.activeRunsMu.Lock()
 	a.activeRuns[runKey] = cancel
 	a.activeRunsMu.Unlock()
@@ -1771,6 +2522,12 @@ func (a *Assistant) executeAgent(ctx context.Context, workspaceID string, sessio
 	modelOverride := session.GetConfig().Model
 	agent := NewAgentRunWithConfig(a.llmClient, a.toolExecutor, a.config.Agent, a.logger)
 	agent.SetModelOverride(modelOverride)
+	runID := generateID()
+	tracer := NewRunTracer(runID, session.ID)
+	agent.SetTracer(tracer)
+	if a.toolSelector != nil && a.flagEnabled(FlagDynamicTools, workspaceID) {
+		agent.SetToolSelector(a.toolSelector)
+	}
 
 	// Wire tool loop detector (new instance per-run to avoid cross-session races).
 	if a.loopDetectorConfig.Enabled {
@@ -1778,6 +2535,26 @@ func (a *Assistant) executeAgent(ctx context.Context, workspaceID string, sessio
 		agent.SetLoopDetector(detector)
 	}
 
+	a.events.EmitActivity(EventRunStarted, session.ID, map[string]any{
+		"run_id":    runID,
+		"workspace": workspaceID,
+	})
+
+	// Track web sources so the reply can cite them (if enabled), and publish
+	// every tool result on the event bus for external subscribers.
+	agent.SetOnToolResult(func(name string, result ToolResult) {
+		if a.config.Citations.Enabled {
+			a.citations.Record(session.ID, name, result.Content)
+		}
+		a.events.EmitActivity(EventToolExecuted, session.ID, map[string]any{
+			"tool":     name,
+			"is_error": result.Error != nil,
+		})
+	})
+	if a.config.Citations.Enabled {
+		a.citations.BeginTurn(session.ID)
+	}
+
 	if a.usageTracker != nil {
 		agent.SetUsageRecorder(func(model string, usage LLMUsage) {
 			a.usageTracker.Record(session.ID, model, usage)
@@ -1785,6 +2562,7 @@ func (a *Assistant) executeAgent(ctx context.Context, workspaceID string, sessio
 	}
 
 	response, usage, err := agent.RunWithUsage(runCtx, systemPrompt, history, userMessage)
+	a.saveRunTrace(tracer, err)
 	if err != nil {
 		if runCtx.Err() != nil {
 			return "Agent stopped."
@@ -1797,9 +2575,28 @@ func (a *Assistant) executeAgent(ctx context.Context, workspaceID string, sessio
 		session.AddTokenUsage(usage.PromptTokens, usage.CompletionTokens)
 	}
 
+	if a.config.Citations.Enabled {
+		response = a.appendCitations(session.ID, response)
+	}
+
 	return response
 }
 
+// saveRunTrace persists a completed run's transcript (no-op without devclawDB).
+func (a *Assistant) saveRunTrace(tracer *RunTracer, runErr error) {
+	if a.runTraceStore == nil || tracer == nil {
+		return
+	}
+	if err := a.runTraceStore.Save(tracer, runErr); err != nil {
+		a.logger.Warn("failed to save run trace", "run_id", tracer.RunID(), "error", err)
+	}
+}
+
+// RunTraceStore returns the run transcript store (nil without devclawDB).
+func (a *Assistant) RunTraceStore() *RunTraceStore {
+	return a.runTraceStore
+}
+
 // ToolExecutor returns the tool executor for external tool registration.
 func (a *Assistant) ToolExecutor() *ToolExecutor {
 	return a.toolExecutor
@@ -1815,6 +2612,11 @@ func (a *Assistant) HookManager() *HookManager {
 	return a.hookMgr
 }
 
+// Events returns the event bus (for gateway event streaming).
+func (a *Assistant) Events() *EventBus {
+	return a.events
+}
+
 // Config returns the assistant configuration.
 func (a *Assistant) Config() *Config {
 	return a.config
@@ -1825,6 +2627,11 @@ func (a *Assistant) LLMClient() *LLMClient {
 	return a.llmClient
 }
 
+// ArtifactStore returns the artifact store (nil without the central database).
+func (a *Assistant) ArtifactStore() *ArtifactStore {
+	return a.artifactStore
+}
+
 // ForceCompactSession runs compaction immediately, returns old and new history length.
 func (a *Assistant) ForceCompactSession(session *Session) (oldLen, newLen int) {
 	return a.forceCompactSession(session)
@@ -1845,16 +2652,32 @@ func (a *Assistant) SQLiteMemory() *memory.SQLiteStore {
 	return a.sqliteMemory
 }
 
+// DB returns the central SQLite database handle (nil when unavailable).
+// Used by the gateway readiness probe to check DB reachability.
+func (a *Assistant) DB() *sql.DB {
+	return a.devclawDB
+}
+
 // SessionStore returns the session store (used by CLI chat).
 func (a *Assistant) SessionStore() *SessionStore {
 	return a.sessionStore
 }
 
 // Scheduler returns the task scheduler (may be nil if not initialized).
+// Notifier returns the push notification router (nil when unconfigured).
+func (a *Assistant) Notifier() *notify.Router {
+	return a.notifier
+}
+
 func (a *Assistant) Scheduler() *scheduler.Scheduler {
 	return a.scheduler
 }
 
+// Federation returns the federation manager (nil when federation is disabled).
+func (a *Assistant) Federation() *FederationManager {
+	return a.federationMgr
+}
+
 // ComposePrompt builds a system prompt for the given session and input.
 // Convenience method for CLI and external callers.
 func (a *Assistant) ComposePrompt(session *Session, input string) string {
@@ -1867,6 +2690,102 @@ func (a *Assistant) ExecuteAgent(ctx context.Context, systemPrompt string, sessi
 	return a.executeAgent(ctx, "default", session, systemPrompt, userMessage)
 }
 
+// ExecuteAgentStream runs the agent loop like ExecuteAgent, but forwards
+// progress to the listener as AgentEvents: "delta" for streamed text tokens,
+// "tool_use" when a tool call starts, and "tool_result" when it finishes.
+// Unlike ExecuteAgent it returns run errors to the caller instead of folding
+// them into the response text. Used by the CLI TUI and headless `run`.
+// Uses "default" as workspace ID.
+func (a *Assistant) ExecuteAgentStream(ctx context.Context, systemPrompt string, session *Session, userMessage string, listener EventListener) (string, error) {
+	runKey := "default:" + session.ID
+
+	// If the session has an active task worktree, steer edits into it.
+	if a.worktreeMgr != nil {
+		systemPrompt += a.worktreeMgr.PromptNote(session.ID)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer func() {
+		a.activeRunsMu.Lock()
+		delete(a.activeRuns, runKey)
+		a.activeRunsMu.Unlock()
+		cancel()
+	}()
+
+	a.activeRunsMu.Lock()
+	a.activeRuns[runKey] = cancel
+	a.activeRunsMu.Unlock()
+
+	history := session.RecentHistory(10)
+
+	agent := NewAgentRunWithConfig(a.llmClient, a.toolExecutor, a.config.Agent, a.logger)
+	agent.SetModelOverride(session.GetConfig().Model)
+	tracer := NewRunTracer(generateID(), session.ID)
+	agent.SetTracer(tracer)
+
+	if listener != nil {
+		agent.SetStreamCallback(func(chunk string) {
+			listener(AgentEvent{
+				SessionID: session.ID,
+				Stream:    "assistant",
+				Type:      "delta",
+				Timestamp: time.Now(),
+				Data:      map[string]string{"content": chunk},
+			})
+		})
+		agent.SetOnToolCall(func(name, argsJSON string) {
+			listener(AgentEvent{
+				SessionID: session.ID,
+				Stream:    "tool",
+				Type:      "tool_use",
+				Timestamp: time.Now(),
+				Data:      map[string]any{"tool": name, "input": argsJSON},
+			})
+		})
+		agent.SetOnToolResult(func(name string, result ToolResult) {
+			output := result.Content
+			if result.Error != nil {
+				output = result.Error.Error()
+			}
+			listener(AgentEvent{
+				SessionID: session.ID,
+				Stream:    "tool",
+				Type:      "tool_result",
+				Timestamp: time.Now(),
+				Data:      map[string]any{"tool": name, "output": output, "is_error": result.Error != nil},
+			})
+		})
+	}
+
+	// Wire tool loop detector (new instance per-run to avoid cross-session races).
+	if a.loopDetectorConfig.Enabled {
+		detector := NewToolLoopDetector(a.loopDetectorConfig, a.logger.With("component", "loop-detect"))
+		agent.SetLoopDetector(detector)
+	}
+
+	if a.usageTracker != nil {
+		agent.SetUsageRecorder(func(model string, usage LLMUsage) {
+			a.usageTracker.Record(session.ID, model, usage)
+		})
+	}
+
+	response, usage, err := agent.RunWithUsage(runCtx, systemPrompt, history, userMessage)
+	a.saveRunTrace(tracer, err)
+	if err != nil {
+		if runCtx.Err() != nil {
+			return "", fmt.Errorf("agent stopped: %w", runCtx.Err())
+		}
+		a.logger.Error("agent failed", "error", err)
+		return "", err
+	}
+
+	if usage != nil {
+		session.AddTokenUsage(usage.PromptTokens, usage.CompletionTokens)
+	}
+
+	return response, nil
+}
+
 // StopActiveRun cancels the active agent run for the given workspace and session.
 // It also signals the tool executor to abort all running tools and forces the
 // session out of "processing" state so new messages are handled immediately.
@@ -1918,6 +2837,10 @@ func (a *Assistant) sessionWatchdog() {
 	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 
+	// Aggregates recoveries for owner alerts and the flaky-tools report.
+	watchdogCfg := a.config.Routines.Watchdog
+	reporter := newWatchdogReporter()
+
 	for {
 		select {
 		case <-a.ctx.Done():
@@ -1928,6 +2851,10 @@ func (a *Assistant) sessionWatchdog() {
 				a.logger.Warn("watchdog: session stuck in processing, force-recovering",
 					"session", sessionID, "max_busy", maxBusy)
 
+				// Snapshot in-flight tools before aborting them — this is the
+				// "which tool hung" diagnostic in the owner alert.
+				running := a.toolExecutor.RunningTools()
+
 				// Try to cancel the active run.
 				a.activeRunsMu.Lock()
 				for key, cancel := range a.activeRuns {
@@ -1945,6 +2872,9 @@ func (a *Assistant) sessionWatchdog() {
 
 				// Force-clear the processing flag.
 				a.messageQueue.SetProcessing(sessionID, false)
+
+				// Tell the owner what happened and what was hanging.
+				a.notifyWatchdogRecovery(watchdogCfg, reporter, sessionID, maxBusy, running)
 			}
 		}
 	}
@@ -1975,9 +2905,22 @@ func (a *Assistant) initScheduler() {
 	// Job handler: runs the command as an agent turn.
 	// Scheduled jobs run with full trust (no approval prompts) because they
 	// were explicitly created by the user and execute autonomously.
-	handler := func(ctx context.Context, job *scheduler.Job) (string, error) {
+	handler := func(ctx context.Context, job *scheduler.Job) (output string, err error) {
+		// A panicking job is captured as a crash report and surfaced as a
+		// job error instead of killing the scheduler worker.
+		defer func() {
+			if r := recover(); r != nil {
+				a.capturePanic("scheduler:"+job.ID, r, debug.Stack())
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+
 		a.logger.Info("scheduler executing job", "id", job.ID, "command", job.Command,
 			"channel", job.Channel, "chat_id", job.ChatID)
+		a.events.EmitActivity(EventJobFired, "scheduler:"+job.ID, map[string]any{
+			"job_id":  job.ID,
+			"channel": job.Channel,
+		})
 
 		// Get or create a session for this scheduled job.
 		session := a.sessionStore.GetOrCreate("scheduler", job.ID)
@@ -2033,18 +2976,40 @@ func (a *Assistant) initScheduler() {
 		if job.Channel != "" && job.ChatID != "" {
 			// Strip internal tags before sending to user
 			cleanResult := StripInternalTags(result)
-			outMsg := &channels.OutgoingMessage{Content: cleanResult}
-			if sendErr := a.channelMgr.Send(ctx, job.Channel, job.ChatID, outMsg); sendErr != nil {
+			var sendErr error
+			if job.Delivery == scheduler.DeliveryWhenActive {
+				sendErr = a.sendWhenActive(ctx, job.Channel, job.ChatID, cleanResult)
+			} else {
+				sendErr = a.sendProactive(ctx, job.Channel, job.ChatID, cleanResult, job.Urgent)
+			}
+			if sendErr != nil {
 				a.logger.Error("failed to deliver scheduled message",
 					"job_id", job.ID, "error", sendErr,
 					"channel", job.Channel, "chat_id", job.ChatID)
+				// Chat delivery failed — push the reminder so it isn't lost.
+				a.notifier.Notify(ctx, &notify.Alert{
+					Event:    "scheduler.delivery_failed",
+					Title:    "Scheduled reminder",
+					Message:  cleanResult,
+					Priority: 2,
+				})
 			}
+		} else {
+			// No chat target — deliver as a push notification.
+			a.notifier.Notify(ctx, &notify.Alert{
+				Event:   "scheduler.completed",
+				Title:   "Scheduled reminder",
+				Message: StripInternalTags(result),
+			})
 		}
 
 		return result, nil
 	}
 
 	a.scheduler = scheduler.New(storage, handler, a.logger)
+	if a.devclawDB != nil {
+		a.scheduler.SetRunHistory(scheduler.NewSQLiteRunHistory(a.devclawDB))
+	}
 	a.logger.Info("scheduler initialized")
 }
 
@@ -2181,8 +3146,55 @@ func (a *Assistant) registerSystemTools() {
 	// Register skill database tools if available.
 	if a.skillDB != nil {
 		RegisterSkillDBTools(a.toolExecutor, a.skillDB)
+
+		// Finance skill (expenses, budgets, statement import).
+		if err := a.skillDB.InitFinanceTables(); err != nil {
+			a.logger.Warn("failed to initialize finance tables", "error", err)
+		} else {
+			RegisterFinanceTools(a.toolExecutor, a.skillDB, a.scheduler)
+		}
+
+		// Habits skill (recurring check-ins, streaks, weekly report).
+		if err := a.skillDB.InitHabitsTables(); err != nil {
+			a.logger.Warn("failed to initialize habits tables", "error", err)
+		} else {
+			RegisterHabitTools(a.toolExecutor, a.skillDB, a.scheduler)
+		}
+
+		// Shared lists skill (per-chat shopping lists and inventory).
+		if err := a.skillDB.InitSharedListsTables(); err != nil {
+			a.logger.Warn("failed to initialize shared lists tables", "error", err)
+		} else {
+			RegisterSharedListTools(a.toolExecutor, a.skillDB)
+		}
+
+		// Weather skill (forecasts, geocoding, per-user default locations).
+		if err := a.skillDB.InitWeatherTables(); err != nil {
+			a.logger.Warn("failed to initialize weather tables", "error", err)
+		} else {
+			RegisterWeatherTools(a.toolExecutor, a.skillDB, a.vault, a.scheduler)
+		}
+		if err := a.skillDB.InitCRMTables(); err != nil {
+			a.logger.Warn("failed to initialize crm tables", "error", err)
+		} else {
+			RegisterCRMTools(a.toolExecutor, a.skillDB, a.scheduler)
+		}
 	}
 
+	// Register web page change monitor tools (requires the central database).
+	if a.devclawDB != nil {
+		RegisterURLWatchTools(a.toolExecutor, NewURLWatchStore(a.devclawDB), a.scheduler, ssrfGuard)
+	}
+
+	// Register artifact tools so long outputs leave chat as named, versioned
+	// artifacts (requires the central database).
+	if a.artifactStore != nil {
+		RegisterArtifactTools(a.toolExecutor, a.artifactStore, a.config.Gateway, a.logger)
+	}
+
+	// Register deterministic math tools (calculator, unit and currency conversion).
+	RegisterCalcTools(a.toolExecutor)
+
 	// Register skill creator tools (including install_skill, search_skills, remove_skill).
 	skillsDir := "./skills"
 	if len(a.config.Skills.ClawdHubDirs) > 0 {
@@ -2204,6 +3216,15 @@ func (a *Assistant) registerSystemTools() {
 	// Register media tools (describe_image, transcribe_audio).
 	RegisterMediaTools(a.toolExecutor, a.llmClient, a.config, a.logger)
 
+	// Translation tool (also backs the auto-translate group mode).
+	RegisterTranslateTool(a.toolExecutor, a.llmClient, a.logger)
+
+	// Register the react tool so the agent can acknowledge with an emoji
+	// instead of a full text reply.
+	if a.config.Reactions.ReactTool {
+		RegisterReactionTools(a.toolExecutor, a.channelMgr, a.logger)
+	}
+
 	// Register native media tools (send_image, send_audio, send_document).
 	if a.mediaSvc != nil {
 		RegisterNativeMediaTools(a.toolExecutor, a.mediaSvc, a.channelMgr, a.logger)
@@ -2211,6 +3232,9 @@ func (a *Assistant) registerSystemTools() {
 
 	// Register native developer tools (git, docker, db, env, utils, codebase, testing, ops, product, IDE).
 	RegisterGitTools(a.toolExecutor)
+	RegisterTmuxTools(a.toolExecutor)
+	RegisterNotifyTools(a.toolExecutor)
+	RegisterMediaPlayerTools(a.toolExecutor, a.vault)
 	RegisterDockerTools(a.toolExecutor)
 	RegisterDBTools(a.toolExecutor)
 	RegisterDBHubTools(a.toolExecutor, a.dbHub) // Database hub management tools
@@ -2218,10 +3242,46 @@ func (a *Assistant) registerSystemTools() {
 	RegisterDevUtilTools(a.toolExecutor)
 	RegisterCodebaseTools(a.toolExecutor)
 	RegisterTestingTools(a.toolExecutor)
+	RegisterLintTools(a.toolExecutor)
+	RegisterDepsTools(a.toolExecutor, a.scheduler)
+	if a.devclawDB != nil {
+		RegisterLessonsTool(a.toolExecutor, a.devclawDB) // Self-critique lessons from past runs
+	}
+	if a.federationMgr != nil && len(a.config.Federation.Peers) > 0 {
+		RegisterFederationTool(a.toolExecutor, a) // Delegate tasks to remote DevClaw peers
+	}
+
+	// Dynamic tool selection: send only the top-N relevant tool definitions
+	// per turn, with find_tool as the escape hatch to load more.
+	if a.config.ToolSelection.Enabled {
+		embedCfg := a.config.Memory.Embedding
+		if embedCfg.APIKey == "" {
+			embedCfg.APIKey = a.config.API.APIKey
+		}
+		a.toolSelector = NewToolSelector(a.config.ToolSelection, memory.NewEmbeddingProvider(embedCfg), a.logger)
+		RegisterFindTool(a.toolExecutor, a.toolSelector)
+	}
+
+	// Data analysis over CSV/Parquet files dropped in chat.
+	if a.dataAnalyzer == nil {
+		a.dataAnalyzer = NewDataAnalyzer()
+	}
+	RegisterDataTools(a.toolExecutor, a.dataAnalyzer, a.channelMgr, a.logger)
 	RegisterOpsTools(a.toolExecutor)
 	RegisterProductTools(a.toolExecutor)
 	RegisterIDETools(a.toolExecutor)
 
+	// Persistent code index in devclaw.db. Registered after the codebase tools
+	// so the indexed code_symbols takes precedence over the per-file variant.
+	if a.devclawDB != nil {
+		if idx, err := NewCodeIndex(a.devclawDB, a.logger); err != nil {
+			a.logger.Warn("code index not available", "error", err)
+		} else {
+			a.codeIndex = idx
+			RegisterCodeIndexTools(a.toolExecutor, a.projectMgr, idx)
+		}
+	}
+
 	// Register browser tools if enabled.
 	if a.config.Browser.Enabled {
 		a.browserMgr = NewBrowserManager(a.config.Browser, a.logger)
@@ -2234,14 +3294,51 @@ func (a *Assistant) registerSystemTools() {
 	if a.daemonMgr == nil {
 		a.daemonMgr = NewDaemonManager()
 	}
+	// Crash notifications go back to the session that started the daemon.
+	a.daemonMgr.SetCrashNotifier(func(sessionID, channel, chatID, content string) {
+		a.enqueueFollowupMessage(sessionID, content, channel, chatID)
+	})
 	RegisterDaemonTools(a.toolExecutor, a.daemonMgr)
 
+	// Test watch mode: re-runs the suite in the background and feeds parsed
+	// failures back into the originating session as they happen.
+	RegisterTestWatchTool(a.toolExecutor, a.daemonMgr, func(sessionID, channel, chatID, content string) {
+		a.enqueueFollowupMessage(sessionID, content, channel, chatID)
+	})
+
+	// Event triggers: local events (file changed, port up/down, daemon
+	// exited) enqueue agent prompts into the session that armed them.
+	if a.triggerMgr == nil {
+		a.triggerMgr = NewTriggerManager(a.daemonMgr, func(sessionID, channel, chatID, content string) {
+			a.enqueueFollowupMessage(sessionID, content, channel, chatID)
+		}, a.logger)
+	}
+	RegisterTriggerTools(a.toolExecutor, a.triggerMgr)
+
 	// Register plugin system.
 	if a.pluginMgr == nil {
 		a.pluginMgr = NewPluginManager()
 	}
 	RegisterPluginTools(a.toolExecutor, a.pluginMgr)
 
+	// Home Assistant smart home integration.
+	if a.config.HomeAssistant.Enabled {
+		a.haClient = NewHomeAssistantClient(a.config.HomeAssistant, a.logger)
+		RegisterHomeAssistantTools(a.toolExecutor, a.haClient)
+
+		// MQTT events (motion, doors, sensors) become agent prompts in the
+		// designated session.
+		haCfg := a.config.HomeAssistant
+		if haCfg.EventChannel != "" && haCfg.EventChatID != "" {
+			sessionID := MakeSessionID(haCfg.EventChannel, haCfg.EventChatID)
+			if err := a.haClient.StartMQTT(func(content string) {
+				a.enqueueFollowupMessage(sessionID, content, haCfg.EventChannel, haCfg.EventChatID)
+			}); err != nil {
+				a.logger.Error("home assistant mqtt unavailable", "error", err)
+			}
+		}
+	}
+
 	// Register multi-user tools (when enabled).
 	if a.config.Team.Enabled {
 		if a.userMgr == nil {
@@ -2379,7 +3476,7 @@ func (a *Assistant) autoCaptureFacts(userMessage, assistantResponse, sessionID s
 	ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
 	defer cancel()
 
-	result, err := a.llmClient.Complete(ctx, "", nil, extractPrompt)
+	result, err := a.llmClient.CompleteUtility(ctx, "", nil, extractPrompt)
 	if err != nil || strings.TrimSpace(result) == "NOTHING" || strings.TrimSpace(result) == "" {
 		return
 	}
@@ -2423,6 +3520,10 @@ func (a *Assistant) autoCaptureFacts(userMessage, assistantResponse, sessionID s
 		"facts_saved", len(facts),
 		"session", sessionID,
 	)
+	a.events.EmitActivity(EventMemorySaved, sessionID, map[string]any{
+		"count":  len(facts),
+		"source": "auto-capture",
+	})
 }
 
 // truncateForCapture limits text length for memory extraction prompts.
@@ -2470,7 +3571,7 @@ func (a *Assistant) compactSummarize(session *Session, threshold int) {
 	const maxSummaryRetries = 3
 
 	for attempt := 1; attempt <= maxSummaryRetries; attempt++ {
-		summary, summaryErr = a.llmClient.Complete(a.ctx, "", session.RecentHistory(20), summaryPrompt)
+		summary, summaryErr = a.llmClient.CompleteUtility(a.ctx, "", session.RecentHistory(20), summaryPrompt)
 		if summaryErr == nil {
 			break
 		}
@@ -2618,6 +3719,18 @@ func (a *Assistant) enrichMessageContentFast(msg *channels.IncomingMessage, logg
 			return enriched, false
 		}
 		return msg.Content, false
+
+	case channels.MessageSticker:
+		if !a.config.Reactions.DescribeStickers || !media.VisionEnabled {
+			return msg.Content, false
+		}
+		// Stickers are small images — describe them inline via vision so
+		// they aren't dropped as opaque "[sticker]" placeholders.
+		enriched := a.enrichMessageContent(a.ctx, msg, logger)
+		if enriched != msg.Content {
+			return enriched, false
+		}
+		return msg.Content, false
 	}
 
 	return msg.Content, false
@@ -2644,6 +3757,8 @@ func (a *Assistant) enrichMediaAsync(ctx context.Context, msg *channels.Incoming
 		result = fmt.Sprintf("[Document content extracted]\n%s", enriched)
 	case channels.MessageVideo:
 		result = fmt.Sprintf("[Video analysis complete]\n%s", enriched)
+	case channels.MessageSticker:
+		result = fmt.Sprintf("[Sticker description complete]\n%s", enriched)
 	default:
 		result = enriched
 	}
@@ -2723,12 +3838,20 @@ func (a *Assistant) enrichMessageContent(ctx context.Context, msg *channels.Inco
 		if filename == "" {
 			filename = "audio.ogg"
 		}
-		transcript, err := a.llmClient.TranscribeAudio(ctx, data, filename, media.TranscriptionModel, media)
+		transcript, err := a.transcribeAudioChunked(ctx, data, filename, media, logger)
 		if err != nil {
 			logger.Warn("audio transcription failed", "error", err)
 			return msg.Content
 		}
 		logger.Info("audio transcribed via Whisper", "transcript_len", len(transcript))
+		// Long recordings (meetings) get the dedicated notes pipeline:
+		// summary + action items + transcript archived to memory, instead of
+		// a giant transcript inlined into one prompt.
+		if len(transcript) >= meetingTranscriptMinChars {
+			if notes := a.processMeetingTranscript(ctx, msg, transcript, logger); notes != "" {
+				return notes
+			}
+		}
 		content := msg.Content
 		content = strings.ReplaceAll(content, "[audio]", transcript)
 		content = strings.ReplaceAll(content, "[voice note]", transcript)
@@ -2740,10 +3863,18 @@ func (a *Assistant) enrichMessageContent(ctx context.Context, msg *channels.Inco
 			logger.Warn("no text extracted from document", "filename", msg.Media.Filename)
 			return msg.Content
 		}
-		// Truncate very large documents to avoid context overflow.
+		// Large documents get a map-reduce summary instead of a hard cut-off,
+		// so contracts and reports survive enrichment intact. Truncation
+		// remains the fallback when summarization fails.
 		const maxDocChars = 30000
 		if len(text) > maxDocChars {
-			text = text[:maxDocChars] + "\n... [truncated — document too large]"
+			summary, err := a.summarizeLongDocument(ctx, msg.Media.Filename, text, logger)
+			if err != nil {
+				logger.Warn("document summarization failed, truncating", "error", err)
+				text = text[:maxDocChars] + "\n... [truncated — document too large]"
+			} else {
+				text = summary
+			}
 		}
 		logger.Info("document text extracted", "chars", len(text), "filename", msg.Media.Filename)
 		if msg.Content != "" {
@@ -2764,6 +3895,28 @@ func (a *Assistant) enrichMessageContent(ctx context.Context, msg *channels.Inco
 			return fmt.Sprintf("[Video: %s]\n\n%s", desc, msg.Content)
 		}
 		return fmt.Sprintf("[Video: %s]", desc)
+
+	case channels.MessageSticker:
+		if !a.config.Reactions.DescribeStickers || !media.VisionEnabled {
+			return msg.Content
+		}
+		if int64(len(data)) > media.MaxImageSize {
+			logger.Warn("sticker too large to process", "size", len(data), "max", media.MaxImageSize)
+			return msg.Content
+		}
+		stickerBase64 := base64.StdEncoding.EncodeToString(data)
+		if mimeType == "" {
+			mimeType = "image/webp"
+		}
+		desc, err := a.llmClient.CompleteWithVision(ctx, "", stickerBase64, mimeType,
+			"This is a sticker the user sent in chat. Describe the sticker briefly: the character/subject, the emotion or reaction it conveys, and any text on it.",
+			media.VisionDetail, media.VisionModel)
+		if err != nil {
+			logger.Warn("sticker vision description failed", "error", err)
+			return msg.Content
+		}
+		logger.Info("sticker described via vision API", "desc_len", len(desc))
+		return fmt.Sprintf("[Sticker: %s]", desc)
 	}
 
 	return msg.Content
@@ -2971,6 +4124,9 @@ func (a *Assistant) maybeSendTTS(msg *channels.IncomingMessage, response string)
 // describe "image saved to /tmp/..." — the user sees the actual image.
 func (a *Assistant) makeToolResultHook(channel, chatID string) func(string, ToolResult) {
 	return func(toolName string, result ToolResult) {
+		if result.Error != nil {
+			return
+		}
 		if toolName != "generate_image" && toolName != "image-gen_generate_image" {
 			return
 		}
@@ -3008,11 +4164,24 @@ func (a *Assistant) makeToolResultHook(channel, chatID string) func(string, Tool
 }
 
 func (a *Assistant) sendReply(original *channels.IncomingMessage, content string) {
+	content = a.postProcess(content, PostProcessContext{
+		Channel:   original.Channel,
+		ChatID:    original.ChatID,
+		Workspace: a.workspaceMgr.WorkspaceIDFor(original.Channel, original.ChatID, original.From, original.IsGroup),
+	})
 	content = FormatForChannel(content, original.Channel)
 	if content == "" {
 		return // Nothing to send (e.g. NO_REPLY, HEARTBEAT_OK, or only tags).
 	}
 
+	// Visible reminder while the session is elevated via /sudo.
+	if a.sudoMgr != nil {
+		sessionID := MakeSessionID(original.Channel, original.ChatID)
+		if left := a.sudoMgr.Remaining(sessionID); left > 0 {
+			content += fmt.Sprintf("\n\n⚡ _sudo mode active (%s left)_", formatSudoRemaining(left))
+		}
+	}
+
 	maxLen := MaxMessageDefault
 	// Could be per-channel configurable later (e.g. WhatsApp: MaxMessageWhatsApp)
 
@@ -0,0 +1,136 @@
+// Package copilot – audio_lid.go implements automatic language identification
+// for audio. Instead of relying on a static transcription_language config, a
+// fast first pass over a clipped audio sample asks Whisper which language is
+// spoken (verbose_json carries the detected language), and the result becomes
+// the language hint for the real transcription. This keeps accuracy high for
+// multilingual users without per-message configuration.
+package copilot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// maxLIDSampleBytes clips the audio sent for language detection. Truncated
+// MP3/Ogg streams decode fine from the start, and a few seconds of speech is
+// enough for identification.
+const maxLIDSampleBytes = 256 * 1024
+
+// whisperLanguageCodes maps the language names Whisper reports in
+// verbose_json to the ISO 639-1 codes its "language" parameter expects.
+var whisperLanguageCodes = map[string]string{
+	"english": "en", "portuguese": "pt", "spanish": "es", "french": "fr",
+	"german": "de", "italian": "it", "japanese": "ja", "korean": "ko",
+	"chinese": "zh", "dutch": "nl", "russian": "ru", "arabic": "ar",
+	"hindi": "hi", "turkish": "tr", "polish": "pl", "swedish": "sv",
+	"ukrainian": "uk", "vietnamese": "vi", "indonesian": "id", "thai": "th",
+}
+
+// DetectAudioLanguage runs a fast Whisper pass over a clipped sample and
+// returns the detected ISO 639-1 language code ("" when unknown).
+// Always uses whisper-1: GLM-ASR has no language identification.
+func (c *LLMClient) DetectAudioLanguage(ctx context.Context, audioData []byte, filename string, media ...MediaConfig) (string, error) {
+	if filename == "" {
+		filename = "audio.webm"
+	}
+	var mediaCfg *MediaConfig
+	if len(media) > 0 {
+		m := media[0]
+		mediaCfg = &m
+	}
+
+	sample := audioData
+	if len(sample) > maxLIDSampleBytes {
+		sample = sample[:maxLIDSampleBytes]
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return "", fmt.Errorf("creating form file: %w", err)
+	}
+	if _, err := part.Write(sample); err != nil {
+		return "", fmt.Errorf("writing audio sample: %w", err)
+	}
+	if err := w.WriteField("model", "whisper-1"); err != nil {
+		return "", fmt.Errorf("writing model field: %w", err)
+	}
+	// verbose_json includes the detected language; no hint is passed so
+	// Whisper runs its own identification.
+	if err := w.WriteField("response_format", "verbose_json"); err != nil {
+		return "", fmt.Errorf("writing response_format field: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.audioEndpoint(mediaCfg), &buf)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+c.audioAPIKey(mediaCfg))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("language detection request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("language detection API returned %d: %s", resp.StatusCode, truncate(string(respBody), 200))
+	}
+
+	var j struct {
+		Language string `json:"language"`
+	}
+	if err := json.Unmarshal(respBody, &j); err != nil {
+		return "", fmt.Errorf("parsing detection response: %w", err)
+	}
+	return whisperLanguageCode(j.Language), nil
+}
+
+// whisperLanguageCode normalizes Whisper's reported language to an ISO 639-1
+// code. Unknown names return "" so no misleading hint is passed downstream.
+func whisperLanguageCode(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ""
+	}
+	// Some providers already return the code itself.
+	if len(name) == 2 {
+		return name
+	}
+	return whisperLanguageCodes[name]
+}
+
+// detectTranscriptionLanguage fills the language hint dynamically when
+// auto-detection is enabled and no static hint is configured. Returns the
+// (possibly updated) config copy; detection failures fall back to Whisper's
+// built-in per-call detection, hint-less.
+func (a *Assistant) detectTranscriptionLanguage(ctx context.Context, data []byte, filename string, mediaCfg MediaConfig) MediaConfig {
+	if !mediaCfg.TranscriptionLanguageDetect || mediaCfg.TranscriptionLanguage != "" {
+		return mediaCfg
+	}
+	lang, err := a.llmClient.DetectAudioLanguage(ctx, data, filename, mediaCfg)
+	if err != nil {
+		a.logger.Debug("audio language detection failed", "error", err)
+		return mediaCfg
+	}
+	if lang != "" {
+		a.logger.Info("audio language detected", "language", lang)
+		mediaCfg.TranscriptionLanguage = lang
+	}
+	return mediaCfg
+}
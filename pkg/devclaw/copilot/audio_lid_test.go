@@ -0,0 +1,85 @@
+package copilot
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWhisperLanguageCode(t *testing.T) {
+	tests := map[string]string{
+		"english":    "en",
+		"Portuguese": "pt",
+		"pt":         "pt", // already a code
+		"klingon":    "",
+		"":           "",
+	}
+	for name, want := range tests {
+		if got := whisperLanguageCode(name); got != want {
+			t.Errorf("whisperLanguageCode(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDetectAudioLanguage(t *testing.T) {
+	var gotLanguageField bool
+	var gotFormat string
+	var gotFileBytes int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(2 << 20); err != nil {
+			t.Fatalf("parsing multipart form: %v", err)
+		}
+		_, gotLanguageField = r.MultipartForm.Value["language"]
+		gotFormat = r.FormValue("response_format")
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("reading file part: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		file.Close()
+		gotFileBytes = len(data)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"language": "Portuguese", "text": "olá"}`))
+	}))
+	defer srv.Close()
+
+	client := &LLMClient{provider: "openai", httpClient: srv.Client(), logger: slog.Default()}
+	media := MediaConfig{TranscriptionBaseURL: srv.URL, TranscriptionAPIKey: "test-key"}
+
+	audio := make([]byte, maxLIDSampleBytes*2)
+	lang, err := client.DetectAudioLanguage(context.Background(), audio, "voice.ogg", media)
+	if err != nil {
+		t.Fatalf("DetectAudioLanguage: %v", err)
+	}
+	if lang != "pt" {
+		t.Errorf("detected language = %q, want %q", lang, "pt")
+	}
+	if gotLanguageField {
+		t.Error("detection request must not pass a language hint")
+	}
+	if gotFormat != "verbose_json" {
+		t.Errorf("response_format = %q, want verbose_json", gotFormat)
+	}
+	if gotFileBytes != maxLIDSampleBytes {
+		t.Errorf("sample not clipped: sent %d bytes, want %d", gotFileBytes, maxLIDSampleBytes)
+	}
+}
+
+func TestDetectAudioLanguageAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := &LLMClient{provider: "openai", httpClient: srv.Client(), logger: slog.Default()}
+	media := MediaConfig{TranscriptionBaseURL: srv.URL, TranscriptionAPIKey: "test-key"}
+
+	if _, err := client.DetectAudioLanguage(context.Background(), []byte("xx"), "voice.ogg", media); err == nil {
+		t.Error("expected error for API failure")
+	}
+}
@@ -0,0 +1,115 @@
+// Package copilot – bash_isolation.go implements optional isolation wrappers
+// for the bash tool. When a tool profile configures a wrapper (bubblewrap,
+// firejail, or nsjail), bash commands run inside a jail that restricts
+// filesystem visibility, network access, and capabilities — while keeping the
+// tool's persistent-cwd semantics, since the wrapped shell still sees the
+// same paths and reports its pwd.
+package copilot
+
+import "fmt"
+
+// BashIsolationConfig configures the isolation wrapper for the bash tool.
+type BashIsolationConfig struct {
+	// Wrapper selects the isolation tool: "bubblewrap" (or "bwrap"),
+	// "firejail", or "nsjail". Empty = run bash directly on the host.
+	Wrapper string `yaml:"wrapper"`
+
+	// AllowNetwork keeps network access inside the jail (default: false).
+	AllowNetwork bool `yaml:"allow_network"`
+
+	// WritablePaths are host paths bind-mounted read-write into the jail.
+	// Everything else is visible read-only.
+	WritablePaths []string `yaml:"writable_paths"`
+
+	// ReadOnlyPaths are extra paths explicitly bind-mounted read-only
+	// (useful with firejail, which otherwise applies its own defaults).
+	ReadOnlyPaths []string `yaml:"read_only_paths"`
+}
+
+// isolationBinary returns the executable name for a wrapper, for availability
+// checks via exec.LookPath. Empty string = unknown wrapper.
+func isolationBinary(wrapper string) string {
+	switch wrapper {
+	case "bubblewrap", "bwrap":
+		return "bwrap"
+	case "firejail":
+		return "firejail"
+	case "nsjail":
+		return "nsjail"
+	default:
+		return ""
+	}
+}
+
+// isolationArgv builds the full argv for running bash under the configured
+// wrapper. bashArgs is the unwrapped bash invocation ("bash", "-l", "-c", cmd).
+func isolationArgv(cfg *BashIsolationConfig, bashArgs []string) ([]string, error) {
+	var argv []string
+
+	switch cfg.Wrapper {
+	case "bubblewrap", "bwrap":
+		argv = []string{
+			"bwrap",
+			"--ro-bind", "/", "/",
+			"--dev", "/dev",
+			"--proc", "/proc",
+			"--tmpfs", "/tmp",
+			"--unshare-pid",
+			"--unshare-ipc",
+			"--unshare-uts",
+			"--die-with-parent",
+		}
+		if !cfg.AllowNetwork {
+			argv = append(argv, "--unshare-net")
+		}
+		for _, p := range cfg.ReadOnlyPaths {
+			argv = append(argv, "--ro-bind", p, p)
+		}
+		for _, p := range cfg.WritablePaths {
+			argv = append(argv, "--bind", p, p)
+		}
+		argv = append(argv, "--")
+
+	case "firejail":
+		argv = []string{
+			"firejail",
+			"--quiet",
+			"--caps.drop=all",
+			"--nonewprivs",
+			"--private-tmp",
+		}
+		if !cfg.AllowNetwork {
+			argv = append(argv, "--net=none")
+		}
+		for _, p := range cfg.ReadOnlyPaths {
+			argv = append(argv, "--read-only="+p)
+		}
+		for _, p := range cfg.WritablePaths {
+			argv = append(argv, "--read-write="+p)
+		}
+		argv = append(argv, "--")
+
+	case "nsjail":
+		argv = []string{
+			"nsjail",
+			"-q",
+			"-Mo",
+			"--chroot", "/",
+		}
+		if cfg.AllowNetwork {
+			argv = append(argv, "--disable_clone_newnet")
+		}
+		for _, p := range cfg.ReadOnlyPaths {
+			argv = append(argv, "--bindmount_ro", p+":"+p)
+		}
+		for _, p := range cfg.WritablePaths {
+			argv = append(argv, "--bindmount", p+":"+p)
+		}
+		argv = append(argv, "--")
+
+	default:
+		return nil, fmt.Errorf("unsupported isolation wrapper: %q", cfg.Wrapper)
+	}
+
+	return append(argv, bashArgs...), nil
+}
@@ -0,0 +1,80 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsolationBinary(t *testing.T) {
+	cases := map[string]string{
+		"bubblewrap": "bwrap",
+		"bwrap":      "bwrap",
+		"firejail":   "firejail",
+		"nsjail":     "nsjail",
+		"chroot":     "",
+	}
+	for wrapper, want := range cases {
+		if got := isolationBinary(wrapper); got != want {
+			t.Errorf("isolationBinary(%q) = %q, want %q", wrapper, got, want)
+		}
+	}
+}
+
+func TestIsolationArgvBubblewrap(t *testing.T) {
+	bash := []string{"bash", "-l", "-c", "ls"}
+
+	argv, err := isolationArgv(&BashIsolationConfig{
+		Wrapper:       "bubblewrap",
+		WritablePaths: []string{"/data/scratch"},
+	}, bash)
+	if err != nil {
+		t.Fatalf("argv: %v", err)
+	}
+
+	joined := strings.Join(argv, " ")
+	if argv[0] != "bwrap" {
+		t.Errorf("argv[0] = %q, want bwrap", argv[0])
+	}
+	if !strings.Contains(joined, "--unshare-net") {
+		t.Error("network not unshared by default")
+	}
+	if !strings.Contains(joined, "--bind /data/scratch /data/scratch") {
+		t.Error("writable path not bind-mounted")
+	}
+	if !strings.HasSuffix(joined, "-- bash -l -c ls") {
+		t.Errorf("bash invocation not preserved: %q", joined)
+	}
+
+	// AllowNetwork drops the network unshare.
+	argv, err = isolationArgv(&BashIsolationConfig{Wrapper: "bwrap", AllowNetwork: true}, bash)
+	if err != nil {
+		t.Fatalf("argv: %v", err)
+	}
+	if strings.Contains(strings.Join(argv, " "), "--unshare-net") {
+		t.Error("network unshared despite allow_network")
+	}
+}
+
+func TestIsolationArgvFirejail(t *testing.T) {
+	argv, err := isolationArgv(&BashIsolationConfig{
+		Wrapper:       "firejail",
+		WritablePaths: []string{"/data/scratch"},
+		ReadOnlyPaths: []string{"/etc"},
+	}, []string{"bash", "-l", "-c", "ls"})
+	if err != nil {
+		t.Fatalf("argv: %v", err)
+	}
+
+	joined := strings.Join(argv, " ")
+	for _, want := range []string{"--caps.drop=all", "--net=none", "--read-write=/data/scratch", "--read-only=/etc"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in %q", want, joined)
+		}
+	}
+}
+
+func TestIsolationArgvUnsupported(t *testing.T) {
+	if _, err := isolationArgv(&BashIsolationConfig{Wrapper: "chroot"}, []string{"bash"}); err == nil {
+		t.Error("expected error for unsupported wrapper")
+	}
+}
@@ -79,6 +79,10 @@ type BlockStreamer struct {
 	done    bool // Finish() was called
 	flushed bool // at least one block was sent
 
+	// onFlush is called with each block after it is sent, e.g. to feed
+	// streaming TTS. Must not block: it runs with mu held.
+	onFlush func(text string)
+
 	idleTimer *time.Timer
 	ctx       context.Context
 	cancel    context.CancelFunc
@@ -103,6 +107,15 @@ func NewBlockStreamer(
 	}
 }
 
+// SetOnFlush registers a callback invoked with each block after it is sent.
+// The callback must not block (it runs under the streamer lock) — hand the
+// text off to a queue or goroutine instead.
+func (bs *BlockStreamer) SetOnFlush(fn func(text string)) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.onFlush = fn
+}
+
 // StreamCallback returns a StreamCallback function suitable for AgentRun.SetStreamCallback.
 func (bs *BlockStreamer) StreamCallback() StreamCallback {
 	return func(chunk string) {
@@ -257,6 +270,10 @@ func (bs *BlockStreamer) flushLocked() {
 	bs.flushed = true
 	bs.sent += len(sendText)
 
+	if bs.onFlush != nil {
+		bs.onFlush(sendText)
+	}
+
 	// Reset buffer with remainder.
 	bs.buf.Reset()
 	if remainder != "" {
@@ -0,0 +1,174 @@
+// Package copilot – boot.go implements the managed startup sequencer. Start()
+// registers named subsystems with declared dependencies; the sequencer runs
+// them in order, retries transient failures with backoff (channel connects),
+// skips subsystems whose dependencies failed, and produces a startup report
+// that is pushed to the owner when anything came up degraded.
+package copilot
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BootStatus is the outcome of one subsystem's startup.
+type BootStatus string
+
+const (
+	// BootOK means the subsystem started normally.
+	BootOK BootStatus = "ok"
+	// BootFailed means the subsystem failed to start; dependents are skipped.
+	BootFailed BootStatus = "failed"
+	// BootSkipped means a dependency failed, so the subsystem never ran.
+	BootSkipped BootStatus = "skipped"
+	// BootDisabled means the subsystem is off by configuration.
+	BootDisabled BootStatus = "disabled"
+)
+
+// BootStep records one subsystem's startup outcome.
+type BootStep struct {
+	Name       string
+	Status     BootStatus
+	Error      string
+	Attempts   int
+	DurationMs int64
+}
+
+// BootSequencer tracks the startup sequence. Steps run in registration order
+// (Start() is inherently sequential); the sequencer adds dependency checks,
+// retry with backoff, and outcome recording on top.
+type BootSequencer struct {
+	mu     sync.Mutex
+	steps  []BootStep
+	logger *slog.Logger
+}
+
+// NewBootSequencer creates an empty startup sequencer.
+func NewBootSequencer(logger *slog.Logger) *BootSequencer {
+	return &BootSequencer{logger: logger.With("component", "boot")}
+}
+
+// Run executes one subsystem's startup function and records the outcome.
+// If any declared dependency failed, the step is skipped without running.
+// A failure never aborts startup — the tree degrades, but the report shows it.
+func (b *BootSequencer) Run(name string, deps []string, fn func() error) error {
+	return b.RunWithRetry(name, deps, 1, 0, fn)
+}
+
+// RunWithRetry is Run with retry/backoff for transient failures (e.g. channel
+// connects racing network availability at boot).
+func (b *BootSequencer) RunWithRetry(name string, deps []string, attempts int, backoff time.Duration, fn func() error) error {
+	if failed := b.failedDependency(deps); failed != "" {
+		b.record(BootStep{Name: name, Status: BootSkipped,
+			Error: fmt.Sprintf("dependency %q failed", failed)})
+		b.logger.Warn("boot step skipped", "step", name, "failed_dependency", failed)
+		return fmt.Errorf("boot step %s skipped: dependency %s failed", name, failed)
+	}
+
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	start := time.Now()
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(); err == nil {
+			b.record(BootStep{Name: name, Status: BootOK, Attempts: attempt,
+				DurationMs: time.Since(start).Milliseconds()})
+			return nil
+		}
+		if attempt < attempts {
+			b.logger.Warn("boot step failed, retrying",
+				"step", name, "attempt", attempt, "backoff", backoff, "error", err)
+			time.Sleep(backoff)
+		}
+	}
+
+	b.record(BootStep{Name: name, Status: BootFailed, Error: err.Error(),
+		Attempts: attempts, DurationMs: time.Since(start).Milliseconds()})
+	b.logger.Error("boot step failed", "step", name, "attempts", attempts, "error", err)
+	return err
+}
+
+// Disabled records a subsystem that is off by configuration.
+func (b *BootSequencer) Disabled(name string) {
+	b.record(BootStep{Name: name, Status: BootDisabled})
+}
+
+// failedDependency returns the first dependency that failed or was skipped,
+// or empty string when all dependencies came up.
+func (b *BootSequencer) failedDependency(deps []string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, dep := range deps {
+		for _, step := range b.steps {
+			if step.Name == dep && (step.Status == BootFailed || step.Status == BootSkipped) {
+				return dep
+			}
+		}
+	}
+	return ""
+}
+
+func (b *BootSequencer) record(step BootStep) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.steps = append(b.steps, step)
+}
+
+// Steps returns the recorded startup outcomes in execution order.
+func (b *BootSequencer) Steps() []BootStep {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]BootStep, len(b.steps))
+	copy(out, b.steps)
+	return out
+}
+
+// Degraded reports whether any subsystem failed or was skipped.
+func (b *BootSequencer) Degraded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, step := range b.steps {
+		if step.Status == BootFailed || step.Status == BootSkipped {
+			return true
+		}
+	}
+	return false
+}
+
+// Report renders the startup sequence as a chat-friendly summary.
+func (b *BootSequencer) Report() string {
+	steps := b.Steps()
+	if len(steps) == 0 {
+		return "No boot steps recorded."
+	}
+
+	var bld strings.Builder
+	bld.WriteString("*Startup report*\n")
+	for _, step := range steps {
+		icon := "✅"
+		switch step.Status {
+		case BootFailed:
+			icon = "❌"
+		case BootSkipped:
+			icon = "⏭️"
+		case BootDisabled:
+			icon = "➖"
+		}
+		fmt.Fprintf(&bld, "%s %s", icon, step.Name)
+		if step.Attempts > 1 {
+			fmt.Fprintf(&bld, " (%d attempts)", step.Attempts)
+		}
+		if step.DurationMs > 100 {
+			fmt.Fprintf(&bld, " [%dms]", step.DurationMs)
+		}
+		if step.Error != "" {
+			fmt.Fprintf(&bld, " — %s", truncateStr(step.Error, 120))
+		}
+		bld.WriteString("\n")
+	}
+	return strings.TrimRight(bld.String(), "\n")
+}
@@ -0,0 +1,87 @@
+package copilot
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBootSequencer() *BootSequencer {
+	return NewBootSequencer(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+}
+
+func TestBootSequencerDependencySkip(t *testing.T) {
+	b := testBootSequencer()
+
+	if err := b.Run("database", nil, func() error { return errors.New("disk full") }); err == nil {
+		t.Fatal("expected database step to fail")
+	}
+
+	ran := false
+	err := b.Run("sessions", []string{"database"}, func() error {
+		ran = true
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected skip error for dependent step")
+	}
+	if ran {
+		t.Error("dependent step should not run when its dependency failed")
+	}
+
+	steps := b.Steps()
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+	if steps[0].Status != BootFailed || steps[1].Status != BootSkipped {
+		t.Errorf("unexpected statuses: %s, %s", steps[0].Status, steps[1].Status)
+	}
+	if !b.Degraded() {
+		t.Error("sequence with failures should report degraded")
+	}
+}
+
+func TestBootSequencerRetry(t *testing.T) {
+	b := testBootSequencer()
+
+	calls := 0
+	err := b.RunWithRetry("channels", nil, 3, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+
+	steps := b.Steps()
+	if len(steps) != 1 || steps[0].Status != BootOK || steps[0].Attempts != 3 {
+		t.Errorf("unexpected step record: %+v", steps)
+	}
+	if b.Degraded() {
+		t.Error("success after retry should not report degraded")
+	}
+}
+
+func TestBootSequencerReport(t *testing.T) {
+	b := testBootSequencer()
+	b.Run("memory", nil, func() error { return nil })
+	b.Run("database", nil, func() error { return errors.New("locked") })
+	b.Run("sessions", []string{"database"}, func() error { return nil })
+	b.Disabled("heartbeat")
+
+	report := b.Report()
+	for _, want := range []string{"✅ memory", "❌ database — locked", "⏭️ sessions", "➖ heartbeat"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,534 @@
+// Package copilot – calc_tools.go implements deterministic math for the
+// agent: an expression calculator, unit conversion, and currency conversion
+// with a cached daily rates fetch. LLMs botch arithmetic; these tools let
+// the agent offload it reliably.
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------- Expression calculator ----------
+
+// calcFunctions are the functions the expression parser accepts.
+var calcFunctions = map[string]func(float64) float64{
+	"sqrt":  math.Sqrt,
+	"abs":   math.Abs,
+	"round": math.Round,
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"ln":    math.Log,
+	"log":   math.Log10,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+}
+
+// calcConstants are named values the parser accepts.
+var calcConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// calcParser is a recursive-descent parser over a tokenized expression.
+// Grammar: expr = term (('+'|'-') term)* ; term = power (('*'|'/'|'%') power)* ;
+// power = unary ('^' power)? ; unary = '-' unary | primary.
+type calcParser struct {
+	input string
+	pos   int
+}
+
+// evalExpression evaluates an arithmetic expression deterministically.
+func evalExpression(expr string) (float64, error) {
+	p := &calcParser{input: expr}
+	v, err := p.parseExpr()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("expression has no finite result")
+	}
+	return v, nil
+}
+
+func (p *calcParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *calcParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *calcParser) parseExpr() (float64, error) {
+	v, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			rhs, err := p.parseTerm()
+			if err != nil {
+				return 0, err
+			}
+			v += rhs
+		case '-':
+			p.pos++
+			rhs, err := p.parseTerm()
+			if err != nil {
+				return 0, err
+			}
+			v -= rhs
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *calcParser) parseTerm() (float64, error) {
+	v, err := p.parsePower()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '*':
+			p.pos++
+			rhs, err := p.parsePower()
+			if err != nil {
+				return 0, err
+			}
+			v *= rhs
+		case '/':
+			p.pos++
+			rhs, err := p.parsePower()
+			if err != nil {
+				return 0, err
+			}
+			if rhs == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			v /= rhs
+		case '%':
+			p.pos++
+			rhs, err := p.parsePower()
+			if err != nil {
+				return 0, err
+			}
+			if rhs == 0 {
+				return 0, fmt.Errorf("modulo by zero")
+			}
+			v = math.Mod(v, rhs)
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *calcParser) parsePower() (float64, error) {
+	v, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() == '^' {
+		p.pos++
+		// Right-associative: 2^3^2 = 2^(3^2).
+		rhs, err := p.parsePower()
+		if err != nil {
+			return 0, err
+		}
+		return math.Pow(v, rhs), nil
+	}
+	return v, nil
+}
+
+func (p *calcParser) parseUnary() (float64, error) {
+	if p.peek() == '-' {
+		p.pos++
+		v, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		return -v, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *calcParser) parsePrimary() (float64, error) {
+	c := p.peek()
+
+	if c == '(' {
+		p.pos++
+		v, err := p.parseExpr()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return v, nil
+	}
+
+	if c >= '0' && c <= '9' || c == '.' {
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.' || p.input[p.pos] == '_') {
+			p.pos++
+		}
+		num := strings.ReplaceAll(p.input[start:p.pos], "_", "")
+		v, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", num)
+		}
+		return v, nil
+	}
+
+	if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= 'a' && p.input[p.pos] <= 'z' || p.input[p.pos] >= 'A' && p.input[p.pos] <= 'Z') {
+			p.pos++
+		}
+		name := strings.ToLower(p.input[start:p.pos])
+
+		if v, ok := calcConstants[name]; ok {
+			return v, nil
+		}
+		fn, ok := calcFunctions[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown function or constant %q", name)
+		}
+		if p.peek() != '(' {
+			return 0, fmt.Errorf("function %q requires parentheses", name)
+		}
+		p.pos++
+		arg, err := p.parseExpr()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return fn(arg), nil
+	}
+
+	if c == 0 {
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+	return 0, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+}
+
+// formatCalcResult trims trailing zeros without losing precision.
+func formatCalcResult(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'g', 12, 64)
+}
+
+// ---------- Unit conversion ----------
+
+// unitFactors maps unit names to a factor relative to the category's base
+// unit. Units only convert within the same category.
+var unitFactors = map[string]struct {
+	category string
+	factor   float64
+}{
+	// Length (base: meter)
+	"mm": {"length", 0.001}, "cm": {"length", 0.01}, "m": {"length", 1},
+	"km": {"length", 1000}, "in": {"length", 0.0254}, "ft": {"length", 0.3048},
+	"yd": {"length", 0.9144}, "mi": {"length", 1609.344},
+	// Mass (base: kilogram)
+	"mg": {"mass", 1e-6}, "g": {"mass", 0.001}, "kg": {"mass", 1},
+	"t": {"mass", 1000}, "oz": {"mass", 0.028349523125}, "lb": {"mass", 0.45359237},
+	// Volume (base: liter)
+	"ml": {"volume", 0.001}, "l": {"volume", 1}, "gal": {"volume", 3.785411784},
+	"cup": {"volume", 0.2365882365}, "floz": {"volume", 0.0295735295625},
+	// Time (base: second)
+	"s": {"time", 1}, "min": {"time", 60}, "h": {"time", 3600},
+	"day": {"time", 86400}, "week": {"time", 604800},
+	// Speed (base: m/s)
+	"m/s": {"speed", 1}, "km/h": {"speed", 1.0 / 3.6}, "mph": {"speed", 0.44704},
+	"knot": {"speed", 0.514444},
+	// Data (base: byte, binary multiples)
+	"b": {"data", 1}, "kb": {"data", 1 << 10}, "mb": {"data", 1 << 20},
+	"gb": {"data", 1 << 30}, "tb": {"data", 1 << 40},
+}
+
+// convertUnit converts a value between compatible units. Temperatures
+// (c, f, k) are handled separately because they are offset scales.
+func convertUnit(value float64, from, to string) (float64, error) {
+	from = strings.ToLower(strings.TrimSpace(from))
+	to = strings.ToLower(strings.TrimSpace(to))
+
+	if isTempUnit(from) || isTempUnit(to) {
+		if !isTempUnit(from) || !isTempUnit(to) {
+			return 0, fmt.Errorf("cannot convert between %q and %q", from, to)
+		}
+		return convertTemperature(value, from, to)
+	}
+
+	fu, ok := unitFactors[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	tu, ok := unitFactors[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if fu.category != tu.category {
+		return 0, fmt.Errorf("cannot convert %s (%s) to %s (%s)", from, fu.category, to, tu.category)
+	}
+
+	return value * fu.factor / tu.factor, nil
+}
+
+func isTempUnit(u string) bool {
+	return u == "c" || u == "f" || u == "k" || u == "°c" || u == "°f"
+}
+
+func convertTemperature(value float64, from, to string) (float64, error) {
+	from = strings.TrimPrefix(from, "°")
+	to = strings.TrimPrefix(to, "°")
+
+	// Normalize to Celsius.
+	var celsius float64
+	switch from {
+	case "c":
+		celsius = value
+	case "f":
+		celsius = (value - 32) * 5 / 9
+	case "k":
+		celsius = value - 273.15
+	default:
+		return 0, fmt.Errorf("unknown temperature unit %q", from)
+	}
+
+	switch to {
+	case "c":
+		return celsius, nil
+	case "f":
+		return celsius*9/5 + 32, nil
+	case "k":
+		return celsius + 273.15, nil
+	default:
+		return 0, fmt.Errorf("unknown temperature unit %q", to)
+	}
+}
+
+// ---------- Currency conversion ----------
+
+// currencyCacheTTL is how long fetched exchange rates stay fresh. The
+// upstream feed updates daily.
+const currencyCacheTTL = 24 * time.Hour
+
+// currencyRates caches USD-based exchange rates from a keyless daily feed.
+type currencyRates struct {
+	client  *http.Client
+	baseURL string
+
+	mu        sync.Mutex
+	rates     map[string]float64
+	fetchedAt time.Time
+}
+
+func newCurrencyRates(client *http.Client) *currencyRates {
+	return &currencyRates{client: client, baseURL: "https://open.er-api.com"}
+}
+
+// Convert converts between two ISO currency codes using USD cross rates.
+func (c *currencyRates) Convert(ctx context.Context, value float64, from, to string) (float64, error) {
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+
+	rates, err := c.get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	fromRate, ok := rates[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", from)
+	}
+	toRate, ok := rates[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", to)
+	}
+
+	return value / fromRate * toRate, nil
+}
+
+// get returns cached rates, refreshing them when stale.
+func (c *currencyRates) get(ctx context.Context) (map[string]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rates != nil && time.Since(c.fetchedAt) < currencyCacheTTL {
+		return c.rates, nil
+	}
+
+	body, err := c.fetch(ctx)
+	if err != nil {
+		// A stale cache beats no answer.
+		if c.rates != nil {
+			return c.rates, nil
+		}
+		return nil, fmt.Errorf("fetching exchange rates: %w", err)
+	}
+
+	var resp struct {
+		Result string             `json:"result"`
+		Rates  map[string]float64 `json:"rates"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing exchange rates: %w", err)
+	}
+	if resp.Result != "success" || len(resp.Rates) == 0 {
+		return nil, fmt.Errorf("exchange rate feed returned no rates")
+	}
+
+	c.rates = resp.Rates
+	c.fetchedAt = time.Now()
+	return c.rates, nil
+}
+
+func (c *currencyRates) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v6/latest/USD", nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "DevClaw/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("rate feed returned HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// ---------- Tools ----------
+
+// RegisterCalcTools registers the calculator and conversion tools in the
+// executor.
+func RegisterCalcTools(executor *ToolExecutor) {
+	rates := newCurrencyRates(&http.Client{Timeout: 15 * time.Second})
+
+	// calc
+	executor.Register(
+		MakeToolDefinition("calc", "Evaluate an arithmetic expression deterministically. ALWAYS use this instead of doing math yourself. Supports + - * / % ^, parentheses, and functions: sqrt, abs, round, floor, ceil, ln, log, sin, cos, tan; constants pi and e.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"expression": map[string]any{
+					"type":        "string",
+					"description": "The expression to evaluate, e.g. '(1200 * 1.08) / 12' or 'sqrt(2) * 10'",
+				},
+			},
+			"required": []string{"expression"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			expr, _ := args["expression"].(string)
+			if expr == "" {
+				return nil, fmt.Errorf("expression is required")
+			}
+			v, err := evalExpression(expr)
+			if err != nil {
+				return nil, fmt.Errorf("evaluating %q: %w", expr, err)
+			}
+			return fmt.Sprintf("%s = %s", expr, formatCalcResult(v)), nil
+		},
+	)
+
+	// convert_unit
+	executor.Register(
+		MakeToolDefinition("convert_unit", "Convert between units: length (mm/cm/m/km/in/ft/yd/mi), mass (mg/g/kg/t/oz/lb), volume (ml/l/gal/cup/floz), temperature (c/f/k), time (s/min/h/day/week), speed (m/s, km/h, mph, knot), data (b/kb/mb/gb/tb).", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"value": map[string]any{
+					"type":        "number",
+					"description": "The value to convert",
+				},
+				"from": map[string]any{
+					"type":        "string",
+					"description": "Source unit (e.g. 'km', 'lb', 'f')",
+				},
+				"to": map[string]any{
+					"type":        "string",
+					"description": "Target unit (e.g. 'mi', 'kg', 'c')",
+				},
+			},
+			"required": []string{"value", "from", "to"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			value, _ := args["value"].(float64)
+			from, _ := args["from"].(string)
+			to, _ := args["to"].(string)
+
+			result, err := convertUnit(value, from, to)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("%s %s = %s %s", formatCalcResult(value), from, formatCalcResult(result), to), nil
+		},
+	)
+
+	// convert_currency
+	executor.Register(
+		MakeToolDefinition("convert_currency", "Convert between currencies using daily exchange rates (cached for 24h). Use ISO codes: USD, EUR, BRL, GBP, JPY, ...", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"value": map[string]any{
+					"type":        "number",
+					"description": "The amount to convert",
+				},
+				"from": map[string]any{
+					"type":        "string",
+					"description": "Source currency code (e.g. 'USD')",
+				},
+				"to": map[string]any{
+					"type":        "string",
+					"description": "Target currency code (e.g. 'BRL')",
+				},
+			},
+			"required": []string{"value", "from", "to"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			value, _ := args["value"].(float64)
+			from, _ := args["from"].(string)
+			to, _ := args["to"].(string)
+			if from == "" || to == "" {
+				return nil, fmt.Errorf("from and to currencies are required")
+			}
+
+			result, err := rates.Convert(ctx, value, from, to)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("%.2f %s = %.2f %s (daily rate)", value, strings.ToUpper(from), result, strings.ToUpper(to)), nil
+		},
+	)
+}
@@ -0,0 +1,186 @@
+// Package copilot – calc_tools_test.go contains unit tests for the
+// calculator and conversion tools.
+package copilot
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvalExpression(t *testing.T) {
+	cases := []struct {
+		expr string
+		want float64
+	}{
+		{"1 + 2", 3},
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 / 4", 2.5},
+		{"10 % 3", 1},
+		{"2^10", 1024},
+		{"2^3^2", 512}, // right-associative
+		{"-5 + 3", -2},
+		{"-(2 + 3)", -5},
+		{"sqrt(16)", 4},
+		{"abs(-3.5)", 3.5},
+		{"round(2.6)", 3},
+		{"floor(2.9)", 2},
+		{"ceil(2.1)", 3},
+		{"2 * pi", 2 * math.Pi},
+		{"1_000_000 / 4", 250000},
+		{"(1200 * 1.08) / 12", 108},
+	}
+	for _, tc := range cases {
+		got, err := evalExpression(tc.expr)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.expr, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%q = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestEvalExpressionErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"1 / 0",
+		"10 % 0",
+		"(1 + 2",
+		"1 + * 2",
+		"nope(3)",
+		"sqrt 4",
+		"1 2",
+	} {
+		if _, err := evalExpression(expr); err == nil {
+			t.Errorf("%q: expected error, got none", expr)
+		}
+	}
+}
+
+func TestFormatCalcResult(t *testing.T) {
+	cases := []struct {
+		in   float64
+		want string
+	}{
+		{3, "3"},
+		{2.5, "2.5"},
+		{1024, "1024"},
+		{-0.125, "-0.125"},
+	}
+	for _, tc := range cases {
+		if got := formatCalcResult(tc.in); got != tc.want {
+			t.Errorf("formatCalcResult(%v) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestConvertUnit(t *testing.T) {
+	cases := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{5, "km", "mi", 3.106855961},
+		{1, "mi", "m", 1609.344},
+		{10, "lb", "kg", 4.5359237},
+		{1, "gal", "l", 3.785411784},
+		{2, "h", "min", 120},
+		{100, "km/h", "m/s", 27.7777777778},
+		{1, "gb", "mb", 1024},
+		{100, "c", "f", 212},
+		{32, "F", "C", 0},
+		{0, "c", "k", 273.15},
+	}
+	for _, tc := range cases {
+		got, err := convertUnit(tc.value, tc.from, tc.to)
+		if err != nil {
+			t.Errorf("%v %s -> %s: unexpected error: %v", tc.value, tc.from, tc.to, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-6 {
+			t.Errorf("%v %s -> %s = %v, want %v", tc.value, tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestConvertUnitIncompatible(t *testing.T) {
+	if _, err := convertUnit(1, "kg", "km"); err == nil {
+		t.Error("expected error converting mass to length")
+	}
+	if _, err := convertUnit(1, "c", "m"); err == nil {
+		t.Error("expected error converting temperature to length")
+	}
+	if _, err := convertUnit(1, "furlong", "m"); err == nil {
+		t.Error("expected error for unknown unit")
+	}
+}
+
+func TestCurrencyRatesCaching(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"result": "success", "rates": {"USD": 1.0, "EUR": 0.9, "BRL": 5.0}}`))
+	}))
+	defer srv.Close()
+
+	c := newCurrencyRates(srv.Client())
+	c.baseURL = srv.URL
+
+	got, err := c.Convert(context.Background(), 100, "usd", "brl")
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if math.Abs(got-500) > 1e-9 {
+		t.Errorf("100 USD = %v BRL, want 500", got)
+	}
+
+	// Cross rate through USD.
+	got, err = c.Convert(context.Background(), 9, "EUR", "BRL")
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if math.Abs(got-50) > 1e-9 {
+		t.Errorf("9 EUR = %v BRL, want 50", got)
+	}
+
+	if _, err := c.Convert(context.Background(), 1, "USD", "XXX"); err == nil {
+		t.Error("expected error for unknown currency")
+	}
+
+	// All calls within the TTL share one fetch.
+	if n := hits.Load(); n != 1 {
+		t.Errorf("expected 1 upstream fetch, got %d", n)
+	}
+
+	// A stale cache triggers a refetch.
+	c.fetchedAt = time.Now().Add(-25 * time.Hour)
+	if _, err := c.Convert(context.Background(), 1, "USD", "EUR"); err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("expected 2 upstream fetches after expiry, got %d", n)
+	}
+}
+
+func TestCurrencyRatesStaleFallback(t *testing.T) {
+	c := newCurrencyRates(&http.Client{Timeout: time.Second})
+	c.baseURL = "http://127.0.0.1:0" // unroutable
+	c.rates = map[string]float64{"USD": 1.0, "EUR": 0.9}
+	c.fetchedAt = time.Now().Add(-48 * time.Hour)
+
+	// The feed is down but stale rates still answer.
+	got, err := c.Convert(context.Background(), 10, "USD", "EUR")
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if math.Abs(got-9) > 1e-9 {
+		t.Errorf("10 USD = %v EUR, want 9", got)
+	}
+}
@@ -0,0 +1,209 @@
+// Package copilot – citations.go tracks which external contents (web_search,
+// web_fetch) contributed to an agent turn and appends compact source links to
+// the reply. The full list for the last answer stays available via /sources.
+package copilot
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// CitationsConfig configures source tracking for web-derived answers.
+type CitationsConfig struct {
+	// Enabled turns citation tracking on/off (default: false).
+	Enabled bool `yaml:"enabled"`
+
+	// MaxSources caps how many sources are appended to a reply; the rest
+	// stay reachable via /sources (default: 3).
+	MaxSources int `yaml:"max_sources"`
+
+	// Style selects the appended format: "domains" (compact, default) or
+	// "urls" (full links).
+	Style string `yaml:"style"`
+}
+
+// DefaultCitationsConfig returns default citation configuration.
+func DefaultCitationsConfig() CitationsConfig {
+	return CitationsConfig{
+		Enabled:    false,
+		MaxSources: 3,
+		Style:      "domains",
+	}
+}
+
+// Citation is one external source that contributed to a turn.
+type Citation struct {
+	URL  string
+	Tool string
+}
+
+// CitationTracker collects sources per session during a run and keeps the
+// previous turn's list for /sources.
+type CitationTracker struct {
+	mu      sync.Mutex
+	current map[string][]Citation
+	last    map[string][]Citation
+}
+
+// NewCitationTracker creates an empty tracker.
+func NewCitationTracker() *CitationTracker {
+	return &CitationTracker{
+		current: make(map[string][]Citation),
+		last:    make(map[string][]Citation),
+	}
+}
+
+// BeginTurn clears the in-progress source list for a session.
+func (t *CitationTracker) BeginTurn(sessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.current, sessionID)
+}
+
+// externalContentRefRe matches the ref attribute of wrapExternalContent
+// output, which carries the fetched URL for web_fetch results.
+var externalContentRefRe = regexp.MustCompile(`<external-content source="[^"]*" ref="([^"]+)"`)
+
+// urlRe matches http(s) URLs inside web_search result text.
+var urlRe = regexp.MustCompile(`https?://[^\s"'<>)\]]+`)
+
+// maxSearchURLsPerCall caps how many result URLs one web_search contributes.
+const maxSearchURLsPerCall = 5
+
+// Record extracts source URLs from a web tool result and adds them to the
+// session's in-progress list. Non-web tools are ignored.
+func (t *CitationTracker) Record(sessionID, toolName, resultContent string) {
+	var urls []string
+	switch toolName {
+	case "web_fetch":
+		if m := externalContentRefRe.FindStringSubmatch(resultContent); m != nil {
+			urls = append(urls, m[1])
+		}
+	case "web_search":
+		found := urlRe.FindAllString(resultContent, maxSearchURLsPerCall)
+		urls = append(urls, found...)
+	default:
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	existing := t.current[sessionID]
+	for _, u := range urls {
+		u = strings.TrimRight(u, ".,;")
+		if !isHTTPURL(u) || containsCitation(existing, u) {
+			continue
+		}
+		existing = append(existing, Citation{URL: u, Tool: toolName})
+	}
+	t.current[sessionID] = existing
+}
+
+// EndTurn finalizes the turn: the collected sources become the session's
+// "last answer" list and are returned for appending to the reply.
+func (t *CitationTracker) EndTurn(sessionID string) []Citation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	citations := t.current[sessionID]
+	delete(t.current, sessionID)
+	if len(citations) > 0 {
+		t.last[sessionID] = citations
+	}
+	return citations
+}
+
+// Last returns the sources behind the session's most recent answer.
+func (t *CitationTracker) Last(sessionID string) []Citation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last[sessionID]
+}
+
+// isHTTPURL reports whether s parses as an absolute http(s) URL.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// containsCitation reports whether the URL was already collected.
+func containsCitation(citations []Citation, u string) bool {
+	for _, c := range citations {
+		if c.URL == u {
+			return true
+		}
+	}
+	return false
+}
+
+// formatCitations renders the compact source line appended to a reply.
+func formatCitations(citations []Citation, cfg CitationsConfig) string {
+	if len(citations) == 0 {
+		return ""
+	}
+	maxShown := cfg.MaxSources
+	if maxShown <= 0 {
+		maxShown = DefaultCitationsConfig().MaxSources
+	}
+
+	var parts []string
+	for i, c := range citations {
+		if i >= maxShown {
+			break
+		}
+		if cfg.Style == "urls" {
+			parts = append(parts, c.URL)
+		} else {
+			parts = append(parts, citationDomain(c.URL))
+		}
+	}
+	line := "Sources: " + strings.Join(parts, ", ")
+	if extra := len(citations) - maxShown; extra > 0 {
+		line += fmt.Sprintf(" (+%d more — /sources)", extra)
+	}
+	return line
+}
+
+// citationDomain reduces a URL to its host for the compact style.
+func citationDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return strings.TrimPrefix(u.Host, "www.")
+}
+
+// appendCitations finalizes the turn's source list and appends the compact
+// source line to the reply. Silent responses are left untouched.
+func (a *Assistant) appendCitations(sessionID, response string) string {
+	citations := a.citations.EndTurn(sessionID)
+	trimmed := strings.TrimSpace(response)
+	if len(citations) == 0 || trimmed == "" ||
+		strings.EqualFold(trimmed, TokenNoReply) || strings.EqualFold(trimmed, TokenHeartbeatOK) {
+		return response
+	}
+	return response + "\n\n" + formatCitations(citations, a.config.Citations)
+}
+
+// sourcesCommand implements /sources: the full source list behind the last
+// answer in this chat.
+func (a *Assistant) sourcesCommand(msg *channels.IncomingMessage) string {
+	if !a.config.Citations.Enabled {
+		return "Citation tracking is disabled (citations.enabled)."
+	}
+	citations := a.citations.Last(MakeSessionID(msg.Channel, msg.ChatID))
+	if len(citations) == 0 {
+		return "No sources recorded for the last answer."
+	}
+
+	var b strings.Builder
+	b.WriteString("Sources for the last answer:\n")
+	for i, c := range citations {
+		fmt.Fprintf(&b, "%d. %s (%s)\n", i+1, c.URL, c.Tool)
+	}
+	return b.String()
+}
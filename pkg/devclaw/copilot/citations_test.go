@@ -0,0 +1,91 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCitationTrackerTurnLifecycle(t *testing.T) {
+	tracker := NewCitationTracker()
+	tracker.BeginTurn("s1")
+
+	fetched := wrapExternalContent("web_fetch", "https://example.com/page", "Status: 200\nbody")
+	tracker.Record("s1", "web_fetch", fetched)
+	// Duplicate URL and non-web tools are ignored.
+	tracker.Record("s1", "web_fetch", fetched)
+	tracker.Record("s1", "run_command", "https://evil.example.com/ignored")
+
+	citations := tracker.EndTurn("s1")
+	if len(citations) != 1 {
+		t.Fatalf("expected 1 citation, got %d: %+v", len(citations), citations)
+	}
+	if citations[0].URL != "https://example.com/page" || citations[0].Tool != "web_fetch" {
+		t.Errorf("unexpected citation: %+v", citations[0])
+	}
+
+	// EndTurn keeps the list available for /sources and clears the turn.
+	if last := tracker.Last("s1"); len(last) != 1 {
+		t.Errorf("Last() after EndTurn = %d citations", len(last))
+	}
+	if again := tracker.EndTurn("s1"); len(again) != 0 {
+		t.Errorf("second EndTurn returned %d citations", len(again))
+	}
+}
+
+func TestCitationTrackerSearchResults(t *testing.T) {
+	tracker := NewCitationTracker()
+	tracker.BeginTurn("s1")
+
+	content := wrapExternalContent("web_search", "go generics",
+		"1. Generics intro\n   https://go.dev/blog/intro-generics\n"+
+			"2. Spec\n   https://go.dev/ref/spec.\n"+
+			"3. Not a link: ftp://example.com/file\n")
+	tracker.Record("s1", "web_search", content)
+
+	citations := tracker.EndTurn("s1")
+	if len(citations) != 2 {
+		t.Fatalf("expected 2 citations, got %d: %+v", len(citations), citations)
+	}
+	// Trailing punctuation is stripped.
+	if citations[1].URL != "https://go.dev/ref/spec" {
+		t.Errorf("trailing dot kept: %q", citations[1].URL)
+	}
+}
+
+func TestFormatCitations(t *testing.T) {
+	citations := []Citation{
+		{URL: "https://www.example.com/a", Tool: "web_fetch"},
+		{URL: "https://go.dev/blog", Tool: "web_search"},
+		{URL: "https://pkg.go.dev/fmt", Tool: "web_search"},
+		{URL: "https://news.example.org/x", Tool: "web_search"},
+	}
+
+	got := formatCitations(citations, CitationsConfig{MaxSources: 2, Style: "domains"})
+	if !strings.Contains(got, "example.com") || !strings.Contains(got, "go.dev") {
+		t.Errorf("domains missing: %q", got)
+	}
+	if strings.Contains(got, "https://") {
+		t.Errorf("domains style leaked full URLs: %q", got)
+	}
+	if !strings.Contains(got, "(+2 more — /sources)") {
+		t.Errorf("overflow hint missing: %q", got)
+	}
+
+	got = formatCitations(citations[:1], CitationsConfig{MaxSources: 3, Style: "urls"})
+	if !strings.Contains(got, "https://www.example.com/a") {
+		t.Errorf("urls style missing full URL: %q", got)
+	}
+
+	if got := formatCitations(nil, DefaultCitationsConfig()); got != "" {
+		t.Errorf("empty list rendered: %q", got)
+	}
+}
+
+func TestCitationDomain(t *testing.T) {
+	if got := citationDomain("https://www.example.com/path?q=1"); got != "example.com" {
+		t.Errorf("citationDomain = %q", got)
+	}
+	if got := citationDomain("not a url"); got != "not a url" {
+		t.Errorf("unparseable input changed: %q", got)
+	}
+}
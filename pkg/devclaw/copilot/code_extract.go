@@ -0,0 +1,279 @@
+// Package copilot – code_extract.go handles source files and patches sent as
+// documents: language detection, outline extraction for files too large to
+// inline, and rendering of unified diffs as structured hunks the agent can
+// reason about.
+package copilot
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+)
+
+// Limits for code attachments.
+const (
+	maxCodeFileBytes   = 48 * 1024 // larger files get an outline instead
+	maxOutlineEntries  = 200       // declarations listed in an outline
+	codePreviewLines   = 40        // leading lines shown alongside an outline
+	maxDiffRenderLines = 400       // diff lines rendered across all hunks
+)
+
+// codeLanguages maps source file extensions to language names, used both for
+// detection and for fencing extracted code.
+var codeLanguages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".java":  "java",
+	".c":     "c",
+	".h":     "c",
+	".cpp":   "cpp",
+	".cc":    "cpp",
+	".hpp":   "cpp",
+	".rs":    "rust",
+	".rb":    "ruby",
+	".php":   "php",
+	".cs":    "csharp",
+	".kt":    "kotlin",
+	".swift": "swift",
+	".sh":    "bash",
+	".bash":  "bash",
+	".sql":   "sql",
+	".lua":   "lua",
+	".pl":    "perl",
+	".scala": "scala",
+	".ex":    "elixir",
+	".exs":   "elixir",
+}
+
+// detectCodeLanguage returns the language for a source file, or "" for
+// non-code attachments.
+func detectCodeLanguage(filename string) string {
+	return codeLanguages[strings.ToLower(filepath.Ext(filename))]
+}
+
+// extractCodeText renders a source file for enrichment. Small files are
+// inlined in a fenced block with the detected language; large files get a
+// declaration outline plus a leading preview instead of blind truncation.
+func extractCodeText(data []byte, filename string, logger *slog.Logger) string {
+	lang := detectCodeLanguage(filename)
+	content := strings.TrimRight(string(data), "\n")
+
+	if len(data) <= maxCodeFileBytes {
+		return fmt.Sprintf("```%s\n%s\n```", lang, content)
+	}
+
+	logger.Debug("code file too large, extracting outline",
+		"filename", filename, "size", len(data), "language", lang)
+
+	lines := strings.Split(content, "\n")
+	outline := extractCodeOutline(lines)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s is a large %s file (%d lines, %d KB) — showing an outline instead of the full content.]\n\n",
+		filename, lang, len(lines), len(data)/1024)
+
+	preview := lines
+	if len(preview) > codePreviewLines {
+		preview = preview[:codePreviewLines]
+	}
+	fmt.Fprintf(&b, "First %d lines:\n```%s\n%s\n```\n\n", len(preview), lang, strings.Join(preview, "\n"))
+
+	if len(outline) > 0 {
+		b.WriteString("Declarations:\n")
+		for _, entry := range outline {
+			b.WriteString(entry + "\n")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// outlinePrefixes are declaration keywords recognized across the supported
+// languages. Lines are matched after trimming indentation, so methods and
+// nested classes appear too.
+var outlinePrefixes = []string{
+	"func ", "def ", "class ", "type ", "interface ", "struct ",
+	"fn ", "impl ", "function ", "module ", "trait ", "enum ",
+	"public ", "private ", "protected ", "static ",
+}
+
+// extractCodeOutline lists declaration lines with their line numbers.
+func extractCodeOutline(lines []string) []string {
+	var outline []string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		for _, prefix := range outlinePrefixes {
+			if strings.HasPrefix(trimmed, prefix) {
+				outline = append(outline, fmt.Sprintf("  L%d: %s", i+1, strings.TrimRight(trimmed, " {")))
+				break
+			}
+		}
+		if len(outline) >= maxOutlineEntries {
+			outline = append(outline, fmt.Sprintf("  [outline truncated at %d entries]", maxOutlineEntries))
+			break
+		}
+	}
+	return outline
+}
+
+// --- Unified diffs ---
+
+// diffHunk is one @@-delimited change block.
+type diffHunk struct {
+	Header  string // "@@ -12,6 +12,8 @@ func main()"
+	Added   int
+	Removed int
+	Lines   []string
+}
+
+// diffFile groups the hunks touching one file.
+type diffFile struct {
+	Path  string
+	Hunks []diffHunk
+}
+
+// isDiffAttachment reports whether an attachment is a unified diff, by
+// extension or by content shape.
+func isDiffAttachment(data []byte, ext string) bool {
+	if ext == ".diff" || ext == ".patch" {
+		return true
+	}
+	head := data
+	if len(head) > 2048 {
+		head = head[:2048]
+	}
+	if bytes.HasPrefix(head, []byte("diff --git ")) {
+		return true
+	}
+	return bytes.Contains(head, []byte("\n--- ")) && bytes.Contains(head, []byte("\n+++ ")) &&
+		bytes.Contains(head, []byte("\n@@ "))
+}
+
+// extractDiffText parses a unified diff and renders it as structured changes:
+// a summary line, then per-file sections with hunk headers and counts.
+func extractDiffText(data []byte, filename string, logger *slog.Logger) string {
+	files := parseDiffAttachment(string(data))
+	if len(files) == 0 {
+		logger.Debug("attachment looked like a diff but had no hunks", "filename", filename)
+		return string(data)
+	}
+
+	totalAdded, totalRemoved := 0, 0
+	for _, f := range files {
+		for _, h := range f.Hunks {
+			totalAdded += h.Added
+			totalRemoved += h.Removed
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Patch %s: %d file(s) changed, +%d/-%d lines.\n\n", filename, len(files), totalAdded, totalRemoved)
+
+	rendered := 0
+	for _, f := range files {
+		added, removed := 0, 0
+		for _, h := range f.Hunks {
+			added += h.Added
+			removed += h.Removed
+		}
+		fmt.Fprintf(&b, "### %s (+%d/-%d, %d hunk(s))\n", f.Path, added, removed, len(f.Hunks))
+		for i, h := range f.Hunks {
+			fmt.Fprintf(&b, "Hunk %d: %s (+%d/-%d)\n", i+1, h.Header, h.Added, h.Removed)
+			if rendered >= maxDiffRenderLines {
+				continue
+			}
+			b.WriteString("```diff\n")
+			for _, line := range h.Lines {
+				if rendered >= maxDiffRenderLines {
+					b.WriteString("[remaining diff lines omitted]\n")
+					break
+				}
+				b.WriteString(line + "\n")
+				rendered++
+			}
+			b.WriteString("```\n")
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// parseDiffAttachment splits a unified diff into files and hunks for
+// rendering. Unlike parseUnifiedDiff in apply_patch.go, which validates
+// strictly before applying changes, this parser is lenient: forwarded patches
+// are often trimmed or partially quoted, and a readable summary beats a parse
+// error.
+func parseDiffAttachment(text string) []diffFile {
+	var files []diffFile
+	var current *diffFile
+	var hunk *diffHunk
+
+	closeHunk := func() {
+		if current != nil && hunk != nil {
+			current.Hunks = append(current.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			closeHunk()
+			files = append(files, diffFile{Path: gitDiffPath(line)})
+			current = &files[len(files)-1]
+		case strings.HasPrefix(line, "+++ "):
+			path := strings.TrimPrefix(line, "+++ ")
+			path = strings.TrimPrefix(path, "b/")
+			if current == nil || (current.Path != "" && len(current.Hunks) > 0 && current.Path != path) {
+				closeHunk()
+				files = append(files, diffFile{})
+				current = &files[len(files)-1]
+			}
+			if current.Path == "" && path != "/dev/null" {
+				current.Path = path
+			}
+		case strings.HasPrefix(line, "@@ "):
+			closeHunk()
+			if current == nil {
+				files = append(files, diffFile{Path: "(unknown)"})
+				current = &files[len(files)-1]
+			}
+			hunk = &diffHunk{Header: line, Lines: []string{}}
+		case hunk != nil:
+			hunk.Lines = append(hunk.Lines, line)
+			if strings.HasPrefix(line, "+") {
+				hunk.Added++
+			} else if strings.HasPrefix(line, "-") {
+				hunk.Removed++
+			}
+		}
+	}
+	closeHunk()
+
+	// Drop header-only entries that never produced hunks.
+	var filled []diffFile
+	for _, f := range files {
+		if len(f.Hunks) > 0 {
+			filled = append(filled, f)
+		}
+	}
+	return filled
+}
+
+// gitDiffPath extracts the new path from a "diff --git a/x b/x" header.
+func gitDiffPath(line string) string {
+	parts := strings.Fields(line)
+	if len(parts) >= 4 {
+		return strings.TrimPrefix(parts[3], "b/")
+	}
+	return ""
+}
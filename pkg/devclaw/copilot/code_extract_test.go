@@ -0,0 +1,104 @@
+package copilot
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDetectCodeLanguage(t *testing.T) {
+	tests := map[string]string{
+		"main.go":   "go",
+		"app.PY":    "python",
+		"index.tsx": "typescript",
+		"notes.txt": "",
+		"data.json": "",
+	}
+	for filename, want := range tests {
+		if got := detectCodeLanguage(filename); got != want {
+			t.Errorf("detectCodeLanguage(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
+
+func TestExtractCodeTextSmallFile(t *testing.T) {
+	src := "package main\n\nfunc main() {}\n"
+	text := extractCodeText([]byte(src), "main.go", slog.Default())
+	if !strings.HasPrefix(text, "```go\n") || !strings.Contains(text, "func main() {}") {
+		t.Errorf("expected fenced go block:\n%s", text)
+	}
+}
+
+func TestExtractCodeTextOutline(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("package big\n")
+	for i := 0; i < 3000; i++ {
+		fmt.Fprintf(&b, "func Handler%d() {\n\t// body body body body body body\n}\n", i)
+	}
+	text := extractCodeText([]byte(b.String()), "big.go", slog.Default())
+	if !strings.Contains(text, "showing an outline") {
+		t.Fatalf("expected outline mode for large file:\n%.300s", text)
+	}
+	if !strings.Contains(text, "L2: func Handler0()") {
+		t.Errorf("missing declaration entries:\n%.500s", text)
+	}
+	if !strings.Contains(text, "outline truncated") {
+		t.Errorf("expected outline truncation note")
+	}
+}
+
+func TestIsDiffAttachment(t *testing.T) {
+	if !isDiffAttachment(nil, ".patch") || !isDiffAttachment(nil, ".diff") {
+		t.Error("extension detection failed")
+	}
+	gitDiff := []byte("diff --git a/x.go b/x.go\n")
+	if !isDiffAttachment(gitDiff, ".txt") {
+		t.Error("git header detection failed")
+	}
+	if isDiffAttachment([]byte("just some text"), ".txt") {
+		t.Error("plain text misdetected as diff")
+	}
+}
+
+const sampleDiff = `diff --git a/pkg/server.go b/pkg/server.go
+index 1111111..2222222 100644
+--- a/pkg/server.go
++++ b/pkg/server.go
+@@ -10,6 +10,7 @@ func Start() {
+ 	listen()
++	logStartup()
+ 	serve()
+@@ -30,5 +31,4 @@ func Stop() {
+-	drain()
+ 	close()
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1,2 +1,3 @@
+ # Server
++Now with logging.
+`
+
+func TestExtractDiffText(t *testing.T) {
+	text := extractDiffText([]byte(sampleDiff), "fix.patch", slog.Default())
+	if !strings.Contains(text, "2 file(s) changed, +2/-1 lines") {
+		t.Errorf("bad summary:\n%s", text)
+	}
+	if !strings.Contains(text, "### pkg/server.go (+1/-1, 2 hunk(s))") {
+		t.Errorf("missing per-file section:\n%s", text)
+	}
+	if !strings.Contains(text, "Hunk 1: @@ -10,6 +10,7 @@ func Start() { (+1/-0)") {
+		t.Errorf("missing hunk header:\n%s", text)
+	}
+	if !strings.Contains(text, "+	logStartup()") {
+		t.Errorf("missing hunk body:\n%s", text)
+	}
+}
+
+func TestExtractDocumentTextRoutesDiffs(t *testing.T) {
+	text := extractDocumentText([]byte(sampleDiff), "text/plain", "fix.patch", slog.Default())
+	if !strings.Contains(text, "file(s) changed") {
+		t.Errorf("diff routing failed:\n%s", text)
+	}
+}
@@ -0,0 +1,292 @@
+// Package copilot – code_index.go implements a persistent per-project code
+// index stored in the central SQLite database (devclaw.db). Symbol definitions
+// and references are extracted once per build and queried via the
+// code_index_build, code_symbols, code_references, and code_outline tools,
+// letting the agent navigate large repos without grepping whole trees into
+// the context window.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// indexableExts lists file extensions the symbol extractor understands.
+var indexableExts = map[string]bool{
+	".go": true, ".ts": true, ".tsx": true, ".js": true, ".jsx": true, ".py": true,
+}
+
+// maxIndexFileSize skips generated or vendored monsters.
+const maxIndexFileSize = 1 << 20 // 1 MB
+
+// CodeIndex is a persistent symbol/reference index for registered projects.
+type CodeIndex struct {
+	db     *sql.DB
+	logger *slog.Logger
+	mu     sync.Mutex // serializes index builds
+}
+
+// codeIndexSymbol is one indexed definition or reference row.
+type codeIndexSymbol struct {
+	File string `json:"file"`
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+	Line int    `json:"line"`
+}
+
+// NewCodeIndex creates the code index tables on the shared devclaw.db
+// connection. The caller retains ownership of the database handle.
+func NewCodeIndex(db *sql.DB, logger *slog.Logger) (*CodeIndex, error) {
+	schema := `
+	CREATE TABLE IF NOT EXISTS code_symbols (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		project_id TEXT NOT NULL,
+		file       TEXT NOT NULL,
+		name       TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		line       INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_code_symbols_lookup ON code_symbols(project_id, name);
+	CREATE INDEX IF NOT EXISTS idx_code_symbols_file   ON code_symbols(project_id, file, line);
+
+	CREATE TABLE IF NOT EXISTS code_references (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		project_id TEXT NOT NULL,
+		file       TEXT NOT NULL,
+		name       TEXT NOT NULL,
+		line       INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_code_references_lookup ON code_references(project_id, name);
+
+	CREATE TABLE IF NOT EXISTS code_index_meta (
+		project_id TEXT PRIMARY KEY,
+		indexed_at TIMESTAMP NOT NULL,
+		files      INTEGER NOT NULL,
+		symbols    INTEGER NOT NULL
+	);`
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("creating code index schema: %w", err)
+	}
+
+	return &CodeIndex{
+		db:     db,
+		logger: logger.With("component", "code-index"),
+	}, nil
+}
+
+// IndexProject (re)builds the index for a project root. Returns counts of
+// indexed files, symbol definitions, and references.
+func (ci *CodeIndex) IndexProject(ctx context.Context, projectID, rootPath string) (files, symbols, refs int, err error) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	start := time.Now()
+
+	// First pass: collect definitions per file.
+	type fileSymbols struct {
+		relPath string
+		content string
+		syms    []symbolInfo
+	}
+	var indexed []fileSymbols
+	names := make(map[string]bool)
+
+	walkErr := filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries.
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if path != rootPath && shouldSkip(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if !indexableExts[ext] {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxIndexFileSize {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			rel = path
+		}
+
+		syms := extractSymbols(string(content), ext)
+		indexed = append(indexed, fileSymbols{relPath: rel, content: string(content), syms: syms})
+		for _, s := range syms {
+			names[s.Name] = true
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return 0, 0, 0, fmt.Errorf("walking project: %w", walkErr)
+	}
+
+	tx, err := ci.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	for _, table := range []string{"code_symbols", "code_references"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE project_id = ?", projectID); err != nil {
+			return 0, 0, 0, fmt.Errorf("clearing %s: %w", table, err)
+		}
+	}
+
+	symStmt, err := tx.PrepareContext(ctx, "INSERT INTO code_symbols (project_id, file, name, kind, line) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("prepare symbols insert: %w", err)
+	}
+	defer symStmt.Close()
+
+	refStmt, err := tx.PrepareContext(ctx, "INSERT INTO code_references (project_id, file, name, line) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("prepare references insert: %w", err)
+	}
+	defer refStmt.Close()
+
+	for _, f := range indexed {
+		// Definition lines are excluded from the reference table below.
+		defLines := make(map[int]bool, len(f.syms))
+		for _, s := range f.syms {
+			if _, err := symStmt.ExecContext(ctx, projectID, f.relPath, s.Name, s.Kind, s.Line); err != nil {
+				return 0, 0, 0, fmt.Errorf("inserting symbol: %w", err)
+			}
+			defLines[s.Line] = true
+			symbols++
+		}
+
+		// Second pass: record lines mentioning any known symbol name.
+		for i, line := range strings.Split(f.content, "\n") {
+			lineNo := i + 1
+			if defLines[lineNo] {
+				continue
+			}
+			for _, token := range identifierTokens(line) {
+				if names[token] {
+					if _, err := refStmt.ExecContext(ctx, projectID, f.relPath, token, lineNo); err != nil {
+						return 0, 0, 0, fmt.Errorf("inserting reference: %w", err)
+					}
+					refs++
+				}
+			}
+		}
+		files++
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT OR REPLACE INTO code_index_meta (project_id, indexed_at, files, symbols) VALUES (?, ?, ?, ?)",
+		projectID, time.Now().UTC(), files, symbols); err != nil {
+		return 0, 0, 0, fmt.Errorf("updating index meta: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, 0, fmt.Errorf("commit: %w", err)
+	}
+
+	ci.logger.Info("project indexed",
+		"project", projectID,
+		"files", files,
+		"symbols", symbols,
+		"references", refs,
+		"elapsed_ms", time.Since(start).Milliseconds(),
+	)
+	return files, symbols, refs, nil
+}
+
+// IsIndexed reports whether a project has been indexed.
+func (ci *CodeIndex) IsIndexed(projectID string) bool {
+	var n int
+	err := ci.db.QueryRow("SELECT COUNT(*) FROM code_index_meta WHERE project_id = ?", projectID).Scan(&n)
+	return err == nil && n > 0
+}
+
+// Symbols searches definitions by (partial) name, optionally filtered by kind.
+func (ci *CodeIndex) Symbols(projectID, query, kind string, max int) ([]codeIndexSymbol, error) {
+	q := "SELECT file, name, kind, line FROM code_symbols WHERE project_id = ? AND name LIKE ?"
+	args := []any{projectID, "%" + query + "%"}
+	if kind != "" {
+		q += " AND kind = ?"
+		args = append(args, kind)
+	}
+	q += " ORDER BY name, file, line LIMIT ?"
+	args = append(args, max)
+
+	return ci.querySymbols(q, args...)
+}
+
+// References returns lines that mention a symbol, excluding its definitions.
+func (ci *CodeIndex) References(projectID, name string, max int) ([]codeIndexSymbol, error) {
+	return ci.querySymbols(
+		"SELECT file, name, '' AS kind, line FROM code_references WHERE project_id = ? AND name = ? ORDER BY file, line LIMIT ?",
+		projectID, name, max)
+}
+
+// Outline returns all definitions in one file ordered by line.
+func (ci *CodeIndex) Outline(projectID, file string) ([]codeIndexSymbol, error) {
+	return ci.querySymbols(
+		"SELECT file, name, kind, line FROM code_symbols WHERE project_id = ? AND file = ? ORDER BY line",
+		projectID, file)
+}
+
+// querySymbols runs a symbol-shaped query and scans the rows.
+func (ci *CodeIndex) querySymbols(query string, args ...any) ([]codeIndexSymbol, error) {
+	rows, err := ci.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying code index: %w", err)
+	}
+	defer rows.Close()
+
+	var out []codeIndexSymbol
+	for rows.Next() {
+		var s codeIndexSymbol
+		if err := rows.Scan(&s.File, &s.Name, &s.Kind, &s.Line); err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// identifierTokens splits a source line into identifier-like tokens.
+func identifierTokens(line string) []string {
+	var tokens []string
+	start := -1
+	for i, r := range line {
+		isIdent := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if isIdent {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, line[start:i])
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, line[start:])
+	}
+	return tokens
+}
@@ -0,0 +1,137 @@
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newTestCodeIndex creates an index over an in-memory database and a temp
+// project with one Go file.
+func newTestCodeIndex(t *testing.T) (*CodeIndex, string) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	idx, err := NewCodeIndex(db, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("NewCodeIndex: %v", err)
+	}
+
+	root := t.TempDir()
+	src := `package demo
+
+type Widget struct{}
+
+func NewWidget() *Widget {
+	return &Widget{}
+}
+
+func useIt() {
+	w := NewWidget()
+	_ = w
+}
+`
+	if err := os.WriteFile(filepath.Join(root, "widget.go"), []byte(src), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	return idx, root
+}
+
+func TestCodeIndexBuildAndQuery(t *testing.T) {
+	idx, root := newTestCodeIndex(t)
+
+	files, symbols, refs, err := idx.IndexProject(context.Background(), "demo", root)
+	if err != nil {
+		t.Fatalf("IndexProject: %v", err)
+	}
+	if files != 1 {
+		t.Errorf("expected 1 file, got %d", files)
+	}
+	if symbols < 3 {
+		t.Errorf("expected at least 3 symbols (Widget, NewWidget, useIt), got %d", symbols)
+	}
+	if refs == 0 {
+		t.Error("expected references to be recorded")
+	}
+	if !idx.IsIndexed("demo") {
+		t.Error("expected project to be marked as indexed")
+	}
+
+	// Symbol search by partial name.
+	found, err := idx.Symbols("demo", "Widget", "", 10)
+	if err != nil {
+		t.Fatalf("Symbols: %v", err)
+	}
+	names := map[string]bool{}
+	for _, s := range found {
+		names[s.Name] = true
+	}
+	if !names["Widget"] || !names["NewWidget"] {
+		t.Errorf("expected Widget and NewWidget, got %v", found)
+	}
+
+	// References exclude the definition line.
+	wrefs, err := idx.References("demo", "NewWidget", 10)
+	if err != nil {
+		t.Fatalf("References: %v", err)
+	}
+	if len(wrefs) != 1 {
+		t.Fatalf("expected 1 reference to NewWidget, got %v", wrefs)
+	}
+	if wrefs[0].Line != 10 {
+		t.Errorf("expected reference on line 10, got %d", wrefs[0].Line)
+	}
+
+	// Outline is ordered by line.
+	outline, err := idx.Outline("demo", "widget.go")
+	if err != nil {
+		t.Fatalf("Outline: %v", err)
+	}
+	if len(outline) < 3 {
+		t.Fatalf("expected full outline, got %v", outline)
+	}
+	for i := 1; i < len(outline); i++ {
+		if outline[i].Line < outline[i-1].Line {
+			t.Errorf("outline not ordered by line: %v", outline)
+		}
+	}
+}
+
+func TestCodeIndexRebuildReplacesRows(t *testing.T) {
+	idx, root := newTestCodeIndex(t)
+
+	if _, _, _, err := idx.IndexProject(context.Background(), "demo", root); err != nil {
+		t.Fatalf("first build: %v", err)
+	}
+	first, _ := idx.Symbols("demo", "", "", 100)
+
+	if _, _, _, err := idx.IndexProject(context.Background(), "demo", root); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	second, _ := idx.Symbols("demo", "", "", 100)
+
+	if len(first) != len(second) {
+		t.Errorf("rebuild duplicated rows: %d vs %d", len(first), len(second))
+	}
+}
+
+func TestIdentifierTokens(t *testing.T) {
+	tokens := identifierTokens("w := NewWidget() // make_one")
+	want := map[string]bool{"w": true, "NewWidget": true, "make_one": true}
+	for _, tok := range tokens {
+		delete(want, tok)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing tokens: %v (got %v)", want, tokens)
+	}
+}
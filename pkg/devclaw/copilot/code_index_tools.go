@@ -0,0 +1,215 @@
+// Package copilot – code_index_tools.go registers the agent tools backed by
+// the persistent CodeIndex: code_index_build, code_symbols, code_references,
+// and code_outline. code_symbols replaces the grep-style per-file extractor
+// from codebase_tools.go with project-wide indexed search (the old per-file
+// behavior is kept via the optional "file" parameter).
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RegisterCodeIndexTools registers the code index tools. Must be called after
+// RegisterCodebaseTools so the indexed code_symbols takes precedence.
+func RegisterCodeIndexTools(executor *ToolExecutor, pm *ProjectManager, idx *CodeIndex) {
+	// code_index_build
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "code_index_build",
+			Description: "Build or refresh the persistent code index for a registered project. Required before code_symbols, code_references, and code_outline can answer project-wide queries.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"project_id": map[string]any{"type": "string", "description": "Registered project ID (default: project containing the current directory)"},
+				},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		project, err := resolveIndexProject(pm, args)
+		if err != nil {
+			return nil, err
+		}
+		files, symbols, refs, err := idx.IndexProject(ctx, project.ID, project.RootPath)
+		if err != nil {
+			return nil, fmt.Errorf("indexing project: %w", err)
+		}
+		return fmt.Sprintf("Indexed project %q: %d files, %d symbols, %d references.",
+			project.ID, files, symbols, refs), nil
+	})
+
+	// code_symbols (overrides the per-file variant from codebase_tools.go)
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "code_symbols",
+			Description: "Search symbol definitions (functions, types, classes) across an indexed project by name. Pass 'file' instead of 'query' to extract symbols from a single file without the index.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query":      map[string]any{"type": "string", "description": "Partial symbol name to search for"},
+					"project_id": map[string]any{"type": "string", "description": "Registered project ID (default: project containing the current directory)"},
+					"kind":       map[string]any{"type": "string", "description": "Filter by kind: function, type, class"},
+					"max":        map[string]any{"type": "integer", "description": "Maximum results (default: 50)"},
+					"file":       map[string]any{"type": "string", "description": "Extract symbols from this single file instead of searching the index"},
+				},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		// Per-file mode: no index needed.
+		if file, ok := args["file"].(string); ok && file != "" {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("reading file: %w", err)
+			}
+			symbols := extractSymbols(string(content), filepath.Ext(file))
+			if len(symbols) == 0 {
+				return "No symbols found.", nil
+			}
+			data, _ := json.MarshalIndent(symbols, "", "  ")
+			return string(data), nil
+		}
+
+		query, _ := args["query"].(string)
+		if query == "" {
+			return nil, fmt.Errorf("provide a 'query' (or 'file' for single-file extraction)")
+		}
+		project, err := resolveIndexProject(pm, args)
+		if err != nil {
+			return nil, err
+		}
+		if err := requireIndexed(idx, project); err != nil {
+			return nil, err
+		}
+
+		kind, _ := args["kind"].(string)
+		max := 50
+		if v, ok := args["max"].(float64); ok && v > 0 {
+			max = int(v)
+		}
+
+		symbols, err := idx.Symbols(project.ID, query, kind, max)
+		if err != nil {
+			return nil, err
+		}
+		if len(symbols) == 0 {
+			return "No symbols found.", nil
+		}
+		data, _ := json.MarshalIndent(symbols, "", "  ")
+		return string(data), nil
+	})
+
+	// code_references
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "code_references",
+			Description: "Find lines referencing a symbol across an indexed project (definitions excluded). Use after code_symbols to trace usage.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":       map[string]any{"type": "string", "description": "Exact symbol name"},
+					"project_id": map[string]any{"type": "string", "description": "Registered project ID (default: project containing the current directory)"},
+					"max":        map[string]any{"type": "integer", "description": "Maximum results (default: 50)"},
+				},
+				"required": []string{"name"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		name, _ := args["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("provide a symbol 'name'")
+		}
+		project, err := resolveIndexProject(pm, args)
+		if err != nil {
+			return nil, err
+		}
+		if err := requireIndexed(idx, project); err != nil {
+			return nil, err
+		}
+
+		max := 50
+		if v, ok := args["max"].(float64); ok && v > 0 {
+			max = int(v)
+		}
+
+		refs, err := idx.References(project.ID, name, max)
+		if err != nil {
+			return nil, err
+		}
+		if len(refs) == 0 {
+			return "No references found.", nil
+		}
+		data, _ := json.MarshalIndent(refs, "", "  ")
+		return string(data), nil
+	})
+
+	// code_outline
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "code_outline",
+			Description: "List all symbol definitions in one file of an indexed project, ordered by line — a cheap structural overview without reading the file.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"file":       map[string]any{"type": "string", "description": "File path relative to the project root"},
+					"project_id": map[string]any{"type": "string", "description": "Registered project ID (default: project containing the current directory)"},
+				},
+				"required": []string{"file"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		file, _ := args["file"].(string)
+		if file == "" {
+			return nil, fmt.Errorf("provide a 'file'")
+		}
+		project, err := resolveIndexProject(pm, args)
+		if err != nil {
+			return nil, err
+		}
+		if err := requireIndexed(idx, project); err != nil {
+			return nil, err
+		}
+
+		symbols, err := idx.Outline(project.ID, file)
+		if err != nil {
+			return nil, err
+		}
+		if len(symbols) == 0 {
+			return "No symbols found in that file (is the path relative to the project root?).", nil
+		}
+		data, _ := json.MarshalIndent(symbols, "", "  ")
+		return string(data), nil
+	})
+}
+
+// resolveIndexProject picks the target project: explicit project_id arg,
+// falling back to the project containing the current working directory.
+func resolveIndexProject(pm *ProjectManager, args map[string]any) (*Project, error) {
+	if id, ok := args["project_id"].(string); ok && id != "" {
+		project := pm.Get(id)
+		if project == nil {
+			return nil, fmt.Errorf("unknown project %q — register it first", id)
+		}
+		return project, nil
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		if project := pm.FindByPath(cwd); project != nil {
+			return project, nil
+		}
+	}
+	return nil, fmt.Errorf("no project specified and the current directory is not inside a registered project — pass project_id")
+}
+
+// requireIndexed ensures the project has a built index before querying.
+func requireIndexed(idx *CodeIndex, project *Project) error {
+	if !idx.IsIndexed(project.ID) {
+		return fmt.Errorf("project %q is not indexed yet — run code_index_build first", project.ID)
+	}
+	return nil
+}
@@ -18,6 +18,10 @@
 //	/group allow             - Allow current group
 //	/group block             - Block current group
 //	/group assign <ws_id>    - Assign current group to workspace
+//	/worktree start <id>     - Start an isolated task worktree
+//	/worktree status         - Show task worktree status
+//	/worktree discard        - Discard the task worktree
+//	/merge                   - Merge the task branch into its base
 //	/skills list             - List installed skills
 //	/skills defaults         - List available default skills
 //	/skills install <n|all>  - Install default skills
@@ -131,6 +135,12 @@ func (a *Assistant) HandleCommand(msg *channels.IncomingMessage) CommandResult {
 		}
 		return CommandResult{Response: a.usersCommand(), Handled: true}
 
+	case "/requests":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.requestsCommand(args, msg.From), Handled: true}
+
 	case "/ws", "/workspace":
 		if !isAdmin {
 			return CommandResult{Response: "Permission denied.", Handled: true}
@@ -160,12 +170,27 @@ func (a *Assistant) HandleCommand(msg *channels.IncomingMessage) CommandResult {
 		return CommandResult{Response: a.modelCommand(args, msg), Handled: true}
 	case "/compact":
 		return CommandResult{Response: a.compactCommand(msg), Handled: true}
+	case "/summarize":
+		return CommandResult{Response: a.summarizeCommand(args, msg), Handled: true}
+	case "/search":
+		return CommandResult{Response: a.searchCommand(args, msg), Handled: true}
+	case "/sources":
+		return CommandResult{Response: a.sourcesCommand(msg), Handled: true}
+	case "/incident":
+		return CommandResult{Response: a.incidentCommand(args, msg), Handled: true}
+	case "/runbook":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.runbookCommand(args, msg), Handled: true}
 	case "/new":
 		return CommandResult{Response: a.newCommand(msg), Handled: true}
 	case "/reset":
 		return CommandResult{Response: a.resetCommand(msg), Handled: true}
 	case "/think":
 		return CommandResult{Response: a.thinkCommand(args, msg), Handled: true}
+	case "/persona":
+		return CommandResult{Response: a.personaCommand(args, msg), Handled: true}
 
 	case "/tts":
 		return CommandResult{Response: a.ttsCommand(args, msg), Handled: true}
@@ -173,6 +198,8 @@ func (a *Assistant) HandleCommand(msg *channels.IncomingMessage) CommandResult {
 	// Extended directives.
 	case "/verbose":
 		return CommandResult{Response: a.verboseCommand(args, msg), Handled: true}
+	case "/verify":
+		return CommandResult{Response: a.verifyCommand(args, msg), Handled: true}
 	case "/reasoning":
 		return CommandResult{Response: a.thinkCommand(args, msg), Handled: true} // Alias for /think
 	case "/queue":
@@ -217,6 +244,9 @@ func (a *Assistant) HandleCommand(msg *channels.IncomingMessage) CommandResult {
 			return CommandResult{Response: "Usage: /exec <queue|approve|deny> [args]", Handled: true}
 		}
 
+	case "/sudo":
+		return CommandResult{Response: a.sudoCommand(args, msg), Handled: true}
+
 	case "/channels":
 		if !isAdmin {
 			return CommandResult{Response: "Permission denied.", Handled: true}
@@ -247,6 +277,45 @@ func (a *Assistant) HandleCommand(msg *channels.IncomingMessage) CommandResult {
 		}
 		return CommandResult{Response: a.systemCommands.MetricsCommand(args), Handled: true}
 
+	case "/tasks", "/habits":
+		return CommandResult{Response: a.tasksCommand(args), Handled: true}
+
+	case "/jobs":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.jobsCommand(args), Handled: true}
+
+	case "/llm":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.llmCommand(args), Handled: true}
+
+	case "/perf":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.perfCommand(), Handled: true}
+
+	case "/db":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.dbCommand(args), Handled: true}
+
+	case "/storage":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.storageCommand(), Handled: true}
+
+	case "/prompt":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.promptCommand(args, msg), Handled: true}
+
 	// Tool profile commands.
 	case "/profile":
 		return CommandResult{Response: a.profileCommand(args, msg, isAdmin), Handled: true}
@@ -265,6 +334,18 @@ func (a *Assistant) HandleCommand(msg *channels.IncomingMessage) CommandResult {
 		}
 		return CommandResult{Response: a.vaultCommand(args), Handled: true}
 
+	// Task worktree commands (isolated coding edits).
+	case "/worktree", "/wt":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.worktreeCommand(args, msg), Handled: true}
+	case "/merge":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.mergeCommand(msg), Handled: true}
+
 	// Hooks management commands.
 	case "/hooks":
 		if !isAdmin {
@@ -272,6 +353,39 @@ func (a *Assistant) HandleCommand(msg *channels.IncomingMessage) CommandResult {
 		}
 		return CommandResult{Response: a.hooksCommand(args), Handled: true}
 
+	case "/webhooks":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.webhooksCommand(args), Handled: true}
+
+	case "/trace":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.traceCommand(args), Handled: true}
+
+	case "/heartbeat":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.heartbeatCommand(args), Handled: true}
+
+	case "/boot":
+		if !isAdmin {
+			return CommandResult{Response: "Permission denied.", Handled: true}
+		}
+		return CommandResult{Response: a.bootCommand(), Handled: true}
+
+	case "/flags":
+		if senderLevel != AccessOwner {
+			return CommandResult{Response: "Only owners can manage feature flags.", Handled: true}
+		}
+		return CommandResult{Response: a.flagsCommand(args, msg), Handled: true}
+
+	case "/link":
+		return CommandResult{Response: a.linkCommand(args, msg, isAdmin), Handled: true}
+
 	default:
 		return CommandResult{Handled: false}
 	}
@@ -290,7 +404,8 @@ func (a *Assistant) helpCommand(isAdmin bool) string {
 		b.WriteString("/unblock <phone> - Unblock a user\n")
 		b.WriteString("/revoke <phone> - Revoke access\n")
 		b.WriteString("/admin <phone> - Promote to admin\n")
-		b.WriteString("/users - List authorized users\n\n")
+		b.WriteString("/users - List authorized users\n")
+		b.WriteString("/requests [approve|deny <id>] - Review \"ask\" policy access requests\n\n")
 
 		b.WriteString("*Workspaces:*\n")
 		b.WriteString("/ws create <id> <name> - Create workspace\n")
@@ -313,10 +428,30 @@ func (a *Assistant) helpCommand(isAdmin bool) string {
 		b.WriteString("/logs [level] [lines] - View audit logs\n")
 		b.WriteString("/health - Health check\n")
 		b.WriteString("/metrics [period] - Usage metrics\n")
+		b.WriteString("/jobs [failed|retry <id>|history <id>] - Scheduled job status and dead-letter list\n")
+		b.WriteString("/llm status - Provider/model health and cooldowns\n")
+		b.WriteString("/perf - Runtime diagnostics (goroutines, heap, slow tools)\n")
+		b.WriteString("/db maintenance - Vacuum and checkpoint the databases now\n")
+		b.WriteString("/storage - Media storage usage per workspace\n")
+		b.WriteString("/prompt preview - Render instruction templates for this session\n")
 		b.WriteString("/profile [list|set <name>] - View or set tool profile\n")
 		b.WriteString("/pairing generate|list|requests - DM access tokens\n")
+		b.WriteString("/link [code|status|remove] - Link your identity across channels\n")
 		b.WriteString("/vault list|set|get|delete - Manage secrets\n")
-		b.WriteString("/hooks list|enable <name>|disable <name> - Manage hooks\n\n")
+		b.WriteString("/hooks list|enable <name>|disable <name> - Manage hooks\n")
+		b.WriteString("/webhooks [failed|replay <id>] - Inspect and replay webhook deliveries\n")
+		b.WriteString("/trace [run-id] - Inspect a run's timeline (LLM turns, tools, durations)\n")
+		b.WriteString("/heartbeat status - Show recent heartbeat beats and skip reasons\n")
+		b.WriteString("/boot - Show the startup report (subsystem outcomes)\n")
+		b.WriteString("/flags [on|off|reset <flag> [ws|*]] - Feature flags per workspace (owner)\n")
+		b.WriteString("/runbook start <path>|continue|status|abort - Step-by-step runbook execution\n")
+		b.WriteString("/sudo [30m|off] - Time-boxed elevation, skips tool confirmations (owner)\n\n")
+
+		b.WriteString("*Task Worktrees:*\n")
+		b.WriteString("/worktree start <project_id> [task] - Isolate edits in a git worktree\n")
+		b.WriteString("/worktree status - Show task worktree status\n")
+		b.WriteString("/worktree discard - Throw away the task worktree\n")
+		b.WriteString("/merge - Merge the task branch into its base\n\n")
 
 		b.WriteString("/status - Bot status (legacy)\n")
 	}
@@ -334,15 +469,22 @@ func (a *Assistant) helpCommand(isAdmin bool) string {
 	b.WriteString("/stop - Stop active agent run\n")
 	b.WriteString("/model [name] - Show or change model\n")
 	b.WriteString("/compact - Compact session history\n")
+	b.WriteString("/summarize [today|last N] [--tasks] - Recap recent discussion\n")
+	b.WriteString("/search group <query> - Search this group's message archive\n")
+	b.WriteString("/sources - List sources behind the last answer\n")
+	b.WriteString("/incident open <title> - Open an incident and page the escalation chain\n")
 	b.WriteString("/new - Start new session (keep facts & config)\n")
 	b.WriteString("/reset - Full session reset\n")
 	b.WriteString("/usage [reset] - Show token usage\n")
 	b.WriteString("/think [off|low|medium|high] - Set thinking level\n")
+	b.WriteString("/persona [name|off] - Switch the assistant voice\n")
 	b.WriteString("/tts [off|always|inbound] - Toggle text-to-speech\n")
 	b.WriteString("/verbose [on|off] - Toggle verbose tool narration\n")
+	b.WriteString("/verify [on|off] - Toggle fact-check pass against sources\n")
 	b.WriteString("/reasoning [off|low|medium|high] - Set reasoning level (alias: /think)\n")
-	b.WriteString("/queue [collect|steer|followup|interrupt] - Set queue mode\n")
+	b.WriteString("/queue [collect|steer|followup|interrupt] - Set queue mode for this chat\n")
 	b.WriteString("/usage [reset|global] - Show token usage\n")
+	b.WriteString("/tasks [done <habit>|skip <habit>|snooze <habit> [1d]|report] - Habit tracker\n")
 
 	if isAdmin {
 		b.WriteString("/activation [always|mention] - Set group activation mode\n")
@@ -400,6 +542,241 @@ func (a *Assistant) usageCommand(args []string, msg *channels.IncomingMessage) s
 	return b.String()
 }
 
+// llmCommand shows LLM provider/model health: request and error counts,
+// median/p95 latency over the health window, and active cooldowns.
+func (a *Assistant) llmCommand(args []string) string {
+	if len(args) == 0 || strings.ToLower(args[0]) != "status" {
+		return "Usage: /llm status"
+	}
+	if a.llmClient == nil {
+		return "LLM client not available."
+	}
+
+	stats := a.llmClient.HealthStats()
+	if len(stats) == 0 {
+		return "*LLM Status*\n\nNo requests recorded yet."
+	}
+
+	cooldownModel, cooldownUntil := a.llmClient.CooldownStatus()
+
+	table := NewTableResult("LLM provider health", []string{"Model", "Requests", "Errors", "Err %", "Median", "P95", "Cooldown"})
+	for _, s := range stats {
+		cooldown := "-"
+		if s.Model == cooldownModel {
+			cooldown = "until " + cooldownUntil.Format("15:04:05")
+		}
+		table.AddRow(
+			s.Model,
+			fmt.Sprintf("%d", s.Requests),
+			fmt.Sprintf("%d", s.Errors),
+			fmt.Sprintf("%.0f%%", s.ErrorRate*100),
+			formatLatency(s.MedianLatency),
+			formatLatency(s.P95Latency),
+			cooldown,
+		)
+	}
+
+	var b strings.Builder
+	b.WriteString(table.RenderMarkdown())
+	for _, s := range stats {
+		if s.LastError != "" {
+			b.WriteString(fmt.Sprintf("\nLast error (%s, %s): %s",
+				s.Model, s.LastErrorAt.Format("15:04:05"), s.LastError))
+		}
+	}
+	return b.String()
+}
+
+// jobsCommand surfaces scheduled job status: the full job list, the
+// dead-letter list of jobs whose last run failed (/jobs failed), per-job
+// execution history, and a one-tap re-run (/jobs retry <id>).
+func (a *Assistant) jobsCommand(args []string) string {
+	if a.scheduler == nil {
+		return "Scheduler not available."
+	}
+
+	sub := ""
+	if len(args) > 0 {
+		sub = strings.ToLower(args[0])
+	}
+
+	switch sub {
+	case "", "list":
+		jobs := a.scheduler.List()
+		if len(jobs) == 0 {
+			return "*Scheduled Jobs*\n\nNo jobs configured."
+		}
+		table := NewTableResult("Scheduled jobs", []string{"ID", "Schedule", "Enabled", "Runs", "Last run", "Status"})
+		for _, j := range jobs {
+			lastRun := "-"
+			if j.LastRunAt != nil {
+				lastRun = j.LastRunAt.Format("02/01 15:04")
+			}
+			status := "ok"
+			if j.LastError != "" {
+				status = fmt.Sprintf("failing (%d)", j.ConsecutiveFailures)
+			}
+			table.AddRow(j.ID, j.Schedule, fmt.Sprintf("%t", j.Enabled), fmt.Sprintf("%d", j.RunCount), lastRun, status)
+		}
+		return table.RenderMarkdown()
+
+	case "failed":
+		failed := a.scheduler.FailedJobs()
+		if len(failed) == 0 {
+			return "*Dead-letter*\n\nNo failing jobs. 🎉"
+		}
+		var b strings.Builder
+		b.WriteString("*Dead-letter* (jobs whose last run failed)\n\n")
+		for _, j := range failed {
+			b.WriteString(fmt.Sprintf("• *%s* — %d consecutive failure(s)\n", j.ID, j.ConsecutiveFailures))
+			b.WriteString(fmt.Sprintf("  Last error: %s\n", truncate(j.LastError, 200)))
+		}
+		b.WriteString("\nRe-run with /jobs retry <id>")
+		return b.String()
+
+	case "retry":
+		if len(args) < 2 {
+			return "Usage: /jobs retry <id>"
+		}
+		if err := a.scheduler.RunNow(args[1]); err != nil {
+			return fmt.Sprintf("Retry failed: %v", err)
+		}
+		return fmt.Sprintf("🔁 Job %s queued for immediate execution.", args[1])
+
+	case "history":
+		if len(args) < 2 {
+			return "Usage: /jobs history <id>"
+		}
+		history := a.scheduler.RunHistory()
+		if history == nil {
+			return "Run history not available (no database configured)."
+		}
+		runs, err := history.RecentRuns(args[1], 10)
+		if err != nil {
+			return fmt.Sprintf("Failed to load history: %v", err)
+		}
+		if len(runs) == 0 {
+			return fmt.Sprintf("No recorded runs for job %s.", args[1])
+		}
+		table := NewTableResult(fmt.Sprintf("Recent runs: %s", args[1]), []string{"When", "Status", "Attempt", "Duration", "Error"})
+		for _, r := range runs {
+			errCol := "-"
+			if r.Error != "" {
+				errCol = truncate(r.Error, 80)
+			}
+			table.AddRow(r.StartedAt.Format("02/01 15:04:05"), r.Status, fmt.Sprintf("%d", r.Attempt), formatLatency(r.Duration), errCol)
+		}
+		return table.RenderMarkdown()
+
+	default:
+		return "Usage: /jobs [list|failed|retry <id>|history <id>]"
+	}
+}
+
+// tasksCommand manages the habit tracker: list habits, record check-ins
+// (/tasks done workout), snooze prompts, and show the weekly report.
+func (a *Assistant) tasksCommand(args []string) string {
+	if a.skillDB == nil {
+		return "Habit tracker not available (skill database disabled)."
+	}
+
+	sub := ""
+	if len(args) > 0 {
+		sub = strings.ToLower(args[0])
+	}
+
+	switch sub {
+	case "", "list":
+		habits, err := a.skillDB.ListHabits()
+		if err != nil {
+			return fmt.Sprintf("Failed to list habits: %v", err)
+		}
+		if len(habits) == 0 {
+			return "*Habits*\n\nNothing tracked yet. Ask me to track a habit, e.g. \"remind me to work out every morning\"."
+		}
+		return habitTable(habits).RenderMarkdown()
+
+	case "done", "skip":
+		if len(args) < 2 {
+			return fmt.Sprintf("Usage: /tasks %s <habit>", sub)
+		}
+		status := "done"
+		if sub == "skip" {
+			status = "skipped"
+		}
+		h, err := a.skillDB.LogHabitCheckIn(args[1], status, "", "")
+		if err != nil {
+			return fmt.Sprintf("Failed: %v", err)
+		}
+		if status == "done" {
+			return fmt.Sprintf("✅ %s done. Streak: %d day(s) (best: %d).", h.Name, h.Streak, h.BestStreak)
+		}
+		return fmt.Sprintf("⏭️ %s skipped. Streak reset (best: %d).", h.Name, h.BestStreak)
+
+	case "snooze":
+		if len(args) < 2 {
+			return "Usage: /tasks snooze <habit> [2h|1d|YYYY-MM-DD]"
+		}
+		untilArg := ""
+		if len(args) > 2 {
+			untilArg = args[2]
+		}
+		until, err := parseSnoozeUntil(untilArg)
+		if err != nil {
+			return fmt.Sprintf("Failed: %v", err)
+		}
+		if err := a.skillDB.SnoozeHabit(args[1], until); err != nil {
+			return fmt.Sprintf("Failed: %v", err)
+		}
+		return fmt.Sprintf("😴 %s snoozed until %s.", args[1], until.Format("02/01 15:04"))
+
+	case "report":
+		progress, err := a.skillDB.WeeklyHabitProgress()
+		if err != nil {
+			return fmt.Sprintf("Failed to build report: %v", err)
+		}
+		if len(progress) == 0 {
+			return "*Habits*\n\nNothing tracked yet."
+		}
+		table := NewTableResult("Habit progress (last 7 days)", []string{"Habit", "Done", "Skipped", "Streak", "Best"})
+		for _, p := range progress {
+			table.AddRow(p.Name, fmt.Sprintf("%d", p.Done), fmt.Sprintf("%d", p.Skipped),
+				fmt.Sprintf("%d", p.Streak), fmt.Sprintf("%d", p.BestStreak))
+		}
+		return table.RenderMarkdown()
+
+	default:
+		return "Usage: /tasks [list|done <habit>|skip <habit>|snooze <habit> [1d]|report]"
+	}
+}
+
+// promptCommand previews the custom instructions rendered with this session's
+// runtime template variables (user name, workspace, date, channel, language,
+// active project).
+func (a *Assistant) promptCommand(args []string, msg *channels.IncomingMessage) string {
+	if len(args) == 0 || strings.ToLower(args[0]) != "preview" {
+		return "Usage: /prompt preview"
+	}
+	if a.config.Instructions == "" {
+		return "No instructions configured."
+	}
+
+	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	vars := a.promptComposer.templateVars(resolved.Session)
+
+	rendered, err := renderPromptTemplate(a.config.Instructions, a.config.PromptPartials, vars)
+	if err != nil {
+		return fmt.Sprintf("Template error: %v", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("*Prompt Preview*\n\n")
+	b.WriteString(fmt.Sprintf("Variables: user=%q workspace=%q date=%s channel=%s lang=%s project=%q\n\n",
+		vars.UserName, vars.Workspace, vars.Date, vars.Channel, vars.Language, vars.Project))
+	b.WriteString(rendered)
+	return b.String()
+}
+
 func (a *Assistant) approveCommand(args []string, msg *channels.IncomingMessage) string {
 	sessionID := MakeSessionID(msg.Channel, msg.ChatID)
 
@@ -409,6 +786,11 @@ func (a *Assistant) approveCommand(args []string, msg *channels.IncomingMessage)
 		targetID = args[0]
 	} else {
 		targetID = a.approvalMgr.LatestPendingForSession(sessionID)
+		if targetID == "" {
+			// The sender may be a designated approver responding from
+			// their own DM to a quorum request raised elsewhere.
+			targetID = a.approvalMgr.LatestPendingForApprover(msg.From)
+		}
 		if targetID == "" {
 			return "No pending approvals."
 		}
@@ -433,6 +815,9 @@ func (a *Assistant) denyCommand(args []string, msg *channels.IncomingMessage) st
 		}
 	} else {
 		targetID = a.approvalMgr.LatestPendingForSession(sessionID)
+		if targetID == "" {
+			targetID = a.approvalMgr.LatestPendingForApprover(msg.From)
+		}
 		if targetID == "" {
 			return "No pending approvals."
 		}
@@ -444,6 +829,59 @@ func (a *Assistant) denyCommand(args []string, msg *channels.IncomingMessage) st
 	return "Approval not found or already resolved."
 }
 
+// sudoCommand handles /sudo — time-boxed session elevation for the owner.
+// Usage: /sudo [duration] to elevate (default 30m), /sudo off to revoke,
+// /sudo status to check remaining time.
+func (a *Assistant) sudoCommand(args []string, msg *channels.IncomingMessage) string {
+	if a.accessMgr == nil || !a.accessMgr.IsOwner(msg.From) {
+		return "Permission denied. Sudo mode is owner-only."
+	}
+
+	sessionID := MakeSessionID(msg.Channel, msg.ChatID)
+
+	auditLog := func(action string) {
+		if guard := a.toolExecutor.Guard(); guard != nil {
+			if audit := guard.SQLiteAudit(); audit != nil {
+				audit.Log("sudo", msg.From, "owner", true, action, "")
+			}
+		}
+	}
+
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "off", "exit":
+			if a.sudoMgr.Revoke(sessionID) {
+				auditLog("sudo mode revoked")
+				return "Sudo mode ended."
+			}
+			return "Sudo mode is not active."
+		case "status":
+			if left := a.sudoMgr.Remaining(sessionID); left > 0 {
+				return fmt.Sprintf("⚡ Sudo mode active, %s remaining.", formatSudoRemaining(left))
+			}
+			return "Sudo mode is not active."
+		}
+	}
+
+	duration := sudoDefaultDuration
+	if len(args) > 0 {
+		d, err := time.ParseDuration(args[0])
+		if err != nil || d <= 0 {
+			return "Usage: /sudo [duration|off|status] — e.g. /sudo 30m"
+		}
+		duration = d
+	}
+	if duration > sudoMaxDuration {
+		duration = sudoMaxDuration
+	}
+
+	expiry := a.sudoMgr.Elevate(sessionID, msg.From, duration)
+	auditLog(fmt.Sprintf("sudo mode granted for %s", formatSudoRemaining(time.Until(expiry))))
+
+	return fmt.Sprintf("⚡ Sudo mode active for %s — tool confirmations are skipped and everything is audit-logged. Use /sudo off to end early.",
+		formatSudoRemaining(time.Until(expiry)))
+}
+
 func (a *Assistant) stopCommand(msg *channels.IncomingMessage) string {
 	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
 	if a.StopActiveRun(resolved.Workspace.ID, resolved.Session.ID) {
@@ -530,6 +968,11 @@ func (a *Assistant) resetCommand(msg *channels.IncomingMessage) string {
 	sessionID := MakeSessionID(msg.Channel, msg.ChatID)
 	a.approvalMgr.ClearSessionTrust(sessionID)
 
+	// Drop the session's sandbox scratch dir along with everything else.
+	if a.sandboxFS != nil && a.sandboxFS.Enabled() {
+		a.sandboxFS.Remove(sessionID)
+	}
+
 	return "Session reset completely."
 }
 
@@ -554,6 +997,42 @@ func (a *Assistant) thinkCommand(args []string, msg *channels.IncomingMessage) s
 	return fmt.Sprintf("Thinking level: %s", level)
 }
 
+// personaCommand shows or switches the session's voice persona.
+func (a *Assistant) personaCommand(args []string, msg *channels.IncomingMessage) string {
+	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	session := resolved.Session
+
+	if len(args) == 0 {
+		current := session.GetConfig().Persona
+		if current == "" {
+			current = a.config.Personas.Default
+		}
+		if current == "" {
+			current = "(none)"
+		}
+		return fmt.Sprintf("Persona: %s\nAvailable: %s\nUse /persona <name> to switch, /persona off to clear.",
+			current, strings.Join(ListPersonas(a.config.Personas.Custom), ", "))
+	}
+
+	name := strings.ToLower(strings.TrimSpace(args[0]))
+	if name == "off" || name == "default" {
+		cfg := session.GetConfig()
+		cfg.Persona = ""
+		session.SetConfig(cfg)
+		return "Persona cleared."
+	}
+
+	if GetPersona(name, a.config.Personas.Custom) == nil {
+		return fmt.Sprintf("Unknown persona %q. Available: %s",
+			name, strings.Join(ListPersonas(a.config.Personas.Custom), ", "))
+	}
+
+	cfg := session.GetConfig()
+	cfg.Persona = name
+	session.SetConfig(cfg)
+	return fmt.Sprintf("Persona: %s", name)
+}
+
 func (a *Assistant) ttsCommand(args []string, msg *channels.IncomingMessage) string {
 	if len(args) == 0 {
 		mode := a.config.TTS.AutoMode
@@ -586,6 +1065,57 @@ func (a *Assistant) ttsCommand(args []string, msg *channels.IncomingMessage) str
 	return fmt.Sprintf("TTS mode set to: %s", mode)
 }
 
+// storageCommand reports media storage usage per workspace, including quotas
+// and the active lifecycle rules.
+func (a *Assistant) storageCommand() string {
+	if a.mediaSvc == nil {
+		return "Native media service not enabled."
+	}
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	usage, err := a.mediaSvc.UsageByWorkspace(ctx)
+	if err != nil {
+		return fmt.Sprintf("Error reading media storage: %v", err)
+	}
+	if len(usage) == 0 {
+		return "*Media Storage*\n\nNo stored media."
+	}
+
+	table := NewTableResult("Media storage by workspace", []string{"Workspace", "Files", "Size", "Pinned", "Quota"})
+	var totalFiles int
+	var totalBytes int64
+	for _, u := range usage {
+		quota := "unlimited"
+		if u.QuotaMB > 0 {
+			quota = fmt.Sprintf("%d MB", u.QuotaMB)
+		}
+		table.AddRow(u.Workspace,
+			fmt.Sprintf("%d", u.Files),
+			fmt.Sprintf("%.1f MB", float64(u.Bytes)/1024/1024),
+			fmt.Sprintf("%d", u.Pinned),
+			quota,
+		)
+		totalFiles += u.Files
+		totalBytes += u.Bytes
+	}
+
+	var b strings.Builder
+	b.WriteString(table.RenderMarkdown())
+	b.WriteString(fmt.Sprintf("\nTotal: %d file(s), %.1f MB.", totalFiles, float64(totalBytes)/1024/1024))
+
+	lifecycle := a.config.NativeMedia.Service.Lifecycle
+	if lifecycle.Enabled {
+		b.WriteString(fmt.Sprintf("\nLifecycle: received kept %dd, generated kept %dd, pinned kept forever.",
+			lifecycle.ReceivedMaxAgeDays, lifecycle.GeneratedMaxAgeDays))
+	} else {
+		b.WriteString("\nLifecycle rules disabled (native_media.service.lifecycle).")
+	}
+	return b.String()
+}
+
 func (a *Assistant) statusCommand() string {
 	health := a.channelMgr.HealthAll()
 	workspaces := a.workspaceMgr.Count()
@@ -676,6 +1206,114 @@ func (a *Assistant) usersCommand() string {
 	return b.String()
 }
 
+// requestsCommand handles /requests: the review queue for unknown contacts
+// under the "ask" access policy.
+func (a *Assistant) requestsCommand(args []string, reviewedBy string) string {
+	if a.accessRequests == nil {
+		return "Access request queue not available (no database)."
+	}
+
+	if len(args) == 0 || strings.EqualFold(args[0], "list") {
+		return a.requestsListCommand()
+	}
+
+	sub := strings.ToLower(args[0])
+	subArgs := args[1:]
+
+	switch sub {
+	case "approve":
+		return a.requestsApproveCommand(subArgs, reviewedBy)
+
+	case "deny":
+		return a.requestsDenyCommand(subArgs, reviewedBy)
+
+	default:
+		return "Usage: /requests [list] | /requests approve <id> | /requests deny <id> [reason]"
+	}
+}
+
+func (a *Assistant) requestsListCommand() string {
+	requests, err := a.accessRequests.ListPending()
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+
+	if len(requests) == 0 {
+		return "No pending access requests."
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*Pending Access Requests (%d):*\n\n", len(requests)))
+
+	for _, r := range requests {
+		b.WriteString(fmt.Sprintf("• ID: `%s`\n", r.ID[:8]))
+		b.WriteString(fmt.Sprintf("  User: %s", r.UserJID))
+		if r.UserName != "" {
+			b.WriteString(fmt.Sprintf(" (%s)", r.UserName))
+		}
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  Channel: %s | Asked: %s\n", r.Channel, r.CreatedAt.Format("2006-01-02 15:04")))
+		if r.FirstMessage != "" {
+			b.WriteString(fmt.Sprintf("  First message: %q\n", r.FirstMessage))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("Use /requests approve <id> or /requests deny <id> to respond.")
+	return b.String()
+}
+
+func (a *Assistant) requestsApproveCommand(args []string, approvedBy string) string {
+	if len(args) < 1 {
+		return "Usage: /requests approve <request_id>"
+	}
+
+	r, err := a.accessRequests.Approve(args[0], approvedBy)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+
+	// Kick off the onboarding flow, same as a pairing approval.
+	if a.onboardingMgr != nil {
+		a.onboardingMgr.Begin(r.UserJID)
+	}
+
+	a.notifyAccessRequester(r, "Your access request has been approved. Welcome! Just send me a message to get started.")
+
+	return fmt.Sprintf("Request approved. %s has been granted access and notified.", r.UserJID)
+}
+
+func (a *Assistant) requestsDenyCommand(args []string, deniedBy string) string {
+	if len(args) < 1 {
+		return "Usage: /requests deny <request_id> [reason]"
+	}
+
+	r, err := a.accessRequests.Deny(args[0], deniedBy)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+
+	notice := "Your access request has been declined."
+	if len(args) > 1 {
+		notice += " Reason: " + strings.Join(args[1:], " ")
+	}
+	a.notifyAccessRequester(r, notice)
+
+	return fmt.Sprintf("Request denied. %s has been notified.", r.UserJID)
+}
+
+// notifyAccessRequester sends the review outcome to the requester on the
+// channel they asked from.
+func (a *Assistant) notifyAccessRequester(r *AccessRequest, content string) {
+	if a.channelMgr == nil || r.Channel == "" {
+		return
+	}
+	if err := a.channelMgr.Send(a.ctx, r.Channel, r.UserJID, &channels.OutgoingMessage{Content: content}); err != nil {
+		a.logger.Warn("failed to notify access requester",
+			"user_jid", r.UserJID, "channel", r.Channel, "error", err)
+	}
+}
+
 func (a *Assistant) workspaceCommand(args []string, msg *channels.IncomingMessage) string {
 	if len(args) == 0 {
 		return "Usage: /ws <create|delete|assign|list|info> [args...]"
@@ -915,11 +1553,35 @@ func (a *Assistant) verboseCommand(args []string, msg *channels.IncomingMessage)
 }
 
 func (a *Assistant) queueCommand(args []string, msg *channels.IncomingMessage) string {
+	sessionID := MakeSessionID(msg.Channel, msg.ChatID)
+
 	if len(args) == 0 {
-		a.configMu.RLock()
-		mode := EffectiveQueueMode(a.config.Queue, msg.Channel)
-		a.configMu.RUnlock()
-		return fmt.Sprintf("Queue mode: %s\n\nAvailable: collect, steer, followup, interrupt, steer-backlog", mode)
+		mode := a.effectiveQueueModeFor(msg, sessionID)
+		return fmt.Sprintf("Queue mode: %s\n\nAvailable: collect, steer, followup, interrupt, steer-backlog\nSet for this chat: /queue <mode> | whole channel: /queue channel <mode> | back to inherited: /queue reset", mode)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "reset":
+		a.queueModeOverridesMu.Lock()
+		delete(a.queueModeOverrides, sessionID)
+		a.queueModeOverridesMu.Unlock()
+		return fmt.Sprintf("Queue mode override cleared — this chat inherits: %s", a.effectiveQueueModeFor(msg, sessionID))
+
+	case "channel":
+		if len(args) < 2 {
+			return "Usage: /queue channel <mode>"
+		}
+		mode, ok := ParseQueueMode(args[1])
+		if !ok {
+			return "Unknown queue mode. Available: collect, steer, followup, interrupt, steer-backlog"
+		}
+		a.configMu.Lock()
+		if a.config.Queue.ByChannel == nil {
+			a.config.Queue.ByChannel = make(map[string]QueueMode)
+		}
+		a.config.Queue.ByChannel[msg.Channel] = mode
+		a.configMu.Unlock()
+		return fmt.Sprintf("Queue mode set to: %s (for channel: %s)", mode, msg.Channel)
 	}
 
 	mode, ok := ParseQueueMode(args[0])
@@ -927,15 +1589,12 @@ func (a *Assistant) queueCommand(args []string, msg *channels.IncomingMessage) s
 		return "Unknown queue mode. Available: collect, steer, followup, interrupt, steer-backlog"
 	}
 
-	// Update the per-channel override.
-	a.configMu.Lock()
-	if a.config.Queue.ByChannel == nil {
-		a.config.Queue.ByChannel = make(map[string]QueueMode)
-	}
-	a.config.Queue.ByChannel[msg.Channel] = mode
-	a.configMu.Unlock()
+	// Per-chat override: takes precedence over group policy and channel config.
+	a.queueModeOverridesMu.Lock()
+	a.queueModeOverrides[sessionID] = mode
+	a.queueModeOverridesMu.Unlock()
 
-	return fmt.Sprintf("Queue mode set to: %s (for channel: %s)", mode, msg.Channel)
+	return fmt.Sprintf("Queue mode set to: %s (for this chat — /queue reset to inherit)", mode)
 }
 
 func (a *Assistant) activationCommand(args []string, msg *channels.IncomingMessage) string {
@@ -1138,6 +1797,9 @@ func (a *Assistant) pairingCommand(args []string, msg *channels.IncomingMessage)
 	case "deny":
 		return a.pairingDenyCommand(subArgs, msg.From)
 
+	case "upgrade":
+		return a.pairingUpgradeCommand(subArgs, msg.From)
+
 	default:
 		return a.pairingHelp()
 	}
@@ -1174,6 +1836,9 @@ func (a *Assistant) pairingHelp() string {
 /pairing deny <request_id> [reason]
   Deny a pending request
 
+/pairing upgrade <jid>
+  Lift the trial limits for an onboarded contact
+
 *Examples:*
 /pairing generate 24h 5 user --auto
 /pairing generate 7d unlimited user --ws team-a --note "Team Alpha"
@@ -1443,6 +2108,97 @@ func (a *Assistant) pairingDenyCommand(args []string, deniedBy string) string {
 	return "Request denied."
 }
 
+func (a *Assistant) pairingUpgradeCommand(args []string, upgradedBy string) string {
+	if len(args) < 1 {
+		return "Usage: /pairing upgrade <jid>"
+	}
+	if a.onboardingMgr == nil {
+		return "Onboarding system not available."
+	}
+
+	if err := a.onboardingMgr.EndTrial(args[0], upgradedBy); err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+
+	return "Trial limits lifted. The user now has full tool access for their level."
+}
+
+// --- Worktree Commands ---
+
+// worktreeCommand handles /worktree for isolated coding-task worktrees.
+func (a *Assistant) worktreeCommand(args []string, msg *channels.IncomingMessage) string {
+	if a.worktreeMgr == nil {
+		return "Worktree isolation not available."
+	}
+
+	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	session := resolved.Session
+
+	if len(args) == 0 {
+		return "Usage: /worktree <start|status|discard> [args...]\n\n" +
+			"/worktree start <project_id> [task description] — create an isolated worktree for this session\n" +
+			"/worktree status — show branch, commits, and pending changes\n" +
+			"/worktree discard — throw away the worktree and its branch\n\n" +
+			"Land the finished task with /merge."
+	}
+
+	sub := strings.ToLower(args[0])
+	subArgs := args[1:]
+
+	switch sub {
+	case "start":
+		if a.projectMgr == nil {
+			return "Project manager not available."
+		}
+		if len(subArgs) < 1 {
+			return "Usage: /worktree start <project_id> [task description]"
+		}
+		project := a.projectMgr.Get(subArgs[0])
+		if project == nil {
+			return fmt.Sprintf("Project '%s' not found. Register it first.", subArgs[0])
+		}
+		task := strings.Join(subArgs[1:], " ")
+
+		wt, err := a.worktreeMgr.Start(session.ID, project.RootPath, task)
+		if err != nil {
+			return fmt.Sprintf("Error: %v", err)
+		}
+		return fmt.Sprintf("Task worktree created.\nBranch: %s (from %s)\nPath: %s\n\nThe agent now edits and commits there. Use /worktree status to check progress and /merge to land it.",
+			wt.Branch, wt.BaseBranch, wt.Path)
+
+	case "status":
+		status, err := a.worktreeMgr.Status(session.ID)
+		if err != nil {
+			return fmt.Sprintf("Error: %v", err)
+		}
+		return status
+
+	case "discard":
+		result, err := a.worktreeMgr.Discard(session.ID)
+		if err != nil {
+			return fmt.Sprintf("Error: %v", err)
+		}
+		return result
+
+	default:
+		return "Unknown worktree command. Use: start, status, discard"
+	}
+}
+
+// mergeCommand handles /merge: land the session's task branch into its base.
+func (a *Assistant) mergeCommand(msg *channels.IncomingMessage) string {
+	if a.worktreeMgr == nil {
+		return "Worktree isolation not available."
+	}
+
+	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	result, err := a.worktreeMgr.Merge(resolved.Session.ID)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	return result
+}
+
 // parseDuration parses a duration string like "1h", "24h", "7d", "30d", or "never".
 func parseDuration(s string) (time.Duration, error) {
 	s = strings.ToLower(s)
@@ -1805,3 +2561,264 @@ func hookEventsToStrings(events []HookEvent) []string {
 	return result
 }
 
+// webhooksCommand handles /webhooks — inspect configured webhooks, list
+// dead-lettered deliveries, and replay them.
+func (a *Assistant) webhooksCommand(args []string) string {
+	if a.webhookMgr == nil {
+		return "Webhooks not configured."
+	}
+
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list", "ls":
+		webhooks := a.webhookMgr.ListWebhooks()
+		if len(webhooks) == 0 {
+			return "No webhooks configured."
+		}
+		var b strings.Builder
+		b.WriteString("*Configured Webhooks*\n\n")
+		for _, wh := range webhooks {
+			status := "enabled"
+			if !wh.Enabled {
+				status = "disabled"
+			}
+			fmt.Fprintf(&b, "`%s` (%s)\n  URL: %s\n  Events: %s\n\n",
+				wh.Name, status, wh.URL, strings.Join(wh.Events, ", "))
+		}
+		return b.String()
+
+	case "failed":
+		letters, err := a.webhookMgr.DeadLetters(20)
+		if err != nil {
+			return fmt.Sprintf("Failed to load dead letters: %v", err)
+		}
+		if len(letters) == 0 {
+			return "No failed webhook deliveries. 🎉"
+		}
+		var b strings.Builder
+		b.WriteString("*Failed Webhook Deliveries*\n\n")
+		for _, dl := range letters {
+			fmt.Fprintf(&b, "`%s` — %s (%s)\n  %s, %d attempt(s)\n  Error: %s\n\n",
+				dl.ID, dl.WebhookName, dl.Event,
+				dl.CreatedAt.Format("2006-01-02 15:04"), dl.Attempts,
+				truncate(dl.LastError, 120))
+		}
+		b.WriteString("Replay with /webhooks replay <id>")
+		return b.String()
+
+	case "replay":
+		if len(args) < 2 {
+			return "Usage: /webhooks replay <id>"
+		}
+		if err := a.webhookMgr.Replay(args[1]); err != nil {
+			return fmt.Sprintf("Replay failed: %v", err)
+		}
+		return fmt.Sprintf("Dead letter `%s` replayed and removed.", args[1])
+
+	default:
+		return "Usage: /webhooks [list|failed|replay <id>]"
+	}
+}
+
+// heartbeatCommand handles /heartbeat status — shows whether the heartbeat is
+// enabled and the recent beat outcomes with their skip reasons.
+func (a *Assistant) heartbeatCommand(args []string) string {
+	if len(args) > 0 && strings.ToLower(args[0]) != "status" {
+		return "Usage: /heartbeat status"
+	}
+
+	a.configMu.RLock()
+	cfg := a.config.Heartbeat
+	a.configMu.RUnlock()
+
+	var b strings.Builder
+	if !cfg.Enabled || a.heartbeat == nil {
+		return "Heartbeat disabled. Enable it in config.yaml under heartbeat."
+	}
+	fmt.Fprintf(&b, "*Heartbeat* — every %s, active %02d:00-%02d:00\n\n",
+		cfg.Interval, cfg.ActiveStart, cfg.ActiveEnd)
+
+	beats := a.heartbeat.RecentBeats()
+	if len(beats) == 0 {
+		b.WriteString("No beats recorded yet.")
+		return b.String()
+	}
+
+	b.WriteString("*Recent beats* (newest last):\n")
+	for _, beat := range beats {
+		icon := "✅"
+		switch beat.Outcome {
+		case "skipped":
+			icon = "⏭️"
+		case "error":
+			icon = "❌"
+		case "delivered", "notified":
+			icon = "📬"
+		}
+		fmt.Fprintf(&b, "%s %s — %s", icon, beat.At.Local().Format("15:04"), beat.Outcome)
+		if beat.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", beat.Reason)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// linkCommand handles /link — cross-channel identity linking. Without args
+// it issues a short-lived pairing code; sent with a code from another
+// channel it joins both JIDs into one identity. Admins can link or unlink
+// JIDs directly.
+func (a *Assistant) linkCommand(args []string, msg *channels.IncomingMessage, isAdmin bool) string {
+	if a.identityMgr == nil {
+		return "Identity linking not initialized."
+	}
+
+	switch {
+	case len(args) == 0:
+		code, err := a.identityMgr.GenerateLinkCode(msg.From, msg.Channel)
+		if err != nil {
+			return fmt.Sprintf("Failed to generate link code: %v", err)
+		}
+		return fmt.Sprintf("Identity link code: *%s*\nSend `/link %s` from your other channel within %s to link both identities.",
+			code, code, linkCodeTTL)
+
+	case len(args) == 1 && strings.EqualFold(args[0], "status"):
+		linked := a.identityMgr.LinkedJIDs(msg.From)
+		if len(linked) <= 1 {
+			return "No linked identities. Use /link to get a pairing code."
+		}
+		return fmt.Sprintf("Linked identities (%d):\n• %s", len(linked), strings.Join(linked, "\n• "))
+
+	case len(args) == 1 && strings.EqualFold(args[0], "remove"):
+		if err := a.identityMgr.Unlink(msg.From); err != nil {
+			return fmt.Sprintf("Failed to unlink: %v", err)
+		}
+		return "This identity is no longer linked."
+
+	case len(args) == 1:
+		if err := a.identityMgr.RedeemLinkCode(args[0], msg.From, msg.Channel); err != nil {
+			return fmt.Sprintf("Failed to link: %v", err)
+		}
+		return "Identities linked. Your access level and workspace now follow you across channels."
+
+	case len(args) == 2:
+		if !isAdmin {
+			return "Only admins can link other users' identities."
+		}
+		if err := a.identityMgr.LinkJIDs(args[0], args[1], msg.From); err != nil {
+			return fmt.Sprintf("Failed to link: %v", err)
+		}
+		return fmt.Sprintf("Linked %s and %s as one identity.", args[0], args[1])
+
+	default:
+		return "Usage: /link [code|status|remove] or /link <jid1> <jid2> (admin)"
+	}
+}
+
+// flagsCommand handles /flags — list feature flags with their effective
+// state for the current workspace, or toggle one at runtime. Overrides set
+// here persist in devclaw.db and win over config rollout rules.
+func (a *Assistant) flagsCommand(args []string, msg *channels.IncomingMessage) string {
+	if a.featureFlags == nil {
+		return "Feature flags not initialized."
+	}
+	wsID := a.workspaceMgr.WorkspaceIDFor(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+
+	if len(args) == 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "*Feature Flags* (workspace: %s)\n\n", wsID)
+		for _, flag := range a.featureFlags.KnownFlags() {
+			state := "off"
+			if a.featureFlags.Enabled(flag, wsID) {
+				state = "on"
+			}
+			fmt.Fprintf(&b, "• `%s` — %s", flag, state)
+			if overrides := a.featureFlags.Overrides(flag); len(overrides) > 0 {
+				parts := make([]string, 0, len(overrides))
+				for scope, enabled := range overrides {
+					parts = append(parts, fmt.Sprintf("%s=%v", scope, enabled))
+				}
+				fmt.Fprintf(&b, " (overrides: %s)", strings.Join(parts, ", "))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\nToggle with /flags on|off <flag> [workspace|*], revert with /flags reset <flag> [workspace|*].")
+		return b.String()
+	}
+
+	if len(args) < 2 {
+		return "Usage: /flags [on|off|reset <flag> [workspace|*]]"
+	}
+	action := strings.ToLower(args[0])
+	flag := FeatureFlag(strings.ToLower(args[1]))
+	if _, known := knownFlags[flag]; !known {
+		return fmt.Sprintf("Unknown flag %q. See /flags for the list.", args[1])
+	}
+	scope := wsID
+	if len(args) > 2 {
+		scope = args[2]
+	}
+
+	switch action {
+	case "on", "off":
+		if err := a.featureFlags.SetOverride(flag, scope, action == "on"); err != nil {
+			return fmt.Sprintf("Failed to set flag: %v", err)
+		}
+		return fmt.Sprintf("Flag `%s` is now %s for %s.", flag, action, scope)
+	case "reset":
+		if err := a.featureFlags.ClearOverride(flag, scope); err != nil {
+			return fmt.Sprintf("Failed to reset flag: %v", err)
+		}
+		return fmt.Sprintf("Flag `%s` override cleared for %s (config rules apply).", flag, scope)
+	default:
+		return "Usage: /flags [on|off|reset <flag> [workspace|*]]"
+	}
+}
+
+// bootCommand handles /boot — shows how each subsystem came up at startup.
+func (a *Assistant) bootCommand() string {
+	if a.boot == nil {
+		return "No boot sequence recorded (assistant not started via Start)."
+	}
+	return a.boot.Report()
+}
+
+// traceCommand handles /trace — list recent agent runs or render one run's
+// turn-by-turn timeline by run ID.
+func (a *Assistant) traceCommand(args []string) string {
+	if a.runTraceStore == nil {
+		return "Run transcripts require the SQLite database."
+	}
+
+	if len(args) == 0 {
+		traces, err := a.runTraceStore.Recent(10)
+		if err != nil {
+			return fmt.Sprintf("Failed to load runs: %v", err)
+		}
+		if len(traces) == 0 {
+			return "No runs recorded yet."
+		}
+		var b strings.Builder
+		b.WriteString("*Recent Runs*\n\n")
+		for _, trace := range traces {
+			icon := "✅"
+			if trace.Status != "ok" {
+				icon = "❌"
+			}
+			fmt.Fprintf(&b, "%s `%s` — %s (%s, %d events)\n  %s\n\n",
+				icon, trace.RunID, trace.SessionID, formatTraceDuration(trace.DurationMs),
+				len(trace.Events), trace.StartedAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		b.WriteString("Inspect one with /trace <run-id>")
+		return b.String()
+	}
+
+	trace, err := a.runTraceStore.Get(args[0])
+	if err != nil {
+		return fmt.Sprintf("Failed to load run: %v", err)
+	}
+	return formatRunTrace(trace)
+}
@@ -7,12 +7,17 @@ import (
 	"strings"
 
 	"github.com/jholhewres/devclaw/pkg/devclaw/channels/discord"
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels/irc"
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels/matrix"
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels/signal"
 	"github.com/jholhewres/devclaw/pkg/devclaw/channels/slack"
 	"github.com/jholhewres/devclaw/pkg/devclaw/channels/telegram"
 	"github.com/jholhewres/devclaw/pkg/devclaw/channels/whatsapp"
 	"github.com/jholhewres/devclaw/pkg/devclaw/copilot/memory"
 	"github.com/jholhewres/devclaw/pkg/devclaw/copilot/security"
 	"github.com/jholhewres/devclaw/pkg/devclaw/database"
+	"github.com/jholhewres/devclaw/pkg/devclaw/media"
+	"github.com/jholhewres/devclaw/pkg/devclaw/notify"
 	"github.com/jholhewres/devclaw/pkg/devclaw/paths"
 	"github.com/jholhewres/devclaw/pkg/devclaw/plugins"
 	"github.com/jholhewres/devclaw/pkg/devclaw/sandbox"
@@ -54,15 +59,32 @@ type Config struct {
 	// Trigger is the keyword that activates the bot (e.g. "@devclaw").
 	Trigger string `yaml:"trigger"`
 
+	// WakeWord is the spoken phrase that activates the bot in group voice
+	// notes (e.g. "hey claw"). Evaluated against the transcript, so it works
+	// even though the text trigger only sees the "[audio]" placeholder.
+	WakeWord string `yaml:"wake_word"`
+
 	// Model is the LLM model to use (e.g. "glm-4.7-flash").
 	Model string `yaml:"model"`
 
+	// Models configures auxiliary model roles beyond the main chat model.
+	Models ModelsConfig `yaml:"models"`
+
 	// API configures the LLM provider endpoint.
 	API APIConfig `yaml:"api"`
 
-	// Instructions are the base system prompt instructions.
+	// Instructions are the base system prompt instructions. May use Go
+	// text/template syntax with PromptVars fields (e.g. {{.UserName}}) and
+	// partials from PromptPartials via {{template "name" .}}.
 	Instructions string `yaml:"instructions"`
 
+	// PromptPartials are named template snippets that Instructions can
+	// include via {{template "name" .}}. Validated at config load.
+	PromptPartials map[string]string `yaml:"prompt_partials"`
+
+	// Personas configures the persona library (voice presets, /persona).
+	Personas PersonasConfig `yaml:"personas"`
+
 	// Timezone is the user's timezone (e.g. "America/Sao_Paulo").
 	Timezone string `yaml:"timezone"`
 
@@ -87,6 +109,21 @@ type Config struct {
 	// TokenBudget configures per-layer token limits.
 	TokenBudget TokenBudgetConfig `yaml:"token_budget"`
 
+	// ProjectBootstrap configures per-project AGENT.md/CLAUDE.md discovery.
+	ProjectBootstrap ProjectBootstrapConfig `yaml:"project_bootstrap"`
+
+	// ToolSelection configures dynamic per-turn tool selection.
+	ToolSelection ToolSelectionConfig `yaml:"tool_selection"`
+
+	// Features configures feature flags and per-workspace rollout.
+	Features FeatureFlagsConfig `yaml:"features"`
+
+	// RequestQueue configures the global LLM request scheduler.
+	RequestQueue RequestQueueConfig `yaml:"request_queue"`
+
+	// Batch configures provider batch APIs for non-interactive jobs.
+	Batch BatchConfig `yaml:"batch"`
+
 	// Plugins configures the plugin loader.
 	Plugins plugins.Config `yaml:"plugins"`
 
@@ -102,6 +139,19 @@ type Config struct {
 	// Heartbeat configures the proactive heartbeat system.
 	Heartbeat HeartbeatConfig `yaml:"heartbeat"`
 
+	// DND configures do-not-disturb silence windows for proactive messages.
+	DND DNDConfig `yaml:"dnd"`
+
+	// Presence configures presence-aware (when-active) delivery.
+	Presence PresenceConfig `yaml:"presence"`
+
+	// Incidents configures the incident subsystem and escalation chain.
+	Incidents IncidentConfig `yaml:"incidents"`
+
+	// Notify configures push notification sinks (ntfy, Pushover) used for
+	// proactive alerts when the user isn't in an active chat.
+	Notify notify.Config `yaml:"notify"`
+
 	// Subagents configures the subagent orchestration system.
 	Subagents SubagentConfig `yaml:"subagents"`
 
@@ -132,6 +182,15 @@ type Config struct {
 	// Gateway configures the HTTP API gateway.
 	Gateway GatewayConfig `yaml:"gateway"`
 
+	// Federation configures delegation to remote DevClaw instances.
+	Federation FederationConfig `yaml:"federation"`
+
+	// GitOps configures live config and skill sync from a git repository.
+	GitOps GitOpsConfig `yaml:"gitops"`
+
+	// HomeAssistant configures the Home Assistant / MQTT smart home integration.
+	HomeAssistant HomeAssistantConfig `yaml:"homeassistant"`
+
 	// BlockStream configures progressive message delivery (stream text to channel
 	// in chunks instead of waiting for the complete response).
 	BlockStream BlockStreamConfig `yaml:"block_stream"`
@@ -168,6 +227,49 @@ type Config struct {
 
 	// Browser configures browser automation tools.
 	Browser BrowserConfig `yaml:"browser"`
+
+	// Reactions configures emoji reaction semantics and sticker handling.
+	Reactions ReactionsConfig `yaml:"reactions"`
+
+	// Crash configures panic capture and crash reporting.
+	Crash CrashConfig `yaml:"crash"`
+
+	// PostProcess configures the response formatting pipeline.
+	PostProcess PostProcessConfig `yaml:"post_process"`
+
+	// Citations configures source tracking for web-derived answers.
+	Citations CitationsConfig `yaml:"citations"`
+
+	// Verify configures the fact-check verification pass.
+	Verify VerifyConfig `yaml:"verify"`
+
+	// TrafficLog configures LLM request/response logging.
+	TrafficLog TrafficLogConfig `yaml:"traffic_log"`
+
+	// RunTrace configures run transcript retention.
+	RunTrace RunTraceConfig `yaml:"run_trace"`
+}
+
+// ReactionsConfig configures emoji reaction semantics for agent responses
+// and how incoming stickers are handled.
+type ReactionsConfig struct {
+	// ReactTool exposes the "react" tool so the agent can acknowledge a
+	// message with a lightweight emoji reaction (👍, ❤️, 😂) instead of a
+	// full text reply (default: true).
+	ReactTool bool `yaml:"react_tool"`
+
+	// DescribeStickers runs vision on incoming stickers so the agent sees
+	// a description instead of dropping them (default: true). Requires
+	// MediaConfig.VisionEnabled.
+	DescribeStickers bool `yaml:"describe_stickers"`
+}
+
+// DefaultReactionsConfig returns sensible defaults for reaction semantics.
+func DefaultReactionsConfig() ReactionsConfig {
+	return ReactionsConfig{
+		ReactTool:        true,
+		DescribeStickers: true,
+	}
 }
 
 // RoutinesConfig configures background routines for metrics and memory indexing.
@@ -177,6 +279,18 @@ type RoutinesConfig struct {
 
 	// MemoryIndexer configures the background memory indexer.
 	MemoryIndexer MemoryIndexerConfig `yaml:"memory_indexer"`
+
+	// Digest configures the per-workspace activity digest.
+	Digest DigestConfig `yaml:"digest"`
+
+	// Watchdog configures owner alerts for session watchdog recoveries.
+	Watchdog WatchdogConfig `yaml:"watchdog"`
+
+	// Anomaly configures the metrics-driven anomaly monitor.
+	Anomaly AnomalyConfig `yaml:"anomaly"`
+
+	// DBMaintenance configures scheduled SQLite maintenance.
+	DBMaintenance DBMaintenanceConfig `yaml:"db_maintenance"`
 }
 
 // DefaultRoutinesConfig returns sensible defaults for background routines.
@@ -184,6 +298,10 @@ func DefaultRoutinesConfig() RoutinesConfig {
 	return RoutinesConfig{
 		Metrics:       DefaultMetricsCollectorConfig(),
 		MemoryIndexer: DefaultMemoryIndexerConfig(),
+		Digest:        DefaultDigestConfig(),
+		Watchdog:      DefaultWatchdogConfig(),
+		Anomaly:       DefaultAnomalyConfig(),
+		DBMaintenance: DefaultDBMaintenanceConfig(),
 	}
 }
 
@@ -233,6 +351,12 @@ type NativeMediaServiceConfig struct {
 
 	// CleanupInterval is the interval between cleanup runs.
 	CleanupInterval string `yaml:"cleanup_interval"`
+
+	// Lifecycle configures retention rules and per-workspace quotas.
+	Lifecycle media.LifecycleConfig `yaml:"lifecycle"`
+
+	// Scan configures malware scanning of attachments (ClamAV or command).
+	Scan media.ScanConfig `yaml:"scan"`
 }
 
 // NativeMediaEnrichmentConfig configures automatic media enrichment.
@@ -268,6 +392,8 @@ func DefaultNativeMediaConfig() NativeMediaConfig {
 			TempTTL:         "24h",
 			CleanupEnabled:  true,
 			CleanupInterval: "1h",
+			Lifecycle:       media.DefaultLifecycleConfig(),
+			Scan:            media.DefaultScanConfig(),
 		},
 		Enrichment: NativeMediaEnrichmentConfig{
 			// These flags request enrichment, but actual enrichment
@@ -318,6 +444,11 @@ type GatewayConfig struct {
 
 	// CORSOrigins lists allowed origins for CORS (empty = no CORS).
 	CORSOrigins []string `yaml:"cors_origins"`
+
+	// Pprof exposes Go profiling endpoints under /debug/pprof/ (heap,
+	// goroutine, CPU). Protected by AuthToken like the rest of the API;
+	// enable only when diagnosing memory or CPU growth (default: false).
+	Pprof bool `yaml:"pprof"`
 }
 
 // QueueConfig configures the message queue for handling bursts.
@@ -334,6 +465,13 @@ type QueueConfig struct {
 	// ByChannel overrides the default mode per channel name.
 	ByChannel map[string]QueueMode `yaml:"by_channel"`
 
+	// SteerInboxSize is the interrupt inbox capacity per active run —
+	// how many steer messages can wait for injection between turns (default: 10).
+	SteerInboxSize int `yaml:"steer_inbox_size"`
+
+	// SteerInboxByChannel overrides the inbox capacity per channel name.
+	SteerInboxByChannel map[string]int `yaml:"steer_inbox_by_channel"`
+
 	// DropPolicy controls what happens when the queue exceeds MaxPending (default: "old").
 	DropPolicy QueueDropPolicy `yaml:"drop_policy"`
 }
@@ -373,6 +511,16 @@ type MediaConfig struct {
 	// For Z.AI GLM-ASR: used as a prompt hint for auto-detection.
 	TranscriptionLanguage string `yaml:"transcription_language"`
 
+	// TranscriptionLanguageDetect runs a fast language-identification pass on
+	// each audio and uses the result as the language hint, instead of the
+	// static TranscriptionLanguage. Ignored when a static hint is set.
+	TranscriptionLanguageDetect bool `yaml:"transcription_language_detect"`
+
+	// SummaryModel overrides the model used for long-document map-reduce
+	// summarization. If empty, uses the main chat model. A cheap/fast model
+	// is recommended since chunk summaries run in parallel.
+	SummaryModel string `yaml:"summary_model"`
+
 	// MaxImageSize is the max image size in bytes to process (default: 20MB).
 	MaxImageSize int64 `yaml:"max_image_size"`
 
@@ -459,6 +607,15 @@ type FallbackConfig struct {
 
 	// RetryOnStatusCodes lists HTTP codes that trigger retry (default: [429, 500, 502, 503, 529]).
 	RetryOnStatusCodes []int `yaml:"retry_on_status_codes"`
+
+	// HealthWindowMinutes is the rolling window for per-model latency health
+	// stats used by /llm status and the failover policy (default: 5).
+	HealthWindowMinutes int `yaml:"health_window_minutes"`
+
+	// PreferFallbackP95Seconds prefers fallback models over the primary when
+	// the primary's p95 latency over the health window exceeds this many
+	// seconds (0 = disabled).
+	PreferFallbackP95Seconds int `yaml:"prefer_fallback_p95_seconds"`
 }
 
 // ProviderChainEntry defines a single provider in the fallback chain.
@@ -497,7 +654,8 @@ func DefaultFallbackConfig() FallbackConfig {
 		MaxRetries:         2,
 		InitialBackoffMs:   1000,
 		MaxBackoffMs:       30000,
-		RetryOnStatusCodes: []int{429, 500, 502, 503, 521, 522, 523, 524, 529},
+		RetryOnStatusCodes:  []int{429, 500, 502, 503, 521, 522, 523, 524, 529},
+		HealthWindowMinutes: 5,
 	}
 }
 
@@ -516,9 +674,20 @@ func (f FallbackConfig) Effective() FallbackConfig {
 	if len(out.RetryOnStatusCodes) == 0 {
 		out.RetryOnStatusCodes = []int{429, 500, 502, 503, 521, 522, 523, 524, 529}
 	}
+	if out.HealthWindowMinutes == 0 {
+		out.HealthWindowMinutes = 5
+	}
 	return out
 }
 
+// ModelsConfig configures auxiliary model roles.
+type ModelsConfig struct {
+	// Utility is a cheaper/faster model for internal low-stakes calls
+	// (compaction summaries, session titles, fact extraction). Falls back
+	// to the main model automatically when unset or failing.
+	Utility string `yaml:"utility"`
+}
+
 // APIConfig configures the LLM provider endpoint and credentials.
 type APIConfig struct {
 	// BaseURL is the API base URL (OpenAI-compatible endpoint).
@@ -547,6 +716,13 @@ type ChannelsConfig struct {
 	// WhatsApp is the WhatsApp channel config (core).
 	WhatsApp whatsapp.Config `yaml:"whatsapp"`
 
+	// WhatsAppAccounts defines additional named WhatsApp accounts
+	// (e.g. "business") beyond the primary one. Each account gets its own
+	// session database and is registered as "whatsapp#<name>", so sessions
+	// and replies are keyed per account. Per-account access rules go in
+	// access.by_channel under the same qualified name.
+	WhatsAppAccounts map[string]whatsapp.Config `yaml:"whatsapp_accounts"`
+
 	// Telegram is the Telegram channel config (core).
 	Telegram telegram.Config `yaml:"telegram"`
 
@@ -555,6 +731,15 @@ type ChannelsConfig struct {
 
 	// Slack is the Slack channel config (core).
 	Slack slack.Config `yaml:"slack"`
+
+	// Matrix is the Matrix channel config (core).
+	Matrix matrix.Config `yaml:"matrix"`
+
+	// Signal is the Signal channel config (via signal-cli daemon).
+	Signal signal.Config `yaml:"signal"`
+
+	// IRC is the IRC channel config.
+	IRC irc.Config `yaml:"irc"`
 }
 
 // MemoryConfig configures the memory and persistence system.
@@ -670,6 +855,9 @@ type SecurityConfig struct {
 
 	// ExecAnalysis configures command risk analysis for bash/exec tools.
 	ExecAnalysis ExecAnalysisConfig `yaml:"exec_analysis"`
+
+	// SandboxFS configures per-session scratch jails for file tools.
+	SandboxFS SandboxFSConfig `yaml:"sandbox_fs"`
 }
 
 // ToolExecutorConfig configures tool execution behavior.
@@ -685,6 +873,14 @@ type ToolExecutorConfig struct {
 
 	// DefaultTimeoutSeconds is the executor-level timeout for all other tools (default: 30).
 	DefaultTimeoutSeconds int `yaml:"default_timeout_seconds"`
+
+	// PerToolTimeoutSeconds overrides the timeout for specific tools by name,
+	// taking precedence over the bash/default timeouts above.
+	PerToolTimeoutSeconds map[string]int `yaml:"per_tool_timeout_seconds"`
+
+	// MaxTimeoutSeconds is a global ceiling applied after all overrides
+	// (0 = no ceiling). Keeps a generous per-tool value from hanging a run.
+	MaxTimeoutSeconds int `yaml:"max_timeout_seconds"`
 }
 
 // TokenBudgetConfig configures per-layer token allocation.
@@ -702,6 +898,62 @@ type TokenBudgetConfig struct {
 	BootstrapMaxChars int `yaml:"bootstrap_max_chars"`
 }
 
+// ProjectBootstrapConfig configures auto-discovery of convention files
+// (AGENT.md, AGENTS.md, CLAUDE.md) in the workspace dir and the session's
+// active project root. Discovered files are layered into the system prompt
+// so project conventions travel with the session.
+type ProjectBootstrapConfig struct {
+	// Disabled turns project bootstrap discovery off (default: enabled).
+	Disabled bool `yaml:"disabled"`
+
+	// Include lists the filenames to discover.
+	// Default: AGENT.md, AGENTS.md, CLAUDE.md.
+	Include []string `yaml:"include"`
+
+	// Exclude lists filenames to skip even when listed in Include.
+	Exclude []string `yaml:"exclude"`
+
+	// MaxTokens is the token budget for this prompt layer (default: 3000).
+	MaxTokens int `yaml:"max_tokens"`
+}
+
+// Effective returns a copy with default values filled in for zero fields.
+func (c ProjectBootstrapConfig) Effective() ProjectBootstrapConfig {
+	out := c
+	if len(out.Include) == 0 {
+		out.Include = []string{"AGENT.md", "AGENTS.md", "CLAUDE.md"}
+	}
+	if out.MaxTokens <= 0 {
+		out.MaxTokens = 3000
+	}
+	return out
+}
+
+// ToolSelectionConfig configures dynamic per-turn tool selection. With 100+
+// registered tools the full catalog bloats every LLM request; when enabled,
+// only the top-N tools relevant to the current message are sent, plus pinned
+// tools and the find_tool escape hatch the model can call to load more.
+type ToolSelectionConfig struct {
+	// Enabled turns dynamic tool selection on (default: off — full catalog).
+	Enabled bool `yaml:"enabled"`
+
+	// TopN is how many tool definitions are sent per turn (default: 24).
+	TopN int `yaml:"top_n"`
+
+	// AlwaysInclude lists tool names that are sent on every turn regardless
+	// of relevance scoring (e.g. bash, memory_search).
+	AlwaysInclude []string `yaml:"always_include"`
+}
+
+// Effective returns a copy with default values filled in for zero fields.
+func (c ToolSelectionConfig) Effective() ToolSelectionConfig {
+	out := c
+	if out.TopN <= 0 {
+		out.TopN = 24
+	}
+	return out
+}
+
 // SkillsConfig configures the skills system.
 type SkillsConfig struct {
 	// Builtin lists built-in skills to enable.
@@ -750,6 +1002,9 @@ func DefaultConfig() *Config {
 		Workspaces: DefaultWorkspaceConfig(),
 		Channels: ChannelsConfig{
 			WhatsApp: whatsapp.DefaultConfig(),
+			Matrix:   matrix.DefaultConfig(),
+			Signal:   signal.DefaultConfig(),
+			IRC:      irc.DefaultConfig(),
 		},
 		Memory: MemoryConfig{
 			Type:                "sqlite",
@@ -792,6 +1047,7 @@ func DefaultConfig() *Config {
 				BashTimeoutSeconds:    300,
 				DefaultTimeoutSeconds: 30,
 			},
+			SandboxFS: DefaultSandboxFSConfig(),
 		},
 		TokenBudget: TokenBudgetConfig{
 			Total:    128000,
@@ -814,6 +1070,7 @@ func DefaultConfig() *Config {
 			Storage: paths.ResolveDatabasePath("scheduler.db"),
 		},
 		Heartbeat:  DefaultHeartbeatConfig(),
+		Notify:     notify.DefaultConfig(),
 		Subagents:  DefaultSubagentConfig(),
 		Agent:      DefaultAgentConfig(),
 		Fallback:   DefaultFallbackConfig(),
@@ -832,6 +1089,7 @@ func DefaultConfig() *Config {
 			Enabled: false,
 			Address: ":8085",
 		},
+		GitOps: DefaultGitOpsConfig(),
 		BlockStream: DefaultBlockStreamConfig(),
 		WebSearch: WebSearchConfig{
 			Provider:   "duckduckgo",
@@ -847,7 +1105,14 @@ func DefaultConfig() *Config {
 			Enabled: false,
 			Address: ":8090",
 		},
-		Browser: DefaultBrowserConfig(),
+		Browser:     DefaultBrowserConfig(),
+		Reactions:   DefaultReactionsConfig(),
+		Crash:       DefaultCrashConfig(),
+		PostProcess: DefaultPostProcessConfig(),
+		Citations:   DefaultCitationsConfig(),
+		Verify:      DefaultVerifyConfig(),
+		TrafficLog:  DefaultTrafficLogConfig(),
+		RunTrace:    DefaultRunTraceConfig(),
 	}
 }
 
@@ -891,6 +1156,14 @@ type TTSConfig struct {
 	//   "always"  - always generate audio alongside text
 	//   "inbound" - generate audio only when the user sent a voice note
 	AutoMode string `yaml:"auto_mode"`
+
+	// Streaming synthesizes audio per flushed text block instead of waiting
+	// for the full response, cutting perceived voice latency (default: false).
+	Streaming bool `yaml:"streaming"`
+
+	// StreamSingleFile lists channels that handle multi-part audio poorly;
+	// their segments are concatenated into one file sent at the end.
+	StreamSingleFile []string `yaml:"stream_single_file"`
 }
 
 // GroupConfig configures group chat behavior.
@@ -0,0 +1,346 @@
+// Package copilot – crash_reporter.go captures panics in the Assistant's
+// background goroutines (message loop, watchdog, heartbeat, scheduler jobs)
+// instead of letting them kill the goroutine silently. Each panic is logged,
+// persisted to devclaw.db as a crash report with a runtime minidump,
+// optionally forwarded to a Sentry DSN, and announced to the owner chat.
+package copilot
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// CrashConfig configures panic capture and crash reporting.
+type CrashConfig struct {
+	// Enabled turns crash capture on/off (default true — a recovered panic
+	// is always worth recording).
+	Enabled bool `yaml:"enabled"`
+
+	// SentryDSN is an optional Sentry project DSN to forward crash events
+	// to. Prefer storing it in the vault under SENTRY_DSN; this field is a
+	// fallback for setups without a vault.
+	SentryDSN string `yaml:"sentry_dsn"`
+
+	// Notify controls whether the owner chat is notified on a recovered
+	// panic (default true).
+	Notify bool `yaml:"notify"`
+
+	// Channel/ChatID override where crash notifications are delivered.
+	// Empty = the most recently active session.
+	Channel string `yaml:"channel"`
+	ChatID  string `yaml:"chat_id"`
+}
+
+// DefaultCrashConfig returns default crash reporting configuration.
+func DefaultCrashConfig() CrashConfig {
+	return CrashConfig{
+		Enabled: true,
+		Notify:  true,
+	}
+}
+
+// sentryDSNVaultKey is the vault secret consulted before CrashConfig.SentryDSN.
+const sentryDSNVaultKey = "SENTRY_DSN"
+
+// CrashReport is one captured panic.
+type CrashReport struct {
+	ID        string
+	Component string
+	Message   string
+	Stack     string
+	CreatedAt time.Time
+	Reported  bool
+}
+
+// CrashStore persists crash reports in the central database.
+type CrashStore struct {
+	db *sql.DB
+}
+
+// NewCrashStore creates a crash report store.
+func NewCrashStore(db *sql.DB) *CrashStore {
+	return &CrashStore{db: db}
+}
+
+// Save inserts a crash report.
+func (s *CrashStore) Save(report *CrashReport) error {
+	_, err := s.db.Exec(
+		`INSERT INTO crash_reports (id, component, message, stack, created_at, reported)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		report.ID, report.Component, report.Message, report.Stack,
+		report.CreatedAt.UTC().Format(time.RFC3339), boolToInt(report.Reported))
+	if err != nil {
+		return fmt.Errorf("save crash report: %w", err)
+	}
+	return nil
+}
+
+// MarkReported flags a report as delivered to the external reporter.
+func (s *CrashStore) MarkReported(id string) error {
+	if _, err := s.db.Exec(`UPDATE crash_reports SET reported = 1 WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("mark crash report: %w", err)
+	}
+	return nil
+}
+
+// Recent returns the newest crash reports, most recent first.
+func (s *CrashStore) Recent(limit int) ([]CrashReport, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := s.db.Query(
+		`SELECT id, component, message, stack, created_at, reported
+		 FROM crash_reports ORDER BY created_at DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list crash reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []CrashReport
+	for rows.Next() {
+		var r CrashReport
+		var createdAt string
+		var reported int
+		if err := rows.Scan(&r.ID, &r.Component, &r.Message, &r.Stack, &createdAt, &reported); err != nil {
+			return nil, fmt.Errorf("scan crash report: %w", err)
+		}
+		r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		r.Reported = reported != 0
+		reports = append(reports, r)
+	}
+	return reports, rows.Err()
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// recoverPanic is meant to be deferred at the top of an Assistant background
+// goroutine: defer a.recoverPanic("message-loop"). A no-op when no panic is
+// in flight.
+func (a *Assistant) recoverPanic(component string) {
+	if r := recover(); r != nil {
+		a.capturePanic(component, r, debug.Stack())
+	}
+}
+
+// runProtected runs fn, capturing a panic and restarting fn after a short
+// pause so one crash doesn't permanently kill a long-lived loop. Returns when
+// fn completes normally or the assistant context ends.
+func (a *Assistant) runProtected(component string, fn func()) {
+	for {
+		completed := func() (ok bool) {
+			defer func() {
+				if r := recover(); r != nil {
+					a.capturePanic(component, r, debug.Stack())
+				}
+			}()
+			fn()
+			return true
+		}()
+		if completed || a.ctx == nil || a.ctx.Err() != nil {
+			return
+		}
+		// Brief pause before the restart so a deterministic panic can't
+		// spin the loop hot.
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// capturePanic records a recovered panic: log, persist to devclaw.db,
+// optionally forward to Sentry, and notify the owner.
+func (a *Assistant) capturePanic(component string, r any, stack []byte) {
+	a.logger.Error("panic recovered", "component", component, "panic", fmt.Sprint(r))
+
+	cfg := a.config.Crash
+	if !cfg.Enabled {
+		return
+	}
+
+	report := &CrashReport{
+		ID:        generateID(),
+		Component: component,
+		Message:   fmt.Sprint(r),
+		Stack:     buildMinidump(stack),
+		CreatedAt: time.Now().UTC(),
+	}
+	if a.crashStore != nil {
+		if err := a.crashStore.Save(report); err != nil {
+			a.logger.Warn("failed to persist crash report", "error", err)
+		}
+	}
+
+	dsn := a.sentryDSN()
+	if dsn != "" {
+		// Forward asynchronously — crash capture must never block (or
+		// panic) the recovering goroutine on network I/O.
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := sendSentryEvent(ctx, dsn, report); err != nil {
+				a.logger.Warn("sentry crash report failed", "error", err)
+				return
+			}
+			if a.crashStore != nil {
+				_ = a.crashStore.MarkReported(report.ID)
+			}
+		}()
+	}
+
+	if !cfg.Notify {
+		return
+	}
+	channel, chatID := a.crashDeliveryTarget(cfg)
+	if channel == "" || chatID == "" {
+		a.logger.Warn("crash notification has no delivery target", "component", component)
+		return
+	}
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Urgent: a panic means part of the agent just died and recovered.
+	if err := a.sendProactive(ctx, channel, chatID, buildCrashAlert(report, dsn != ""), true); err != nil {
+		a.logger.Warn("crash notification delivery failed", "channel", channel, "error", err)
+	}
+}
+
+// sentryDSN resolves the Sentry DSN: vault key SENTRY_DSN when available,
+// otherwise the config fallback.
+func (a *Assistant) sentryDSN() string {
+	if a.vault != nil && a.vault.IsUnlocked() {
+		if dsn, err := a.vault.Get(sentryDSNVaultKey); err == nil && dsn != "" {
+			return dsn
+		}
+	}
+	return a.config.Crash.SentryDSN
+}
+
+// crashDeliveryTarget resolves where crash notifications go: the config
+// override when set, otherwise the most recently active session.
+func (a *Assistant) crashDeliveryTarget(cfg CrashConfig) (channel, chatID string) {
+	if cfg.Channel != "" && cfg.ChatID != "" {
+		return cfg.Channel, cfg.ChatID
+	}
+	if a.workspaceMgr == nil {
+		return "", ""
+	}
+	var latest time.Time
+	for _, info := range a.workspaceMgr.ListAllSessions() {
+		if info.LastActiveAt.After(latest) && info.Channel != "" && info.ChatID != "" {
+			latest = info.LastActiveAt
+			channel = info.Channel
+			chatID = info.ChatID
+		}
+	}
+	return channel, chatID
+}
+
+// buildMinidump renders the crash blob persisted with a report: a snapshot of
+// the runtime state followed by the full stack trace.
+func buildMinidump(stack []byte) string {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var b strings.Builder
+	b.WriteString("=== runtime ===\n")
+	fmt.Fprintf(&b, "go:         %s (%s/%s)\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "goroutines: %d\n", runtime.NumGoroutine())
+	fmt.Fprintf(&b, "heap:       %d KiB alloc, %d KiB sys\n", mem.HeapAlloc/1024, mem.Sys/1024)
+	b.WriteString("=== stack ===\n")
+	b.Write(bytes.TrimRight(stack, "\n"))
+	return b.String()
+}
+
+// buildCrashAlert renders the owner notification for a recovered panic.
+func buildCrashAlert(report *CrashReport, sentToSentry bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "💥 *Panic recovered in %s*\n%s", report.Component, truncate(report.Message, 300))
+	fmt.Fprintf(&b, "\n\nThe goroutine was recovered and the service kept running. Full stack saved as crash report `%s`.", report.ID)
+	if sentToSentry {
+		b.WriteString(" Forwarded to Sentry.")
+	}
+	return b.String()
+}
+
+// parseSentryDSN splits a DSN (scheme://publickey@host/projectid) into the
+// store-API endpoint and the public key.
+func parseSentryDSN(dsn string) (endpoint, key string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("parse sentry dsn: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("sentry dsn missing public key")
+	}
+	project := strings.Trim(u.Path, "/")
+	if project == "" {
+		return "", "", fmt.Errorf("sentry dsn missing project id")
+	}
+	endpoint = fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, project)
+	return endpoint, u.User.Username(), nil
+}
+
+// buildSentryEvent renders the store-API payload for a crash report.
+func buildSentryEvent(report *CrashReport) map[string]any {
+	eventID := make([]byte, 16)
+	_, _ = rand.Read(eventID)
+	hostname, _ := os.Hostname()
+	return map[string]any{
+		"event_id":    hex.EncodeToString(eventID),
+		"timestamp":   report.CreatedAt.UTC().Format(time.RFC3339),
+		"platform":    "go",
+		"level":       "fatal",
+		"logger":      report.Component,
+		"server_name": hostname,
+		"message":     report.Message,
+		"extra": map[string]any{
+			"crash_report_id": report.ID,
+			"minidump":        report.Stack,
+		},
+	}
+}
+
+// sendSentryEvent posts a crash report to the DSN's store API.
+func sendSentryEvent(ctx context.Context, dsn string, report *CrashReport) error {
+	endpoint, key, err := parseSentryDSN(dsn)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(buildSentryEvent(report))
+	if err != nil {
+		return fmt.Errorf("marshal sentry event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build sentry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth",
+		fmt.Sprintf("Sentry sentry_version=7, sentry_client=devclaw/1.0, sentry_key=%s", key))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send sentry event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package copilot – crash_reporter_test.go contains unit tests for crash
+// report persistence and the Sentry forwarding path.
+package copilot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCrashStoreSaveAndRecent(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenDatabase: %v", err)
+	}
+	defer db.Close()
+	store := NewCrashStore(db)
+
+	first := &CrashReport{ID: "c1", Component: "message-loop", Message: "nil map write",
+		Stack: "=== stack ===\ngoroutine 1", CreatedAt: time.Now().Add(-time.Minute)}
+	second := &CrashReport{ID: "c2", Component: "heartbeat", Message: "index out of range",
+		Stack: "=== stack ===\ngoroutine 7", CreatedAt: time.Now()}
+	for _, r := range []*CrashReport{first, second} {
+		if err := store.Save(r); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	reports, err := store.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(reports) != 2 || reports[0].ID != "c2" || reports[1].ID != "c1" {
+		t.Fatalf("expected newest first, got %+v", reports)
+	}
+	if reports[0].Reported {
+		t.Error("new report should not be marked reported")
+	}
+
+	if err := store.MarkReported("c2"); err != nil {
+		t.Fatalf("MarkReported: %v", err)
+	}
+	reports, _ = store.Recent(1)
+	if len(reports) != 1 || !reports[0].Reported {
+		t.Errorf("expected c2 marked reported, got %+v", reports)
+	}
+}
+
+func TestParseSentryDSN(t *testing.T) {
+	endpoint, key, err := parseSentryDSN("https://abc123@sentry.example.com/42")
+	if err != nil {
+		t.Fatalf("parseSentryDSN: %v", err)
+	}
+	if endpoint != "https://sentry.example.com/api/42/store/" {
+		t.Errorf("unexpected endpoint: %s", endpoint)
+	}
+	if key != "abc123" {
+		t.Errorf("unexpected key: %s", key)
+	}
+
+	for _, bad := range []string{"https://sentry.example.com/42", "https://abc123@sentry.example.com/"} {
+		if _, _, err := parseSentryDSN(bad); err == nil {
+			t.Errorf("expected error for DSN %q", bad)
+		}
+	}
+}
+
+func TestSendSentryEvent(t *testing.T) {
+	var gotPath, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("X-Sentry-Auth")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	dsn := "http://testkey@" + u.Host + "/42"
+	report := &CrashReport{ID: "c1", Component: "message-loop", Message: "boom",
+		Stack: "stack", CreatedAt: time.Now()}
+
+	if err := sendSentryEvent(context.Background(), dsn, report); err != nil {
+		t.Fatalf("sendSentryEvent: %v", err)
+	}
+	if gotPath != "/api/42/store/" {
+		t.Errorf("unexpected store path: %s", gotPath)
+	}
+	if !strings.Contains(gotAuth, "sentry_key=testkey") {
+		t.Errorf("unexpected auth header: %s", gotAuth)
+	}
+}
+
+func TestBuildMinidump(t *testing.T) {
+	dump := buildMinidump([]byte("goroutine 1 [running]:\nmain.main()\n"))
+	for _, want := range []string{"=== runtime ===", "goroutines:", "=== stack ===", "goroutine 1 [running]"} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("minidump missing %q:\n%s", want, dump)
+		}
+	}
+}
+
+func TestBuildCrashAlert(t *testing.T) {
+	report := &CrashReport{ID: "c1", Component: "message-loop", Message: "nil pointer dereference"}
+	alert := buildCrashAlert(report, false)
+	for _, want := range []string{"Panic recovered in message-loop", "nil pointer dereference", "`c1`"} {
+		if !strings.Contains(alert, want) {
+			t.Errorf("alert missing %q:\n%s", want, alert)
+		}
+	}
+	if strings.Contains(alert, "Sentry") {
+		t.Error("alert should not mention Sentry without a DSN")
+	}
+	if !strings.Contains(buildCrashAlert(report, true), "Forwarded to Sentry") {
+		t.Error("expected Sentry note when forwarded")
+	}
+}
@@ -0,0 +1,574 @@
+// Package copilot – crm_tools.go implements a lightweight people/CRM skill:
+// contacts with notes and last-interaction timestamps, follow-up reminders
+// tied into the scheduler, and workspace isolation so personal and business
+// contacts stay separate.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/scheduler"
+)
+
+const (
+	// crmFollowupJobPrefix namespaces scheduler jobs created for follow-ups.
+	crmFollowupJobPrefix = "followup-"
+	// crmDefaultWorkspace is used when the caller does not name one.
+	crmDefaultWorkspace = "personal"
+)
+
+// crmSchema is the DDL for the CRM tables. Contacts are unique per
+// workspace so "Ana" in personal and "Ana" in business never collide.
+const crmSchema = `
+CREATE TABLE IF NOT EXISTS _contacts (
+	id TEXT PRIMARY KEY,
+	workspace TEXT NOT NULL DEFAULT 'personal',
+	name TEXT NOT NULL,
+	company TEXT NOT NULL DEFAULT '',
+	tags TEXT NOT NULL DEFAULT '',
+	last_interaction TEXT NOT NULL DEFAULT '',
+	created_at TEXT NOT NULL DEFAULT (datetime('now')),
+	updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_workspace_name ON _contacts(workspace, name);
+
+CREATE TABLE IF NOT EXISTS _contact_notes (
+	id TEXT PRIMARY KEY,
+	contact_id TEXT NOT NULL,
+	note TEXT NOT NULL,
+	created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+CREATE INDEX IF NOT EXISTS idx_contact_notes_contact ON _contact_notes(contact_id);
+`
+
+// InitCRMTables creates the CRM tables if they don't exist.
+func (s *SkillDB) InitCRMTables() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.Exec(crmSchema); err != nil {
+		return fmt.Errorf("create crm tables: %w", err)
+	}
+	return nil
+}
+
+// ContactInfo is a contact row plus its recent notes.
+type ContactInfo struct {
+	ID              string
+	Workspace       string
+	Name            string
+	Company         string
+	Tags            string
+	LastInteraction string
+	CreatedAt       string
+	Notes           []ContactNote
+}
+
+// ContactNote is a single dated note on a contact.
+type ContactNote struct {
+	Note      string
+	CreatedAt string
+}
+
+// normalizeContactWorkspace lowercases a workspace name, defaulting to
+// "personal".
+func normalizeContactWorkspace(ws string) string {
+	ws = strings.ToLower(strings.TrimSpace(ws))
+	if ws == "" {
+		return crmDefaultWorkspace
+	}
+	return ws
+}
+
+// AddContact creates a contact, or updates company/tags if it already
+// exists in the workspace. Returns the contact ID.
+func (s *SkillDB) AddContact(workspace, name, company, tags string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workspace = normalizeContactWorkspace(workspace)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("contact name is required")
+	}
+
+	id := generateID()
+	_, err := s.db.Exec(`
+		INSERT INTO _contacts (id, workspace, name, company, tags)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(workspace, name) DO UPDATE SET
+			company = CASE WHEN excluded.company != '' THEN excluded.company ELSE company END,
+			tags = CASE WHEN excluded.tags != '' THEN excluded.tags ELSE tags END,
+			updated_at = datetime('now')
+	`, id, workspace, name, strings.TrimSpace(company), strings.TrimSpace(tags))
+	if err != nil {
+		return "", fmt.Errorf("add contact: %w", err)
+	}
+
+	// The upsert may have kept an existing row; read back the real ID.
+	var realID string
+	if err := s.db.QueryRow(
+		"SELECT id FROM _contacts WHERE workspace = ? AND name = ?",
+		workspace, name,
+	).Scan(&realID); err != nil {
+		return "", fmt.Errorf("read contact id: %w", err)
+	}
+	return realID, nil
+}
+
+// GetContact finds a contact by name or ID within a workspace, including
+// its notes (newest first).
+func (s *SkillDB) GetContact(workspace, nameOrID string) (*ContactInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.getContactLocked(workspace, nameOrID)
+}
+
+func (s *SkillDB) getContactLocked(workspace, nameOrID string) (*ContactInfo, error) {
+	workspace = normalizeContactWorkspace(workspace)
+	nameOrID = strings.TrimSpace(nameOrID)
+
+	var c ContactInfo
+	err := s.db.QueryRow(`
+		SELECT id, workspace, name, company, tags, last_interaction, created_at
+		FROM _contacts
+		WHERE workspace = ? AND (name = ? COLLATE NOCASE OR id = ?)
+	`, workspace, nameOrID, nameOrID).Scan(
+		&c.ID, &c.Workspace, &c.Name, &c.Company, &c.Tags, &c.LastInteraction, &c.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("contact %q not found in workspace %q", nameOrID, workspace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get contact: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT note, created_at FROM _contact_notes
+		WHERE contact_id = ? ORDER BY created_at DESC LIMIT 20
+	`, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get contact notes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var n ContactNote
+		if err := rows.Scan(&n.Note, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		c.Notes = append(c.Notes, n)
+	}
+	return &c, rows.Err()
+}
+
+// AddContactNote appends a dated note to a contact and bumps its
+// last-interaction timestamp.
+func (s *SkillDB) AddContactNote(workspace, nameOrID, note string) (*ContactInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, fmt.Errorf("note is required")
+	}
+
+	c, err := s.getContactLocked(workspace, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(
+		"INSERT INTO _contact_notes (id, contact_id, note) VALUES (?, ?, ?)",
+		generateID(), c.ID, note,
+	); err != nil {
+		return nil, fmt.Errorf("add note: %w", err)
+	}
+	if _, err := s.db.Exec(
+		"UPDATE _contacts SET last_interaction = datetime('now'), updated_at = datetime('now') WHERE id = ?",
+		c.ID,
+	); err != nil {
+		return nil, fmt.Errorf("touch contact: %w", err)
+	}
+	return c, nil
+}
+
+// ListContacts returns a workspace's contacts, most recently touched first.
+func (s *SkillDB) ListContacts(workspace string) ([]ContactInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	workspace = normalizeContactWorkspace(workspace)
+	rows, err := s.db.Query(`
+		SELECT id, workspace, name, company, tags, last_interaction, created_at
+		FROM _contacts
+		WHERE workspace = ?
+		ORDER BY last_interaction DESC, name
+	`, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("list contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []ContactInfo
+	for rows.Next() {
+		var c ContactInfo
+		if err := rows.Scan(&c.ID, &c.Workspace, &c.Name, &c.Company, &c.Tags, &c.LastInteraction, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan contact: %w", err)
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+// SearchContacts matches a query against names, companies, tags, and note
+// text within one workspace.
+func (s *SkillDB) SearchContacts(workspace, query string) ([]ContactInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	workspace = normalizeContactWorkspace(workspace)
+	pattern := "%" + strings.TrimSpace(query) + "%"
+	rows, err := s.db.Query(`
+		SELECT DISTINCT c.id, c.workspace, c.name, c.company, c.tags, c.last_interaction, c.created_at
+		FROM _contacts c
+		LEFT JOIN _contact_notes n ON n.contact_id = c.id
+		WHERE c.workspace = ? AND (
+			c.name LIKE ? COLLATE NOCASE OR
+			c.company LIKE ? COLLATE NOCASE OR
+			c.tags LIKE ? COLLATE NOCASE OR
+			n.note LIKE ? COLLATE NOCASE
+		)
+		ORDER BY c.last_interaction DESC, c.name
+	`, workspace, pattern, pattern, pattern, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("search contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []ContactInfo
+	for rows.Next() {
+		var c ContactInfo
+		if err := rows.Scan(&c.ID, &c.Workspace, &c.Name, &c.Company, &c.Tags, &c.LastInteraction, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan contact: %w", err)
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+// RemoveContact deletes a contact and its notes.
+func (s *SkillDB) RemoveContact(workspace, nameOrID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.getContactLocked(workspace, nameOrID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("DELETE FROM _contact_notes WHERE contact_id = ?", c.ID); err != nil {
+		return fmt.Errorf("remove notes: %w", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM _contacts WHERE id = ?", c.ID); err != nil {
+		return fmt.Errorf("remove contact: %w", err)
+	}
+	return nil
+}
+
+// parseFollowupWhen accepts a relative delay ("2w", "3d", "48h") or an
+// absolute YYYY-MM-DD date and returns the follow-up time.
+func parseFollowupWhen(when string) (time.Time, error) {
+	when = strings.ToLower(strings.TrimSpace(when))
+	if when == "" {
+		return time.Time{}, fmt.Errorf("when is required (e.g. '2w', '3d', '2026-09-15')")
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(when, "%dw", &n); err == nil && n > 0 {
+		return time.Now().AddDate(0, 0, n*7), nil
+	}
+	if _, err := fmt.Sscanf(when, "%dd", &n); err == nil && n > 0 {
+		return time.Now().AddDate(0, 0, n), nil
+	}
+	if d, err := time.ParseDuration(when); err == nil && d > 0 {
+		return time.Now().Add(d), nil
+	}
+	if t, err := time.Parse("2006-01-02", when); err == nil {
+		// Follow up mid-morning, not at midnight.
+		return t.Add(9 * time.Hour), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid follow-up time %q (use '2w', '3d', '48h', or YYYY-MM-DD)", when)
+}
+
+// contactFollowupCommand builds the agent prompt a follow-up job runs.
+func contactFollowupCommand(workspace, name, about string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Follow-up reminder: reach out to %s", name)
+	if about != "" {
+		fmt.Fprintf(&b, " about %s", about)
+	}
+	fmt.Fprintf(&b, ". Call contact_show with workspace %q and name %q first and include their recent notes for context, then remind the user.", workspace, name)
+	return b.String()
+}
+
+// contactTable renders contacts as a table result.
+func contactTable(title string, contacts []ContactInfo) *StructuredResult {
+	table := NewTableResult(title, []string{"Name", "Company", "Tags", "Last interaction"})
+	for _, c := range contacts {
+		last := c.LastInteraction
+		if last == "" {
+			last = "never"
+		}
+		table.AddRow(c.Name, c.Company, c.Tags, last)
+	}
+	return table
+}
+
+// RegisterCRMTools registers the people/CRM tools in the executor.
+func RegisterCRMTools(executor *ToolExecutor, skillDB *SkillDB, sched *scheduler.Scheduler) {
+	if skillDB == nil {
+		return
+	}
+
+	// contact_add
+	executor.Register(
+		MakeToolDefinition("contact_add", "Add a contact (or update company/tags of an existing one). Use workspace 'business' for work contacts; default is 'personal'.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"type":        "string",
+					"description": "The contact's name",
+				},
+				"company": map[string]any{
+					"type":        "string",
+					"description": "Company or affiliation (optional)",
+				},
+				"tags": map[string]any{
+					"type":        "string",
+					"description": "Comma-separated tags, e.g. 'client, design' (optional)",
+				},
+				"workspace": map[string]any{
+					"type":        "string",
+					"description": "Contact workspace: 'personal' (default) or 'business'",
+				},
+			},
+			"required": []string{"name"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			name, _ := args["name"].(string)
+			company, _ := args["company"].(string)
+			tags, _ := args["tags"].(string)
+			workspace, _ := args["workspace"].(string)
+
+			if _, err := skillDB.AddContact(workspace, name, company, tags); err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Contact %q saved in workspace %q.", strings.TrimSpace(name), normalizeContactWorkspace(workspace)), nil
+		},
+	)
+
+	// contact_note
+	executor.Register(
+		MakeToolDefinition("contact_note", "Add a dated note to a contact (meeting outcome, preference, context). Updates their last-interaction timestamp.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"contact": map[string]any{
+					"type":        "string",
+					"description": "Contact name or ID",
+				},
+				"note": map[string]any{
+					"type":        "string",
+					"description": "The note text",
+				},
+				"workspace": map[string]any{
+					"type":        "string",
+					"description": "Contact workspace: 'personal' (default) or 'business'",
+				},
+			},
+			"required": []string{"contact", "note"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			contact, _ := args["contact"].(string)
+			note, _ := args["note"].(string)
+			workspace, _ := args["workspace"].(string)
+
+			c, err := skillDB.AddContactNote(workspace, contact, note)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Note added to %s.", c.Name), nil
+		},
+	)
+
+	// contact_show
+	executor.Register(
+		MakeToolDefinition("contact_show", "Show a contact with their recent notes, or list all contacts in a workspace when no contact is given. Searches name, company, tags, and notes when 'query' is set.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"contact": map[string]any{
+					"type":        "string",
+					"description": "Contact name or ID to show in detail (optional)",
+				},
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Free-text search over names, companies, tags, and notes (optional)",
+				},
+				"workspace": map[string]any{
+					"type":        "string",
+					"description": "Contact workspace: 'personal' (default) or 'business'",
+				},
+			},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			contact, _ := args["contact"].(string)
+			query, _ := args["query"].(string)
+			workspace, _ := args["workspace"].(string)
+
+			if contact != "" {
+				c, err := skillDB.GetContact(workspace, contact)
+				if err != nil {
+					return nil, err
+				}
+				var b strings.Builder
+				fmt.Fprintf(&b, "*%s*", c.Name)
+				if c.Company != "" {
+					fmt.Fprintf(&b, " — %s", c.Company)
+				}
+				if c.Tags != "" {
+					fmt.Fprintf(&b, "\nTags: %s", c.Tags)
+				}
+				if c.LastInteraction != "" {
+					fmt.Fprintf(&b, "\nLast interaction: %s", c.LastInteraction)
+				}
+				for _, n := range c.Notes {
+					fmt.Fprintf(&b, "\n- [%s] %s", n.CreatedAt, n.Note)
+				}
+				return b.String(), nil
+			}
+
+			var (
+				contacts []ContactInfo
+				err      error
+				title    = fmt.Sprintf("Contacts (%s)", normalizeContactWorkspace(workspace))
+			)
+			if query != "" {
+				contacts, err = skillDB.SearchContacts(workspace, query)
+				title = fmt.Sprintf("Contacts matching %q (%s)", query, normalizeContactWorkspace(workspace))
+			} else {
+				contacts, err = skillDB.ListContacts(workspace)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(contacts) == 0 {
+				return "No contacts found.", nil
+			}
+			return contactTable(title, contacts), nil
+		},
+	)
+
+	// contact_followup
+	executor.Register(
+		MakeToolDefinition("contact_followup", "Schedule a follow-up reminder for a contact, e.g. 'ping Ana about the proposal in 2 weeks'.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"contact": map[string]any{
+					"type":        "string",
+					"description": "Contact name or ID",
+				},
+				"when": map[string]any{
+					"type":        "string",
+					"description": "When to follow up: '2w', '3d', '48h', or YYYY-MM-DD",
+				},
+				"about": map[string]any{
+					"type":        "string",
+					"description": "What the follow-up is about (optional)",
+				},
+				"workspace": map[string]any{
+					"type":        "string",
+					"description": "Contact workspace: 'personal' (default) or 'business'",
+				},
+			},
+			"required": []string{"contact", "when"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			contact, _ := args["contact"].(string)
+			when, _ := args["when"].(string)
+			about, _ := args["about"].(string)
+			workspace, _ := args["workspace"].(string)
+
+			if sched == nil {
+				return nil, fmt.Errorf("scheduler is not enabled")
+			}
+			c, err := skillDB.GetContact(workspace, contact)
+			if err != nil {
+				return nil, err
+			}
+			at, err := parseFollowupWhen(when)
+			if err != nil {
+				return nil, err
+			}
+
+			dt := DeliveryTargetFromContext(ctx)
+			jobID := crmFollowupJobPrefix + generateID()
+			job := &scheduler.Job{
+				ID:             jobID,
+				Schedule:       at.Format(time.RFC3339),
+				Type:           "at",
+				Command:        contactFollowupCommand(c.Workspace, c.Name, strings.TrimSpace(about)),
+				Channel:        dt.Channel,
+				ChatID:         dt.ChatID,
+				Enabled:        true,
+				IsolateSession: true,
+				Announce:       true,
+			}
+			if err := sched.Add(job); err != nil {
+				return nil, fmt.Errorf("scheduling follow-up: %w", err)
+			}
+			return fmt.Sprintf("Follow-up for %s scheduled for %s (job %q). Remove it with cron_remove.",
+				c.Name, at.Format("2006-01-02 15:04"), jobID), nil
+		},
+	)
+
+	// contact_remove
+	executor.Register(
+		MakeToolDefinition("contact_remove", "Delete a contact and all their notes. Requires confirm=true.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"contact": map[string]any{
+					"type":        "string",
+					"description": "Contact name or ID",
+				},
+				"workspace": map[string]any{
+					"type":        "string",
+					"description": "Contact workspace: 'personal' (default) or 'business'",
+				},
+				"confirm": map[string]any{
+					"type":        "boolean",
+					"description": "Must be true to actually delete",
+				},
+			},
+			"required": []string{"contact", "confirm"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			contact, _ := args["contact"].(string)
+			workspace, _ := args["workspace"].(string)
+			confirm, _ := args["confirm"].(bool)
+
+			if !confirm {
+				return nil, fmt.Errorf("set confirm=true to delete the contact and their notes")
+			}
+			c, err := skillDB.GetContact(workspace, contact)
+			if err != nil {
+				return nil, err
+			}
+			if err := skillDB.RemoveContact(workspace, contact); err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Contact %s removed.", c.Name), nil
+		},
+	)
+}
@@ -0,0 +1,182 @@
+// Package copilot – crm_tools_test.go contains unit tests for the CRM skill.
+package copilot
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestCRMDB(t *testing.T) *SkillDB {
+	t.Helper()
+	db, err := OpenSkillDatabase(t.TempDir())
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitCRMTables(); err != nil {
+		t.Fatalf("init crm tables: %v", err)
+	}
+	return db
+}
+
+func TestAddContactUpsert(t *testing.T) {
+	db := newTestCRMDB(t)
+
+	id1, err := db.AddContact("", "Ana", "Acme", "client")
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	// Re-adding updates company/tags but keeps the same contact.
+	id2, err := db.AddContact("personal", "Ana", "Globex", "")
+	if err != nil {
+		t.Fatalf("re-add: %v", err)
+	}
+	if id1 != id2 {
+		t.Errorf("expected same id on upsert, got %q and %q", id1, id2)
+	}
+
+	c, err := db.GetContact("", "ana")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if c.Company != "Globex" || c.Tags != "client" {
+		t.Errorf("unexpected contact after upsert: %+v", c)
+	}
+
+	if _, err := db.AddContact("", "  ", "", ""); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
+
+func TestWorkspaceIsolation(t *testing.T) {
+	db := newTestCRMDB(t)
+
+	if _, err := db.AddContact("personal", "Ana", "", "friend"); err != nil {
+		t.Fatalf("add personal: %v", err)
+	}
+	if _, err := db.AddContact("business", "Ana", "Acme", "client"); err != nil {
+		t.Fatalf("add business: %v", err)
+	}
+
+	personal, err := db.GetContact("personal", "Ana")
+	if err != nil {
+		t.Fatalf("get personal: %v", err)
+	}
+	business, err := db.GetContact("business", "Ana")
+	if err != nil {
+		t.Fatalf("get business: %v", err)
+	}
+	if personal.ID == business.ID {
+		t.Error("expected distinct contacts per workspace")
+	}
+	if business.Company != "Acme" || personal.Company != "" {
+		t.Errorf("workspaces leaked: personal=%+v business=%+v", personal, business)
+	}
+
+	contacts, err := db.ListContacts("business")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].Tags != "client" {
+		t.Errorf("unexpected business contacts: %+v", contacts)
+	}
+}
+
+func TestContactNotesAndSearch(t *testing.T) {
+	db := newTestCRMDB(t)
+
+	if _, err := db.AddContact("", "Ana", "Acme", ""); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := db.AddContact("", "Bruno", "", ""); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	if _, err := db.AddContactNote("", "Ana", "Sent the proposal, waiting on budget approval"); err != nil {
+		t.Fatalf("note: %v", err)
+	}
+	if _, err := db.AddContactNote("", "nobody", "x"); err == nil {
+		t.Error("expected error for unknown contact")
+	}
+
+	c, err := db.GetContact("", "Ana")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(c.Notes) != 1 || c.Notes[0].Note != "Sent the proposal, waiting on budget approval" {
+		t.Errorf("unexpected notes: %+v", c.Notes)
+	}
+	if c.LastInteraction == "" {
+		t.Error("expected last_interaction to be set after a note")
+	}
+
+	// Search matches note text, not just names.
+	results, err := db.SearchContacts("", "proposal")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "Ana" {
+		t.Errorf("unexpected search results: %+v", results)
+	}
+	results, err = db.SearchContacts("", "acme")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected company match, got %+v", results)
+	}
+}
+
+func TestRemoveContact(t *testing.T) {
+	db := newTestCRMDB(t)
+
+	if _, err := db.AddContact("", "Ana", "", ""); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := db.AddContactNote("", "Ana", "note"); err != nil {
+		t.Fatalf("note: %v", err)
+	}
+	if err := db.RemoveContact("", "Ana"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := db.GetContact("", "Ana"); err == nil {
+		t.Error("expected contact to be gone")
+	}
+	if err := db.RemoveContact("", "Ana"); err == nil {
+		t.Error("expected error removing missing contact")
+	}
+}
+
+func TestParseFollowupWhen(t *testing.T) {
+	now := time.Now()
+
+	at, err := parseFollowupWhen("2w")
+	if err != nil {
+		t.Fatalf("2w: %v", err)
+	}
+	if d := at.Sub(now); d < 13*24*time.Hour || d > 15*24*time.Hour {
+		t.Errorf("2w resolved to %v from now", d)
+	}
+
+	at, err = parseFollowupWhen("3d")
+	if err != nil {
+		t.Fatalf("3d: %v", err)
+	}
+	if d := at.Sub(now); d < 2*24*time.Hour || d > 4*24*time.Hour {
+		t.Errorf("3d resolved to %v from now", d)
+	}
+
+	at, err = parseFollowupWhen("2026-09-15")
+	if err != nil {
+		t.Fatalf("date: %v", err)
+	}
+	if at.Format("2006-01-02 15:04") != "2026-09-15 09:00" {
+		t.Errorf("unexpected date resolution: %v", at)
+	}
+
+	for _, bad := range []string{"", "soon", "-1d"} {
+		if _, err := parseFollowupWhen(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 // Package copilot – daemon_manager.go implements a process manager that lets
 // the agent start, monitor, and control long-running background processes
 // (dev servers, watchers, database engines, etc.) with ring-buffer output
-// capture and health checking.
+// capture, disk spill with rotation, crash detection, and health checking.
 package copilot
 
 import (
@@ -9,16 +9,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/paths"
 )
 
 const (
 	defaultRingSize = 500
 	healthCheckFreq = 30 * time.Second
+
+	// daemonLogMaxSize is the spill file size that triggers rotation.
+	// One rotated backup (<label>.log.1) is kept per daemon.
+	daemonLogMaxSize = 5 << 20 // 5 MB
+
+	// crashReportLines is how much output a crash notification includes.
+	crashReportLines = 50
 )
 
 // Daemon represents a managed background process.
@@ -31,11 +42,23 @@ type Daemon struct {
 	StartedAt   time.Time `json:"started_at"`
 	ExitCode    int       `json:"exit_code,omitempty"`
 	Error       string    `json:"error,omitempty"`
+	LogFile     string    `json:"log_file,omitempty"`
 
 	cmd        *exec.Cmd
 	ringBuffer *ringBuffer
+	spill      *spillWriter
 	cancel     context.CancelFunc
 	done       chan struct{}
+
+	// stopping is set when the daemon is stopped deliberately, so the exit
+	// is not reported as a crash.
+	stopping bool
+
+	// sessionID, channel, and chatID route crash notifications back to the
+	// conversation that started the daemon.
+	sessionID string
+	channel   string
+	chatID    string
 }
 
 // DaemonManager manages a set of background daemons.
@@ -43,6 +66,13 @@ type DaemonManager struct {
 	mu      sync.RWMutex
 	daemons map[string]*Daemon
 	stopCh  chan struct{}
+
+	// logDir is where daemon output spills to disk (one file per label).
+	logDir string
+
+	// onCrash is invoked when a daemon exits unexpectedly, with the last
+	// crashReportLines of output. Delivered to the owning session.
+	onCrash func(sessionID, channel, chatID, content string)
 }
 
 // NewDaemonManager creates a new daemon manager.
@@ -50,11 +80,33 @@ func NewDaemonManager() *DaemonManager {
 	dm := &DaemonManager{
 		daemons: make(map[string]*Daemon),
 		stopCh:  make(chan struct{}),
+		logDir:  filepath.Join(paths.ResolveDataDir(), "daemon-logs"),
 	}
 	go dm.healthLoop()
 	return dm
 }
 
+// SetCrashNotifier sets the callback invoked when a daemon crashes. The
+// callback receives the owning session and a report with the last lines
+// of output.
+func (dm *DaemonManager) SetCrashNotifier(fn func(sessionID, channel, chatID, content string)) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.onCrash = fn
+}
+
+// SetOwner records which session started a daemon, so crash notifications
+// can be routed back to it.
+func (dm *DaemonManager) SetOwner(label, sessionID, channel, chatID string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if d, ok := dm.daemons[label]; ok {
+		d.sessionID = sessionID
+		d.channel = channel
+		d.chatID = chatID
+	}
+}
+
 // StartDaemon starts a new background process.
 func (dm *DaemonManager) StartDaemon(label, command string, port int, readyPattern string) (*Daemon, error) {
 	dm.mu.Lock()
@@ -70,11 +122,31 @@ func (dm *DaemonManager) StartDaemon(label, command string, port int, readyPatte
 	cmd := exec.CommandContext(ctx, "bash", "-c", command)
 
 	rb := newRingBuffer(defaultRingSize)
-	cmd.Stdout = rb
-	cmd.Stderr = rb
+
+	// Spill output to a rotating log file so lines that scroll out of the
+	// ring buffer remain queryable. Best-effort: the daemon still runs if
+	// the spill file can't be created.
+	var spill *spillWriter
+	logFile := ""
+	if dm.logDir != "" {
+		if err := os.MkdirAll(dm.logDir, 0700); err == nil {
+			logFile = filepath.Join(dm.logDir, label+".log")
+			spill = newSpillWriter(logFile, daemonLogMaxSize)
+		}
+	}
+
+	var output io.Writer = rb
+	if spill != nil {
+		output = io.MultiWriter(rb, spill)
+	}
+	cmd.Stdout = output
+	cmd.Stderr = output
 
 	if err := cmd.Start(); err != nil {
 		cancel()
+		if spill != nil {
+			spill.Close()
+		}
 		return nil, fmt.Errorf("starting daemon %q: %w", label, err)
 	}
 
@@ -85,8 +157,10 @@ func (dm *DaemonManager) StartDaemon(label, command string, port int, readyPatte
 		Port:       port,
 		Status:     "running",
 		StartedAt:  time.Now(),
+		LogFile:    logFile,
 		cmd:        cmd,
 		ringBuffer: rb,
+		spill:      spill,
 		cancel:     cancel,
 		done:       make(chan struct{}),
 	}
@@ -95,7 +169,6 @@ func (dm *DaemonManager) StartDaemon(label, command string, port int, readyPatte
 	go func() {
 		err := cmd.Wait()
 		dm.mu.Lock()
-		defer dm.mu.Unlock()
 		d.Status = "stopped"
 		if err != nil {
 			d.Status = "failed"
@@ -104,6 +177,30 @@ func (dm *DaemonManager) StartDaemon(label, command string, port int, readyPatte
 		if cmd.ProcessState != nil {
 			d.ExitCode = cmd.ProcessState.ExitCode()
 		}
+		if d.spill != nil {
+			d.spill.Close()
+		}
+		crashed := !d.stopping && (d.Status == "failed" || d.ExitCode != 0)
+		sessionID, channel, chatID := d.sessionID, d.channel, d.chatID
+		status, exitCode, errMsg := d.Status, d.ExitCode, d.Error
+		onCrash := dm.onCrash
+		dm.mu.Unlock()
+
+		// Crash detection: notify the owning session with the tail of the
+		// daemon's output. Runs before done is closed so waiters observe
+		// the notification as already sent.
+		if crashed && onCrash != nil && sessionID != "" {
+			lines := rb.Lines()
+			if len(lines) > crashReportLines {
+				lines = lines[len(lines)-crashReportLines:]
+			}
+			report := fmt.Sprintf("[daemon:%s] crashed (status: %s, exit code: %d", label, status, exitCode)
+			if errMsg != "" {
+				report += ", error: " + errMsg
+			}
+			report += fmt.Sprintf(")\n\nLast %d lines of output:\n%s", len(lines), strings.Join(lines, "\n"))
+			onCrash(sessionID, channel, chatID, report)
+		}
 		close(d.done)
 	}()
 
@@ -138,6 +235,10 @@ func (dm *DaemonManager) StartDaemon(label, command string, port int, readyPatte
 func (dm *DaemonManager) StopDaemon(label string, force bool) error {
 	dm.mu.Lock()
 	d, ok := dm.daemons[label]
+	if ok {
+		// Deliberate stop: don't report the exit as a crash.
+		d.stopping = true
+	}
 	dm.mu.Unlock()
 
 	if !ok {
@@ -178,6 +279,7 @@ func (dm *DaemonManager) RestartDaemon(label string) (*Daemon, error) {
 
 	cmd := d.Command
 	port := d.Port
+	sessionID, channel, chatID := d.sessionID, d.channel, d.chatID
 
 	if d.Status == "running" {
 		if err := dm.StopDaemon(label, false); err != nil {
@@ -186,7 +288,12 @@ func (dm *DaemonManager) RestartDaemon(label string) (*Daemon, error) {
 		time.Sleep(500 * time.Millisecond)
 	}
 
-	return dm.StartDaemon(label, cmd, port, "")
+	restarted, err := dm.StartDaemon(label, cmd, port, "")
+	if err != nil {
+		return nil, err
+	}
+	dm.SetOwner(label, sessionID, channel, chatID)
+	return restarted, nil
 }
 
 // GetLogs returns the last n lines from a daemon's output ring buffer.
@@ -200,6 +307,15 @@ func (dm *DaemonManager) GetLogs(label string, n int, filter string) (string, er
 	}
 
 	lines := d.ringBuffer.Lines()
+
+	// The ring buffer only holds the most recent output; fall back to the
+	// on-disk spill file when more lines were requested than it retains.
+	if d.LogFile != "" && (n <= 0 || n > len(lines)) {
+		if diskLines, err := tailSpillFile(d.LogFile, n); err == nil && len(diskLines) > len(lines) {
+			lines = diskLines
+		}
+	}
+
 	if n > 0 && n < len(lines) {
 		lines = lines[len(lines)-n:]
 	}
@@ -342,6 +458,120 @@ func (rb *ringBuffer) String() string {
 // Ensure ringBuffer implements io.Writer.
 var _ io.Writer = (*ringBuffer)(nil)
 
+// ---------- Disk Spill ----------
+
+// spillWriter appends daemon output to a log file, rotating it when it
+// grows past maxSize. One rotated backup (<path>.1) is kept; older output
+// is discarded.
+type spillWriter struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+	failed  bool
+}
+
+// newSpillWriter creates a spill writer for the given path. The file is
+// opened lazily on first write.
+func newSpillWriter(path string, maxSize int64) *spillWriter {
+	return &spillWriter{path: path, maxSize: maxSize}
+}
+
+func (sw *spillWriter) Write(p []byte) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	// A previous open/rotate failure disables the spill permanently for
+	// this daemon run; output capture must never block the process.
+	if sw.failed {
+		return len(p), nil
+	}
+
+	if sw.file == nil {
+		f, err := os.OpenFile(sw.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			sw.failed = true
+			return len(p), nil
+		}
+		sw.file = f
+		if info, err := f.Stat(); err == nil {
+			sw.size = info.Size()
+		}
+	}
+
+	n, err := sw.file.Write(p)
+	sw.size += int64(n)
+	if err != nil {
+		sw.failed = true
+		return len(p), nil
+	}
+
+	if sw.size >= sw.maxSize {
+		sw.rotateLocked()
+	}
+	return len(p), nil
+}
+
+// rotateLocked closes the current file, moves it to <path>.1 (replacing any
+// previous backup), and starts a fresh file. Caller must hold sw.mu.
+func (sw *spillWriter) rotateLocked() {
+	sw.file.Close()
+	sw.file = nil
+	sw.size = 0
+	if err := os.Rename(sw.path, sw.path+".1"); err != nil {
+		sw.failed = true
+	}
+}
+
+// Close flushes and closes the spill file.
+func (sw *spillWriter) Close() {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if sw.file != nil {
+		sw.file.Close()
+		sw.file = nil
+	}
+}
+
+// Ensure spillWriter implements io.Writer.
+var _ io.Writer = (*spillWriter)(nil)
+
+// tailSpillFile returns the last n lines from a spill file, reading the
+// rotated backup first when the current file alone doesn't have enough
+// lines. n <= 0 returns everything available.
+func tailSpillFile(path string, n int) ([]string, error) {
+	lines, err := readLogLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if n <= 0 || len(lines) < n {
+		if backup, err := readLogLines(path + ".1"); err == nil {
+			lines = append(backup, lines...)
+		}
+	}
+
+	if n > 0 && n < len(lines) {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// readLogLines reads a log file and splits it into lines, dropping a
+// trailing empty line from the final newline.
+func readLogLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
+
 // ---------- Tool Registration ----------
 
 // RegisterDaemonTools registers daemon management tools in the executor.
@@ -362,7 +592,7 @@ func RegisterDaemonTools(executor *ToolExecutor, dm *DaemonManager) {
 				"required": []string{"command", "label"},
 			}),
 		},
-	}, func(_ context.Context, args map[string]any) (any, error) {
+	}, func(ctx context.Context, args map[string]any) (any, error) {
 		command, _ := args["command"].(string)
 		label, _ := args["label"].(string)
 		port, _ := args["port"].(float64)
@@ -372,6 +602,11 @@ func RegisterDaemonTools(executor *ToolExecutor, dm *DaemonManager) {
 		if err != nil {
 			return nil, err
 		}
+
+		// Record the owning session so crash notifications land in this chat.
+		target := DeliveryTargetFromContext(ctx)
+		dm.SetOwner(label, SessionIDFromContext(ctx), target.Channel, target.ChatID)
+
 		return fmt.Sprintf("Daemon %q started (PID %d, port %d, status: %s)", d.Label, d.PID, d.Port, d.Status), nil
 	})
 
@@ -379,7 +614,7 @@ func RegisterDaemonTools(executor *ToolExecutor, dm *DaemonManager) {
 		Type: "function",
 		Function: FunctionDef{
 			Name:        "daemon_logs",
-			Description: "Get the last N lines of output from a running daemon. Supports regex filtering.",
+			Description: "Get the last N lines of output from a running daemon. Supports regex filtering. Reads the in-memory ring buffer, falling back to the on-disk spill file for older lines.",
 			Parameters: mustJSON(map[string]any{
 				"type": "object",
 				"properties": map[string]any{
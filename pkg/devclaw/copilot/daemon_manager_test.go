@@ -0,0 +1,111 @@
+package copilot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSpillWriterRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	// Small max size so a few writes trigger rotation.
+	sw := newSpillWriter(path, 64)
+	defer sw.Close()
+
+	for i := 0; i < 10; i++ {
+		if _, err := sw.Write([]byte(strings.Repeat("x", 20) + "\n")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	sw.Close()
+
+	// Rotation must have produced a backup file.
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected rotated backup %s.1: %v", path, err)
+	}
+
+	// Current file must be under the rotation threshold.
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected current spill file: %v", err)
+	}
+	if info.Size() >= 64+21 {
+		t.Errorf("current file too large after rotation: %d bytes", info.Size())
+	}
+}
+
+func TestTailSpillFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	if err := os.WriteFile(path+".1", []byte("old1\nold2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("new1\nnew2\nnew3\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tail within the current file.
+	lines, err := tailSpillFile(path, 2)
+	if err != nil {
+		t.Fatalf("tail failed: %v", err)
+	}
+	if len(lines) != 2 || lines[0] != "new2" || lines[1] != "new3" {
+		t.Errorf("unexpected tail: %v", lines)
+	}
+
+	// Tail spanning into the rotated backup.
+	lines, err = tailSpillFile(path, 4)
+	if err != nil {
+		t.Fatalf("tail failed: %v", err)
+	}
+	if len(lines) != 4 || lines[0] != "old2" || lines[3] != "new3" {
+		t.Errorf("expected tail to span the backup, got: %v", lines)
+	}
+
+	// n <= 0 returns everything, backup included.
+	lines, err = tailSpillFile(path, 0)
+	if err != nil {
+		t.Fatalf("tail failed: %v", err)
+	}
+	if len(lines) != 5 {
+		t.Errorf("expected 5 lines total, got %d: %v", len(lines), lines)
+	}
+}
+
+func TestDaemonCrashNotification(t *testing.T) {
+	dm := NewDaemonManager()
+	dm.logDir = t.TempDir()
+	defer dm.Shutdown()
+
+	notified := make(chan string, 1)
+	dm.SetCrashNotifier(func(sessionID, channel, chatID, content string) {
+		notified <- sessionID + "|" + content
+	})
+
+	d, err := dm.StartDaemon("crash-test", "echo boom; exit 3", 0, "")
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	dm.SetOwner("crash-test", "test-session", "whatsapp", "chat1")
+
+	<-d.done
+
+	select {
+	case report := <-notified:
+		if !strings.HasPrefix(report, "test-session|") {
+			t.Errorf("expected owner session in report, got %q", report)
+		}
+		if !strings.Contains(report, "exit code: 3") {
+			t.Errorf("expected exit code in report, got %q", report)
+		}
+		if !strings.Contains(report, "boom") {
+			t.Errorf("expected output tail in report, got %q", report)
+		}
+	default:
+		t.Fatal("expected crash notification")
+	}
+}
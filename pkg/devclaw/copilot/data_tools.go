@@ -0,0 +1,607 @@
+// Package copilot – data_tools.go implements lightweight data analysis over
+// CSV and Parquet files. Datasets load into an in-memory SQLite database so
+// the agent can describe and aggregate them with SQL; charts render to PNG
+// via gonum/plot and go back to the user through the media service.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// maxDataRows caps how many rows a single dataset load will ingest.
+const maxDataRows = 100000
+
+// dataColumn describes one column of a loaded dataset.
+type dataColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // INTEGER, REAL, or TEXT
+}
+
+// DataAnalyzer holds loaded datasets in an in-memory SQLite database.
+type DataAnalyzer struct {
+	mu     sync.Mutex
+	db     *sql.DB
+	tables map[string][]dataColumn
+}
+
+// NewDataAnalyzer creates an empty analyzer. The database is opened lazily
+// on the first load.
+func NewDataAnalyzer() *DataAnalyzer {
+	return &DataAnalyzer{tables: make(map[string][]dataColumn)}
+}
+
+// ensureDB opens the in-memory database on first use. A single connection is
+// enforced because each SQLite ":memory:" connection is its own database.
+func (da *DataAnalyzer) ensureDB() (*sql.DB, error) {
+	if da.db != nil {
+		return da.db, nil
+	}
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("opening in-memory database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	da.db = db
+	return db, nil
+}
+
+// Close releases the in-memory database and all loaded datasets.
+func (da *DataAnalyzer) Close() {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	if da.db != nil {
+		da.db.Close()
+		da.db = nil
+	}
+	da.tables = make(map[string][]dataColumn)
+}
+
+// Load ingests a CSV or Parquet file into a table. Parquet files are
+// converted through the duckdb CLI (matching the CLI-client approach of
+// db_tools.go) to avoid a heavy driver dependency.
+func (da *DataAnalyzer) Load(path, table string) ([]dataColumn, int, error) {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+
+	if table == "" {
+		table = dataTableName(path)
+	} else {
+		table = sanitizeDataIdent(table)
+	}
+
+	var reader *csv.Reader
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".parquet":
+		out, err := exec.Command("duckdb", "-csv", "-c",
+			fmt.Sprintf("SELECT * FROM read_parquet('%s')", strings.ReplaceAll(path, "'", "''"))).Output()
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading parquet (requires the duckdb CLI): %w", err)
+		}
+		reader = csv.NewReader(strings.NewReader(string(out)))
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("opening dataset: %w", err)
+		}
+		defer f.Close()
+		reader = csv.NewReader(f)
+	}
+
+	return da.loadCSV(reader, table)
+}
+
+// loadCSV reads all records, infers column types, and bulk-inserts into a
+// freshly created table. Caller must hold da.mu.
+func (da *DataAnalyzer) loadCSV(reader *csv.Reader, table string) ([]dataColumn, int, error) {
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading header: %w", err)
+	}
+
+	columns := make([]dataColumn, len(header))
+	for i, name := range header {
+		columns[i] = dataColumn{Name: sanitizeDataIdent(name), Type: "INTEGER"}
+		if columns[i].Name == "" {
+			columns[i].Name = fmt.Sprintf("col%d", i+1)
+		}
+	}
+
+	var records [][]string
+	for len(records) < maxDataRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading row %d: %w", len(records)+2, err)
+		}
+		// Narrow each column's inferred type: INTEGER → REAL → TEXT.
+		for i, val := range record {
+			if i >= len(columns) || val == "" {
+				continue
+			}
+			switch columns[i].Type {
+			case "INTEGER":
+				if _, err := strconv.ParseInt(val, 10, 64); err != nil {
+					if _, err := strconv.ParseFloat(val, 64); err == nil {
+						columns[i].Type = "REAL"
+					} else {
+						columns[i].Type = "TEXT"
+					}
+				}
+			case "REAL":
+				if _, err := strconv.ParseFloat(val, 64); err != nil {
+					columns[i].Type = "TEXT"
+				}
+			}
+		}
+		records = append(records, record)
+	}
+
+	db, err := da.ensureDB()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defs := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, col := range columns {
+		defs[i] = fmt.Sprintf("%q %s", col.Name, col.Type)
+		placeholders[i] = "?"
+	}
+	if _, err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %q", table)); err != nil {
+		return nil, 0, fmt.Errorf("dropping old table: %w", err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("CREATE TABLE %q (%s)", table, strings.Join(defs, ", "))); err != nil {
+		return nil, 0, fmt.Errorf("creating table: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, 0, fmt.Errorf("starting insert transaction: %w", err)
+	}
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %q VALUES (%s)", table, strings.Join(placeholders, ", ")))
+	if err != nil {
+		tx.Rollback()
+		return nil, 0, fmt.Errorf("preparing insert: %w", err)
+	}
+	for _, record := range records {
+		values := make([]any, len(columns))
+		for i := range columns {
+			if i >= len(record) || record[i] == "" {
+				values[i] = nil
+				continue
+			}
+			values[i] = dataCellValue(record[i], columns[i].Type)
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			stmt.Close()
+			tx.Rollback()
+			return nil, 0, fmt.Errorf("inserting row: %w", err)
+		}
+	}
+	stmt.Close()
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("committing insert: %w", err)
+	}
+
+	da.tables[table] = columns
+	return columns, len(records), nil
+}
+
+// Tables returns the loaded tables and their columns.
+func (da *DataAnalyzer) Tables() map[string][]dataColumn {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	out := make(map[string][]dataColumn, len(da.tables))
+	for name, cols := range da.tables {
+		out[name] = cols
+	}
+	return out
+}
+
+// dataCellValue converts a CSV cell to the SQLite value for its column type.
+func dataCellValue(raw, colType string) any {
+	switch colType {
+	case "INTEGER":
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return n
+		}
+	case "REAL":
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f
+		}
+	}
+	return raw
+}
+
+// sanitizeDataIdent reduces a name to a safe SQL identifier.
+func sanitizeDataIdent(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(strings.ToLower(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '_':
+			b.WriteRune('_')
+		}
+	}
+	ident := strings.Trim(b.String(), "_")
+	if ident != "" && ident[0] >= '0' && ident[0] <= '9' {
+		ident = "c" + ident
+	}
+	return ident
+}
+
+// dataTableName derives a table name from a dataset file path.
+func dataTableName(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if name := sanitizeDataIdent(base); name != "" {
+		return name
+	}
+	return "dataset"
+}
+
+// ---------- Tool Registration ----------
+
+// RegisterDataTools registers the data analysis toolset. channelMgr may be
+// nil; charts are then saved to disk without being sent to the chat.
+func RegisterDataTools(executor *ToolExecutor, analyzer *DataAnalyzer, channelMgr *channels.Manager, logger *slog.Logger) {
+	// data_load
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "data_load",
+			Description: "Load a CSV or Parquet file into an in-memory SQL table for analysis. Returns the table name, inferred column types, and row count. Query it afterwards with data_query.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path":  map[string]any{"type": "string", "description": "Path to the CSV or Parquet file"},
+					"table": map[string]any{"type": "string", "description": "Table name (default: derived from the filename)"},
+				},
+				"required": []string{"path"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		path, _ := args["path"].(string)
+		table, _ := args["table"].(string)
+
+		columns, rowCount, err := analyzer.Load(path, table)
+		if err != nil {
+			return nil, err
+		}
+		if table == "" {
+			table = dataTableName(path)
+		} else {
+			table = sanitizeDataIdent(table)
+		}
+		return map[string]any{
+			"table":   table,
+			"columns": columns,
+			"rows":    rowCount,
+		}, nil
+	})
+
+	// data_describe
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "data_describe",
+			Description: "Summarize a loaded dataset: column types, null counts, distinct counts, and min/max/avg for numeric columns. Omit table to list all loaded tables.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"table": map[string]any{"type": "string", "description": "Loaded table name (from data_load)"},
+				},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		table, _ := args["table"].(string)
+		tables := analyzer.Tables()
+
+		if table == "" {
+			if len(tables) == 0 {
+				return "No datasets loaded. Use data_load first.", nil
+			}
+			summary := make(map[string]any, len(tables))
+			for name, cols := range tables {
+				summary[name] = cols
+			}
+			return summary, nil
+		}
+
+		cols, ok := tables[sanitizeDataIdent(table)]
+		if !ok {
+			return nil, fmt.Errorf("table %q not loaded — use data_load first", table)
+		}
+		table = sanitizeDataIdent(table)
+
+		analyzer.mu.Lock()
+		db := analyzer.db
+		analyzer.mu.Unlock()
+
+		stats := make([]map[string]any, 0, len(cols))
+		for _, col := range cols {
+			stat := map[string]any{"name": col.Name, "type": col.Type}
+			row := db.QueryRowContext(ctx, fmt.Sprintf(
+				"SELECT COUNT(*), COUNT(%q), COUNT(DISTINCT %q) FROM %q", col.Name, col.Name, table))
+			var total, nonNull, distinct int
+			if err := row.Scan(&total, &nonNull, &distinct); err == nil {
+				stat["nulls"] = total - nonNull
+				stat["distinct"] = distinct
+			}
+			if col.Type == "INTEGER" || col.Type == "REAL" {
+				row := db.QueryRowContext(ctx, fmt.Sprintf(
+					"SELECT MIN(%q), MAX(%q), AVG(%q) FROM %q", col.Name, col.Name, col.Name, table))
+				var min, max, avg sql.NullFloat64
+				if err := row.Scan(&min, &max, &avg); err == nil && avg.Valid {
+					stat["min"] = min.Float64
+					stat["max"] = max.Float64
+					stat["avg"] = avg.Float64
+				}
+			}
+			stats = append(stats, stat)
+		}
+		return map[string]any{"table": table, "columns": stats}, nil
+	})
+
+	// data_query
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "data_query",
+			Description: "Run a SQL SELECT against the loaded datasets (SQLite dialect) for filtering and aggregation. Results come back as a Markdown table.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query":    map[string]any{"type": "string", "description": "SQL SELECT query over loaded tables"},
+					"max_rows": map[string]any{"type": "integer", "description": "Maximum rows to return (default: 50)"},
+				},
+				"required": []string{"query"},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		query, _ := args["query"].(string)
+		maxRows := toolArgInt(args, "max_rows", 50)
+
+		normalized := strings.ToUpper(strings.TrimSpace(query))
+		if !strings.HasPrefix(normalized, "SELECT") && !strings.HasPrefix(normalized, "WITH") {
+			return nil, fmt.Errorf("only SELECT queries are allowed with data_query")
+		}
+
+		analyzer.mu.Lock()
+		db := analyzer.db
+		analyzer.mu.Unlock()
+		if db == nil {
+			return nil, fmt.Errorf("no datasets loaded — use data_load first")
+		}
+
+		columns, rows, err := queryDataRows(ctx, db, ensureQueryLimit(query, maxRows))
+		if err != nil {
+			return nil, err
+		}
+		return formatRowsMarkdown(columns, rows), nil
+	})
+
+	// data_plot
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "data_plot",
+			Description: "Render a chart (bar, line, scatter) from a SQL query over loaded datasets and send it to the chat as a PNG image. The query must return two columns: label/x first, numeric value second.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{"type": "string", "description": "SQL SELECT returning (label, value) rows"},
+					"chart": map[string]any{"type": "string", "enum": []string{"bar", "line", "scatter"}, "description": "Chart type (default: bar)"},
+					"title": map[string]any{"type": "string", "description": "Chart title"},
+				},
+				"required": []string{"query"},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		query, _ := args["query"].(string)
+		chart, _ := args["chart"].(string)
+		title, _ := args["title"].(string)
+		if chart == "" {
+			chart = "bar"
+		}
+
+		analyzer.mu.Lock()
+		db := analyzer.db
+		analyzer.mu.Unlock()
+		if db == nil {
+			return nil, fmt.Errorf("no datasets loaded — use data_load first")
+		}
+
+		labels, values, err := queryPlotSeries(ctx, db, query)
+		if err != nil {
+			return nil, err
+		}
+
+		outPath := filepath.Join(os.TempDir(), fmt.Sprintf("devclaw-chart-%d.png", time.Now().UnixNano()))
+		if err := renderDataChart(chart, title, labels, values, outPath); err != nil {
+			return nil, err
+		}
+
+		result := map[string]any{
+			"chart":  chart,
+			"path":   outPath,
+			"points": len(values),
+		}
+
+		// Push the image to the originating chat through the channel manager.
+		dt := DeliveryTargetFromContext(ctx)
+		if channelMgr != nil && dt.Channel != "" && dt.ChatID != "" {
+			data, err := os.ReadFile(outPath)
+			if err != nil {
+				return nil, fmt.Errorf("reading rendered chart: %w", err)
+			}
+			msg := &channels.MediaMessage{
+				Type:     channels.MessageImage,
+				Data:     data,
+				MimeType: "image/png",
+				Filename: filepath.Base(outPath),
+				Caption:  title,
+			}
+			if err := channelMgr.SendMedia(ctx, dt.Channel, dt.ChatID, msg); err != nil {
+				logger.Warn("failed to send chart", "channel", dt.Channel, "error", err)
+				result["sent"] = false
+			} else {
+				result["sent"] = true
+			}
+		}
+
+		return result, nil
+	})
+}
+
+// queryDataRows runs a query and scans all rows into column-keyed maps.
+func queryDataRows(ctx context.Context, db *sql.DB, query string) ([]string, []map[string]any, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get columns: %w", err)
+	}
+
+	var results []map[string]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		valuePtrs := make([]any, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, fmt.Errorf("scan row: %w", err)
+		}
+		row := make(map[string]any)
+		for i, col := range columns {
+			val := values[i]
+			if b, ok := val.([]byte); ok {
+				val = string(b)
+			}
+			row[col] = val
+		}
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows error: %w", err)
+	}
+	return columns, results, nil
+}
+
+// queryPlotSeries runs a (label, value) query and returns the two series.
+func queryPlotSeries(ctx context.Context, db *sql.DB, query string) ([]string, []float64, error) {
+	columns, rows, err := queryDataRows(ctx, db, ensureQueryLimit(query, 200))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(columns) < 2 {
+		return nil, nil, fmt.Errorf("plot query must return two columns (label, value), got %d", len(columns))
+	}
+
+	labels := make([]string, 0, len(rows))
+	values := make([]float64, 0, len(rows))
+	for _, row := range rows {
+		labels = append(labels, fmt.Sprintf("%v", row[columns[0]]))
+		v, err := toFloat(row[columns[1]])
+		if err != nil {
+			return nil, nil, fmt.Errorf("column %q is not numeric: %w", columns[1], err)
+		}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return nil, nil, fmt.Errorf("plot query returned no rows")
+	}
+	return labels, values, nil
+}
+
+// toFloat converts a scanned SQL value to float64.
+func toFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int64:
+		return float64(n), nil
+	case string:
+		return strconv.ParseFloat(n, 64)
+	case nil:
+		return 0, fmt.Errorf("NULL value")
+	}
+	return 0, fmt.Errorf("unsupported type %T", v)
+}
+
+// renderDataChart renders the series to a PNG file with gonum/plot.
+func renderDataChart(chart, title string, labels []string, values []float64, outPath string) error {
+	p := plot.New()
+	p.Title.Text = title
+
+	switch chart {
+	case "bar":
+		bars, err := plotter.NewBarChart(plotter.Values(values), vg.Points(20))
+		if err != nil {
+			return fmt.Errorf("building bar chart: %w", err)
+		}
+		p.Add(bars)
+		p.NominalX(labels...)
+		if len(labels) > 8 {
+			p.X.Tick.Label.Rotation = 0.5
+		}
+
+	case "line", "scatter":
+		pts := make(plotter.XYs, len(values))
+		for i, v := range values {
+			x := float64(i)
+			// Numeric labels become real X coordinates.
+			if f, err := strconv.ParseFloat(labels[i], 64); err == nil {
+				x = f
+			}
+			pts[i].X = x
+			pts[i].Y = v
+		}
+		if chart == "line" {
+			line, err := plotter.NewLine(pts)
+			if err != nil {
+				return fmt.Errorf("building line chart: %w", err)
+			}
+			p.Add(line)
+		} else {
+			scatter, err := plotter.NewScatter(pts)
+			if err != nil {
+				return fmt.Errorf("building scatter chart: %w", err)
+			}
+			p.Add(scatter)
+		}
+
+	default:
+		return fmt.Errorf("unsupported chart type: %s", chart)
+	}
+
+	if err := p.Save(6*vg.Inch, 4*vg.Inch, outPath); err != nil {
+		return fmt.Errorf("rendering chart: %w", err)
+	}
+	return nil
+}
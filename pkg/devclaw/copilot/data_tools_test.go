@@ -0,0 +1,118 @@
+package copilot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestCSV(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sales report.csv")
+	csv := "Region,Units,Revenue\nnorth,10,100.50\nsouth,20,201.25\nnorth,5,\n"
+	if err := os.WriteFile(path, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDataAnalyzerLoadCSV(t *testing.T) {
+	da := NewDataAnalyzer()
+	defer da.Close()
+
+	columns, rows, err := da.Load(writeTestCSV(t), "")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if rows != 3 {
+		t.Errorf("expected 3 rows, got %d", rows)
+	}
+
+	want := map[string]string{"region": "TEXT", "units": "INTEGER", "revenue": "REAL"}
+	if len(columns) != len(want) {
+		t.Fatalf("expected %d columns, got %+v", len(want), columns)
+	}
+	for _, col := range columns {
+		if want[col.Name] != col.Type {
+			t.Errorf("column %s: expected type %s, got %s", col.Name, want[col.Name], col.Type)
+		}
+	}
+
+	tables := da.Tables()
+	if _, ok := tables["sales_report"]; !ok {
+		t.Errorf("expected table sales_report, got %v", tables)
+	}
+}
+
+func TestDataAnalyzerQueryAndSeries(t *testing.T) {
+	da := NewDataAnalyzer()
+	defer da.Close()
+
+	if _, _, err := da.Load(writeTestCSV(t), "sales"); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	ctx := context.Background()
+	columns, rows, err := queryDataRows(ctx, da.db,
+		"SELECT region, SUM(units) AS total FROM sales GROUP BY region ORDER BY region")
+	if err != nil {
+		t.Fatalf("queryDataRows: %v", err)
+	}
+	if len(rows) != 2 || columns[0] != "region" {
+		t.Fatalf("unexpected result: cols=%v rows=%+v", columns, rows)
+	}
+
+	labels, values, err := queryPlotSeries(ctx, da.db,
+		"SELECT region, SUM(units) FROM sales GROUP BY region ORDER BY region")
+	if err != nil {
+		t.Fatalf("queryPlotSeries: %v", err)
+	}
+	if len(labels) != 2 || labels[0] != "north" || values[0] != 15 {
+		t.Errorf("unexpected series: labels=%v values=%v", labels, values)
+	}
+
+	// Non-numeric value column must be rejected.
+	if _, _, err := queryPlotSeries(ctx, da.db, "SELECT units, region FROM sales"); err == nil {
+		t.Error("expected error for non-numeric value column")
+	}
+}
+
+func TestRenderDataChart(t *testing.T) {
+	outPath := filepath.Join(t.TempDir(), "chart.png")
+	labels := []string{"a", "b", "c"}
+	values := []float64{1, 3, 2}
+
+	if err := renderDataChart("bar", "Test", labels, values, outPath); err != nil {
+		t.Fatalf("renderDataChart: %v", err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("chart not written: %v", err)
+	}
+	if len(data) < 8 || string(data[1:4]) != "PNG" {
+		t.Error("output is not a PNG file")
+	}
+
+	if err := renderDataChart("pie", "Test", labels, values, outPath); err == nil {
+		t.Error("expected error for unsupported chart type")
+	}
+}
+
+func TestSanitizeDataIdent(t *testing.T) {
+	cases := map[string]string{
+		"Region":      "region",
+		"Unit Price":  "unit_price",
+		"2024 Sales":  "c2024_sales",
+		"  weird!@# ": "weird",
+	}
+	for in, want := range cases {
+		if got := sanitizeDataIdent(in); got != want {
+			t.Errorf("sanitizeDataIdent(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if !strings.HasPrefix(dataTableName("/tmp/My Data.csv"), "my_data") {
+		t.Errorf("unexpected table name: %q", dataTableName("/tmp/My Data.csv"))
+	}
+}
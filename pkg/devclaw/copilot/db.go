@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite driver.
 )
@@ -31,6 +32,19 @@ CREATE TABLE IF NOT EXISTS jobs (
     run_count   INTEGER DEFAULT 0
 );
 
+-- Scheduler job execution history (one row per run attempt).
+CREATE TABLE IF NOT EXISTS job_runs (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id      TEXT NOT NULL,
+    started_at  TEXT NOT NULL,
+    duration_ms INTEGER DEFAULT 0,
+    status      TEXT NOT NULL,
+    attempt     INTEGER DEFAULT 1,
+    error       TEXT DEFAULT '',
+    output      TEXT DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_job_runs_job ON job_runs(job_id);
+
 -- Session conversation entries (append-only, one row per exchange).
 CREATE TABLE IF NOT EXISTS session_entries (
     id                 INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -102,6 +116,19 @@ CREATE TABLE IF NOT EXISTS subagent_runs (
 CREATE INDEX IF NOT EXISTS idx_subagent_runs_parent ON subagent_runs(parent_session_id);
 CREATE INDEX IF NOT EXISTS idx_subagent_runs_status ON subagent_runs(status);
 
+-- Agent self-reflection lessons (post-run critiques).
+CREATE TABLE IF NOT EXISTS agent_lessons (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    workspace_id  TEXT NOT NULL,
+    session_id    TEXT NOT NULL,
+    what_failed   TEXT DEFAULT '',
+    tool_mistakes TEXT DEFAULT '',
+    missing_info  TEXT DEFAULT '',
+    lesson        TEXT NOT NULL,
+    created_at    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_agent_lessons_ws ON agent_lessons(workspace_id);
+
 -- System state (maintenance mode, etc.)
 CREATE TABLE IF NOT EXISTS system_state (
     key       TEXT PRIMARY KEY,
@@ -144,6 +171,21 @@ CREATE TABLE IF NOT EXISTS pairing_requests (
 CREATE INDEX IF NOT EXISTS idx_pairing_requests_status ON pairing_requests(status);
 CREATE INDEX IF NOT EXISTS idx_pairing_requests_user ON pairing_requests(user_jid);
 
+-- Access requests (unknown contacts queued for review under the "ask" policy).
+CREATE TABLE IF NOT EXISTS access_requests (
+    id            TEXT PRIMARY KEY,
+    user_jid      TEXT NOT NULL,
+    user_name     TEXT DEFAULT '',
+    channel       TEXT DEFAULT '',
+    first_message TEXT DEFAULT '',
+    status        TEXT NOT NULL DEFAULT 'pending',
+    reviewed_by   TEXT DEFAULT '',
+    reviewed_at   TEXT DEFAULT '',
+    created_at    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_access_requests_status ON access_requests(status);
+CREATE INDEX IF NOT EXISTS idx_access_requests_user ON access_requests(user_jid);
+
 -- ═══════════════════════════════════════════════════════════════════
 -- TEAM MANAGEMENT SYSTEM
 -- ═══════════════════════════════════════════════════════════════════
@@ -242,6 +284,8 @@ CREATE TABLE IF NOT EXISTS team_facts (
     key        TEXT NOT NULL,
     value      TEXT NOT NULL,
     author     TEXT NOT NULL,
+    visibility TEXT DEFAULT 'team',
+    roles      TEXT DEFAULT '[]',
     created_at TEXT NOT NULL,
     updated_at TEXT NOT NULL,
     FOREIGN KEY (team_id) REFERENCES teams(id),
@@ -249,6 +293,17 @@ CREATE TABLE IF NOT EXISTS team_facts (
 );
 CREATE INDEX IF NOT EXISTS idx_facts_team ON team_facts(team_id);
 
+-- Onboarding flow for newly paired contacts
+CREATE TABLE IF NOT EXISTS user_onboarding (
+    jid          TEXT PRIMARY KEY,
+    status       TEXT NOT NULL,
+    language     TEXT DEFAULT '',
+    preferences  TEXT DEFAULT '',
+    trial        INTEGER DEFAULT 1,
+    started_at   TEXT NOT NULL,
+    completed_at TEXT DEFAULT ''
+);
+
 -- Team activity feed
 CREATE TABLE IF NOT EXISTS team_activities (
     id         TEXT PRIMARY KEY,
@@ -347,6 +402,161 @@ CREATE TABLE IF NOT EXISTS team_notifications (
 CREATE INDEX IF NOT EXISTS idx_notifications_team ON team_notifications(team_id);
 CREATE INDEX IF NOT EXISTS idx_notifications_timestamp ON team_notifications(timestamp);
 CREATE INDEX IF NOT EXISTS idx_notifications_read ON team_notifications(read);
+
+-- Runbook execution progress (resumable step-by-step runs)
+CREATE TABLE IF NOT EXISTS runbook_runs (
+    id           TEXT PRIMARY KEY,
+    path         TEXT NOT NULL,
+    title        TEXT DEFAULT '',
+    session_id   TEXT DEFAULT '',
+    channel      TEXT DEFAULT '',
+    chat_id      TEXT DEFAULT '',
+    current_step INTEGER DEFAULT 0,
+    total_steps  INTEGER DEFAULT 0,
+    status       TEXT DEFAULT 'running',
+    started_at   TEXT NOT NULL,
+    updated_at   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_runbook_runs_chat ON runbook_runs(channel, chat_id);
+CREATE INDEX IF NOT EXISTS idx_runbook_runs_status ON runbook_runs(status);
+
+-- Group archive (passive listening mode, opt-in per group)
+CREATE TABLE IF NOT EXISTS group_archive (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_jid   TEXT NOT NULL,
+    sender_jid  TEXT DEFAULT '',
+    sender_name TEXT DEFAULT '',
+    content     TEXT NOT NULL,
+    created_at  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_group_archive_group ON group_archive(group_jid);
+CREATE INDEX IF NOT EXISTS idx_group_archive_created ON group_archive(created_at);
+
+-- Web page change watches (watch_url tool)
+CREATE TABLE IF NOT EXISTS url_watches (
+    id            TEXT PRIMARY KEY,
+    url           TEXT NOT NULL,
+    selector      TEXT DEFAULT '',
+    label         TEXT DEFAULT '',
+    threshold_pct REAL DEFAULT 0,
+    interval      TEXT DEFAULT '',
+    channel       TEXT DEFAULT '',
+    chat_id       TEXT DEFAULT '',
+    last_hash     TEXT DEFAULT '',
+    last_content  TEXT DEFAULT '',
+    last_checked  TEXT DEFAULT '',
+    created_at    TEXT NOT NULL
+);
+
+-- Per-check history for url watches
+CREATE TABLE IF NOT EXISTS url_watch_checks (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    watch_id   TEXT NOT NULL,
+    checked_at TEXT NOT NULL,
+    changed    INTEGER DEFAULT 0,
+    change_pct REAL DEFAULT 0,
+    error      TEXT DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_url_watch_checks_watch ON url_watch_checks(watch_id, checked_at);
+
+-- Crash reports (panics recovered in background goroutines)
+CREATE TABLE IF NOT EXISTS crash_reports (
+    id         TEXT PRIMARY KEY,
+    component  TEXT NOT NULL,
+    message    TEXT NOT NULL,
+    stack      TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    reported   INTEGER DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_crash_reports_created ON crash_reports(created_at);
+
+-- Artifacts (long generated outputs, versioned by name)
+CREATE TABLE IF NOT EXISTS artifacts (
+    id         TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    version    INTEGER NOT NULL,
+    mime_type  TEXT DEFAULT 'text/plain',
+    content    TEXT NOT NULL,
+    size       INTEGER DEFAULT 0,
+    session_id TEXT DEFAULT '',
+    created_at TEXT NOT NULL,
+    UNIQUE(name, version)
+);
+
+-- Webhook dead letters (deliveries that exhausted all retries)
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id           TEXT PRIMARY KEY,
+    webhook_name TEXT NOT NULL,
+    event        TEXT NOT NULL,
+    payload      TEXT NOT NULL,
+    last_error   TEXT DEFAULT '',
+    attempts     INTEGER DEFAULT 0,
+    created_at   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_created ON webhook_dead_letters(created_at);
+
+-- LLM traffic log (request/response payloads per privacy level, with retention)
+CREATE TABLE IF NOT EXISTS llm_traffic (
+    id                TEXT PRIMARY KEY,
+    session_id        TEXT DEFAULT '',
+    provider          TEXT DEFAULT '',
+    model             TEXT NOT NULL,
+    request           TEXT DEFAULT '',
+    response          TEXT DEFAULT '',
+    status            TEXT NOT NULL,
+    error             TEXT DEFAULT '',
+    prompt_tokens     INTEGER DEFAULT 0,
+    completion_tokens INTEGER DEFAULT 0,
+    duration_ms       INTEGER DEFAULT 0,
+    created_at        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_llm_traffic_created ON llm_traffic(created_at);
+CREATE INDEX IF NOT EXISTS idx_llm_traffic_session ON llm_traffic(session_id);
+
+-- Run transcripts (per-run timeline of LLM turns, tool calls and interrupts)
+CREATE TABLE IF NOT EXISTS agent_runs (
+    run_id      TEXT PRIMARY KEY,
+    session_id  TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    error       TEXT DEFAULT '',
+    duration_ms INTEGER DEFAULT 0,
+    events      TEXT NOT NULL,
+    started_at  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_agent_runs_started ON agent_runs(started_at);
+CREATE INDEX IF NOT EXISTS idx_agent_runs_session ON agent_runs(session_id);
+
+-- Archived idle sessions (summary + pointer; full transcript exported to memory)
+CREATE TABLE IF NOT EXISTS session_archives (
+    session_id  TEXT PRIMARY KEY,
+    channel     TEXT NOT NULL,
+    chat_id     TEXT NOT NULL,
+    summary     TEXT NOT NULL,
+    facts       TEXT DEFAULT '[]',
+    messages    INTEGER DEFAULT 0,
+    memory_date TEXT DEFAULT '',
+    archived_at TEXT NOT NULL,
+    restored_at TEXT DEFAULT ''
+);
+
+-- Cross-channel identity links (many channel JIDs → one person)
+CREATE TABLE IF NOT EXISTS identity_links (
+    jid         TEXT PRIMARY KEY,
+    identity_id TEXT NOT NULL,
+    channel     TEXT DEFAULT '',
+    linked_by   TEXT DEFAULT '',
+    linked_at   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_identity_links_identity ON identity_links(identity_id);
+
+-- Feature flag overrides set at runtime via /flags (scope is a workspace ID or '*')
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+    flag       TEXT NOT NULL,
+    scope      TEXT NOT NULL,
+    enabled    INTEGER NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (flag, scope)
+);
 `
 
 // OpenDatabase opens (or creates) the central devclaw.db at the given path.
@@ -380,5 +590,18 @@ func OpenDatabase(path string) (*sql.DB, error) {
 		return nil, fmt.Errorf("create schema: %w", err)
 	}
 
+	// Column migrations for databases created before the column existed.
+	// "duplicate column name" errors are expected and ignored.
+	migrations := []string{
+		`ALTER TABLE team_facts ADD COLUMN visibility TEXT DEFAULT 'team'`,
+		`ALTER TABLE team_facts ADD COLUMN roles TEXT DEFAULT '[]'`,
+	}
+	for _, stmt := range migrations {
+		if _, err := db.Exec(stmt); err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			db.Close()
+			return nil, fmt.Errorf("migrate schema: %w", err)
+		}
+	}
+
 	return db, nil
 }
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -125,13 +126,17 @@ func RegisterDBHubTools(executor *ToolExecutor, hub *database.Hub) {
 		Type: "function",
 		Function: FunctionDef{
 			Name:        "db_hub_query",
-			Description: "Execute a SQL SELECT query on the database hub using native Go drivers (faster than CLI-based db_query).",
+			Description: "Execute a SQL SELECT query on the database hub using native Go drivers (faster than CLI-based db_query). Use params with ? placeholders for untrusted values. In safe mode (default) a LIMIT is injected automatically.",
 			Parameters: mustJSON(map[string]any{
 				"type": "object",
 				"properties": map[string]any{
 					"query": map[string]any{
 						"type":        "string",
-						"description": "SQL SELECT query to execute",
+						"description": "SQL SELECT query to execute. Use ? placeholders with params for values.",
+					},
+					"params": map[string]any{
+						"type":        "array",
+						"description": "Positional parameters bound to ? placeholders (prevents SQL injection)",
 					},
 					"backend": map[string]any{
 						"type":        "string",
@@ -141,6 +146,15 @@ func RegisterDBHubTools(executor *ToolExecutor, hub *database.Hub) {
 						"type":        "integer",
 						"description": "Maximum rows to return (default: 100)",
 					},
+					"format": map[string]any{
+						"type":        "string",
+						"enum":        []string{"json", "markdown"},
+						"description": "Result format (default: json)",
+					},
+					"safe_mode": map[string]any{
+						"type":        "boolean",
+						"description": "Inject LIMIT max_rows when the query has none (default: true)",
+					},
 				},
 				"required": []string{"query"},
 			}),
@@ -148,9 +162,11 @@ func RegisterDBHubTools(executor *ToolExecutor, hub *database.Hub) {
 	}, func(ctx context.Context, args map[string]any) (any, error) {
 		query, _ := args["query"].(string)
 		backendName, _ := args["backend"].(string)
-		maxRows, _ := args["max_rows"].(int)
-		if maxRows == 0 {
-			maxRows = 100
+		format, _ := args["format"].(string)
+		maxRows := toolArgInt(args, "max_rows", 100)
+		safeMode := true
+		if v, ok := args["safe_mode"].(bool); ok {
+			safeMode = v
 		}
 
 		// Validate it's a SELECT query
@@ -162,7 +178,13 @@ func RegisterDBHubTools(executor *ToolExecutor, hub *database.Hub) {
 			return nil, fmt.Errorf("only SELECT queries are allowed with db_hub_query; use db_hub_execute for mutations")
 		}
 
-		rows, err := hub.Query(ctx, backendName, query)
+		// Safe mode: cap the result set at the database level so runaway
+		// queries don't pull entire tables across the wire.
+		if safeMode {
+			query = ensureQueryLimit(query, maxRows)
+		}
+
+		rows, err := hub.Query(ctx, backendName, query, toolArgParams(args)...)
 		if err != nil {
 			return nil, fmt.Errorf("query failed: %w", err)
 		}
@@ -208,6 +230,10 @@ func RegisterDBHubTools(executor *ToolExecutor, hub *database.Hub) {
 			return nil, fmt.Errorf("rows error: %w", err)
 		}
 
+		if format == "markdown" {
+			return formatRowsMarkdown(columns, results), nil
+		}
+
 		return map[string]any{
 			"columns": columns,
 			"rows":    results,
@@ -221,13 +247,17 @@ func RegisterDBHubTools(executor *ToolExecutor, hub *database.Hub) {
 		Type: "function",
 		Function: FunctionDef{
 			Name:        "db_hub_execute",
-			Description: "Execute a mutating SQL statement (INSERT, UPDATE, DELETE, CREATE, etc.) on the database hub.",
+			Description: "Execute a mutating SQL statement (INSERT, UPDATE, DELETE, CREATE, etc.) on the database hub. Use params with ? placeholders for untrusted values. Requires user approval for non-owner callers.",
 			Parameters: mustJSON(map[string]any{
 				"type": "object",
 				"properties": map[string]any{
 					"statement": map[string]any{
 						"type":        "string",
-						"description": "SQL statement to execute",
+						"description": "SQL statement to execute. Use ? placeholders with params for values.",
+					},
+					"params": map[string]any{
+						"type":        "array",
+						"description": "Positional parameters bound to ? placeholders (prevents SQL injection)",
 					},
 					"backend": map[string]any{
 						"type":        "string",
@@ -241,7 +271,7 @@ func RegisterDBHubTools(executor *ToolExecutor, hub *database.Hub) {
 		statement, _ := args["statement"].(string)
 		backendName, _ := args["backend"].(string)
 
-		result, err := hub.Exec(ctx, backendName, statement)
+		result, err := hub.Exec(ctx, backendName, statement, toolArgParams(args)...)
 		if err != nil {
 			return nil, fmt.Errorf("execute failed: %w", err)
 		}
@@ -652,6 +682,75 @@ func executeRawQuery(ctx context.Context, db *sql.DB, query string) (any, error)
 	}, nil
 }
 
+// toolArgInt reads an integer tool argument, accepting both JSON numbers
+// (float64) and native ints. Returns def when absent or zero.
+func toolArgInt(args map[string]any, key string, def int) int {
+	switch v := args[key].(type) {
+	case float64:
+		if v > 0 {
+			return int(v)
+		}
+	case int:
+		if v > 0 {
+			return v
+		}
+	}
+	return def
+}
+
+// toolArgParams reads the positional query parameters from the "params" arg.
+func toolArgParams(args map[string]any) []any {
+	params, _ := args["params"].([]any)
+	return params
+}
+
+// limitClauseRe detects an existing LIMIT clause so safe mode doesn't stack
+// a second one onto the query.
+var limitClauseRe = regexp.MustCompile(`(?i)\bLIMIT\s+\d+`)
+
+// ensureQueryLimit appends "LIMIT maxRows" to SELECT/WITH queries that don't
+// already carry a LIMIT clause. Other statements pass through unchanged.
+func ensureQueryLimit(query string, maxRows int) string {
+	trimmed := strings.TrimSpace(query)
+	upper := strings.ToUpper(trimmed)
+	if !strings.HasPrefix(upper, "SELECT") && !strings.HasPrefix(upper, "WITH") {
+		return query
+	}
+	if limitClauseRe.MatchString(trimmed) {
+		return query
+	}
+	trimmed = strings.TrimRight(trimmed, "; \t\n")
+	return fmt.Sprintf("%s LIMIT %d", trimmed, maxRows)
+}
+
+// formatRowsMarkdown renders query results as a Markdown table.
+func formatRowsMarkdown(columns []string, rows []map[string]any) string {
+	if len(rows) == 0 {
+		return "No rows."
+	}
+
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			val := row[col]
+			cell := "NULL"
+			if val != nil {
+				cell = fmt.Sprintf("%v", val)
+			}
+			// Keep the table intact: escape pipes, collapse newlines.
+			cell = strings.ReplaceAll(cell, "|", "\\|")
+			cell = strings.ReplaceAll(cell, "\n", " ")
+			cells[i] = cell
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	b.WriteString(fmt.Sprintf("\n%d row(s)", len(rows)))
+	return b.String()
+}
+
 // sessionIDKey is the context key for session ID
 type sessionIDKey struct{}
 
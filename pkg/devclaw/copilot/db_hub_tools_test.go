@@ -2,6 +2,7 @@ package copilot
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 )
@@ -173,3 +174,61 @@ func TestDBHubRateLimiter_MultipleSessions(t *testing.T) {
 		}
 	}
 }
+
+func TestEnsureQueryLimit(t *testing.T) {
+	cases := map[string]string{
+		"SELECT * FROM users":           "SELECT * FROM users LIMIT 100",
+		"SELECT * FROM users;":          "SELECT * FROM users LIMIT 100",
+		"SELECT * FROM users LIMIT 5":   "SELECT * FROM users LIMIT 5",
+		"select * from users limit 5":   "select * from users limit 5",
+		"WITH t AS (SELECT 1) SELECT *": "WITH t AS (SELECT 1) SELECT * LIMIT 100",
+		"PRAGMA table_info(users)":      "PRAGMA table_info(users)",
+	}
+	for query, want := range cases {
+		if got := ensureQueryLimit(query, 100); got != want {
+			t.Errorf("ensureQueryLimit(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestFormatRowsMarkdown(t *testing.T) {
+	columns := []string{"id", "name"}
+	rows := []map[string]any{
+		{"id": 1, "name": "alice"},
+		{"id": 2, "name": "has|pipe"},
+		{"id": 3, "name": nil},
+	}
+
+	table := formatRowsMarkdown(columns, rows)
+	if !strings.Contains(table, "| id | name |") {
+		t.Errorf("missing header row: %q", table)
+	}
+	if !strings.Contains(table, "has\\|pipe") {
+		t.Errorf("pipe not escaped: %q", table)
+	}
+	if !strings.Contains(table, "NULL") {
+		t.Errorf("nil not rendered as NULL: %q", table)
+	}
+	if !strings.Contains(table, "3 row(s)") {
+		t.Errorf("missing row count: %q", table)
+	}
+
+	if got := formatRowsMarkdown(columns, nil); got != "No rows." {
+		t.Errorf("expected 'No rows.', got %q", got)
+	}
+}
+
+func TestIsDBWriteCall(t *testing.T) {
+	if !isDBWriteCall("db_hub_execute", nil) {
+		t.Error("db_hub_execute should be a write call")
+	}
+	if !isDBWriteCall("db_hub_raw", map[string]any{"query": "DELETE FROM users"}) {
+		t.Error("db_hub_raw with DELETE should be a write call")
+	}
+	if isDBWriteCall("db_hub_raw", map[string]any{"query": "SELECT * FROM users"}) {
+		t.Error("db_hub_raw with SELECT should not be a write call")
+	}
+	if isDBWriteCall("db_hub_query", map[string]any{"query": "SELECT 1"}) {
+		t.Error("db_hub_query should never be a write call")
+	}
+}
@@ -0,0 +1,293 @@
+// Package copilot – db_maintenance.go implements the SQLite maintenance
+// routine: scheduled VACUUM/ANALYZE, WAL checkpointing, and integrity checks
+// for devclaw.db and memory.db, with owner alerts when a database grows past
+// the configured size. Also backs the manual /db maintenance command.
+// Configured under Routines.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// DBMaintenanceConfig configures the SQLite maintenance routine.
+type DBMaintenanceConfig struct {
+	// Enabled turns scheduled maintenance on/off. The /db maintenance
+	// command works regardless.
+	Enabled bool `yaml:"enabled"`
+
+	// IntervalHours is how often maintenance runs (default 24).
+	IntervalHours int `yaml:"interval_hours"`
+
+	// MaxSizeMB alerts the owner when a database is still larger than this
+	// after maintenance (default 500, 0 = no size alerts).
+	MaxSizeMB int64 `yaml:"max_size_mb"`
+
+	// Channel/ChatID override where size alerts are delivered. Empty = the
+	// most recently active session.
+	Channel string `yaml:"channel"`
+	ChatID  string `yaml:"chat_id"`
+}
+
+// DefaultDBMaintenanceConfig returns default configuration (disabled, like
+// the other opt-in routines — VACUUM briefly locks the database).
+func DefaultDBMaintenanceConfig() DBMaintenanceConfig {
+	return DBMaintenanceConfig{
+		Enabled:       false,
+		IntervalHours: 24,
+		MaxSizeMB:     500,
+	}
+}
+
+// DBMaintenanceReport summarizes one maintenance pass over one database.
+type DBMaintenanceReport struct {
+	Name            string
+	SizeBeforeMB    float64
+	SizeAfterMB     float64
+	WALPagesFlushed int64
+	IntegrityOK     bool
+	IntegrityDetail string
+	Err             error
+}
+
+// ReclaimedMB is the space freed by the pass (never negative).
+func (r DBMaintenanceReport) ReclaimedMB() float64 {
+	if reclaimed := r.SizeBeforeMB - r.SizeAfterMB; reclaimed > 0 {
+		return reclaimed
+	}
+	return 0
+}
+
+// DBMaintenance runs scheduled SQLite maintenance.
+type DBMaintenance struct {
+	config    DBMaintenanceConfig
+	assistant *Assistant
+	logger    *slog.Logger
+	cancel    context.CancelFunc
+}
+
+// NewDBMaintenance creates a new maintenance routine.
+func NewDBMaintenance(cfg DBMaintenanceConfig, assistant *Assistant, logger *slog.Logger) *DBMaintenance {
+	if cfg.IntervalHours <= 0 {
+		cfg.IntervalHours = DefaultDBMaintenanceConfig().IntervalHours
+	}
+	return &DBMaintenance{
+		config:    cfg,
+		assistant: assistant,
+		logger:    logger.With("component", "db-maintenance"),
+	}
+}
+
+// Start begins the maintenance loop in a background goroutine.
+func (d *DBMaintenance) Start(ctx context.Context) {
+	if !d.config.Enabled {
+		d.logger.Debug("db maintenance disabled")
+		return
+	}
+
+	dCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+
+	d.logger.Info("db maintenance started", "interval_hours", d.config.IntervalHours)
+	go d.loop(dCtx)
+}
+
+// Stop shuts down the maintenance routine.
+func (d *DBMaintenance) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+}
+
+// loop runs maintenance every interval until the context ends.
+func (d *DBMaintenance) loop(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(d.config.IntervalHours) * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.tick(ctx)
+		case <-ctx.Done():
+			d.logger.Info("db maintenance stopped")
+			return
+		}
+	}
+}
+
+// tick runs one scheduled maintenance pass and alerts on oversized databases.
+func (d *DBMaintenance) tick(ctx context.Context) {
+	reports := d.assistant.runDBMaintenance(ctx)
+	for _, r := range reports {
+		if r.Err != nil {
+			d.logger.Warn("db maintenance failed", "db", r.Name, "error", r.Err)
+			continue
+		}
+		d.logger.Info("db maintenance pass",
+			"db", r.Name,
+			"size_mb", fmt.Sprintf("%.1f", r.SizeAfterMB),
+			"reclaimed_mb", fmt.Sprintf("%.1f", r.ReclaimedMB()),
+			"wal_pages_flushed", r.WALPagesFlushed,
+			"integrity_ok", r.IntegrityOK,
+		)
+		if !r.IntegrityOK {
+			d.alert(ctx, fmt.Sprintf("⚠️ *Database integrity check failed*\n%s: %s\n\nTake a backup and consider restoring from a known-good copy.",
+				r.Name, truncate(r.IntegrityDetail, 300)), true)
+			continue
+		}
+		if d.config.MaxSizeMB > 0 && r.SizeAfterMB > float64(d.config.MaxSizeMB) {
+			d.alert(ctx, fmt.Sprintf("📦 *Database growing large*\n%s is %.1f MB after maintenance (threshold %d MB). Consider pruning old sessions or raising `routines.db_maintenance.max_size_mb`.",
+				r.Name, r.SizeAfterMB, d.config.MaxSizeMB), false)
+		}
+	}
+}
+
+// alert delivers a maintenance notification to the owner chat.
+func (d *DBMaintenance) alert(ctx context.Context, text string, urgent bool) {
+	channel, chatID := d.deliveryTarget()
+	if channel == "" || chatID == "" {
+		d.logger.Warn("db maintenance alert has no delivery target")
+		return
+	}
+	if err := d.assistant.sendProactive(ctx, channel, chatID, text, urgent); err != nil {
+		d.logger.Warn("db maintenance alert delivery failed", "channel", channel, "error", err)
+	}
+}
+
+// deliveryTarget resolves where maintenance alerts go: the config override
+// when set, otherwise the most recently active session.
+func (d *DBMaintenance) deliveryTarget() (channel, chatID string) {
+	if d.config.Channel != "" && d.config.ChatID != "" {
+		return d.config.Channel, d.config.ChatID
+	}
+	if d.assistant == nil || d.assistant.workspaceMgr == nil {
+		return "", ""
+	}
+	var latest time.Time
+	for _, info := range d.assistant.workspaceMgr.ListAllSessions() {
+		if info.LastActiveAt.After(latest) && info.Channel != "" && info.ChatID != "" {
+			latest = info.LastActiveAt
+			channel = info.Channel
+			chatID = info.ChatID
+		}
+	}
+	return channel, chatID
+}
+
+// dbCommand implements the /db admin command.
+func (a *Assistant) dbCommand(args []string) string {
+	if len(args) == 0 || strings.ToLower(args[0]) != "maintenance" {
+		return "Usage: /db maintenance"
+	}
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	reports := a.runDBMaintenance(ctx)
+	return formatDBMaintenanceReports(reports, time.Since(start))
+}
+
+// runDBMaintenance runs one maintenance pass over all known databases and
+// returns per-database reports. Shared by the routine and /db maintenance.
+func (a *Assistant) runDBMaintenance(ctx context.Context) []DBMaintenanceReport {
+	// Drop expired run transcripts first so VACUUM reclaims their pages.
+	if a.runTraceStore != nil {
+		if n, err := a.runTraceStore.Purge(a.config.RunTrace.RetentionDays); err != nil {
+			a.logger.Warn("run trace purge failed", "error", err)
+		} else if n > 0 {
+			a.logger.Info("purged expired run transcripts", "count", n)
+		}
+	}
+
+	var reports []DBMaintenanceReport
+	if a.devclawDB != nil {
+		reports = append(reports, maintainSQLiteDB(ctx, "devclaw.db", a.devclawDB))
+	}
+	if a.sqliteMemory != nil {
+		reports = append(reports, maintainSQLiteDB(ctx, "memory.db", a.sqliteMemory.DB()))
+	}
+	return reports
+}
+
+// maintainSQLiteDB runs WAL checkpoint → ANALYZE → VACUUM → integrity check
+// on one database and measures the size before/after.
+func maintainSQLiteDB(ctx context.Context, name string, db *sql.DB) DBMaintenanceReport {
+	report := DBMaintenanceReport{Name: name}
+	if db == nil {
+		report.Err = fmt.Errorf("database not available")
+		return report
+	}
+
+	report.SizeBeforeMB = sqliteSizeMB(ctx, db)
+
+	// Flush and truncate the WAL so its pages return to the main file.
+	var busy, logPages, checkpointed int64
+	if err := db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logPages, &checkpointed); err == nil {
+		report.WALPagesFlushed = checkpointed
+	}
+
+	// Refresh query-planner statistics before the rewrite.
+	if _, err := db.ExecContext(ctx, "ANALYZE"); err != nil {
+		report.Err = fmt.Errorf("analyze %s: %w", name, err)
+		return report
+	}
+
+	// VACUUM rewrites the file, reclaiming free pages. Briefly locks the
+	// database — the busy_timeout set at open keeps writers waiting.
+	if _, err := db.ExecContext(ctx, "VACUUM"); err != nil {
+		report.Err = fmt.Errorf("vacuum %s: %w", name, err)
+		return report
+	}
+
+	var integrity string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&integrity); err != nil {
+		report.Err = fmt.Errorf("integrity check %s: %w", name, err)
+		return report
+	}
+	report.IntegrityOK = integrity == "ok"
+	report.IntegrityDetail = integrity
+
+	report.SizeAfterMB = sqliteSizeMB(ctx, db)
+	return report
+}
+
+// sqliteSizeMB returns the database size in MB from page statistics.
+func sqliteSizeMB(ctx context.Context, db *sql.DB) float64 {
+	var pageCount, pageSize int64
+	if err := db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0
+	}
+	return float64(pageCount*pageSize) / 1024 / 1024
+}
+
+// formatDBMaintenanceReports renders the /db maintenance response.
+func formatDBMaintenanceReports(reports []DBMaintenanceReport, took time.Duration) string {
+	if len(reports) == 0 {
+		return "No databases available for maintenance."
+	}
+	var b strings.Builder
+	b.WriteString("*Database Maintenance*\n")
+	for _, r := range reports {
+		if r.Err != nil {
+			b.WriteString(fmt.Sprintf("\n• *%s*: failed — %v\n", r.Name, r.Err))
+			continue
+		}
+		integrity := "ok"
+		if !r.IntegrityOK {
+			integrity = "FAILED: " + truncate(r.IntegrityDetail, 200)
+		}
+		b.WriteString(fmt.Sprintf("\n• *%s*: %.1f MB → %.1f MB (%.1f MB reclaimed)\n",
+			r.Name, r.SizeBeforeMB, r.SizeAfterMB, r.ReclaimedMB()))
+		b.WriteString(fmt.Sprintf("  WAL pages flushed: %d, integrity: %s\n", r.WALPagesFlushed, integrity))
+	}
+	b.WriteString(fmt.Sprintf("\nCompleted in %s.", took.Round(100*time.Millisecond)))
+	return b.String()
+}
@@ -0,0 +1,80 @@
+// Package copilot – db_maintenance_test.go contains unit tests for the
+// SQLite maintenance pass.
+package copilot
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintainSQLiteDB(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenDatabase: %v", err)
+	}
+	defer db.Close()
+
+	// Create churn so VACUUM has free pages to reclaim.
+	for i := 0; i < 500; i++ {
+		if _, err := db.Exec(`INSERT INTO session_facts (session_id, fact, created_at) VALUES ('s1', ?, datetime('now'))`,
+			strings.Repeat("x", 1000)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`DELETE FROM session_facts`); err != nil {
+		t.Fatal(err)
+	}
+
+	report := maintainSQLiteDB(context.Background(), "test.db", db)
+	if report.Err != nil {
+		t.Fatalf("maintenance failed: %v", report.Err)
+	}
+	if !report.IntegrityOK {
+		t.Errorf("integrity check failed: %s", report.IntegrityDetail)
+	}
+	if report.SizeAfterMB <= 0 {
+		t.Errorf("expected a measured size, got %+v", report)
+	}
+	if report.SizeAfterMB > report.SizeBeforeMB {
+		t.Errorf("expected VACUUM not to grow the database: %+v", report)
+	}
+
+	// The database is still usable after the pass.
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM session_facts").Scan(&count); err != nil || count != 0 {
+		t.Errorf("database unusable after maintenance: count=%d err=%v", count, err)
+	}
+}
+
+func TestMaintainSQLiteDBNil(t *testing.T) {
+	report := maintainSQLiteDB(context.Background(), "missing.db", nil)
+	if report.Err == nil {
+		t.Error("expected error for nil database")
+	}
+}
+
+func TestFormatDBMaintenanceReports(t *testing.T) {
+	reports := []DBMaintenanceReport{
+		{Name: "devclaw.db", SizeBeforeMB: 12.5, SizeAfterMB: 10.0, WALPagesFlushed: 42, IntegrityOK: true, IntegrityDetail: "ok"},
+		{Name: "memory.db", Err: context.DeadlineExceeded},
+	}
+	out := formatDBMaintenanceReports(reports, 1300*time.Millisecond)
+	for _, want := range []string{
+		"devclaw.db",
+		"12.5 MB → 10.0 MB (2.5 MB reclaimed)",
+		"WAL pages flushed: 42",
+		"memory.db*: failed",
+		"1.3s",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+
+	if out := formatDBMaintenanceReports(nil, 0); !strings.Contains(out, "No databases") {
+		t.Errorf("unexpected empty-report output: %s", out)
+	}
+}
@@ -0,0 +1,313 @@
+// Package copilot – deps_tools.go implements dependency vulnerability
+// scanning. The deps_audit tool wraps govulncheck, npm audit, and pip-audit
+// depending on the project type, summarizes findings by severity with
+// upgrade suggestions, and can install a weekly scheduler job that reports
+// new findings back to the owner chat.
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/scheduler"
+)
+
+// ---------- Data Types ----------
+
+// depsVulnerability is one normalized finding across audit backends.
+type depsVulnerability struct {
+	ID       string `json:"id"`
+	Package  string `json:"package"`
+	Version  string `json:"version,omitempty"`
+	Severity string `json:"severity"`
+	FixedIn  string `json:"fixed_in,omitempty"`
+	Title    string `json:"title,omitempty"`
+}
+
+type depsAuditResult struct {
+	Tool            string              `json:"tool"`
+	Command         string              `json:"command"`
+	ExitCode        int                 `json:"exit_code"`
+	Total           int                 `json:"total"`
+	BySeverity      map[string]int      `json:"by_severity,omitempty"`
+	Vulnerabilities []depsVulnerability `json:"vulnerabilities,omitempty"`
+	Output          string              `json:"output,omitempty"`
+	Hint            string              `json:"hint,omitempty"`
+}
+
+// depsAuditJobID is the scheduler job installed by schedule_weekly.
+const depsAuditJobID = "deps-audit-weekly"
+
+// ---------- Tool Registration ----------
+
+// RegisterDepsTools registers the dependency audit tool. sched may be nil;
+// in that case the weekly scheduling option is unavailable.
+func RegisterDepsTools(executor *ToolExecutor, sched *scheduler.Scheduler) {
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "deps_audit",
+			Description: "Scan project dependencies for known vulnerabilities (auto-detects govulncheck, npm audit, pip-audit). Returns findings by severity with upgrade suggestions. Set schedule_weekly to install a weekly scan that reports new findings to this chat.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"tool":            map[string]any{"type": "string", "enum": []string{"govulncheck", "npm", "pip-audit"}, "description": "Force a specific audit backend"},
+					"schedule_weekly": map[string]any{"type": "boolean", "description": "Install a weekly scheduled audit that announces new findings to this chat"},
+				},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		tool, _ := args["tool"].(string)
+		scheduleWeekly, _ := args["schedule_weekly"].(bool)
+
+		if scheduleWeekly {
+			if sched == nil {
+				return nil, fmt.Errorf("scheduler is not enabled")
+			}
+			dt := DeliveryTargetFromContext(ctx)
+			job := &scheduler.Job{
+				ID:             depsAuditJobID,
+				Schedule:       "@weekly",
+				Type:           "cron",
+				Command:        "Run the deps_audit tool and report any new or high-severity vulnerabilities with upgrade suggestions. If there are no findings, reply with NO_REPLY.",
+				Channel:        dt.Channel,
+				ChatID:         dt.ChatID,
+				Enabled:        true,
+				IsolateSession: true,
+				Announce:       true,
+			}
+			if err := sched.Add(job); err != nil {
+				return nil, fmt.Errorf("scheduling weekly audit: %w", err)
+			}
+			return fmt.Sprintf("Weekly dependency audit scheduled as job %q. Remove it with cron_remove.", depsAuditJobID), nil
+		}
+
+		if tool == "" {
+			tool = detectAuditTool()
+		}
+		cmdStr := buildAuditCommand(tool)
+		if cmdStr == "" {
+			return nil, fmt.Errorf("no audit backend available for tool: %s", tool)
+		}
+
+		out, exitCode, err := runToolCommand(cmdStr)
+		if err != nil {
+			return nil, fmt.Errorf("running audit: %w", err)
+		}
+
+		vulns := parseAuditOutput(tool, out)
+		result := depsAuditResult{
+			Tool:            tool,
+			Command:         cmdStr,
+			ExitCode:        exitCode,
+			Total:           len(vulns),
+			Vulnerabilities: vulns,
+		}
+		if len(vulns) > 0 {
+			result.BySeverity = make(map[string]int)
+			for _, v := range vulns {
+				result.BySeverity[v.Severity]++
+			}
+			result.Hint = "Upgrade the affected packages to the fixed_in versions, then rerun deps_audit to confirm."
+		} else if exitCode != 0 {
+			// Audit failed without parseable findings (missing tool, bad config).
+			result.Output = truncateOutput(out, 3000)
+		}
+
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return string(data), nil
+	})
+}
+
+// ---------- Detection & Commands ----------
+
+// detectAuditTool picks an audit backend from project marker files.
+func detectAuditTool() string {
+	if fileExistsAny("go.mod") {
+		return "govulncheck"
+	}
+	if fileExistsAny("package.json") {
+		return "npm"
+	}
+	if fileExistsAny("requirements.txt", "pyproject.toml", "Pipfile") {
+		return "pip-audit"
+	}
+	return "govulncheck"
+}
+
+func buildAuditCommand(tool string) string {
+	switch tool {
+	case "govulncheck":
+		return "govulncheck -json ./..."
+	case "npm":
+		return "npm audit --json"
+	case "pip-audit":
+		return "pip-audit -f json"
+	}
+	return ""
+}
+
+// ---------- Output Parsing ----------
+
+// parseAuditOutput normalizes backend-specific JSON into findings.
+func parseAuditOutput(tool, output string) []depsVulnerability {
+	switch tool {
+	case "govulncheck":
+		return parseGovulncheck(output)
+	case "npm":
+		return parseNpmAudit(output)
+	case "pip-audit":
+		return parsePipAudit(output)
+	}
+	return nil
+}
+
+// parseGovulncheck parses the govulncheck -json stream: a sequence of
+// pretty-printed objects carrying "osv" entries (advisory details) and
+// "finding" entries (affected module + fixed version). Findings are
+// deduplicated per advisory.
+func parseGovulncheck(output string) []depsVulnerability {
+	type govulnMessage struct {
+		OSV *struct {
+			ID      string `json:"id"`
+			Summary string `json:"summary"`
+		} `json:"osv"`
+		Finding *struct {
+			OSV          string `json:"osv"`
+			FixedVersion string `json:"fixed_version"`
+			Trace        []struct {
+				Module  string `json:"module"`
+				Version string `json:"version"`
+			} `json:"trace"`
+		} `json:"finding"`
+	}
+
+	summaries := make(map[string]string)
+	byID := make(map[string]*depsVulnerability)
+	var order []string
+
+	dec := json.NewDecoder(strings.NewReader(output))
+	for {
+		var msg govulnMessage
+		if err := dec.Decode(&msg); err != nil {
+			break
+		}
+		if msg.OSV != nil {
+			summaries[msg.OSV.ID] = msg.OSV.Summary
+		}
+		if msg.Finding != nil && len(msg.Finding.Trace) > 0 {
+			f := msg.Finding
+			v, ok := byID[f.OSV]
+			if !ok {
+				v = &depsVulnerability{
+					ID:       f.OSV,
+					Package:  f.Trace[0].Module,
+					Version:  f.Trace[0].Version,
+					Severity: "unknown",
+				}
+				byID[f.OSV] = v
+				order = append(order, f.OSV)
+			}
+			if v.FixedIn == "" {
+				v.FixedIn = f.FixedVersion
+			}
+		}
+	}
+
+	vulns := make([]depsVulnerability, 0, len(order))
+	for _, id := range order {
+		v := byID[id]
+		v.Title = summaries[id]
+		vulns = append(vulns, *v)
+	}
+	return vulns
+}
+
+// parseNpmAudit parses `npm audit --json` (npm 7+ format).
+func parseNpmAudit(output string) []depsVulnerability {
+	var report struct {
+		Vulnerabilities map[string]struct {
+			Name         string            `json:"name"`
+			Severity     string            `json:"severity"`
+			Range        string            `json:"range"`
+			Via          []json.RawMessage `json:"via"`
+			FixAvailable json.RawMessage   `json:"fixAvailable"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		return nil
+	}
+
+	var vulns []depsVulnerability
+	for _, entry := range report.Vulnerabilities {
+		v := depsVulnerability{
+			Package:  entry.Name,
+			Version:  entry.Range,
+			Severity: entry.Severity,
+		}
+		// via mixes advisory objects and plain package-name strings.
+		for _, raw := range entry.Via {
+			var advisory struct {
+				Source int    `json:"source"`
+				Title  string `json:"title"`
+				URL    string `json:"url"`
+			}
+			if err := json.Unmarshal(raw, &advisory); err == nil && advisory.Title != "" {
+				v.Title = advisory.Title
+				if advisory.Source != 0 {
+					v.ID = fmt.Sprintf("GHSA-%d", advisory.Source)
+				}
+				if advisory.URL != "" {
+					v.ID = advisory.URL
+				}
+				break
+			}
+		}
+		var fix struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(entry.FixAvailable, &fix); err == nil && fix.Version != "" {
+			v.FixedIn = fix.Version
+		}
+		vulns = append(vulns, v)
+	}
+	return vulns
+}
+
+// parsePipAudit parses `pip-audit -f json` output.
+func parsePipAudit(output string) []depsVulnerability {
+	var report struct {
+		Dependencies []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Vulns   []struct {
+				ID          string   `json:"id"`
+				FixVersions []string `json:"fix_versions"`
+				Description string   `json:"description"`
+			} `json:"vulns"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		return nil
+	}
+
+	var vulns []depsVulnerability
+	for _, dep := range report.Dependencies {
+		for _, vuln := range dep.Vulns {
+			v := depsVulnerability{
+				ID:       vuln.ID,
+				Package:  dep.Name,
+				Version:  dep.Version,
+				Severity: "unknown",
+				Title:    truncateOutput(vuln.Description, 200),
+			}
+			if len(vuln.FixVersions) > 0 {
+				v.FixedIn = vuln.FixVersions[len(vuln.FixVersions)-1]
+			}
+			vulns = append(vulns, v)
+		}
+	}
+	return vulns
+}
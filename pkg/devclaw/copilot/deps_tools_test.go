@@ -0,0 +1,131 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGovulncheck(t *testing.T) {
+	output := `{
+  "config": {
+    "protocol_version": "v1.0.0"
+  }
+}
+{
+  "osv": {
+    "id": "GO-2024-1234",
+    "summary": "Denial of service in golang.org/x/net"
+  }
+}
+{
+  "finding": {
+    "osv": "GO-2024-1234",
+    "fixed_version": "v0.17.0",
+    "trace": [
+      {
+        "module": "golang.org/x/net",
+        "version": "v0.10.0"
+      }
+    ]
+  }
+}
+{
+  "finding": {
+    "osv": "GO-2024-1234",
+    "fixed_version": "v0.17.0",
+    "trace": [
+      {
+        "module": "golang.org/x/net",
+        "version": "v0.10.0",
+        "function": "Handle"
+      }
+    ]
+  }
+}
+`
+	vulns := parseGovulncheck(output)
+	if len(vulns) != 1 {
+		t.Fatalf("expected 1 deduplicated finding, got %d: %+v", len(vulns), vulns)
+	}
+	v := vulns[0]
+	if v.ID != "GO-2024-1234" || v.Package != "golang.org/x/net" || v.FixedIn != "v0.17.0" {
+		t.Errorf("unexpected finding: %+v", v)
+	}
+	if !strings.Contains(v.Title, "Denial of service") {
+		t.Errorf("summary not attached: %q", v.Title)
+	}
+}
+
+func TestParseNpmAudit(t *testing.T) {
+	output := `{
+  "vulnerabilities": {
+    "lodash": {
+      "name": "lodash",
+      "severity": "high",
+      "range": "<4.17.21",
+      "via": [
+        {
+          "source": 1065,
+          "title": "Prototype Pollution",
+          "url": "https://github.com/advisories/GHSA-xxxx"
+        }
+      ],
+      "fixAvailable": {
+        "name": "lodash",
+        "version": "4.17.21"
+      }
+    }
+  },
+  "metadata": {
+    "vulnerabilities": {"high": 1}
+  }
+}`
+	vulns := parseNpmAudit(output)
+	if len(vulns) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(vulns), vulns)
+	}
+	v := vulns[0]
+	if v.Package != "lodash" || v.Severity != "high" || v.FixedIn != "4.17.21" {
+		t.Errorf("unexpected finding: %+v", v)
+	}
+	if v.Title != "Prototype Pollution" {
+		t.Errorf("unexpected title: %q", v.Title)
+	}
+}
+
+func TestParsePipAudit(t *testing.T) {
+	output := `{
+  "dependencies": [
+    {
+      "name": "requests",
+      "version": "2.25.0",
+      "vulns": [
+        {
+          "id": "PYSEC-2023-74",
+          "fix_versions": ["2.31.0"],
+          "description": "Leak of Proxy-Authorization header"
+        }
+      ]
+    },
+    {
+      "name": "flask",
+      "version": "3.0.0",
+      "vulns": []
+    }
+  ]
+}`
+	vulns := parsePipAudit(output)
+	if len(vulns) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(vulns), vulns)
+	}
+	v := vulns[0]
+	if v.ID != "PYSEC-2023-74" || v.Package != "requests" || v.FixedIn != "2.31.0" {
+		t.Errorf("unexpected finding: %+v", v)
+	}
+}
+
+func TestParseAuditOutputUnknownTool(t *testing.T) {
+	if vulns := parseAuditOutput("cargo-audit", "{}"); vulns != nil {
+		t.Errorf("expected nil for unknown tool, got %+v", vulns)
+	}
+}
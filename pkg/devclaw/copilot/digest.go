@@ -0,0 +1,393 @@
+// Package copilot – digest.go implements the per-workspace activity digest:
+// a background routine that compiles each workspace's recent activity
+// (session traffic, token spend, scheduler outcomes, subagent results,
+// memory index growth) into a morning summary delivered to the workspace's
+// digest channel. Daily digests cover the last 24 hours; weekly digests
+// are delivered on Fridays and cover the last 7 days.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Digest frequencies.
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestConfig configures the workspace activity digest routine.
+type DigestConfig struct {
+	// Enabled turns the digest on/off.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// Frequency is "daily" or "weekly" (weekly delivers on Fridays).
+	Frequency string `yaml:"frequency" json:"frequency"`
+
+	// Time is the local delivery time in HH:MM format (default "08:00").
+	Time string `yaml:"time" json:"time"`
+
+	// Workspaces lists per-workspace delivery overrides. Empty = digest
+	// every active workspace to its most recently active session.
+	Workspaces []WorkspaceDigestConfig `yaml:"workspaces" json:"workspaces"`
+}
+
+// WorkspaceDigestConfig overrides digest delivery for one workspace.
+type WorkspaceDigestConfig struct {
+	// Workspace is the workspace ID.
+	Workspace string `yaml:"workspace" json:"workspace"`
+
+	// Channel overrides the delivery channel. Empty = the channel of the
+	// workspace's most recently active session.
+	Channel string `yaml:"channel" json:"channel"`
+
+	// ChatID overrides the delivery chat.
+	ChatID string `yaml:"chat_id" json:"chat_id"`
+
+	// Frequency overrides the global frequency for this workspace.
+	Frequency string `yaml:"frequency" json:"frequency"`
+}
+
+// DefaultDigestConfig returns default configuration (disabled).
+func DefaultDigestConfig() DigestConfig {
+	return DigestConfig{
+		Enabled:   false,
+		Frequency: DigestDaily,
+		Time:      "08:00",
+	}
+}
+
+// DigestRoutine compiles and delivers workspace activity digests.
+type DigestRoutine struct {
+	config    DigestConfig
+	assistant *Assistant
+	logger    *slog.Logger
+	cancel    context.CancelFunc
+
+	// lastSentMu guards lastSent (workspace ID -> delivery day).
+	lastSentMu sync.Mutex
+	lastSent   map[string]string
+}
+
+// workspaceActivity aggregates one workspace's activity for the digest window.
+type workspaceActivity struct {
+	ActiveSessions   int
+	TotalMessages    int
+	PromptTokens     int
+	CompletionTokens int
+	Requests         int
+	JobsOK           int
+	JobsFailed       int
+	JobFailures      []string
+	SubagentsDone    int
+	SubagentsFailed  int
+	SubagentLabels   []string
+	MemoryFiles      int
+	MemoryChunks     int
+}
+
+// empty reports whether there is anything worth delivering.
+func (w *workspaceActivity) empty() bool {
+	return w.ActiveSessions == 0 && w.JobsOK == 0 && w.JobsFailed == 0 &&
+		w.SubagentsDone == 0 && w.SubagentsFailed == 0
+}
+
+// NewDigestRoutine creates a new digest routine.
+func NewDigestRoutine(cfg DigestConfig, assistant *Assistant, logger *slog.Logger) *DigestRoutine {
+	if cfg.Frequency == "" {
+		cfg.Frequency = DigestDaily
+	}
+	if cfg.Time == "" {
+		cfg.Time = "08:00"
+	}
+	return &DigestRoutine{
+		config:    cfg,
+		assistant: assistant,
+		logger:    logger.With("component", "digest"),
+		lastSent:  make(map[string]string),
+	}
+}
+
+// Start begins the digest loop in a background goroutine.
+func (d *DigestRoutine) Start(ctx context.Context) {
+	if !d.config.Enabled {
+		d.logger.Debug("digest routine disabled")
+		return
+	}
+
+	dCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+
+	d.logger.Info("digest routine started",
+		"frequency", d.config.Frequency,
+		"time", d.config.Time,
+	)
+
+	go d.loop(dCtx)
+}
+
+// Stop shuts down the digest routine.
+func (d *DigestRoutine) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+}
+
+// loop ticks every minute and fires once per workspace per delivery day.
+func (d *DigestRoutine) loop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.tick(ctx, time.Now())
+		case <-ctx.Done():
+			d.logger.Info("digest routine stopped")
+			return
+		}
+	}
+}
+
+// tick delivers due digests. Exposed for testing via the now parameter.
+func (d *DigestRoutine) tick(ctx context.Context, now time.Time) {
+	loc, err := time.LoadLocation(d.assistant.config.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+
+	target := parseTimeMinutes(d.config.Time)
+	if target < 0 {
+		target = 8 * 60
+	}
+	if now.Hour()*60+now.Minute() < target {
+		return
+	}
+
+	for _, ws := range d.assistant.workspaceMgr.List() {
+		if !ws.Active {
+			continue
+		}
+
+		override := d.workspaceOverride(ws.ID)
+		if len(d.config.Workspaces) > 0 && override == nil {
+			// Explicit workspace list: skip unlisted workspaces.
+			continue
+		}
+
+		frequency := d.config.Frequency
+		if override != nil && override.Frequency != "" {
+			frequency = override.Frequency
+		}
+		if frequency == DigestWeekly && now.Weekday() != time.Friday {
+			continue
+		}
+
+		day := now.Format("2006-01-02")
+		d.lastSentMu.Lock()
+		sent := d.lastSent[ws.ID] == day
+		d.lastSentMu.Unlock()
+		if sent {
+			continue
+		}
+
+		window := 24 * time.Hour
+		if frequency == DigestWeekly {
+			window = 7 * 24 * time.Hour
+		}
+
+		text := d.buildDigest(ws, now.Add(-window), frequency)
+
+		channel, chatID := d.deliveryTarget(ws, override)
+		if channel == "" || chatID == "" {
+			d.logger.Debug("digest skipped - no delivery target", "workspace", ws.ID)
+			continue
+		}
+
+		if text == "" {
+			d.logger.Debug("digest skipped - no activity", "workspace", ws.ID)
+		} else if err := d.assistant.sendProactive(ctx, channel, chatID, text, false); err != nil {
+			d.logger.Warn("digest delivery failed",
+				"workspace", ws.ID, "channel", channel, "error", err)
+			continue
+		} else {
+			d.logger.Info("digest delivered",
+				"workspace", ws.ID, "channel", channel, "frequency", frequency)
+		}
+
+		// Mark the day handled even when there was nothing to report, so
+		// quiet workspaces are not re-checked every minute.
+		d.lastSentMu.Lock()
+		d.lastSent[ws.ID] = day
+		d.lastSentMu.Unlock()
+	}
+}
+
+// workspaceOverride returns the per-workspace config entry, or nil.
+func (d *DigestRoutine) workspaceOverride(wsID string) *WorkspaceDigestConfig {
+	for i := range d.config.Workspaces {
+		if d.config.Workspaces[i].Workspace == wsID {
+			return &d.config.Workspaces[i]
+		}
+	}
+	return nil
+}
+
+// deliveryTarget resolves where a workspace's digest is sent: the config
+// override when set, otherwise the workspace's most recently active session.
+func (d *DigestRoutine) deliveryTarget(ws *Workspace, override *WorkspaceDigestConfig) (channel, chatID string) {
+	if override != nil && override.Channel != "" && override.ChatID != "" {
+		return override.Channel, override.ChatID
+	}
+
+	var latest time.Time
+	for _, info := range d.assistant.workspaceMgr.ListAllSessions() {
+		if info.WorkspaceID != ws.ID {
+			continue
+		}
+		if info.LastActiveAt.After(latest) {
+			latest = info.LastActiveAt
+			channel = info.Channel
+			chatID = info.ChatID
+		}
+	}
+	return channel, chatID
+}
+
+// buildDigest renders the digest text for a workspace, or "" when there
+// was no activity in the window.
+func (d *DigestRoutine) buildDigest(ws *Workspace, since time.Time, frequency string) string {
+	activity := d.collectActivity(ws, since)
+	if activity.empty() {
+		return ""
+	}
+	return formatDigest(ws, activity, frequency)
+}
+
+// collectActivity aggregates workspace activity since the given cutoff.
+func (d *DigestRoutine) collectActivity(ws *Workspace, since time.Time) *workspaceActivity {
+	a := d.assistant
+	activity := &workspaceActivity{}
+
+	// Session traffic and token spend. Chats served by this workspace also
+	// scope scheduler jobs below.
+	chats := make(map[string]bool)
+	for _, info := range a.workspaceMgr.ListAllSessions() {
+		if info.WorkspaceID != ws.ID {
+			continue
+		}
+		chats[info.Channel+"|"+info.ChatID] = true
+		if info.LastActiveAt.Before(since) {
+			continue
+		}
+		activity.ActiveSessions++
+		activity.TotalMessages += info.MessageCount
+		if session := a.workspaceMgr.FindSessionByID(info.ID); session != nil {
+			prompt, completion, requests := session.GetTokenUsage()
+			activity.PromptTokens += prompt
+			activity.CompletionTokens += completion
+			activity.Requests += requests
+		}
+	}
+
+	// Scheduler outcomes for jobs targeting this workspace's chats.
+	if a.scheduler != nil {
+		for _, job := range a.scheduler.List() {
+			if job.LastRunAt == nil || job.LastRunAt.Before(since) {
+				continue
+			}
+			if !chats[job.Channel+"|"+job.ChatID] {
+				continue
+			}
+			if job.LastError != "" {
+				activity.JobsFailed++
+				activity.JobFailures = append(activity.JobFailures,
+					fmt.Sprintf("%s: %s", job.ID, truncate(job.LastError, 80)))
+			} else {
+				activity.JobsOK++
+			}
+		}
+	}
+
+	// Subagent results, scoped via the parent session's workspace.
+	if a.subagentMgr != nil {
+		for _, run := range a.subagentMgr.List() {
+			if run.CompletedAt.IsZero() || run.CompletedAt.Before(since) {
+				continue
+			}
+			_, runWS := a.workspaceMgr.GetSessionByID(run.ParentSessionID)
+			if runWS == nil || runWS.ID != ws.ID {
+				continue
+			}
+			if run.Status == SubagentStatusFailed || run.Status == SubagentStatusTimeout {
+				activity.SubagentsFailed++
+			} else {
+				activity.SubagentsDone++
+			}
+			if run.Label != "" {
+				activity.SubagentLabels = append(activity.SubagentLabels, run.Label)
+			}
+		}
+		sort.Strings(activity.SubagentLabels)
+	}
+
+	// Memory index size (shared across workspaces, shown for context).
+	if a.sqliteMemory != nil {
+		activity.MemoryFiles = a.sqliteMemory.FileCount()
+		activity.MemoryChunks = a.sqliteMemory.ChunkCount()
+	}
+
+	return activity
+}
+
+// formatDigest renders the digest message body.
+func formatDigest(ws *Workspace, activity *workspaceActivity, frequency string) string {
+	period := "last 24h"
+	if frequency == DigestWeekly {
+		period = "last 7 days"
+	}
+
+	name := ws.Name
+	if name == "" {
+		name = ws.ID
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%s digest (%s)*\n", name, period))
+
+	if activity.ActiveSessions > 0 {
+		b.WriteString(fmt.Sprintf("\n💬 %d active session(s), %d message(s) in history",
+			activity.ActiveSessions, activity.TotalMessages))
+	}
+	if activity.Requests > 0 {
+		b.WriteString(fmt.Sprintf("\n🔢 Token spend: %d prompt + %d completion over %d request(s)",
+			activity.PromptTokens, activity.CompletionTokens, activity.Requests))
+	}
+	if activity.JobsOK > 0 || activity.JobsFailed > 0 {
+		b.WriteString(fmt.Sprintf("\n⏰ Scheduled jobs: %d ok, %d failed",
+			activity.JobsOK, activity.JobsFailed))
+		for _, failure := range activity.JobFailures {
+			b.WriteString("\n  ⚠️ " + failure)
+		}
+	}
+	if activity.SubagentsDone > 0 || activity.SubagentsFailed > 0 {
+		b.WriteString(fmt.Sprintf("\n🤖 Subagents: %d completed, %d failed",
+			activity.SubagentsDone, activity.SubagentsFailed))
+		if len(activity.SubagentLabels) > 0 {
+			b.WriteString(" (" + strings.Join(activity.SubagentLabels, ", ") + ")")
+		}
+	}
+	if activity.MemoryFiles > 0 {
+		b.WriteString(fmt.Sprintf("\n🧠 Memory index: %d file(s), %d chunk(s)",
+			activity.MemoryFiles, activity.MemoryChunks))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFormatDigest(t *testing.T) {
+	ws := &Workspace{ID: "work", Name: "Work"}
+	activity := &workspaceActivity{
+		ActiveSessions:   2,
+		TotalMessages:    40,
+		PromptTokens:     1200,
+		CompletionTokens: 300,
+		Requests:         15,
+		JobsOK:           3,
+		JobsFailed:       1,
+		JobFailures:      []string{"backup: disk full"},
+		SubagentsDone:    2,
+		SubagentLabels:   []string{"research", "review"},
+	}
+
+	text := formatDigest(ws, activity, DigestDaily)
+
+	if !strings.Contains(text, "*Work digest (last 24h)*") {
+		t.Errorf("missing header: %q", text)
+	}
+	if !strings.Contains(text, "2 active session(s)") {
+		t.Errorf("missing session line: %q", text)
+	}
+	if !strings.Contains(text, "1200 prompt + 300 completion") {
+		t.Errorf("missing token line: %q", text)
+	}
+	if !strings.Contains(text, "backup: disk full") {
+		t.Errorf("missing job failure: %q", text)
+	}
+	if !strings.Contains(text, "research, review") {
+		t.Errorf("missing subagent labels: %q", text)
+	}
+
+	weekly := formatDigest(ws, activity, DigestWeekly)
+	if !strings.Contains(weekly, "last 7 days") {
+		t.Errorf("weekly digest should cover 7 days: %q", weekly)
+	}
+
+	// Workspace name falls back to the ID.
+	unnamed := formatDigest(&Workspace{ID: "dev"}, activity, DigestDaily)
+	if !strings.Contains(unnamed, "*dev digest") {
+		t.Errorf("expected ID fallback in header: %q", unnamed)
+	}
+}
+
+func TestWorkspaceActivityEmpty(t *testing.T) {
+	if !(&workspaceActivity{}).empty() {
+		t.Error("zero activity should be empty")
+	}
+	if !(&workspaceActivity{MemoryFiles: 10, MemoryChunks: 50}).empty() {
+		t.Error("memory index size alone is not activity")
+	}
+	if (&workspaceActivity{ActiveSessions: 1}).empty() {
+		t.Error("active sessions count as activity")
+	}
+	if (&workspaceActivity{JobsFailed: 1}).empty() {
+		t.Error("failed jobs count as activity")
+	}
+}
+
+func TestDigestWorkspaceOverride(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	d := NewDigestRoutine(DigestConfig{
+		Enabled:   true,
+		Frequency: DigestDaily,
+		Workspaces: []WorkspaceDigestConfig{
+			{Workspace: "work", Channel: "whatsapp", ChatID: "123", Frequency: DigestWeekly},
+		},
+	}, nil, logger)
+
+	override := d.workspaceOverride("work")
+	if override == nil {
+		t.Fatal("expected override for configured workspace")
+	}
+	if override.Frequency != DigestWeekly {
+		t.Errorf("got frequency %q", override.Frequency)
+	}
+	if d.workspaceOverride("personal") != nil {
+		t.Error("expected nil override for unconfigured workspace")
+	}
+}
+
+func TestDigestDefaults(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	d := NewDigestRoutine(DigestConfig{Enabled: true}, nil, logger)
+
+	if d.config.Frequency != DigestDaily {
+		t.Errorf("expected daily default, got %q", d.config.Frequency)
+	}
+	if d.config.Time != "08:00" {
+		t.Errorf("expected 08:00 default, got %q", d.config.Time)
+	}
+}
@@ -0,0 +1,200 @@
+// Package copilot – dnd.go implements do-not-disturb silence windows for
+// proactive features (heartbeat, scheduler, digests). During an active
+// quiet window, proactive messages are held in a pending queue and
+// delivered when the window ends. Scheduler jobs can set urgent=true to
+// bypass the window. Windows can be configured globally (dnd.default),
+// per user (dnd.users) and per workspace (workspace quiet_hours); a
+// message is held while ANY applicable window is active.
+package copilot
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// DNDConfig configures do-not-disturb silence windows.
+type DNDConfig struct {
+	// Enabled turns do-not-disturb on/off.
+	Enabled bool `yaml:"enabled"`
+
+	// Default is the silence window applied to everyone unless a more
+	// specific user or workspace window exists.
+	Default *QuietHoursConfig `yaml:"default"`
+
+	// Users maps user JIDs to their personal silence windows.
+	Users map[string]*QuietHoursConfig `yaml:"users"`
+}
+
+// heldMessage is a proactive message waiting for a quiet window to end.
+type heldMessage struct {
+	Channel   string
+	ChatID    string
+	UserJID   string
+	Workspace *Workspace
+	Content   string
+	QueuedAt  time.Time
+}
+
+// DNDManager holds proactive messages during quiet windows and flushes
+// them when the windows end.
+type DNDManager struct {
+	config DNDConfig
+	logger *slog.Logger
+	cancel context.CancelFunc
+
+	// sendFunc delivers a flushed message (wired to channelMgr.Send).
+	sendFunc func(channel, chatID, content string) error
+
+	// mu guards pending.
+	mu      sync.Mutex
+	pending []heldMessage
+}
+
+// NewDNDManager creates a do-not-disturb manager.
+func NewDNDManager(cfg DNDConfig, logger *slog.Logger) *DNDManager {
+	// Normalize user JID keys so lookups match incoming sender format.
+	if len(cfg.Users) > 0 {
+		normalized := make(map[string]*QuietHoursConfig, len(cfg.Users))
+		for jid, qh := range cfg.Users {
+			normalized[normalizeJID(jid)] = qh
+		}
+		cfg.Users = normalized
+	}
+	return &DNDManager{
+		config: cfg,
+		logger: logger.With("component", "dnd"),
+	}
+}
+
+// SetSendFunc sets the delivery function for flushed messages.
+func (d *DNDManager) SetSendFunc(fn func(channel, chatID, content string) error) {
+	d.sendFunc = fn
+}
+
+// IsQuiet reports whether a proactive message for the given user and
+// workspace should currently be held. Any active applicable window holds.
+func (d *DNDManager) IsQuiet(userJID string, ws *Workspace, now time.Time) bool {
+	if !d.config.Enabled {
+		return false
+	}
+	if userJID != "" {
+		if qh, ok := d.config.Users[normalizeJID(userJID)]; ok && quietHoursActive(qh, now) {
+			return true
+		}
+	}
+	if ws != nil && quietHoursActive(ws.QuietHours, now) {
+		return true
+	}
+	return quietHoursActive(d.config.Default, now)
+}
+
+// Hold queues a proactive message for delivery after the quiet window.
+func (d *DNDManager) Hold(channel, chatID, userJID string, ws *Workspace, content string) {
+	d.mu.Lock()
+	d.pending = append(d.pending, heldMessage{
+		Channel:   channel,
+		ChatID:    chatID,
+		UserJID:   userJID,
+		Workspace: ws,
+		Content:   content,
+		QueuedAt:  time.Now(),
+	})
+	count := len(d.pending)
+	d.mu.Unlock()
+
+	d.logger.Info("proactive message held (do-not-disturb)",
+		"channel", channel, "chat_id", chatID, "pending", count)
+}
+
+// PendingCount returns how many messages are waiting for delivery.
+func (d *DNDManager) PendingCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pending)
+}
+
+// Start begins the background flush loop.
+func (d *DNDManager) Start(ctx context.Context) {
+	dCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+
+	d.logger.Info("do-not-disturb manager started",
+		"users", len(d.config.Users),
+		"default_window", d.config.Default != nil,
+	)
+
+	go d.loop(dCtx)
+}
+
+// Stop shuts down the flush loop.
+func (d *DNDManager) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+}
+
+// loop flushes released messages once a minute.
+func (d *DNDManager) loop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.flush(time.Now())
+		case <-ctx.Done():
+			d.logger.Info("do-not-disturb manager stopped")
+			return
+		}
+	}
+}
+
+// flush delivers pending messages whose quiet windows have ended.
+func (d *DNDManager) flush(now time.Time) {
+	d.mu.Lock()
+	var release []heldMessage
+	var keep []heldMessage
+	for _, m := range d.pending {
+		if d.IsQuiet(m.UserJID, m.Workspace, now) {
+			keep = append(keep, m)
+		} else {
+			release = append(release, m)
+		}
+	}
+	d.pending = keep
+	d.mu.Unlock()
+
+	if len(release) == 0 || d.sendFunc == nil {
+		return
+	}
+
+	for _, m := range release {
+		if err := d.sendFunc(m.Channel, m.ChatID, m.Content); err != nil {
+			d.logger.Warn("held message delivery failed",
+				"channel", m.Channel, "chat_id", m.ChatID, "error", err)
+			continue
+		}
+		d.logger.Info("held message delivered",
+			"channel", m.Channel, "chat_id", m.ChatID,
+			"held_for", now.Sub(m.QueuedAt).Round(time.Second).String())
+	}
+}
+
+// sendProactive delivers a bot-initiated message, honoring do-not-disturb
+// windows: held messages are queued and flushed when the window ends.
+// Urgent messages bypass DND entirely. In DMs the chat ID doubles as the
+// user JID for per-user windows; groups only match workspace/default ones.
+func (a *Assistant) sendProactive(ctx context.Context, channel, chatID, content string, urgent bool) error {
+	if a.dndMgr != nil && !urgent {
+		_, ws := a.workspaceMgr.GetSessionByID(MakeSessionID(channel, chatID))
+		if a.dndMgr.IsQuiet(chatID, ws, time.Now()) {
+			a.dndMgr.Hold(channel, chatID, chatID, ws, content)
+			return nil
+		}
+	}
+	return a.channelMgr.Send(ctx, channel, chatID, &channels.OutgoingMessage{Content: content})
+}
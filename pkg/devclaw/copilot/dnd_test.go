@@ -0,0 +1,114 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursActive(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 6, 2, hour, minute, 0, 0, time.UTC)
+	}
+
+	sameDay := &QuietHoursConfig{Start: "12:00", End: "14:00"}
+	if !quietHoursActive(sameDay, at(13, 0)) {
+		t.Error("13:00 should be inside 12:00-14:00")
+	}
+	if quietHoursActive(sameDay, at(14, 0)) {
+		t.Error("window end is exclusive")
+	}
+
+	overnight := &QuietHoursConfig{Start: "22:00", End: "08:00"}
+	if !quietHoursActive(overnight, at(23, 30)) {
+		t.Error("23:30 should be inside 22:00-08:00")
+	}
+	if !quietHoursActive(overnight, at(7, 0)) {
+		t.Error("07:00 should be inside 22:00-08:00")
+	}
+	if quietHoursActive(overnight, at(12, 0)) {
+		t.Error("12:00 should be outside 22:00-08:00")
+	}
+
+	if quietHoursActive(nil, at(13, 0)) {
+		t.Error("nil window is never active")
+	}
+	if quietHoursActive(&QuietHoursConfig{Start: "22:00"}, at(23, 0)) {
+		t.Error("incomplete window is never active")
+	}
+}
+
+func TestDNDIsQuiet(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	night := time.Date(2025, 6, 2, 23, 0, 0, 0, time.UTC)
+	day := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	d := NewDNDManager(DNDConfig{
+		Enabled: true,
+		Default: &QuietHoursConfig{Start: "22:00", End: "08:00"},
+		Users: map[string]*QuietHoursConfig{
+			"alice@s.whatsapp.net": {Start: "10:00", End: "13:00"},
+		},
+	}, logger)
+
+	if !d.IsQuiet("bob@s.whatsapp.net", nil, night) {
+		t.Error("default window should hold at night")
+	}
+	if d.IsQuiet("bob@s.whatsapp.net", nil, day) {
+		t.Error("no window active at noon for bob")
+	}
+	if !d.IsQuiet("alice@s.whatsapp.net", nil, day) {
+		t.Error("alice's personal window should hold at noon")
+	}
+
+	ws := &Workspace{ID: "work", QuietHours: &QuietHoursConfig{Start: "11:00", End: "14:00"}}
+	if !d.IsQuiet("bob@s.whatsapp.net", ws, day) {
+		t.Error("workspace window should hold at noon")
+	}
+
+	disabled := NewDNDManager(DNDConfig{
+		Default: &QuietHoursConfig{Start: "22:00", End: "08:00"},
+	}, logger)
+	if disabled.IsQuiet("bob@s.whatsapp.net", nil, night) {
+		t.Error("disabled DND never holds")
+	}
+}
+
+func TestDNDHoldAndFlush(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	d := NewDNDManager(DNDConfig{
+		Enabled: true,
+		Default: &QuietHoursConfig{Start: "22:00", End: "08:00"},
+	}, logger)
+
+	var delivered []string
+	d.SetSendFunc(func(channel, chatID, content string) error {
+		delivered = append(delivered, channel+"|"+chatID+"|"+content)
+		return nil
+	})
+
+	d.Hold("whatsapp", "123", "123", nil, "reminder A")
+	d.Hold("telegram", "456", "456", nil, "reminder B")
+	if d.PendingCount() != 2 {
+		t.Fatalf("expected 2 pending, got %d", d.PendingCount())
+	}
+
+	// Still inside the window: nothing flushes.
+	d.flush(time.Date(2025, 6, 2, 23, 0, 0, 0, time.UTC))
+	if len(delivered) != 0 || d.PendingCount() != 2 {
+		t.Fatalf("flush inside window delivered %d, pending %d", len(delivered), d.PendingCount())
+	}
+
+	// Window ended: everything flushes in order.
+	d.flush(time.Date(2025, 6, 3, 8, 30, 0, 0, time.UTC))
+	if d.PendingCount() != 0 {
+		t.Errorf("expected empty queue, got %d pending", d.PendingCount())
+	}
+	if len(delivered) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(delivered))
+	}
+	if delivered[0] != "whatsapp|123|reminder A" {
+		t.Errorf("unexpected first delivery: %q", delivered[0])
+	}
+}
@@ -0,0 +1,211 @@
+// Package copilot – doc_summarizer.go implements map-reduce summarization
+// for documents too large to inline into the conversation. The document is
+// split into chunks, each chunk is summarized in parallel (optionally with a
+// cheaper model), and the chunk summaries are synthesized into one faithful
+// summary. Progress is reported through the session's ProgressSender.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+const (
+	// docSummaryChunkSize is the target size of one map-phase chunk.
+	docSummaryChunkSize = 12000
+
+	// docSummaryMaxParallel caps concurrent chunk summarization calls.
+	docSummaryMaxParallel = 4
+
+	// docSummaryMapSystemPrompt instructs the map phase, shared by the live
+	// and batch paths.
+	docSummaryMapSystemPrompt = "You summarize document sections faithfully. Preserve key facts, figures, names, dates, amounts, and obligations. Do not add commentary or interpretation. Be concise but complete."
+)
+
+// summarizeLongDocument produces a map-reduce summary of a large document.
+// Chunks are summarized concurrently with the configured summary model (or
+// the main model), then synthesized into a single summary. Returns an error
+// if any phase fails so the caller can fall back to truncation.
+func (a *Assistant) summarizeLongDocument(ctx context.Context, filename, text string, logger *slog.Logger) (string, error) {
+	if a.llmClient == nil {
+		return "", fmt.Errorf("no LLM client available")
+	}
+
+	chunks := chunkDocumentText(text, docSummaryChunkSize)
+	model := a.MediaConfig().SummaryModel
+	if model == "" {
+		// No dedicated summary model: prefer the utility model when set.
+		model = a.llmClient.UtilityModel()
+	}
+	progress := ProgressSenderFromContext(ctx)
+
+	if progress != nil {
+		progress(ctx, fmt.Sprintf("📄 Summarizing %s (%d sections)…", filename, len(chunks)))
+	}
+	logger.Info("summarizing long document",
+		"filename", filename,
+		"chars", len(text),
+		"chunks", len(chunks),
+		"model", model)
+
+	// Batch path: when provider batch APIs are enabled for document
+	// summarization, run the map phase as one batch job (~50% cost, higher
+	// latency). Any batch failure falls back to live calls.
+	var summaries []string
+	if len(chunks) > 1 && a.llmClient.BatchEnabledFor(batchOpDocSummary) {
+		batched, err := a.batchSummarizeChunks(ctx, model, filename, chunks)
+		if err != nil {
+			logger.Warn("batch summarization failed, falling back to live calls", "error", err)
+		} else {
+			summaries = batched
+		}
+	}
+
+	// Map: summarize each chunk in parallel, bounded by a semaphore.
+	if summaries == nil {
+		summaries = make([]string, len(chunks))
+		errs := make([]error, len(chunks))
+		sem := make(chan struct{}, docSummaryMaxParallel)
+		var wg sync.WaitGroup
+
+		for i, chunk := range chunks {
+			wg.Add(1)
+			go func(i int, chunk string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				prompt := fmt.Sprintf("Section %d of %d from the document %q:\n\n%s", i+1, len(chunks), filename, chunk)
+				summary, err := a.completeForSummary(ctx, model,
+					docSummaryMapSystemPrompt,
+					prompt)
+				if err != nil {
+					errs[i] = fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+					return
+				}
+				summaries[i] = summary
+			}(i, chunk)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				return "", fmt.Errorf("map phase: %w", err)
+			}
+		}
+	}
+
+	// Single chunk: no reduce phase needed.
+	if len(summaries) == 1 {
+		return formatDocSummary(filename, len(text), summaries[0]), nil
+	}
+
+	if progress != nil {
+		progress(ctx, "📄 Synthesizing final summary…")
+	}
+
+	var b strings.Builder
+	for i, s := range summaries {
+		b.WriteString(fmt.Sprintf("## Section %d\n%s\n\n", i+1, s))
+	}
+	final, err := a.completeForSummary(ctx, model,
+		"You combine section summaries of one document into a single faithful summary. Merge overlapping points, keep all key facts, figures, names, dates, and obligations, and preserve the document's structure where it helps.",
+		fmt.Sprintf("Section summaries of the document %q:\n\n%s", filename, b.String()))
+	if err != nil {
+		return "", fmt.Errorf("reduce phase: %w", err)
+	}
+
+	return formatDocSummary(filename, len(text), final), nil
+}
+
+// batchSummarizeChunks runs the map phase through the provider's batch API.
+// Returns the chunk summaries in order, or an error if the job or any single
+// chunk failed (the caller falls back to live calls).
+func (a *Assistant) batchSummarizeChunks(ctx context.Context, model, filename string, chunks []string) ([]string, error) {
+	requests := make([]BatchRequest, len(chunks))
+	for i, chunk := range chunks {
+		requests[i] = BatchRequest{
+			CustomID: fmt.Sprintf("chunk-%d", i),
+			System:   docSummaryMapSystemPrompt,
+			Prompt:   fmt.Sprintf("Section %d of %d from the document %q:\n\n%s", i+1, len(chunks), filename, chunk),
+		}
+	}
+
+	results, err := a.llmClient.CompleteBatch(ctx, model, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]string, len(chunks))
+	for i := range chunks {
+		res, ok := results[fmt.Sprintf("chunk-%d", i)]
+		if !ok {
+			return nil, fmt.Errorf("chunk %d/%d: missing from batch results", i+1, len(chunks))
+		}
+		if res.Err != nil {
+			return nil, fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), res.Err)
+		}
+		summaries[i] = res.Content
+	}
+	return summaries, nil
+}
+
+// completeForSummary runs a simple completion, using modelOverride when set.
+func (a *Assistant) completeForSummary(ctx context.Context, modelOverride, systemPrompt, userMessage string) (string, error) {
+	if modelOverride == "" {
+		return a.llmClient.Complete(ctx, systemPrompt, nil, userMessage)
+	}
+	messages := []chatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userMessage},
+	}
+	resp, err := a.llmClient.CompleteWithToolsUsingModel(ctx, modelOverride, messages, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+// formatDocSummary wraps the summary so the agent knows it reads a condensed
+// version rather than the raw document.
+func formatDocSummary(filename string, originalChars int, summary string) string {
+	return fmt.Sprintf("[Summary of %s — original is %d characters, condensed via map-reduce]\n%s",
+		filename, originalChars, strings.TrimSpace(summary))
+}
+
+// chunkDocumentText splits text into chunks of roughly chunkSize characters,
+// preferring paragraph boundaries so sections stay coherent.
+func chunkDocumentText(text string, chunkSize int) []string {
+	if len(text) <= chunkSize {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	for _, para := range strings.Split(text, "\n\n") {
+		// Oversized paragraph: hard-split it.
+		for len(para) > chunkSize {
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+			chunks = append(chunks, para[:chunkSize])
+			para = para[chunkSize:]
+		}
+		if current.Len() > 0 && current.Len()+len(para)+2 > chunkSize {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(para)
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
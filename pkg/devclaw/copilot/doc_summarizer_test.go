@@ -0,0 +1,59 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunkDocumentText(t *testing.T) {
+	// Small text: single chunk, unchanged.
+	small := "just a short document"
+	chunks := chunkDocumentText(small, 100)
+	if len(chunks) != 1 || chunks[0] != small {
+		t.Fatalf("expected single unchanged chunk, got %v", chunks)
+	}
+
+	// Paragraphs should not be split across chunks when they fit.
+	paras := []string{
+		strings.Repeat("a", 40),
+		strings.Repeat("b", 40),
+		strings.Repeat("c", 40),
+	}
+	chunks = chunkDocumentText(strings.Join(paras, "\n\n"), 90)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %v", len(chunks), chunks)
+	}
+	for _, chunk := range chunks {
+		if len(chunk) > 90 {
+			t.Errorf("chunk exceeds size limit: %d chars", len(chunk))
+		}
+	}
+
+	// No content may be lost.
+	joined := strings.Join(chunks, "\n\n")
+	for _, para := range paras {
+		if !strings.Contains(joined, para) {
+			t.Errorf("paragraph lost during chunking")
+		}
+	}
+
+	// A single oversized paragraph is hard-split.
+	huge := strings.Repeat("x", 250)
+	chunks = chunkDocumentText(huge, 100)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks for oversized paragraph, got %d", len(chunks))
+	}
+	if total := len(chunks[0]) + len(chunks[1]) + len(chunks[2]); total != 250 {
+		t.Errorf("content lost in hard split: %d of 250 chars", total)
+	}
+}
+
+func TestFormatDocSummary(t *testing.T) {
+	out := formatDocSummary("contract.pdf", 90000, "  the summary  ")
+	if !strings.Contains(out, "contract.pdf") || !strings.Contains(out, "90000") {
+		t.Errorf("missing metadata: %q", out)
+	}
+	if !strings.HasSuffix(out, "the summary") {
+		t.Errorf("summary not trimmed: %q", out)
+	}
+}
@@ -0,0 +1,59 @@
+// Package copilot – event_bridge.go connects the HookManager to the EventBus.
+// Every dispatched hook event is re-published on the bus: the well-known
+// lifecycle events map to typed "activity" events, everything else goes out
+// on the "hook" stream. External subscribers (dashboards, integrations)
+// consume the bus via the gateway's /api/events SSE endpoint.
+package copilot
+
+import "context"
+
+// hookActivityTypes maps hook events to their typed activity event names.
+var hookActivityTypes = map[HookEvent]string{
+	HookUserPromptSubmit: EventMessageReceived,
+	HookAgentStart:       EventRunStarted,
+	HookPostToolUse:      EventToolExecuted,
+	HookMemorySave:       EventMemorySaved,
+}
+
+// initEventBridge registers the hook that mirrors hook events onto the event bus.
+func (a *Assistant) initEventBridge() {
+	_ = a.hookMgr.Register(&RegisteredHook{
+		Name:        "event-bus-bridge",
+		Description: "Re-publishes hook events on the event bus for external subscribers",
+		Source:      "system",
+		Events:      AllHookEvents,
+		Priority:    900, // Observe only — run after hooks that may modify the payload.
+		Enabled:     true,
+		Handler:     a.bridgeHookEvent,
+	})
+}
+
+// bridgeHookEvent re-emits a hook payload on the event bus. It never blocks
+// the operation being hooked.
+func (a *Assistant) bridgeHookEvent(_ context.Context, payload HookPayload) HookAction {
+	data := make(map[string]any)
+	if payload.Channel != "" {
+		data["channel"] = payload.Channel
+	}
+	if payload.ToolName != "" {
+		data["tool"] = payload.ToolName
+	}
+	if payload.Message != "" {
+		data["message"] = payload.Message
+	}
+	if payload.Error != nil {
+		data["error"] = payload.Error.Error()
+	}
+
+	stream, eventType := "hook", string(payload.Event)
+	if t, ok := hookActivityTypes[payload.Event]; ok {
+		stream, eventType = "activity", t
+	}
+	a.events.Emit(AgentEvent{
+		SessionID: payload.SessionID,
+		Stream:    stream,
+		Type:      eventType,
+		Data:      data,
+	})
+	return HookAction{}
+}
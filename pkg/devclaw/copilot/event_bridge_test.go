@@ -0,0 +1,63 @@
+package copilot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBridgeHookEvent(t *testing.T) {
+	a := &Assistant{events: NewEventBus()}
+
+	var got []AgentEvent
+	unsubscribe := a.events.Subscribe(func(ev AgentEvent) { got = append(got, ev) })
+	defer unsubscribe()
+
+	// A mapped hook event becomes a typed activity event.
+	a.bridgeHookEvent(context.Background(), HookPayload{
+		Event:     HookUserPromptSubmit,
+		SessionID: "whatsapp:123",
+		Channel:   "whatsapp",
+	})
+	// An unmapped hook event is forwarded on the "hook" stream.
+	a.bridgeHookEvent(context.Background(), HookPayload{
+		Event: HookHeartbeat,
+		Error: errors.New("boom"),
+	})
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if got[0].Stream != "activity" || got[0].Type != EventMessageReceived {
+		t.Errorf("mapped event: got stream=%q type=%q", got[0].Stream, got[0].Type)
+	}
+	if got[0].SessionID != "whatsapp:123" {
+		t.Errorf("session ID not propagated: %q", got[0].SessionID)
+	}
+	if got[1].Stream != "hook" || got[1].Type != string(HookHeartbeat) {
+		t.Errorf("unmapped event: got stream=%q type=%q", got[1].Stream, got[1].Type)
+	}
+	data, ok := got[1].Data.(map[string]any)
+	if !ok || data["error"] != "boom" {
+		t.Errorf("error not included in data: %#v", got[1].Data)
+	}
+}
+
+func TestEmitActivity(t *testing.T) {
+	eb := NewEventBus()
+
+	var got []AgentEvent
+	eb.Subscribe(func(ev AgentEvent) { got = append(got, ev) })
+
+	eb.EmitActivity(EventJobFired, "scheduler:j1", map[string]any{"job_id": "j1"})
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got))
+	}
+	if got[0].Stream != "activity" || got[0].Type != EventJobFired {
+		t.Errorf("got stream=%q type=%q", got[0].Stream, got[0].Type)
+	}
+	if got[0].Timestamp.IsZero() {
+		t.Error("timestamp should be auto-assigned")
+	}
+}
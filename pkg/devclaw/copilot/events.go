@@ -7,6 +7,8 @@
 //   - "assistant": delta (text tokens), thinking_start, thinking_delta, thinking_end
 //   - "tool": tool_use, tool_result
 //   - "error": agent errors, LLM errors
+//   - "activity": message_received, run_started, tool_executed, memory_saved, job_fired
+//   - "hook": every other HookManager event, re-published by the event bridge
 package copilot
 
 import (
@@ -26,6 +28,17 @@ type AgentEvent struct {
 	Data      any       `json:"data"`
 }
 
+// Activity event types published on the "activity" stream. These are the
+// coarse-grained events external subscribers (dashboards, integrations)
+// consume via the gateway's /api/events endpoint.
+const (
+	EventMessageReceived = "message_received"
+	EventRunStarted      = "run_started"
+	EventToolExecuted    = "tool_executed"
+	EventMemorySaved     = "memory_saved"
+	EventJobFired        = "job_fired"
+)
+
 // EventListener is a callback that receives agent events.
 type EventListener func(event AgentEvent)
 
@@ -175,6 +188,17 @@ func (eb *EventBus) EmitError(runID, sessionID, message string) {
 	})
 }
 
+// EmitActivity publishes a typed activity event (message_received, run_started,
+// tool_executed, memory_saved, job_fired) for external subscribers.
+func (eb *EventBus) EmitActivity(eventType, sessionID string, data map[string]any) {
+	eb.Emit(AgentEvent{
+		SessionID: sessionID,
+		Stream:    "activity",
+		Type:      eventType,
+		Data:      data,
+	})
+}
+
 // CleanupRun removes the sequence counter for a completed run.
 func (eb *EventBus) CleanupRun(runID string) {
 	eb.seqByRun.Delete(runID)
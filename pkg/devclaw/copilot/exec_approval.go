@@ -30,6 +30,35 @@ type ApprovalResult struct {
 	Reason   string
 }
 
+// ApproverConfig names who may approve gated tools in a workspace and how
+// many approvals each tool needs before it runs.
+type ApproverConfig struct {
+	// Users lists approver JIDs. Any listed user may approve or deny
+	// requests from this workspace, not just the caller who triggered them.
+	Users []string `yaml:"users"`
+
+	// Channel is the channel used to DM approvers (e.g. "whatsapp").
+	// Empty = same channel as the requesting session.
+	Channel string `yaml:"channel"`
+
+	// Quorum maps tool names to the number of approvals required
+	// (e.g. deploy: 2 for "2 of 3"). Tools not listed require 1.
+	Quorum map[string]int `yaml:"quorum,omitempty"`
+}
+
+// QuorumFor returns the number of approvals required for a tool,
+// clamped to [1, len(Users)].
+func (c *ApproverConfig) QuorumFor(toolName string) int {
+	q := c.Quorum[toolName]
+	if q < 1 {
+		q = 1
+	}
+	if q > len(c.Users) {
+		q = len(c.Users)
+	}
+	return q
+}
+
 // PendingApproval represents a tool call waiting for user approval.
 type PendingApproval struct {
 	ID          string
@@ -40,6 +69,13 @@ type PendingApproval struct {
 	CallerJID   string
 	CreatedAt   time.Time
 	Result      chan ApprovalResult
+
+	// Quorum approvals: Approvers lists JIDs allowed to resolve this
+	// request (empty = caller only); Quorum is how many approvals are
+	// needed. approvedBy records votes, guarded by the manager's mutex.
+	Approvers  []string
+	Quorum     int
+	approvedBy map[string]bool
 }
 
 // ApprovalManager manages pending tool approvals and their resolution.
@@ -100,6 +136,44 @@ func (m *ApprovalManager) Create(sessionID, callerJID, toolName string, args map
 	return id, message
 }
 
+// CreateQuorum creates a pending approval that requires quorum approvals from
+// the listed approvers. Returns the ID and the message to DM each approver.
+func (m *ApprovalManager) CreateQuorum(sessionID, callerJID, toolName string, args map[string]any, approvers []string, quorum int) (id string, message string) {
+	desc := formatApprovalDescription(toolName, args)
+	id = uuid.New().String()
+
+	pa := &PendingApproval{
+		ID:          id,
+		ToolName:    toolName,
+		Args:        args,
+		Description: desc,
+		SessionID:   sessionID,
+		CallerJID:   callerJID,
+		CreatedAt:   time.Now(),
+		Result:      make(chan ApprovalResult, 1),
+		Approvers:   approvers,
+		Quorum:      quorum,
+		approvedBy:  make(map[string]bool),
+	}
+
+	m.mu.Lock()
+	m.pending[id] = pa
+	m.mu.Unlock()
+
+	message = fmt.Sprintf("⚠️ Approval requested by %s: %s\nRequires %d of %d approvers.\n\nReply /approve %s or /deny %s",
+		callerJID, desc, quorum, len(approvers), id, id)
+
+	m.logger.Info("quorum approval created",
+		"id", id,
+		"tool", toolName,
+		"session", sessionID,
+		"quorum", quorum,
+		"approvers", len(approvers),
+	)
+
+	return id, message
+}
+
 // Wait blocks until the approval is resolved or times out.
 // Must be called after Create. Removes the pending approval when done.
 func (m *ApprovalManager) Wait(id string) (approved bool, err error) {
@@ -162,6 +236,41 @@ func (m *ApprovalManager) Request(sessionID, callerJID, toolName string, args ma
 	return approved, err
 }
 
+// RequestQuorum creates a quorum approval, fans the request out to each
+// approver's DM via sendDM, notifies the requesting chat via notify, then
+// blocks until the quorum approves, any approver denies, or timeout.
+func (m *ApprovalManager) RequestQuorum(sessionID, callerJID, toolName string, args map[string]any, cfg *ApproverConfig, sendDM func(jid, msg string), notify func(msg string)) (bool, error) {
+	// Session trust applies to quorum approvals too — once the quorum has
+	// approved a tool in this session, repeat calls skip the prompt.
+	if m.IsTrusted(sessionID, toolName) {
+		m.logger.Debug("tool auto-approved (session trust)",
+			"tool", toolName,
+			"session", sessionID,
+		)
+		return true, nil
+	}
+
+	quorum := cfg.QuorumFor(toolName)
+	id, message := m.CreateQuorum(sessionID, callerJID, toolName, args, cfg.Users, quorum)
+
+	if notify != nil {
+		notify(fmt.Sprintf("⏳ Waiting for approval: %s (%d of %d approvers required)",
+			formatApprovalDescription(toolName, args), quorum, len(cfg.Users)))
+	}
+	if sendDM != nil {
+		for _, jid := range cfg.Users {
+			sendDM(jid, message)
+		}
+	}
+
+	approved, err := m.Wait(id)
+	if approved && err == nil {
+		m.GrantTrust(sessionID, toolName)
+	}
+
+	return approved, err
+}
+
 // Resolve resolves a pending approval by ID. Returns true if the approval was found and resolved.
 // resolverJID is the user resolving (must match CallerJID for "own requests only").
 func (m *ApprovalManager) Resolve(id, sessionID, resolverJID string, approved bool, reason string) bool {
@@ -173,6 +282,12 @@ func (m *ApprovalManager) Resolve(id, sessionID, resolverJID string, approved bo
 		return false
 	}
 
+	// Quorum approvals: any listed approver may resolve, from any session
+	// (approvers respond from their own DMs, not the requesting chat).
+	if len(pa.Approvers) > 0 {
+		return m.resolveQuorum(pa, resolverJID, approved, reason)
+	}
+
 	// Per-session: only the session that created the approval can resolve it.
 	if pa.SessionID != sessionID {
 		m.logger.Warn("approval resolve rejected: session mismatch",
@@ -196,6 +311,85 @@ func (m *ApprovalManager) Resolve(id, sessionID, resolverJID string, approved bo
 	}
 }
 
+// resolveQuorum records an approver's vote. A single deny vetoes the request;
+// approvals accumulate until the quorum is reached.
+func (m *ApprovalManager) resolveQuorum(pa *PendingApproval, resolverJID string, approved bool, reason string) bool {
+	resolver := normalizeJID(resolverJID)
+	allowed := false
+	for _, jid := range pa.Approvers {
+		if normalizeJID(jid) == resolver {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		m.logger.Warn("approval resolve rejected: not a designated approver",
+			"id", pa.ID, "resolver", resolverJID)
+		return false
+	}
+
+	if !approved {
+		select {
+		case pa.Result <- ApprovalResult{Approved: false, Reason: reason}:
+			m.logger.Info("quorum approval vetoed", "id", pa.ID, "approver", resolver)
+			return true
+		default:
+			return false
+		}
+	}
+
+	m.mu.Lock()
+	pa.approvedBy[resolver] = true
+	votes := len(pa.approvedBy)
+	m.mu.Unlock()
+
+	m.logger.Info("approval vote recorded",
+		"id", pa.ID,
+		"approver", resolver,
+		"votes", votes,
+		"quorum", pa.Quorum,
+	)
+
+	if votes < pa.Quorum {
+		return true
+	}
+
+	select {
+	case pa.Result <- ApprovalResult{Approved: true, Reason: reason}:
+		return true
+	default:
+		return false
+	}
+}
+
+// LatestPendingForApprover returns the ID of the most recent pending quorum
+// approval that lists the given JID as an approver and has not yet received
+// their vote. This lets approvers reply /approve from their own DMs.
+func (m *ApprovalManager) LatestPendingForApprover(jid string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	resolver := normalizeJID(jid)
+	var latest *PendingApproval
+	for _, pa := range m.pending {
+		if pa.approvedBy[resolver] {
+			continue
+		}
+		for _, approver := range pa.Approvers {
+			if normalizeJID(approver) == resolver {
+				if latest == nil || pa.CreatedAt.After(latest.CreatedAt) {
+					latest = pa
+				}
+				break
+			}
+		}
+	}
+	if latest != nil {
+		return latest.ID
+	}
+	return ""
+}
+
 // LatestPendingForSession returns the ID of the most recent pending approval
 // for the given session, or empty string if none. This allows "/approve" without
 // specifying the UUID — it resolves the latest pending request.
@@ -306,6 +500,16 @@ func formatApprovalDescription(toolName string, args map[string]any) string {
 		}
 		return "scp"
 
+	case "db_execute", "db_hub_execute", "db_hub_raw":
+		stmt, _ := args["statement"].(string)
+		if stmt == "" {
+			stmt, _ = args["query"].(string)
+		}
+		if stmt != "" {
+			return "database write: " + sanitizeForMarkdown(truncateForApproval(stmt, 80))
+		}
+		return toolName + " (database write)"
+
 	default:
 		return toolName
 	}
@@ -0,0 +1,150 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func testApprovalManager(t *testing.T) *ApprovalManager {
+	t.Helper()
+	return NewApprovalManager(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+}
+
+func TestApproverConfigQuorumFor(t *testing.T) {
+	cfg := &ApproverConfig{
+		Users:  []string{"alice@s.whatsapp.net", "bob@s.whatsapp.net", "carol@s.whatsapp.net"},
+		Quorum: map[string]int{"deploy": 2, "bash": 5, "broken": -1},
+	}
+
+	if q := cfg.QuorumFor("deploy"); q != 2 {
+		t.Errorf("deploy quorum = %d, want 2", q)
+	}
+	// Unlisted tools require a single approval.
+	if q := cfg.QuorumFor("write_file"); q != 1 {
+		t.Errorf("write_file quorum = %d, want 1", q)
+	}
+	// Quorum is clamped to the number of approvers.
+	if q := cfg.QuorumFor("bash"); q != 3 {
+		t.Errorf("bash quorum = %d, want 3", q)
+	}
+	if q := cfg.QuorumFor("broken"); q != 1 {
+		t.Errorf("broken quorum = %d, want 1", q)
+	}
+}
+
+func TestQuorumApproval(t *testing.T) {
+	m := testApprovalManager(t)
+	approvers := []string{"alice@s.whatsapp.net", "bob@s.whatsapp.net", "carol@s.whatsapp.net"}
+
+	id, _ := m.CreateQuorum("whatsapp:123", "dev@s.whatsapp.net", "deploy", nil, approvers, 2)
+
+	// First vote records but does not release the result.
+	if !m.Resolve(id, "whatsapp:alice", "alice@s.whatsapp.net", true, "") {
+		t.Fatal("first approver vote rejected")
+	}
+
+	// Second vote reaches the quorum.
+	if !m.Resolve(id, "whatsapp:bob", "bob@s.whatsapp.net", true, "") {
+		t.Fatal("second approver vote rejected")
+	}
+
+	approved, err := m.Wait(id)
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if !approved {
+		t.Error("expected approval after quorum reached")
+	}
+}
+
+func TestQuorumDenyVetoes(t *testing.T) {
+	m := testApprovalManager(t)
+	approvers := []string{"alice@s.whatsapp.net", "bob@s.whatsapp.net"}
+
+	id, _ := m.CreateQuorum("whatsapp:123", "dev@s.whatsapp.net", "deploy", nil, approvers, 2)
+
+	if !m.Resolve(id, "whatsapp:alice", "alice@s.whatsapp.net", true, "") {
+		t.Fatal("approve vote rejected")
+	}
+	// A single deny vetoes regardless of votes already cast.
+	if !m.Resolve(id, "whatsapp:bob", "bob@s.whatsapp.net", false, "too risky") {
+		t.Fatal("deny vote rejected")
+	}
+
+	approved, err := m.Wait(id)
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if approved {
+		t.Error("expected denial after veto")
+	}
+}
+
+func TestQuorumRejectsNonApprover(t *testing.T) {
+	m := testApprovalManager(t)
+	approvers := []string{"alice@s.whatsapp.net"}
+
+	id, _ := m.CreateQuorum("whatsapp:123", "dev@s.whatsapp.net", "deploy", nil, approvers, 1)
+
+	// The caller is not a designated approver and cannot self-approve.
+	if m.Resolve(id, "whatsapp:123", "dev@s.whatsapp.net", true, "") {
+		t.Error("non-approver vote accepted")
+	}
+	if m.Resolve(id, "whatsapp:mallory", "mallory@s.whatsapp.net", true, "") {
+		t.Error("stranger vote accepted")
+	}
+
+	if !m.Resolve(id, "whatsapp:alice", "alice@s.whatsapp.net", true, "") {
+		t.Fatal("designated approver vote rejected")
+	}
+	if approved, _ := m.Wait(id); !approved {
+		t.Error("expected approval")
+	}
+}
+
+func TestLatestPendingForApprover(t *testing.T) {
+	m := testApprovalManager(t)
+	approvers := []string{"alice@s.whatsapp.net", "bob@s.whatsapp.net"}
+
+	id, _ := m.CreateQuorum("whatsapp:123", "dev@s.whatsapp.net", "deploy", nil, approvers, 2)
+
+	if got := m.LatestPendingForApprover("alice@s.whatsapp.net"); got != id {
+		t.Errorf("alice pending = %q, want %q", got, id)
+	}
+	if got := m.LatestPendingForApprover("mallory@s.whatsapp.net"); got != "" {
+		t.Errorf("mallory pending = %q, want empty", got)
+	}
+
+	// Once alice has voted, the request no longer shows up for her but
+	// still does for bob.
+	if !m.Resolve(id, "whatsapp:alice", "alice@s.whatsapp.net", true, "") {
+		t.Fatal("vote rejected")
+	}
+	if got := m.LatestPendingForApprover("alice@s.whatsapp.net"); got != "" {
+		t.Errorf("alice pending after vote = %q, want empty", got)
+	}
+	if got := m.LatestPendingForApprover("bob@s.whatsapp.net"); got != id {
+		t.Errorf("bob pending = %q, want %q", got, id)
+	}
+}
+
+func TestResolveOwnRequestStillWorks(t *testing.T) {
+	m := testApprovalManager(t)
+
+	id, _ := m.Create("whatsapp:123", "dev@s.whatsapp.net", "bash", map[string]any{"command": "ls"})
+
+	// Without approvers configured, only the caller's session can resolve.
+	if m.Resolve(id, "whatsapp:456", "dev@s.whatsapp.net", true, "") {
+		t.Error("resolve from wrong session accepted")
+	}
+	if m.Resolve(id, "whatsapp:123", "other@s.whatsapp.net", true, "") {
+		t.Error("resolve from wrong caller accepted")
+	}
+	if !m.Resolve(id, "whatsapp:123", "dev@s.whatsapp.net", true, "") {
+		t.Fatal("own resolve rejected")
+	}
+	if approved, _ := m.Wait(id); !approved {
+		t.Error("expected approval")
+	}
+}
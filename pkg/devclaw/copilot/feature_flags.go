@@ -0,0 +1,214 @@
+// Package copilot – feature_flags.go implements feature flags with
+// per-workspace rollout. Experimental behaviors (dynamic tool selection,
+// plan mode, streaming TTS) ship dark behind a flag and are dialed up via
+// config defaults, percentage rollout hashed on the workspace ID, or
+// runtime overrides set with /flags (persisted in devclaw.db).
+package copilot
+
+import (
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FeatureFlag names an experimental behavior that can ship dark.
+type FeatureFlag string
+
+const (
+	// FlagDynamicTools gates per-turn dynamic tool selection.
+	FlagDynamicTools FeatureFlag = "dynamic_tools"
+	// FlagPlanMode gates the plan-before-act agent mode.
+	FlagPlanMode FeatureFlag = "plan_mode"
+	// FlagStreamingTTS gates sentence-level streaming TTS.
+	FlagStreamingTTS FeatureFlag = "streaming_tts"
+)
+
+// knownFlags maps each flag to its built-in default. Behaviors that already
+// have their own config gate (tool_selection.enabled, tts.enabled) default to
+// on so the flag only dials them off; genuinely new behaviors default to off.
+var knownFlags = map[FeatureFlag]bool{
+	FlagDynamicTools: true,
+	FlagPlanMode:     false,
+	FlagStreamingTTS: true,
+}
+
+// FlagRule configures one flag's rollout in config.yaml.
+type FlagRule struct {
+	// Default is the flag state where no finer-grained rule matches.
+	Default bool `yaml:"default"`
+
+	// RolloutPct enables the flag for a stable percentage of workspaces
+	// (0-100, hashed on flag name + workspace ID). Ignored when 0.
+	RolloutPct int `yaml:"rollout_pct"`
+
+	// Workspaces pins the flag state for specific workspace IDs.
+	Workspaces map[string]bool `yaml:"workspaces"`
+}
+
+// FeatureFlagsConfig configures feature flags and their rollout.
+type FeatureFlagsConfig struct {
+	// Flags maps flag names to rollout rules.
+	Flags map[string]FlagRule `yaml:"flags"`
+}
+
+// FeatureFlagManager resolves flag state per workspace. Resolution order:
+// runtime override for the workspace → runtime global override → config
+// workspace pin → config percentage rollout → config default → built-in
+// default. Runtime overrides persist in devclaw.db across restarts.
+type FeatureFlagManager struct {
+	mu        sync.RWMutex
+	config    FeatureFlagsConfig
+	overrides map[FeatureFlag]map[string]bool // flag → scope ("*" or workspace ID) → state
+	db        *sql.DB
+	logger    *slog.Logger
+}
+
+// NewFeatureFlagManager creates a flag manager and loads persisted overrides.
+// db may be nil; overrides then live in memory only.
+func NewFeatureFlagManager(cfg FeatureFlagsConfig, db *sql.DB, logger *slog.Logger) *FeatureFlagManager {
+	m := &FeatureFlagManager{
+		config:    cfg,
+		overrides: make(map[FeatureFlag]map[string]bool),
+		db:        db,
+		logger:    logger.With("component", "feature-flags"),
+	}
+	m.loadOverrides()
+	return m
+}
+
+// Enabled reports whether the flag is on for the given workspace.
+func (m *FeatureFlagManager) Enabled(flag FeatureFlag, workspaceID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if scopes, ok := m.overrides[flag]; ok {
+		if state, ok := scopes[workspaceID]; ok && workspaceID != "" {
+			return state
+		}
+		if state, ok := scopes["*"]; ok {
+			return state
+		}
+	}
+
+	if rule, ok := m.config.Flags[string(flag)]; ok {
+		if state, ok := rule.Workspaces[workspaceID]; ok && workspaceID != "" {
+			return state
+		}
+		if rule.RolloutPct > 0 && workspaceID != "" {
+			return rolloutBucket(flag, workspaceID) < rule.RolloutPct
+		}
+		return rule.Default
+	}
+
+	return knownFlags[flag]
+}
+
+// SetOverride sets a runtime override for the flag. scope is a workspace ID
+// or "*" for all workspaces. Persisted when the database is available.
+func (m *FeatureFlagManager) SetOverride(flag FeatureFlag, scope string, enabled bool) error {
+	if scope == "" {
+		scope = "*"
+	}
+	m.mu.Lock()
+	if m.overrides[flag] == nil {
+		m.overrides[flag] = make(map[string]bool)
+	}
+	m.overrides[flag][scope] = enabled
+	m.mu.Unlock()
+
+	if m.db == nil {
+		return nil
+	}
+	_, err := m.db.Exec(`INSERT OR REPLACE INTO feature_flag_overrides (flag, scope, enabled, updated_at)
+		VALUES (?, ?, ?, ?)`,
+		string(flag), scope, boolToInt(enabled), time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("persist flag override: %w", err)
+	}
+	return nil
+}
+
+// ClearOverride removes a runtime override, falling back to config rules.
+func (m *FeatureFlagManager) ClearOverride(flag FeatureFlag, scope string) error {
+	if scope == "" {
+		scope = "*"
+	}
+	m.mu.Lock()
+	delete(m.overrides[flag], scope)
+	m.mu.Unlock()
+
+	if m.db == nil {
+		return nil
+	}
+	if _, err := m.db.Exec(`DELETE FROM feature_flag_overrides WHERE flag = ? AND scope = ?`,
+		string(flag), scope); err != nil {
+		return fmt.Errorf("clear flag override: %w", err)
+	}
+	return nil
+}
+
+// KnownFlags returns all flag names, sorted for stable listing.
+func (m *FeatureFlagManager) KnownFlags() []FeatureFlag {
+	flags := make([]FeatureFlag, 0, len(knownFlags))
+	for flag := range knownFlags {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i] < flags[j] })
+	return flags
+}
+
+// Overrides returns the runtime overrides for a flag (scope → state).
+func (m *FeatureFlagManager) Overrides(flag FeatureFlag) map[string]bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]bool, len(m.overrides[flag]))
+	for scope, state := range m.overrides[flag] {
+		out[scope] = state
+	}
+	return out
+}
+
+// loadOverrides restores persisted overrides from the database.
+func (m *FeatureFlagManager) loadOverrides() {
+	if m.db == nil {
+		return
+	}
+	rows, err := m.db.Query(`SELECT flag, scope, enabled FROM feature_flag_overrides`)
+	if err != nil {
+		m.logger.Warn("failed to load feature flag overrides", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var flag, scope string
+		var enabled int
+		if err := rows.Scan(&flag, &scope, &enabled); err != nil {
+			m.logger.Warn("failed to scan feature flag override", "error", err)
+			continue
+		}
+		if m.overrides[FeatureFlag(flag)] == nil {
+			m.overrides[FeatureFlag(flag)] = make(map[string]bool)
+		}
+		m.overrides[FeatureFlag(flag)][scope] = enabled != 0
+		count++
+	}
+	if count > 0 {
+		m.logger.Info("feature flag overrides loaded", "count", count)
+	}
+}
+
+// rolloutBucket maps a flag/workspace pair to a stable bucket in [0, 100).
+// The flag name is part of the hash so different flags roll out to different
+// workspace subsets at the same percentage.
+func rolloutBucket(flag FeatureFlag, workspaceID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(string(flag) + ":" + strings.ToLower(workspaceID)))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,111 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testFlagManager(t *testing.T, cfg FeatureFlagsConfig) *FeatureFlagManager {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return NewFeatureFlagManager(cfg, nil, logger)
+}
+
+func TestFeatureFlagResolution(t *testing.T) {
+	cfg := FeatureFlagsConfig{Flags: map[string]FlagRule{
+		string(FlagPlanMode): {
+			Default:    false,
+			Workspaces: map[string]bool{"work": true},
+		},
+	}}
+	m := testFlagManager(t, cfg)
+
+	// Built-in defaults apply to flags without config rules.
+	if !m.Enabled(FlagStreamingTTS, "personal") {
+		t.Error("streaming_tts should default on")
+	}
+	// Config default.
+	if m.Enabled(FlagPlanMode, "personal") {
+		t.Error("plan_mode should be off by config default")
+	}
+	// Per-workspace pin wins over config default.
+	if !m.Enabled(FlagPlanMode, "work") {
+		t.Error("plan_mode should be pinned on for work")
+	}
+
+	// Runtime override wins over everything.
+	if err := m.SetOverride(FlagPlanMode, "personal", true); err != nil {
+		t.Fatalf("SetOverride: %v", err)
+	}
+	if !m.Enabled(FlagPlanMode, "personal") {
+		t.Error("override should enable plan_mode for personal")
+	}
+	// Global override applies to workspaces without their own.
+	if err := m.SetOverride(FlagStreamingTTS, "*", false); err != nil {
+		t.Fatalf("SetOverride: %v", err)
+	}
+	if m.Enabled(FlagStreamingTTS, "work") {
+		t.Error("global override should disable streaming_tts")
+	}
+
+	// Clearing falls back to config rules.
+	if err := m.ClearOverride(FlagPlanMode, "personal"); err != nil {
+		t.Fatalf("ClearOverride: %v", err)
+	}
+	if m.Enabled(FlagPlanMode, "personal") {
+		t.Error("plan_mode should be off again after reset")
+	}
+}
+
+func TestFeatureFlagRollout(t *testing.T) {
+	cfg := FeatureFlagsConfig{Flags: map[string]FlagRule{
+		string(FlagPlanMode): {RolloutPct: 50},
+	}}
+	m := testFlagManager(t, cfg)
+
+	// The bucket is stable: the same workspace always resolves the same way.
+	first := m.Enabled(FlagPlanMode, "workspace-1")
+	for i := 0; i < 10; i++ {
+		if m.Enabled(FlagPlanMode, "workspace-1") != first {
+			t.Fatal("rollout decision must be stable per workspace")
+		}
+	}
+
+	// At 100% everyone is in; at 0% the config default applies.
+	m100 := testFlagManager(t, FeatureFlagsConfig{Flags: map[string]FlagRule{
+		string(FlagPlanMode): {RolloutPct: 100},
+	}})
+	if !m100.Enabled(FlagPlanMode, "anyone") {
+		t.Error("100%% rollout should enable for all workspaces")
+	}
+
+	// Rollout is ignored without a workspace ID.
+	if m.Enabled(FlagPlanMode, "") {
+		t.Error("empty workspace should fall back to config default")
+	}
+}
+
+func TestFeatureFlagPersistence(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	m := NewFeatureFlagManager(FeatureFlagsConfig{}, db, logger)
+	if err := m.SetOverride(FlagPlanMode, "work", true); err != nil {
+		t.Fatalf("SetOverride: %v", err)
+	}
+
+	// A fresh manager on the same database sees the override.
+	m2 := NewFeatureFlagManager(FeatureFlagsConfig{}, db, logger)
+	if !m2.Enabled(FlagPlanMode, "work") {
+		t.Error("override should survive a restart")
+	}
+	if m2.Enabled(FlagPlanMode, "personal") {
+		t.Error("override is scoped to the work workspace")
+	}
+}
@@ -0,0 +1,217 @@
+// Package copilot – federation.go implements assistant-to-assistant
+// delegation between DevClaw instances. Remote instances are registered as
+// peers; the delegate_to_peer tool sends a task to a peer's gateway API
+// (mutual bearer tokens), and the result comes back through the followup
+// queue — a home instance can hand work tasks to an office instance.
+package copilot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// FederationPeer describes one remote DevClaw instance.
+type FederationPeer struct {
+	// Name identifies the peer (e.g. "office").
+	Name string `yaml:"name"`
+
+	// URL is the peer's gateway base URL (e.g. "https://office.example:8085").
+	URL string `yaml:"url"`
+
+	// Token is the bearer token the peer expects for delegated tasks.
+	Token string `yaml:"token"`
+}
+
+// FederationConfig configures assistant-to-assistant delegation.
+type FederationConfig struct {
+	// Enabled turns federation on/off (default: false).
+	Enabled bool `yaml:"enabled"`
+
+	// Token is the bearer token THIS instance requires from peers that
+	// delegate tasks to it (inbound side of the mutual tokens).
+	Token string `yaml:"token"`
+
+	// Peers lists remote instances this one can delegate to.
+	Peers []FederationPeer `yaml:"peers"`
+
+	// TimeoutSeconds bounds one delegated task round-trip (default: 300).
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+}
+
+// federationDelegateRequest is the wire format for POST /api/federation/delegate.
+type federationDelegateRequest struct {
+	Task string `json:"task"`
+	From string `json:"from"` // delegating instance name, for audit logs
+}
+
+// federationDelegateResponse is the peer's reply.
+type federationDelegateResponse struct {
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// FederationManager sends delegated tasks to registered peers and validates
+// inbound delegation tokens.
+type FederationManager struct {
+	cfg          FederationConfig
+	instanceName string
+	client       *http.Client
+	logger       *slog.Logger
+}
+
+// NewFederationManager creates a federation manager from config.
+func NewFederationManager(cfg FederationConfig, instanceName string, logger *slog.Logger) *FederationManager {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	return &FederationManager{
+		cfg:          cfg,
+		instanceName: instanceName,
+		client:       &http.Client{Timeout: timeout},
+		logger:       logger.With("component", "federation"),
+	}
+}
+
+// Peer returns the named peer config.
+func (fm *FederationManager) Peer(name string) (FederationPeer, bool) {
+	for _, peer := range fm.cfg.Peers {
+		if strings.EqualFold(peer.Name, name) {
+			return peer, true
+		}
+	}
+	return FederationPeer{}, false
+}
+
+// PeerNames returns the registered peer names for tool descriptions and errors.
+func (fm *FederationManager) PeerNames() []string {
+	names := make([]string, len(fm.cfg.Peers))
+	for i, peer := range fm.cfg.Peers {
+		names[i] = peer.Name
+	}
+	return names
+}
+
+// AcceptToken reports whether an inbound delegation token is valid.
+// Comparison is timing-safe (hash both sides before comparing).
+func (fm *FederationManager) AcceptToken(token string) bool {
+	if fm.cfg.Token == "" {
+		return false // No inbound token configured → inbound delegation off.
+	}
+	ha := sha256.Sum256([]byte(token))
+	hb := sha256.Sum256([]byte(fm.cfg.Token))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// Delegate sends a task to the named peer and returns its result. Blocks for
+// up to the configured timeout; callers that shouldn't block run it in a
+// goroutine and deliver the result via the followup queue.
+func (fm *FederationManager) Delegate(ctx context.Context, peerName, task string) (string, error) {
+	peer, ok := fm.Peer(peerName)
+	if !ok {
+		return "", fmt.Errorf("unknown peer %q (registered: %s)", peerName, strings.Join(fm.PeerNames(), ", "))
+	}
+
+	payload, err := json.Marshal(federationDelegateRequest{Task: task, From: fm.instanceName})
+	if err != nil {
+		return "", fmt.Errorf("marshal delegation request: %w", err)
+	}
+
+	url := strings.TrimRight(peer.URL, "/") + "/api/federation/delegate"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("build delegation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+peer.Token)
+
+	fm.logger.Info("delegating task to peer", "peer", peer.Name, "task_len", len(task))
+	resp, err := fm.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("delegate to peer %s: %w", peer.Name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	if err != nil {
+		return "", fmt.Errorf("read peer response: %w", err)
+	}
+
+	var out federationDelegateResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("peer %s returned status %d with unparseable body", peer.Name, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK || out.Error != "" {
+		if out.Error == "" {
+			out.Error = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		return "", fmt.Errorf("peer %s rejected task: %s", peer.Name, out.Error)
+	}
+	return out.Result, nil
+}
+
+// RegisterFederationTool registers delegate_to_peer. The tool returns
+// immediately; the peer's result is injected into the delegating session via
+// the followup queue, like subagent completions.
+func RegisterFederationTool(executor *ToolExecutor, a *Assistant) {
+	peers := strings.Join(a.federationMgr.PeerNames(), ", ")
+	executor.Register(
+		MakeToolDefinition("delegate_to_peer", fmt.Sprintf("Delegate a task to a remote DevClaw instance (federation peer). The peer runs the task with its own tools and context; the result arrives as a followup message. Registered peers: %s.", peers), map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"peer": map[string]any{
+					"type":        "string",
+					"description": "Name of the registered peer to delegate to.",
+				},
+				"task": map[string]any{
+					"type":        "string",
+					"description": "The task for the peer, with all context it needs (peers don't share memory).",
+				},
+			},
+			"required": []string{"peer", "task"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			peerName, _ := args["peer"].(string)
+			task, _ := args["task"].(string)
+			if peerName == "" || task == "" {
+				return nil, fmt.Errorf("peer and task are required")
+			}
+			if _, ok := a.federationMgr.Peer(peerName); !ok {
+				return nil, fmt.Errorf("unknown peer %q (registered: %s)", peerName, peers)
+			}
+
+			sessionID := SessionIDFromContext(ctx)
+			channel, chatID, _ := strings.Cut(sessionID, ":")
+
+			// Delegation runs detached from the tool call: the peer may take
+			// minutes, and the result comes back through the followup queue.
+			go func() {
+				dctx, cancel := context.WithTimeout(context.Background(), a.federationMgr.client.Timeout)
+				defer cancel()
+
+				result, err := a.federationMgr.Delegate(dctx, peerName, task)
+				var msg string
+				if err != nil {
+					msg = fmt.Sprintf("[System Message] Delegated task to peer %q failed: %v\n\nLet the user know about this failure briefly and offer to retry.", peerName, err)
+				} else {
+					if len(result) > 4000 {
+						result = result[:4000] + "\n... (truncated)"
+					}
+					msg = fmt.Sprintf("[System Message] Peer %q completed the delegated task.\n\nResult:\n%s\n\nConvert this result into your normal assistant voice and send that user-facing update now.", peerName, result)
+				}
+				a.enqueueFollowupMessage(sessionID, msg, channel, chatID)
+			}()
+
+			return fmt.Sprintf("Task delegated to peer %q. The result will arrive as a followup message.", peerName), nil
+		},
+	)
+}
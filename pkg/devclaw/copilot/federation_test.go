@@ -0,0 +1,101 @@
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func testFederationManager(cfg FederationConfig) *FederationManager {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return NewFederationManager(cfg, "home", logger)
+}
+
+func TestFederationPeerLookup(t *testing.T) {
+	fm := testFederationManager(FederationConfig{
+		Peers: []FederationPeer{
+			{Name: "office", URL: "http://office.local:8085", Token: "t1"},
+		},
+	})
+
+	if _, ok := fm.Peer("office"); !ok {
+		t.Error("expected to find peer office")
+	}
+	if _, ok := fm.Peer("OFFICE"); !ok {
+		t.Error("peer lookup should be case-insensitive")
+	}
+	if _, ok := fm.Peer("home"); ok {
+		t.Error("unknown peer should not resolve")
+	}
+}
+
+func TestFederationAcceptToken(t *testing.T) {
+	fm := testFederationManager(FederationConfig{Token: "secret"})
+	if !fm.AcceptToken("secret") {
+		t.Error("valid token rejected")
+	}
+	if fm.AcceptToken("wrong") {
+		t.Error("invalid token accepted")
+	}
+
+	// No inbound token configured → inbound delegation is off entirely.
+	open := testFederationManager(FederationConfig{})
+	if open.AcceptToken("") {
+		t.Error("empty configured token must not accept delegations")
+	}
+}
+
+func TestFederationDelegate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/federation/delegate" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer peer-token" {
+			t.Errorf("unexpected auth header %q", got)
+		}
+		var req federationDelegateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.From != "home" {
+			t.Errorf("expected from=home, got %q", req.From)
+		}
+		json.NewEncoder(w).Encode(federationDelegateResponse{Result: "done: " + req.Task})
+	}))
+	defer srv.Close()
+
+	fm := testFederationManager(FederationConfig{
+		Peers: []FederationPeer{{Name: "office", URL: srv.URL, Token: "peer-token"}},
+	})
+
+	result, err := fm.Delegate(context.Background(), "office", "check the build")
+	if err != nil {
+		t.Fatalf("Delegate: %v", err)
+	}
+	if result != "done: check the build" {
+		t.Errorf("unexpected result %q", result)
+	}
+
+	if _, err := fm.Delegate(context.Background(), "nowhere", "x"); err == nil {
+		t.Error("expected error for unknown peer")
+	}
+}
+
+func TestFederationDelegateError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(federationDelegateResponse{Error: "invalid federation token"})
+	}))
+	defer srv.Close()
+
+	fm := testFederationManager(FederationConfig{
+		Peers: []FederationPeer{{Name: "office", URL: srv.URL, Token: "stale"}},
+	})
+	if _, err := fm.Delegate(context.Background(), "office", "x"); err == nil {
+		t.Error("expected error when peer rejects the task")
+	}
+}
@@ -0,0 +1,539 @@
+// Package copilot – finance_tools.go implements the built-in finance skill:
+// expense tracking, monthly budgets per category, CSV import of bank
+// statements, and an optional scheduled monthly summary.
+//
+// Storage lives in the skill database alongside the other internal tables
+// (_expenses, _budgets). Schema changes go through ordered migrations tracked
+// in _finance_schema, so later releases can evolve the tables without losing
+// existing data.
+package copilot
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/scheduler"
+)
+
+// financeSummaryJobID is the scheduler job installed by schedule_monthly.
+const financeSummaryJobID = "finance-monthly-summary"
+
+// maxCSVImportRows caps how many statement rows a single import processes.
+const maxCSVImportRows = 5000
+
+// financeMigrations is the ordered list of schema migrations for the finance
+// tables. Each entry runs at most once; the highest applied version is
+// tracked in _finance_schema.
+var financeMigrations = []string{
+	// v1: expenses and budgets.
+	`CREATE TABLE IF NOT EXISTS _expenses (
+		id          TEXT PRIMARY KEY,
+		amount      REAL NOT NULL,
+		category    TEXT NOT NULL,
+		description TEXT,
+		spent_at    TEXT NOT NULL,
+		created_at  TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_expenses_spent_at ON _expenses(spent_at);
+	CREATE INDEX IF NOT EXISTS idx_expenses_category ON _expenses(category);
+	CREATE TABLE IF NOT EXISTS _budgets (
+		category      TEXT PRIMARY KEY,
+		monthly_limit REAL NOT NULL,
+		updated_at    TEXT NOT NULL
+	);`,
+	// v2: track where an expense came from (manual entry vs. CSV import).
+	`ALTER TABLE _expenses ADD COLUMN source TEXT NOT NULL DEFAULT 'manual';`,
+}
+
+// InitFinanceTables applies any pending finance schema migrations.
+// Safe to call on every startup.
+func (s *SkillDB) InitFinanceTables() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS _finance_schema (version INTEGER NOT NULL)`); err != nil {
+		return fmt.Errorf("create finance schema table: %w", err)
+	}
+
+	var version int
+	if err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM _finance_schema`).Scan(&version); err != nil {
+		return fmt.Errorf("read finance schema version: %w", err)
+	}
+
+	for i := version; i < len(financeMigrations); i++ {
+		if _, err := s.db.Exec(financeMigrations[i]); err != nil {
+			return fmt.Errorf("finance migration %d: %w", i+1, err)
+		}
+		if _, err := s.db.Exec(`INSERT INTO _finance_schema (version) VALUES (?)`, i+1); err != nil {
+			return fmt.Errorf("record finance migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// ExpenseInfo contains a single recorded expense.
+type ExpenseInfo struct {
+	ID          string  `json:"id"`
+	Amount      float64 `json:"amount"`
+	Category    string  `json:"category"`
+	Description string  `json:"description,omitempty"`
+	SpentAt     string  `json:"spent_at"`
+	Source      string  `json:"source"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// CategorySpend aggregates expenses for one category within a month,
+// together with the configured budget (0 when no budget is set).
+type CategorySpend struct {
+	Category string  `json:"category"`
+	Total    float64 `json:"total"`
+	Count    int     `json:"count"`
+	Budget   float64 `json:"budget,omitempty"`
+}
+
+// AddExpense records an expense and returns its ID. spentAt must be a
+// YYYY-MM-DD date; when empty, today is used.
+func (s *SkillDB) AddExpense(amount float64, category, description, spentAt, source string) (string, error) {
+	if amount <= 0 {
+		return "", fmt.Errorf("amount must be positive, got %.2f", amount)
+	}
+	category = normalizeExpenseCategory(category)
+	if category == "" {
+		return "", fmt.Errorf("category is required")
+	}
+	if spentAt == "" {
+		spentAt = time.Now().Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", spentAt); err != nil {
+		return "", fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", spentAt)
+	}
+	if source == "" {
+		source = "manual"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := generateID()
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(`
+		INSERT INTO _expenses (id, amount, category, description, spent_at, source, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id, amount, category, description, spentAt, source, now,
+	)
+	if err != nil {
+		return "", fmt.Errorf("insert expense: %w", err)
+	}
+
+	return id, nil
+}
+
+// MonthlySpendReport returns per-category totals for the given month
+// ("2006-01" format) joined with any configured budgets, plus the grand
+// total. Categories are ordered by total spend, highest first.
+func (s *SkillDB) MonthlySpendReport(month string) ([]CategorySpend, float64, error) {
+	if _, err := time.Parse("2006-01", month); err != nil {
+		return nil, 0, fmt.Errorf("invalid month %q (expected YYYY-MM)", month)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT e.category, SUM(e.amount), COUNT(*), COALESCE(b.monthly_limit, 0)
+		FROM _expenses e
+		LEFT JOIN _budgets b ON b.category = e.category
+		WHERE e.spent_at LIKE ?
+		GROUP BY e.category
+		ORDER BY SUM(e.amount) DESC`,
+		month+"-%",
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("monthly report: %w", err)
+	}
+	defer rows.Close()
+
+	var report []CategorySpend
+	var grandTotal float64
+	for rows.Next() {
+		var c CategorySpend
+		if err := rows.Scan(&c.Category, &c.Total, &c.Count, &c.Budget); err != nil {
+			return nil, 0, fmt.Errorf("scan report row: %w", err)
+		}
+		grandTotal += c.Total
+		report = append(report, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return report, grandTotal, nil
+}
+
+// SetBudget sets (or replaces) the monthly limit for a category.
+// A limit of 0 removes the budget.
+func (s *SkillDB) SetBudget(category string, monthlyLimit float64) error {
+	category = normalizeExpenseCategory(category)
+	if category == "" {
+		return fmt.Errorf("category is required")
+	}
+	if monthlyLimit < 0 {
+		return fmt.Errorf("monthly limit must not be negative, got %.2f", monthlyLimit)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if monthlyLimit == 0 {
+		if _, err := s.db.Exec(`DELETE FROM _budgets WHERE category = ?`, category); err != nil {
+			return fmt.Errorf("remove budget: %w", err)
+		}
+		return nil
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(`
+		INSERT INTO _budgets (category, monthly_limit, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(category) DO UPDATE SET monthly_limit = excluded.monthly_limit, updated_at = excluded.updated_at`,
+		category, monthlyLimit, now,
+	)
+	if err != nil {
+		return fmt.Errorf("set budget: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeExpenseCategory lowercases and trims a category name so
+// "Groceries" and "groceries " land in the same bucket.
+func normalizeExpenseCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// ImportExpensesCSV reads a bank statement CSV and records one expense per
+// row. The first row must be a header; date, amount and description columns
+// are detected by common names, and rows with a positive amount (credits)
+// or an unparsable date/amount are skipped. defaultCategory applies to rows
+// without a category column. Returns (imported, skipped).
+func (s *SkillDB) ImportExpensesCSV(r io.Reader, defaultCategory string) (int, int, error) {
+	defaultCategory = normalizeExpenseCategory(defaultCategory)
+	if defaultCategory == "" {
+		defaultCategory = "uncategorized"
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return 0, 0, fmt.Errorf("read CSV header: %w", err)
+	}
+
+	dateCol, amountCol, descCol, catCol := detectStatementColumns(header)
+	if dateCol < 0 || amountCol < 0 {
+		return 0, 0, fmt.Errorf("could not detect date and amount columns in header: %v", header)
+	}
+
+	imported, skipped := 0, 0
+	for i := 0; i < maxCSVImportRows; i++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imported, skipped, fmt.Errorf("read CSV row: %w", err)
+		}
+		if dateCol >= len(row) || amountCol >= len(row) {
+			skipped++
+			continue
+		}
+
+		spentAt, err := parseStatementDate(row[dateCol])
+		if err != nil {
+			skipped++
+			continue
+		}
+		amount, err := parseStatementAmount(row[amountCol])
+		if err != nil || amount >= 0 {
+			// Credits (deposits, refunds) are not expenses.
+			skipped++
+			continue
+		}
+
+		description := ""
+		if descCol >= 0 && descCol < len(row) {
+			description = strings.TrimSpace(row[descCol])
+		}
+		category := defaultCategory
+		if catCol >= 0 && catCol < len(row) {
+			if c := normalizeExpenseCategory(row[catCol]); c != "" {
+				category = c
+			}
+		}
+
+		if _, err := s.AddExpense(-amount, category, description, spentAt, "csv"); err != nil {
+			skipped++
+			continue
+		}
+		imported++
+	}
+
+	return imported, skipped, nil
+}
+
+// detectStatementColumns maps common bank statement header names to column
+// indexes. Returns -1 for columns that were not found.
+func detectStatementColumns(header []string) (dateCol, amountCol, descCol, catCol int) {
+	dateCol, amountCol, descCol, catCol = -1, -1, -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "date", "data", "transaction date", "posted date":
+			if dateCol < 0 {
+				dateCol = i
+			}
+		case "amount", "valor", "value", "debit":
+			if amountCol < 0 {
+				amountCol = i
+			}
+		case "description", "descricao", "descrição", "memo", "payee", "details":
+			if descCol < 0 {
+				descCol = i
+			}
+		case "category", "categoria":
+			if catCol < 0 {
+				catCol = i
+			}
+		}
+	}
+	return dateCol, amountCol, descCol, catCol
+}
+
+// statementDateLayouts are the date formats accepted in statement CSVs,
+// tried in order.
+var statementDateLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"01/02/2006",
+	"2006/01/02",
+	"02-01-2006",
+}
+
+// parseStatementDate normalizes a statement date to YYYY-MM-DD.
+func parseStatementDate(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range statementDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized date %q", s)
+}
+
+// parseStatementAmount parses a statement amount, tolerating currency
+// symbols and both "1,234.56" and "1.234,56" separators. Debits stay
+// negative.
+func parseStatementAmount(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimLeft(s, "R$€£ ")
+	if s == "" {
+		return 0, fmt.Errorf("empty amount")
+	}
+
+	lastComma := strings.LastIndex(s, ",")
+	lastDot := strings.LastIndex(s, ".")
+	if lastComma > lastDot {
+		// European format: comma is the decimal separator.
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
+
+// RegisterFinanceTools registers the finance skill tools in the executor.
+func RegisterFinanceTools(executor *ToolExecutor, skillDB *SkillDB, sched *scheduler.Scheduler) {
+	if skillDB == nil {
+		return
+	}
+
+	// expense_add
+	executor.Register(
+		MakeToolDefinition("expense_add", "Record an expense with an amount, category, and optional description/date. Categories are free-form (lowercase): groceries, transport, rent, etc.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"amount": map[string]any{
+					"type":        "number",
+					"description": "Amount spent (positive number)",
+				},
+				"category": map[string]any{
+					"type":        "string",
+					"description": "Expense category (e.g. 'groceries', 'transport')",
+				},
+				"description": map[string]any{
+					"type":        "string",
+					"description": "What the expense was for",
+				},
+				"date": map[string]any{
+					"type":        "string",
+					"description": "Date of the expense as YYYY-MM-DD (default: today)",
+				},
+			},
+			"required": []string{"amount", "category"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			amount, _ := args["amount"].(float64)
+			category, _ := args["category"].(string)
+			description, _ := args["description"].(string)
+			date, _ := args["date"].(string)
+
+			id, err := skillDB.AddExpense(amount, category, description, date, "manual")
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Expense %s recorded: %.2f in '%s'.", id, amount, normalizeExpenseCategory(category)), nil
+		},
+	)
+
+	// expense_report
+	executor.Register(
+		MakeToolDefinition("expense_report", "Monthly expense report grouped by category, with budget usage where budgets are set. Use schedule_monthly=true to install a recurring summary on the 1st of each month.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"month": map[string]any{
+					"type":        "string",
+					"description": "Month to report as YYYY-MM (default: current month)",
+				},
+				"schedule_monthly": map[string]any{
+					"type":        "boolean",
+					"description": "Install a scheduled job that posts last month's summary to this chat on the 1st of each month",
+				},
+			},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			month, _ := args["month"].(string)
+			scheduleMonthly, _ := args["schedule_monthly"].(bool)
+
+			if scheduleMonthly {
+				if sched == nil {
+					return nil, fmt.Errorf("scheduler is not enabled")
+				}
+				dt := DeliveryTargetFromContext(ctx)
+				job := &scheduler.Job{
+					ID:             financeSummaryJobID,
+					Schedule:       "0 9 1 * *",
+					Type:           "cron",
+					Command:        "Run the expense_report tool for last month and post a short summary: total spend, top categories, and any budgets that were exceeded.",
+					Channel:        dt.Channel,
+					ChatID:         dt.ChatID,
+					Enabled:        true,
+					IsolateSession: true,
+					Announce:       true,
+				}
+				if err := sched.Add(job); err != nil {
+					return nil, fmt.Errorf("scheduling monthly summary: %w", err)
+				}
+				return fmt.Sprintf("Monthly expense summary scheduled as job %q (1st of each month, 09:00). Remove it with cron_remove.", financeSummaryJobID), nil
+			}
+
+			if month == "" {
+				month = time.Now().Format("2006-01")
+			}
+
+			report, total, err := skillDB.MonthlySpendReport(month)
+			if err != nil {
+				return nil, err
+			}
+			if len(report) == 0 {
+				return fmt.Sprintf("No expenses recorded for %s.", month), nil
+			}
+
+			table := NewTableResult(fmt.Sprintf("Expenses %s (total %.2f)", month, total), []string{"Category", "Spent", "Count", "Budget", "Used"})
+			for _, c := range report {
+				budget, used := "—", "—"
+				if c.Budget > 0 {
+					budget = fmt.Sprintf("%.2f", c.Budget)
+					used = fmt.Sprintf("%.0f%%", c.Total/c.Budget*100)
+				}
+				table.AddRow(c.Category, fmt.Sprintf("%.2f", c.Total), fmt.Sprintf("%d", c.Count), budget, used)
+			}
+			return table, nil
+		},
+	)
+
+	// budget_set
+	executor.Register(
+		MakeToolDefinition("budget_set", "Set a monthly budget limit for an expense category. A limit of 0 removes the budget.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"category": map[string]any{
+					"type":        "string",
+					"description": "Expense category the budget applies to",
+				},
+				"monthly_limit": map[string]any{
+					"type":        "number",
+					"description": "Spending limit per month (0 removes the budget)",
+				},
+			},
+			"required": []string{"category", "monthly_limit"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			category, _ := args["category"].(string)
+			limit, _ := args["monthly_limit"].(float64)
+
+			if err := skillDB.SetBudget(category, limit); err != nil {
+				return nil, err
+			}
+			if limit == 0 {
+				return fmt.Sprintf("Budget for '%s' removed.", normalizeExpenseCategory(category)), nil
+			}
+			return fmt.Sprintf("Budget for '%s' set to %.2f per month.", normalizeExpenseCategory(category), limit), nil
+		},
+	)
+
+	// expense_import_csv
+	executor.Register(
+		MakeToolDefinition("expense_import_csv", "Import expenses from a bank statement CSV file. The header row is used to detect date, amount, and description columns; credit rows (positive amounts) are skipped.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{
+					"type":        "string",
+					"description": "Path to the CSV file to import",
+				},
+				"default_category": map[string]any{
+					"type":        "string",
+					"description": "Category for rows without a category column (default: 'uncategorized')",
+				},
+			},
+			"required": []string{"path"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			path, _ := args["path"].(string)
+			defaultCategory, _ := args["default_category"].(string)
+			if path == "" {
+				return nil, fmt.Errorf("path is required")
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("open statement: %w", err)
+			}
+			defer f.Close()
+
+			imported, skipped, err := skillDB.ImportExpensesCSV(f, defaultCategory)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Imported %d expenses from %s (%d rows skipped: credits or unparsable).", imported, path, skipped), nil
+		},
+	)
+}
@@ -0,0 +1,211 @@
+// Package copilot – finance_tools_test.go contains unit tests for the finance skill.
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestFinanceDB(t *testing.T) *SkillDB {
+	t.Helper()
+	db, err := OpenSkillDatabase(t.TempDir())
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitFinanceTables(); err != nil {
+		t.Fatalf("init finance tables: %v", err)
+	}
+	return db
+}
+
+func TestInitFinanceTablesIdempotent(t *testing.T) {
+	db := newTestFinanceDB(t)
+
+	// Running migrations again must be a no-op.
+	if err := db.InitFinanceTables(); err != nil {
+		t.Fatalf("second init failed: %v", err)
+	}
+
+	var version int
+	if err := db.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM _finance_schema`).Scan(&version); err != nil {
+		t.Fatalf("read version: %v", err)
+	}
+	if version != len(financeMigrations) {
+		t.Errorf("expected schema version %d, got %d", len(financeMigrations), version)
+	}
+}
+
+func TestAddExpenseAndMonthlyReport(t *testing.T) {
+	db := newTestFinanceDB(t)
+
+	if _, err := db.AddExpense(-5, "groceries", "", "", "manual"); err == nil {
+		t.Error("expected error for negative amount")
+	}
+	if _, err := db.AddExpense(5, "", "", "", "manual"); err == nil {
+		t.Error("expected error for empty category")
+	}
+	if _, err := db.AddExpense(5, "groceries", "", "not-a-date", "manual"); err == nil {
+		t.Error("expected error for invalid date")
+	}
+
+	for _, e := range []struct {
+		amount   float64
+		category string
+		date     string
+	}{
+		{42.50, "Groceries", "2026-08-03"},
+		{17.25, "groceries", "2026-08-10"},
+		{120.00, "transport", "2026-08-05"},
+		{99.99, "groceries", "2026-07-30"}, // previous month, excluded
+	} {
+		if _, err := db.AddExpense(e.amount, e.category, "", e.date, "manual"); err != nil {
+			t.Fatalf("add expense: %v", err)
+		}
+	}
+
+	report, total, err := db.MonthlySpendReport("2026-08")
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("expected 2 categories, got %d: %+v", len(report), report)
+	}
+	// Ordered by spend, highest first.
+	if report[0].Category != "transport" || report[1].Category != "groceries" {
+		t.Errorf("unexpected order: %+v", report)
+	}
+	if report[1].Total != 59.75 || report[1].Count != 2 {
+		t.Errorf("groceries: expected total 59.75 over 2 expenses, got %+v", report[1])
+	}
+	if total != 179.75 {
+		t.Errorf("expected grand total 179.75, got %.2f", total)
+	}
+
+	if _, _, err := db.MonthlySpendReport("08/2026"); err == nil {
+		t.Error("expected error for invalid month format")
+	}
+}
+
+func TestSetBudget(t *testing.T) {
+	db := newTestFinanceDB(t)
+
+	if err := db.SetBudget("groceries", -1); err == nil {
+		t.Error("expected error for negative limit")
+	}
+	if err := db.SetBudget("Groceries", 400); err != nil {
+		t.Fatalf("set budget: %v", err)
+	}
+	if _, err := db.AddExpense(100, "groceries", "", "2026-08-01", "manual"); err != nil {
+		t.Fatalf("add expense: %v", err)
+	}
+
+	report, _, err := db.MonthlySpendReport("2026-08")
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if report[0].Budget != 400 {
+		t.Errorf("expected budget 400 in report, got %+v", report[0])
+	}
+
+	// Replacing and removing.
+	if err := db.SetBudget("groceries", 500); err != nil {
+		t.Fatalf("update budget: %v", err)
+	}
+	if err := db.SetBudget("groceries", 0); err != nil {
+		t.Fatalf("remove budget: %v", err)
+	}
+	report, _, err = db.MonthlySpendReport("2026-08")
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if report[0].Budget != 0 {
+		t.Errorf("expected no budget after removal, got %+v", report[0])
+	}
+}
+
+func TestImportExpensesCSV(t *testing.T) {
+	db := newTestFinanceDB(t)
+
+	statement := strings.Join([]string{
+		"Date,Description,Amount,Category",
+		"2026-08-01,Supermarket,-42.50,Groceries",
+		"05/08/2026,Bus pass,\"-1.234,56\",", // European amount + DD/MM date
+		"2026-08-07,Salary,2500.00,",         // credit, skipped
+		"2026-08-09,Coffee,not-a-number,",    // unparsable amount, skipped
+		"someday,Mystery,-5.00,",             // unparsable date, skipped
+	}, "\n")
+
+	imported, skipped, err := db.ImportExpensesCSV(strings.NewReader(statement), "misc")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if imported != 2 {
+		t.Errorf("expected 2 imported, got %d", imported)
+	}
+	if skipped != 3 {
+		t.Errorf("expected 3 skipped, got %d", skipped)
+	}
+
+	report, total, err := db.MonthlySpendReport("2026-08")
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if total != 1277.06 {
+		t.Errorf("expected total 1277.06, got %.2f", total)
+	}
+	categories := map[string]bool{}
+	for _, c := range report {
+		categories[c.Category] = true
+	}
+	if !categories["groceries"] || !categories["misc"] {
+		t.Errorf("expected groceries and misc categories, got %+v", report)
+	}
+
+	// Missing date/amount columns.
+	if _, _, err := db.ImportExpensesCSV(strings.NewReader("Foo,Bar\n1,2\n"), ""); err == nil {
+		t.Error("expected error for undetectable columns")
+	}
+}
+
+func TestParseStatementAmount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want float64
+	}{
+		{"-42.50", -42.50},
+		{"1,234.56", 1234.56},
+		{"-1.234,56", -1234.56},
+		{"R$ 10,00", 10.00},
+		{"€5.00", 5.00},
+	}
+	for _, tc := range cases {
+		got, err := parseStatementAmount(tc.in)
+		if err != nil {
+			t.Errorf("parseStatementAmount(%q) failed: %v", tc.in, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseStatementAmount(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+	if _, err := parseStatementAmount(""); err == nil {
+		t.Error("expected error for empty amount")
+	}
+}
+
+func TestParseStatementDate(t *testing.T) {
+	for _, in := range []string{"2026-08-05", "05/08/2026", "2026/08/05", "05-08-2026"} {
+		got, err := parseStatementDate(in)
+		if err != nil {
+			t.Errorf("parseStatementDate(%q) failed: %v", in, err)
+			continue
+		}
+		if got != "2026-08-05" {
+			t.Errorf("parseStatementDate(%q) = %q, want 2026-08-05", in, got)
+		}
+	}
+	if _, err := parseStatementDate("yesterday"); err == nil {
+		t.Error("expected error for unparsable date")
+	}
+}
@@ -0,0 +1,315 @@
+// Package copilot – gitops.go syncs assistant state from a git repository.
+// In GitOps mode the daemon polls a remote repo containing config, skills,
+// personas, and runbooks; on new commits it validates the incoming config,
+// hot-applies what it can, and reports the applied diff to the owner chat.
+// Teams get reviewable, versioned assistant configuration.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	gitopsDefaultBranch   = "main"
+	gitopsDefaultPath     = "./data/gitops"
+	gitopsDefaultInterval = 60
+)
+
+// GitOpsConfig configures live config and skill sync from a git repository.
+type GitOpsConfig struct {
+	// Enabled turns GitOps mode on.
+	Enabled bool `yaml:"enabled"`
+
+	// RepoURL is the git repository to sync from (https or ssh).
+	RepoURL string `yaml:"repo_url"`
+
+	// Branch is the branch to track (default: "main").
+	Branch string `yaml:"branch"`
+
+	// LocalPath is where the repo is checked out (default: "./data/gitops").
+	LocalPath string `yaml:"local_path"`
+
+	// IntervalSeconds is the polling interval (default: 60).
+	IntervalSeconds int `yaml:"interval_seconds"`
+
+	// NotifyChannel and NotifyChatID pick the chat that receives applied-diff
+	// reports. When empty, the first configured owner is notified.
+	NotifyChannel string `yaml:"notify_channel"`
+	NotifyChatID  string `yaml:"notify_chat_id"`
+}
+
+// DefaultGitOpsConfig returns GitOps defaults (disabled).
+func DefaultGitOpsConfig() GitOpsConfig {
+	return GitOpsConfig{
+		Enabled:         false,
+		Branch:          gitopsDefaultBranch,
+		LocalPath:       gitopsDefaultPath,
+		IntervalSeconds: gitopsDefaultInterval,
+	}
+}
+
+// GitOpsManager polls the configured repository and applies new commits.
+// Uses the git CLI (like the git tools) so the user's credentials and SSH
+// config work unchanged.
+type GitOpsManager struct {
+	config GitOpsConfig
+	logger *slog.Logger
+
+	// onConfig is called with the validated config from the new commit
+	// (typically Assistant.ApplyConfigUpdate).
+	onConfig func(*Config)
+
+	// onSkills is called when skill files changed, with the checkout's
+	// skills directory. The caller syncs and reloads the registry.
+	onSkills func(dir string) error
+
+	// notify delivers applied-diff reports to the owner chat.
+	notify func(msg string)
+}
+
+// NewGitOpsManager creates a GitOps manager. Callbacks may be nil.
+func NewGitOpsManager(cfg GitOpsConfig, onConfig func(*Config), onSkills func(string) error, notify func(string), logger *slog.Logger) *GitOpsManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.Branch == "" {
+		cfg.Branch = gitopsDefaultBranch
+	}
+	if cfg.LocalPath == "" {
+		cfg.LocalPath = gitopsDefaultPath
+	}
+	if cfg.IntervalSeconds <= 0 {
+		cfg.IntervalSeconds = gitopsDefaultInterval
+	}
+	return &GitOpsManager{
+		config:   cfg,
+		logger:   logger.With("component", "gitops"),
+		onConfig: onConfig,
+		onSkills: onSkills,
+		notify:   notify,
+	}
+}
+
+// Start begins polling in the calling goroutine. Exits when ctx is cancelled.
+func (g *GitOpsManager) Start(ctx context.Context) {
+	if g.config.RepoURL == "" {
+		g.logger.Warn("gitops enabled but repo_url is empty, not starting")
+		return
+	}
+
+	g.sync(ctx)
+
+	ticker := time.NewTicker(time.Duration(g.config.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			g.logger.Info("gitops sync stopped")
+			return
+		case <-ticker.C:
+			g.sync(ctx)
+		}
+	}
+}
+
+// sync clones on first run, then fetches and applies new commits.
+func (g *GitOpsManager) sync(ctx context.Context) {
+	if _, err := os.Stat(filepath.Join(g.config.LocalPath, ".git")); err != nil {
+		g.initialClone(ctx)
+		return
+	}
+
+	if _, err := g.git(ctx, "fetch", "origin", g.config.Branch); err != nil {
+		g.logger.Warn("gitops: fetch failed", "error", err)
+		return
+	}
+
+	local, err := g.git(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		g.logger.Warn("gitops: rev-parse failed", "error", err)
+		return
+	}
+	remote, err := g.git(ctx, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		g.logger.Warn("gitops: rev-parse failed", "error", err)
+		return
+	}
+	if local == remote {
+		return
+	}
+
+	changed, _ := g.git(ctx, "diff", "--name-only", "HEAD", "FETCH_HEAD")
+	stat, _ := g.git(ctx, "diff", "--stat", "HEAD", "FETCH_HEAD")
+	changedFiles := strings.Fields(changed)
+
+	// Validate the incoming config BEFORE moving the checkout. A broken
+	// commit is rejected and the running state stays untouched; the next
+	// commit that fixes it will apply normally.
+	configChanged := containsPathPrefix(changedFiles, "config.yaml")
+	if configChanged {
+		if err := g.validateIncomingConfig(ctx); err != nil {
+			g.logger.Warn("gitops: rejecting commit, config invalid",
+				"commit", shortCommit(remote), "error", err)
+			g.report(fmt.Sprintf("❌ GitOps: commit %s rejected — config invalid: %v", shortCommit(remote), err))
+			return
+		}
+	}
+
+	if _, err := g.git(ctx, "reset", "--hard", "FETCH_HEAD"); err != nil {
+		g.logger.Warn("gitops: reset failed", "error", err)
+		return
+	}
+
+	var applied []string
+	if configChanged && g.onConfig != nil {
+		cfg, err := LoadConfigFromFile(filepath.Join(g.config.LocalPath, "config.yaml"))
+		if err == nil {
+			g.onConfig(cfg)
+			applied = append(applied, "config")
+		}
+	}
+	if hasPathUnder(changedFiles, "skills/") && g.onSkills != nil {
+		if err := g.onSkills(filepath.Join(g.config.LocalPath, "skills")); err != nil {
+			g.logger.Warn("gitops: skill sync failed", "error", err)
+		} else {
+			applied = append(applied, "skills")
+		}
+	}
+	if hasPathUnder(changedFiles, "personas/") {
+		applied = append(applied, "personas")
+	}
+	if hasPathUnder(changedFiles, "runbooks/") {
+		applied = append(applied, "runbooks")
+	}
+
+	g.logger.Info("gitops: applied commit",
+		"commit", shortCommit(remote), "files", len(changedFiles), "applied", applied)
+
+	msg := fmt.Sprintf("✅ GitOps: applied %s", shortCommit(remote))
+	if len(applied) > 0 {
+		msg += " (" + strings.Join(applied, ", ") + ")"
+	}
+	if stat != "" {
+		msg += "\n```\n" + stat + "\n```"
+	}
+	g.report(msg)
+}
+
+// initialClone performs the first checkout and applies its contents.
+func (g *GitOpsManager) initialClone(ctx context.Context) {
+	cloneCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(cloneCtx, "git", "clone", "--depth", "1",
+		"--branch", g.config.Branch, g.config.RepoURL, g.config.LocalPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		g.logger.Warn("gitops: clone failed",
+			"repo", g.config.RepoURL, "error", err, "output", strings.TrimSpace(string(out)))
+		return
+	}
+
+	head, _ := g.git(ctx, "rev-parse", "HEAD")
+	g.logger.Info("gitops: initial clone", "repo", g.config.RepoURL, "commit", shortCommit(head))
+
+	if g.onConfig != nil {
+		configPath := filepath.Join(g.config.LocalPath, "config.yaml")
+		if _, err := os.Stat(configPath); err == nil {
+			if cfg, err := LoadConfigFromFile(configPath); err == nil {
+				g.onConfig(cfg)
+			} else {
+				g.logger.Warn("gitops: initial config invalid, skipping", "error", err)
+			}
+		}
+	}
+	if g.onSkills != nil {
+		skillsDir := filepath.Join(g.config.LocalPath, "skills")
+		if _, err := os.Stat(skillsDir); err == nil {
+			if err := g.onSkills(skillsDir); err != nil {
+				g.logger.Warn("gitops: initial skill sync failed", "error", err)
+			}
+		}
+	}
+
+	g.report(fmt.Sprintf("✅ GitOps: initial sync at %s", shortCommit(head)))
+}
+
+// validateIncomingConfig loads the config from FETCH_HEAD without touching
+// the working tree.
+func (g *GitOpsManager) validateIncomingConfig(ctx context.Context) error {
+	content, err := g.git(ctx, "show", "FETCH_HEAD:config.yaml")
+	if err != nil {
+		return fmt.Errorf("reading config from commit: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "gitops-config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	tmp.Close()
+
+	if _, err := LoadConfigFromFile(tmp.Name()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// git runs a git command inside the local checkout and returns trimmed output.
+func (g *GitOpsManager) git(ctx context.Context, args ...string) (string, error) {
+	gitCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(gitCtx, "git", append([]string{"-C", g.config.LocalPath}, args...)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w (%s)", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// report sends a message to the owner chat, if a notifier is wired.
+func (g *GitOpsManager) report(msg string) {
+	if g.notify != nil {
+		g.notify(msg)
+	}
+}
+
+// containsPathPrefix reports whether any changed file equals the given path.
+func containsPathPrefix(files []string, path string) bool {
+	for _, f := range files {
+		if f == path {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPathUnder reports whether any changed file lives under the given prefix.
+func hasPathUnder(files []string, prefix string) bool {
+	for _, f := range files {
+		if strings.HasPrefix(f, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// shortCommit abbreviates a commit hash for chat messages.
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,48 @@
+package copilot
+
+import (
+	"testing"
+)
+
+func TestGitOpsManagerDefaults(t *testing.T) {
+	m := NewGitOpsManager(GitOpsConfig{Enabled: true, RepoURL: "https://example.com/repo.git"}, nil, nil, nil, nil)
+
+	if m.config.Branch != "main" {
+		t.Errorf("Branch = %q, want main", m.config.Branch)
+	}
+	if m.config.LocalPath != "./data/gitops" {
+		t.Errorf("LocalPath = %q, want ./data/gitops", m.config.LocalPath)
+	}
+	if m.config.IntervalSeconds != 60 {
+		t.Errorf("IntervalSeconds = %d, want 60", m.config.IntervalSeconds)
+	}
+}
+
+func TestGitOpsChangedFileMatching(t *testing.T) {
+	files := []string{"config.yaml", "skills/deploy/skill.yaml", "runbooks/oncall.md"}
+
+	if !containsPathPrefix(files, "config.yaml") {
+		t.Error("config.yaml should match")
+	}
+	if containsPathPrefix(files, "config.yml") {
+		t.Error("config.yml should not match")
+	}
+	if !hasPathUnder(files, "skills/") {
+		t.Error("skills/ prefix should match")
+	}
+	if !hasPathUnder(files, "runbooks/") {
+		t.Error("runbooks/ prefix should match")
+	}
+	if hasPathUnder(files, "personas/") {
+		t.Error("personas/ prefix should not match")
+	}
+}
+
+func TestShortCommit(t *testing.T) {
+	if got := shortCommit("0123456789abcdef"); got != "0123456" {
+		t.Errorf("shortCommit = %q, want 0123456", got)
+	}
+	if got := shortCommit("abc"); got != "abc" {
+		t.Errorf("shortCommit = %q, want abc", got)
+	}
+}
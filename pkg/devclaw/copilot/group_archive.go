@@ -0,0 +1,166 @@
+// Package copilot – group_archive.go implements passive listening mode:
+// groups that opt in (groups.groups[].archive.enabled) have every message
+// silently recorded into a searchable per-group archive, even when the
+// bot never responds. Members can later run /search group <query> to
+// answer "what did we decide about X last week?". Retention is bounded
+// per group and expired rows are pruned on write.
+package copilot
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+const (
+	// groupArchiveDefaultRetentionDays applies when a group enables
+	// archiving without setting retention_days.
+	groupArchiveDefaultRetentionDays = 30
+
+	// groupArchiveSearchLimit caps how many matches a search returns.
+	groupArchiveSearchLimit = 10
+)
+
+// GroupArchiveEntry is one archived group message.
+type GroupArchiveEntry struct {
+	SenderJID  string
+	SenderName string
+	Content    string
+	CreatedAt  time.Time
+}
+
+// GroupArchive stores passively archived group messages in the central
+// database (group_archive table).
+type GroupArchive struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewGroupArchive creates a group archive backed by the given database.
+func NewGroupArchive(db *sql.DB, logger *slog.Logger) *GroupArchive {
+	return &GroupArchive{db: db, logger: logger}
+}
+
+// Save records a group message and prunes entries older than the group's
+// retention window. Empty messages (e.g. media without caption) are skipped.
+func (g *GroupArchive) Save(groupJID, senderJID, senderName, content string, retentionDays int) error {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil
+	}
+	groupJID = normalizeJID(groupJID)
+
+	now := time.Now().UTC()
+	_, err := g.db.Exec(
+		`INSERT INTO group_archive (group_jid, sender_jid, sender_name, content, created_at)
+		 VALUES (?, ?, ?, ?, ?)`,
+		groupJID, senderJID, senderName, content, now.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("archive group message: %w", err)
+	}
+
+	if retentionDays <= 0 {
+		retentionDays = groupArchiveDefaultRetentionDays
+	}
+	cutoff := now.AddDate(0, 0, -retentionDays).Format(time.RFC3339)
+	if _, err := g.db.Exec(
+		`DELETE FROM group_archive WHERE group_jid = ? AND created_at < ?`,
+		groupJID, cutoff); err != nil {
+		g.logger.Warn("group archive prune failed", "group", groupJID, "error", err)
+	}
+
+	return nil
+}
+
+// Search returns the most recent archived messages in a group matching
+// the query (case-insensitive substring), newest first.
+func (g *GroupArchive) Search(groupJID, query string) ([]GroupArchiveEntry, error) {
+	rows, err := g.db.Query(
+		`SELECT sender_jid, sender_name, content, created_at
+		 FROM group_archive
+		 WHERE group_jid = ? AND content LIKE ? ESCAPE '\'
+		 ORDER BY id DESC LIMIT ?`,
+		normalizeJID(groupJID), "%"+escapeLIKE(query)+"%", groupArchiveSearchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("search group archive: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []GroupArchiveEntry
+	for rows.Next() {
+		var e GroupArchiveEntry
+		var createdAt string
+		if err := rows.Scan(&e.SenderJID, &e.SenderName, &e.Content, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan archive entry: %w", err)
+		}
+		e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Count returns how many messages are currently archived for a group.
+func (g *GroupArchive) Count(groupJID string) (int, error) {
+	var n int
+	err := g.db.QueryRow(`SELECT COUNT(*) FROM group_archive WHERE group_jid = ?`,
+		normalizeJID(groupJID)).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count group archive: %w", err)
+	}
+	return n, nil
+}
+
+// escapeLIKE escapes SQL LIKE metacharacters so user queries match
+// literally.
+func escapeLIKE(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "%", `\%`)
+	s = strings.ReplaceAll(s, "_", `\_`)
+	return s
+}
+
+// searchCommand handles /search group <query>: members of an archiving
+// group can look up past messages by keyword.
+func (a *Assistant) searchCommand(args []string, msg *channels.IncomingMessage) string {
+	if len(args) < 2 || !strings.EqualFold(args[0], "group") {
+		return "Usage: /search group <query>"
+	}
+	if !msg.IsGroup {
+		return "This command only works in group chats."
+	}
+	if a.groupArchive == nil || a.groupPolicyMgr == nil {
+		return "Group archiving is not available."
+	}
+	if a.groupPolicyMgr.GetArchive(msg.ChatID) == nil {
+		return "Archiving is not enabled for this group."
+	}
+	if !a.groupPolicyMgr.CanSearchArchive(msg.ChatID, msg.From) {
+		return "Permission denied."
+	}
+
+	query := strings.Join(args[1:], " ")
+	entries, err := a.groupArchive.Search(msg.ChatID, query)
+	if err != nil {
+		a.logger.Warn("group archive search failed", "error", err)
+		return "Search failed. Try again in a moment."
+	}
+	if len(entries) == 0 {
+		return fmt.Sprintf("No archived messages matching %q.", query)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*Archive results for %q:*\n", query))
+	for _, e := range entries {
+		sender := e.SenderName
+		if sender == "" {
+			sender = e.SenderJID
+		}
+		b.WriteString(fmt.Sprintf("\n[%s] %s: %s",
+			e.CreatedAt.Local().Format("Jan 2 15:04"), sender, truncate(e.Content, 200)))
+	}
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package copilot
+
+import (
+	"database/sql"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// testGroupArchiveDB creates an in-memory SQLite database for testing.
+func testGroupArchiveDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	schema := `
+	CREATE TABLE IF NOT EXISTS group_archive (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		group_jid   TEXT NOT NULL,
+		sender_jid  TEXT DEFAULT '',
+		sender_name TEXT DEFAULT '',
+		content     TEXT NOT NULL,
+		created_at  TEXT NOT NULL
+	);`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestGroupArchiveSaveAndSearch(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	archive := NewGroupArchive(testGroupArchiveDB(t), logger)
+
+	group := "12345@g.us"
+	if err := archive.Save(group, "a@s.whatsapp.net", "João", "We should deploy on Friday", 30); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := archive.Save(group, "b@s.whatsapp.net", "Maria", "Agreed, Friday works", 30); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := archive.Save("other@g.us", "c@s.whatsapp.net", "Carlos", "Friday is bad", 30); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	entries, err := archive.Search(group, "friday")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 matches scoped to the group, got %d", len(entries))
+	}
+	// Newest first.
+	if entries[0].SenderName != "Maria" {
+		t.Errorf("expected newest entry first, got sender %q", entries[0].SenderName)
+	}
+
+	if n, err := archive.Count(group); err != nil || n != 2 {
+		t.Errorf("expected count 2, got %d (err %v)", n, err)
+	}
+}
+
+func TestGroupArchiveSkipsEmptyMessages(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	archive := NewGroupArchive(testGroupArchiveDB(t), logger)
+
+	if err := archive.Save("12345@g.us", "a@s.whatsapp.net", "João", "   ", 30); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if n, _ := archive.Count("12345@g.us"); n != 0 {
+		t.Errorf("expected empty messages to be skipped, got %d entries", n)
+	}
+}
+
+func TestGroupArchiveRetention(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db := testGroupArchiveDB(t)
+	archive := NewGroupArchive(db, logger)
+
+	group := "12345@g.us"
+
+	// Insert an entry well past the retention window directly.
+	old := time.Now().UTC().AddDate(0, 0, -60).Format(time.RFC3339)
+	if _, err := db.Exec(
+		`INSERT INTO group_archive (group_jid, sender_jid, sender_name, content, created_at)
+		 VALUES (?, ?, ?, ?, ?)`,
+		group, "a@s.whatsapp.net", "João", "ancient decision", old); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	// A fresh save prunes expired rows for the group.
+	if err := archive.Save(group, "b@s.whatsapp.net", "Maria", "new message", 30); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	if n, _ := archive.Count(group); n != 1 {
+		t.Errorf("expected expired entry to be pruned, got %d entries", n)
+	}
+}
+
+func TestGroupArchiveSearchEscapesLIKE(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	archive := NewGroupArchive(testGroupArchiveDB(t), logger)
+
+	group := "12345@g.us"
+	if err := archive.Save(group, "a@s.whatsapp.net", "João", "progress at 50% today", 30); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	entries, err := archive.Search(group, "50%")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected literal %% match, got %d entries", len(entries))
+	}
+
+	// A stray wildcard must not match everything.
+	entries, err = archive.Search(group, "a%z")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no matches for escaped wildcard query, got %d", len(entries))
+	}
+}
+
+func TestGroupPolicyArchiveConfig(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mgr := NewGroupPolicyManager(GroupsPolicyConfig{
+		Groups: []GroupPolicyConfig{
+			{
+				ID: "archived@g.us",
+				Archive: &GroupArchiveConfig{
+					Enabled:          true,
+					AllowedSearchers: []string{"alice@s.whatsapp.net"},
+				},
+			},
+			{
+				ID: "open-search@g.us",
+				Archive: &GroupArchiveConfig{
+					Enabled: true,
+				},
+			},
+			{
+				ID:      "disabled@g.us",
+				Archive: &GroupArchiveConfig{Enabled: false},
+			},
+		},
+	}, logger)
+
+	if mgr.GetArchive("archived@g.us") == nil {
+		t.Error("expected archive config for opted-in group")
+	}
+	if mgr.GetArchive("disabled@g.us") != nil {
+		t.Error("expected nil archive config when not enabled")
+	}
+	if mgr.GetArchive("unknown@g.us") != nil {
+		t.Error("expected nil archive config for unconfigured group")
+	}
+
+	if !mgr.CanSearchArchive("archived@g.us", "alice@s.whatsapp.net") {
+		t.Error("allowed searcher should be able to search")
+	}
+	if mgr.CanSearchArchive("archived@g.us", "bob@s.whatsapp.net") {
+		t.Error("non-listed user must not search a restricted archive")
+	}
+	if !mgr.CanSearchArchive("open-search@g.us", "anyone@s.whatsapp.net") {
+		t.Error("any member may search when allowed_searchers is empty")
+	}
+	if mgr.CanSearchArchive("disabled@g.us", "alice@s.whatsapp.net") {
+		t.Error("search must be denied when archiving is disabled")
+	}
+}
@@ -49,6 +49,35 @@ type QuietHoursConfig struct {
 	Days []int `json:"days,omitempty" yaml:"days,omitempty"`
 }
 
+// AutoTranslateConfig defines the language pair for a cross-language group.
+// When enabled, incoming group messages are translated into the agent's
+// language before processing, and replies are translated back.
+type AutoTranslateConfig struct {
+	// Enabled activates auto-translation for this group.
+	Enabled bool `yaml:"enabled"`
+	// GroupLanguage is the language the group chats in (e.g. "Portuguese").
+	GroupLanguage string `yaml:"group_language"`
+	// AgentLanguage is the language the agent works in (e.g. "English").
+	AgentLanguage string `yaml:"agent_language"`
+	// DualPost posts both the agent's original reply and its translation,
+	// so mixed-language groups can read either version.
+	DualPost bool `yaml:"dual_post"`
+}
+
+// GroupArchiveConfig enables passive listening mode for a group: the bot
+// silently records messages into a searchable per-group archive without
+// responding to them, so members can later ask "what did we decide about
+// X last week?" via /search group. Archiving is strictly opt-in per group.
+type GroupArchiveConfig struct {
+	// Enabled activates message archiving for this group.
+	Enabled bool `yaml:"enabled"`
+	// RetentionDays is how long archived messages are kept (default: 30).
+	RetentionDays int `yaml:"retention_days"`
+	// AllowedSearchers restricts /search group to these user JIDs.
+	// Empty means any allowed group member can search.
+	AllowedSearchers []string `yaml:"allowed_searchers"`
+}
+
 // GroupPolicyConfig holds configuration for a specific group's policy.
 type GroupPolicyConfig struct {
 	// ID is the group JID.
@@ -69,6 +98,13 @@ type GroupPolicyConfig struct {
 	MaxParticipants int `yaml:"max_participants"`
 	// AllowedUsers is the list of allowed user JIDs for allowlist policy.
 	AllowedUsers []string `yaml:"allowed_users"`
+	// AutoTranslate configures translation for cross-language groups.
+	AutoTranslate *AutoTranslateConfig `yaml:"auto_translate"`
+	// QueueMode overrides the channel queue mode for this group (empty = inherit).
+	// Busy groups typically want "collect"; owner DMs "interrupt".
+	QueueMode QueueMode `yaml:"queue_mode"`
+	// Archive configures passive message archiving for this group.
+	Archive *GroupArchiveConfig `yaml:"archive"`
 }
 
 // GroupsPolicyConfig holds all group policy configuration.
@@ -109,6 +145,13 @@ func NewGroupPolicyManager(cfg GroupsPolicyConfig, logger *slog.Logger) *GroupPo
 			normalizedUsers[i] = normalizeJID(u)
 		}
 		g.AllowedUsers = normalizedUsers
+
+		// Normalize archive searchers.
+		if g.Archive != nil {
+			for i, u := range g.Archive.AllowedSearchers {
+				g.Archive.AllowedSearchers[i] = normalizeJID(u)
+			}
+		}
 	}
 
 	// Index blocked groups.
@@ -192,12 +235,16 @@ func (m *GroupPolicyManager) GetGroupConfig(groupJID string) *GroupPolicyConfig
 
 // IsQuietHours checks if quiet hours are active for a group.
 func (m *GroupPolicyManager) IsQuietHours(cfg *GroupPolicyConfig) bool {
-	if cfg == nil || cfg.QuietHours == nil {
+	if cfg == nil {
 		return false
 	}
+	return quietHoursActive(cfg.QuietHours, time.Now())
+}
 
-	qh := cfg.QuietHours
-	if qh.Start == "" || qh.End == "" {
+// quietHoursActive checks whether a quiet hours window covers the given
+// moment. Shared by group policy and do-not-disturb (dnd.go).
+func quietHoursActive(qh *QuietHoursConfig, now time.Time) bool {
+	if qh == nil || qh.Start == "" || qh.End == "" {
 		return false
 	}
 
@@ -211,8 +258,8 @@ func (m *GroupPolicyManager) IsQuietHours(cfg *GroupPolicyConfig) bool {
 		}
 	}
 
-	// Get current time in the group's timezone.
-	now := time.Now().In(tz)
+	// Get the time in the window's timezone.
+	now = now.In(tz)
 	currentMinutes := now.Hour()*60 + now.Minute()
 
 	// Parse start and end times.
@@ -242,6 +289,64 @@ func (m *GroupPolicyManager) GetWorkspace(groupJID string) string {
 	return ""
 }
 
+// GetAutoTranslate returns the auto-translate config for a group, or nil
+// when auto-translation is disabled or the language pair is incomplete.
+func (m *GroupPolicyManager) GetAutoTranslate(groupJID string) *AutoTranslateConfig {
+	cfg := m.GetGroupConfig(groupJID)
+	if cfg == nil || cfg.AutoTranslate == nil {
+		return nil
+	}
+	at := cfg.AutoTranslate
+	if !at.Enabled || at.GroupLanguage == "" || at.AgentLanguage == "" {
+		return nil
+	}
+	return at
+}
+
+// GetQueueMode returns the queue mode override for a group, or empty string
+// when the group inherits the channel/default mode.
+func (m *GroupPolicyManager) GetQueueMode(groupJID string) QueueMode {
+	cfg := m.GetGroupConfig(groupJID)
+	if cfg == nil {
+		return ""
+	}
+	return cfg.QueueMode
+}
+
+// GetArchive returns the archive config for a group, or nil when
+// archiving is not enabled for it.
+func (m *GroupPolicyManager) GetArchive(groupJID string) *GroupArchiveConfig {
+	cfg := m.GetGroupConfig(groupJID)
+	if cfg == nil || cfg.Archive == nil {
+		return nil
+	}
+	ar := cfg.Archive
+	if !ar.Enabled {
+		return nil
+	}
+	return ar
+}
+
+// CanSearchArchive reports whether a user may run /search group in the
+// given group. With no allowed_searchers configured, any member of a group
+// with archiving enabled may search.
+func (m *GroupPolicyManager) CanSearchArchive(groupJID, userJID string) bool {
+	ar := m.GetArchive(groupJID)
+	if ar == nil {
+		return false
+	}
+	if len(ar.AllowedSearchers) == 0 {
+		return true
+	}
+	userJID = normalizeJID(userJID)
+	for _, u := range ar.AllowedSearchers {
+		if u == userJID {
+			return true
+		}
+	}
+	return false
+}
+
 // IsBlocked returns true if the group is blocked.
 func (m *GroupPolicyManager) IsBlocked(groupJID string) bool {
 	return m.blocked[normalizeJID(groupJID)]
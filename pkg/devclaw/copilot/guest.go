@@ -0,0 +1,151 @@
+// Package copilot – guest.go implements the read-only demo/guest access level.
+// Guests run with the locked-down "guest" tool profile, a capped turn count,
+// and a daily token budget; their replies carry a visible watermark so owners
+// can safely share the bot in groups or demos.
+package copilot
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// guestDefaultMaxTurns caps LLM round-trips per guest message.
+	guestDefaultMaxTurns = 5
+
+	// guestDefaultDailyTokens is the default per-guest daily token budget.
+	guestDefaultDailyTokens = 50000
+
+	// guestDefaultWatermark is appended to every guest reply.
+	guestDefaultWatermark = "🔒 _guest mode — read-only demo_"
+)
+
+// GuestConfig configures the limits applied to guest-level contacts.
+type GuestConfig struct {
+	// MaxTurns caps agent round-trips per guest message (default: 5).
+	MaxTurns int `yaml:"max_turns"`
+
+	// DailyTokenBudget is the per-guest token budget per day (default: 50000).
+	DailyTokenBudget int `yaml:"daily_token_budget"`
+
+	// Watermark is appended to every guest reply. Empty = default watermark.
+	Watermark string `yaml:"watermark"`
+}
+
+// DefaultGuestConfig returns the default guest limits.
+func DefaultGuestConfig() GuestConfig {
+	return GuestConfig{
+		MaxTurns:         guestDefaultMaxTurns,
+		DailyTokenBudget: guestDefaultDailyTokens,
+	}
+}
+
+// guestUsage tracks a guest's token consumption for one day.
+type guestUsage struct {
+	Day    string // "2006-01-02"
+	Tokens int
+}
+
+// GuestLimiter enforces per-guest daily token budgets.
+type GuestLimiter struct {
+	config GuestConfig
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	usage map[string]*guestUsage // normalized JID → today's usage
+}
+
+// NewGuestLimiter creates a guest limiter. Zero-value config fields fall back
+// to the defaults.
+func NewGuestLimiter(config GuestConfig, logger *slog.Logger) *GuestLimiter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.MaxTurns <= 0 {
+		config.MaxTurns = guestDefaultMaxTurns
+	}
+	if config.DailyTokenBudget <= 0 {
+		config.DailyTokenBudget = guestDefaultDailyTokens
+	}
+	if config.Watermark == "" {
+		config.Watermark = guestDefaultWatermark
+	}
+	return &GuestLimiter{
+		config: config,
+		logger: logger.With("component", "guest_limiter"),
+		usage:  make(map[string]*guestUsage),
+	}
+}
+
+// Allow reports whether the guest still has budget left today.
+func (g *GuestLimiter) Allow(jid string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.remaining(jid, time.Now()) > 0
+}
+
+// Record adds tokens consumed by a guest to today's usage.
+func (g *GuestLimiter) Record(jid string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	norm := normalizeJID(jid)
+	day := time.Now().Format("2006-01-02")
+	u, ok := g.usage[norm]
+	if !ok || u.Day != day {
+		u = &guestUsage{Day: day}
+		g.usage[norm] = u
+	}
+	u.Tokens += tokens
+
+	if u.Tokens >= g.config.DailyTokenBudget {
+		g.logger.Info("guest daily token budget exhausted",
+			"jid", norm,
+			"tokens", u.Tokens,
+			"budget", g.config.DailyTokenBudget,
+		)
+	}
+}
+
+// Remaining returns the guest's remaining token budget for today.
+func (g *GuestLimiter) Remaining(jid string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.remaining(jid, time.Now())
+}
+
+// remaining computes the budget left. Caller must hold g.mu.
+func (g *GuestLimiter) remaining(jid string, now time.Time) int {
+	u, ok := g.usage[normalizeJID(jid)]
+	if !ok || u.Day != now.Format("2006-01-02") {
+		return g.config.DailyTokenBudget
+	}
+	left := g.config.DailyTokenBudget - u.Tokens
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// CapAgentConfig returns a copy of the agent config with guest limits applied:
+// capped turn count, no continuations, no reflection passes.
+func (g *GuestLimiter) CapAgentConfig(base AgentConfig) AgentConfig {
+	capped := base
+	if capped.MaxTurns == 0 || capped.MaxTurns > g.config.MaxTurns {
+		capped.MaxTurns = g.config.MaxTurns
+	}
+	capped.MaxContinuations = 0
+	capped.ReflectionEnabled = false
+	capped.SelfCritiqueEnabled = false
+	return capped
+}
+
+// Watermark returns the suffix appended to guest replies.
+func (g *GuestLimiter) Watermark() string {
+	return g.config.Watermark
+}
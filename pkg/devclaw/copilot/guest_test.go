@@ -0,0 +1,148 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+func testGuestLimiter(t *testing.T, cfg GuestConfig) *GuestLimiter {
+	t.Helper()
+	return NewGuestLimiter(cfg, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+}
+
+func TestGuestLimiterBudget(t *testing.T) {
+	g := testGuestLimiter(t, GuestConfig{DailyTokenBudget: 1000})
+
+	if !g.Allow("guest@s.whatsapp.net") {
+		t.Error("fresh guest should be allowed")
+	}
+
+	g.Record("guest@s.whatsapp.net", 600)
+	if !g.Allow("guest@s.whatsapp.net") {
+		t.Error("guest under budget should be allowed")
+	}
+	if left := g.Remaining("guest@s.whatsapp.net"); left != 400 {
+		t.Errorf("remaining = %d, want 400", left)
+	}
+
+	g.Record("guest@s.whatsapp.net", 600)
+	if g.Allow("guest@s.whatsapp.net") {
+		t.Error("guest over budget should be refused")
+	}
+	if left := g.Remaining("guest@s.whatsapp.net"); left != 0 {
+		t.Errorf("remaining = %d, want 0", left)
+	}
+
+	// Budgets are per-guest.
+	if !g.Allow("other@s.whatsapp.net") {
+		t.Error("other guest should have their own budget")
+	}
+}
+
+func TestGuestLimiterDailyReset(t *testing.T) {
+	g := testGuestLimiter(t, GuestConfig{DailyTokenBudget: 100})
+
+	g.Record("guest@s.whatsapp.net", 200)
+	if g.Allow("guest@s.whatsapp.net") {
+		t.Error("guest over budget should be refused")
+	}
+
+	// Backdate yesterday's usage — the budget resets on a new day.
+	g.mu.Lock()
+	for _, u := range g.usage {
+		u.Day = time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	g.mu.Unlock()
+
+	if !g.Allow("guest@s.whatsapp.net") {
+		t.Error("budget should reset on a new day")
+	}
+	if left := g.Remaining("guest@s.whatsapp.net"); left != 100 {
+		t.Errorf("remaining after reset = %d, want 100", left)
+	}
+}
+
+func TestGuestCapAgentConfig(t *testing.T) {
+	g := testGuestLimiter(t, GuestConfig{MaxTurns: 5})
+
+	capped := g.CapAgentConfig(AgentConfig{
+		MaxTurns:            25,
+		MaxContinuations:    3,
+		ReflectionEnabled:   true,
+		SelfCritiqueEnabled: true,
+	})
+	if capped.MaxTurns != 5 {
+		t.Errorf("MaxTurns = %d, want 5", capped.MaxTurns)
+	}
+	if capped.MaxContinuations != 0 || capped.ReflectionEnabled || capped.SelfCritiqueEnabled {
+		t.Errorf("continuations/reflection not disabled: %+v", capped)
+	}
+
+	// Unlimited base turns (0) are also capped.
+	if capped := g.CapAgentConfig(AgentConfig{MaxTurns: 0}); capped.MaxTurns != 5 {
+		t.Errorf("MaxTurns from unlimited = %d, want 5", capped.MaxTurns)
+	}
+
+	// A base already below the cap keeps its stricter limit.
+	if capped := g.CapAgentConfig(AgentConfig{MaxTurns: 3}); capped.MaxTurns != 3 {
+		t.Errorf("MaxTurns = %d, want 3", capped.MaxTurns)
+	}
+}
+
+func TestGuestAccessPolicy(t *testing.T) {
+	t.Parallel()
+	am := NewAccessManager(AccessConfig{DefaultPolicy: PolicyGuest}, nil)
+
+	r := am.Check(makeMsg("stranger@s.whatsapp.net", "stranger@s.whatsapp.net", false))
+	if !r.Allowed {
+		t.Fatal("guest policy should allow unknown contacts")
+	}
+	if r.Level != AccessGuest {
+		t.Errorf("expected AccessGuest, got %v", r.Level)
+	}
+}
+
+func TestExplicitGuestList(t *testing.T) {
+	t.Parallel()
+	am := NewAccessManager(AccessConfig{
+		DefaultPolicy: PolicyDeny,
+		Guests:        []string{"guest@s.whatsapp.net"},
+	}, nil)
+
+	r := am.Check(makeMsg("guest@s.whatsapp.net", "guest@s.whatsapp.net", false))
+	if !r.Allowed || r.Level != AccessGuest {
+		t.Errorf("explicit guest: allowed=%v level=%v, want guest access", r.Allowed, r.Level)
+	}
+
+	// Unknown contacts are still denied.
+	if r := am.Check(makeMsg("stranger@s.whatsapp.net", "stranger@s.whatsapp.net", false)); r.Allowed {
+		t.Error("unknown contact allowed under deny policy")
+	}
+}
+
+func TestGuestProfileLockedDown(t *testing.T) {
+	profile := GetProfile("guest", nil)
+	if profile == nil {
+		t.Fatal("guest profile not found")
+	}
+
+	allTools := []string{
+		"bash", "exec", "ssh", "scp", "set_env",
+		"read_file", "write_file", "edit_file", "list_files",
+		"web_search", "web_fetch", "vault_get", "vault_save",
+	}
+	pc := NewProfileChecker(profile.Allow, profile.Deny, allTools)
+
+	for _, tool := range []string{"bash", "exec", "ssh", "write_file", "edit_file", "vault_get", "vault_save"} {
+		if allowed, _ := pc.Check(tool); allowed {
+			t.Errorf("guest profile allows %s", tool)
+		}
+	}
+	for _, tool := range []string{"read_file", "list_files", "web_search"} {
+		if allowed, reason := pc.Check(tool); !allowed {
+			t.Errorf("guest profile blocks %s: %s", tool, reason)
+		}
+	}
+}
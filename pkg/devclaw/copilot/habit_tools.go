@@ -0,0 +1,669 @@
+// Package copilot – habit_tools.go implements the built-in habits/tasks skill:
+// recurring check-in prompts via the scheduler ("did you work out today?"),
+// streak tracking, snooze handling, and a weekly progress report.
+//
+// Habits live in the skill database (_habits) with one log row per check-in
+// (_habit_log). Each habit owns a scheduler job that prompts the user on its
+// schedule; answers are recorded conversationally through habit_log or with
+// the /tasks command.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/scheduler"
+)
+
+// habitJobPrefix namespaces the scheduler jobs owned by habits.
+const habitJobPrefix = "habit-"
+
+// habitReportJobID is the scheduler job installed by schedule_weekly.
+const habitReportJobID = "habits-weekly-report"
+
+// habitDefaultSchedule is used when habit_add gets no schedule (daily, 9am).
+const habitDefaultSchedule = "0 9 * * *"
+
+// habitsSchema is the DDL for the habit tracking tables.
+const habitsSchema = `
+CREATE TABLE IF NOT EXISTS _habits (
+    id            TEXT PRIMARY KEY,
+    name          TEXT NOT NULL,
+    prompt        TEXT NOT NULL,
+    schedule      TEXT NOT NULL,
+    job_id        TEXT NOT NULL,
+    channel       TEXT,
+    chat_id       TEXT,
+    streak        INTEGER NOT NULL DEFAULT 0,
+    best_streak   INTEGER NOT NULL DEFAULT 0,
+    last_done     TEXT,
+    snoozed_until TEXT,
+    created_at    TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_habits_name ON _habits(name);
+
+CREATE TABLE IF NOT EXISTS _habit_log (
+    id         TEXT PRIMARY KEY,
+    habit_id   TEXT NOT NULL,
+    date       TEXT NOT NULL,
+    status     TEXT NOT NULL,
+    note       TEXT,
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_habit_log_habit ON _habit_log(habit_id, date);
+`
+
+// InitHabitsTables creates the habit tracking tables if they don't exist.
+func (s *SkillDB) InitHabitsTables() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.Exec(habitsSchema); err != nil {
+		return fmt.Errorf("create habits tables: %w", err)
+	}
+
+	return nil
+}
+
+// HabitInfo contains a tracked habit and its current streak state.
+type HabitInfo struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Prompt       string `json:"prompt"`
+	Schedule     string `json:"schedule"`
+	JobID        string `json:"job_id"`
+	Channel      string `json:"channel,omitempty"`
+	ChatID       string `json:"chat_id,omitempty"`
+	Streak       int    `json:"streak"`
+	BestStreak   int    `json:"best_streak"`
+	LastDone     string `json:"last_done,omitempty"`
+	SnoozedUntil string `json:"snoozed_until,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// Snoozed reports whether the habit is currently snoozed.
+func (h *HabitInfo) Snoozed() bool {
+	if h.SnoozedUntil == "" {
+		return false
+	}
+	until, err := time.Parse(time.RFC3339, h.SnoozedUntil)
+	if err != nil {
+		return false
+	}
+	return time.Now().Before(until)
+}
+
+// CreateHabit records a new habit and returns its ID. Names are normalized
+// to lowercase and must be unique. The habit's scheduler job ID is derived
+// from the habit ID (habitJobPrefix + id).
+func (s *SkillDB) CreateHabit(name, prompt, schedule, channel, chatID string) (string, error) {
+	name = normalizeHabitName(name)
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	if prompt == "" {
+		return "", fmt.Errorf("prompt is required")
+	}
+	if schedule == "" {
+		schedule = habitDefaultSchedule
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM _habits WHERE name = ?`, name).Scan(&count); err != nil {
+		return "", fmt.Errorf("check existing habit: %w", err)
+	}
+	if count > 0 {
+		return "", fmt.Errorf("habit %q already exists", name)
+	}
+
+	id := generateID()
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(`
+		INSERT INTO _habits (id, name, prompt, schedule, job_id, channel, chat_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, name, prompt, schedule, habitJobPrefix+id, channel, chatID, now,
+	)
+	if err != nil {
+		return "", fmt.Errorf("insert habit: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetHabit looks a habit up by name or ID.
+func (s *SkillDB) GetHabit(nameOrID string) (*HabitInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.getHabitLocked(nameOrID)
+}
+
+// getHabitLocked is GetHabit without locking; callers must hold s.mu.
+func (s *SkillDB) getHabitLocked(nameOrID string) (*HabitInfo, error) {
+	var h HabitInfo
+	var channel, chatID, lastDone, snoozedUntil sql.NullString
+	err := s.db.QueryRow(`
+		SELECT id, name, prompt, schedule, job_id, channel, chat_id, streak, best_streak, last_done, snoozed_until, created_at
+		FROM _habits
+		WHERE name = ? OR id = ?`,
+		normalizeHabitName(nameOrID), nameOrID,
+	).Scan(&h.ID, &h.Name, &h.Prompt, &h.Schedule, &h.JobID, &channel, &chatID, &h.Streak, &h.BestStreak, &lastDone, &snoozedUntil, &h.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("habit %q not found", nameOrID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get habit: %w", err)
+	}
+
+	if channel.Valid {
+		h.Channel = channel.String
+	}
+	if chatID.Valid {
+		h.ChatID = chatID.String
+	}
+	if lastDone.Valid {
+		h.LastDone = lastDone.String
+	}
+	if snoozedUntil.Valid {
+		h.SnoozedUntil = snoozedUntil.String
+	}
+
+	return &h, nil
+}
+
+// ListHabits returns all habits ordered by name.
+func (s *SkillDB) ListHabits() ([]HabitInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT id, name, prompt, schedule, job_id, channel, chat_id, streak, best_streak, last_done, snoozed_until, created_at
+		FROM _habits
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list habits: %w", err)
+	}
+	defer rows.Close()
+
+	var habits []HabitInfo
+	for rows.Next() {
+		var h HabitInfo
+		var channel, chatID, lastDone, snoozedUntil sql.NullString
+		if err := rows.Scan(&h.ID, &h.Name, &h.Prompt, &h.Schedule, &h.JobID, &channel, &chatID, &h.Streak, &h.BestStreak, &lastDone, &snoozedUntil, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan habit: %w", err)
+		}
+		if channel.Valid {
+			h.Channel = channel.String
+		}
+		if chatID.Valid {
+			h.ChatID = chatID.String
+		}
+		if lastDone.Valid {
+			h.LastDone = lastDone.String
+		}
+		if snoozedUntil.Valid {
+			h.SnoozedUntil = snoozedUntil.String
+		}
+		habits = append(habits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return habits, nil
+}
+
+// LogHabitCheckIn records a check-in for the given date (YYYY-MM-DD, default
+// today) and updates the streak. status must be "done" or "skipped": "done"
+// on consecutive days grows the streak, "skipped" resets it. Logging "done"
+// twice for the same date is a no-op. Returns the updated habit.
+func (s *SkillDB) LogHabitCheckIn(nameOrID, status, note, date string) (*HabitInfo, error) {
+	if status != "done" && status != "skipped" {
+		return nil, fmt.Errorf("status must be 'done' or 'skipped', got %q", status)
+	}
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", date)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.getHabitLocked(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == "done" {
+		if h.LastDone == date {
+			return h, nil
+		}
+		prevDay := day.AddDate(0, 0, -1).Format("2006-01-02")
+		if h.LastDone == prevDay {
+			h.Streak++
+		} else {
+			h.Streak = 1
+		}
+		if h.Streak > h.BestStreak {
+			h.BestStreak = h.Streak
+		}
+		h.LastDone = date
+	} else {
+		h.Streak = 0
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err = s.db.Exec(`
+		UPDATE _habits
+		SET streak = ?, best_streak = ?, last_done = ?, snoozed_until = NULL
+		WHERE id = ?`,
+		h.Streak, h.BestStreak, h.LastDone, h.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update habit: %w", err)
+	}
+	h.SnoozedUntil = ""
+
+	_, err = s.db.Exec(`
+		INSERT INTO _habit_log (id, habit_id, date, status, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		generateID(), h.ID, date, status, note, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert habit log: %w", err)
+	}
+
+	return h, nil
+}
+
+// SnoozeHabit suppresses check-in prompts for the habit until the given time.
+func (s *SkillDB) SnoozeHabit(nameOrID string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.getHabitLocked(nameOrID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`UPDATE _habits SET snoozed_until = ? WHERE id = ?`,
+		until.UTC().Format(time.RFC3339), h.ID)
+	if err != nil {
+		return fmt.Errorf("snooze habit: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveHabit deletes a habit and its log, returning the scheduler job ID
+// the caller should remove.
+func (s *SkillDB) RemoveHabit(nameOrID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.getHabitLocked(nameOrID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.db.Exec(`DELETE FROM _habit_log WHERE habit_id = ?`, h.ID); err != nil {
+		return "", fmt.Errorf("delete habit log: %w", err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM _habits WHERE id = ?`, h.ID); err != nil {
+		return "", fmt.Errorf("delete habit: %w", err)
+	}
+
+	return h.JobID, nil
+}
+
+// HabitProgress aggregates one habit's check-ins over a report window.
+type HabitProgress struct {
+	Name       string `json:"name"`
+	Done       int    `json:"done"`
+	Skipped    int    `json:"skipped"`
+	Streak     int    `json:"streak"`
+	BestStreak int    `json:"best_streak"`
+}
+
+// WeeklyHabitProgress returns per-habit done/skipped counts for the last
+// seven days (including today).
+func (s *SkillDB) WeeklyHabitProgress() ([]HabitProgress, error) {
+	since := time.Now().AddDate(0, 0, -6).Format("2006-01-02")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT h.name, h.streak, h.best_streak,
+			COALESCE(SUM(CASE WHEN l.status = 'done' AND l.date >= ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN l.status = 'skipped' AND l.date >= ? THEN 1 ELSE 0 END), 0)
+		FROM _habits h
+		LEFT JOIN _habit_log l ON l.habit_id = h.id
+		GROUP BY h.id
+		ORDER BY h.name`,
+		since, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("weekly progress: %w", err)
+	}
+	defer rows.Close()
+
+	var progress []HabitProgress
+	for rows.Next() {
+		var p HabitProgress
+		if err := rows.Scan(&p.Name, &p.Streak, &p.BestStreak, &p.Done, &p.Skipped); err != nil {
+			return nil, fmt.Errorf("scan progress: %w", err)
+		}
+		progress = append(progress, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return progress, nil
+}
+
+// parseSnoozeUntil accepts a relative duration ("2h", "1d", "3d") or an
+// absolute YYYY-MM-DD date.
+func parseSnoozeUntil(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Now().AddDate(0, 0, 1), nil
+	}
+	if strings.HasSuffix(s, "d") {
+		var days int
+		if _, err := fmt.Sscanf(s, "%dd", &days); err == nil && days > 0 {
+			return time.Now().AddDate(0, 0, days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return time.Now().Add(d), nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid snooze duration %q (use '2h', '1d', or YYYY-MM-DD)", s)
+}
+
+// normalizeHabitName lowercases and trims a habit name so lookups are
+// case-insensitive.
+func normalizeHabitName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// habitCheckInCommand builds the agent prompt the scheduler fires for a habit.
+func habitCheckInCommand(name, prompt string) string {
+	return fmt.Sprintf("Scheduled check-in for habit %q. First call habit_list: if the habit is snoozed or no longer exists, reply with NO_REPLY. Otherwise ask the user: %s — and when they answer, record it with habit_log (habit %q, status 'done' or 'skipped').", name, prompt, name)
+}
+
+// RegisterHabitTools registers the habits skill tools in the executor.
+func RegisterHabitTools(executor *ToolExecutor, skillDB *SkillDB, sched *scheduler.Scheduler) {
+	if skillDB == nil {
+		return
+	}
+
+	// habit_add
+	executor.Register(
+		MakeToolDefinition("habit_add", "Track a new habit with a recurring check-in prompt (e.g. 'did you work out today?'). A scheduler job asks the question on the given schedule.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Short habit name (e.g. 'workout', 'reading')",
+				},
+				"prompt": map[string]any{
+					"type":        "string",
+					"description": "Check-in question to ask (e.g. 'Did you work out today?')",
+				},
+				"schedule": map[string]any{
+					"type":        "string",
+					"description": "Cron expression for the check-in (default: '0 9 * * *', daily at 09:00)",
+				},
+			},
+			"required": []string{"name", "prompt"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			name, _ := args["name"].(string)
+			prompt, _ := args["prompt"].(string)
+			schedule, _ := args["schedule"].(string)
+			if schedule == "" {
+				schedule = habitDefaultSchedule
+			}
+
+			dt := DeliveryTargetFromContext(ctx)
+
+			id, err := skillDB.CreateHabit(name, prompt, schedule, dt.Channel, dt.ChatID)
+			if err != nil {
+				return nil, err
+			}
+
+			if sched != nil {
+				job := &scheduler.Job{
+					ID:             habitJobPrefix + id,
+					Schedule:       schedule,
+					Type:           "cron",
+					Command:        habitCheckInCommand(normalizeHabitName(name), prompt),
+					Channel:        dt.Channel,
+					ChatID:         dt.ChatID,
+					Enabled:        true,
+					IsolateSession: true,
+					Announce:       true,
+				}
+				if err := sched.Add(job); err != nil {
+					// Roll back so a retry with the same name works.
+					skillDB.RemoveHabit(id)
+					return nil, fmt.Errorf("scheduling check-in: %w", err)
+				}
+			}
+
+			return fmt.Sprintf("Habit %q tracked. Check-in scheduled: %s.", normalizeHabitName(name), schedule), nil
+		},
+	)
+
+	// habit_log
+	executor.Register(
+		MakeToolDefinition("habit_log", "Record a habit check-in: status 'done' grows the streak, 'skipped' resets it.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"habit": map[string]any{
+					"type":        "string",
+					"description": "Habit name or ID",
+				},
+				"status": map[string]any{
+					"type":        "string",
+					"description": "Check-in result",
+					"enum":        []string{"done", "skipped"},
+				},
+				"note": map[string]any{
+					"type":        "string",
+					"description": "Optional note (e.g. '5km run')",
+				},
+				"date": map[string]any{
+					"type":        "string",
+					"description": "Date of the check-in as YYYY-MM-DD (default: today)",
+				},
+			},
+			"required": []string{"habit", "status"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			habit, _ := args["habit"].(string)
+			status, _ := args["status"].(string)
+			note, _ := args["note"].(string)
+			date, _ := args["date"].(string)
+
+			h, err := skillDB.LogHabitCheckIn(habit, status, note, date)
+			if err != nil {
+				return nil, err
+			}
+			if status == "done" {
+				return fmt.Sprintf("Logged %q as done. Streak: %d day(s) (best: %d).", h.Name, h.Streak, h.BestStreak), nil
+			}
+			return fmt.Sprintf("Logged %q as skipped. Streak reset (best: %d).", h.Name, h.BestStreak), nil
+		},
+	)
+
+	// habit_list
+	executor.Register(
+		MakeToolDefinition("habit_list", "List tracked habits with streaks, schedules, and snooze state.", map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{},
+			"additionalProperties": false,
+		}),
+		func(_ context.Context, _ map[string]any) (any, error) {
+			habits, err := skillDB.ListHabits()
+			if err != nil {
+				return nil, err
+			}
+			if len(habits) == 0 {
+				return "No habits tracked. Add one with habit_add.", nil
+			}
+			return habitTable(habits), nil
+		},
+	)
+
+	// habit_snooze
+	executor.Register(
+		MakeToolDefinition("habit_snooze", "Snooze a habit's check-in prompts for a while (the streak is kept).", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"habit": map[string]any{
+					"type":        "string",
+					"description": "Habit name or ID",
+				},
+				"until": map[string]any{
+					"type":        "string",
+					"description": "How long to snooze: duration ('2h', '1d', '3d') or date (YYYY-MM-DD). Default: 1 day.",
+				},
+			},
+			"required": []string{"habit"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			habit, _ := args["habit"].(string)
+			untilArg, _ := args["until"].(string)
+
+			until, err := parseSnoozeUntil(untilArg)
+			if err != nil {
+				return nil, err
+			}
+			if err := skillDB.SnoozeHabit(habit, until); err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Habit %q snoozed until %s.", habit, until.Format("2006-01-02 15:04")), nil
+		},
+	)
+
+	// habit_remove
+	executor.Register(
+		MakeToolDefinition("habit_remove", "Stop tracking a habit and remove its scheduled check-in. Only use when the user explicitly asks.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"habit": map[string]any{
+					"type":        "string",
+					"description": "Habit name or ID",
+				},
+				"confirm": map[string]any{
+					"type":        "boolean",
+					"description": "Set to true to confirm removal. Required for safety.",
+				},
+			},
+			"required": []string{"habit", "confirm"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			habit, _ := args["habit"].(string)
+			confirm, _ := args["confirm"].(bool)
+			if !confirm {
+				return nil, fmt.Errorf("removal not confirmed. Set confirm=true to remove habit %q", habit)
+			}
+
+			jobID, err := skillDB.RemoveHabit(habit)
+			if err != nil {
+				return nil, err
+			}
+			if sched != nil && jobID != "" {
+				sched.Remove(jobID)
+			}
+			return fmt.Sprintf("Habit %q removed.", habit), nil
+		},
+	)
+
+	// habit_report
+	executor.Register(
+		MakeToolDefinition("habit_report", "Weekly habit progress: check-ins over the last 7 days per habit. Use schedule_weekly=true to install a recurring report every Monday.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"schedule_weekly": map[string]any{
+					"type":        "boolean",
+					"description": "Install a scheduled job that posts the weekly progress report to this chat every Monday",
+				},
+			},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			scheduleWeekly, _ := args["schedule_weekly"].(bool)
+
+			if scheduleWeekly {
+				if sched == nil {
+					return nil, fmt.Errorf("scheduler is not enabled")
+				}
+				dt := DeliveryTargetFromContext(ctx)
+				job := &scheduler.Job{
+					ID:             habitReportJobID,
+					Schedule:       "0 9 * * 1",
+					Type:           "cron",
+					Command:        "Run the habit_report tool and post a short weekly progress summary: streaks, habits going well, and habits that were skipped a lot.",
+					Channel:        dt.Channel,
+					ChatID:         dt.ChatID,
+					Enabled:        true,
+					IsolateSession: true,
+					Announce:       true,
+				}
+				if err := sched.Add(job); err != nil {
+					return nil, fmt.Errorf("scheduling weekly report: %w", err)
+				}
+				return fmt.Sprintf("Weekly habit report scheduled as job %q (Mondays, 09:00). Remove it with cron_remove.", habitReportJobID), nil
+			}
+
+			progress, err := skillDB.WeeklyHabitProgress()
+			if err != nil {
+				return nil, err
+			}
+			if len(progress) == 0 {
+				return "No habits tracked. Add one with habit_add.", nil
+			}
+
+			table := NewTableResult("Habit progress (last 7 days)", []string{"Habit", "Done", "Skipped", "Streak", "Best"})
+			for _, p := range progress {
+				table.AddRow(p.Name, fmt.Sprintf("%d", p.Done), fmt.Sprintf("%d", p.Skipped),
+					fmt.Sprintf("%d", p.Streak), fmt.Sprintf("%d", p.BestStreak))
+			}
+			return table, nil
+		},
+	)
+}
+
+// habitTable renders habits as a table result.
+func habitTable(habits []HabitInfo) *StructuredResult {
+	table := NewTableResult("Habits", []string{"Name", "Schedule", "Streak", "Best", "Last done", "Status"})
+	for _, h := range habits {
+		lastDone := "-"
+		if h.LastDone != "" {
+			lastDone = h.LastDone
+		}
+		status := "active"
+		if h.Snoozed() {
+			until, _ := time.Parse(time.RFC3339, h.SnoozedUntil)
+			status = "snoozed until " + until.Local().Format("02/01 15:04")
+		}
+		table.AddRow(h.Name, h.Schedule, fmt.Sprintf("%d", h.Streak), fmt.Sprintf("%d", h.BestStreak), lastDone, status)
+	}
+	return table
+}
@@ -0,0 +1,204 @@
+// Package copilot – habit_tools_test.go contains unit tests for the habits skill.
+package copilot
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestHabitDB(t *testing.T) *SkillDB {
+	t.Helper()
+	db, err := OpenSkillDatabase(t.TempDir())
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitHabitsTables(); err != nil {
+		t.Fatalf("init habits tables: %v", err)
+	}
+	return db
+}
+
+func TestCreateHabit(t *testing.T) {
+	db := newTestHabitDB(t)
+
+	if _, err := db.CreateHabit("", "Did you?", "", "", ""); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if _, err := db.CreateHabit("workout", "", "", "", ""); err == nil {
+		t.Error("expected error for empty prompt")
+	}
+
+	id, err := db.CreateHabit("Workout", "Did you work out today?", "", "whatsapp", "123")
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+
+	// Duplicate names (case-insensitive) are rejected.
+	if _, err := db.CreateHabit("workout", "Again?", "", "", ""); err == nil {
+		t.Error("expected error for duplicate name")
+	}
+
+	// Lookup by normalized name and by ID, with derived job ID and defaults.
+	h, err := db.GetHabit("WORKOUT")
+	if err != nil {
+		t.Fatalf("get by name: %v", err)
+	}
+	if h.ID != id || h.Name != "workout" {
+		t.Errorf("unexpected habit: %+v", h)
+	}
+	if h.JobID != habitJobPrefix+id {
+		t.Errorf("expected job ID %q, got %q", habitJobPrefix+id, h.JobID)
+	}
+	if h.Schedule != habitDefaultSchedule {
+		t.Errorf("expected default schedule, got %q", h.Schedule)
+	}
+	if _, err := db.GetHabit(id); err != nil {
+		t.Errorf("get by ID: %v", err)
+	}
+	if _, err := db.GetHabit("nope"); err == nil {
+		t.Error("expected error for unknown habit")
+	}
+}
+
+func TestHabitStreaks(t *testing.T) {
+	db := newTestHabitDB(t)
+
+	if _, err := db.CreateHabit("reading", "Did you read?", "", "", ""); err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+
+	if _, err := db.LogHabitCheckIn("reading", "maybe", "", ""); err == nil {
+		t.Error("expected error for invalid status")
+	}
+
+	// Consecutive days grow the streak.
+	for i, date := range []string{"2026-08-10", "2026-08-11", "2026-08-12"} {
+		h, err := db.LogHabitCheckIn("reading", "done", "", date)
+		if err != nil {
+			t.Fatalf("log day %s: %v", date, err)
+		}
+		if h.Streak != i+1 {
+			t.Errorf("day %s: expected streak %d, got %d", date, i+1, h.Streak)
+		}
+	}
+
+	// Same day twice is a no-op.
+	h, err := db.LogHabitCheckIn("reading", "done", "", "2026-08-12")
+	if err != nil {
+		t.Fatalf("duplicate log: %v", err)
+	}
+	if h.Streak != 3 {
+		t.Errorf("expected streak unchanged at 3, got %d", h.Streak)
+	}
+
+	// A gap restarts the streak at 1; best streak is kept.
+	h, err = db.LogHabitCheckIn("reading", "done", "", "2026-08-15")
+	if err != nil {
+		t.Fatalf("log after gap: %v", err)
+	}
+	if h.Streak != 1 || h.BestStreak != 3 {
+		t.Errorf("expected streak 1 / best 3, got %d / %d", h.Streak, h.BestStreak)
+	}
+
+	// Skipping resets the streak.
+	h, err = db.LogHabitCheckIn("reading", "skipped", "", "2026-08-16")
+	if err != nil {
+		t.Fatalf("log skip: %v", err)
+	}
+	if h.Streak != 0 || h.BestStreak != 3 {
+		t.Errorf("expected streak 0 / best 3, got %d / %d", h.Streak, h.BestStreak)
+	}
+}
+
+func TestHabitSnooze(t *testing.T) {
+	db := newTestHabitDB(t)
+
+	if _, err := db.CreateHabit("workout", "Did you?", "", "", ""); err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+
+	if err := db.SnoozeHabit("workout", time.Now().Add(24*time.Hour)); err != nil {
+		t.Fatalf("snooze: %v", err)
+	}
+	h, err := db.GetHabit("workout")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !h.Snoozed() {
+		t.Error("expected habit to be snoozed")
+	}
+
+	// Logging a check-in clears the snooze.
+	h, err = db.LogHabitCheckIn("workout", "done", "", "")
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if h.Snoozed() {
+		t.Error("expected snooze cleared after check-in")
+	}
+
+	// Expired snooze.
+	if err := db.SnoozeHabit("workout", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("snooze: %v", err)
+	}
+	h, _ = db.GetHabit("workout")
+	if h.Snoozed() {
+		t.Error("expected expired snooze to be inactive")
+	}
+}
+
+func TestRemoveHabitAndWeeklyProgress(t *testing.T) {
+	db := newTestHabitDB(t)
+
+	id, err := db.CreateHabit("workout", "Did you?", "", "", "")
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	today := time.Now().Format("2006-01-02")
+	if _, err := db.LogHabitCheckIn("workout", "done", "", today); err != nil {
+		t.Fatalf("log: %v", err)
+	}
+
+	progress, err := db.WeeklyHabitProgress()
+	if err != nil {
+		t.Fatalf("progress: %v", err)
+	}
+	if len(progress) != 1 || progress[0].Done != 1 || progress[0].Streak != 1 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+
+	jobID, err := db.RemoveHabit("workout")
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if jobID != habitJobPrefix+id {
+		t.Errorf("expected job ID %q, got %q", habitJobPrefix+id, jobID)
+	}
+	if _, err := db.GetHabit("workout"); err == nil {
+		t.Error("expected habit to be gone")
+	}
+}
+
+func TestParseSnoozeUntil(t *testing.T) {
+	now := time.Now()
+
+	until, err := parseSnoozeUntil("")
+	if err != nil || until.Before(now.Add(23*time.Hour)) {
+		t.Errorf("default snooze: expected ~1 day, got %v (err: %v)", until, err)
+	}
+	until, err = parseSnoozeUntil("3d")
+	if err != nil || until.Before(now.Add(71*time.Hour)) {
+		t.Errorf("3d snooze: got %v (err: %v)", until, err)
+	}
+	until, err = parseSnoozeUntil("2h")
+	if err != nil || until.Before(now.Add(time.Hour)) || until.After(now.Add(3*time.Hour)) {
+		t.Errorf("2h snooze: got %v (err: %v)", until, err)
+	}
+	if _, err := parseSnoozeUntil("2026-12-01"); err != nil {
+		t.Errorf("date snooze failed: %v", err)
+	}
+	if _, err := parseSnoozeUntil("whenever"); err == nil {
+		t.Error("expected error for invalid snooze")
+	}
+}
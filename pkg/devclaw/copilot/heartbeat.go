@@ -10,9 +10,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+	"github.com/jholhewres/devclaw/pkg/devclaw/notify"
 )
 
 // HeartbeatConfig configures the heartbeat system.
@@ -51,12 +52,27 @@ func DefaultHeartbeatConfig() HeartbeatConfig {
 	}
 }
 
+// maxBeatHistory is how many recent beat outcomes are kept for /heartbeat status.
+const maxBeatHistory = 20
+
+// BeatOutcome records what one heartbeat tick did and why.
+type BeatOutcome struct {
+	At      time.Time
+	Outcome string // "skipped", "ok", "delivered", "notified", "error"
+	Reason  string // skip reason or error detail
+}
+
 // Heartbeat runs periodic checks and proactive behavior.
 type Heartbeat struct {
 	config    HeartbeatConfig
 	assistant *Assistant
 	logger    *slog.Logger
 	cancel    context.CancelFunc
+
+	// mu guards the beat history and last-run tracking below.
+	mu        sync.Mutex
+	beats     []BeatOutcome
+	lastRunAt time.Time // last time the agent turn actually ran
 }
 
 // NewHeartbeat creates a new heartbeat instance.
@@ -116,7 +132,7 @@ func (h *Heartbeat) loop(ctx context.Context, interval time.Duration) {
 	for {
 		select {
 		case <-ticker.C:
-			h.tick(ctx)
+			h.safeTick(ctx)
 		case <-ctx.Done():
 			h.logger.Info("heartbeat stopped")
 			return
@@ -124,25 +140,32 @@ func (h *Heartbeat) loop(ctx context.Context, interval time.Duration) {
 	}
 }
 
+// safeTick runs a tick with panic capture so one bad beat doesn't kill the
+// heartbeat loop for the rest of the process lifetime.
+func (h *Heartbeat) safeTick(ctx context.Context) {
+	if h.assistant != nil {
+		defer h.assistant.recoverPanic("heartbeat")
+	}
+	h.tick(ctx)
+}
+
 // tick performs a single heartbeat check.
 func (h *Heartbeat) tick(ctx context.Context) {
 	now := time.Now()
-	hour := now.Hour()
 
-	// Check if we're in active hours.
-	if hour < h.config.ActiveStart || hour >= h.config.ActiveEnd {
-		h.logger.Debug("heartbeat: outside active hours, skipping")
+	// Conditional gating: skip cheaply (no API call) when there is nothing
+	// a beat could usefully do, recording the reason for /heartbeat status.
+	if reason := h.skipReason(now); reason != "" {
+		h.recordBeat(BeatOutcome{At: now, Outcome: "skipped", Reason: reason})
+		h.logger.Debug("heartbeat skipped", "reason", reason)
 		return
 	}
 
 	h.logger.Debug("heartbeat tick", "time", now.Format("15:04"))
 
-	// Skip the API call when HEARTBEAT.md is absent or empty — nothing to act on.
-	heartbeatFile := filepath.Join(h.config.WorkspaceDir, "HEARTBEAT.md")
-	if content, err := os.ReadFile(heartbeatFile); err != nil || len(strings.TrimSpace(string(content))) == 0 {
-		h.logger.Debug("skipping heartbeat: HEARTBEAT.md is absent or empty")
-		return
-	}
+	h.mu.Lock()
+	h.lastRunAt = now
+	h.mu.Unlock()
 
 	// Build the heartbeat prompt.
 	prompt := h.buildHeartbeatPrompt(now)
@@ -158,6 +181,7 @@ func (h *Heartbeat) tick(ctx context.Context) {
 
 	response, err := agent.Run(turnCtx, systemPrompt, session.RecentHistory(5), prompt)
 	if err != nil {
+		h.recordBeat(BeatOutcome{At: now, Outcome: "error", Reason: err.Error()})
 		h.logger.Error("heartbeat agent turn failed", "error", err)
 		return
 	}
@@ -167,6 +191,7 @@ func (h *Heartbeat) tick(ctx context.Context) {
 	// bloating the transcript over time ("Heartbeat Transcript Pruning").
 	trimmed := strings.TrimSpace(response)
 	if trimmed == "" || strings.EqualFold(trimmed, TokenHeartbeatOK) || strings.EqualFold(trimmed, TokenNoReply) {
+		h.recordBeat(BeatOutcome{At: now, Outcome: "ok", Reason: "nothing to deliver"})
 		h.logger.Debug("heartbeat: nothing to deliver, pruning from transcript")
 		return
 	}
@@ -175,19 +200,82 @@ func (h *Heartbeat) tick(ctx context.Context) {
 	session.AddMessage(prompt, response)
 
 	// Deliver proactive message to configured channel.
+	delivered := false
 	if h.config.Channel != "" && h.config.ChatID != "" {
 		// Strip internal tags before sending to user
 		cleanResponse := StripInternalTags(response)
-		outMsg := &channels.OutgoingMessage{Content: cleanResponse}
-		if err := h.assistant.channelMgr.Send(ctx, h.config.Channel, h.config.ChatID, outMsg); err != nil {
+		if err := h.assistant.sendProactive(ctx, h.config.Channel, h.config.ChatID, cleanResponse, false); err != nil {
 			h.logger.Error("heartbeat: failed to deliver message", "error", err)
 		} else {
+			delivered = true
 			h.logger.Info("heartbeat: proactive message delivered",
 				"channel", h.config.Channel,
 				"response_len", len(response),
 			)
 		}
 	}
+
+	// Fall back to push notification when no chat delivery happened —
+	// the user isn't in an active chat but the heartbeat found something.
+	if !delivered {
+		h.assistant.notifier.Notify(ctx, &notify.Alert{
+			Event:   "heartbeat",
+			Title:   "DevClaw heartbeat",
+			Message: StripInternalTags(response),
+		})
+		h.recordBeat(BeatOutcome{At: now, Outcome: "notified"})
+		return
+	}
+	h.recordBeat(BeatOutcome{At: now, Outcome: "delivered"})
+}
+
+// skipReason returns why this tick should be skipped, or empty string to run.
+func (h *Heartbeat) skipReason(now time.Time) string {
+	hour := now.Hour()
+	if hour < h.config.ActiveStart || hour >= h.config.ActiveEnd {
+		return "outside active hours"
+	}
+
+	// Don't compete with an active run — the agent is already busy, and a
+	// proactive turn would just queue behind it.
+	if h.assistant != nil && h.assistant.hasActiveRuns() {
+		return "agent run active"
+	}
+
+	// Nothing to act on without a checklist.
+	heartbeatFile := filepath.Join(h.config.WorkspaceDir, "HEARTBEAT.md")
+	if content, err := os.ReadFile(heartbeatFile); err != nil || len(strings.TrimSpace(string(content))) == 0 {
+		return "HEARTBEAT.md absent or empty"
+	}
+
+	// Idle sessions since the last beat mean nothing new to react to.
+	h.mu.Lock()
+	lastRun := h.lastRunAt
+	h.mu.Unlock()
+	if h.assistant != nil && !lastRun.IsZero() && !h.assistant.hasActivitySince(lastRun) {
+		return "no new activity since last beat"
+	}
+
+	return ""
+}
+
+// recordBeat appends one outcome to the bounded beat history.
+func (h *Heartbeat) recordBeat(beat BeatOutcome) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beats = append(h.beats, beat)
+	if len(h.beats) > maxBeatHistory {
+		h.beats = h.beats[len(h.beats)-maxBeatHistory:]
+	}
+}
+
+// RecentBeats returns the recorded beat outcomes, newest last.
+func (h *Heartbeat) RecentBeats() []BeatOutcome {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]BeatOutcome, len(h.beats))
+	copy(out, h.beats)
+	return out
 }
 
 // buildHeartbeatPrompt builds the prompt for a heartbeat turn.
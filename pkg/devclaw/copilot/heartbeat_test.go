@@ -0,0 +1,86 @@
+package copilot
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testHeartbeat(t *testing.T, withChecklist bool) *Heartbeat {
+	t.Helper()
+	dir := t.TempDir()
+	if withChecklist {
+		if err := os.WriteFile(filepath.Join(dir, "HEARTBEAT.md"), []byte("- check reminders"), 0600); err != nil {
+			t.Fatalf("write checklist: %v", err)
+		}
+	}
+	cfg := DefaultHeartbeatConfig()
+	cfg.ActiveStart = 0
+	cfg.ActiveEnd = 24
+	cfg.WorkspaceDir = dir
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return NewHeartbeat(cfg, nil, logger)
+}
+
+func TestHeartbeatSkipReason(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("outside active hours", func(t *testing.T) {
+		h := testHeartbeat(t, true)
+		h.config.ActiveStart = 9
+		h.config.ActiveEnd = 22
+		night := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+		if got := h.skipReason(night); got != "outside active hours" {
+			t.Errorf("skipReason = %q", got)
+		}
+	})
+
+	t.Run("missing checklist", func(t *testing.T) {
+		h := testHeartbeat(t, false)
+		if got := h.skipReason(now); got != "HEARTBEAT.md absent or empty" {
+			t.Errorf("skipReason = %q", got)
+		}
+	})
+
+	t.Run("runs with checklist present", func(t *testing.T) {
+		h := testHeartbeat(t, true)
+		if got := h.skipReason(now); got != "" {
+			t.Errorf("expected no skip, got %q", got)
+		}
+	})
+
+	t.Run("no new activity since last beat", func(t *testing.T) {
+		h := testHeartbeat(t, true)
+		h.assistant = &Assistant{sessionStore: NewSessionStore(slog.New(slog.NewTextHandler(os.Stdout, nil)))}
+		h.lastRunAt = now.Add(-time.Hour)
+		if got := h.skipReason(now); got != "no new activity since last beat" {
+			t.Errorf("skipReason = %q", got)
+		}
+
+		// A session touched after the last beat clears the skip.
+		h.assistant.sessionStore.GetOrCreate("whatsapp", "123")
+		if got := h.skipReason(now); got != "" {
+			t.Errorf("expected no skip after activity, got %q", got)
+		}
+	})
+}
+
+func TestHeartbeatBeatHistory(t *testing.T) {
+	h := testHeartbeat(t, true)
+
+	for i := 0; i < maxBeatHistory+5; i++ {
+		h.recordBeat(BeatOutcome{At: time.Now(), Outcome: "skipped", Reason: fmt.Sprintf("r%d", i)})
+	}
+
+	beats := h.RecentBeats()
+	if len(beats) != maxBeatHistory {
+		t.Fatalf("expected %d beats, got %d", maxBeatHistory, len(beats))
+	}
+	// Oldest entries were dropped; the last recorded beat is kept.
+	if beats[len(beats)-1].Reason != fmt.Sprintf("r%d", maxBeatHistory+4) {
+		t.Errorf("unexpected newest beat: %+v", beats[len(beats)-1])
+	}
+}
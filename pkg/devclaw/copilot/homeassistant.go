@@ -0,0 +1,237 @@
+// Package copilot – homeassistant.go integrates Home Assistant: entity
+// state queries and service calls over its REST API, plus MQTT event
+// subscriptions that inject smart-home events ("motion detected at front
+// door") into a designated session as agent prompts.
+package copilot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"log/slog"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// HomeAssistantConfig configures the Home Assistant integration.
+type HomeAssistantConfig struct {
+	// Enabled toggles the integration.
+	Enabled bool `yaml:"enabled"`
+
+	// BaseURL is the Home Assistant instance URL (e.g. http://homeassistant.local:8123).
+	BaseURL string `yaml:"base_url"`
+
+	// Token is a long-lived access token for the REST API.
+	Token string `yaml:"token"`
+
+	// MQTT configures the event subscription via an MQTT broker.
+	MQTT HAMQTTConfig `yaml:"mqtt"`
+
+	// EventChannel and EventChatID designate the session that receives
+	// MQTT events as agent prompts.
+	EventChannel string `yaml:"event_channel"`
+	EventChatID  string `yaml:"event_chat_id"`
+}
+
+// HAMQTTConfig configures the MQTT client for Home Assistant events.
+type HAMQTTConfig struct {
+	// Broker is the MQTT broker URL (e.g. tcp://homeassistant.local:1883).
+	Broker string `yaml:"broker"`
+
+	// Username and Password authenticate against the broker.
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// Topics lists the topics to subscribe to (MQTT wildcards supported,
+	// e.g. "homeassistant/motion/#").
+	Topics []string `yaml:"topics"`
+}
+
+// HAClient talks to a Home Assistant instance over REST and MQTT.
+type HAClient struct {
+	cfg    HomeAssistantConfig
+	client *http.Client
+	logger *slog.Logger
+
+	mqttClient mqtt.Client
+}
+
+// NewHomeAssistantClient creates a Home Assistant client.
+func NewHomeAssistantClient(cfg HomeAssistantConfig, logger *slog.Logger) *HAClient {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &HAClient{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 15 * time.Second},
+		logger: logger.With("component", "homeassistant"),
+	}
+}
+
+// GetState fetches the state of an entity (e.g. "light.living_room").
+func (h *HAClient) GetState(ctx context.Context, entityID string) (string, error) {
+	body, err := h.request(ctx, http.MethodGet, "/api/states/"+entityID, nil)
+	if err != nil {
+		return "", err
+	}
+	return body, nil
+}
+
+// CallService invokes a Home Assistant service (e.g. domain "light",
+// service "turn_on") with optional service data.
+func (h *HAClient) CallService(ctx context.Context, domain, service string, data map[string]any) (string, error) {
+	var payload []byte
+	if len(data) > 0 {
+		var err error
+		payload, err = json.Marshal(data)
+		if err != nil {
+			return "", fmt.Errorf("encoding service data: %w", err)
+		}
+	}
+	return h.request(ctx, http.MethodPost, fmt.Sprintf("/api/services/%s/%s", domain, service), payload)
+}
+
+// request performs an authenticated REST call against the instance.
+func (h *HAClient) request(ctx context.Context, method, path string, body []byte) (string, error) {
+	url := strings.TrimRight(h.cfg.BaseURL, "/") + path
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+h.cfg.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("home assistant request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	result := strings.TrimSpace(string(respBody))
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("home assistant returned %d: %s", resp.StatusCode, truncate(result, 300))
+	}
+	return result, nil
+}
+
+// StartMQTT connects to the broker and subscribes to the configured topics.
+// Each message is delivered through onEvent as a short event description.
+func (h *HAClient) StartMQTT(onEvent func(content string)) error {
+	if h.cfg.MQTT.Broker == "" || len(h.cfg.MQTT.Topics) == 0 {
+		return nil // MQTT not configured — REST tools still work.
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(h.cfg.MQTT.Broker).
+		SetClientID("devclaw").
+		SetAutoReconnect(true).
+		SetConnectTimeout(10 * time.Second)
+	if h.cfg.MQTT.Username != "" {
+		opts.SetUsername(h.cfg.MQTT.Username)
+		opts.SetPassword(h.cfg.MQTT.Password)
+	}
+	opts.SetOnConnectHandler(func(c mqtt.Client) {
+		for _, topic := range h.cfg.MQTT.Topics {
+			topic := topic
+			token := c.Subscribe(topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+				payload := truncate(strings.TrimSpace(string(msg.Payload())), 500)
+				h.logger.Debug("mqtt event", "topic", msg.Topic())
+				onEvent(fmt.Sprintf("[homeassistant] %s: %s", msg.Topic(), payload))
+			})
+			if token.Wait() && token.Error() != nil {
+				h.logger.Error("mqtt subscribe failed", "topic", topic, "error", token.Error())
+			}
+		}
+	})
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		h.logger.Warn("mqtt connection lost", "error", err)
+	})
+
+	client := mqtt.NewClient(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("connecting to mqtt broker: %w", token.Error())
+	}
+	h.mqttClient = client
+	h.logger.Info("mqtt connected", "broker", h.cfg.MQTT.Broker, "topics", len(h.cfg.MQTT.Topics))
+	return nil
+}
+
+// Stop disconnects the MQTT client.
+func (h *HAClient) Stop() {
+	if h.mqttClient != nil && h.mqttClient.IsConnected() {
+		h.mqttClient.Disconnect(250)
+	}
+}
+
+// ---------- Tool Registration ----------
+
+// RegisterHomeAssistantTools registers entity and service tools in the executor.
+func RegisterHomeAssistantTools(executor *ToolExecutor, ha *HAClient) {
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "ha_get_state",
+			Description: "Get the current state of a Home Assistant entity (lights, sensors, switches, climate). Returns state and attributes as JSON.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"entity_id": map[string]any{"type": "string", "description": "Entity ID (e.g. 'light.living_room', 'sensor.front_door_motion')"},
+				},
+				"required": []string{"entity_id"},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		entityID, _ := args["entity_id"].(string)
+		if entityID == "" {
+			return nil, fmt.Errorf("entity_id is required")
+		}
+		return ha.GetState(ctx, entityID)
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "ha_call_service",
+			Description: "Call a Home Assistant service to control devices (e.g. domain 'light' service 'turn_on', domain 'climate' service 'set_temperature'). Pass the target entity in data.entity_id.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"domain":  map[string]any{"type": "string", "description": "Service domain (e.g. 'light', 'switch', 'climate')"},
+					"service": map[string]any{"type": "string", "description": "Service name (e.g. 'turn_on', 'turn_off', 'toggle')"},
+					"data":    map[string]any{"type": "object", "description": "Service data including entity_id (e.g. {\"entity_id\": \"light.living_room\", \"brightness\": 128})"},
+				},
+				"required": []string{"domain", "service"},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		domain, _ := args["domain"].(string)
+		service, _ := args["service"].(string)
+		data, _ := args["data"].(map[string]any)
+		if domain == "" || service == "" {
+			return nil, fmt.Errorf("domain and service are required")
+		}
+		result, err := ha.CallService(ctx, domain, service, data)
+		if err != nil {
+			return nil, err
+		}
+		if result == "" || result == "[]" {
+			return fmt.Sprintf("Service %s.%s called.", domain, service), nil
+		}
+		return result, nil
+	})
+}
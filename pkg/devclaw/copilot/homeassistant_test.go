@@ -0,0 +1,74 @@
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHAClientGetState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/states/light.living_room" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("missing bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"entity_id":"light.living_room","state":"on"}`))
+	}))
+	defer srv.Close()
+
+	ha := NewHomeAssistantClient(HomeAssistantConfig{BaseURL: srv.URL, Token: "test-token"}, nil)
+	state, err := ha.GetState(context.Background(), "light.living_room")
+	if err != nil {
+		t.Fatalf("GetState failed: %v", err)
+	}
+	if !strings.Contains(state, `"state":"on"`) {
+		t.Errorf("unexpected state payload: %s", state)
+	}
+}
+
+func TestHAClientCallService(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/services/light/turn_on" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		var data map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			t.Errorf("decoding service data: %v", err)
+		}
+		if data["entity_id"] != "light.living_room" {
+			t.Errorf("unexpected entity_id: %v", data["entity_id"])
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	ha := NewHomeAssistantClient(HomeAssistantConfig{BaseURL: srv.URL, Token: "t"}, nil)
+	result, err := ha.CallService(context.Background(), "light", "turn_on",
+		map[string]any{"entity_id": "light.living_room"})
+	if err != nil {
+		t.Fatalf("CallService failed: %v", err)
+	}
+	if result != "[]" {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestHAClientErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	ha := NewHomeAssistantClient(HomeAssistantConfig{BaseURL: srv.URL, Token: "bad"}, nil)
+	if _, err := ha.GetState(context.Background(), "light.x"); err == nil {
+		t.Fatal("expected error for 401 response")
+	}
+}
@@ -0,0 +1,252 @@
+// Package copilot – identity.go implements cross-channel identity linking.
+// The same person on WhatsApp and the WebUI is two unrelated JIDs today;
+// linking maps multiple channel identities to one profile so access level,
+// workspace (and with it memory and preferences) follow the person across
+// channels. Links are created with a short-lived pairing code issued via
+// /link, or directly by an admin.
+package copilot
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// linkCodeTTL is how long a /link pairing code stays redeemable.
+const linkCodeTTL = 10 * time.Minute
+
+// IdentityLink maps one channel JID to a person's identity.
+type IdentityLink struct {
+	JID        string    `json:"jid"`
+	IdentityID string    `json:"identity_id"`
+	Channel    string    `json:"channel"`
+	LinkedBy   string    `json:"linked_by"`
+	LinkedAt   time.Time `json:"linked_at"`
+}
+
+// pendingLinkCode is an issued, not-yet-redeemed pairing code.
+type pendingLinkCode struct {
+	jid       string
+	channel   string
+	expiresAt time.Time
+}
+
+// IdentityManager maintains the JID → identity mapping. Links persist in
+// devclaw.db; pairing codes are in-memory only (they expire in minutes).
+type IdentityManager struct {
+	db     *sql.DB
+	logger *slog.Logger
+
+	mu      sync.RWMutex
+	links   map[string]string          // normalized JID → identity ID
+	members map[string][]string        // identity ID → normalized JIDs
+	codes   map[string]pendingLinkCode // pairing code → issuer
+}
+
+// NewIdentityManager creates an identity manager. db may be nil (links then
+// live in memory only).
+func NewIdentityManager(db *sql.DB, logger *slog.Logger) *IdentityManager {
+	return &IdentityManager{
+		db:      db,
+		logger:  logger.With("component", "identity"),
+		links:   make(map[string]string),
+		members: make(map[string][]string),
+		codes:   make(map[string]pendingLinkCode),
+	}
+}
+
+// Load restores persisted links from the database on startup.
+func (im *IdentityManager) Load() error {
+	if im.db == nil {
+		return nil
+	}
+	rows, err := im.db.Query(`SELECT jid, identity_id FROM identity_links`)
+	if err != nil {
+		return fmt.Errorf("load identity links: %w", err)
+	}
+	defer rows.Close()
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	count := 0
+	for rows.Next() {
+		var jid, identityID string
+		if err := rows.Scan(&jid, &identityID); err != nil {
+			im.logger.Warn("failed to scan identity link", "error", err)
+			continue
+		}
+		im.links[jid] = identityID
+		im.members[identityID] = append(im.members[identityID], jid)
+		count++
+	}
+	if count > 0 {
+		im.logger.Info("identity links loaded", "links", count, "identities", len(im.members))
+	}
+	return nil
+}
+
+// GenerateLinkCode issues a short-lived pairing code for the given JID.
+// Sending the code from another channel links both JIDs to one identity.
+func (im *IdentityManager) GenerateLinkCode(jid, channel string) (string, error) {
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate link code: %w", err)
+	}
+	code := strings.ToUpper(hex.EncodeToString(buf))
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	// Drop expired codes while we're here; the map stays tiny.
+	now := time.Now()
+	for c, pending := range im.codes {
+		if now.After(pending.expiresAt) {
+			delete(im.codes, c)
+		}
+	}
+	im.codes[code] = pendingLinkCode{
+		jid:       normalizeJID(jid),
+		channel:   channel,
+		expiresAt: now.Add(linkCodeTTL),
+	}
+	return code, nil
+}
+
+// RedeemLinkCode links the redeeming JID to the code issuer's identity.
+func (im *IdentityManager) RedeemLinkCode(code, jid, channel string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	im.mu.Lock()
+	pending, ok := im.codes[code]
+	if ok {
+		delete(im.codes, code)
+	}
+	im.mu.Unlock()
+
+	if !ok || time.Now().After(pending.expiresAt) {
+		return fmt.Errorf("link code invalid or expired")
+	}
+	if normalizeJID(jid) == pending.jid {
+		return fmt.Errorf("cannot link an identity to itself")
+	}
+	return im.link(pending.jid, pending.channel, jid, channel, pending.jid)
+}
+
+// LinkJIDs joins two JIDs into one identity (admin path).
+func (im *IdentityManager) LinkJIDs(jidA, jidB, linkedBy string) error {
+	if normalizeJID(jidA) == normalizeJID(jidB) {
+		return fmt.Errorf("cannot link an identity to itself")
+	}
+	return im.link(jidA, "", jidB, "", linkedBy)
+}
+
+// Unlink removes one JID from its identity.
+func (im *IdentityManager) Unlink(jid string) error {
+	norm := normalizeJID(jid)
+
+	im.mu.Lock()
+	identityID, ok := im.links[norm]
+	if ok {
+		delete(im.links, norm)
+		kept := im.members[identityID][:0]
+		for _, member := range im.members[identityID] {
+			if member != norm {
+				kept = append(kept, member)
+			}
+		}
+		if len(kept) == 0 {
+			delete(im.members, identityID)
+		} else {
+			im.members[identityID] = kept
+		}
+	}
+	im.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("jid %q is not linked", jid)
+	}
+	if im.db != nil {
+		if _, err := im.db.Exec(`DELETE FROM identity_links WHERE jid = ?`, norm); err != nil {
+			return fmt.Errorf("remove identity link: %w", err)
+		}
+	}
+	im.logger.Info("identity unlinked", "jid", norm, "identity", identityID)
+	return nil
+}
+
+// LinkedJIDs returns all JIDs sharing the given JID's identity, including
+// itself. Unlinked JIDs return just themselves, so callers can always range
+// over the result.
+func (im *IdentityManager) LinkedJIDs(jid string) []string {
+	norm := normalizeJID(jid)
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	identityID, ok := im.links[norm]
+	if !ok {
+		return []string{norm}
+	}
+	out := make([]string, len(im.members[identityID]))
+	copy(out, im.members[identityID])
+	return out
+}
+
+// IdentityID returns the identity a JID belongs to, or "" when unlinked.
+func (im *IdentityManager) IdentityID(jid string) string {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+	return im.links[normalizeJID(jid)]
+}
+
+// link joins two JIDs under one identity, merging existing identities when
+// either side is already linked.
+func (im *IdentityManager) link(jidA, channelA, jidB, channelB, linkedBy string) error {
+	normA, normB := normalizeJID(jidA), normalizeJID(jidB)
+
+	im.mu.Lock()
+	identityID := im.links[normA]
+	if identityID == "" {
+		identityID = im.links[normB]
+	}
+	if identityID == "" {
+		identityID = uuid.New().String()
+	}
+
+	// Merge: every JID of both sides ends up under the surviving identity.
+	toLink := map[string]string{normA: channelA, normB: channelB}
+	for _, norm := range []string{normA, normB} {
+		if existing, ok := im.links[norm]; ok && existing != identityID {
+			for _, member := range im.members[existing] {
+				toLink[member] = ""
+			}
+			delete(im.members, existing)
+		}
+	}
+	for norm := range toLink {
+		if im.links[norm] != identityID {
+			im.links[norm] = identityID
+			im.members[identityID] = append(im.members[identityID], norm)
+		}
+	}
+	im.mu.Unlock()
+
+	if im.db != nil {
+		now := time.Now().UTC().Format(time.RFC3339)
+		for norm, channel := range toLink {
+			if _, err := im.db.Exec(`INSERT OR REPLACE INTO identity_links (jid, identity_id, channel, linked_by, linked_at)
+				VALUES (?, ?, ?, ?, ?)`, norm, identityID, channel, linkedBy, now); err != nil {
+				return fmt.Errorf("persist identity link: %w", err)
+			}
+		}
+	}
+
+	im.logger.Info("identities linked",
+		"identity", identityID, "jid_a", normA, "jid_b", normB, "linked_by", linkedBy)
+	return nil
+}
@@ -0,0 +1,118 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testIdentityManager(t *testing.T) *IdentityManager {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return NewIdentityManager(nil, logger)
+}
+
+func TestIdentityLinkCodeFlow(t *testing.T) {
+	im := testIdentityManager(t)
+
+	code, err := im.GenerateLinkCode("5511999@s.whatsapp.net", "whatsapp")
+	if err != nil {
+		t.Fatalf("GenerateLinkCode: %v", err)
+	}
+	if err := im.RedeemLinkCode(code, "user-42", "webui"); err != nil {
+		t.Fatalf("RedeemLinkCode: %v", err)
+	}
+
+	linked := im.LinkedJIDs("user-42")
+	if len(linked) != 2 {
+		t.Fatalf("expected 2 linked JIDs, got %v", linked)
+	}
+	if im.IdentityID("5511999@s.whatsapp.net") != im.IdentityID("user-42") {
+		t.Error("both JIDs should share one identity")
+	}
+
+	// Codes are single-use.
+	if err := im.RedeemLinkCode(code, "user-43", "webui"); err == nil {
+		t.Error("redeemed code should not be reusable")
+	}
+}
+
+func TestIdentityLinkMerge(t *testing.T) {
+	im := testIdentityManager(t)
+
+	if err := im.LinkJIDs("a@s.whatsapp.net", "b-webui", "owner"); err != nil {
+		t.Fatalf("LinkJIDs: %v", err)
+	}
+	if err := im.LinkJIDs("c-discord", "d-telegram", "owner"); err != nil {
+		t.Fatalf("LinkJIDs: %v", err)
+	}
+	// Linking across two identities merges them into one.
+	if err := im.LinkJIDs("b-webui", "c-discord", "owner"); err != nil {
+		t.Fatalf("LinkJIDs merge: %v", err)
+	}
+	if got := len(im.LinkedJIDs("a@s.whatsapp.net")); got != 4 {
+		t.Errorf("expected 4 JIDs after merge, got %d", got)
+	}
+
+	// Unlink removes one JID, the rest stay together.
+	if err := im.Unlink("d-telegram"); err != nil {
+		t.Fatalf("Unlink: %v", err)
+	}
+	if got := len(im.LinkedJIDs("a@s.whatsapp.net")); got != 3 {
+		t.Errorf("expected 3 JIDs after unlink, got %d", got)
+	}
+	if got := im.LinkedJIDs("d-telegram"); len(got) != 1 {
+		t.Errorf("unlinked JID should stand alone, got %v", got)
+	}
+
+	// Self-links are rejected.
+	if err := im.LinkJIDs("a@s.whatsapp.net", "a@s.whatsapp.net", "owner"); err == nil {
+		t.Error("expected error linking a JID to itself")
+	}
+}
+
+func TestIdentityPersistence(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	im := NewIdentityManager(db, logger)
+	if err := im.LinkJIDs("a@s.whatsapp.net", "b-webui", "owner"); err != nil {
+		t.Fatalf("LinkJIDs: %v", err)
+	}
+
+	im2 := NewIdentityManager(db, logger)
+	if err := im2.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := len(im2.LinkedJIDs("b-webui")); got != 2 {
+		t.Errorf("expected links to survive restart, got %d JIDs", got)
+	}
+}
+
+func TestAccessLevelFollowsIdentity(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := DefaultAccessConfig()
+	cfg.Admins = []string{"5511999@s.whatsapp.net"}
+	am := NewAccessManager(cfg, logger)
+
+	im := testIdentityManager(t)
+	if err := im.LinkJIDs("5511999@s.whatsapp.net", "user-42", "owner"); err != nil {
+		t.Fatalf("LinkJIDs: %v", err)
+	}
+	am.SetIdentityResolver(im.LinkedJIDs)
+
+	if got := am.GetLevel("user-42"); got != AccessAdmin {
+		t.Errorf("linked JID should inherit admin level, got %s", got)
+	}
+
+	// A block on one linked JID blocks the person everywhere.
+	am.Block("user-42", "owner")
+	if got := am.GetLevel("5511999@s.whatsapp.net"); got != AccessBlocked {
+		t.Errorf("block should follow the identity, got %s", got)
+	}
+}
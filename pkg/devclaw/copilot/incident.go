@@ -0,0 +1,414 @@
+// Package copilot – incident.go implements the incident subsystem:
+// /incident open creates a tracked incident with a dedicated session,
+// pages an escalation chain with acknowledgment tracking, pins relevant
+// runbooks from memory, and keeps a timeline that can be exported as a
+// postmortem draft. Incident pages are urgent and bypass do-not-disturb.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// Incident statuses.
+const (
+	IncidentOpen         = "open"
+	IncidentAcknowledged = "acknowledged"
+	IncidentResolved     = "resolved"
+)
+
+// incidentDefaultAckTimeout is how long each escalation target has to
+// acknowledge before the next one in the chain is paged.
+const incidentDefaultAckTimeout = 5 * time.Minute
+
+// EscalationTarget is one step of the paging chain.
+type EscalationTarget struct {
+	// Name is a human-readable label (e.g. "on-call primary").
+	Name string `yaml:"name"`
+	// Channel is the messaging channel to page through.
+	Channel string `yaml:"channel"`
+	// ChatID is the chat or user to page.
+	ChatID string `yaml:"chat_id"`
+}
+
+// IncidentConfig configures the incident subsystem.
+type IncidentConfig struct {
+	// Escalation is the ordered paging chain. The first target is paged
+	// immediately; later targets are paged when earlier ones don't
+	// acknowledge within AckTimeout.
+	Escalation []EscalationTarget `yaml:"escalation"`
+
+	// AckTimeout is how long each target has to acknowledge (default: 5m).
+	AckTimeout time.Duration `yaml:"ack_timeout"`
+}
+
+// IncidentEvent is one timeline entry.
+type IncidentEvent struct {
+	At    time.Time `json:"at"`
+	Actor string    `json:"actor"`
+	Note  string    `json:"note"`
+}
+
+// Incident tracks one open incident.
+type Incident struct {
+	ID         string          `json:"id"`
+	Title      string          `json:"title"`
+	Status     string          `json:"status"`
+	OpenedBy   string          `json:"opened_by"`
+	OpenedAt   time.Time       `json:"opened_at"`
+	AckedBy    string          `json:"acked_by,omitempty"`
+	AckedAt    time.Time       `json:"acked_at,omitempty"`
+	ResolvedAt time.Time       `json:"resolved_at,omitempty"`
+	SessionID  string          `json:"session_id"`
+	Runbooks   []string        `json:"runbooks,omitempty"`
+	Timeline   []IncidentEvent `json:"timeline"`
+}
+
+// IncidentManager tracks incidents and runs the escalation chain.
+type IncidentManager struct {
+	config IncidentConfig
+	logger *slog.Logger
+
+	// pageFunc pages one escalation target (wired to urgent sendProactive).
+	pageFunc func(target EscalationTarget, incident *Incident)
+
+	// mu guards incidents and counter.
+	mu        sync.Mutex
+	incidents map[string]*Incident
+	counter   int
+}
+
+// NewIncidentManager creates an incident manager.
+func NewIncidentManager(cfg IncidentConfig, logger *slog.Logger) *IncidentManager {
+	if cfg.AckTimeout <= 0 {
+		cfg.AckTimeout = incidentDefaultAckTimeout
+	}
+	return &IncidentManager{
+		config:    cfg,
+		logger:    logger.With("component", "incidents"),
+		incidents: make(map[string]*Incident),
+	}
+}
+
+// SetPageFunc sets the function used to page escalation targets.
+func (im *IncidentManager) SetPageFunc(fn func(target EscalationTarget, incident *Incident)) {
+	im.pageFunc = fn
+}
+
+// Open creates a new incident and starts the escalation chain.
+func (im *IncidentManager) Open(ctx context.Context, title, openedBy, sessionID string) *Incident {
+	im.mu.Lock()
+	im.counter++
+	inc := &Incident{
+		ID:        fmt.Sprintf("inc-%d", im.counter),
+		Title:     title,
+		Status:    IncidentOpen,
+		OpenedBy:  openedBy,
+		OpenedAt:  time.Now(),
+		SessionID: sessionID,
+	}
+	inc.Timeline = append(inc.Timeline, IncidentEvent{
+		At: inc.OpenedAt, Actor: openedBy, Note: "incident opened: " + title,
+	})
+	im.incidents[inc.ID] = inc
+	im.mu.Unlock()
+
+	im.logger.Info("incident opened", "id", inc.ID, "title", title, "by", openedBy)
+
+	go im.escalate(ctx, inc.ID)
+	return inc
+}
+
+// statusOf returns an incident's current status under lock.
+func (im *IncidentManager) statusOf(incidentID string) (string, bool) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	inc, ok := im.incidents[incidentID]
+	if !ok {
+		return "", false
+	}
+	return inc.Status, true
+}
+
+// escalate pages the chain in order until someone acknowledges.
+func (im *IncidentManager) escalate(ctx context.Context, incidentID string) {
+	for i, target := range im.config.Escalation {
+		status, ok := im.statusOf(incidentID)
+		if !ok || status != IncidentOpen {
+			return
+		}
+
+		if im.pageFunc != nil {
+			inc, _ := im.Get(incidentID)
+			im.pageFunc(target, inc)
+		}
+		im.AddNote(incidentID, "system",
+			fmt.Sprintf("paged %s (step %d of %d)", target.Name, i+1, len(im.config.Escalation)))
+
+		select {
+		case <-time.After(im.config.AckTimeout):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	if status, ok := im.statusOf(incidentID); ok && status == IncidentOpen {
+		im.AddNote(incidentID, "system", "escalation chain exhausted without acknowledgment")
+		im.logger.Warn("incident unacknowledged after full escalation", "id", incidentID)
+	}
+}
+
+// Ack marks an incident as acknowledged, stopping further escalation.
+func (im *IncidentManager) Ack(incidentID, by string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	inc, ok := im.incidents[incidentID]
+	if !ok {
+		return fmt.Errorf("incident %s not found", incidentID)
+	}
+	if inc.Status != IncidentOpen {
+		return fmt.Errorf("incident %s is %s", incidentID, inc.Status)
+	}
+
+	inc.Status = IncidentAcknowledged
+	inc.AckedBy = by
+	inc.AckedAt = time.Now()
+	inc.Timeline = append(inc.Timeline, IncidentEvent{
+		At: inc.AckedAt, Actor: by, Note: "acknowledged",
+	})
+	return nil
+}
+
+// Resolve closes an incident.
+func (im *IncidentManager) Resolve(incidentID, by string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	inc, ok := im.incidents[incidentID]
+	if !ok {
+		return fmt.Errorf("incident %s not found", incidentID)
+	}
+	if inc.Status == IncidentResolved {
+		return fmt.Errorf("incident %s is already resolved", incidentID)
+	}
+
+	inc.Status = IncidentResolved
+	inc.ResolvedAt = time.Now()
+	inc.Timeline = append(inc.Timeline, IncidentEvent{
+		At: inc.ResolvedAt, Actor: by, Note: "resolved",
+	})
+	return nil
+}
+
+// AddNote appends a timeline entry to an incident.
+func (im *IncidentManager) AddNote(incidentID, actor, note string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	inc, ok := im.incidents[incidentID]
+	if !ok {
+		return fmt.Errorf("incident %s not found", incidentID)
+	}
+	inc.Timeline = append(inc.Timeline, IncidentEvent{
+		At: time.Now(), Actor: actor, Note: note,
+	})
+	return nil
+}
+
+// PinRunbooks attaches runbook references to an incident.
+func (im *IncidentManager) PinRunbooks(incidentID string, runbooks []string) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	if inc, ok := im.incidents[incidentID]; ok {
+		inc.Runbooks = runbooks
+	}
+}
+
+// Get returns a copy-safe pointer to an incident.
+func (im *IncidentManager) Get(incidentID string) (*Incident, bool) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	inc, ok := im.incidents[incidentID]
+	return inc, ok
+}
+
+// List returns all incidents, newest first.
+func (im *IncidentManager) List() []*Incident {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	out := make([]*Incident, 0, len(im.incidents))
+	for _, inc := range im.incidents {
+		out = append(out, inc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].OpenedAt.After(out[j].OpenedAt) })
+	return out
+}
+
+// Postmortem renders an incident timeline as a postmortem draft.
+func (im *IncidentManager) Postmortem(incidentID string) (string, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	inc, ok := im.incidents[incidentID]
+	if !ok {
+		return "", fmt.Errorf("incident %s not found", incidentID)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Postmortem draft: %s\n\n", inc.Title))
+	b.WriteString(fmt.Sprintf("- **Incident:** %s\n", inc.ID))
+	b.WriteString(fmt.Sprintf("- **Status:** %s\n", inc.Status))
+	b.WriteString(fmt.Sprintf("- **Opened:** %s by %s\n", inc.OpenedAt.Format(time.RFC3339), inc.OpenedBy))
+	if !inc.AckedAt.IsZero() {
+		b.WriteString(fmt.Sprintf("- **Acknowledged:** %s by %s (after %s)\n",
+			inc.AckedAt.Format(time.RFC3339), inc.AckedBy,
+			inc.AckedAt.Sub(inc.OpenedAt).Round(time.Second)))
+	}
+	if !inc.ResolvedAt.IsZero() {
+		b.WriteString(fmt.Sprintf("- **Resolved:** %s (duration %s)\n",
+			inc.ResolvedAt.Format(time.RFC3339),
+			inc.ResolvedAt.Sub(inc.OpenedAt).Round(time.Second)))
+	}
+	if len(inc.Runbooks) > 0 {
+		b.WriteString("\n## Runbooks\n\n")
+		for _, rb := range inc.Runbooks {
+			b.WriteString("- " + rb + "\n")
+		}
+	}
+	b.WriteString("\n## Timeline\n\n")
+	for _, ev := range inc.Timeline {
+		b.WriteString(fmt.Sprintf("- %s — %s: %s\n",
+			ev.At.Format("15:04:05"), ev.Actor, ev.Note))
+	}
+	b.WriteString("\n## What went well\n\n- (fill in)\n\n## What went wrong\n\n- (fill in)\n\n## Action items\n\n- (fill in)\n")
+	return b.String(), nil
+}
+
+// SetSessionID records the dedicated session backing an incident.
+func (im *IncidentManager) SetSessionID(incidentID, sessionID string) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	if inc, ok := im.incidents[incidentID]; ok {
+		inc.SessionID = sessionID
+	}
+}
+
+const incidentUsage = "Usage: /incident open <title> | ack <id> | note <id> <text> | resolve <id> | status | postmortem <id>"
+
+// incidentCommand handles /incident open|ack|note|resolve|status|postmortem.
+func (a *Assistant) incidentCommand(args []string, msg *channels.IncomingMessage) string {
+	if a.incidentMgr == nil {
+		return "Incident subsystem is not available."
+	}
+	if len(args) == 0 {
+		return incidentUsage
+	}
+
+	actor := msg.FromName
+	if actor == "" {
+		actor = msg.From
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "open":
+		if len(args) < 2 {
+			return "Usage: /incident open <title>"
+		}
+		title := strings.Join(args[1:], " ")
+
+		inc := a.incidentMgr.Open(a.ctx, title, actor, "")
+
+		// Dedicated incident session, isolated from the originating chat.
+		resolved := a.workspaceMgr.Resolve("incident", inc.ID, msg.From, false)
+		a.incidentMgr.SetSessionID(inc.ID, resolved.Session.ID)
+
+		// Pin relevant runbooks from memory.
+		var runbooks []string
+		if a.sqliteMemory != nil {
+			if results, err := a.sqliteMemory.SearchBM25(title, 3); err == nil {
+				for _, r := range results {
+					runbooks = append(runbooks, r.FileID)
+				}
+			}
+		}
+		if len(runbooks) > 0 {
+			a.incidentMgr.PinRunbooks(inc.ID, runbooks)
+			a.incidentMgr.AddNote(inc.ID, "system",
+				fmt.Sprintf("pinned %d runbook(s) from memory", len(runbooks)))
+		}
+
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("🚨 Incident *%s* opened: %s\n", inc.ID, title))
+		b.WriteString(fmt.Sprintf("Paging %d escalation target(s). Acknowledge with /incident ack %s",
+			len(a.incidentMgr.config.Escalation), inc.ID))
+		if len(runbooks) > 0 {
+			b.WriteString("\n\n📌 Pinned runbooks:")
+			for _, rb := range runbooks {
+				b.WriteString("\n- " + rb)
+			}
+		}
+		return b.String()
+
+	case "ack":
+		if len(args) < 2 {
+			return "Usage: /incident ack <id>"
+		}
+		if err := a.incidentMgr.Ack(args[1], actor); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("✅ Incident %s acknowledged by %s.", args[1], actor)
+
+	case "note":
+		if len(args) < 3 {
+			return "Usage: /incident note <id> <text>"
+		}
+		if err := a.incidentMgr.AddNote(args[1], actor, strings.Join(args[2:], " ")); err != nil {
+			return err.Error()
+		}
+		return "📝 Noted."
+
+	case "resolve":
+		if len(args) < 2 {
+			return "Usage: /incident resolve <id>"
+		}
+		if err := a.incidentMgr.Resolve(args[1], actor); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("✅ Incident %s resolved. Export the timeline with /incident postmortem %s", args[1], args[1])
+
+	case "status":
+		incidents := a.incidentMgr.List()
+		if len(incidents) == 0 {
+			return "No incidents."
+		}
+		var b strings.Builder
+		b.WriteString("*Incidents:*\n")
+		for _, inc := range incidents {
+			b.WriteString(fmt.Sprintf("\n%s [%s] %s (opened %s by %s)",
+				inc.ID, inc.Status, inc.Title,
+				inc.OpenedAt.Format("Jan 2 15:04"), inc.OpenedBy))
+		}
+		return b.String()
+
+	case "postmortem":
+		if len(args) < 2 {
+			return "Usage: /incident postmortem <id>"
+		}
+		draft, err := a.incidentMgr.Postmortem(args[1])
+		if err != nil {
+			return err.Error()
+		}
+		return draft
+
+	default:
+		return incidentUsage
+	}
+}
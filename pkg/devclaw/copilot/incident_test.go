@@ -0,0 +1,150 @@
+package copilot
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testIncidentManager(t *testing.T, cfg IncidentConfig) *IncidentManager {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return NewIncidentManager(cfg, logger)
+}
+
+func TestIncidentLifecycle(t *testing.T) {
+	im := testIncidentManager(t, IncidentConfig{})
+
+	inc := im.Open(context.Background(), "API down", "João", "incident:inc-1")
+	if inc.Status != IncidentOpen {
+		t.Errorf("expected open status, got %s", inc.Status)
+	}
+	if inc.ID != "inc-1" {
+		t.Errorf("expected inc-1, got %s", inc.ID)
+	}
+
+	if err := im.Ack(inc.ID, "Maria"); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+	got, _ := im.Get(inc.ID)
+	if got.Status != IncidentAcknowledged || got.AckedBy != "Maria" {
+		t.Errorf("got status %s acked by %q", got.Status, got.AckedBy)
+	}
+
+	// Double-ack fails.
+	if err := im.Ack(inc.ID, "Carlos"); err == nil {
+		t.Error("expected error on second ack")
+	}
+
+	if err := im.AddNote(inc.ID, "Maria", "restarted the API pods"); err != nil {
+		t.Fatalf("note: %v", err)
+	}
+	if err := im.Resolve(inc.ID, "Maria"); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if err := im.Resolve(inc.ID, "Maria"); err == nil {
+		t.Error("expected error on double resolve")
+	}
+
+	if err := im.Ack("inc-99", "x"); err == nil {
+		t.Error("expected error for unknown incident")
+	}
+}
+
+func TestIncidentEscalationChain(t *testing.T) {
+	im := testIncidentManager(t, IncidentConfig{
+		Escalation: []EscalationTarget{
+			{Name: "primary", Channel: "whatsapp", ChatID: "1"},
+			{Name: "secondary", Channel: "whatsapp", ChatID: "2"},
+		},
+		AckTimeout: 20 * time.Millisecond,
+	})
+
+	var mu sync.Mutex
+	var paged []string
+	im.SetPageFunc(func(target EscalationTarget, inc *Incident) {
+		mu.Lock()
+		paged = append(paged, target.Name)
+		mu.Unlock()
+	})
+
+	im.Open(context.Background(), "DB latency", "João", "")
+
+	// Without an ack, the chain pages both targets.
+	time.Sleep(100 * time.Millisecond)
+	mu.Lock()
+	got := append([]string(nil), paged...)
+	mu.Unlock()
+	if len(got) != 2 || got[0] != "primary" || got[1] != "secondary" {
+		t.Errorf("expected full chain, got %v", got)
+	}
+}
+
+func TestIncidentAckStopsEscalation(t *testing.T) {
+	im := testIncidentManager(t, IncidentConfig{
+		Escalation: []EscalationTarget{
+			{Name: "primary", Channel: "whatsapp", ChatID: "1"},
+			{Name: "secondary", Channel: "whatsapp", ChatID: "2"},
+		},
+		AckTimeout: 50 * time.Millisecond,
+	})
+
+	var mu sync.Mutex
+	var paged []string
+	im.SetPageFunc(func(target EscalationTarget, inc *Incident) {
+		mu.Lock()
+		paged = append(paged, target.Name)
+		mu.Unlock()
+	})
+
+	inc := im.Open(context.Background(), "DB latency", "João", "")
+
+	// Acknowledge before the first timeout expires.
+	time.Sleep(10 * time.Millisecond)
+	if err := im.Ack(inc.ID, "Maria"); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+
+	time.Sleep(120 * time.Millisecond)
+	mu.Lock()
+	got := append([]string(nil), paged...)
+	mu.Unlock()
+	if len(got) != 1 || got[0] != "primary" {
+		t.Errorf("expected escalation to stop after ack, got %v", got)
+	}
+}
+
+func TestIncidentPostmortem(t *testing.T) {
+	im := testIncidentManager(t, IncidentConfig{})
+
+	inc := im.Open(context.Background(), "API down", "João", "")
+	im.PinRunbooks(inc.ID, []string{"runbooks/api-outage.md"})
+	im.AddNote(inc.ID, "João", "rolled back deploy")
+	im.Ack(inc.ID, "Maria")
+	im.Resolve(inc.ID, "Maria")
+
+	draft, err := im.Postmortem(inc.ID)
+	if err != nil {
+		t.Fatalf("postmortem: %v", err)
+	}
+
+	for _, want := range []string{
+		"# Postmortem draft: API down",
+		"runbooks/api-outage.md",
+		"rolled back deploy",
+		"## Timeline",
+		"## Action items",
+	} {
+		if !strings.Contains(draft, want) {
+			t.Errorf("postmortem missing %q:\n%s", want, draft)
+		}
+	}
+
+	if _, err := im.Postmortem("inc-99"); err == nil {
+		t.Error("expected error for unknown incident")
+	}
+}
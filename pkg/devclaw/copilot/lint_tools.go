@@ -0,0 +1,353 @@
+// Package copilot – lint_tools.go wraps the project's linter and formatter
+// (golangci-lint, ruff, eslint, prettier, gofmt) behind tools that return
+// structured diagnostics. Format diffs come back as unified diffs so the
+// agent can land them through the apply_patch flow.
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ---------- Data Types ----------
+
+// lintDiagnostic is one parsed linter finding.
+type lintDiagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Col     int    `json:"col,omitempty"`
+	Linter  string `json:"linter,omitempty"`
+	Message string `json:"message"`
+}
+
+type lintResult struct {
+	Tool        string           `json:"tool"`
+	Command     string           `json:"command"`
+	ExitCode    int              `json:"exit_code"`
+	Diagnostics []lintDiagnostic `json:"diagnostics"`
+	Output      string           `json:"output,omitempty"`
+	Hint        string           `json:"hint,omitempty"`
+}
+
+type formatResult struct {
+	Tool     string `json:"tool"`
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"`
+	Clean    bool   `json:"clean"`
+	Diff     string `json:"diff,omitempty"`
+	Output   string `json:"output,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// ---------- Tool Registration ----------
+
+// RegisterLintTools registers linter and formatter integration tools.
+func RegisterLintTools(executor *ToolExecutor) {
+	// lint_project
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "lint_project",
+			Description: "Run the project's linter (auto-detects golangci-lint, go vet, ruff, eslint) and return structured diagnostics (file, line, message). Set fix to apply the linter's auto-fixes.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{"type": "string", "description": "File or directory to lint (default: whole project)"},
+					"tool": map[string]any{"type": "string", "enum": []string{"golangci-lint", "govet", "ruff", "eslint"}, "description": "Force a specific linter"},
+					"fix":  map[string]any{"type": "boolean", "description": "Apply auto-fixes where the linter supports them"},
+				},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		path, _ := args["path"].(string)
+		tool, _ := args["tool"].(string)
+		fix, _ := args["fix"].(bool)
+
+		if tool == "" {
+			tool = detectLintTool()
+		}
+		cmdStr := buildLintCommand(tool, path, fix)
+		if cmdStr == "" {
+			return nil, fmt.Errorf("no linter available for tool: %s", tool)
+		}
+
+		out, exitCode, err := runToolCommand(cmdStr)
+		if err != nil {
+			return nil, fmt.Errorf("running linter: %w", err)
+		}
+
+		diagnostics := parseLintOutput(tool, out)
+		result := lintResult{
+			Tool:        tool,
+			Command:     cmdStr,
+			ExitCode:    exitCode,
+			Diagnostics: diagnostics,
+		}
+		if len(diagnostics) == 0 && exitCode != 0 {
+			// Linter failed without parseable findings (config error, crash).
+			result.Output = truncateOutput(out, 3000)
+		}
+		if len(diagnostics) > 0 && !fix && lintToolSupportsFix(tool) {
+			result.Hint = "Rerun with fix:true to apply auto-fixes, or fix the findings with apply_patch."
+		}
+
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return string(data), nil
+	})
+
+	// format_files
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "format_files",
+			Description: "Check or apply code formatting (auto-detects gofmt, ruff format, prettier). Without write, returns the formatter's unified diff, ready for apply_patch.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path":  map[string]any{"type": "string", "description": "File or directory to format (default: whole project)"},
+					"tool":  map[string]any{"type": "string", "enum": []string{"gofmt", "ruff", "prettier"}, "description": "Force a specific formatter"},
+					"write": map[string]any{"type": "boolean", "description": "Write changes to disk instead of returning a diff"},
+				},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		path, _ := args["path"].(string)
+		tool, _ := args["tool"].(string)
+		write, _ := args["write"].(bool)
+
+		if tool == "" {
+			tool = detectFormatTool()
+		}
+		cmdStr := buildFormatCommand(tool, path, write)
+		if cmdStr == "" {
+			return nil, fmt.Errorf("no formatter available for tool: %s", tool)
+		}
+
+		out, exitCode, err := runToolCommand(cmdStr)
+		if err != nil {
+			return nil, fmt.Errorf("running formatter: %w", err)
+		}
+
+		result := formatResult{
+			Tool:     tool,
+			Command:  cmdStr,
+			ExitCode: exitCode,
+		}
+		if write {
+			result.Clean = exitCode == 0
+			result.Output = truncateOutput(out, 3000)
+		} else if looksLikeUnifiedDiff(out) {
+			result.Diff = truncateOutput(out, 6000)
+			result.Hint = "Apply this diff with apply_patch, or rerun with write:true."
+		} else {
+			result.Clean = exitCode == 0 && strings.TrimSpace(out) == ""
+			result.Output = truncateOutput(out, 3000)
+			if !result.Clean {
+				result.Hint = "Rerun with write:true to apply formatting."
+			}
+		}
+
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return string(data), nil
+	})
+}
+
+// ---------- Detection & Commands ----------
+
+// detectLintTool picks a linter from project marker files, preferring the
+// dedicated linter over the language baseline.
+func detectLintTool() string {
+	if fileExistsAny(".golangci.yml", ".golangci.yaml", ".golangci.toml") {
+		return "golangci-lint"
+	}
+	if fileExistsAny("ruff.toml", ".ruff.toml") || pyprojectMentions("ruff") {
+		return "ruff"
+	}
+	if fileExistsAny(".eslintrc", ".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", "eslint.config.js", "eslint.config.mjs") {
+		return "eslint"
+	}
+	if fileExistsAny("go.mod") {
+		if _, err := exec.LookPath("golangci-lint"); err == nil {
+			return "golangci-lint"
+		}
+		return "govet"
+	}
+	if fileExistsAny("pyproject.toml", "setup.cfg") {
+		return "ruff"
+	}
+	return "govet"
+}
+
+func buildLintCommand(tool, path string, fix bool) string {
+	switch tool {
+	case "golangci-lint":
+		cmd := "golangci-lint run --out-format line-number"
+		if fix {
+			cmd += " --fix"
+		}
+		if path != "" {
+			cmd += " " + path
+		}
+		return cmd
+	case "govet":
+		pkg := "./..."
+		if path != "" {
+			pkg = path
+		}
+		return "go vet " + pkg
+	case "ruff":
+		cmd := "ruff check --output-format concise"
+		if fix {
+			cmd += " --fix"
+		}
+		if path != "" {
+			cmd += " " + path
+		} else {
+			cmd += " ."
+		}
+		return cmd
+	case "eslint":
+		cmd := "npx eslint --format unix"
+		if fix {
+			cmd += " --fix"
+		}
+		if path != "" {
+			cmd += " " + path
+		} else {
+			cmd += " ."
+		}
+		return cmd
+	}
+	return ""
+}
+
+func lintToolSupportsFix(tool string) bool {
+	return tool == "golangci-lint" || tool == "ruff" || tool == "eslint"
+}
+
+// detectFormatTool picks a formatter from project marker files.
+func detectFormatTool() string {
+	if fileExistsAny("go.mod") {
+		return "gofmt"
+	}
+	if fileExistsAny(".prettierrc", ".prettierrc.json", ".prettierrc.js", "prettier.config.js", "package.json") {
+		return "prettier"
+	}
+	if fileExistsAny("ruff.toml", ".ruff.toml", "pyproject.toml") {
+		return "ruff"
+	}
+	return "gofmt"
+}
+
+func buildFormatCommand(tool, path string, write bool) string {
+	switch tool {
+	case "gofmt":
+		flag := "-d"
+		if write {
+			flag = "-w"
+		}
+		target := "."
+		if path != "" {
+			target = path
+		}
+		return fmt.Sprintf("gofmt %s %s", flag, target)
+	case "ruff":
+		cmd := "ruff format"
+		if !write {
+			cmd += " --diff"
+		}
+		if path != "" {
+			cmd += " " + path
+		} else {
+			cmd += " ."
+		}
+		return cmd
+	case "prettier":
+		mode := "--check"
+		if write {
+			mode = "--write"
+		}
+		target := "."
+		if path != "" {
+			target = path
+		}
+		return fmt.Sprintf("npx prettier %s %s", mode, target)
+	}
+	return ""
+}
+
+// runToolCommand runs a space-separated command and returns its combined
+// output and exit code. Non-zero exits are expected (findings present) and
+// not treated as errors.
+func runToolCommand(cmdStr string) (string, int, error) {
+	parts := strings.Fields(cmdStr)
+	cmd := exec.Command(parts[0], parts[1:]...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return string(out), exitErr.ExitCode(), nil
+		}
+		return "", 0, err
+	}
+	return string(out), 0, nil
+}
+
+// ---------- Output Parsing ----------
+
+// lintLineRe matches the file:line:col: message format shared by go vet,
+// golangci-lint (line-number), ruff (concise), and eslint (unix).
+var lintLineRe = regexp.MustCompile(`^(.+?\.\w+):(\d+)(?::(\d+))?:?\s+(.+)$`)
+
+// lintLinterSuffixRe captures the trailing "(linter)" / "[rule]" annotation
+// golangci-lint and eslint append to each finding.
+var lintLinterSuffixRe = regexp.MustCompile(`\s+[(\[]([\w./@-]+)[)\]]$`)
+
+// parseLintOutput parses per-line linter findings into diagnostics.
+func parseLintOutput(tool, output string) []lintDiagnostic {
+	var diagnostics []lintDiagnostic
+	for _, line := range strings.Split(output, "\n") {
+		m := lintLineRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		d := lintDiagnostic{File: m[1], Message: m[4]}
+		d.Line, _ = strconv.Atoi(m[2])
+		if m[3] != "" {
+			d.Col, _ = strconv.Atoi(m[3])
+		}
+		if s := lintLinterSuffixRe.FindStringSubmatch(d.Message); s != nil {
+			d.Linter = s[1]
+			d.Message = strings.TrimSpace(strings.TrimSuffix(d.Message, s[0]))
+		} else if tool == "govet" {
+			d.Linter = "govet"
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+// ---------- Helpers ----------
+
+func fileExistsAny(names ...string) bool {
+	for _, name := range names {
+		if _, err := os.Stat(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func pyprojectMentions(tool string) bool {
+	content, err := os.ReadFile(filepath.Join(".", "pyproject.toml"))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(content), "[tool."+tool)
+}
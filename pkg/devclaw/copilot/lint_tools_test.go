@@ -0,0 +1,59 @@
+package copilot
+
+import "testing"
+
+func TestParseLintOutput(t *testing.T) {
+	golangci := `pkg/foo/bar.go:42:10: Error return value is not checked (errcheck)
+pkg/foo/baz.go:7: exported function Baz should have comment (revive)
+level=info msg="done"
+`
+	diags := parseLintOutput("golangci-lint", golangci)
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %d: %+v", len(diags), diags)
+	}
+	d := diags[0]
+	if d.File != "pkg/foo/bar.go" || d.Line != 42 || d.Col != 10 {
+		t.Errorf("unexpected location: %s:%d:%d", d.File, d.Line, d.Col)
+	}
+	if d.Linter != "errcheck" || d.Message != "Error return value is not checked" {
+		t.Errorf("unexpected linter/message: %q / %q", d.Linter, d.Message)
+	}
+
+	eslint := `src/app.ts:3:1: 'x' is assigned a value but never used. [@typescript-eslint/no-unused-vars]
+`
+	diags = parseLintOutput("eslint", eslint)
+	if len(diags) != 1 || diags[0].Linter != "@typescript-eslint/no-unused-vars" {
+		t.Errorf("unexpected eslint diagnostics: %+v", diags)
+	}
+
+	govet := `pkg/x/y.go:10:2: unreachable code
+`
+	diags = parseLintOutput("govet", govet)
+	if len(diags) != 1 || diags[0].Linter != "govet" || diags[0].Message != "unreachable code" {
+		t.Errorf("unexpected govet diagnostics: %+v", diags)
+	}
+}
+
+func TestBuildLintCommand(t *testing.T) {
+	if cmd := buildLintCommand("ruff", "src/", true); cmd != "ruff check --output-format concise --fix src/" {
+		t.Errorf("unexpected ruff command: %q", cmd)
+	}
+	if cmd := buildLintCommand("govet", "", false); cmd != "go vet ./..." {
+		t.Errorf("unexpected govet command: %q", cmd)
+	}
+	if cmd := buildLintCommand("unknown", "", false); cmd != "" {
+		t.Errorf("unknown tool should yield empty command, got %q", cmd)
+	}
+}
+
+func TestBuildFormatCommand(t *testing.T) {
+	if cmd := buildFormatCommand("gofmt", "", false); cmd != "gofmt -d ." {
+		t.Errorf("unexpected gofmt diff command: %q", cmd)
+	}
+	if cmd := buildFormatCommand("gofmt", "pkg/", true); cmd != "gofmt -w pkg/" {
+		t.Errorf("unexpected gofmt write command: %q", cmd)
+	}
+	if cmd := buildFormatCommand("ruff", "", false); cmd != "ruff format --diff ." {
+		t.Errorf("unexpected ruff format command: %q", cmd)
+	}
+}
@@ -0,0 +1,187 @@
+// Package copilot – llamacpp.go implements the embedded llama.cpp inference
+// backend. With api.provider: llamacpp and a GGUF model path, the client
+// manages a llama.cpp server (llama-server) bound to localhost and speaks
+// its OpenAI-compatible API, so small local models can power lightweight
+// tasks (fact extraction, routing, summarization) without any external
+// service. The server is started lazily on the first request and stopped
+// with the client.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// llamaCppDefaultBinary is the llama.cpp server binary looked up on PATH
+	// when params.llamacpp_binary is not set.
+	llamaCppDefaultBinary = "llama-server"
+
+	// llamaCppDefaultPort is the localhost port for the managed server.
+	llamaCppDefaultPort = 8873
+
+	// llamaCppStartTimeout is how long to wait for the server to become
+	// healthy after launch (model loading can take a while for large GGUFs).
+	llamaCppStartTimeout = 120 * time.Second
+)
+
+// llamaCppServer manages one llama.cpp server subprocess.
+type llamaCppServer struct {
+	binary    string
+	modelPath string
+	port      int
+	ctxSize   int
+	threads   int
+	logger    *slog.Logger
+
+	mu  sync.Mutex
+	cmd *exec.Cmd
+}
+
+// newLlamaCppServer builds a server manager from api.params:
+//
+//	model_path       — path to the GGUF model file (required)
+//	llamacpp_binary  — server binary (default: llama-server on PATH)
+//	llamacpp_port    — localhost port (default: 8873)
+//	context_size     — context window in tokens (0 = server default)
+//	threads          — inference threads (0 = server default)
+func newLlamaCppServer(params map[string]any, logger *slog.Logger) *llamaCppServer {
+	s := &llamaCppServer{
+		binary: llamaCppDefaultBinary,
+		port:   llamaCppDefaultPort,
+		logger: logger.With("component", "llamacpp"),
+	}
+	if v, ok := params["model_path"].(string); ok {
+		s.modelPath = v
+	}
+	if v, ok := params["llamacpp_binary"].(string); ok && v != "" {
+		s.binary = v
+	}
+	if v, ok := params["llamacpp_port"].(int); ok && v > 0 {
+		s.port = v
+	}
+	if v, ok := params["context_size"].(int); ok && v > 0 {
+		s.ctxSize = v
+	}
+	if v, ok := params["threads"].(int); ok && v > 0 {
+		s.threads = v
+	}
+	return s
+}
+
+// baseURL returns the OpenAI-compatible endpoint of the managed server.
+func (s *llamaCppServer) baseURL() string {
+	return fmt.Sprintf("http://127.0.0.1:%d/v1", s.port)
+}
+
+// ensureRunning starts the server if needed and waits until it is healthy.
+// Safe for concurrent use; only one process is ever started.
+func (s *llamaCppServer) ensureRunning(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Already running (or something else serves the port — reuse it).
+	if s.healthy(ctx) {
+		return nil
+	}
+	if s.modelPath == "" {
+		return fmt.Errorf("llamacpp provider requires api.params.model_path")
+	}
+
+	// A previous process died: reap it before starting a new one.
+	if s.cmd != nil {
+		_ = s.cmd.Wait()
+		s.cmd = nil
+	}
+
+	args := []string{
+		"-m", s.modelPath,
+		"--host", "127.0.0.1",
+		"--port", strconv.Itoa(s.port),
+	}
+	if s.ctxSize > 0 {
+		args = append(args, "-c", strconv.Itoa(s.ctxSize))
+	}
+	if s.threads > 0 {
+		args = append(args, "-t", strconv.Itoa(s.threads))
+	}
+
+	cmd := exec.Command(s.binary, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting llama.cpp server: %w", err)
+	}
+	s.cmd = cmd
+	s.logger.Info("llama.cpp server started",
+		"binary", s.binary,
+		"model", s.modelPath,
+		"port", s.port,
+		"pid", cmd.Process.Pid,
+	)
+
+	// Wait for the health endpoint — model loading dominates startup time.
+	deadline := time.Now().Add(llamaCppStartTimeout)
+	for time.Now().Before(deadline) {
+		if s.healthy(ctx) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for llama.cpp server: %w", ctx.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	return fmt.Errorf("llama.cpp server did not become healthy within %s", llamaCppStartTimeout)
+}
+
+// healthy checks the server's /health endpoint.
+func (s *llamaCppServer) healthy(ctx context.Context) bool {
+	reqCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, "GET", fmt.Sprintf("http://127.0.0.1:%d/health", s.port), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// Stop terminates the managed server process, if one was started.
+func (s *llamaCppServer) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cmd == nil || s.cmd.Process == nil {
+		return
+	}
+	s.logger.Info("stopping llama.cpp server", "pid", s.cmd.Process.Pid)
+	_ = s.cmd.Process.Kill()
+	_ = s.cmd.Wait()
+	s.cmd = nil
+}
+
+// ensureLocalBackend starts the embedded inference backend before a request
+// when one is configured. No-op for remote providers.
+func (c *LLMClient) ensureLocalBackend(ctx context.Context) error {
+	if c.llamacpp == nil {
+		return nil
+	}
+	return c.llamacpp.ensureRunning(ctx)
+}
+
+// Close releases client resources, stopping the embedded inference backend
+// if one is running.
+func (c *LLMClient) Close() {
+	if c.llamacpp != nil {
+		c.llamacpp.Stop()
+	}
+}
@@ -0,0 +1,82 @@
+package copilot
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestNewLlamaCppServerParams(t *testing.T) {
+	s := newLlamaCppServer(map[string]any{
+		"model_path":      "/models/phi-3-mini.gguf",
+		"llamacpp_binary": "/opt/llama/llama-server",
+		"llamacpp_port":   9090,
+		"context_size":    8192,
+		"threads":         4,
+	}, slog.Default())
+
+	if s.modelPath != "/models/phi-3-mini.gguf" {
+		t.Errorf("unexpected model path: %q", s.modelPath)
+	}
+	if s.binary != "/opt/llama/llama-server" {
+		t.Errorf("unexpected binary: %q", s.binary)
+	}
+	if s.port != 9090 || s.ctxSize != 8192 || s.threads != 4 {
+		t.Errorf("unexpected params: port=%d ctx=%d threads=%d", s.port, s.ctxSize, s.threads)
+	}
+	if s.baseURL() != "http://127.0.0.1:9090/v1" {
+		t.Errorf("unexpected base URL: %q", s.baseURL())
+	}
+}
+
+func TestNewLlamaCppServerDefaults(t *testing.T) {
+	s := newLlamaCppServer(nil, slog.Default())
+	if s.binary != llamaCppDefaultBinary {
+		t.Errorf("expected default binary, got %q", s.binary)
+	}
+	if s.port != llamaCppDefaultPort {
+		t.Errorf("expected default port, got %d", s.port)
+	}
+	if s.modelPath != "" {
+		t.Errorf("expected empty model path, got %q", s.modelPath)
+	}
+}
+
+func TestLlamaCppClientWiring(t *testing.T) {
+	cfg := &Config{Model: "phi-3-mini"}
+	cfg.API.Provider = "llamacpp"
+	cfg.API.Params = map[string]any{"model_path": "/models/phi-3-mini.gguf"}
+
+	client := NewLLMClient(cfg, slog.Default())
+	if client.provider != "llamacpp" {
+		t.Errorf("expected llamacpp provider, got %q", client.provider)
+	}
+	if client.llamacpp == nil {
+		t.Fatal("expected embedded backend to be configured")
+	}
+	if client.baseURL != "http://127.0.0.1:8873/v1" {
+		t.Errorf("expected managed server base URL, got %q", client.baseURL)
+	}
+
+	// No API key required; the shim provides tool calling.
+	tools := []ToolDefinition{MakeToolDefinition("probe", "test", nil)}
+	if !client.toolShimActive("phi-3-mini", tools) {
+		t.Error("llamacpp models should use the function-calling shim")
+	}
+
+	// An explicit base URL is respected (e.g. externally managed server).
+	cfg.API.BaseURL = "http://127.0.0.1:9999/v1"
+	external := NewLLMClient(cfg, slog.Default())
+	if external.baseURL != "http://127.0.0.1:9999/v1" {
+		t.Errorf("explicit base URL must win, got %q", external.baseURL)
+	}
+
+	// Remote providers get no embedded backend.
+	remote := NewLLMClient(&Config{Model: "gpt-4o"}, slog.Default())
+	if remote.llamacpp != nil {
+		t.Error("remote providers must not start a local backend")
+	}
+	if err := remote.ensureLocalBackend(context.Background()); err != nil {
+		t.Errorf("ensureLocalBackend must be a no-op for remote providers: %v", err)
+	}
+}
@@ -31,18 +31,40 @@ type StreamCallback func(chunk string)
 
 // LLMClient handles communication with the LLM provider API.
 type LLMClient struct {
-	baseURL    string
-	provider   string // "openai", "zai", "zai-coding", "zai-anthropic", "anthropic", ""
-	apiKey     string
-	model      string
-	fallback   FallbackConfig
-	params     map[string]any // provider-specific params (context1m, tool_stream, etc.)
-	httpClient *http.Client
-	logger     *slog.Logger
+	baseURL      string
+	provider     string // "openai", "zai", "zai-coding", "zai-anthropic", "anthropic", ""
+	apiKey       string
+	model        string
+	utilityModel string // cheaper model for internal low-stakes calls ("" = main model)
+	fallback     FallbackConfig
+	batch        BatchConfig
+	params       map[string]any // provider-specific params (context1m, tool_stream, etc.)
+	httpClient   *http.Client
+	logger       *slog.Logger
 
 	// OAuth support (optional)
 	oauthTokenManager OAuthTokenManager
 
+	// scheduler coordinates requests across sessions when the global request
+	// queue is enabled (nil = no queuing).
+	scheduler *RequestScheduler
+
+	// llamacpp is the embedded llama.cpp backend when api.provider is
+	// "llamacpp" (nil otherwise).
+	llamacpp *llamaCppServer
+
+	// health tracks per-model error rates and latency for status reporting
+	// and the latency-based failover policy.
+	health *healthTracker
+
+	// onPolicyViolation is called when a workspace model policy blocks a
+	// model (nil = log only). Wired by the assistant to alert admins.
+	onPolicyViolation func(workspaceID, provider, model string)
+
+	// Traffic logging (nil recorder = off). See traffic_log.go.
+	trafficLevel    string
+	trafficRecorder func(TrafficRecord)
+
 	// Rate-limit cooldown tracking for auto-recovery.
 	// When the primary model hits a rate limit, we record when the cooldown
 	// expires and which fallback model we're using. Once the cooldown nears
@@ -76,12 +98,29 @@ func NewLLMClient(cfg *Config, logger *slog.Logger) *LLMClient {
 		provider = cfg.API.Provider
 	}
 
+	// Embedded llama.cpp backend: manage a local llama-server and point the
+	// client at its OpenAI-compatible endpoint.
+	var llamacpp *llamaCppServer
+	if cfg.API.Provider == "llamacpp" {
+		provider = "llamacpp"
+		llamacpp = newLlamaCppServer(cfg.API.Params, logger)
+		if cfg.API.BaseURL == "" {
+			baseURL = llamacpp.baseURL()
+		}
+	}
+
+	fallback := cfg.Fallback.Effective()
+
 	return &LLMClient{
 		baseURL:          baseURL,
 		provider:         provider,
 		apiKey:           cfg.API.APIKey,
 		model:            normalizeGeminiModelID(cfg.Model),
-		fallback:         cfg.Fallback.Effective(),
+		utilityModel:     normalizeGeminiModelID(cfg.Models.Utility),
+		fallback:         fallback,
+		batch:            cfg.Batch.Effective(),
+		llamacpp:         llamacpp,
+		health:           newHealthTracker(fallback.HealthWindowMinutes),
 		params:           cfg.API.Params,
 		probeMinInterval: 30 * time.Second,
 		httpClient: &http.Client{
@@ -196,6 +235,55 @@ func (c *LLMClient) resolveAPIKey() string {
 	return c.apiKey // returns empty string if nothing found
 }
 
+// SetScheduler wires the global request scheduler. When set, every completion
+// acquires a slot (per-provider concurrency + token budget) before sending.
+func (c *LLMClient) SetScheduler(s *RequestScheduler) {
+	c.scheduler = s
+}
+
+// SetPolicyViolationHandler registers a callback invoked whenever a workspace
+// model policy blocks a model. Used to surface violations to the admin.
+func (c *LLMClient) SetPolicyViolationHandler(fn func(workspaceID, provider, model string)) {
+	c.onPolicyViolation = fn
+}
+
+// applyModelPolicy filters a candidate model list against the workspace model
+// policy carried in ctx (data-residency enforcement). Blocked models are
+// logged and reported via the policy violation handler. Returns the list
+// unchanged when no policy is set.
+func (c *LLMClient) applyModelPolicy(ctx context.Context, models []string) []string {
+	wsID, policy := ModelPolicyFromContext(ctx)
+	if policy == nil {
+		return models
+	}
+
+	allowed := make([]string, 0, len(models))
+	for _, model := range models {
+		if policy.Allows(c.provider, model) {
+			allowed = append(allowed, model)
+			continue
+		}
+		c.logger.Warn("model blocked by workspace policy",
+			"workspace", wsID,
+			"provider", c.provider,
+			"model", model,
+		)
+		if c.onPolicyViolation != nil {
+			c.onPolicyViolation(wsID, c.provider, model)
+		}
+	}
+	return allowed
+}
+
+// acquireSlot reserves a request slot from the scheduler, or is a no-op when
+// no scheduler is configured. The returned release function must be deferred.
+func (c *LLMClient) acquireSlot(ctx context.Context, messages []chatMessage) (func(), error) {
+	if c.scheduler == nil {
+		return func() {}, nil
+	}
+	return c.scheduler.Acquire(ctx, c.provider, SessionIDFromContext(ctx), estimateMessagesTokens(messages))
+}
+
 // SetOAuthTokenManager sets the OAuth token manager for this client.
 func (c *LLMClient) SetOAuthTokenManager(tm OAuthTokenManager) {
 	c.oauthTokenManager = tm
@@ -264,6 +352,8 @@ func (c *LLMClient) providerForModel(model string) string {
 			return "Google"
 		case "ollama":
 			return "Ollama"
+		case "llamacpp":
+			return "llama.cpp"
 		case "lmstudio":
 			return "LM Studio"
 		case "vllm":
@@ -475,13 +565,19 @@ func getModelDefaults(model, provider string) modelDefaults {
 	case strings.HasPrefix(model, "llama"),
 		strings.HasPrefix(model, "mistral"),
 		strings.HasPrefix(model, "qwen"),
-		strings.HasPrefix(model, "gemma"),
-		strings.HasPrefix(model, "phi"),
 		strings.HasPrefix(model, "deepseek"),
-		strings.HasPrefix(model, "codellama"),
 		strings.HasPrefix(model, "command-r"):
 		d.DefaultTemperature = 0.7
 		d.MaxOutputTokens = 4096
+
+	// Local models without native tool calling — the function-calling shim
+	// (tool_shim.go) provides tools via a prompt protocol instead.
+	case strings.HasPrefix(model, "gemma"),
+		strings.HasPrefix(model, "phi"),
+		strings.HasPrefix(model, "codellama"):
+		d.DefaultTemperature = 0.7
+		d.MaxOutputTokens = 4096
+		d.SupportsTools = false
 	}
 
 	// Provider-level overrides.
@@ -494,6 +590,13 @@ func getModelDefaults(model, provider string) modelDefaults {
 		if d.MaxOutputTokens == 0 {
 			d.MaxOutputTokens = 4096
 		}
+	case "llamacpp":
+		// Embedded llama.cpp serves small GGUF models; tool calling depends
+		// on the chat template, so the function-calling shim handles tools.
+		if d.MaxOutputTokens == 0 {
+			d.MaxOutputTokens = 4096
+		}
+		d.SupportsTools = false
 	}
 
 	return d
@@ -1130,6 +1233,50 @@ func classifyAPIError(statusCode int, body string) LLMErrorKind {
 // Complete sends a simple chat completion request (no tools) and returns the text.
 // Convenience wrapper around CompleteWithTools for non-agentic use cases.
 func (c *LLMClient) Complete(ctx context.Context, systemPrompt string, history []ConversationEntry, userMessage string) (string, error) {
+	messages := buildCompletionMessages(systemPrompt, history, userMessage)
+
+	resp, err := c.CompleteWithTools(ctx, messages, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Content, nil
+}
+
+// CompleteUtility is like Complete but runs on the configured utility model
+// (models.utility) — a cheaper/faster model for internal low-stakes calls
+// like compaction summaries, session titles, and fact extraction. Falls back
+// to the main model when no utility model is set or it fails.
+func (c *LLMClient) CompleteUtility(ctx context.Context, systemPrompt string, history []ConversationEntry, userMessage string) (string, error) {
+	if c.utilityModel == "" || c.utilityModel == c.model {
+		return c.Complete(ctx, systemPrompt, history, userMessage)
+	}
+
+	messages := buildCompletionMessages(systemPrompt, history, userMessage)
+	resp, err := c.CompleteWithToolsUsingModel(ctx, c.utilityModel, messages, nil)
+	if err == nil {
+		return resp.Content, nil
+	}
+
+	c.logger.Warn("utility model failed, falling back to main model",
+		"utility_model", c.utilityModel,
+		"error", err,
+	)
+	resp, err = c.CompleteWithTools(ctx, messages, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+// UtilityModel returns the configured utility model ("" = main model).
+func (c *LLMClient) UtilityModel() string {
+	return c.utilityModel
+}
+
+// buildCompletionMessages converts a simple prompt + history into the chat
+// message form used by the completion APIs.
+func buildCompletionMessages(systemPrompt string, history []ConversationEntry, userMessage string) []chatMessage {
 	messages := make([]chatMessage, 0, len(history)*2+2)
 
 	if systemPrompt != "" {
@@ -1157,12 +1304,7 @@ func (c *LLMClient) Complete(ctx context.Context, systemPrompt string, history [
 		Content: userMessage,
 	})
 
-	resp, err := c.CompleteWithTools(ctx, messages, nil)
-	if err != nil {
-		return "", err
-	}
-
-	return resp.Content, nil
+	return messages
 }
 
 // CompleteWithVision sends an image plus optional text to the LLM vision API
@@ -1377,10 +1519,41 @@ func envOrEmpty(key string) string {
 // completeOnce performs a single chat completion request. Returns *apiError on HTTP errors
 // so the caller can classify and decide retry/fallback.
 func (c *LLMClient) completeOnce(ctx context.Context, model string, messages []chatMessage, tools []ToolDefinition) (*LLMResponse, error) {
+	release, err := c.acquireSlot(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Function-calling shim: models without native tool support get the tool
+	// catalog as a prompt protocol and their text invocations parsed back
+	// into ToolCalls.
+	shim := c.toolShimActive(model, tools)
+	if shim {
+		messages = applyToolShimMessages(messages, tools)
+		tools = nil
+	}
+
+	if err := c.ensureLocalBackend(ctx); err != nil {
+		return nil, err
+	}
+
+	tools = adaptToolsForDialect(c.toolDialect(model), tools)
+	start := time.Now()
+	var resp *LLMResponse
 	if c.isAnthropicAPI() {
-		return c.completeOnceAnthropic(ctx, model, messages, tools)
+		resp, err = c.completeOnceAnthropic(ctx, model, messages, tools)
+	} else {
+		resp, err = c.completeOnceOpenAI(ctx, model, messages, tools)
 	}
-	return c.completeOnceOpenAI(ctx, model, messages, tools)
+	c.health.Record(model, time.Since(start), err)
+	if shim && err == nil {
+		resp = applyToolShimResponse(resp)
+	}
+	if c.trafficRecorder != nil {
+		c.recordTraffic(ctx, model, messages, resp, err, time.Since(start))
+	}
+	return resp, err
 }
 
 // completeOnceAnthropic performs a single request using the Anthropic Messages API.
@@ -1628,7 +1801,7 @@ func (c *LLMClient) CompleteWithToolsStream(ctx context.Context, messages []chat
 // when non-empty. Empty = use c.model. Includes retry for transient HTTP errors
 // before falling back to non-streaming.
 func (c *LLMClient) CompleteWithToolsStreamUsingModel(ctx context.Context, modelOverride string, messages []chatMessage, tools []ToolDefinition, onChunk StreamCallback) (*LLMResponse, error) {
-	if c.resolveAPIKey() == "" && c.provider != "ollama" {
+	if c.resolveAPIKey() == "" && c.provider != "ollama" && c.provider != "llamacpp" {
 		return nil, fmt.Errorf("API key not configured. Set %s in vault or environment", GetProviderKeyName(c.provider))
 	}
 
@@ -1637,6 +1810,12 @@ func (c *LLMClient) CompleteWithToolsStreamUsingModel(ctx context.Context, model
 		model = modelOverride
 	}
 
+	// Per-workspace model policy: when the streaming model is forbidden,
+	// delegate to the non-streaming path, which filters the fallback chain.
+	if allowed := c.applyModelPolicy(ctx, []string{model}); len(allowed) == 0 {
+		return c.CompleteWithFallbackUsingModel(ctx, modelOverride, messages, tools)
+	}
+
 	// Try streaming with 1 retry for transient errors.
 	const maxStreamRetries = 1
 	const transientRetryDelay = 2500 * time.Millisecond
@@ -1686,10 +1865,32 @@ func (c *LLMClient) CompleteWithToolsStreamUsingModel(ctx context.Context, model
 
 // completeOnceStream performs a single streaming chat completion. Uses SSE parsing.
 func (c *LLMClient) completeOnceStream(ctx context.Context, model string, messages []chatMessage, tools []ToolDefinition, onChunk StreamCallback) (*LLMResponse, error) {
+	// Function-calling shim: the prompt protocol cannot stream tool calls
+	// incrementally, so shimmed requests run through the non-streaming path.
+	if c.toolShimActive(model, tools) {
+		return c.completeOnce(ctx, model, messages, tools)
+	}
+
+	if err := c.ensureLocalBackend(ctx); err != nil {
+		return nil, err
+	}
+
+	release, err := c.acquireSlot(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	tools = adaptToolsForDialect(c.toolDialect(model), tools)
+	start := time.Now()
+	var resp *LLMResponse
 	if c.isAnthropicAPI() {
-		return c.completeOnceStreamAnthropic(ctx, model, messages, tools, onChunk)
+		resp, err = c.completeOnceStreamAnthropic(ctx, model, messages, tools, onChunk)
+	} else {
+		resp, err = c.completeOnceStreamOpenAI(ctx, model, messages, tools, onChunk)
 	}
-	return c.completeOnceStreamOpenAI(ctx, model, messages, tools, onChunk)
+	c.health.Record(model, time.Since(start), err)
+	return resp, err
 }
 
 // completeOnceStreamAnthropic handles Anthropic streaming with event types.
@@ -2113,6 +2314,12 @@ func (c *LLMClient) setCooldown(model string, retryAfterSec int) {
 		"cooldown_seconds", int(duration.Seconds()),
 		"expires_at", c.cooldownExpires.Format(time.RFC3339),
 	)
+
+	// Coordinated backpressure: pause the global queue for this provider so
+	// queued sessions don't pile onto an already rate-limited API.
+	if c.scheduler != nil {
+		c.scheduler.Backoff(c.provider, duration)
+	}
 }
 
 // shouldProbePrimary returns true if the primary model was rate-limited, the
@@ -2178,7 +2385,7 @@ func (c *LLMClient) isInCooldown(model string) bool {
 // calls use fallback models. Near cooldown expiry, a probe is sent to the
 // primary model to check if it recovered. On success, cooldown is cleared.
 func (c *LLMClient) CompleteWithFallbackUsingModel(ctx context.Context, modelOverride string, messages []chatMessage, tools []ToolDefinition) (*LLMResponse, error) {
-	if c.resolveAPIKey() == "" && c.provider != "ollama" {
+	if c.resolveAPIKey() == "" && c.provider != "ollama" && c.provider != "llamacpp" {
 		return nil, fmt.Errorf("API key not configured. Set %s in vault or environment", GetProviderKeyName(c.provider))
 	}
 
@@ -2191,6 +2398,29 @@ func (c *LLMClient) CompleteWithFallbackUsingModel(ctx context.Context, modelOve
 	models = append(models, primary)
 	models = append(models, c.fallback.Models...)
 
+	// Per-workspace model policy (data residency): drop forbidden models from
+	// the chain so no request ever reaches them, not even as fallback.
+	models = c.applyModelPolicy(ctx, models)
+	if len(models) == 0 {
+		wsID, _ := ModelPolicyFromContext(ctx)
+		return nil, fmt.Errorf("workspace %q model policy forbids all configured models (provider %s)", wsID, c.provider)
+	}
+	primary = models[0]
+
+	// Latency-based failover policy: when the primary's p95 latency over the
+	// health window exceeds the configured threshold, prefer fallbacks and
+	// keep the primary as last resort.
+	if len(models) > 1 && c.primaryDegraded(primary) {
+		c.logger.Warn("primary model degraded, preferring fallbacks",
+			"model", primary,
+			"p95_threshold_s", c.fallback.PreferFallbackP95Seconds,
+		)
+		reordered := make([]string, 0, len(models))
+		reordered = append(reordered, models[1:]...)
+		reordered = append(reordered, models[0])
+		models = reordered
+	}
+
 	initialBackoff := time.Duration(c.fallback.InitialBackoffMs) * time.Millisecond
 	maxBackoff := time.Duration(c.fallback.MaxBackoffMs) * time.Millisecond
 
@@ -0,0 +1,510 @@
+// Package copilot – llm_batch.go implements provider batch completions
+// (OpenAI Batch API, Anthropic Message Batches) for non-interactive jobs.
+// Bulk work like document summarization does not need low latency, and both
+// providers price batched requests at roughly half the live rate. Jobs are
+// submitted, polled locally until they finish, and the results are returned
+// keyed by custom ID. Which internal operations use batching is selectable
+// via BatchConfig.Operations.
+package copilot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// batchOpDocSummary is the operation name for document map-reduce
+// summarization (see doc_summarizer.go).
+const batchOpDocSummary = "doc_summary"
+
+// BatchConfig configures provider batch APIs for non-interactive jobs.
+type BatchConfig struct {
+	// Enabled turns batch completions on (default: off).
+	Enabled bool `yaml:"enabled"`
+
+	// Operations lists the internal operations allowed to use batching
+	// (e.g. "doc_summary"). Empty = all operations.
+	Operations []string `yaml:"operations"`
+
+	// PollIntervalSeconds is how often to poll a submitted job (default: 30).
+	PollIntervalSeconds int `yaml:"poll_interval_seconds"`
+
+	// TimeoutMinutes is how long to wait for a job before giving up and
+	// falling back to live calls (default: 60).
+	TimeoutMinutes int `yaml:"timeout_minutes"`
+}
+
+// Effective returns a copy with default values filled in for zero fields.
+func (c BatchConfig) Effective() BatchConfig {
+	out := c
+	if out.PollIntervalSeconds <= 0 {
+		out.PollIntervalSeconds = 30
+	}
+	if out.TimeoutMinutes <= 0 {
+		out.TimeoutMinutes = 60
+	}
+	return out
+}
+
+// BatchRequest is one completion inside a batch job.
+type BatchRequest struct {
+	// CustomID identifies this request in the results (unique per batch).
+	CustomID string
+
+	// System is the system prompt.
+	System string
+
+	// Prompt is the user message.
+	Prompt string
+}
+
+// BatchResult is the outcome of one request in a finished batch.
+type BatchResult struct {
+	CustomID string
+	Content  string
+	Err      error
+}
+
+// SupportsBatch reports whether the configured provider has a batch API.
+// Only first-party OpenAI and Anthropic endpoints are supported — proxy
+// providers generally do not expose the batch routes.
+func (c *LLMClient) SupportsBatch() bool {
+	return c.provider == "openai" || c.provider == "anthropic"
+}
+
+// BatchEnabledFor reports whether the given internal operation should use
+// batch completions.
+func (c *LLMClient) BatchEnabledFor(op string) bool {
+	if !c.batch.Enabled || !c.SupportsBatch() {
+		return false
+	}
+	return len(c.batch.Operations) == 0 || slices.Contains(c.batch.Operations, op)
+}
+
+// CompleteBatch submits the requests as one provider batch job, polls until
+// it finishes, and returns results keyed by custom ID. Blocks for the job's
+// full duration — callers must be non-interactive. Empty model = c.model.
+func (c *LLMClient) CompleteBatch(ctx context.Context, model string, requests []BatchRequest) (map[string]BatchResult, error) {
+	if !c.SupportsBatch() {
+		return nil, fmt.Errorf("provider %s has no batch API", c.provider)
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("empty batch")
+	}
+	if model == "" {
+		model = c.model
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.batch.TimeoutMinutes)*time.Minute)
+	defer cancel()
+
+	var (
+		batchID string
+		err     error
+	)
+	if c.provider == "anthropic" {
+		batchID, err = c.submitBatchAnthropic(ctx, model, requests)
+	} else {
+		batchID, err = c.submitBatchOpenAI(ctx, model, requests)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("submitting batch: %w", err)
+	}
+
+	c.logger.Info("batch job submitted",
+		"batch_id", batchID,
+		"model", model,
+		"requests", len(requests),
+	)
+
+	interval := time.Duration(c.batch.PollIntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for batch %s: %w", batchID, ctx.Err())
+		case <-ticker.C:
+		}
+
+		var (
+			done    bool
+			results map[string]BatchResult
+		)
+		if c.provider == "anthropic" {
+			done, results, err = c.pollBatchAnthropic(ctx, batchID)
+		} else {
+			done, results, err = c.pollBatchOpenAI(ctx, batchID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("polling batch %s: %w", batchID, err)
+		}
+		if done {
+			c.logger.Info("batch job finished", "batch_id", batchID, "results", len(results))
+			return results, nil
+		}
+	}
+}
+
+// --- OpenAI Batch API ---
+
+// submitBatchOpenAI uploads the requests as a JSONL file and creates a batch
+// against /chat/completions with a 24h completion window.
+func (c *LLMClient) submitBatchOpenAI(ctx context.Context, model string, requests []BatchRequest) (string, error) {
+	var jsonl bytes.Buffer
+	for _, r := range requests {
+		line := map[string]any{
+			"custom_id": r.CustomID,
+			"method":    "POST",
+			"url":       "/v1/chat/completions",
+			"body": map[string]any{
+				"model": model,
+				"messages": []map[string]string{
+					{"role": "system", "content": r.System},
+					{"role": "user", "content": r.Prompt},
+				},
+			},
+		}
+		if err := json.NewEncoder(&jsonl).Encode(line); err != nil {
+			return "", fmt.Errorf("encoding batch line: %w", err)
+		}
+	}
+
+	// Upload the input file.
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("purpose", "batch"); err != nil {
+		return "", fmt.Errorf("writing purpose field: %w", err)
+	}
+	part, err := w.CreateFormFile("file", "batch.jsonl")
+	if err != nil {
+		return "", fmt.Errorf("creating form file: %w", err)
+	}
+	if _, err := part.Write(jsonl.Bytes()); err != nil {
+		return "", fmt.Errorf("writing batch file: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/files", &body)
+	if err != nil {
+		return "", fmt.Errorf("creating upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+c.resolveAPIKey())
+
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := c.doBatchJSON(req, &file); err != nil {
+		return "", fmt.Errorf("uploading batch file: %w", err)
+	}
+
+	// Create the batch job.
+	payload, err := json.Marshal(map[string]any{
+		"input_file_id":     file.ID,
+		"endpoint":          "/v1/chat/completions",
+		"completion_window": "24h",
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshaling batch request: %w", err)
+	}
+	req, err = http.NewRequestWithContext(ctx, "POST", c.baseURL+"/batches", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("creating batch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.resolveAPIKey())
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.doBatchJSON(req, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// pollBatchOpenAI checks an OpenAI batch and, when completed, downloads and
+// parses the output file.
+func (c *LLMClient) pollBatchOpenAI(ctx context.Context, batchID string) (bool, map[string]BatchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/batches/"+batchID, nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("creating status request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.resolveAPIKey())
+
+	var status struct {
+		Status       string `json:"status"`
+		OutputFileID string `json:"output_file_id"`
+		ErrorFileID  string `json:"error_file_id"`
+	}
+	if err := c.doBatchJSON(req, &status); err != nil {
+		return false, nil, err
+	}
+
+	switch status.Status {
+	case "completed":
+	case "failed", "expired", "cancelled":
+		return false, nil, fmt.Errorf("batch ended with status %q", status.Status)
+	default:
+		return false, nil, nil // validating / in_progress / finalizing
+	}
+
+	fileID := status.OutputFileID
+	if fileID == "" {
+		fileID = status.ErrorFileID
+	}
+	req, err = http.NewRequestWithContext(ctx, "GET", c.baseURL+"/files/"+fileID+"/content", nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("creating results request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.resolveAPIKey())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, nil, fmt.Errorf("downloading batch results: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, nil, fmt.Errorf("downloading batch results: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	results, err := parseOpenAIBatchResults(resp.Body)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, results, nil
+}
+
+// parseOpenAIBatchResults parses the JSONL output of an OpenAI batch.
+func parseOpenAIBatchResults(r io.Reader) (map[string]BatchResult, error) {
+	results := make(map[string]BatchResult)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry struct {
+			CustomID string `json:"custom_id"`
+			Response *struct {
+				StatusCode int `json:"status_code"`
+				Body       struct {
+					Choices []struct {
+						Message struct {
+							Content string `json:"content"`
+						} `json:"message"`
+					} `json:"choices"`
+				} `json:"body"`
+			} `json:"response"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("parsing batch result line: %w", err)
+		}
+
+		res := BatchResult{CustomID: entry.CustomID}
+		switch {
+		case entry.Error != nil:
+			res.Err = fmt.Errorf("batch request failed: %s", entry.Error.Message)
+		case entry.Response == nil || entry.Response.StatusCode != http.StatusOK:
+			code := 0
+			if entry.Response != nil {
+				code = entry.Response.StatusCode
+			}
+			res.Err = fmt.Errorf("batch request failed: HTTP %d", code)
+		case len(entry.Response.Body.Choices) == 0:
+			res.Err = fmt.Errorf("batch request returned no choices")
+		default:
+			res.Content = entry.Response.Body.Choices[0].Message.Content
+		}
+		results[entry.CustomID] = res
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading batch results: %w", err)
+	}
+	return results, nil
+}
+
+// --- Anthropic Message Batches ---
+
+// submitBatchAnthropic creates a message batch with the requests inline.
+func (c *LLMClient) submitBatchAnthropic(ctx context.Context, model string, requests []BatchRequest) (string, error) {
+	maxTok := getModelDefaults(model, c.provider).MaxOutputTokens
+	if maxTok == 0 {
+		maxTok = 8192
+	}
+
+	entries := make([]map[string]any, 0, len(requests))
+	for _, r := range requests {
+		entries = append(entries, map[string]any{
+			"custom_id": r.CustomID,
+			"params": map[string]any{
+				"model":      model,
+				"max_tokens": maxTok,
+				"system":     r.System,
+				"messages": []map[string]string{
+					{"role": "user", "content": r.Prompt},
+				},
+			},
+		})
+	}
+	payload, err := json.Marshal(map[string]any{"requests": entries})
+	if err != nil {
+		return "", fmt.Errorf("marshaling batch request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/messages/batches", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("creating batch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("x-api-key", c.resolveAPIKey())
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.doBatchJSON(req, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// pollBatchAnthropic checks a message batch and, when ended, downloads and
+// parses the results stream.
+func (c *LLMClient) pollBatchAnthropic(ctx context.Context, batchID string) (bool, map[string]BatchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/v1/messages/batches/"+batchID, nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("creating status request: %w", err)
+	}
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("x-api-key", c.resolveAPIKey())
+
+	var status struct {
+		ProcessingStatus string `json:"processing_status"`
+		ResultsURL       string `json:"results_url"`
+	}
+	if err := c.doBatchJSON(req, &status); err != nil {
+		return false, nil, err
+	}
+	if status.ProcessingStatus != "ended" {
+		return false, nil, nil
+	}
+	if status.ResultsURL == "" {
+		return false, nil, fmt.Errorf("batch ended without results URL")
+	}
+
+	req, err = http.NewRequestWithContext(ctx, "GET", status.ResultsURL, nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("creating results request: %w", err)
+	}
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("x-api-key", c.resolveAPIKey())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, nil, fmt.Errorf("downloading batch results: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, nil, fmt.Errorf("downloading batch results: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	results, err := parseAnthropicBatchResults(resp.Body)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, results, nil
+}
+
+// parseAnthropicBatchResults parses the JSONL results of a message batch.
+func parseAnthropicBatchResults(r io.Reader) (map[string]BatchResult, error) {
+	results := make(map[string]BatchResult)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry struct {
+			CustomID string `json:"custom_id"`
+			Result   struct {
+				Type    string `json:"type"`
+				Message struct {
+					Content []struct {
+						Type string `json:"type"`
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"message"`
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("parsing batch result line: %w", err)
+		}
+
+		res := BatchResult{CustomID: entry.CustomID}
+		if entry.Result.Type != "succeeded" {
+			msg := entry.Result.Error.Message
+			if msg == "" {
+				msg = entry.Result.Type
+			}
+			res.Err = fmt.Errorf("batch request failed: %s", msg)
+		} else {
+			var text strings.Builder
+			for _, block := range entry.Result.Message.Content {
+				if block.Type == "text" {
+					text.WriteString(block.Text)
+				}
+			}
+			res.Content = text.String()
+		}
+		results[entry.CustomID] = res
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading batch results: %w", err)
+	}
+	return results, nil
+}
+
+// doBatchJSON executes an HTTP request and decodes the JSON response into
+// out, returning an error for non-2xx responses.
+func (c *LLMClient) doBatchJSON(req *http.Request, out any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(body), 300))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatchConfigEffective(t *testing.T) {
+	cfg := BatchConfig{}.Effective()
+	if cfg.PollIntervalSeconds != 30 {
+		t.Errorf("expected default poll interval 30, got %d", cfg.PollIntervalSeconds)
+	}
+	if cfg.TimeoutMinutes != 60 {
+		t.Errorf("expected default timeout 60, got %d", cfg.TimeoutMinutes)
+	}
+
+	custom := BatchConfig{PollIntervalSeconds: 5, TimeoutMinutes: 10}.Effective()
+	if custom.PollIntervalSeconds != 5 || custom.TimeoutMinutes != 10 {
+		t.Errorf("explicit values must be kept, got %+v", custom)
+	}
+}
+
+func TestBatchEnabledFor(t *testing.T) {
+	client := &LLMClient{provider: "openai", batch: BatchConfig{Enabled: true}}
+	if !client.BatchEnabledFor(batchOpDocSummary) {
+		t.Error("empty operations list should allow all operations")
+	}
+
+	client.batch.Operations = []string{"other_op"}
+	if client.BatchEnabledFor(batchOpDocSummary) {
+		t.Error("operation outside the allowlist must not use batching")
+	}
+	client.batch.Operations = []string{batchOpDocSummary}
+	if !client.BatchEnabledFor(batchOpDocSummary) {
+		t.Error("allowlisted operation should use batching")
+	}
+
+	client.batch.Enabled = false
+	if client.BatchEnabledFor(batchOpDocSummary) {
+		t.Error("disabled config must not use batching")
+	}
+
+	// Proxy providers have no batch routes.
+	proxy := &LLMClient{provider: "zai-anthropic", batch: BatchConfig{Enabled: true}}
+	if proxy.BatchEnabledFor(batchOpDocSummary) {
+		t.Error("proxy providers must not use batching")
+	}
+}
+
+func TestParseOpenAIBatchResults(t *testing.T) {
+	jsonl := `{"custom_id":"chunk-0","response":{"status_code":200,"body":{"choices":[{"message":{"content":"first summary"}}]}}}
+{"custom_id":"chunk-1","error":{"message":"rate limited"}}
+{"custom_id":"chunk-2","response":{"status_code":500,"body":{}}}`
+
+	results, err := parseOpenAIBatchResults(strings.NewReader(jsonl))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results["chunk-0"].Content != "first summary" || results["chunk-0"].Err != nil {
+		t.Errorf("expected successful chunk-0, got %+v", results["chunk-0"])
+	}
+	if results["chunk-1"].Err == nil || !strings.Contains(results["chunk-1"].Err.Error(), "rate limited") {
+		t.Errorf("expected chunk-1 error, got %+v", results["chunk-1"])
+	}
+	if results["chunk-2"].Err == nil {
+		t.Errorf("expected chunk-2 error for HTTP 500, got %+v", results["chunk-2"])
+	}
+}
+
+func TestParseAnthropicBatchResults(t *testing.T) {
+	jsonl := `{"custom_id":"chunk-0","result":{"type":"succeeded","message":{"content":[{"type":"text","text":"part one "},{"type":"text","text":"part two"}]}}}
+{"custom_id":"chunk-1","result":{"type":"errored","error":{"message":"overloaded"}}}
+{"custom_id":"chunk-2","result":{"type":"expired"}}`
+
+	results, err := parseAnthropicBatchResults(strings.NewReader(jsonl))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if results["chunk-0"].Content != "part one part two" {
+		t.Errorf("expected concatenated text blocks, got %q", results["chunk-0"].Content)
+	}
+	if results["chunk-1"].Err == nil || !strings.Contains(results["chunk-1"].Err.Error(), "overloaded") {
+		t.Errorf("expected chunk-1 error, got %+v", results["chunk-1"])
+	}
+	if results["chunk-2"].Err == nil || !strings.Contains(results["chunk-2"].Err.Error(), "expired") {
+		t.Errorf("expected expired error, got %+v", results["chunk-2"])
+	}
+}
@@ -0,0 +1,259 @@
+// Package copilot – llm_health.go tracks per-model health for the LLM
+// client: request and error counts, last error, and a rolling latency window
+// for median/p95. The stats feed the /llm status command and the
+// latency-based failover policy configured in FallbackConfig ("if the
+// primary's p95 exceeds the threshold, prefer fallbacks").
+package copilot
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples caps the per-model latency window to bound memory.
+const maxLatencySamples = 256
+
+// minPolicySamples is the minimum number of windowed samples before the
+// failover policy trusts the p95 — a single slow request should not demote
+// the primary.
+const minPolicySamples = 3
+
+// latencySample is one completed request in the rolling window.
+type latencySample struct {
+	at      time.Time
+	latency time.Duration
+}
+
+// modelHealth accumulates health data for one model.
+type modelHealth struct {
+	requests    int // total since client start
+	errors      int // total since client start
+	lastError   string
+	lastErrorAt time.Time
+	samples     []latencySample // pruned to the health window
+}
+
+// healthTracker records per-model request outcomes. All methods are safe for
+// concurrent use.
+type healthTracker struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	models map[string]*modelHealth
+
+	// lastModel/lastAt identify the most recent request across all models,
+	// used for watchdog recovery diagnostics.
+	lastModel string
+	lastAt    time.Time
+}
+
+// newHealthTracker creates a tracker with the given rolling window.
+func newHealthTracker(windowMinutes int) *healthTracker {
+	if windowMinutes <= 0 {
+		windowMinutes = 5
+	}
+	return &healthTracker{
+		window: time.Duration(windowMinutes) * time.Minute,
+		models: make(map[string]*modelHealth),
+	}
+}
+
+// Record registers the outcome of one request against a model. Safe to call
+// on a nil tracker (clients built without NewLLMClient, e.g. in tests).
+func (h *healthTracker) Record(model string, latency time.Duration, err error) {
+	if h == nil {
+		return
+	}
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	mh, ok := h.models[model]
+	if !ok {
+		mh = &modelHealth{}
+		h.models[model] = mh
+	}
+
+	mh.requests++
+	if err != nil {
+		mh.errors++
+		mh.lastError = truncate(err.Error(), 200)
+		mh.lastErrorAt = now
+	}
+
+	mh.samples = append(mh.samples, latencySample{at: now, latency: latency})
+	pruneSamples(mh, now, h.window)
+
+	h.lastModel = model
+	h.lastAt = now
+}
+
+// LastCall returns the model and completion time of the most recent request,
+// or ("", zero) when none was recorded yet.
+func (h *healthTracker) LastCall() (string, time.Time) {
+	if h == nil {
+		return "", time.Time{}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastModel, h.lastAt
+}
+
+// P95 returns the p95 latency over the window and the number of samples it
+// is based on.
+func (h *healthTracker) P95(model string) (time.Duration, int) {
+	if h == nil {
+		return 0, 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	mh, ok := h.models[model]
+	if !ok {
+		return 0, 0
+	}
+	pruneSamples(mh, time.Now(), h.window)
+	return latencyPercentile(windowLatencies(mh), 95), len(mh.samples)
+}
+
+// ModelHealthStats is a point-in-time health snapshot for one model.
+// Request and error counts are totals since client start; the latency
+// percentiles cover only the rolling health window.
+type ModelHealthStats struct {
+	Model         string
+	Requests      int
+	Errors        int
+	ErrorRate     float64
+	MedianLatency time.Duration
+	P95Latency    time.Duration
+	LastError     string
+	LastErrorAt   time.Time
+}
+
+// Stats returns health snapshots for all models, sorted by model name.
+func (h *healthTracker) Stats() []ModelHealthStats {
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := make([]ModelHealthStats, 0, len(h.models))
+	for model, mh := range h.models {
+		pruneSamples(mh, now, h.window)
+		latencies := windowLatencies(mh)
+		s := ModelHealthStats{
+			Model:         model,
+			Requests:      mh.requests,
+			Errors:        mh.errors,
+			MedianLatency: latencyPercentile(latencies, 50),
+			P95Latency:    latencyPercentile(latencies, 95),
+			LastError:     mh.lastError,
+			LastErrorAt:   mh.lastErrorAt,
+		}
+		if mh.requests > 0 {
+			s.ErrorRate = float64(mh.errors) / float64(mh.requests)
+		}
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b ModelHealthStats) int {
+		return strings.Compare(a.Model, b.Model)
+	})
+	return out
+}
+
+// pruneSamples drops samples outside the window and enforces the size cap.
+// Caller must hold h.mu.
+func pruneSamples(mh *modelHealth, now time.Time, window time.Duration) {
+	cutoff := now.Add(-window)
+	keep := 0
+	for keep < len(mh.samples) && mh.samples[keep].at.Before(cutoff) {
+		keep++
+	}
+	if keep > 0 {
+		mh.samples = append(mh.samples[:0], mh.samples[keep:]...)
+	}
+	if len(mh.samples) > maxLatencySamples {
+		mh.samples = append(mh.samples[:0], mh.samples[len(mh.samples)-maxLatencySamples:]...)
+	}
+}
+
+// windowLatencies extracts the raw latencies from the current window.
+func windowLatencies(mh *modelHealth) []time.Duration {
+	out := make([]time.Duration, len(mh.samples))
+	for i, s := range mh.samples {
+		out[i] = s.latency
+	}
+	return out
+}
+
+// latencyPercentile computes the p-th percentile (nearest-rank) of latencies.
+func latencyPercentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	idx := int(float64(len(sorted))*p/100+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// formatLatency renders a latency for status output ("-" when unknown).
+func formatLatency(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// HealthStats returns per-model health snapshots for status reporting.
+func (c *LLMClient) HealthStats() []ModelHealthStats {
+	if c.health == nil {
+		return nil
+	}
+	return c.health.Stats()
+}
+
+// LastCall returns the model and completion time of the client's most
+// recent request.
+func (c *LLMClient) LastCall() (model string, at time.Time) {
+	return c.health.LastCall()
+}
+
+// CooldownStatus returns the rate-limited model and when its cooldown
+// expires. An empty model means no cooldown is active.
+func (c *LLMClient) CooldownStatus() (model string, until time.Time) {
+	c.cooldownMu.Lock()
+	defer c.cooldownMu.Unlock()
+	if c.cooldownModel == "" || time.Now().After(c.cooldownExpires) {
+		return "", time.Time{}
+	}
+	return c.cooldownModel, c.cooldownExpires
+}
+
+// primaryDegraded reports whether the latency-based failover policy should
+// prefer fallback models over the primary: the primary's p95 latency across
+// the health window exceeds FallbackConfig.PreferFallbackP95Seconds.
+func (c *LLMClient) primaryDegraded(model string) bool {
+	threshold := c.fallback.PreferFallbackP95Seconds
+	if threshold <= 0 || c.health == nil {
+		return false
+	}
+	p95, samples := c.health.P95(model)
+	if samples < minPolicySamples {
+		return false
+	}
+	return p95 > time.Duration(threshold)*time.Second
+}
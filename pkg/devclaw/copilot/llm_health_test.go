@@ -0,0 +1,121 @@
+package copilot
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestHealthTrackerRecord(t *testing.T) {
+	h := newHealthTracker(5)
+	h.Record("gpt-4o", 100*time.Millisecond, nil)
+	h.Record("gpt-4o", 200*time.Millisecond, nil)
+	h.Record("gpt-4o", 300*time.Millisecond, errors.New("boom"))
+	h.Record("glm-5", 50*time.Millisecond, nil)
+
+	stats := h.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("expected stats for 2 models, got %d", len(stats))
+	}
+	// Sorted by model name.
+	if stats[0].Model != "glm-5" || stats[1].Model != "gpt-4o" {
+		t.Fatalf("expected sorted models, got %v, %v", stats[0].Model, stats[1].Model)
+	}
+
+	s := stats[1]
+	if s.Requests != 3 || s.Errors != 1 {
+		t.Errorf("expected 3 requests / 1 error, got %d / %d", s.Requests, s.Errors)
+	}
+	if s.ErrorRate < 0.3 || s.ErrorRate > 0.4 {
+		t.Errorf("expected error rate ~0.33, got %f", s.ErrorRate)
+	}
+	if s.MedianLatency != 200*time.Millisecond {
+		t.Errorf("expected median 200ms, got %v", s.MedianLatency)
+	}
+	if s.LastError != "boom" {
+		t.Errorf("expected last error recorded, got %q", s.LastError)
+	}
+
+	// Nil tracker is a no-op (clients built without NewLLMClient).
+	var nilTracker *healthTracker
+	nilTracker.Record("gpt-4o", time.Second, nil)
+	if p95, n := nilTracker.P95("gpt-4o"); p95 != 0 || n != 0 {
+		t.Errorf("nil tracker should report zero stats, got %v / %d", p95, n)
+	}
+}
+
+func TestHealthTrackerWindowPrune(t *testing.T) {
+	h := newHealthTracker(5)
+	h.window = 20 * time.Millisecond
+
+	h.Record("gpt-4o", time.Second, nil)
+	time.Sleep(40 * time.Millisecond)
+	h.Record("gpt-4o", 100*time.Millisecond, nil)
+
+	// The old sample fell out of the window; totals are kept.
+	if p95, n := h.P95("gpt-4o"); n != 1 || p95 != 100*time.Millisecond {
+		t.Errorf("expected 1 windowed sample with p95 100ms, got %d / %v", n, p95)
+	}
+	if stats := h.Stats(); stats[0].Requests != 2 {
+		t.Errorf("request total should survive pruning, got %d", stats[0].Requests)
+	}
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	latencies := []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		30 * time.Millisecond,
+		40 * time.Millisecond,
+		100 * time.Millisecond,
+	}
+	if got := latencyPercentile(latencies, 50); got != 30*time.Millisecond {
+		t.Errorf("expected median 30ms, got %v", got)
+	}
+	if got := latencyPercentile(latencies, 95); got != 100*time.Millisecond {
+		t.Errorf("expected p95 100ms, got %v", got)
+	}
+	if got := latencyPercentile(nil, 95); got != 0 {
+		t.Errorf("expected 0 for no samples, got %v", got)
+	}
+}
+
+func TestPrimaryDegradedPolicy(t *testing.T) {
+	cfg := &Config{Model: "gpt-4o"}
+	cfg.Fallback = FallbackConfig{
+		Models:                   []string{"glm-5"},
+		PreferFallbackP95Seconds: 1,
+	}
+	client := NewLLMClient(cfg, slog.Default())
+
+	// Too few samples: policy stays off even with slow requests.
+	client.health.Record("gpt-4o", 5*time.Second, nil)
+	if client.primaryDegraded("gpt-4o") {
+		t.Error("policy should require a minimum number of samples")
+	}
+
+	client.health.Record("gpt-4o", 5*time.Second, nil)
+	client.health.Record("gpt-4o", 5*time.Second, nil)
+	if !client.primaryDegraded("gpt-4o") {
+		t.Error("expected primary degraded with p95 above threshold")
+	}
+
+	// Fast primary is never degraded.
+	fast := NewLLMClient(cfg, slog.Default())
+	for i := 0; i < 5; i++ {
+		fast.health.Record("gpt-4o", 100*time.Millisecond, nil)
+	}
+	if fast.primaryDegraded("gpt-4o") {
+		t.Error("fast primary should not be degraded")
+	}
+
+	// Threshold 0 disables the policy entirely.
+	off := NewLLMClient(&Config{Model: "gpt-4o"}, slog.Default())
+	for i := 0; i < 5; i++ {
+		off.health.Record("gpt-4o", time.Minute, nil)
+	}
+	if off.primaryDegraded("gpt-4o") {
+		t.Error("policy must be disabled when no threshold is configured")
+	}
+}
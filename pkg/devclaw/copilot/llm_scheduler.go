@@ -0,0 +1,264 @@
+// Package copilot – llm_scheduler.go implements a global LLM request
+// scheduler. Without it, every agent run hits the provider independently and
+// concurrent sessions trigger 429 storms. The scheduler coordinates all
+// requests through per-provider concurrency limits and a tokens-per-minute
+// budget, with fair queuing across sessions so one busy chat cannot starve
+// the others. Rate-limit cooldowns feed back into the queue as coordinated
+// backpressure instead of each client backing off on its own.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// RequestQueueConfig configures the global LLM request scheduler.
+type RequestQueueConfig struct {
+	// Enabled turns global request scheduling on (default: off).
+	Enabled bool `yaml:"enabled"`
+
+	// MaxConcurrent is the per-provider limit on in-flight requests
+	// (default: 4).
+	MaxConcurrent int `yaml:"max_concurrent"`
+
+	// TokensPerMinute is the per-provider token budget per rolling minute,
+	// estimated from prompt size (0 = unlimited).
+	TokensPerMinute int `yaml:"tokens_per_minute"`
+}
+
+// Effective returns a copy with default values filled in for zero fields.
+func (c RequestQueueConfig) Effective() RequestQueueConfig {
+	out := c
+	if out.MaxConcurrent <= 0 {
+		out.MaxConcurrent = 4
+	}
+	return out
+}
+
+// requestWaiter is one queued request waiting for admission.
+type requestWaiter struct {
+	session string
+	tokens  int
+	ready   chan struct{}
+}
+
+// providerQueue tracks in-flight requests, the token window, and queued
+// waiters for one provider.
+type providerQueue struct {
+	inFlight     int
+	windowStart  time.Time
+	windowTokens int
+	backoffUntil time.Time
+	lastSession  string // For round-robin fairness across sessions.
+	waiters      []*requestWaiter
+	pumpTimer    *time.Timer
+}
+
+// RequestScheduler coordinates LLM requests across all sessions.
+type RequestScheduler struct {
+	cfg    RequestQueueConfig
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	providers map[string]*providerQueue
+}
+
+// NewRequestScheduler creates a scheduler from config.
+func NewRequestScheduler(cfg RequestQueueConfig, logger *slog.Logger) *RequestScheduler {
+	return &RequestScheduler{
+		cfg:       cfg.Effective(),
+		logger:    logger.With("component", "llm_scheduler"),
+		providers: make(map[string]*providerQueue),
+	}
+}
+
+// Acquire blocks until a request slot and token budget are available for the
+// provider, or the context is done. Returns a release function that MUST be
+// called when the request finishes (it is safe to call more than once).
+func (s *RequestScheduler) Acquire(ctx context.Context, provider, sessionID string, estTokens int) (func(), error) {
+	s.mu.Lock()
+	q := s.queueFor(provider)
+
+	// Fast path: no queue and directly admissible.
+	if len(q.waiters) == 0 && s.admitLocked(q, sessionID, estTokens) {
+		s.mu.Unlock()
+		return s.releaseFunc(provider), nil
+	}
+
+	w := &requestWaiter{session: sessionID, tokens: estTokens, ready: make(chan struct{})}
+	q.waiters = append(q.waiters, w)
+	s.logger.Debug("llm request queued",
+		"provider", provider,
+		"session", sessionID,
+		"queue_len", len(q.waiters),
+	)
+	s.schedulePumpLocked(provider, q)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return s.releaseFunc(provider), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, queued := range q.waiters {
+			if queued == w {
+				q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+				return nil, fmt.Errorf("waiting for llm request slot: %w", ctx.Err())
+			}
+		}
+		// Admitted concurrently with cancellation: free the slot again.
+		q.inFlight--
+		s.pumpLocked(provider, q)
+		return nil, fmt.Errorf("waiting for llm request slot: %w", ctx.Err())
+	}
+}
+
+// Backoff pauses admissions for the provider, typically after a 429. The
+// pause extends an existing backoff, never shortens it.
+func (s *RequestScheduler) Backoff(provider string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.queueFor(provider)
+	until := time.Now().Add(d)
+	if until.After(q.backoffUntil) {
+		q.backoffUntil = until
+		s.logger.Info("provider backoff engaged",
+			"provider", provider,
+			"seconds", int(d.Seconds()),
+			"queued", len(q.waiters),
+		)
+	}
+	s.schedulePumpLocked(provider, q)
+}
+
+// QueueDepth returns the number of queued requests for a provider.
+func (s *RequestScheduler) QueueDepth(provider string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if q, ok := s.providers[provider]; ok {
+		return len(q.waiters)
+	}
+	return 0
+}
+
+// queueFor returns the provider's queue, creating it on first use.
+// Caller must hold s.mu.
+func (s *RequestScheduler) queueFor(provider string) *providerQueue {
+	q, ok := s.providers[provider]
+	if !ok {
+		q = &providerQueue{windowStart: time.Now()}
+		s.providers[provider] = q
+	}
+	return q
+}
+
+// admitLocked checks all limits and, on success, reserves the slot and token
+// budget. Caller must hold s.mu.
+func (s *RequestScheduler) admitLocked(q *providerQueue, sessionID string, estTokens int) bool {
+	now := time.Now()
+	if now.Before(q.backoffUntil) {
+		return false
+	}
+	if q.inFlight >= s.cfg.MaxConcurrent {
+		return false
+	}
+	if s.cfg.TokensPerMinute > 0 {
+		// Rotate the budget window once a minute.
+		if now.Sub(q.windowStart) >= time.Minute {
+			q.windowStart = now
+			q.windowTokens = 0
+		}
+		// Always admit at least one request per window, even when a single
+		// prompt exceeds the whole budget — it would otherwise queue forever.
+		if q.windowTokens > 0 && q.windowTokens+estTokens > s.cfg.TokensPerMinute {
+			return false
+		}
+	}
+
+	q.inFlight++
+	q.windowTokens += estTokens
+	q.lastSession = sessionID
+	return true
+}
+
+// pumpLocked admits queued waiters in fair order: the first waiter from a
+// session other than the last admitted one is preferred, falling back to
+// strict FIFO. Caller must hold s.mu.
+func (s *RequestScheduler) pumpLocked(provider string, q *providerQueue) {
+	for len(q.waiters) > 0 {
+		idx := 0
+		for i, w := range q.waiters {
+			if w.session != q.lastSession {
+				idx = i
+				break
+			}
+		}
+		w := q.waiters[idx]
+		if !s.admitLocked(q, w.session, w.tokens) {
+			s.schedulePumpLocked(provider, q)
+			return
+		}
+		q.waiters = append(q.waiters[:idx], q.waiters[idx+1:]...)
+		close(w.ready)
+	}
+}
+
+// schedulePumpLocked arms a timer to re-run the pump when a time-based limit
+// (token window, backoff) expires. Concurrency releases pump directly.
+// Caller must hold s.mu.
+func (s *RequestScheduler) schedulePumpLocked(provider string, q *providerQueue) {
+	if len(q.waiters) == 0 || q.pumpTimer != nil {
+		return
+	}
+	now := time.Now()
+	var wake time.Time
+	if now.Before(q.backoffUntil) {
+		wake = q.backoffUntil
+	} else if s.cfg.TokensPerMinute > 0 && q.windowTokens > 0 {
+		wake = q.windowStart.Add(time.Minute)
+	} else {
+		return // Blocked on concurrency only; a release will pump.
+	}
+	q.pumpTimer = time.AfterFunc(time.Until(wake)+10*time.Millisecond, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		q.pumpTimer = nil
+		s.pumpLocked(provider, q)
+	})
+}
+
+// releaseFunc returns the idempotent release closure handed to callers.
+func (s *RequestScheduler) releaseFunc(provider string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			q := s.queueFor(provider)
+			q.inFlight--
+			s.pumpLocked(provider, q)
+		})
+	}
+}
+
+// estimateMessagesTokens approximates the token count of a request payload.
+// Uses 4 chars per token, matching estimateTokens in prompt_layers.go.
+func estimateMessagesTokens(messages []chatMessage) int {
+	chars := 0
+	for _, m := range messages {
+		chars += len(m.Role)
+		if s, ok := m.Content.(string); ok {
+			chars += len(s)
+		} else if m.Content != nil {
+			chars += len(fmt.Sprintf("%v", m.Content))
+		}
+	}
+	return chars / 4
+}
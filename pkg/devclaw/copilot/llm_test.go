@@ -1,6 +1,7 @@
 package copilot
 
 import (
+	"log/slog"
 	"testing"
 	"time"
 )
@@ -180,3 +181,19 @@ func TestNormalizeGeminiModelID(t *testing.T) {
 		})
 	}
 }
+
+func TestUtilityModelWiring(t *testing.T) {
+	cfg := &Config{Model: "gpt-4o"}
+	cfg.Models.Utility = "gpt-4o-mini"
+
+	client := NewLLMClient(cfg, slog.Default())
+	if client.UtilityModel() != "gpt-4o-mini" {
+		t.Errorf("expected configured utility model, got %q", client.UtilityModel())
+	}
+
+	// Unset utility model means internal calls use the main model.
+	plain := NewLLMClient(&Config{Model: "gpt-4o"}, slog.Default())
+	if plain.UtilityModel() != "" {
+		t.Errorf("expected empty utility model, got %q", plain.UtilityModel())
+	}
+}
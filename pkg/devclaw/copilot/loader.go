@@ -58,6 +58,11 @@ func LoadConfigFromFile(path string) (*Config, error) {
 	// Resolve relative paths based on config file location.
 	resolveRelativePaths(cfg, path)
 
+	// Validate instruction templates so malformed prompts fail at startup.
+	if err := ValidatePromptTemplates(cfg); err != nil {
+		return nil, fmt.Errorf("validating prompt templates: %w", err)
+	}
+
 	// Check file permissions and warn if too open.
 	checkFilePermissions(path)
 
@@ -15,12 +15,46 @@ import (
 )
 
 // extractDocumentText extracts readable text from a document based on MIME type.
-// Supports PDF (via pdftotext), plain text, and common text formats.
+// Supports PDF (via pdftotext), plain text, common text formats, and zip/tar
+// archives. The declared MIME type is never trusted alone — magic bytes are
+// sniffed first, so a mislabeled PDF or zip still extracts correctly.
 // Returns empty string if extraction fails or format is unsupported.
 func extractDocumentText(data []byte, mimeType, filename string, logger *slog.Logger) string {
 	mime := strings.ToLower(mimeType)
 	ext := strings.ToLower(filepath.Ext(filename))
 
+	// DOCX first — it is a zip container, but gets dedicated handling.
+	if mime == "application/vnd.openxmlformats-officedocument.wordprocessingml.document" || ext == ".docx" {
+		return extractDOCXText(data, logger)
+	}
+
+	// Spreadsheets (XLSX/ODS) are also zip containers.
+	if mime == "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" ||
+		mime == "application/vnd.oasis.opendocument.spreadsheet" ||
+		ext == ".xlsx" || ext == ".ods" {
+		return extractSpreadsheetText(data, logger)
+	}
+
+	// Magic-byte sniffing overrides the declared type.
+	if bytes.HasPrefix(data, []byte("%PDF")) {
+		return extractPDFText(data, logger)
+	}
+	if sniffArchiveType(data) == "zip" && zipLooksLikeSpreadsheet(data) {
+		return extractSpreadsheetText(data, logger)
+	}
+	if sniffArchiveType(data) != "" {
+		return extractArchiveText(data, filename, logger)
+	}
+
+	// Patches render as structured hunks; source files get syntax-aware
+	// handling (language fencing, outline for huge files).
+	if isDiffAttachment(data, ext) {
+		return extractDiffText(data, filename, logger)
+	}
+	if detectCodeLanguage(filename) != "" {
+		return extractCodeText(data, filename, logger)
+	}
+
 	// Plain text formats — return directly.
 	if isPlainText(mime, ext) {
 		return string(data)
@@ -31,11 +65,6 @@ func extractDocumentText(data []byte, mimeType, filename string, logger *slog.Lo
 		return extractPDFText(data, logger)
 	}
 
-	// DOCX — extract from XML inside the zip.
-	if mime == "application/vnd.openxmlformats-officedocument.wordprocessingml.document" || ext == ".docx" {
-		return extractDOCXText(data, logger)
-	}
-
 	logger.Debug("unsupported document format", "mime", mimeType, "ext", ext)
 	return ""
 }
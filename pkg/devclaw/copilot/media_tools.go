@@ -147,7 +147,17 @@ func registerTranscribeAudioTool(executor *ToolExecutor, llm *LLMClient, media M
 				"filename", filename,
 			)
 
-			transcript, err := llm.TranscribeAudio(ctx, decoded, filename, media.TranscriptionModel, media)
+			// Detect the spoken language for a dynamic hint (see audio_lid.go).
+			cfg := media
+			if cfg.TranscriptionLanguageDetect && cfg.TranscriptionLanguage == "" {
+				if lang, err := llm.DetectAudioLanguage(ctx, decoded, filename, cfg); err != nil {
+					logger.Debug("audio language detection failed", "error", err)
+				} else if lang != "" {
+					cfg.TranscriptionLanguage = lang
+				}
+			}
+
+			transcript, err := llm.TranscribeAudio(ctx, decoded, filename, cfg.TranscriptionModel, cfg)
 			if err != nil {
 				logger.Error("transcription failed", "error", err)
 				return nil, fmt.Errorf("transcription: %w", err)
@@ -0,0 +1,398 @@
+// Package copilot – meeting_notes.go implements a dedicated pipeline for
+// long audio such as forwarded meeting recordings: chunked transcription via
+// ffmpeg segmentation, speaker-agnostic map-reduce summarization, action-item
+// extraction into the chat's shared "tasks" list, and the full transcript
+// saved as a memory document — instead of dumping a giant transcript into
+// one prompt.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot/memory"
+)
+
+const (
+	// meetingSegmentSeconds is the length of one transcription segment.
+	meetingSegmentSeconds = 600
+
+	// meetingSegmentThreshold is the recording duration above which audio is
+	// split into segments before transcription. Shorter audio goes to the
+	// transcription API in one call, same as before.
+	meetingSegmentThreshold = 900.0
+
+	// meetingTranscriptMinChars is the transcript length above which the
+	// meeting-notes pipeline kicks in. Shorter transcripts are inlined
+	// verbatim, as for any voice note.
+	meetingTranscriptMinChars = 6000
+
+	// meetingTasksList is the shared list that receives extracted action
+	// items, visible via list_show.
+	meetingTasksList = "tasks"
+
+	meetingMapSystemPrompt = "You summarize sections of a meeting transcript faithfully. The transcript has no speaker labels — do not invent names; write speaker-agnostically ('it was decided', 'one participant raised'). Preserve decisions, numbers, dates, deadlines, and open questions. Be concise but complete."
+)
+
+// transcribeAudioChunked transcribes audio, splitting long recordings into
+// segments first so each transcription call stays within API limits. Falls
+// back to a single call when ffmpeg/ffprobe are unavailable or the recording
+// is short.
+func (a *Assistant) transcribeAudioChunked(ctx context.Context, data []byte, filename string, mediaCfg MediaConfig, logger *slog.Logger) (string, error) {
+	// Detect the spoken language once up front so every segment gets the
+	// same dynamic hint (see audio_lid.go).
+	mediaCfg = a.detectTranscriptionLanguage(ctx, data, filename, mediaCfg)
+
+	duration := audioDurationSeconds(data, filename, logger)
+	if duration < meetingSegmentThreshold {
+		return a.llmClient.TranscribeAudio(ctx, data, filename, mediaCfg.TranscriptionModel, mediaCfg)
+	}
+
+	segments, err := splitAudioSegments(data, filename, logger)
+	if err != nil || len(segments) <= 1 {
+		if err != nil {
+			logger.Warn("audio segmentation failed, transcribing in one call", "error", err)
+		}
+		return a.llmClient.TranscribeAudio(ctx, data, filename, mediaCfg.TranscriptionModel, mediaCfg)
+	}
+
+	logger.Info("transcribing long audio in segments",
+		"duration_s", int(duration),
+		"segments", len(segments))
+
+	var parts []string
+	for i, seg := range segments {
+		part, err := a.llmClient.TranscribeAudio(ctx, seg, filename, mediaCfg.TranscriptionModel, mediaCfg)
+		if err != nil {
+			return "", fmt.Errorf("transcribing segment %d/%d: %w", i+1, len(segments), err)
+		}
+		parts = append(parts, strings.TrimSpace(part))
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// audioDurationSeconds probes the recording length via ffprobe. Returns 0
+// when ffprobe is unavailable or probing fails, which disables segmentation.
+func audioDurationSeconds(data []byte, filename string, logger *slog.Logger) float64 {
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		return 0
+	}
+
+	tmp, err := os.CreateTemp("", "devclaw-audio-*"+audioTempExt(filename))
+	if err != nil {
+		return 0
+	}
+	defer os.Remove(tmp.Name())
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		tmp.Close()
+		return 0
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return 0
+	}
+	tmp.Close()
+
+	out, err := exec.Command("ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		tmp.Name(),
+	).Output()
+	if err != nil {
+		logger.Debug("ffprobe failed", "error", err)
+		return 0
+	}
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+// splitAudioSegments splits audio into fixed-length segments with ffmpeg
+// (stream copy, no re-encode) and returns them in order.
+func splitAudioSegments(data []byte, filename string, logger *slog.Logger) ([][]byte, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, fmt.Errorf("ffmpeg not found")
+	}
+
+	ext := audioTempExt(filename)
+	tmpIn, err := os.CreateTemp("", "devclaw-audio-*"+ext)
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %w", err)
+	}
+	defer os.Remove(tmpIn.Name())
+	if err := os.Chmod(tmpIn.Name(), 0o600); err != nil {
+		tmpIn.Close()
+		return nil, fmt.Errorf("restricting temp file: %w", err)
+	}
+	if _, err := tmpIn.Write(data); err != nil {
+		tmpIn.Close()
+		return nil, fmt.Errorf("writing temp file: %w", err)
+	}
+	tmpIn.Close()
+
+	// Segments land in a private temp directory; audio may be sensitive.
+	segDir, err := os.MkdirTemp("", "devclaw-segments-")
+	if err != nil {
+		return nil, fmt.Errorf("creating segment directory: %w", err)
+	}
+	defer os.RemoveAll(segDir)
+	if err := os.Chmod(segDir, 0o700); err != nil {
+		return nil, fmt.Errorf("restricting segment directory: %w", err)
+	}
+
+	cmd := exec.Command("ffmpeg",
+		"-i", tmpIn.Name(),
+		"-f", "segment",
+		"-segment_time", strconv.Itoa(meetingSegmentSeconds),
+		"-c", "copy",
+		"-y",
+		filepath.Join(segDir, "segment-%03d"+ext),
+	)
+	cmd.Stderr = nil
+	cmd.Stdout = nil
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg segmentation: %w", err)
+	}
+
+	entries, err := os.ReadDir(segDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading segments: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var segments [][]byte
+	for _, name := range names {
+		seg, err := os.ReadFile(filepath.Join(segDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading segment %s: %w", name, err)
+		}
+		segments = append(segments, seg)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("ffmpeg produced no segments")
+	}
+	logger.Debug("audio segmented", "segments", len(segments))
+	return segments, nil
+}
+
+// audioTempExt picks a temp-file extension ffmpeg can infer a format from.
+func audioTempExt(filename string) string {
+	if ext := filepath.Ext(filename); ext != "" {
+		return ext
+	}
+	return ".ogg"
+}
+
+// processMeetingTranscript runs the meeting-notes pipeline on a long
+// transcript: summarize, extract action items into the chat's shared "tasks"
+// list, and save the full transcript as a memory document. Returns the
+// enriched content for the agent, or "" when the pipeline fails (the caller
+// falls back to inlining the raw transcript).
+func (a *Assistant) processMeetingTranscript(ctx context.Context, msg *channels.IncomingMessage, transcript string, logger *slog.Logger) string {
+	summary, err := a.summarizeMeetingTranscript(ctx, transcript, logger)
+	if err != nil {
+		logger.Warn("meeting summarization failed", "error", err)
+		return ""
+	}
+
+	actions, err := a.extractMeetingActionItems(ctx, transcript)
+	if err != nil {
+		logger.Warn("action-item extraction failed", "error", err)
+		actions = nil // Summary alone is still worth delivering.
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Meeting recording transcribed — %d characters. Summary below; the full transcript was saved to memory.]\n\n%s",
+		len(transcript), strings.TrimSpace(summary))
+
+	if len(actions) > 0 {
+		b.WriteString("\n\nAction items")
+		added := 0
+		if a.skillDB != nil && msg.ChatID != "" {
+			for _, item := range actions {
+				if _, err := a.skillDB.AddListItem(msg.ChatID, meetingTasksList, item, "", "meeting-notes"); err != nil {
+					logger.Warn("failed to add action item to tasks list", "error", err)
+					continue
+				}
+				added++
+			}
+		}
+		if added > 0 {
+			fmt.Fprintf(&b, " (added to the %q list)", meetingTasksList)
+		}
+		b.WriteString(":")
+		for _, item := range actions {
+			fmt.Fprintf(&b, "\n- %s", item)
+		}
+	}
+
+	if path := a.saveMeetingTranscript(summary, transcript, logger); path != "" {
+		fmt.Fprintf(&b, "\n\nFull transcript: %s", path)
+	}
+
+	if msg.Content != "" {
+		content := msg.Content
+		content = strings.ReplaceAll(content, "[audio]", "")
+		content = strings.ReplaceAll(content, "[voice note]", "")
+		if content = strings.TrimSpace(content); content != "" {
+			fmt.Fprintf(&b, "\n\n%s", content)
+		}
+	}
+	return b.String()
+}
+
+// summarizeMeetingTranscript produces a speaker-agnostic map-reduce summary
+// of a meeting transcript, mirroring the long-document summarizer.
+func (a *Assistant) summarizeMeetingTranscript(ctx context.Context, transcript string, logger *slog.Logger) (string, error) {
+	if a.llmClient == nil {
+		return "", fmt.Errorf("no LLM client available")
+	}
+
+	chunks := chunkDocumentText(transcript, docSummaryChunkSize)
+	model := a.MediaConfig().SummaryModel
+	if model == "" {
+		model = a.llmClient.UtilityModel()
+	}
+	logger.Info("summarizing meeting transcript", "chars", len(transcript), "chunks", len(chunks), "model", model)
+
+	summaries := make([]string, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, docSummaryMaxParallel)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			summary, err := a.completeForSummary(ctx, model,
+				meetingMapSystemPrompt,
+				fmt.Sprintf("Part %d of %d of a meeting transcript:\n\n%s", i+1, len(chunks), chunk))
+			if err != nil {
+				errs[i] = fmt.Errorf("part %d/%d: %w", i+1, len(chunks), err)
+				return
+			}
+			summaries[i] = summary
+		}(i, chunk)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return "", fmt.Errorf("map phase: %w", err)
+		}
+	}
+
+	if len(summaries) == 1 {
+		return summaries[0], nil
+	}
+
+	var b strings.Builder
+	for i, s := range summaries {
+		b.WriteString(fmt.Sprintf("## Part %d\n%s\n\n", i+1, s))
+	}
+	final, err := a.completeForSummary(ctx, model,
+		"You combine partial summaries of one meeting into a single summary with sections: key points, decisions, and open questions. Stay speaker-agnostic, merge overlapping points, and keep all dates, numbers, and deadlines.",
+		fmt.Sprintf("Partial summaries of the meeting:\n\n%s", b.String()))
+	if err != nil {
+		return "", fmt.Errorf("reduce phase: %w", err)
+	}
+	return final, nil
+}
+
+// extractMeetingActionItems asks the model for concrete action items, one
+// per line, and parses them into a slice.
+func (a *Assistant) extractMeetingActionItems(ctx context.Context, transcript string) ([]string, error) {
+	model := a.MediaConfig().SummaryModel
+	if model == "" {
+		model = a.llmClient.UtilityModel()
+	}
+
+	// Action items are usually agreed near the end; a bounded excerpt keeps
+	// this call cheap even for multi-hour meetings.
+	excerpt := transcript
+	const maxActionInput = 3 * docSummaryChunkSize
+	if len(excerpt) > maxActionInput {
+		excerpt = excerpt[:docSummaryChunkSize] + "\n...\n" + excerpt[len(excerpt)-2*docSummaryChunkSize:]
+	}
+
+	out, err := a.completeForSummary(ctx, model,
+		"You extract action items from meeting transcripts. Output one concrete, self-contained action per line, starting with '- '. Include owners and deadlines only when the transcript states them. If there are no action items, output exactly NONE.",
+		excerpt)
+	if err != nil {
+		return nil, err
+	}
+	return parseMeetingActionItems(out), nil
+}
+
+// parseMeetingActionItems parses '- item' lines from model output.
+func parseMeetingActionItems(out string) []string {
+	var items []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "- ")
+		line = strings.TrimPrefix(line, "* ")
+		line = strings.TrimSpace(line)
+		if line == "" || strings.EqualFold(line, "NONE") {
+			continue
+		}
+		items = append(items, line)
+	}
+	return items
+}
+
+// saveMeetingTranscript writes the full transcript to the memory directory
+// as YYYY-MM-DD-slug.md and re-indexes it, so memory search can find it
+// later. Returns the file path, or "" on failure.
+func (a *Assistant) saveMeetingTranscript(summary, transcript string, logger *slog.Logger) string {
+	slug := generateSlug(summary, 5)
+	if slug == "" {
+		slug = "meeting"
+	}
+	now := time.Now()
+	filename := fmt.Sprintf("%s-meeting-%s.md", now.Format("2006-01-02"), slug)
+
+	memDir := filepath.Join(filepath.Dir(a.config.Memory.Path), "memory")
+	if err := os.MkdirAll(memDir, 0o755); err != nil {
+		logger.Warn("failed to create memory directory", "error", err)
+		return ""
+	}
+
+	content := fmt.Sprintf("# Meeting — %s\n\n## Summary\n\n%s\n\n## Transcript\n\n%s\n",
+		now.Format("2006-01-02 15:04"), strings.TrimSpace(summary), strings.TrimSpace(transcript))
+
+	path := filepath.Join(memDir, filename)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		logger.Warn("failed to save meeting transcript", "path", path, "error", err)
+		return ""
+	}
+	logger.Info("meeting transcript saved", "path", path, "chars", len(transcript))
+
+	if a.sqliteMemory != nil && a.config.Memory.Index.Auto {
+		chunkCfg := memory.ChunkConfig{MaxTokens: a.config.Memory.Index.ChunkMaxTokens, Overlap: 100}
+		if chunkCfg.MaxTokens <= 0 {
+			chunkCfg.MaxTokens = 500
+		}
+		_ = a.sqliteMemory.IndexMemoryDir(a.ctx, memDir, chunkCfg)
+	}
+	return path
+}
@@ -0,0 +1,49 @@
+// Package copilot – meeting_notes_test.go contains unit tests for the
+// meeting-notes pipeline helpers.
+package copilot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMeetingActionItems(t *testing.T) {
+	out := `- Send the updated proposal by Friday
+* Book the follow-up call for next week
+
+- NONE of the budget items were approved`
+	got := parseMeetingActionItems(out)
+	want := []string{
+		"Send the updated proposal by Friday",
+		"Book the follow-up call for next week",
+		"NONE of the budget items were approved",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMeetingActionItems = %#v, want %#v", got, want)
+	}
+
+	if got := parseMeetingActionItems("NONE"); got != nil {
+		t.Errorf("expected no items for NONE, got %#v", got)
+	}
+	if got := parseMeetingActionItems("  none  \n\n"); got != nil {
+		t.Errorf("expected no items for lowercase none, got %#v", got)
+	}
+}
+
+func TestAudioTempExt(t *testing.T) {
+	cases := []struct {
+		filename string
+		want     string
+	}{
+		{"meeting.mp3", ".mp3"},
+		{"voice.ogg", ".ogg"},
+		{"recording.m4a", ".m4a"},
+		{"audio", ".ogg"},
+		{"", ".ogg"},
+	}
+	for _, tc := range cases {
+		if got := audioTempExt(tc.filename); got != tc.want {
+			t.Errorf("audioTempExt(%q) = %q, want %q", tc.filename, got, tc.want)
+		}
+	}
+}
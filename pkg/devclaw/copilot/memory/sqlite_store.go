@@ -888,6 +888,12 @@ func (s *SQLiteStore) Close() error {
 	return s.db.Close()
 }
 
+// DB exposes the underlying connection for maintenance tasks (VACUUM,
+// WAL checkpoint, integrity check).
+func (s *SQLiteStore) DB() *sql.DB {
+	return s.db
+}
+
 // ChunkCount returns the total number of indexed chunks.
 func (s *SQLiteStore) ChunkCount() int {
 	var count int
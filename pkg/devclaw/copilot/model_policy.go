@@ -0,0 +1,110 @@
+// Package copilot – model_policy.go implements per-workspace model and
+// provider allowlists. Enterprises use these for data-residency constraints:
+// a workspace handling sensitive data can forbid providers entirely, and the
+// LLM client drops forbidden models from its fallback chain so no request
+// ever reaches them. Violations are logged and surfaced to the admin.
+package copilot
+
+import (
+	"context"
+	"strings"
+)
+
+// ModelPolicyConfig restricts which models and providers a workspace may use.
+// Empty lists impose no restriction of that kind.
+type ModelPolicyConfig struct {
+	// AllowedProviders lists the only providers this workspace may send data
+	// to (e.g. "openai", "anthropic", "ollama"). Empty = all providers.
+	AllowedProviders []string `yaml:"allowed_providers,omitempty"`
+
+	// BlockedProviders lists providers this workspace must never send data
+	// to. Takes precedence over AllowedProviders.
+	BlockedProviders []string `yaml:"blocked_providers,omitempty"`
+
+	// AllowedModels lists the only models this workspace may use. Entries
+	// match exactly or by prefix with a trailing "*" (e.g. "gpt-4*").
+	// Empty = all models.
+	AllowedModels []string `yaml:"allowed_models,omitempty"`
+}
+
+// Allows reports whether the policy permits sending data to the given
+// provider/model combination. A nil policy allows everything.
+func (p *ModelPolicyConfig) Allows(provider, model string) bool {
+	if p == nil {
+		return true
+	}
+
+	for _, blocked := range p.BlockedProviders {
+		if strings.EqualFold(blocked, provider) {
+			return false
+		}
+	}
+
+	if len(p.AllowedProviders) > 0 {
+		ok := false
+		for _, allowed := range p.AllowedProviders {
+			if strings.EqualFold(allowed, provider) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if len(p.AllowedModels) > 0 {
+		ok := false
+		for _, pattern := range p.AllowedModels {
+			if matchesModelPattern(pattern, model) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesModelPattern matches a model name against an allowlist entry.
+// A trailing "*" matches any suffix; otherwise the match is exact.
+func matchesModelPattern(pattern, model string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(strings.ToLower(model), strings.ToLower(prefix))
+	}
+	return strings.EqualFold(pattern, model)
+}
+
+// ctxKeyModelPolicy is the context key for the workspace model policy.
+type ctxKeyModelPolicy struct{}
+
+// workspacePolicy pairs a model policy with the workspace it belongs to,
+// so violations can be attributed in logs and admin alerts.
+type workspacePolicy struct {
+	workspaceID string
+	policy      *ModelPolicyConfig
+}
+
+// ContextWithModelPolicy returns a new context carrying a workspace's model
+// policy. The LLM client enforces it on every completion made with this context.
+func ContextWithModelPolicy(ctx context.Context, workspaceID string, policy *ModelPolicyConfig) context.Context {
+	if policy == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKeyModelPolicy{}, workspacePolicy{
+		workspaceID: workspaceID,
+		policy:      policy,
+	})
+}
+
+// ModelPolicyFromContext extracts the workspace model policy from a context.
+// Returns ("", nil) when no policy is set.
+func ModelPolicyFromContext(ctx context.Context) (string, *ModelPolicyConfig) {
+	if v, ok := ctx.Value(ctxKeyModelPolicy{}).(workspacePolicy); ok {
+		return v.workspaceID, v.policy
+	}
+	return "", nil
+}
@@ -0,0 +1,120 @@
+package copilot
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestModelPolicyAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *ModelPolicyConfig
+		provider string
+		model    string
+		want     bool
+	}{
+		{"nil policy allows everything", nil, "openai", "gpt-4o", true},
+		{"empty policy allows everything", &ModelPolicyConfig{}, "openai", "gpt-4o", true},
+		{
+			"blocked provider",
+			&ModelPolicyConfig{BlockedProviders: []string{"openai"}},
+			"openai", "gpt-4o", false,
+		},
+		{
+			"blocked provider is case-insensitive",
+			&ModelPolicyConfig{BlockedProviders: []string{"OpenAI"}},
+			"openai", "gpt-4o", false,
+		},
+		{
+			"provider not in allowlist",
+			&ModelPolicyConfig{AllowedProviders: []string{"anthropic", "ollama"}},
+			"openai", "gpt-4o", false,
+		},
+		{
+			"provider in allowlist",
+			&ModelPolicyConfig{AllowedProviders: []string{"anthropic", "ollama"}},
+			"ollama", "llama3", true,
+		},
+		{
+			"blocked wins over allowed",
+			&ModelPolicyConfig{AllowedProviders: []string{"openai"}, BlockedProviders: []string{"openai"}},
+			"openai", "gpt-4o", false,
+		},
+		{
+			"model prefix pattern matches",
+			&ModelPolicyConfig{AllowedModels: []string{"gpt-4*"}},
+			"openai", "gpt-4o-mini", true,
+		},
+		{
+			"model not in allowlist",
+			&ModelPolicyConfig{AllowedModels: []string{"gpt-4*"}},
+			"openai", "gpt-3.5-turbo", false,
+		},
+		{
+			"exact model match",
+			&ModelPolicyConfig{AllowedModels: []string{"claude-sonnet-4"}},
+			"anthropic", "claude-sonnet-4", true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Allows(tt.provider, tt.model); got != tt.want {
+				t.Errorf("Allows(%q, %q) = %v, want %v", tt.provider, tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelPolicyContext(t *testing.T) {
+	policy := &ModelPolicyConfig{BlockedProviders: []string{"openai"}}
+	ctx := ContextWithModelPolicy(context.Background(), "legal", policy)
+
+	wsID, got := ModelPolicyFromContext(ctx)
+	if wsID != "legal" || got != policy {
+		t.Errorf("round-trip failed: wsID=%q policy=%p", wsID, got)
+	}
+
+	// Nil policy attaches nothing.
+	ctx = ContextWithModelPolicy(context.Background(), "legal", nil)
+	if wsID, got := ModelPolicyFromContext(ctx); wsID != "" || got != nil {
+		t.Errorf("nil policy should not be attached: wsID=%q policy=%p", wsID, got)
+	}
+}
+
+func TestApplyModelPolicy(t *testing.T) {
+	var violations []string
+	c := &LLMClient{provider: "openai", logger: slog.Default()}
+	c.SetPolicyViolationHandler(func(wsID, provider, model string) {
+		violations = append(violations, wsID+"/"+provider+"/"+model)
+	})
+
+	models := []string{"gpt-4o", "gpt-4o-mini", "gpt-3.5-turbo"}
+
+	// No policy: list passes through untouched.
+	if got := c.applyModelPolicy(context.Background(), models); !reflect.DeepEqual(got, models) {
+		t.Errorf("without policy, got %v", got)
+	}
+
+	// Policy filters forbidden models and reports each violation.
+	ctx := ContextWithModelPolicy(context.Background(), "legal", &ModelPolicyConfig{
+		AllowedModels: []string{"gpt-4o*"},
+	})
+	got := c.applyModelPolicy(ctx, models)
+	if want := []string{"gpt-4o", "gpt-4o-mini"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filtered list = %v, want %v", got, want)
+	}
+	if want := []string{"legal/openai/gpt-3.5-turbo"}; !reflect.DeepEqual(violations, want) {
+		t.Errorf("violations = %v, want %v", violations, want)
+	}
+
+	// Blocked provider empties the list entirely.
+	ctx = ContextWithModelPolicy(context.Background(), "legal", &ModelPolicyConfig{
+		BlockedProviders: []string{"openai"},
+	})
+	if got := c.applyModelPolicy(ctx, models); len(got) != 0 {
+		t.Errorf("blocked provider should filter all models, got %v", got)
+	}
+}
@@ -0,0 +1,54 @@
+// Package copilot – notify_tools.go exposes the notify_desktop tool: an
+// OS-level notification on the host the assistant runs on, for users who
+// work locally without always watching a chat.
+package copilot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/notify"
+)
+
+// RegisterNotifyTools registers host notification tools in the executor.
+func RegisterNotifyTools(executor *ToolExecutor) {
+	desktop := notify.NewDesktopSink()
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "notify_desktop",
+			Description: "Show a native desktop notification on the host machine (notify-send on Linux, osascript on macOS, toast on Windows). Use for local alerts the user should see even when not watching the chat.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"title":   map[string]any{"type": "string", "description": "Notification headline"},
+					"message": map[string]any{"type": "string", "description": "Notification body"},
+					"urgent":  map[string]any{"type": "boolean", "description": "Mark the notification as urgent/critical (default: false)"},
+				},
+				"required": []string{"title", "message"},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		title, _ := args["title"].(string)
+		message, _ := args["message"].(string)
+		urgent, _ := args["urgent"].(bool)
+		if title == "" || message == "" {
+			return nil, fmt.Errorf("title and message are required")
+		}
+
+		priority := 3
+		if urgent {
+			priority = 1
+		}
+		if err := desktop.Push(ctx, &notify.Alert{
+			Event:    "tool.notify_desktop",
+			Title:    title,
+			Message:  message,
+			Priority: priority,
+		}); err != nil {
+			return nil, fmt.Errorf("desktop notification: %w", err)
+		}
+		return "Desktop notification shown.", nil
+	})
+}
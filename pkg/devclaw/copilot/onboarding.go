@@ -0,0 +1,342 @@
+// Package copilot – onboarding.go implements the welcome flow for newly
+// paired contacts. After a pairing approval the user gets a welcome message
+// with a capabilities overview, is asked for language and preferences (stored
+// to their profile), is assigned to the default workspace, and runs on a
+// rate-limited "trial" tool profile until the owner upgrades them.
+package copilot
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// Onboarding flow states.
+const (
+	// OnboardingPendingWelcome: approved, welcome not yet delivered.
+	OnboardingPendingWelcome = "pending_welcome"
+
+	// OnboardingAwaitingLanguage: welcome sent, waiting for the language answer.
+	OnboardingAwaitingLanguage = "awaiting_language"
+
+	// OnboardingAwaitingPreferences: language stored, waiting for preferences.
+	OnboardingAwaitingPreferences = "awaiting_preferences"
+
+	// OnboardingComplete: flow finished (trial may still be active).
+	OnboardingComplete = "complete"
+)
+
+const (
+	// trialDefaultMaxTurns caps agent round-trips per message during trial.
+	trialDefaultMaxTurns = 10
+
+	// trialDefaultDailyTokens is the daily token budget during trial.
+	trialDefaultDailyTokens = 100000
+)
+
+// OnboardingState tracks one contact's progress through the welcome flow.
+type OnboardingState struct {
+	JID         string
+	Status      string
+	Language    string
+	Preferences string
+	Trial       bool
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// OnboardingManager runs the welcome flow for newly paired contacts and
+// enforces the trial limits until the owner upgrades them.
+type OnboardingManager struct {
+	db      *sql.DB
+	wsMgr   *WorkspaceManager
+	logger  *slog.Logger
+	limiter *GuestLimiter // trial token budget, same mechanics as guests
+
+	mu     sync.RWMutex
+	states map[string]*OnboardingState // normalized JID → state
+}
+
+// NewOnboardingManager creates an onboarding manager. db may be nil (states
+// are then kept in memory only).
+func NewOnboardingManager(db *sql.DB, wsMgr *WorkspaceManager, logger *slog.Logger) *OnboardingManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &OnboardingManager{
+		db:     db,
+		wsMgr:  wsMgr,
+		logger: logger.With("component", "onboarding"),
+		limiter: NewGuestLimiter(GuestConfig{
+			MaxTurns:         trialDefaultMaxTurns,
+			DailyTokenBudget: trialDefaultDailyTokens,
+		}, logger),
+		states: make(map[string]*OnboardingState),
+	}
+}
+
+// Load restores onboarding states from the database on startup.
+func (om *OnboardingManager) Load() error {
+	if om.db == nil {
+		return nil
+	}
+
+	rows, err := om.db.Query(`
+		SELECT jid, status, language, preferences, trial, started_at, completed_at
+		FROM user_onboarding`)
+	if err != nil {
+		return fmt.Errorf("query onboarding states: %w", err)
+	}
+	defer rows.Close()
+
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	om.states = make(map[string]*OnboardingState)
+	for rows.Next() {
+		st := &OnboardingState{}
+		var trial int
+		var startedAt, completedAt string
+		if err := rows.Scan(&st.JID, &st.Status, &st.Language, &st.Preferences, &trial, &startedAt, &completedAt); err != nil {
+			continue
+		}
+		st.Trial = trial == 1
+		st.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+		if completedAt != "" {
+			st.CompletedAt, _ = time.Parse(time.RFC3339, completedAt)
+		}
+		om.states[st.JID] = st
+	}
+
+	om.logger.Info("loaded onboarding states", "count", len(om.states))
+	return nil
+}
+
+// Begin starts the onboarding flow for a newly approved contact. Called from
+// the pairing approval paths; a no-op when the contact was already onboarded.
+func (om *OnboardingManager) Begin(jid string) {
+	norm := normalizeJID(jid)
+
+	om.mu.Lock()
+	if _, exists := om.states[norm]; exists {
+		om.mu.Unlock()
+		return
+	}
+	st := &OnboardingState{
+		JID:       norm,
+		Status:    OnboardingPendingWelcome,
+		Trial:     true,
+		StartedAt: time.Now(),
+	}
+	om.states[norm] = st
+	om.mu.Unlock()
+
+	om.persist(st)
+	om.logger.Info("onboarding started", "jid", norm)
+}
+
+// State returns the contact's onboarding state (nil when never onboarded).
+func (om *OnboardingManager) State(jid string) *OnboardingState {
+	om.mu.RLock()
+	defer om.mu.RUnlock()
+	st, ok := om.states[normalizeJID(jid)]
+	if !ok {
+		return nil
+	}
+	copied := *st
+	return &copied
+}
+
+// Advance moves the flow forward, storing the user's answer for the current
+// step. Returns the new status.
+func (om *OnboardingManager) Advance(jid, answer string) string {
+	norm := normalizeJID(jid)
+
+	om.mu.Lock()
+	st, ok := om.states[norm]
+	if !ok {
+		om.mu.Unlock()
+		return ""
+	}
+
+	switch st.Status {
+	case OnboardingPendingWelcome:
+		st.Status = OnboardingAwaitingLanguage
+	case OnboardingAwaitingLanguage:
+		st.Language = strings.TrimSpace(answer)
+		st.Status = OnboardingAwaitingPreferences
+	case OnboardingAwaitingPreferences:
+		st.Preferences = strings.TrimSpace(answer)
+		st.Status = OnboardingComplete
+		st.CompletedAt = time.Now()
+	}
+	status := st.Status
+	copied := *st
+	om.mu.Unlock()
+
+	om.persist(&copied)
+	return status
+}
+
+// InTrial reports whether the contact still runs on the trial profile.
+func (om *OnboardingManager) InTrial(jid string) bool {
+	om.mu.RLock()
+	defer om.mu.RUnlock()
+	st, ok := om.states[normalizeJID(jid)]
+	return ok && st.Trial
+}
+
+// EndTrial lifts the trial restrictions (owner upgrade).
+func (om *OnboardingManager) EndTrial(jid, by string) error {
+	norm := normalizeJID(jid)
+
+	om.mu.Lock()
+	st, ok := om.states[norm]
+	if !ok || !st.Trial {
+		om.mu.Unlock()
+		return fmt.Errorf("no active trial for %s", norm)
+	}
+	st.Trial = false
+	copied := *st
+	om.mu.Unlock()
+
+	om.persist(&copied)
+	om.logger.Info("trial ended", "jid", norm, "by", by)
+	return nil
+}
+
+// AllowTrial reports whether the contact still has trial budget left today.
+func (om *OnboardingManager) AllowTrial(jid string) bool {
+	return om.limiter.Allow(jid)
+}
+
+// RecordTrial charges tokens against the contact's trial budget.
+func (om *OnboardingManager) RecordTrial(jid string, tokens int) {
+	om.limiter.Record(jid, tokens)
+}
+
+// persist writes a state to the database (no-op without one).
+func (om *OnboardingManager) persist(st *OnboardingState) {
+	if om.db == nil {
+		return
+	}
+
+	trial := 0
+	if st.Trial {
+		trial = 1
+	}
+	completedAt := ""
+	if !st.CompletedAt.IsZero() {
+		completedAt = st.CompletedAt.Format(time.RFC3339)
+	}
+
+	_, err := om.db.Exec(`
+		INSERT OR REPLACE INTO user_onboarding (jid, status, language, preferences, trial, started_at, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		st.JID, st.Status, st.Language, st.Preferences, trial,
+		st.StartedAt.Format(time.RFC3339), completedAt,
+	)
+	if err != nil {
+		om.logger.Warn("failed to persist onboarding state", "jid", st.JID, "error", err)
+	}
+}
+
+// onboardingWelcome is the first message a newly paired contact receives.
+func onboardingWelcome(assistantName string) string {
+	if assistantName == "" {
+		assistantName = "DevClaw"
+	}
+	return fmt.Sprintf(`👋 Welcome! You now have access to %s.
+
+Here's what I can help with:
+• Answering questions and research (web search included)
+• Reading and summarizing documents, images and voice notes
+• Reminders and scheduled tasks
+• Notes and long-term memory
+
+While you're on the trial profile some tools (shell, file edits, integrations) stay off until the owner upgrades you.
+
+To get set up: *what language should I reply in?* (e.g. English, Português, Español)`, assistantName)
+}
+
+// onboardingPreferencesQuestion follows the language answer.
+const onboardingPreferencesQuestion = `Got it — I'll reply in %s.
+
+One last thing: *anything I should know about how to help you?* (topics you care about, tone, working hours — or just say "no")`
+
+// onboardingDone closes the flow.
+const onboardingDone = `All set! 🎉 Your preferences are saved and you've been added to the workspace.
+
+Just send me a message whenever you need something. Type /help to see the available commands.`
+
+// handleOnboarding intercepts DMs from contacts mid-onboarding and drives the
+// welcome flow. Returns the reply and true while the flow owns the message.
+func (a *Assistant) handleOnboarding(msg *channels.IncomingMessage) (string, bool) {
+	if a.onboardingMgr == nil || msg.IsGroup {
+		return "", false
+	}
+	st := a.onboardingMgr.State(msg.From)
+	if st == nil || st.Status == OnboardingComplete {
+		return "", false
+	}
+
+	switch st.Status {
+	case OnboardingPendingWelcome:
+		a.onboardingMgr.Advance(msg.From, "")
+		return onboardingWelcome(a.config.Name), true
+
+	case OnboardingAwaitingLanguage:
+		lang := strings.TrimSpace(msg.Content)
+		a.onboardingMgr.Advance(msg.From, lang)
+		a.applyOnboardingLanguage(msg, lang)
+		return fmt.Sprintf(onboardingPreferencesQuestion, lang), true
+
+	case OnboardingAwaitingPreferences:
+		prefs := strings.TrimSpace(msg.Content)
+		a.onboardingMgr.Advance(msg.From, prefs)
+		a.applyOnboardingPreferences(msg, prefs)
+		a.assignDefaultWorkspace(msg.From)
+		return onboardingDone, true
+	}
+
+	return "", false
+}
+
+// applyOnboardingLanguage stores the language on the contact's session so
+// prompts pick it up immediately.
+func (a *Assistant) applyOnboardingLanguage(msg *channels.IncomingMessage, lang string) {
+	if lang == "" {
+		return
+	}
+	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	resolved.Session.SetLanguage(lang)
+	resolved.Session.AddFact("Preferred language: " + lang)
+}
+
+// applyOnboardingPreferences stores the free-form preferences as a session fact.
+func (a *Assistant) applyOnboardingPreferences(msg *channels.IncomingMessage, prefs string) {
+	if prefs == "" || strings.EqualFold(prefs, "no") {
+		return
+	}
+	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	resolved.Session.AddFact("User preferences (from onboarding): " + prefs)
+}
+
+// assignDefaultWorkspace maps the contact to the default workspace when the
+// pairing token didn't assign one already.
+func (a *Assistant) assignDefaultWorkspace(jid string) {
+	if a.workspaceMgr == nil {
+		return
+	}
+	if a.workspaceMgr.IsAssigned(jid) {
+		return
+	}
+	if err := a.workspaceMgr.AssignUser(jid, a.workspaceMgr.DefaultWorkspaceID(), "onboarding"); err != nil {
+		a.logger.Warn("failed to assign default workspace", "jid", jid, "error", err)
+	}
+}
@@ -0,0 +1,139 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testOnboardingManager(t *testing.T) *OnboardingManager {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return NewOnboardingManager(nil, nil, logger)
+}
+
+func TestOnboardingFlow(t *testing.T) {
+	om := testOnboardingManager(t)
+	jid := "5511999@s.whatsapp.net"
+
+	if om.State(jid) != nil {
+		t.Fatal("state should be nil before Begin")
+	}
+
+	om.Begin(jid)
+	st := om.State(jid)
+	if st == nil || st.Status != OnboardingPendingWelcome {
+		t.Fatalf("expected pending_welcome, got %+v", st)
+	}
+	if !om.InTrial(jid) {
+		t.Error("new contact should be in trial")
+	}
+
+	// Begin is idempotent.
+	om.Begin(jid)
+	if got := om.State(jid).Status; got != OnboardingPendingWelcome {
+		t.Errorf("second Begin should not reset the flow, got %s", got)
+	}
+
+	if got := om.Advance(jid, ""); got != OnboardingAwaitingLanguage {
+		t.Errorf("expected awaiting_language, got %s", got)
+	}
+	if got := om.Advance(jid, "Português"); got != OnboardingAwaitingPreferences {
+		t.Errorf("expected awaiting_preferences, got %s", got)
+	}
+	if got := om.Advance(jid, "short answers please"); got != OnboardingComplete {
+		t.Errorf("expected complete, got %s", got)
+	}
+
+	st = om.State(jid)
+	if st.Language != "Português" || st.Preferences != "short answers please" {
+		t.Errorf("answers not stored: %+v", st)
+	}
+	if st.CompletedAt.IsZero() {
+		t.Error("CompletedAt should be set after completion")
+	}
+
+	// Completing the flow does not end the trial.
+	if !om.InTrial(jid) {
+		t.Error("trial should persist until the owner upgrades")
+	}
+	if err := om.EndTrial(jid, "owner"); err != nil {
+		t.Fatalf("EndTrial: %v", err)
+	}
+	if om.InTrial(jid) {
+		t.Error("trial should be over after EndTrial")
+	}
+	if err := om.EndTrial(jid, "owner"); err == nil {
+		t.Error("second EndTrial should report no active trial")
+	}
+}
+
+func TestOnboardingPersistence(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	om := NewOnboardingManager(db, nil, logger)
+	om.Begin("user@s.whatsapp.net")
+	om.Advance("user@s.whatsapp.net", "")
+	om.Advance("user@s.whatsapp.net", "English")
+
+	om2 := NewOnboardingManager(db, nil, logger)
+	if err := om2.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	st := om2.State("user@s.whatsapp.net")
+	if st == nil || st.Status != OnboardingAwaitingPreferences {
+		t.Fatalf("state should survive restart, got %+v", st)
+	}
+	if st.Language != "English" {
+		t.Errorf("language should survive restart, got %q", st.Language)
+	}
+	if !om2.InTrial("user@s.whatsapp.net") {
+		t.Error("trial flag should survive restart")
+	}
+}
+
+func TestOnboardingTrialBudget(t *testing.T) {
+	om := testOnboardingManager(t)
+	jid := "trial@s.whatsapp.net"
+	om.Begin(jid)
+
+	if !om.AllowTrial(jid) {
+		t.Fatal("fresh trial should have budget")
+	}
+	om.RecordTrial(jid, trialDefaultDailyTokens)
+	if om.AllowTrial(jid) {
+		t.Error("exhausted trial budget should refuse")
+	}
+}
+
+func TestPairingApprovalStartsOnboarding(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	am := NewAccessManager(DefaultAccessConfig(), logger)
+	pm := NewPairingManager(nil, am, nil, logger)
+
+	om := testOnboardingManager(t)
+	pm.SetApprovalCallback(om.Begin)
+
+	token, err := pm.GenerateToken("owner", TokenOptions{AutoApprove: true})
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	approved, _, err := pm.ProcessTokenRedemption(token.Token, "new@s.whatsapp.net", "New User")
+	if err != nil {
+		t.Fatalf("ProcessTokenRedemption: %v", err)
+	}
+	if !approved {
+		t.Fatal("auto-approve token should grant access")
+	}
+
+	st := om.State("new@s.whatsapp.net")
+	if st == nil || st.Status != OnboardingPendingWelcome {
+		t.Fatalf("approval should start onboarding, got %+v", st)
+	}
+}
@@ -103,6 +103,10 @@ type PairingManager struct {
 
 	// In-memory cache of valid tokens for fast lookup
 	tokenCache map[string]*PairingToken
+
+	// onApprove is invoked after access is granted (auto or manual approval),
+	// e.g. to kick off the onboarding flow for the new contact.
+	onApprove func(userJID string)
 }
 
 // NewPairingManager creates a new pairing manager.
@@ -119,6 +123,11 @@ func NewPairingManager(db *sql.DB, accessMgr *AccessManager, wsMgr *WorkspaceMan
 	}
 }
 
+// SetApprovalCallback registers a hook invoked after a user is approved.
+func (pm *PairingManager) SetApprovalCallback(fn func(userJID string)) {
+	pm.onApprove = fn
+}
+
 // Load restores token cache from database on startup.
 func (pm *PairingManager) Load() error {
 	if pm.db == nil {
@@ -315,6 +324,10 @@ func (pm *PairingManager) ProcessTokenRedemption(tokenStr, userJID, userName str
 		// Increment use count.
 		pm.incrementUseCount(token.ID)
 
+		if pm.onApprove != nil {
+			pm.onApprove(userJID)
+		}
+
 		pm.logger.Info("auto-approved via pairing token",
 			"token_id", token.ID,
 			"user_jid", userJID,
@@ -428,6 +441,10 @@ func (pm *PairingManager) ApproveRequest(requestID, approvedBy string) error {
 	// Increment token use count.
 	pm.incrementUseCount(tokenID)
 
+	if pm.onApprove != nil {
+		pm.onApprove(userJID)
+	}
+
 	pm.logger.Info("approved pairing request",
 		"request_id", requestID,
 		"user_jid", userJID,
@@ -0,0 +1,109 @@
+// Package copilot – perf_command.go implements the /perf admin command:
+// runtime diagnostics for long-running deployments. Reports goroutine count,
+// heap and GC statistics, database connection pool state, and the slowest
+// tools, plus recent tool errors from the audit log. Pairs with the optional
+// pprof endpoints on the gateway for deep profiling.
+package copilot
+
+import (
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// perfSlowToolLimit caps how many slow tools /perf lists.
+const perfSlowToolLimit = 5
+
+// perfCommand renders the runtime diagnostics report.
+func (a *Assistant) perfCommand() string {
+	var b strings.Builder
+	b.WriteString("*Runtime Diagnostics*\n\n")
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	b.WriteString(fmt.Sprintf("Goroutines: %d\n", runtime.NumGoroutine()))
+	b.WriteString(fmt.Sprintf("Heap: %.1f MB in use (%.1f MB reserved, %d objects)\n",
+		float64(m.HeapAlloc)/1024/1024, float64(m.HeapSys)/1024/1024, m.HeapObjects))
+	b.WriteString(fmt.Sprintf("GC: %d cycles, %.1fms total pause, next at %.1f MB\n",
+		m.NumGC, float64(m.PauseTotalNs)/1e6, float64(m.NextGC)/1024/1024))
+	if m.LastGC > 0 {
+		b.WriteString(fmt.Sprintf("Last GC: %s ago\n",
+			time.Since(time.Unix(0, int64(m.LastGC))).Round(time.Second)))
+	}
+
+	if a.devclawDB != nil {
+		stats := a.devclawDB.Stats()
+		b.WriteString(fmt.Sprintf("\nDB pool: %d open (%d in use, %d idle)\n",
+			stats.OpenConnections, stats.InUse, stats.Idle))
+		if stats.WaitCount > 0 {
+			b.WriteString(fmt.Sprintf("DB waits: %d (%.1fms total)\n",
+				stats.WaitCount, float64(stats.WaitDuration.Milliseconds())))
+		}
+	}
+
+	if a.toolExecutor != nil {
+		if slow := a.toolExecutor.ToolDurationStats(); len(slow) > 0 {
+			if len(slow) > perfSlowToolLimit {
+				slow = slow[:perfSlowToolLimit]
+			}
+			table := NewTableResult("Slowest tools (since start)", []string{"Tool", "Calls", "Errors", "Avg", "Max"})
+			for _, s := range slow {
+				table.AddRow(s.Name,
+					fmt.Sprintf("%d", s.Count),
+					fmt.Sprintf("%d", s.Errors),
+					formatLatency(time.Duration(s.AvgMs)*time.Millisecond),
+					formatLatency(time.Duration(s.MaxMs)*time.Millisecond),
+				)
+			}
+			b.WriteString("\n")
+			b.WriteString(table.RenderMarkdown())
+		}
+	}
+
+	if errors := recentToolErrors(a.devclawDB, time.Hour, perfSlowToolLimit); len(errors) != 0 {
+		b.WriteString("\n*Tool errors (last hour)*\n")
+		for _, e := range errors {
+			b.WriteString(fmt.Sprintf("• %s — %d error(s)\n", e.Tool, e.Count))
+		}
+	}
+
+	if a.config.Gateway.Enabled && a.config.Gateway.Pprof {
+		b.WriteString(fmt.Sprintf("\npprof: %s/debug/pprof/\n", a.config.Gateway.Address))
+	}
+	return b.String()
+}
+
+// toolErrorCount is one tool's error tally from the audit log.
+type toolErrorCount struct {
+	Tool  string
+	Count int
+}
+
+// recentToolErrors tallies tool errors from audit entries newer than the
+// given age, worst first. Nil-safe for setups without devclaw.db.
+func recentToolErrors(db *sql.DB, maxAge time.Duration, limit int) []toolErrorCount {
+	if db == nil {
+		return nil
+	}
+	cutoff := time.Now().Add(-maxAge).UTC().Format(time.RFC3339)
+	rows, err := db.Query(`
+		SELECT tool, COUNT(*) FROM audit_log
+		WHERE created_at > ? AND result_summary LIKE 'ERROR:%'
+		GROUP BY tool ORDER BY COUNT(*) DESC LIMIT ?`, cutoff, limit)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var out []toolErrorCount
+	for rows.Next() {
+		var e toolErrorCount
+		if err := rows.Scan(&e.Tool, &e.Count); err != nil {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
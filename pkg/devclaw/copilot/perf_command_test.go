@@ -0,0 +1,68 @@
+// Package copilot – perf_command_test.go contains unit tests for the /perf
+// diagnostics helpers.
+package copilot
+
+import (
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestToolDurationStats(t *testing.T) {
+	e := NewToolExecutor(slog.Default())
+
+	e.recordToolDuration("bash", 100*time.Millisecond, false)
+	e.recordToolDuration("bash", 300*time.Millisecond, true)
+	e.recordToolDuration("web_fetch", 2*time.Second, false)
+
+	stats := e.ToolDurationStats()
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 tools, got %+v", stats)
+	}
+	// Slowest average first.
+	if stats[0].Name != "web_fetch" || stats[0].AvgMs != 2000 {
+		t.Errorf("unexpected slowest tool: %+v", stats[0])
+	}
+	if stats[1].Name != "bash" || stats[1].AvgMs != 200 || stats[1].MaxMs != 300 || stats[1].Errors != 1 {
+		t.Errorf("unexpected bash stats: %+v", stats[1])
+	}
+}
+
+func TestRecentToolErrors(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenDatabase: %v", err)
+	}
+	defer db.Close()
+
+	insert := `INSERT INTO audit_log (tool, allowed, result_summary, created_at) VALUES (?, 1, ?, ?)`
+	now := time.Now().UTC().Format(time.RFC3339)
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	for _, row := range [][3]string{
+		{"web_fetch", "ERROR: timeout", now},
+		{"web_fetch", "ERROR: dns failure", now},
+		{"bash", "ERROR: exit 1", now},
+		{"bash", "ok", now},                // success, not counted
+		{"db_query", "ERROR: locked", old}, // too old
+	} {
+		if _, err := db.Exec(insert, row[0], row[1], row[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errors := recentToolErrors(db, time.Hour, 5)
+	if len(errors) != 2 {
+		t.Fatalf("expected 2 tools with errors, got %+v", errors)
+	}
+	if errors[0].Tool != "web_fetch" || errors[0].Count != 2 {
+		t.Errorf("unexpected worst tool: %+v", errors[0])
+	}
+	if errors[1].Tool != "bash" || errors[1].Count != 1 {
+		t.Errorf("unexpected second tool: %+v", errors[1])
+	}
+
+	if recentToolErrors(nil, time.Hour, 5) != nil {
+		t.Error("expected nil result without a database")
+	}
+}
@@ -0,0 +1,130 @@
+// Package copilot – persona.go implements the persona library: named voice
+// presets (tone, verbosity, emoji policy, language style) switchable per
+// session via /persona. Personas compose with agent profiles, so routing can
+// pick both the model and the voice. The persona only shapes how the agent
+// speaks; instructions and skills are unaffected.
+package copilot
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Persona defines a voice preset for the assistant.
+type Persona struct {
+	// Name is the persona identifier (e.g., "professional", "friendly").
+	Name string `yaml:"name"`
+
+	// Description explains when this persona fits.
+	Description string `yaml:"description"`
+
+	// Tone describes the overall register (e.g., "warm and casual").
+	Tone string `yaml:"tone"`
+
+	// Verbosity guides answer length (e.g., "short and to the point").
+	Verbosity string `yaml:"verbosity"`
+
+	// EmojiPolicy controls emoji usage (e.g., "never", "sparingly").
+	EmojiPolicy string `yaml:"emoji_policy"`
+
+	// LanguageStyle describes wording preferences (e.g., "plain language,
+	// no jargon").
+	LanguageStyle string `yaml:"language_style"`
+}
+
+// PersonasConfig holds the persona library configuration.
+type PersonasConfig struct {
+	// Default is the persona applied when a session has none set.
+	Default string `yaml:"default"`
+
+	// Custom adds user-defined personas (by name) on top of the built-ins.
+	Custom map[string]Persona `yaml:"custom"`
+}
+
+// BuiltInPersonas provides predefined personas for common voices.
+var BuiltInPersonas = map[string]Persona{
+	"professional": {
+		Name:          "professional",
+		Description:   "Formal business voice for client-facing contexts",
+		Tone:          "courteous and formal",
+		Verbosity:     "complete but structured; no filler",
+		EmojiPolicy:   "never use emojis",
+		LanguageStyle: "precise business language, full sentences",
+	},
+	"friendly": {
+		Name:          "friendly",
+		Description:   "Warm everyday voice for personal assistants",
+		Tone:          "warm and casual",
+		Verbosity:     "conversational; expand when helpful",
+		EmojiPolicy:   "use emojis sparingly where they add warmth",
+		LanguageStyle: "plain language, contractions welcome",
+	},
+	"concise": {
+		Name:          "concise",
+		Description:   "Minimal voice for power users and busy chats",
+		Tone:          "neutral and direct",
+		Verbosity:     "as short as possible; answer first, detail only on request",
+		EmojiPolicy:   "never use emojis",
+		LanguageStyle: "terse phrasing, bullet points over prose",
+	},
+}
+
+// GetPersona returns a persona by name (built-in or custom).
+func GetPersona(name string, custom map[string]Persona) *Persona {
+	if persona, ok := BuiltInPersonas[name]; ok {
+		return &persona
+	}
+	if custom != nil {
+		if persona, ok := custom[name]; ok {
+			persona := persona // copy
+			if persona.Name == "" {
+				persona.Name = name
+			}
+			return &persona
+		}
+	}
+	return nil
+}
+
+// ListPersonas returns all available persona names, sorted.
+func ListPersonas(custom map[string]Persona) []string {
+	names := make([]string, 0, len(BuiltInPersonas)+len(custom))
+	for name := range BuiltInPersonas {
+		names = append(names, name)
+	}
+	for name := range custom {
+		if _, ok := BuiltInPersonas[name]; ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// PromptText renders the persona as voice guidance for the system prompt.
+// Empty fields are omitted so sparse custom personas stay compact.
+func (p *Persona) PromptText() string {
+	var b strings.Builder
+	b.WriteString("## Voice\n\n")
+	b.WriteString(fmt.Sprintf("Active persona: %s", p.Name))
+	if p.Description != "" {
+		b.WriteString(" — " + p.Description)
+	}
+	b.WriteString("\n")
+	if p.Tone != "" {
+		b.WriteString(fmt.Sprintf("- Tone: %s\n", p.Tone))
+	}
+	if p.Verbosity != "" {
+		b.WriteString(fmt.Sprintf("- Verbosity: %s\n", p.Verbosity))
+	}
+	if p.EmojiPolicy != "" {
+		b.WriteString(fmt.Sprintf("- Emoji: %s\n", p.EmojiPolicy))
+	}
+	if p.LanguageStyle != "" {
+		b.WriteString(fmt.Sprintf("- Style: %s\n", p.LanguageStyle))
+	}
+	b.WriteString("This shapes HOW you speak, not WHAT you do; instructions and safety rules still apply.")
+	return b.String()
+}
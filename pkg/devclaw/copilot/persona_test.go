@@ -0,0 +1,112 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetPersona(t *testing.T) {
+	t.Run("built-in persona", func(t *testing.T) {
+		p := GetPersona("concise", nil)
+		if p == nil {
+			t.Fatal("expected built-in persona")
+		}
+		if p.Name != "concise" {
+			t.Errorf("got name %q", p.Name)
+		}
+	})
+
+	t.Run("custom persona", func(t *testing.T) {
+		custom := map[string]Persona{
+			"pirate": {Tone: "swashbuckling"},
+		}
+		p := GetPersona("pirate", custom)
+		if p == nil {
+			t.Fatal("expected custom persona")
+		}
+		// Name is filled from the map key when omitted.
+		if p.Name != "pirate" {
+			t.Errorf("got name %q", p.Name)
+		}
+	})
+
+	t.Run("unknown persona", func(t *testing.T) {
+		if p := GetPersona("nope", nil); p != nil {
+			t.Errorf("expected nil, got %+v", p)
+		}
+	})
+}
+
+func TestListPersonas(t *testing.T) {
+	custom := map[string]Persona{
+		"pirate":  {},
+		"concise": {}, // shadows a built-in; must not be listed twice
+	}
+	names := ListPersonas(custom)
+
+	seen := make(map[string]int)
+	for _, n := range names {
+		seen[n]++
+	}
+	if seen["concise"] != 1 {
+		t.Errorf("concise listed %d times", seen["concise"])
+	}
+	if seen["pirate"] != 1 {
+		t.Error("custom persona missing from list")
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("list not sorted: %v", names)
+			break
+		}
+	}
+}
+
+func TestPersonaPromptText(t *testing.T) {
+	p := &Persona{
+		Name:        "test",
+		Tone:        "direct",
+		EmojiPolicy: "never",
+	}
+	text := p.PromptText()
+
+	if !strings.Contains(text, "Active persona: test") {
+		t.Errorf("missing persona name: %q", text)
+	}
+	if !strings.Contains(text, "Tone: direct") {
+		t.Errorf("missing tone: %q", text)
+	}
+	if strings.Contains(text, "Verbosity:") {
+		t.Errorf("empty field should be omitted: %q", text)
+	}
+}
+
+func TestBuildPersonaLayer(t *testing.T) {
+	cfg := &Config{}
+	cfg.Personas.Default = "professional"
+	composer := NewPromptComposer(cfg)
+
+	t.Run("config default applies", func(t *testing.T) {
+		session := &Session{ID: "s1"}
+		layer := composer.buildPersonaLayer(session)
+		if !strings.Contains(layer, "Active persona: professional") {
+			t.Errorf("expected default persona, got %q", layer)
+		}
+	})
+
+	t.Run("session persona wins", func(t *testing.T) {
+		session := &Session{ID: "s2"}
+		session.SetConfig(SessionConfig{Persona: "concise"})
+		layer := composer.buildPersonaLayer(session)
+		if !strings.Contains(layer, "Active persona: concise") {
+			t.Errorf("expected session persona, got %q", layer)
+		}
+	})
+
+	t.Run("no persona means no layer", func(t *testing.T) {
+		bare := NewPromptComposer(&Config{})
+		if layer := bare.buildPersonaLayer(&Session{ID: "s3"}); layer != "" {
+			t.Errorf("expected empty layer, got %q", layer)
+		}
+	})
+}
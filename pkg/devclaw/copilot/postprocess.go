@@ -0,0 +1,278 @@
+// Package copilot – postprocess.go implements the response formatting
+// pipeline: pluggable stages that run on agent responses right before
+// sendReply. Built-in stages cover citation footnotes, code-fence language
+// fixing, profanity masking, and channel-specific emoji stripping; plugins
+// can append custom stages via RegisterPostProcessor. Stage toggles are
+// configurable globally and overridable per workspace.
+package copilot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PostProcessContext carries routing information into pipeline stages.
+type PostProcessContext struct {
+	Channel   string
+	ChatID    string
+	Workspace string
+}
+
+// PostProcessor is one stage of the response formatting pipeline. Stages
+// receive the full response text and return the adjusted version.
+type PostProcessor interface {
+	Name() string
+	Process(text string, pctx PostProcessContext) string
+}
+
+// PostProcessConfig configures the built-in pipeline stages.
+type PostProcessConfig struct {
+	// Enabled turns the pipeline on/off (default: false).
+	Enabled bool `yaml:"enabled"`
+
+	// CitationFootnotes moves inline markdown links into numbered footnotes
+	// when a response carries several of them.
+	CitationFootnotes bool `yaml:"citation_footnotes"`
+
+	// FixCodeFences adds a language tag to bare ``` fences by sniffing the
+	// block content.
+	FixCodeFences bool `yaml:"fix_code_fences"`
+
+	// ProfanityWords lists words to mask in responses (empty = stage off).
+	ProfanityWords []string `yaml:"profanity_words"`
+
+	// StripEmojiChannels lists channels whose responses get emoji removed.
+	StripEmojiChannels []string `yaml:"strip_emoji_channels"`
+
+	// Workspaces overrides the stage configuration per workspace ID.
+	Workspaces map[string]PostProcessConfig `yaml:"workspaces"`
+}
+
+// DefaultPostProcessConfig returns default pipeline configuration.
+func DefaultPostProcessConfig() PostProcessConfig {
+	return PostProcessConfig{
+		Enabled:           false,
+		CitationFootnotes: true,
+		FixCodeFences:     true,
+	}
+}
+
+// effectiveFor returns the configuration that applies to a workspace.
+func (c PostProcessConfig) effectiveFor(workspace string) PostProcessConfig {
+	if override, ok := c.Workspaces[workspace]; ok {
+		return override
+	}
+	return c
+}
+
+// postProcess runs the formatting pipeline on a response. Output that fails
+// the guardrail after processing falls back to the unprocessed text, so a
+// misbehaving stage can never make a reply worse than no pipeline at all.
+func (a *Assistant) postProcess(text string, pctx PostProcessContext) string {
+	a.configMu.RLock()
+	cfg := a.config.PostProcess.effectiveFor(pctx.Workspace)
+	a.configMu.RUnlock()
+	if !cfg.Enabled && len(a.postProcessors) == 0 {
+		return text
+	}
+
+	processed := text
+	if cfg.Enabled {
+		for _, stage := range builtinPostProcessors(cfg) {
+			processed = stage.Process(processed, pctx)
+		}
+	}
+	for _, stage := range a.postProcessors {
+		processed = stage.Process(processed, pctx)
+	}
+
+	if processed != text {
+		if err := a.outputGuard.Validate(processed); err != nil {
+			a.logger.Warn("post-processed response failed output guardrail, using original",
+				"error", err)
+			return text
+		}
+	}
+	return processed
+}
+
+// RegisterPostProcessor appends a custom pipeline stage. Custom stages run
+// after the built-in ones, in registration order.
+func (a *Assistant) RegisterPostProcessor(p PostProcessor) {
+	a.postProcessors = append(a.postProcessors, p)
+	a.logger.Debug("registered post-processor", "name", p.Name())
+}
+
+// builtinPostProcessors assembles the built-in stages for one configuration.
+func builtinPostProcessors(cfg PostProcessConfig) []PostProcessor {
+	var stages []PostProcessor
+	if cfg.CitationFootnotes {
+		stages = append(stages, citationStage{})
+	}
+	if cfg.FixCodeFences {
+		stages = append(stages, codeFenceStage{})
+	}
+	if len(cfg.ProfanityWords) > 0 {
+		stages = append(stages, profanityStage{words: cfg.ProfanityWords})
+	}
+	if len(cfg.StripEmojiChannels) > 0 {
+		stages = append(stages, emojiStage{channels: cfg.StripEmojiChannels})
+	}
+	return stages
+}
+
+// --- Citation footnotes ---
+
+// citationFootnoteThreshold is the link count above which inline markdown
+// links become footnotes. Short answers keep their links inline.
+const citationFootnoteThreshold = 3
+
+var markdownLinkRe = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^\s)]+)\)`)
+
+type citationStage struct{}
+
+func (citationStage) Name() string { return "citation_footnotes" }
+
+func (citationStage) Process(text string, _ PostProcessContext) string {
+	matches := markdownLinkRe.FindAllStringSubmatch(text, -1)
+	if len(matches) < citationFootnoteThreshold {
+		return text
+	}
+
+	var footnotes []string
+	seen := map[string]int{}
+	result := markdownLinkRe.ReplaceAllStringFunc(text, func(m string) string {
+		parts := markdownLinkRe.FindStringSubmatch(m)
+		url := parts[2]
+		n, ok := seen[url]
+		if !ok {
+			n = len(seen) + 1
+			seen[url] = n
+			footnotes = append(footnotes, fmt.Sprintf("[%d]: %s", n, url))
+		}
+		return fmt.Sprintf("%s [%d]", parts[1], n)
+	})
+	return result + "\n\n" + strings.Join(footnotes, "\n")
+}
+
+// --- Code fence language fixing ---
+
+type codeFenceStage struct{}
+
+func (codeFenceStage) Name() string { return "fix_code_fences" }
+
+func (codeFenceStage) Process(text string, _ PostProcessContext) string {
+	lines := strings.Split(text, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		if inFence {
+			inFence = false
+			continue
+		}
+		inFence = true
+		if trimmed != "```" {
+			continue // already has a language tag
+		}
+		// Sniff the language from the block's first lines.
+		var block []string
+		for j := i + 1; j < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[j]), "```"); j++ {
+			block = append(block, lines[j])
+			if len(block) >= 5 {
+				break
+			}
+		}
+		if lang := sniffBlockLanguage(block); lang != "" {
+			lines[i] = strings.Replace(line, "```", "```"+lang, 1)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sniffBlockLanguage guesses the language of a code block from its first
+// lines. Returns "" when unsure — a wrong tag is worse than none.
+func sniffBlockLanguage(block []string) string {
+	if len(block) == 0 {
+		return ""
+	}
+	head := strings.TrimSpace(strings.Join(block, "\n"))
+	switch {
+	case strings.HasPrefix(head, "package ") || strings.Contains(head, "func "):
+		return "go"
+	case strings.HasPrefix(head, "#!/bin/bash") || strings.HasPrefix(head, "#!/bin/sh") ||
+		strings.HasPrefix(head, "#!/usr/bin/env bash"):
+		return "bash"
+	case strings.HasPrefix(head, "def ") || strings.HasPrefix(head, "import ") && strings.Contains(head, ":"):
+		return "python"
+	case strings.HasPrefix(head, "SELECT ") || strings.HasPrefix(head, "INSERT ") ||
+		strings.HasPrefix(head, "UPDATE ") || strings.HasPrefix(head, "CREATE TABLE"):
+		return "sql"
+	case strings.HasPrefix(head, "{") && strings.Contains(head, "\":"):
+		return "json"
+	case strings.HasPrefix(head, "<?php"):
+		return "php"
+	case strings.HasPrefix(head, "function ") || strings.HasPrefix(head, "const ") ||
+		strings.HasPrefix(head, "let "):
+		return "javascript"
+	default:
+		return ""
+	}
+}
+
+// --- Profanity masking ---
+
+type profanityStage struct {
+	words []string
+}
+
+func (profanityStage) Name() string { return "profanity_filter" }
+
+func (s profanityStage) Process(text string, _ PostProcessContext) string {
+	for _, word := range s.words {
+		if word == "" {
+			continue
+		}
+		re, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(word) + `\b`)
+		if err != nil {
+			continue
+		}
+		text = re.ReplaceAllStringFunc(text, func(m string) string {
+			if len(m) <= 1 {
+				return "*"
+			}
+			return m[:1] + strings.Repeat("*", len(m)-1)
+		})
+	}
+	return text
+}
+
+// --- Channel emoji stripping ---
+
+var emojiRe = regexp.MustCompile(`[\x{1F300}-\x{1FAFF}\x{2600}-\x{27BF}\x{FE0F}\x{1F000}-\x{1F02F}]`)
+
+type emojiStage struct {
+	channels []string
+}
+
+func (emojiStage) Name() string { return "strip_emoji" }
+
+func (s emojiStage) Process(text string, pctx PostProcessContext) string {
+	applies := false
+	for _, ch := range s.channels {
+		if ch == pctx.Channel {
+			applies = true
+			break
+		}
+	}
+	if !applies {
+		return text
+	}
+	stripped := emojiRe.ReplaceAllString(text, "")
+	// Collapse double spaces left behind by removed emoji.
+	stripped = strings.ReplaceAll(stripped, "  ", " ")
+	return stripped
+}
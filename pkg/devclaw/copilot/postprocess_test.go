@@ -0,0 +1,125 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCitationStage(t *testing.T) {
+	stage := citationStage{}
+
+	// Under the threshold, links stay inline.
+	short := "See [docs](https://example.com/docs)."
+	if got := stage.Process(short, PostProcessContext{}); got != short {
+		t.Errorf("short text changed: %q", got)
+	}
+
+	text := "See [docs](https://example.com/docs), [api](https://example.com/api), " +
+		"and [docs again](https://example.com/docs) plus [blog](https://example.com/blog)."
+	got := stage.Process(text, PostProcessContext{})
+	if !strings.Contains(got, "docs [1]") || !strings.Contains(got, "api [2]") || !strings.Contains(got, "blog [3]") {
+		t.Errorf("inline references missing:\n%s", got)
+	}
+	// Repeated URLs share a footnote number.
+	if !strings.Contains(got, "docs again [1]") {
+		t.Errorf("duplicate URL got a new number:\n%s", got)
+	}
+	if !strings.Contains(got, "[1]: https://example.com/docs") || !strings.Contains(got, "[3]: https://example.com/blog") {
+		t.Errorf("footnote list missing:\n%s", got)
+	}
+}
+
+func TestCodeFenceStage(t *testing.T) {
+	stage := codeFenceStage{}
+
+	text := "Here:\n```\npackage main\n\nfunc main() {}\n```\ndone"
+	got := stage.Process(text, PostProcessContext{})
+	if !strings.Contains(got, "```go\n") {
+		t.Errorf("go fence not tagged:\n%s", got)
+	}
+
+	tagged := "```python\nprint(1)\n```"
+	if got := stage.Process(tagged, PostProcessContext{}); got != tagged {
+		t.Errorf("tagged fence changed:\n%s", got)
+	}
+
+	unknown := "```\nsome prose output\n```"
+	if got := stage.Process(unknown, PostProcessContext{}); got != unknown {
+		t.Errorf("unknown block should stay untagged:\n%s", got)
+	}
+}
+
+func TestSniffBlockLanguage(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM users":   "sql",
+		"def handler():":        "python",
+		"{\n  \"key\": 1\n}":    "json",
+		"const x = 1;":          "javascript",
+		"#!/bin/bash\nset -e":   "bash",
+		"just a plain sentence": "",
+	}
+	for input, want := range tests {
+		if got := sniffBlockLanguage(strings.Split(input, "\n")); got != want {
+			t.Errorf("sniffBlockLanguage(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestProfanityStage(t *testing.T) {
+	stage := profanityStage{words: []string{"dang"}}
+	got := stage.Process("Well DANG, that failed. Dangling pointers are fine.", PostProcessContext{})
+	if !strings.Contains(got, "D***") {
+		t.Errorf("word not masked: %q", got)
+	}
+	// Word-boundary match: "Dangling" must survive.
+	if !strings.Contains(got, "Dangling pointers") {
+		t.Errorf("substring over-masked: %q", got)
+	}
+}
+
+func TestEmojiStage(t *testing.T) {
+	stage := emojiStage{channels: []string{"slack"}}
+	text := "Deployed 🚀 successfully ✅"
+
+	got := stage.Process(text, PostProcessContext{Channel: "slack"})
+	if strings.Contains(got, "🚀") || strings.Contains(got, "✅") {
+		t.Errorf("emoji not stripped: %q", got)
+	}
+	if got := stage.Process(text, PostProcessContext{Channel: "whatsapp"}); got != text {
+		t.Errorf("unlisted channel changed: %q", got)
+	}
+}
+
+func TestPostProcessConfigEffectiveFor(t *testing.T) {
+	cfg := DefaultPostProcessConfig()
+	cfg.Enabled = true
+	cfg.Workspaces = map[string]PostProcessConfig{
+		"work": {Enabled: true, FixCodeFences: false, ProfanityWords: []string{"heck"}},
+	}
+
+	base := cfg.effectiveFor("personal")
+	if !base.FixCodeFences || len(base.ProfanityWords) != 0 {
+		t.Errorf("unexpected base config: %+v", base)
+	}
+	work := cfg.effectiveFor("work")
+	if work.FixCodeFences || len(work.ProfanityWords) != 1 {
+		t.Errorf("workspace override not applied: %+v", work)
+	}
+}
+
+func TestBuiltinPostProcessors(t *testing.T) {
+	cfg := PostProcessConfig{
+		Enabled:            true,
+		CitationFootnotes:  true,
+		FixCodeFences:      true,
+		ProfanityWords:     []string{"x"},
+		StripEmojiChannels: []string{"slack"},
+	}
+	stages := builtinPostProcessors(cfg)
+	if len(stages) != 4 {
+		t.Fatalf("expected 4 stages, got %d", len(stages))
+	}
+	if stages[0].Name() != "citation_footnotes" || stages[3].Name() != "strip_emoji" {
+		t.Errorf("unexpected stage order: %s ... %s", stages[0].Name(), stages[3].Name())
+	}
+}
@@ -0,0 +1,257 @@
+// Package copilot – presence.go implements presence-aware delivery.
+// Every inbound message marks its chat as active; low-priority proactive
+// notifications can use the "when-active" delivery mode to wait until the
+// user messages or comes online instead of pinging at arbitrary times.
+// Held notifications are released as soon as the chat shows activity, or
+// after a max-hold timeout so nothing waits forever.
+package copilot
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// presenceDefaultActiveWindow is how recently a chat must have shown
+	// activity to count as "active".
+	presenceDefaultActiveWindow = 5 * time.Minute
+
+	// presenceDefaultMaxHold is the longest a when-active notification
+	// waits before being delivered anyway.
+	presenceDefaultMaxHold = 4 * time.Hour
+)
+
+// PresenceConfig configures presence-aware delivery.
+type PresenceConfig struct {
+	// ActiveWindow is how recently the user must have messaged for the
+	// chat to count as active (default: 5m).
+	ActiveWindow time.Duration `yaml:"active_window"`
+
+	// MaxHold is the longest a when-active notification is held before
+	// being delivered regardless of presence (default: 4h).
+	MaxHold time.Duration `yaml:"max_hold"`
+}
+
+// DefaultPresenceConfig returns sensible presence defaults.
+func DefaultPresenceConfig() PresenceConfig {
+	return PresenceConfig{
+		ActiveWindow: presenceDefaultActiveWindow,
+		MaxHold:      presenceDefaultMaxHold,
+	}
+}
+
+// heldDelivery is a when-active notification waiting for chat activity.
+type heldDelivery struct {
+	Channel  string
+	ChatID   string
+	Content  string
+	QueuedAt time.Time
+	Deadline time.Time
+}
+
+// PresenceTracker records per-chat activity and holds when-active
+// notifications until the chat is active again.
+type PresenceTracker struct {
+	config PresenceConfig
+	logger *slog.Logger
+	cancel context.CancelFunc
+
+	// sendFunc delivers a released notification (wired to sendProactive
+	// so do-not-disturb windows still apply on release).
+	sendFunc func(channel, chatID, content string) error
+
+	// mu guards lastSeen and pending.
+	mu       sync.RWMutex
+	lastSeen map[string]time.Time
+	pending  []heldDelivery
+}
+
+// NewPresenceTracker creates a presence tracker.
+func NewPresenceTracker(cfg PresenceConfig, logger *slog.Logger) *PresenceTracker {
+	if cfg.ActiveWindow <= 0 {
+		cfg.ActiveWindow = presenceDefaultActiveWindow
+	}
+	if cfg.MaxHold <= 0 {
+		cfg.MaxHold = presenceDefaultMaxHold
+	}
+	return &PresenceTracker{
+		config:   cfg,
+		logger:   logger.With("component", "presence"),
+		lastSeen: make(map[string]time.Time),
+	}
+}
+
+// SetSendFunc sets the delivery function for released notifications.
+func (p *PresenceTracker) SetSendFunc(fn func(channel, chatID, content string) error) {
+	p.sendFunc = fn
+}
+
+// Touch marks a chat as active and releases any notifications held for
+// it. Called for every inbound message.
+func (p *PresenceTracker) Touch(channel, chatID string) {
+	key := channel + "|" + chatID
+
+	p.mu.Lock()
+	p.lastSeen[key] = time.Now()
+	hasPending := false
+	for _, h := range p.pending {
+		if h.Channel == channel && h.ChatID == chatID {
+			hasPending = true
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	if hasPending {
+		go p.flushChat(channel, chatID)
+	}
+}
+
+// LastSeen returns the last recorded activity for a chat.
+func (p *PresenceTracker) LastSeen(channel, chatID string) (time.Time, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	t, ok := p.lastSeen[channel+"|"+chatID]
+	return t, ok
+}
+
+// IsActive reports whether a chat showed activity within the active window.
+func (p *PresenceTracker) IsActive(channel, chatID string, now time.Time) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	seen, ok := p.lastSeen[channel+"|"+chatID]
+	return ok && now.Sub(seen) < p.config.ActiveWindow
+}
+
+// HoldUntilActive queues a notification for delivery when the chat is
+// active again (or after the max-hold timeout).
+func (p *PresenceTracker) HoldUntilActive(channel, chatID, content string) {
+	now := time.Now()
+
+	p.mu.Lock()
+	p.pending = append(p.pending, heldDelivery{
+		Channel:  channel,
+		ChatID:   chatID,
+		Content:  content,
+		QueuedAt: now,
+		Deadline: now.Add(p.config.MaxHold),
+	})
+	count := len(p.pending)
+	p.mu.Unlock()
+
+	p.logger.Info("notification held until chat is active",
+		"channel", channel, "chat_id", chatID, "pending", count)
+}
+
+// PendingCount returns how many notifications are waiting for activity.
+func (p *PresenceTracker) PendingCount() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.pending)
+}
+
+// Start begins the background max-hold flush loop.
+func (p *PresenceTracker) Start(ctx context.Context) {
+	pCtx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+
+	p.logger.Info("presence tracker started",
+		"active_window", p.config.ActiveWindow.String(),
+		"max_hold", p.config.MaxHold.String(),
+	)
+
+	go p.loop(pCtx)
+}
+
+// Stop shuts down the flush loop.
+func (p *PresenceTracker) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// loop delivers expired holds once a minute.
+func (p *PresenceTracker) loop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.flush(time.Now())
+		case <-ctx.Done():
+			p.logger.Info("presence tracker stopped")
+			return
+		}
+	}
+}
+
+// flushChat releases all notifications held for one chat.
+func (p *PresenceTracker) flushChat(channel, chatID string) {
+	p.mu.Lock()
+	var release []heldDelivery
+	var keep []heldDelivery
+	for _, h := range p.pending {
+		if h.Channel == channel && h.ChatID == chatID {
+			release = append(release, h)
+		} else {
+			keep = append(keep, h)
+		}
+	}
+	p.pending = keep
+	p.mu.Unlock()
+
+	p.deliver(release, "chat became active")
+}
+
+// flush releases notifications whose chat is active or whose max-hold
+// deadline passed.
+func (p *PresenceTracker) flush(now time.Time) {
+	p.mu.Lock()
+	var release []heldDelivery
+	var keep []heldDelivery
+	for _, h := range p.pending {
+		seen, ok := p.lastSeen[h.Channel+"|"+h.ChatID]
+		active := ok && now.Sub(seen) < p.config.ActiveWindow && seen.After(h.QueuedAt)
+		if active || !now.Before(h.Deadline) {
+			release = append(release, h)
+		} else {
+			keep = append(keep, h)
+		}
+	}
+	p.pending = keep
+	p.mu.Unlock()
+
+	p.deliver(release, "flush")
+}
+
+// deliver sends released notifications through the configured send func.
+func (p *PresenceTracker) deliver(release []heldDelivery, reason string) {
+	if len(release) == 0 || p.sendFunc == nil {
+		return
+	}
+	for _, h := range release {
+		if err := p.sendFunc(h.Channel, h.ChatID, h.Content); err != nil {
+			p.logger.Warn("held notification delivery failed",
+				"channel", h.Channel, "chat_id", h.ChatID, "error", err)
+			continue
+		}
+		p.logger.Info("held notification delivered",
+			"channel", h.Channel, "chat_id", h.ChatID, "reason", reason,
+			"held_for", time.Since(h.QueuedAt).Round(time.Second).String())
+	}
+}
+
+// sendWhenActive delivers a low-priority notification when the user is
+// active: if the chat showed recent activity it goes out immediately
+// (still honoring do-not-disturb), otherwise it waits for the next
+// inbound message or the max-hold timeout.
+func (a *Assistant) sendWhenActive(ctx context.Context, channel, chatID, content string) error {
+	if a.presenceTracker != nil && !a.presenceTracker.IsActive(channel, chatID, time.Now()) {
+		a.presenceTracker.HoldUntilActive(channel, chatID, content)
+		return nil
+	}
+	return a.sendProactive(ctx, channel, chatID, content, false)
+}
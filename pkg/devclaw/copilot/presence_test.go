@@ -0,0 +1,83 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPresenceIsActive(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	p := NewPresenceTracker(PresenceConfig{ActiveWindow: 5 * time.Minute}, logger)
+
+	now := time.Now()
+	if p.IsActive("whatsapp", "123", now) {
+		t.Error("untouched chat must not be active")
+	}
+
+	p.Touch("whatsapp", "123")
+	if !p.IsActive("whatsapp", "123", now) {
+		t.Error("just-touched chat should be active")
+	}
+	if p.IsActive("whatsapp", "123", now.Add(10*time.Minute)) {
+		t.Error("chat should go inactive after the window")
+	}
+	if p.IsActive("whatsapp", "456", now) {
+		t.Error("activity must not leak to other chats")
+	}
+}
+
+func TestPresenceFlushOnActivity(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	p := NewPresenceTracker(DefaultPresenceConfig(), logger)
+
+	var delivered []string
+	p.SetSendFunc(func(channel, chatID, content string) error {
+		delivered = append(delivered, chatID+"|"+content)
+		return nil
+	})
+
+	p.HoldUntilActive("whatsapp", "123", "low-prio A")
+	p.HoldUntilActive("whatsapp", "456", "low-prio B")
+	if p.PendingCount() != 2 {
+		t.Fatalf("expected 2 pending, got %d", p.PendingCount())
+	}
+
+	// Activity in one chat only releases that chat's holds.
+	p.flushChat("whatsapp", "123")
+	if p.PendingCount() != 1 {
+		t.Errorf("expected 1 pending after flush, got %d", p.PendingCount())
+	}
+	if len(delivered) != 1 || delivered[0] != "123|low-prio A" {
+		t.Errorf("unexpected deliveries: %v", delivered)
+	}
+}
+
+func TestPresenceMaxHoldTimeout(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	p := NewPresenceTracker(PresenceConfig{MaxHold: time.Hour}, logger)
+
+	var delivered []string
+	p.SetSendFunc(func(channel, chatID, content string) error {
+		delivered = append(delivered, content)
+		return nil
+	})
+
+	p.HoldUntilActive("whatsapp", "123", "expiring")
+
+	// Before the deadline and without activity, nothing moves.
+	p.flush(time.Now().Add(30 * time.Minute))
+	if len(delivered) != 0 {
+		t.Fatalf("delivered too early: %v", delivered)
+	}
+
+	// Past the max-hold deadline it goes out regardless of presence.
+	p.flush(time.Now().Add(2 * time.Hour))
+	if len(delivered) != 1 || delivered[0] != "expiring" {
+		t.Errorf("expected timeout delivery, got %v", delivered)
+	}
+	if p.PendingCount() != 0 {
+		t.Errorf("expected empty queue, got %d", p.PendingCount())
+	}
+}
@@ -30,8 +30,10 @@ const (
 	LayerCore           PromptLayer = 0  // Base identity and tooling.
 	LayerSafety         PromptLayer = 5  // Safety rules.
 	LayerIdentity       PromptLayer = 10 // Custom instructions.
+	LayerPersona        PromptLayer = 11 // Active persona voice (from /persona).
 	LayerThinking       PromptLayer = 12 // Extended thinking level hint (from /think).
 	LayerBootstrap      PromptLayer = 15 // SOUL.md, AGENTS.md, etc.
+	LayerProjectFiles   PromptLayer = 17 // Per-project AGENT.md/CLAUDE.md conventions.
 	LayerBuiltinSkills  PromptLayer = 18 // Built-in tool guides (memory, teams, etc.)
 	LayerBusiness       PromptLayer = 20 // User/workspace context.
 	LayerProjectContext PromptLayer = 25 // Auto-discovered project context.
@@ -96,6 +98,19 @@ type PromptComposer struct {
 	toolExecutor  *ToolExecutor // For dynamic tool list generation
 	isSubagent    bool // When true, only AGENTS.md + TOOLS.md are loaded.
 
+	// projectResolver returns the project a session is bound to (or nil).
+	// Used to discover per-project convention files (AGENT.md, CLAUDE.md).
+	projectResolver func(sessionID string) *Project
+
+	// workspaceResolver returns the workspace a session is bound to (or nil).
+	// Used to fill the {{.Workspace}} instruction template variable.
+	workspaceResolver func(sessionID string) *Workspace
+
+	// sessionUsers tracks the last-known sender display name per session,
+	// used to fill the {{.UserName}} instruction template variable.
+	sessionUsersMu sync.RWMutex
+	sessionUsers   map[string]string
+
 	// bootstrapCache caches bootstrap file contents to avoid re-reading from disk
 	// on every prompt compose. Invalidated when file content changes (hash mismatch).
 	bootstrapCacheMu sync.RWMutex
@@ -120,6 +135,7 @@ func NewPromptComposer(config *Config) *PromptComposer {
 		config:         config,
 		bootstrapCache: make(map[string]*bootstrapCacheEntry),
 		layerCache:     make(map[string]*promptLayerCache),
+		sessionUsers:   make(map[string]string),
 	}
 }
 
@@ -158,6 +174,82 @@ func (p *PromptComposer) SetToolExecutor(executor *ToolExecutor) {
 	p.toolExecutor = executor
 }
 
+// SetProjectResolver sets the function used to resolve a session's active project.
+func (p *PromptComposer) SetProjectResolver(resolver func(sessionID string) *Project) {
+	p.projectResolver = resolver
+}
+
+// SetWorkspaceResolver sets the function used to resolve a session's workspace.
+func (p *PromptComposer) SetWorkspaceResolver(resolver func(sessionID string) *Workspace) {
+	p.workspaceResolver = resolver
+}
+
+// SetSessionUser records the sender display name for a session, making it
+// available to instruction templates as {{.UserName}}.
+func (p *PromptComposer) SetSessionUser(sessionID, name string) {
+	if name == "" {
+		return
+	}
+	p.sessionUsersMu.Lock()
+	p.sessionUsers[sessionID] = name
+	p.sessionUsersMu.Unlock()
+}
+
+// templateVars builds the instruction template variables for a session.
+// Accepts a nil session (ComposeMinimal), filling only global values.
+func (p *PromptComposer) templateVars(session *Session) PromptVars {
+	loc, err := time.LoadLocation(p.config.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	vars := PromptVars{
+		Date:     time.Now().In(loc).Format("2006-01-02"),
+		Language: p.config.Language,
+	}
+	if session == nil {
+		return vars
+	}
+
+	vars.Channel = session.Channel
+	if lang := session.GetConfig().Language; lang != "" {
+		vars.Language = lang
+	}
+
+	p.sessionUsersMu.RLock()
+	vars.UserName = p.sessionUsers[session.ID]
+	p.sessionUsersMu.RUnlock()
+
+	if p.workspaceResolver != nil {
+		if ws := p.workspaceResolver(session.ID); ws != nil {
+			vars.Workspace = ws.Name
+			if vars.Workspace == "" {
+				vars.Workspace = ws.ID
+			}
+		}
+	}
+	if p.projectResolver != nil {
+		if project := p.projectResolver(session.ID); project != nil {
+			vars.Project = project.Name
+			if vars.Project == "" {
+				vars.Project = project.ID
+			}
+		}
+	}
+	return vars
+}
+
+// renderedInstructions applies template expansion to the configured
+// instructions using the session's runtime variables. On render errors the
+// raw instructions are returned as-is; config load validation already
+// rejects malformed templates, so this is a defensive fallback.
+func (p *PromptComposer) renderedInstructions(session *Session) string {
+	rendered, err := renderPromptTemplate(p.config.Instructions, p.config.PromptPartials, p.templateVars(session))
+	if err != nil {
+		return p.config.Instructions
+	}
+	return rendered
+}
+
 // Compose builds the complete system prompt for a session and user input.
 // Heavy layers (bootstrap, memory, skills, conversation) are built concurrently
 // to minimize prompt composition latency.
@@ -173,9 +265,12 @@ func (p *PromptComposer) Compose(session *Session, input string) string {
 	if p.config.Instructions != "" {
 		layers = append(layers, layerEntry{
 			layer:   LayerIdentity,
-			content: "## Custom Instructions\n\n" + p.config.Instructions,
+			content: "## Custom Instructions\n\n" + p.renderedInstructions(session),
 		})
 	}
+	if personaPrompt := p.buildPersonaLayer(session); personaPrompt != "" {
+		layers = append(layers, layerEntry{layer: LayerPersona, content: personaPrompt})
+	}
 	if thinkingPrompt := p.buildThinkingLayer(session); thinkingPrompt != "" {
 		layers = append(layers, layerEntry{layer: LayerThinking, content: thinkingPrompt})
 	}
@@ -189,6 +284,9 @@ func (p *PromptComposer) Compose(session *Session, input string) string {
 	if projectContext := p.buildProjectContextLayer(); projectContext != "" {
 		layers = append(layers, layerEntry{layer: LayerProjectContext, content: projectContext})
 	}
+	if projectFiles := p.buildProjectFilesLayer(session); projectFiles != "" {
+		layers = append(layers, layerEntry{layer: LayerProjectFiles, content: projectFiles})
+	}
 
 	// ── Heavy layers (I/O, search) ──
 	// Critical layers (bootstrap + history) are loaded synchronously because
@@ -250,7 +348,7 @@ func (p *PromptComposer) ComposeMinimal() string {
 	if p.config.Instructions != "" {
 		layers = append(layers, layerEntry{
 			layer:   LayerIdentity,
-			content: "## Custom Instructions\n\n" + p.config.Instructions,
+			content: "## Custom Instructions\n\n" + p.renderedInstructions(nil),
 		})
 	}
 
@@ -281,7 +379,7 @@ func (p *PromptComposer) ComposeWithMode(session *Session, input string, mode Pr
 		if p.config.Instructions != "" {
 			layers = append(layers, layerEntry{
 				layer:   LayerIdentity,
-				content: "## Custom Instructions\n\n" + p.config.Instructions,
+				content: "## Custom Instructions\n\n" + p.renderedInstructions(session),
 			})
 		}
 		// Include bootstrap but not full skills/memory
@@ -304,7 +402,7 @@ func (p *PromptComposer) ComposeWithMode(session *Session, input string, mode Pr
 		if p.config.Instructions != "" && len(p.config.Instructions) < 200 {
 			layers = append(layers, layerEntry{
 				layer:   LayerIdentity,
-				content: "## Instructions\n\n" + p.config.Instructions,
+				content: "## Instructions\n\n" + p.renderedInstructions(session),
 			})
 		}
 		// Skip everything else
@@ -430,6 +528,88 @@ func (p *PromptComposer) buildProjectContextLayer() string {
 	return b.String()
 }
 
+// buildProjectFilesLayer discovers convention files (AGENT.md, AGENTS.md,
+// CLAUDE.md) in the workspace dir and in the session's active project root,
+// layering them into the prompt so project conventions follow the session.
+// Controlled by the project_bootstrap config (include/exclude lists and a
+// per-layer token budget).
+func (p *PromptComposer) buildProjectFilesLayer(session *Session) string {
+	if p.config.ProjectBootstrap.Disabled || p.isSubagent {
+		return ""
+	}
+	cfg := p.config.ProjectBootstrap.Effective()
+
+	excluded := make(map[string]bool, len(cfg.Exclude))
+	for _, name := range cfg.Exclude {
+		excluded[strings.ToLower(name)] = true
+	}
+
+	// Discovery roots: workspace dir first, then the bound project root.
+	type discoveryRoot struct {
+		dir   string
+		label string
+		// skipGlobal skips files the global bootstrap layer already injects.
+		skipGlobal bool
+	}
+	var roots []discoveryRoot
+	if wsDir := p.config.Heartbeat.WorkspaceDir; wsDir != "" && wsDir != "." {
+		roots = append(roots, discoveryRoot{dir: wsDir, label: "workspace", skipGlobal: true})
+	}
+	if p.projectResolver != nil {
+		if project := p.projectResolver(session.ID); project != nil && project.RootPath != "" {
+			roots = append(roots, discoveryRoot{dir: project.RootPath, label: "project " + project.ID})
+		}
+	}
+	if len(roots) == 0 {
+		return ""
+	}
+
+	var files []struct {
+		label   string
+		content string
+	}
+	for _, root := range roots {
+		for _, name := range cfg.Include {
+			if excluded[strings.ToLower(name)] {
+				continue
+			}
+			// AGENTS.md in the workspace dir is already injected by the
+			// global bootstrap layer; don't duplicate it here.
+			if root.skipGlobal && name == "AGENTS.md" {
+				continue
+			}
+			// Pass the full path as the cache key so same-named files in
+			// different roots don't collide in the bootstrap cache.
+			text := p.loadBootstrapFileCached(filepath.Join(root.dir, name), []string{""})
+			if text == "" {
+				continue
+			}
+			files = append(files, struct {
+				label   string
+				content string
+			}{fmt.Sprintf("%s (%s)", name, root.label), text})
+		}
+	}
+	if len(files) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Project Conventions (auto-discovered)\n\n")
+	b.WriteString("These convention files apply to the project/workspace this session is bound to. Follow them when working in this codebase.\n\n")
+	for _, f := range files {
+		b.WriteString(fmt.Sprintf("### %s\n\n%s\n\n", f.label, f.content))
+	}
+
+	// Per-layer token budget (~4 chars per token, matching estimateTokens).
+	result := b.String()
+	maxChars := cfg.MaxTokens * 4
+	if len(result) > maxChars {
+		result = result[:maxChars] + "\n\n... [project conventions truncated at layer budget]"
+	}
+	return result
+}
+
 // ---------- Layer Builders ----------
 
 // buildCoreLayer creates the base identity and tooling guidance.
@@ -585,6 +765,24 @@ When you generate an image with generate_image, it is automatically sent as medi
 Install with: sudo apt install -y poppler-utils ffmpeg unzip`
 }
 
+// buildPersonaLayer renders the active persona's voice guidance.
+// The session persona (set via /persona or an agent profile) takes
+// precedence over the config-level default.
+func (p *PromptComposer) buildPersonaLayer(session *Session) string {
+	name := session.GetConfig().Persona
+	if name == "" {
+		name = p.config.Personas.Default
+	}
+	if name == "" {
+		return ""
+	}
+	persona := GetPersona(name, p.config.Personas.Custom)
+	if persona == nil {
+		return ""
+	}
+	return persona.PromptText()
+}
+
 // buildThinkingLayer adds extended-thinking guidance based on session /think level.
 func (p *PromptComposer) buildThinkingLayer(session *Session) string {
 	level := session.GetThinkingLevel()
@@ -957,6 +1155,7 @@ func (p *PromptComposer) buildConversationLayer(session *Session) string {
 	}
 	var entries []formattedEntry
 	totalTokens := 0
+	multiparty := false
 
 	for i := len(history) - 1; i >= 0; i-- {
 		entry := history[i]
@@ -971,7 +1170,14 @@ func (p *PromptComposer) buildConversationLayer(session *Session) string {
 			assistMsg = assistMsg[:4000] + "..."
 		}
 
-		text := fmt.Sprintf("**User:** %s\n**Assistant:** %s\n", userMsg, assistMsg)
+		// In groups, attribute each message to its sender.
+		speaker := "User"
+		if entry.SenderName != "" {
+			speaker = entry.SenderName
+			multiparty = true
+		}
+
+		text := fmt.Sprintf("**%s:** %s\n**Assistant:** %s\n", speaker, userMsg, assistMsg)
 		tokens := estimateTokens(text)
 
 		// Stop adding if we'd exceed the budget.
@@ -995,6 +1201,11 @@ func (p *PromptComposer) buildConversationLayer(session *Session) string {
 	var b strings.Builder
 	b.WriteString("## Recent Conversation\n\n")
 
+	if multiparty {
+		b.WriteString("This is a group conversation with multiple participants; messages are attributed by sender name.\n")
+		b.WriteString("When an answer is meant for a specific participant, address them by name (e.g. \"João: here is...\").\n\n")
+	}
+
 	// If we had to skip older entries, note it.
 	if len(entries) < len(history) {
 		b.WriteString(fmt.Sprintf("_(%d older messages omitted to fit token budget)_\n\n",
@@ -1076,8 +1287,10 @@ func (p *PromptComposer) assembleLayers(layers []layerEntry) string {
 		LayerCore:          p.config.TokenBudget.System,
 		LayerSafety:        500,  // safety is short and critical
 		LayerIdentity:      1000, // custom instructions
+		LayerPersona:       300,  // persona voice guidance
 		LayerThinking:      200,  // thinking hint
 		LayerBootstrap:     4000, // bootstrap files
+		LayerProjectFiles:  p.config.ProjectBootstrap.Effective().MaxTokens,
 		LayerBuiltinSkills: 2000, // built-in tool guides
 		LayerBusiness:      1000, // workspace context
 		LayerSkills:        p.config.TokenBudget.Skills,
@@ -1,6 +1,7 @@
 package copilot
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -124,3 +125,40 @@ func TestPromptComposer_ComposeWithMode_Invalid(t *testing.T) {
 		t.Error("expected non-empty prompt for invalid mode")
 	}
 }
+
+func TestBuildConversationLayer_GroupAttribution(t *testing.T) {
+	t.Parallel()
+
+	composer := NewPromptComposer(&Config{Name: "Test"})
+
+	session := &Session{
+		ID:         "whatsapp:group",
+		maxHistory: 100,
+	}
+	session.AddMessageFrom("João", "Qual o status?", "Tudo certo.")
+	session.AddMessageFrom("Maria", "E o deploy?", "Saiu ontem.")
+
+	layer := composer.buildConversationLayer(session)
+
+	if !strings.Contains(layer, "**João:** Qual o status?") {
+		t.Errorf("missing João attribution: %q", layer)
+	}
+	if !strings.Contains(layer, "**Maria:** E o deploy?") {
+		t.Errorf("missing Maria attribution: %q", layer)
+	}
+	if !strings.Contains(layer, "group conversation") {
+		t.Errorf("missing multiparty addressing guidance: %q", layer)
+	}
+
+	// DM history without senders keeps the plain "User" label and no
+	// group guidance.
+	dm := &Session{ID: "whatsapp:dm", maxHistory: 100}
+	dm.AddMessage("Oi", "Olá!")
+	dmLayer := composer.buildConversationLayer(dm)
+	if !strings.Contains(dmLayer, "**User:** Oi") {
+		t.Errorf("missing plain User label: %q", dmLayer)
+	}
+	if strings.Contains(dmLayer, "group conversation") {
+		t.Errorf("unexpected group guidance in DM: %q", dmLayer)
+	}
+}
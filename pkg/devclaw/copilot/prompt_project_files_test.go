@@ -0,0 +1,113 @@
+package copilot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildProjectFilesLayer(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "AGENT.md"), []byte("Use tabs, not spaces."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "CLAUDE.md"), []byte("Run make test before committing."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	composer := NewPromptComposer(&Config{Name: "Test"})
+	composer.SetProjectResolver(func(sessionID string) *Project {
+		return &Project{ID: "demo", RootPath: projectDir}
+	})
+	session := &Session{ID: "test-session"}
+
+	layer := composer.buildProjectFilesLayer(session)
+	if !strings.Contains(layer, "Use tabs, not spaces.") {
+		t.Errorf("AGENT.md content missing from layer: %q", layer)
+	}
+	if !strings.Contains(layer, "Run make test before committing.") {
+		t.Errorf("CLAUDE.md content missing from layer: %q", layer)
+	}
+	if !strings.Contains(layer, "project demo") {
+		t.Errorf("project label missing from layer: %q", layer)
+	}
+}
+
+func TestBuildProjectFilesLayer_ExcludeAndDisabled(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "CLAUDE.md"), []byte("excluded content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := func(sessionID string) *Project {
+		return &Project{ID: "demo", RootPath: projectDir}
+	}
+	session := &Session{ID: "test-session"}
+
+	// Excluded filename is skipped (case-insensitive).
+	composer := NewPromptComposer(&Config{
+		Name:             "Test",
+		ProjectBootstrap: ProjectBootstrapConfig{Exclude: []string{"claude.md"}},
+	})
+	composer.SetProjectResolver(resolver)
+	if layer := composer.buildProjectFilesLayer(session); strings.Contains(layer, "excluded content") {
+		t.Errorf("excluded file leaked into layer: %q", layer)
+	}
+
+	// Disabled config produces no layer at all.
+	composer = NewPromptComposer(&Config{
+		Name:             "Test",
+		ProjectBootstrap: ProjectBootstrapConfig{Disabled: true},
+	})
+	composer.SetProjectResolver(resolver)
+	if layer := composer.buildProjectFilesLayer(session); layer != "" {
+		t.Errorf("expected empty layer when disabled, got %q", layer)
+	}
+
+	// No resolver and no workspace dir: nothing to discover.
+	composer = NewPromptComposer(&Config{Name: "Test"})
+	if layer := composer.buildProjectFilesLayer(session); layer != "" {
+		t.Errorf("expected empty layer without roots, got %q", layer)
+	}
+}
+
+func TestBuildProjectFilesLayer_TokenBudget(t *testing.T) {
+	projectDir := t.TempDir()
+	big := strings.Repeat("convention line\n", 500)
+	if err := os.WriteFile(filepath.Join(projectDir, "AGENTS.md"), []byte(big), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	composer := NewPromptComposer(&Config{
+		Name:             "Test",
+		ProjectBootstrap: ProjectBootstrapConfig{MaxTokens: 100},
+	})
+	composer.SetProjectResolver(func(sessionID string) *Project {
+		return &Project{ID: "demo", RootPath: projectDir}
+	})
+
+	layer := composer.buildProjectFilesLayer(&Session{ID: "test-session"})
+	if !strings.Contains(layer, "truncated at layer budget") {
+		t.Errorf("expected truncation marker in layer")
+	}
+	// ~4 chars per token plus the truncation marker.
+	if len(layer) > 100*4+100 {
+		t.Errorf("layer exceeds budget: %d chars", len(layer))
+	}
+}
+
+func TestProjectBootstrapConfig_Effective(t *testing.T) {
+	eff := ProjectBootstrapConfig{}.Effective()
+	if len(eff.Include) != 3 || eff.Include[0] != "AGENT.md" {
+		t.Errorf("unexpected default include list: %v", eff.Include)
+	}
+	if eff.MaxTokens != 3000 {
+		t.Errorf("unexpected default max tokens: %d", eff.MaxTokens)
+	}
+
+	custom := ProjectBootstrapConfig{Include: []string{"CONVENTIONS.md"}, MaxTokens: 500}.Effective()
+	if len(custom.Include) != 1 || custom.MaxTokens != 500 {
+		t.Errorf("explicit values overridden: %+v", custom)
+	}
+}
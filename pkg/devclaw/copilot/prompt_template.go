@@ -0,0 +1,92 @@
+// Package copilot – prompt_template.go adds Go text/template support to the
+// custom instructions. Instructions may reference runtime variables (user
+// name, workspace, date, channel, language, active project) and user-defined
+// partials from the prompt_partials config section via {{template "name" .}}.
+// Templates are validated at config load so malformed instructions fail at
+// startup instead of producing broken prompts at runtime. Instructions
+// without template markers pass through untouched.
+package copilot
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptVars holds the runtime variables available to instruction templates.
+// Fields may be empty when the information is not known for a session
+// (e.g. UserName before the first message with a display name).
+type PromptVars struct {
+	// UserName is the display name of the user the session belongs to.
+	UserName string
+
+	// Workspace is the name of the workspace the session is bound to.
+	Workspace string
+
+	// Date is today's date in the configured timezone (2006-01-02).
+	Date string
+
+	// Channel is the source channel of the session (e.g. "whatsapp").
+	Channel string
+
+	// Language is the effective response language for the session.
+	Language string
+
+	// Project is the name of the session's active project, if any.
+	Project string
+}
+
+// parsePromptTemplate parses an instruction template together with the
+// configured partials, so the main text can invoke them via
+// {{template "name" .}}. missingkey has no effect on struct fields; unknown
+// variable references fail at parse/execute time instead.
+func parsePromptTemplate(name, text string, partials map[string]string) (*template.Template, error) {
+	tmpl := template.New(name)
+	for pname, ptext := range partials {
+		if _, err := tmpl.New(pname).Parse(ptext); err != nil {
+			return nil, fmt.Errorf("partial %q: %w", pname, err)
+		}
+	}
+	if _, err := tmpl.Parse(text); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// renderPromptTemplate executes an instruction template with the given
+// variables. Text without template markers is returned as-is, so plain
+// string instructions keep working with zero overhead.
+func renderPromptTemplate(text string, partials map[string]string, vars PromptVars) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := parsePromptTemplate("instructions", text, partials)
+	if err != nil {
+		return "", fmt.Errorf("parsing: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("executing: %w", err)
+	}
+	return b.String(), nil
+}
+
+// ValidatePromptTemplates checks that the configured instructions and all
+// prompt partials parse and execute cleanly. Called at config load so typos
+// (unknown variables, unclosed actions, missing partials) fail at startup.
+func ValidatePromptTemplates(cfg *Config) error {
+	// Each partial must parse and execute on its own, so a broken partial is
+	// reported by name even when the instructions never reference it.
+	for name, text := range cfg.PromptPartials {
+		if _, err := renderPromptTemplate(text, cfg.PromptPartials, PromptVars{}); err != nil {
+			return fmt.Errorf("prompt partial %q: %w", name, err)
+		}
+	}
+
+	if _, err := renderPromptTemplate(cfg.Instructions, cfg.PromptPartials, PromptVars{}); err != nil {
+		return fmt.Errorf("instructions template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderPromptTemplate(t *testing.T) {
+	vars := PromptVars{
+		UserName:  "Ana",
+		Workspace: "work",
+		Date:      "2026-08-30",
+		Channel:   "whatsapp",
+		Language:  "pt-BR",
+		Project:   "billing",
+	}
+
+	t.Run("plain text passes through", func(t *testing.T) {
+		text := "You are a helpful assistant."
+		result, err := renderPromptTemplate(text, nil, vars)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != text {
+			t.Errorf("expected passthrough, got %q", result)
+		}
+	})
+
+	t.Run("variables are expanded", func(t *testing.T) {
+		result, err := renderPromptTemplate(
+			"Today is {{.Date}}. You assist {{.UserName}} on {{.Channel}} in {{.Language}}.",
+			nil, vars)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "Today is 2026-08-30. You assist Ana on whatsapp in pt-BR."
+		if result != expected {
+			t.Errorf("got %q, want %q", result, expected)
+		}
+	})
+
+	t.Run("partials are included", func(t *testing.T) {
+		partials := map[string]string{
+			"greeting": "Greet {{.UserName}} warmly.",
+		}
+		result, err := renderPromptTemplate(
+			"{{template \"greeting\" .}} Workspace: {{.Workspace}}.",
+			partials, vars)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "Greet Ana warmly. Workspace: work."
+		if result != expected {
+			t.Errorf("got %q, want %q", result, expected)
+		}
+	})
+
+	t.Run("unknown variable fails", func(t *testing.T) {
+		_, err := renderPromptTemplate("Hello {{.Nope}}", nil, vars)
+		if err == nil {
+			t.Fatal("expected error for unknown variable")
+		}
+	})
+
+	t.Run("missing partial fails", func(t *testing.T) {
+		_, err := renderPromptTemplate("{{template \"nope\" .}}", nil, vars)
+		if err == nil {
+			t.Fatal("expected error for missing partial")
+		}
+	})
+
+	t.Run("empty variables render as empty strings", func(t *testing.T) {
+		result, err := renderPromptTemplate("Project: {{.Project}}.", nil, PromptVars{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "Project: ." {
+			t.Errorf("got %q", result)
+		}
+	})
+}
+
+func TestValidatePromptTemplates(t *testing.T) {
+	t.Run("plain instructions are valid", func(t *testing.T) {
+		cfg := &Config{Instructions: "Be concise."}
+		if err := ValidatePromptTemplates(cfg); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("templated instructions with partials are valid", func(t *testing.T) {
+		cfg := &Config{
+			Instructions: "{{template \"tone\" .}} Today: {{.Date}}.",
+			PromptPartials: map[string]string{
+				"tone": "Be friendly with {{.UserName}}.",
+			},
+		}
+		if err := ValidatePromptTemplates(cfg); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("malformed instructions fail", func(t *testing.T) {
+		cfg := &Config{Instructions: "Hello {{.Date"}
+		err := ValidatePromptTemplates(cfg)
+		if err == nil {
+			t.Fatal("expected error for unclosed action")
+		}
+		if !strings.Contains(err.Error(), "instructions template") {
+			t.Errorf("error should name the instructions: %v", err)
+		}
+	})
+
+	t.Run("malformed partial fails with its name", func(t *testing.T) {
+		cfg := &Config{
+			Instructions:   "Plain.",
+			PromptPartials: map[string]string{"broken": "{{.Date"},
+		}
+		err := ValidatePromptTemplates(cfg)
+		if err == nil {
+			t.Fatal("expected error for broken partial")
+		}
+		if !strings.Contains(err.Error(), "broken") {
+			t.Errorf("error should name the partial: %v", err)
+		}
+	})
+}
+
+func TestComposerRendersInstructions(t *testing.T) {
+	cfg := &Config{
+		Instructions: "Assist {{.UserName}} on {{.Channel}}.",
+		Language:     "en",
+	}
+	composer := NewPromptComposer(cfg)
+
+	session := &Session{
+		ID:      "whatsapp:123",
+		Channel: "whatsapp",
+		ChatID:  "123",
+	}
+	composer.SetSessionUser(session.ID, "Ana")
+
+	rendered := composer.renderedInstructions(session)
+	if rendered != "Assist Ana on whatsapp." {
+		t.Errorf("got %q", rendered)
+	}
+
+	// Nil session (ComposeMinimal path): session-scoped vars stay empty.
+	minimal := composer.renderedInstructions(nil)
+	if minimal != "Assist  on ." {
+		t.Errorf("got %q", minimal)
+	}
+}
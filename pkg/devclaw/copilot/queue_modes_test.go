@@ -1,6 +1,8 @@
 package copilot
 
 import (
+	"log/slog"
+	"os"
 	"testing"
 
 	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
@@ -19,8 +21,8 @@ func TestParseQueueMode(t *testing.T) {
 		{"followup", QueueModeFollowup, true},
 		{"interrupt", QueueModeInterrupt, true},
 		{"steer-backlog", QueueModeSteerBacklog, true},
-		{"COLLECT", QueueModeCollect, true},     // case insensitive
-		{"  steer  ", QueueModeSteer, true},     // whitespace trimmed
+		{"COLLECT", QueueModeCollect, true}, // case insensitive
+		{"  steer  ", QueueModeSteer, true}, // whitespace trimmed
 		{"invalid", "", false},
 		{"", "", false},
 	}
@@ -126,3 +128,41 @@ func qmContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestEffectiveQueueModeFor(t *testing.T) {
+	a := &Assistant{
+		config: &Config{Queue: QueueConfig{
+			DefaultMode: QueueModeCollect,
+			ByChannel:   map[string]QueueMode{"cli": QueueModeFollowup},
+		}},
+		queueModeOverrides: make(map[string]QueueMode),
+		groupPolicyMgr: NewGroupPolicyManager(GroupsPolicyConfig{
+			Groups: []GroupPolicyConfig{
+				{ID: "123@g.us", QueueMode: QueueModeCollect},
+			},
+		}, slog.New(slog.NewTextHandler(os.Stdout, nil))),
+	}
+
+	dm := &channels.IncomingMessage{Channel: "whatsapp", ChatID: "555"}
+	group := &channels.IncomingMessage{Channel: "whatsapp", ChatID: "123@g.us", IsGroup: true}
+	cli := &channels.IncomingMessage{Channel: "cli", ChatID: "local"}
+
+	// Channel config and default apply without overrides.
+	if got := a.effectiveQueueModeFor(dm, "whatsapp:555"); got != QueueModeCollect {
+		t.Errorf("default mode: got %s, want collect", got)
+	}
+	if got := a.effectiveQueueModeFor(cli, "cli:local"); got != QueueModeFollowup {
+		t.Errorf("channel mode: got %s, want followup", got)
+	}
+
+	// Group policy overrides the channel.
+	if got := a.effectiveQueueModeFor(group, "whatsapp:123@g.us"); got != QueueModeCollect {
+		t.Errorf("group policy mode: got %s, want collect", got)
+	}
+
+	// In-chat override wins over everything.
+	a.queueModeOverrides["whatsapp:123@g.us"] = QueueModeSteer
+	if got := a.effectiveQueueModeFor(group, "whatsapp:123@g.us"); got != QueueModeSteer {
+		t.Errorf("session override: got %s, want steer", got)
+	}
+}
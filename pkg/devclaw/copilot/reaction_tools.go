@@ -0,0 +1,66 @@
+// Package copilot – reaction_tools.go registers the react tool, letting the
+// agent acknowledge a message with a lightweight emoji reaction instead of a
+// full text reply.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// RegisterReactionTools registers the react tool in the executor.
+// The tool sends an emoji reaction to the message that triggered the current
+// run. Channels without reaction support silently ignore it (Manager handles
+// the capability check).
+func RegisterReactionTools(executor *ToolExecutor, mgr *channels.Manager, logger *slog.Logger) {
+	executor.Register(
+		MakeToolDefinition("react",
+			"React to the user's message with a single emoji (👍, ❤️, 😂, 🎉, 😮, 😢). "+
+				"Use this for lightweight acknowledgements where a full text reply would be noise "+
+				"(e.g. 'ok thanks', 'nice', confirmations). If the reaction fully answers the user, "+
+				"reply with NO_REPLY afterwards so no text message is sent.",
+			map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"emoji": map[string]any{
+						"type":        "string",
+						"description": "The emoji to react with (a single emoji, e.g. '👍')",
+					},
+					"remove": map[string]any{
+						"type":        "boolean",
+						"description": "If true, remove a previous reaction instead of adding one. Default: false",
+					},
+				},
+				"required": []string{"emoji"},
+			}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			emoji, _ := args["emoji"].(string)
+			remove, _ := args["remove"].(bool)
+			if emoji == "" {
+				return nil, fmt.Errorf("emoji is required")
+			}
+
+			dt := DeliveryTargetFromContext(ctx)
+			if dt.Channel == "" || dt.ChatID == "" || dt.MessageID == "" {
+				return nil, fmt.Errorf("no message to react to in this context (reactions require a triggering channel message)")
+			}
+
+			// Sending an empty emoji removes the reaction on most platforms.
+			if remove {
+				emoji = ""
+			}
+
+			mgr.SendReaction(ctx, dt.Channel, dt.ChatID, dt.MessageID, emoji)
+			logger.Debug("agent reaction sent",
+				"channel", dt.Channel, "chat_id", dt.ChatID, "emoji", emoji)
+
+			if remove {
+				return "Reaction removed.", nil
+			}
+			return fmt.Sprintf("Reacted with %s. If this reaction fully answers the user, reply with NO_REPLY.", emoji), nil
+		},
+	)
+}
@@ -0,0 +1,255 @@
+// Package copilot – reflection.go implements post-run self-critique. After an
+// agent run, the LLM produces a short structured critique (what failed, which
+// tool calls went wrong, what information was missing) that is persisted to
+// devclaw.db as a "lesson". Lessons are searchable via the lessons_search tool
+// and can optionally be injected into future prompts for the same workspace.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// reflectionTimeout bounds the post-run critique LLM call.
+	reflectionTimeout = 45 * time.Second
+
+	// maxCritiqueFailures caps how many tool failures are fed to the critique.
+	maxCritiqueFailures = 8
+
+	// maxLessonsInPrompt is how many recent lessons are injected into prompts.
+	maxLessonsInPrompt = 5
+)
+
+// reflectionSystemPrompt asks the LLM for a structured self-critique.
+const reflectionSystemPrompt = `You review one completed agent run and produce a short self-critique as JSON with exactly these keys:
+"what_failed" (what went wrong during the run, or "" if nothing),
+"tool_mistakes" (wrong or failed tool usage, or ""),
+"missing_info" (information that was missing and had to be guessed, or ""),
+"lesson" (ONE actionable sentence for future runs; "" if there is nothing worth remembering).
+Be honest and specific. Output ONLY the JSON object.`
+
+// agentLesson is the structured critique produced after a run.
+type agentLesson struct {
+	WhatFailed   string `json:"what_failed"`
+	ToolMistakes string `json:"tool_mistakes"`
+	MissingInfo  string `json:"missing_info"`
+	Lesson       string `json:"lesson"`
+}
+
+// lessonRow is a persisted lesson as returned by searchLessons.
+type lessonRow struct {
+	ID           int64  `json:"id"`
+	WorkspaceID  string `json:"workspace_id"`
+	SessionID    string `json:"session_id"`
+	WhatFailed   string `json:"what_failed,omitempty"`
+	ToolMistakes string `json:"tool_mistakes,omitempty"`
+	MissingInfo  string `json:"missing_info,omitempty"`
+	Lesson       string `json:"lesson"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// runCritic collects tool failures during a run for the post-run critique.
+type runCritic struct {
+	mu       sync.Mutex
+	failures []string
+}
+
+func newRunCritic() *runCritic { return &runCritic{} }
+
+// Record notes a failed tool call. Successful calls are ignored.
+func (c *runCritic) Record(name string, result ToolResult) {
+	if result.Error == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.failures) >= maxCritiqueFailures {
+		return
+	}
+	c.failures = append(c.failures, fmt.Sprintf("%s: %v", name, result.Error))
+}
+
+// Failures returns a copy of the recorded tool failures.
+func (c *runCritic) Failures() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]string, len(c.failures))
+	copy(out, c.failures)
+	return out
+}
+
+// reflectOnRun asks the LLM for a self-critique of the completed run and
+// persists it as a lesson. Runs in the background after the reply is sent,
+// so failures here are logged and never affect the user.
+func (a *Assistant) reflectOnRun(workspaceID, sessionID, userMessage, response string, toolFailures []string) {
+	if a.llmClient == nil || a.devclawDB == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reflectionTimeout)
+	defer cancel()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "User request:\n%s\n\nFinal response:\n%s\n",
+		truncate(userMessage, 2000), truncate(response, 2000))
+	if len(toolFailures) > 0 {
+		b.WriteString("\nTool failures during the run:\n")
+		for _, f := range toolFailures {
+			fmt.Fprintf(&b, "- %s\n", truncate(f, 300))
+		}
+	}
+
+	critique, err := a.llmClient.Complete(ctx, reflectionSystemPrompt, nil, b.String())
+	if err != nil {
+		a.logger.Debug("self-critique call failed", "error", err)
+		return
+	}
+
+	lesson, err := parseLessonJSON(critique)
+	if err != nil {
+		a.logger.Debug("self-critique not parseable", "error", err)
+		return
+	}
+	// Nothing worth remembering: clean runs produce no lesson.
+	if strings.TrimSpace(lesson.Lesson) == "" {
+		return
+	}
+
+	_, err = a.devclawDB.Exec(`
+		INSERT INTO agent_lessons (workspace_id, session_id, what_failed, tool_mistakes, missing_info, lesson, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, datetime('now'))
+	`, workspaceID, sessionID, lesson.WhatFailed, lesson.ToolMistakes, lesson.MissingInfo, lesson.Lesson)
+	if err != nil {
+		a.logger.Warn("failed to persist lesson", "error", err)
+		return
+	}
+	a.logger.Info("run self-critique persisted",
+		"workspace", workspaceID,
+		"lesson", truncate(lesson.Lesson, 120),
+	)
+}
+
+// parseLessonJSON extracts the critique JSON from an LLM response, tolerating
+// surrounding text and markdown code fences.
+func parseLessonJSON(s string) (agentLesson, error) {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end <= start {
+		return agentLesson{}, fmt.Errorf("no JSON object in critique")
+	}
+	var lesson agentLesson
+	if err := json.Unmarshal([]byte(s[start:end+1]), &lesson); err != nil {
+		return agentLesson{}, fmt.Errorf("parsing critique: %w", err)
+	}
+	return lesson, nil
+}
+
+// searchLessons queries persisted lessons, newest first. Empty workspaceID
+// matches all workspaces; empty query lists the most recent lessons.
+func searchLessons(db *sql.DB, workspaceID, query string, limit int) ([]lessonRow, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var where []string
+	var args []any
+	if workspaceID != "" {
+		where = append(where, "workspace_id = ?")
+		args = append(args, workspaceID)
+	}
+	if query != "" {
+		pattern := "%" + query + "%"
+		where = append(where, "(lesson LIKE ? OR what_failed LIKE ? OR tool_mistakes LIKE ? OR missing_info LIKE ?)")
+		args = append(args, pattern, pattern, pattern, pattern)
+	}
+
+	q := "SELECT id, workspace_id, session_id, what_failed, tool_mistakes, missing_info, lesson, created_at FROM agent_lessons"
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying lessons: %w", err)
+	}
+	defer rows.Close()
+
+	var lessons []lessonRow
+	for rows.Next() {
+		var l lessonRow
+		if err := rows.Scan(&l.ID, &l.WorkspaceID, &l.SessionID, &l.WhatFailed, &l.ToolMistakes, &l.MissingInfo, &l.Lesson, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning lesson: %w", err)
+		}
+		lessons = append(lessons, l)
+	}
+	return lessons, rows.Err()
+}
+
+// lessonsPromptNote returns a system prompt section with the workspace's most
+// recent lessons, or "" when lesson injection is disabled or there are none.
+func (a *Assistant) lessonsPromptNote(workspaceID string) string {
+	if !a.config.Agent.LessonsInPrompt || a.devclawDB == nil {
+		return ""
+	}
+
+	lessons, err := searchLessons(a.devclawDB, workspaceID, "", maxLessonsInPrompt)
+	if err != nil || len(lessons) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n## Lessons From Past Runs\n\n")
+	b.WriteString("Self-critiques from earlier runs in this workspace. Avoid repeating these mistakes:\n")
+	for _, l := range lessons {
+		fmt.Fprintf(&b, "- %s\n", l.Lesson)
+	}
+	return b.String()
+}
+
+// RegisterLessonsTool registers the lessons_search tool backed by devclaw.db.
+func RegisterLessonsTool(executor *ToolExecutor, db *sql.DB) {
+	executor.Register(
+		MakeToolDefinition("lessons_search", "Search self-critique lessons from past agent runs (what failed, tool mistakes, missing info). Use before similar tasks to avoid repeating past mistakes.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Text to search for. Omit to list the most recent lessons.",
+				},
+				"workspace": map[string]any{
+					"type":        "string",
+					"description": "Filter by workspace ID. Omit for all workspaces.",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "Maximum results to return (default: 10)",
+				},
+			},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			query, _ := args["query"].(string)
+			workspace, _ := args["workspace"].(string)
+			limit := toolArgInt(args, "limit", 10)
+
+			lessons, err := searchLessons(db, workspace, query, limit)
+			if err != nil {
+				return nil, fmt.Errorf("searching lessons: %w", err)
+			}
+			if len(lessons) == 0 {
+				return "No lessons found.", nil
+			}
+			return map[string]any{
+				"count":   len(lessons),
+				"lessons": lessons,
+			}, nil
+		},
+	)
+}
@@ -0,0 +1,98 @@
+package copilot
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLessonJSON(t *testing.T) {
+	// Plain JSON.
+	lesson, err := parseLessonJSON(`{"what_failed":"timeout","tool_mistakes":"","missing_info":"","lesson":"set a longer timeout"}`)
+	if err != nil {
+		t.Fatalf("parseLessonJSON: %v", err)
+	}
+	if lesson.WhatFailed != "timeout" || lesson.Lesson != "set a longer timeout" {
+		t.Errorf("unexpected lesson: %+v", lesson)
+	}
+
+	// Markdown code fences and surrounding text are tolerated.
+	lesson, err = parseLessonJSON("Here is the critique:\n```json\n{\"lesson\":\"check the file exists first\"}\n```")
+	if err != nil {
+		t.Fatalf("parseLessonJSON with fences: %v", err)
+	}
+	if lesson.Lesson != "check the file exists first" {
+		t.Errorf("unexpected lesson: %+v", lesson)
+	}
+
+	// No JSON at all.
+	if _, err := parseLessonJSON("nothing to report"); err == nil {
+		t.Error("expected error for non-JSON critique")
+	}
+}
+
+func TestRunCriticRecord(t *testing.T) {
+	c := newRunCritic()
+	c.Record("bash", ToolResult{Name: "bash"}) // success: ignored
+	c.Record("web_fetch", ToolResult{Name: "web_fetch", Error: fmt.Errorf("connection refused")})
+
+	failures := c.Failures()
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d", len(failures))
+	}
+	if failures[0] != "web_fetch: connection refused" {
+		t.Errorf("unexpected failure: %q", failures[0])
+	}
+
+	// The cap prevents unbounded growth on pathological runs.
+	for i := 0; i < maxCritiqueFailures*2; i++ {
+		c.Record("bash", ToolResult{Error: fmt.Errorf("error %d", i)})
+	}
+	if got := len(c.Failures()); got != maxCritiqueFailures {
+		t.Errorf("expected %d failures after cap, got %d", maxCritiqueFailures, got)
+	}
+}
+
+func TestSearchLessons(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenDatabase: %v", err)
+	}
+	defer db.Close()
+
+	insert := `INSERT INTO agent_lessons (workspace_id, session_id, what_failed, tool_mistakes, missing_info, lesson, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, datetime('now'))`
+	if _, err := db.Exec(insert, "default", "s1", "deploy failed", "", "", "always run tests before deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(insert, "work", "s2", "", "wrong db_query table", "", "check the schema with db_schema first"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Workspace filter.
+	lessons, err := searchLessons(db, "default", "", 10)
+	if err != nil {
+		t.Fatalf("searchLessons: %v", err)
+	}
+	if len(lessons) != 1 || lessons[0].Lesson != "always run tests before deploy" {
+		t.Errorf("unexpected results: %+v", lessons)
+	}
+
+	// Text search matches the tool_mistakes column too.
+	lessons, err = searchLessons(db, "", "db_query", 10)
+	if err != nil {
+		t.Fatalf("searchLessons: %v", err)
+	}
+	if len(lessons) != 1 || lessons[0].WorkspaceID != "work" {
+		t.Errorf("unexpected results: %+v", lessons)
+	}
+
+	// No match.
+	lessons, err = searchLessons(db, "", "kubernetes", 10)
+	if err != nil {
+		t.Fatalf("searchLessons: %v", err)
+	}
+	if len(lessons) != 0 {
+		t.Errorf("expected no results, got %+v", lessons)
+	}
+}
@@ -0,0 +1,289 @@
+// Package copilot – run_trace.go implements run transcripts. Every agent run
+// gets a run_id and a turn-by-turn timeline (LLM turns, tool calls with
+// durations, interrupts), persisted to the agent_runs table and rendered via
+// the /trace command and the gateway's /api/runs endpoint — turning "why did
+// it do that?" into an inspectable artifact.
+package copilot
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunTraceConfig configures run transcript retention.
+type RunTraceConfig struct {
+	// RetentionDays is how long transcripts are kept (default 7).
+	RetentionDays int `yaml:"retention_days"`
+}
+
+// DefaultRunTraceConfig returns the default configuration.
+func DefaultRunTraceConfig() RunTraceConfig {
+	return RunTraceConfig{
+		RetentionDays: 7,
+	}
+}
+
+// Trace event types.
+const (
+	TraceLLMTurn   = "llm_turn"
+	TraceToolCall  = "tool_call"
+	TraceInterrupt = "interrupt"
+)
+
+// TraceEvent is one entry in a run's timeline.
+type TraceEvent struct {
+	Seq        int       `json:"seq"`
+	Type       string    `json:"type"`
+	Name       string    `json:"name,omitempty"`   // model or tool name
+	Detail     string    `json:"detail,omitempty"` // tokens, interrupt preview, error
+	IsError    bool      `json:"is_error,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// RunTracer collects the timeline of a single agent run. Safe for concurrent
+// use — tool results may arrive from parallel executor goroutines.
+type RunTracer struct {
+	mu        sync.Mutex
+	runID     string
+	sessionID string
+	startedAt time.Time
+	events    []TraceEvent
+}
+
+// NewRunTracer creates a tracer for one agent run.
+func NewRunTracer(runID, sessionID string) *RunTracer {
+	return &RunTracer{
+		runID:     runID,
+		sessionID: sessionID,
+		startedAt: time.Now(),
+	}
+}
+
+// RunID returns the run identifier.
+func (t *RunTracer) RunID() string { return t.runID }
+
+// SessionID returns the session this run belongs to.
+func (t *RunTracer) SessionID() string { return t.sessionID }
+
+// StartedAt returns when the run started.
+func (t *RunTracer) StartedAt() time.Time { return t.startedAt }
+
+func (t *RunTracer) add(ev TraceEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ev.Seq = len(t.events) + 1
+	if ev.At.IsZero() {
+		ev.At = time.Now()
+	}
+	t.events = append(t.events, ev)
+}
+
+// RecordLLMTurn records one LLM call.
+func (t *RunTracer) RecordLLMTurn(model string, toolCalls int, usage LLMUsage, d time.Duration) {
+	t.add(TraceEvent{
+		Type:       TraceLLMTurn,
+		Name:       model,
+		Detail:     fmt.Sprintf("%d prompt / %d completion tokens, %d tool calls", usage.PromptTokens, usage.CompletionTokens, toolCalls),
+		DurationMs: d.Milliseconds(),
+	})
+}
+
+// RecordToolResult records one completed tool execution.
+func (t *RunTracer) RecordToolResult(result ToolResult) {
+	ev := TraceEvent{
+		Type:       TraceToolCall,
+		Name:       result.Name,
+		IsError:    result.Error != nil,
+		DurationMs: result.DurationMs,
+	}
+	if result.Error != nil {
+		ev.Detail = truncateStr(result.Error.Error(), 120)
+	}
+	t.add(ev)
+}
+
+// RecordInterrupt records follow-up user messages injected mid-run.
+func (t *RunTracer) RecordInterrupt(count int) {
+	t.add(TraceEvent{
+		Type:   TraceInterrupt,
+		Detail: fmt.Sprintf("%d follow-up message(s) injected", count),
+	})
+}
+
+// Events returns a copy of the timeline.
+func (t *RunTracer) Events() []TraceEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]TraceEvent, len(t.events))
+	copy(out, t.events)
+	return out
+}
+
+// RunTrace is a persisted run transcript.
+type RunTrace struct {
+	RunID      string       `json:"run_id"`
+	SessionID  string       `json:"session_id"`
+	Status     string       `json:"status"` // "ok" or "error"
+	Error      string       `json:"error,omitempty"`
+	DurationMs int64        `json:"duration_ms"`
+	StartedAt  time.Time    `json:"started_at"`
+	Events     []TraceEvent `json:"events"`
+}
+
+// RunTraceStore persists run transcripts in devclaw.db.
+type RunTraceStore struct {
+	db *sql.DB
+}
+
+// NewRunTraceStore creates a run trace store.
+func NewRunTraceStore(db *sql.DB) *RunTraceStore {
+	return &RunTraceStore{db: db}
+}
+
+// Save persists a completed run's transcript.
+func (s *RunTraceStore) Save(tracer *RunTracer, runErr error) error {
+	status := "ok"
+	errMsg := ""
+	if runErr != nil {
+		status = "error"
+		errMsg = runErr.Error()
+	}
+
+	events, err := json.Marshal(tracer.Events())
+	if err != nil {
+		return fmt.Errorf("marshal trace events: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT OR REPLACE INTO agent_runs (run_id, session_id, status, error, duration_ms, events, started_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, tracer.RunID(), tracer.SessionID(), status, errMsg,
+		time.Since(tracer.StartedAt()).Milliseconds(), string(events),
+		tracer.StartedAt().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("insert run trace: %w", err)
+	}
+	return nil
+}
+
+// Get returns one run transcript by run ID.
+func (s *RunTraceStore) Get(runID string) (*RunTrace, error) {
+	row := s.db.QueryRow(`
+		SELECT run_id, session_id, status, error, duration_ms, events, started_at
+		FROM agent_runs WHERE run_id = ?
+	`, runID)
+	trace, err := scanRunTrace(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("run %q not found", runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query run trace: %w", err)
+	}
+	return trace, nil
+}
+
+// Recent returns the newest run transcripts, most recent first.
+func (s *RunTraceStore) Recent(limit int) ([]RunTrace, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := s.db.Query(`
+		SELECT run_id, session_id, status, error, duration_ms, events, started_at
+		FROM agent_runs ORDER BY started_at DESC LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query run traces: %w", err)
+	}
+	defer rows.Close()
+
+	var out []RunTrace
+	for rows.Next() {
+		trace, err := scanRunTrace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan run trace: %w", err)
+		}
+		out = append(out, *trace)
+	}
+	return out, rows.Err()
+}
+
+// Purge deletes transcripts older than the retention window. Returns the
+// number of deleted rows.
+func (s *RunTraceStore) Purge(retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		retentionDays = DefaultRunTraceConfig().RetentionDays
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays).UTC().Format(time.RFC3339)
+	res, err := s.db.Exec(`DELETE FROM agent_runs WHERE started_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge run traces: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+func scanRunTrace(row dlqRowScanner) (*RunTrace, error) {
+	var trace RunTrace
+	var events, startedAt string
+	if err := row.Scan(&trace.RunID, &trace.SessionID, &trace.Status, &trace.Error,
+		&trace.DurationMs, &events, &startedAt); err != nil {
+		return nil, err
+	}
+	_ = json.Unmarshal([]byte(events), &trace.Events)
+	trace.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+	return &trace, nil
+}
+
+// formatRunTrace renders a transcript as a chat-friendly timeline.
+func formatRunTrace(trace *RunTrace) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔍 Run %s (%s)\n", trace.RunID, trace.SessionID)
+	fmt.Fprintf(&b, "Status: %s | Duration: %s | Started: %s\n",
+		trace.Status, formatTraceDuration(trace.DurationMs),
+		trace.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	if trace.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", trace.Error)
+	}
+	if len(trace.Events) == 0 {
+		b.WriteString("\n(no events recorded)")
+		return b.String()
+	}
+
+	b.WriteString("\n")
+	for _, ev := range trace.Events {
+		offset := ev.At.Sub(trace.StartedAt).Round(100 * time.Millisecond)
+		switch ev.Type {
+		case TraceLLMTurn:
+			fmt.Fprintf(&b, "%2d. [%6s] 🧠 %s (%s) — %s\n",
+				ev.Seq, offset, ev.Name, formatTraceDuration(ev.DurationMs), ev.Detail)
+		case TraceToolCall:
+			icon := "🔧"
+			if ev.IsError {
+				icon = "❌"
+			}
+			fmt.Fprintf(&b, "%2d. [%6s] %s %s (%s)", ev.Seq, offset, icon, ev.Name, formatTraceDuration(ev.DurationMs))
+			if ev.Detail != "" {
+				fmt.Fprintf(&b, " — %s", ev.Detail)
+			}
+			b.WriteString("\n")
+		case TraceInterrupt:
+			fmt.Fprintf(&b, "%2d. [%6s] ✋ %s\n", ev.Seq, offset, ev.Detail)
+		default:
+			fmt.Fprintf(&b, "%2d. [%6s] %s %s\n", ev.Seq, offset, ev.Type, ev.Detail)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatTraceDuration renders milliseconds compactly (820ms, 4.2s).
+func formatTraceDuration(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
@@ -0,0 +1,150 @@
+package copilot
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunTracerTimeline(t *testing.T) {
+	tracer := NewRunTracer("run1", "whatsapp:123")
+
+	tracer.RecordLLMTurn("gpt-4o", 2, LLMUsage{PromptTokens: 100, CompletionTokens: 20}, 800*time.Millisecond)
+	tracer.RecordToolResult(ToolResult{Name: "web_search", DurationMs: 300})
+	tracer.RecordToolResult(ToolResult{Name: "read_file", Error: errors.New("not found"), DurationMs: 5})
+	tracer.RecordInterrupt(1)
+	tracer.RecordLLMTurn("gpt-4o", 0, LLMUsage{PromptTokens: 150, CompletionTokens: 40}, 600*time.Millisecond)
+
+	events := tracer.Events()
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+	for i, ev := range events {
+		if ev.Seq != i+1 {
+			t.Errorf("event %d has seq %d", i, ev.Seq)
+		}
+		if ev.At.IsZero() {
+			t.Errorf("event %d has no timestamp", i)
+		}
+	}
+	if events[0].Type != TraceLLMTurn || events[0].Name != "gpt-4o" || events[0].DurationMs != 800 {
+		t.Errorf("unexpected llm turn: %+v", events[0])
+	}
+	if events[1].Type != TraceToolCall || events[1].IsError {
+		t.Errorf("unexpected tool event: %+v", events[1])
+	}
+	if !events[2].IsError || !strings.Contains(events[2].Detail, "not found") {
+		t.Errorf("tool error not recorded: %+v", events[2])
+	}
+	if events[3].Type != TraceInterrupt {
+		t.Errorf("unexpected interrupt event: %+v", events[3])
+	}
+}
+
+func TestRunTraceStoreLifecycle(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+	store := NewRunTraceStore(db)
+
+	tracer := NewRunTracer("run1", "cli:local")
+	tracer.RecordLLMTurn("gpt-4o", 1, LLMUsage{PromptTokens: 10}, 100*time.Millisecond)
+	tracer.RecordToolResult(ToolResult{Name: "get_time", DurationMs: 2})
+
+	if err := store.Save(tracer, nil); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	trace, err := store.Get("run1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if trace.SessionID != "cli:local" || trace.Status != "ok" {
+		t.Errorf("unexpected trace: %+v", trace)
+	}
+	if len(trace.Events) != 2 || trace.Events[1].Name != "get_time" {
+		t.Errorf("events not round-tripped: %+v", trace.Events)
+	}
+
+	// Failed runs record status and error.
+	failed := NewRunTracer("run2", "cli:local")
+	if err := store.Save(failed, errors.New("LLM call failed")); err != nil {
+		t.Fatalf("Save failed run: %v", err)
+	}
+	trace, err = store.Get("run2")
+	if err != nil {
+		t.Fatalf("Get failed run: %v", err)
+	}
+	if trace.Status != "error" || trace.Error != "LLM call failed" {
+		t.Errorf("failure not recorded: %+v", trace)
+	}
+
+	traces, err := store.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(traces) != 2 {
+		t.Errorf("expected 2 runs, got %d", len(traces))
+	}
+
+	if _, err := store.Get("nope"); err == nil {
+		t.Error("Get with unknown run ID should fail")
+	}
+}
+
+func TestRunTraceStorePurge(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+	store := NewRunTraceStore(db)
+
+	old := NewRunTracer("run-old", "cli:local")
+	old.startedAt = time.Now().AddDate(0, 0, -30)
+	if err := store.Save(old, nil); err != nil {
+		t.Fatalf("Save old: %v", err)
+	}
+	fresh := NewRunTracer("run-fresh", "cli:local")
+	if err := store.Save(fresh, nil); err != nil {
+		t.Fatalf("Save fresh: %v", err)
+	}
+
+	n, err := store.Purge(7)
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 purged run, got %d", n)
+	}
+	traces, _ := store.Recent(10)
+	if len(traces) != 1 || traces[0].RunID != "run-fresh" {
+		t.Errorf("expected only the fresh run to remain, got %+v", traces)
+	}
+}
+
+func TestFormatRunTrace(t *testing.T) {
+	trace := &RunTrace{
+		RunID:      "run1",
+		SessionID:  "whatsapp:123",
+		Status:     "ok",
+		DurationMs: 4200,
+		StartedAt:  time.Now(),
+		Events: []TraceEvent{
+			{Seq: 1, Type: TraceLLMTurn, Name: "gpt-4o", Detail: "100 prompt / 20 completion tokens, 1 tool calls", DurationMs: 820, At: time.Now()},
+			{Seq: 2, Type: TraceToolCall, Name: "web_search", DurationMs: 1500, At: time.Now()},
+			{Seq: 3, Type: TraceToolCall, Name: "read_file", IsError: true, Detail: "not found", DurationMs: 5, At: time.Now()},
+		},
+	}
+
+	out := formatRunTrace(trace)
+	for _, want := range []string{"run1", "whatsapp:123", "gpt-4o", "web_search", "1.5s", "not found", "4.2s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered trace missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+// Package copilot – runbook.go implements step-by-step runbook execution.
+// Runbooks are Markdown files where each "## " heading is a step; HTML
+// comment annotations control execution:
+//
+//	<!-- expect: substring -->  step output must contain the substring
+//	<!-- risky -->              the user must confirm before the step runs
+//
+// Each step runs through the agent loop (tools enabled), its output is
+// checked against the expectation, and progress is persisted to the
+// central database so an interrupted runbook resumes at the right step
+// after a restart (/runbook continue).
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// Runbook run statuses.
+const (
+	RunbookRunning      = "running"
+	RunbookAwaitConfirm = "awaiting_confirmation"
+	RunbookFailed       = "failed"
+	RunbookCompleted    = "completed"
+	RunbookAborted      = "aborted"
+)
+
+// runbookStepTimeout bounds a single step's agent run.
+const runbookStepTimeout = 120 * time.Second
+
+// RunbookStep is one executable step of a runbook.
+type RunbookStep struct {
+	// Title is the step heading text.
+	Title string
+	// Body is the step's instructions for the agent.
+	Body string
+	// Expect is a substring the step output must contain (empty = no check).
+	Expect string
+	// Risky requires explicit user confirmation before the step runs.
+	Risky bool
+}
+
+// Runbook is a parsed runbook document.
+type Runbook struct {
+	Title string
+	Steps []RunbookStep
+}
+
+var (
+	runbookExpectRe = regexp.MustCompile(`<!--\s*expect:\s*(.*?)\s*-->`)
+	runbookRiskyRe  = regexp.MustCompile(`<!--\s*risky\s*-->`)
+)
+
+// parseRunbook parses a Markdown runbook into titled steps.
+func parseRunbook(content string) (*Runbook, error) {
+	rb := &Runbook{}
+	var current *RunbookStep
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "# ") && rb.Title == "" && current == nil {
+			rb.Title = strings.TrimSpace(strings.TrimPrefix(trimmed, "# "))
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "## ") {
+			if current != nil {
+				rb.Steps = append(rb.Steps, *current)
+			}
+			title := strings.TrimSpace(strings.TrimPrefix(trimmed, "## "))
+			step := RunbookStep{}
+			if runbookRiskyRe.MatchString(title) {
+				step.Risky = true
+				title = strings.TrimSpace(runbookRiskyRe.ReplaceAllString(title, ""))
+			}
+			step.Title = title
+			current = &step
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if m := runbookExpectRe.FindStringSubmatch(trimmed); m != nil {
+			current.Expect = m[1]
+			continue
+		}
+		if runbookRiskyRe.MatchString(trimmed) {
+			current.Risky = true
+			continue
+		}
+		current.Body += line + "\n"
+	}
+	if current != nil {
+		rb.Steps = append(rb.Steps, *current)
+	}
+
+	if len(rb.Steps) == 0 {
+		return nil, fmt.Errorf("runbook has no steps (expected \"## \" headings)")
+	}
+	for i := range rb.Steps {
+		rb.Steps[i].Body = strings.TrimSpace(rb.Steps[i].Body)
+	}
+	return rb, nil
+}
+
+// RunbookRun is the persisted progress of one runbook execution.
+type RunbookRun struct {
+	ID          string
+	Path        string
+	Title       string
+	SessionID   string
+	Channel     string
+	ChatID      string
+	CurrentStep int
+	TotalSteps  int
+	Status      string
+	StartedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// RunbookStore persists runbook progress in the central database.
+type RunbookStore struct {
+	db *sql.DB
+}
+
+// NewRunbookStore creates a runbook progress store.
+func NewRunbookStore(db *sql.DB) *RunbookStore {
+	return &RunbookStore{db: db}
+}
+
+// Save upserts a run's progress.
+func (s *RunbookStore) Save(run *RunbookRun) error {
+	run.UpdatedAt = time.Now().UTC()
+	_, err := s.db.Exec(
+		`INSERT INTO runbook_runs (id, path, title, session_id, channel, chat_id, current_step, total_steps, status, started_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET current_step = excluded.current_step,
+			status = excluded.status, updated_at = excluded.updated_at`,
+		run.ID, run.Path, run.Title, run.SessionID, run.Channel, run.ChatID,
+		run.CurrentStep, run.TotalSteps, run.Status,
+		run.StartedAt.UTC().Format(time.RFC3339), run.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("save runbook run: %w", err)
+	}
+	return nil
+}
+
+// ActiveRun returns the unfinished run for a chat, or nil.
+func (s *RunbookStore) ActiveRun(channel, chatID string) (*RunbookRun, error) {
+	row := s.db.QueryRow(
+		`SELECT id, path, title, session_id, channel, chat_id, current_step, total_steps, status, started_at, updated_at
+		 FROM runbook_runs
+		 WHERE channel = ? AND chat_id = ? AND status IN (?, ?)
+		 ORDER BY started_at DESC LIMIT 1`,
+		channel, chatID, RunbookRunning, RunbookAwaitConfirm)
+
+	run := &RunbookRun{}
+	var startedAt, updatedAt string
+	err := row.Scan(&run.ID, &run.Path, &run.Title, &run.SessionID, &run.Channel, &run.ChatID,
+		&run.CurrentStep, &run.TotalSteps, &run.Status, &startedAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load runbook run: %w", err)
+	}
+	run.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+	run.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return run, nil
+}
+
+// runbookCommand handles /runbook start <path> | continue | status | abort.
+func (a *Assistant) runbookCommand(args []string, msg *channels.IncomingMessage) string {
+	if a.runbookStore == nil {
+		return "Runbook execution requires the central database."
+	}
+	if len(args) == 0 {
+		return "Usage: /runbook start <path> | continue | status | abort"
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "start":
+		if len(args) < 2 {
+			return "Usage: /runbook start <path>"
+		}
+		if active, _ := a.runbookStore.ActiveRun(msg.Channel, msg.ChatID); active != nil {
+			return fmt.Sprintf("Runbook %q is already in progress (step %d/%d). Use /runbook continue or /runbook abort.",
+				active.Title, active.CurrentStep+1, active.TotalSteps)
+		}
+
+		path := args[1]
+		rb, err := a.loadRunbook(path)
+		if err != nil {
+			return err.Error()
+		}
+
+		run := &RunbookRun{
+			ID:         fmt.Sprintf("rb-%d", time.Now().Unix()),
+			Path:       path,
+			Title:      rb.Title,
+			SessionID:  MakeSessionID(msg.Channel, msg.ChatID),
+			Channel:    msg.Channel,
+			ChatID:     msg.ChatID,
+			TotalSteps: len(rb.Steps),
+			Status:     RunbookRunning,
+			StartedAt:  time.Now().UTC(),
+		}
+		if err := a.runbookStore.Save(run); err != nil {
+			a.logger.Warn("runbook persist failed", "error", err)
+		}
+		return a.executeRunbook(run, rb, false)
+
+	case "continue":
+		run, err := a.runbookStore.ActiveRun(msg.Channel, msg.ChatID)
+		if err != nil || run == nil {
+			return "No runbook in progress. Start one with /runbook start <path>."
+		}
+		rb, err := a.loadRunbook(run.Path)
+		if err != nil {
+			return err.Error()
+		}
+		// A continue on an awaiting step counts as the risky confirmation.
+		confirmed := run.Status == RunbookAwaitConfirm
+		run.Status = RunbookRunning
+		return a.executeRunbook(run, rb, confirmed)
+
+	case "status":
+		run, err := a.runbookStore.ActiveRun(msg.Channel, msg.ChatID)
+		if err != nil || run == nil {
+			return "No runbook in progress."
+		}
+		return fmt.Sprintf("Runbook %q: step %d/%d (%s, started %s)",
+			run.Title, run.CurrentStep+1, run.TotalSteps, run.Status,
+			run.StartedAt.Local().Format("Jan 2 15:04"))
+
+	case "abort":
+		run, err := a.runbookStore.ActiveRun(msg.Channel, msg.ChatID)
+		if err != nil || run == nil {
+			return "No runbook in progress."
+		}
+		run.Status = RunbookAborted
+		if err := a.runbookStore.Save(run); err != nil {
+			a.logger.Warn("runbook persist failed", "error", err)
+		}
+		return fmt.Sprintf("Runbook %q aborted at step %d/%d.", run.Title, run.CurrentStep+1, run.TotalSteps)
+
+	default:
+		return "Usage: /runbook start <path> | continue | status | abort"
+	}
+}
+
+// loadRunbook reads and parses a runbook file.
+func (a *Assistant) loadRunbook(path string) (*Runbook, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read runbook %q: %w", path, err)
+	}
+	rb, err := parseRunbook(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse runbook %q: %w", path, err)
+	}
+	if rb.Title == "" {
+		rb.Title = path
+	}
+	return rb, nil
+}
+
+// executeRunbook runs steps from the current position until completion or
+// a pause point (risky step awaiting confirmation, failed expectation).
+// confirmed marks the current step's risky confirmation as already given.
+func (a *Assistant) executeRunbook(run *RunbookRun, rb *Runbook, confirmed bool) string {
+	var progress strings.Builder
+	progress.WriteString(fmt.Sprintf("*Runbook: %s*\n", rb.Title))
+
+	for i := run.CurrentStep; i < len(rb.Steps); i++ {
+		step := rb.Steps[i]
+
+		if step.Risky && !(confirmed && i == run.CurrentStep) {
+			run.CurrentStep = i
+			run.Status = RunbookAwaitConfirm
+			if err := a.runbookStore.Save(run); err != nil {
+				a.logger.Warn("runbook persist failed", "error", err)
+			}
+			progress.WriteString(fmt.Sprintf(
+				"\n⚠️ Step %d/%d (%s) is marked risky:\n%s\n\nReply /runbook continue to run it, or /runbook abort.",
+				i+1, len(rb.Steps), step.Title, truncate(step.Body, 300)))
+			return progress.String()
+		}
+
+		output, err := a.runRunbookStep(rb, step)
+		if err != nil {
+			run.CurrentStep = i
+			run.Status = RunbookFailed
+			if saveErr := a.runbookStore.Save(run); saveErr != nil {
+				a.logger.Warn("runbook persist failed", "error", saveErr)
+			}
+			progress.WriteString(fmt.Sprintf("\n❌ Step %d/%d (%s) failed: %v", i+1, len(rb.Steps), step.Title, err))
+			return progress.String()
+		}
+
+		if step.Expect != "" && !strings.Contains(output, step.Expect) {
+			run.CurrentStep = i
+			run.Status = RunbookFailed
+			if saveErr := a.runbookStore.Save(run); saveErr != nil {
+				a.logger.Warn("runbook persist failed", "error", saveErr)
+			}
+			progress.WriteString(fmt.Sprintf(
+				"\n❌ Step %d/%d (%s): output did not contain expected %q.\nOutput: %s",
+				i+1, len(rb.Steps), step.Title, step.Expect, truncate(output, 300)))
+			return progress.String()
+		}
+
+		progress.WriteString(fmt.Sprintf("\n✅ Step %d/%d: %s", i+1, len(rb.Steps), step.Title))
+		run.CurrentStep = i + 1
+		run.Status = RunbookRunning
+		if err := a.runbookStore.Save(run); err != nil {
+			a.logger.Warn("runbook persist failed", "error", err)
+		}
+	}
+
+	run.Status = RunbookCompleted
+	if err := a.runbookStore.Save(run); err != nil {
+		a.logger.Warn("runbook persist failed", "error", err)
+	}
+	progress.WriteString("\n\n🏁 Runbook completed.")
+	return progress.String()
+}
+
+// runRunbookStep executes one step through the agent loop with tools.
+func (a *Assistant) runRunbookStep(rb *Runbook, step RunbookStep) (string, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, runbookStepTimeout)
+	defer cancel()
+
+	stepPrompt := fmt.Sprintf(
+		"[RUNBOOK STEP — execute exactly as written]\n"+
+			"You are executing step %q of runbook %q. "+
+			"Perform only what this step describes, using tools as needed, "+
+			"then report the outcome concisely.\n\n%s",
+		step.Title, rb.Title, step.Body)
+
+	stepAgentCfg := AgentConfig{
+		MaxTurns:              5,
+		RunTimeoutSeconds:     int(runbookStepTimeout.Seconds()),
+		LLMCallTimeoutSeconds: 60,
+		MaxContinuations:      0,
+		ReflectionEnabled:     false,
+	}
+
+	agent := NewAgentRunWithConfig(a.llmClient, a.toolExecutor, stepAgentCfg, a.logger)
+	result, err := agent.Run(ctx, a.promptComposer.ComposeMinimal(), nil, stepPrompt)
+	if err != nil {
+		return "", fmt.Errorf("step agent run: %w", err)
+	}
+	return StripInternalTags(result), nil
+}
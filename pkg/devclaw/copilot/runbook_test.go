@@ -0,0 +1,147 @@
+package copilot
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const testRunbook = `# Restart the API
+
+Some intro text that is not a step.
+
+## Check pod status
+Run kubectl get pods and report the states.
+<!-- expect: Running -->
+
+## Restart the deployment <!-- risky -->
+Run kubectl rollout restart deployment/api.
+
+## Verify recovery
+Check that the health endpoint returns 200.
+<!-- risky -->
+<!-- expect: 200 -->
+`
+
+func TestParseRunbook(t *testing.T) {
+	rb, err := parseRunbook(testRunbook)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if rb.Title != "Restart the API" {
+		t.Errorf("got title %q", rb.Title)
+	}
+	if len(rb.Steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(rb.Steps))
+	}
+
+	if rb.Steps[0].Title != "Check pod status" || rb.Steps[0].Risky {
+		t.Errorf("step 1 wrong: %+v", rb.Steps[0])
+	}
+	if rb.Steps[0].Expect != "Running" {
+		t.Errorf("step 1 expect %q", rb.Steps[0].Expect)
+	}
+
+	// Risky annotation in the heading is stripped from the title.
+	if rb.Steps[1].Title != "Restart the deployment" || !rb.Steps[1].Risky {
+		t.Errorf("step 2 wrong: %+v", rb.Steps[1])
+	}
+
+	// Risky annotation in the body also works, combined with expect.
+	if !rb.Steps[2].Risky || rb.Steps[2].Expect != "200" {
+		t.Errorf("step 3 wrong: %+v", rb.Steps[2])
+	}
+
+	// Annotations are not part of the step body.
+	for i, step := range rb.Steps {
+		if strings.Contains(step.Body, "<!--") {
+			t.Errorf("step %d body contains annotation: %q", i+1, step.Body)
+		}
+	}
+}
+
+func TestParseRunbookNoSteps(t *testing.T) {
+	if _, err := parseRunbook("# Just a title\n\nProse without headings.\n"); err == nil {
+		t.Error("expected error for runbook without steps")
+	}
+}
+
+func testRunbookDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	schema := `
+	CREATE TABLE IF NOT EXISTS runbook_runs (
+		id           TEXT PRIMARY KEY,
+		path         TEXT NOT NULL,
+		title        TEXT DEFAULT '',
+		session_id   TEXT DEFAULT '',
+		channel      TEXT DEFAULT '',
+		chat_id      TEXT DEFAULT '',
+		current_step INTEGER DEFAULT 0,
+		total_steps  INTEGER DEFAULT 0,
+		status       TEXT DEFAULT 'running',
+		started_at   TEXT NOT NULL,
+		updated_at   TEXT NOT NULL
+	);`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRunbookStoreResume(t *testing.T) {
+	store := NewRunbookStore(testRunbookDB(t))
+
+	run := &RunbookRun{
+		ID:         "rb-1",
+		Path:       "runbooks/restart.md",
+		Title:      "Restart the API",
+		Channel:    "whatsapp",
+		ChatID:     "123",
+		TotalSteps: 3,
+		Status:     RunbookRunning,
+	}
+	if err := store.Save(run); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// Progress update persists.
+	run.CurrentStep = 2
+	run.Status = RunbookAwaitConfirm
+	if err := store.Save(run); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	loaded, err := store.ActiveRun("whatsapp", "123")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("expected active run")
+	}
+	if loaded.CurrentStep != 2 || loaded.Status != RunbookAwaitConfirm {
+		t.Errorf("resumed at step %d status %s", loaded.CurrentStep, loaded.Status)
+	}
+
+	// Other chats have no active run.
+	if other, _ := store.ActiveRun("whatsapp", "456"); other != nil {
+		t.Error("unexpected run for other chat")
+	}
+
+	// Finished runs are no longer active.
+	run.Status = RunbookCompleted
+	if err := store.Save(run); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if done, _ := store.ActiveRun("whatsapp", "123"); done != nil {
+		t.Error("completed run should not be active")
+	}
+}
@@ -0,0 +1,182 @@
+// Package copilot – sandbox_fs.go implements optional per-session scratch
+// directories ("jails") for file tools. When enabled, non-owner callers get
+// their file operations confined to a session-scoped directory: paths are
+// re-rooted inside the jail so escapes via ".." are impossible, and scratch
+// directories are garbage-collected once their session expires — reducing
+// blast radius for group-triggered file operations.
+package copilot
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// sandboxFSDefaultRoot is where session scratch directories live.
+	sandboxFSDefaultRoot = "./data/sandbox"
+
+	// sandboxFSDefaultTTLHours is how long an idle scratch dir survives
+	// after its session disappears.
+	sandboxFSDefaultTTLHours = 24
+
+	// sandboxFSGCInterval is how often expired scratch dirs are collected.
+	sandboxFSGCInterval = 1 * time.Hour
+)
+
+// SandboxFSConfig configures per-session scratch directories for file tools.
+type SandboxFSConfig struct {
+	// Enabled turns on per-session file jails for non-owner callers.
+	Enabled bool `yaml:"enabled"`
+
+	// Root is the base directory for scratch dirs (default: ./data/sandbox).
+	Root string `yaml:"root"`
+
+	// TTLHours is how long idle scratch dirs are kept before garbage
+	// collection (default: 24).
+	TTLHours int `yaml:"ttl_hours"`
+}
+
+// DefaultSandboxFSConfig returns the default (disabled) sandbox config.
+func DefaultSandboxFSConfig() SandboxFSConfig {
+	return SandboxFSConfig{
+		Enabled:  false,
+		Root:     sandboxFSDefaultRoot,
+		TTLHours: sandboxFSDefaultTTLHours,
+	}
+}
+
+// SandboxFS manages per-session scratch directories.
+type SandboxFS struct {
+	config SandboxFSConfig
+	logger *slog.Logger
+	cancel context.CancelFunc
+}
+
+// NewSandboxFS creates a sandbox filesystem manager. Zero-value config fields
+// fall back to the defaults.
+func NewSandboxFS(config SandboxFSConfig, logger *slog.Logger) *SandboxFS {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.Root == "" {
+		config.Root = sandboxFSDefaultRoot
+	}
+	if config.TTLHours <= 0 {
+		config.TTLHours = sandboxFSDefaultTTLHours
+	}
+	return &SandboxFS{
+		config: config,
+		logger: logger.With("component", "sandbox_fs"),
+	}
+}
+
+// Enabled reports whether per-session jails are active.
+func (s *SandboxFS) Enabled() bool {
+	return s.config.Enabled
+}
+
+// sessionDirName converts a session ID into a filesystem-safe directory name.
+func sessionDirName(sessionID string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, sessionID)
+}
+
+// SessionDir returns the scratch directory for a session, creating it with
+// restrictive permissions on first use.
+func (s *SandboxFS) SessionDir(sessionID string) string {
+	dir := filepath.Join(s.config.Root, sessionDirName(sessionID))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		s.logger.Warn("failed to create session scratch dir", "dir", dir, "error", err)
+	}
+	return dir
+}
+
+// Resolve re-roots a file path inside the session's scratch directory.
+// Cleaning the path as if it were absolute strips any ".." components, so the
+// result can never escape the jail.
+func (s *SandboxFS) Resolve(sessionID, path string) string {
+	dir := s.SessionDir(sessionID)
+	return filepath.Join(dir, filepath.Clean("/"+path))
+}
+
+// Remove deletes a session's scratch directory (e.g. on session reset).
+func (s *SandboxFS) Remove(sessionID string) {
+	dir := filepath.Join(s.config.Root, sessionDirName(sessionID))
+	if err := os.RemoveAll(dir); err != nil {
+		s.logger.Warn("failed to remove session scratch dir", "dir", dir, "error", err)
+	}
+}
+
+// Start launches the periodic garbage collector. isActive reports whether a
+// scratch directory (by its sanitized name) still belongs to a live session.
+func (s *SandboxFS) Start(ctx context.Context, isActive func(dirName string) bool) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(sandboxFSGCInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.gc(isActive)
+			}
+		}
+	}()
+
+	s.logger.Info("sandbox fs started", "root", s.config.Root, "ttl_hours", s.config.TTLHours)
+}
+
+// Stop halts the garbage collector.
+func (s *SandboxFS) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// gc removes scratch dirs whose session expired and that have been idle
+// longer than the TTL.
+func (s *SandboxFS) gc(isActive func(dirName string) bool) {
+	entries, err := os.ReadDir(s.config.Root)
+	if err != nil {
+		return // Root not created yet — nothing to collect.
+	}
+
+	cutoff := time.Now().Add(-time.Duration(s.config.TTLHours) * time.Hour)
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if isActive != nil && isActive(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		dir := filepath.Join(s.config.Root, entry.Name())
+		if err := os.RemoveAll(dir); err != nil {
+			s.logger.Warn("failed to collect scratch dir", "dir", dir, "error", err)
+			continue
+		}
+		removed++
+	}
+	if removed > 0 {
+		s.logger.Info("expired scratch dirs collected", "removed", removed)
+	}
+}
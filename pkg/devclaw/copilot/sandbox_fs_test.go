@@ -0,0 +1,112 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSandboxFS(t *testing.T) *SandboxFS {
+	t.Helper()
+	return NewSandboxFS(SandboxFSConfig{
+		Enabled: true,
+		Root:    t.TempDir(),
+	}, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+}
+
+func TestSandboxFSResolve(t *testing.T) {
+	s := testSandboxFS(t)
+
+	dir := s.SessionDir("whatsapp:123")
+	resolved := s.Resolve("whatsapp:123", "notes/todo.txt")
+	if resolved != filepath.Join(dir, "notes", "todo.txt") {
+		t.Errorf("resolved = %q, want inside %q", resolved, dir)
+	}
+
+	// Sessions get distinct scratch dirs.
+	other := s.Resolve("whatsapp:456", "notes/todo.txt")
+	if strings.HasPrefix(other, dir) {
+		t.Error("sessions share a scratch dir")
+	}
+}
+
+func TestSandboxFSBlocksEscapes(t *testing.T) {
+	s := testSandboxFS(t)
+	dir := s.SessionDir("whatsapp:123")
+
+	escapes := []string{
+		"../../../etc/passwd",
+		"/etc/passwd",
+		"notes/../../../../etc/shadow",
+		"..",
+	}
+	for _, path := range escapes {
+		resolved := s.Resolve("whatsapp:123", path)
+		if !strings.HasPrefix(resolved, dir) {
+			t.Errorf("Resolve(%q) = %q escapes the jail %q", path, resolved, dir)
+		}
+	}
+}
+
+func TestSandboxFSSessionDirPermissions(t *testing.T) {
+	s := testSandboxFS(t)
+
+	dir := s.SessionDir("whatsapp:123@g.us")
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("stat scratch dir: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("scratch dir perm = %o, want 0700", perm)
+	}
+
+	// Session IDs are sanitized into safe directory names.
+	name := filepath.Base(dir)
+	if strings.ContainsAny(name, ":@/") {
+		t.Errorf("unsanitized dir name %q", name)
+	}
+}
+
+func TestSandboxFSRemove(t *testing.T) {
+	s := testSandboxFS(t)
+
+	path := s.Resolve("whatsapp:123", "scratch.txt")
+	if err := os.WriteFile(path, []byte("data"), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	s.Remove("whatsapp:123")
+	if _, err := os.Stat(filepath.Dir(path)); !os.IsNotExist(err) {
+		t.Error("scratch dir still exists after Remove")
+	}
+}
+
+func TestSandboxFSGC(t *testing.T) {
+	s := testSandboxFS(t)
+
+	activeDir := s.SessionDir("whatsapp:active")
+	staleDir := s.SessionDir("whatsapp:stale")
+
+	// Backdate both dirs beyond the TTL; only the inactive one is collected.
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(activeDir, old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if err := os.Chtimes(staleDir, old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	s.gc(func(dirName string) bool {
+		return dirName == sessionDirName("whatsapp:active")
+	})
+
+	if _, err := os.Stat(activeDir); err != nil {
+		t.Error("active session scratch dir was collected")
+	}
+	if _, err := os.Stat(staleDir); !os.IsNotExist(err) {
+		t.Error("stale scratch dir survived GC")
+	}
+}
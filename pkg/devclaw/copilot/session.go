@@ -96,24 +96,41 @@ type SessionConfig struct {
 	// ThinkingLevel controls extended thinking: "", "off", "low", "medium", "high".
 	ThinkingLevel string `yaml:"thinking_level"`
 
+	// Persona is the active voice preset for this session (see personas config).
+	Persona string `yaml:"persona"`
+
 	// Verbose enables narration of tool calls and internal steps.
 	Verbose bool `yaml:"verbose"`
+
+	// Verify enables the fact-check pass for this session (see /verify).
+	Verify bool `yaml:"verify"`
 }
 
 // ConversationEntry representa uma troca de mensagem na sessão.
 type ConversationEntry struct {
 	UserMessage       string
 	AssistantResponse string
-	Timestamp         time.Time
+	// SenderName é o nome de exibição de quem enviou a mensagem (grupos).
+	// Vazio em DMs, onde há apenas um interlocutor.
+	SenderName string
+	Timestamp  time.Time
 }
 
 // AddMessage adiciona uma nova entrada de conversa à sessão.
 // Aplica o limite de maxHistory, removendo mensagens antigas quando excedido.
 // Persiste a entrada em disco se persistence estiver configurada.
 func (s *Session) AddMessage(userMsg, assistantResp string) {
+	s.AddMessageFrom("", userMsg, assistantResp)
+}
+
+// AddMessageFrom adiciona uma entrada de conversa atribuída a um remetente.
+// Em grupos, senderName identifica o participante que falou; em DMs use
+// AddMessage (sem atribuição).
+func (s *Session) AddMessageFrom(senderName, userMsg, assistantResp string) {
 	entry := ConversationEntry{
 		UserMessage:       userMsg,
 		AssistantResponse: assistantResp,
+		SenderName:        senderName,
 		Timestamp:         time.Now(),
 	}
 
@@ -277,6 +294,13 @@ func (s *Session) SetThinkingLevel(level string) {
 	s.config.ThinkingLevel = level
 }
 
+// SetLanguage sets the preferred reply language. Thread-safe.
+func (s *Session) SetLanguage(lang string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config.Language = lang
+}
+
 // CompactHistory replaces the full history with a summary entry,
 // keeping only the most recent entries. Returns the old entries for
 // memory extraction.
@@ -314,6 +338,14 @@ type SessionStore struct {
 	logger      *slog.Logger
 	mu          sync.RWMutex
 	persistence SessionPersister
+
+	// onExpire, quando configurado, recebe cada sessão idle antes de ser
+	// removida (arquivamento em vez de descarte; ver session_archive.go).
+	onExpire func(*Session)
+
+	// archiveRestore, quando configurado, é consultado em GetOrCreate para
+	// restaurar uma sessão arquivada (resumo + fatos) do devclaw.db.
+	archiveRestore func(sessionID string) (summary string, facts []string, ok bool)
 }
 
 // NewSessionStore cria um novo store de sessões.
@@ -336,6 +368,15 @@ func (ss *SessionStore) SetPersistence(p SessionPersister) {
 	ss.persistence = p
 }
 
+// SetArchival wires idle-session archival: onExpire runs for each session the
+// pruner removes, and restore is consulted when a pruned chat comes back.
+func (ss *SessionStore) SetArchival(onExpire func(*Session), restore func(sessionID string) (string, []string, bool)) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.onExpire = onExpire
+	ss.archiveRestore = restore
+}
+
 // GetOrCreate retorna a sessão existente ou cria uma nova para o canal e chatID.
 // Se persistence estiver configurada, tenta carregar do disco antes de criar.
 func (ss *SessionStore) GetOrCreate(channel, chatID string) *Session {
@@ -347,6 +388,7 @@ func (ss *SessionStore) GetOrCreate(channel, chatID string) *Session {
 		return session
 	}
 	persistence := ss.persistence
+	archiveRestore := ss.archiveRestore
 	ss.mu.RUnlock()
 
 	ss.mu.Lock()
@@ -383,6 +425,36 @@ func (ss *SessionStore) GetOrCreate(channel, chatID string) *Session {
 		}
 	}
 
+	// Sessão arquivada: restaura resumo + fatos de forma transparente
+	// quando o mesmo chat volta a ficar ativo (ver session_archive.go).
+	if archiveRestore != nil {
+		if summary, facts, ok := archiveRestore(key); ok {
+			session = &Session{
+				ID:           key,
+				Channel:      channel,
+				ChatID:       chatID,
+				config:       SessionConfig{},
+				activeSkills: []string{},
+				facts:        facts,
+				history: []ConversationEntry{{
+					UserMessage:       "[session restored from archive]",
+					AssistantResponse: summary,
+					Timestamp:         time.Now(),
+				}},
+				maxHistory:   DefaultMaxHistory,
+				CreatedAt:    time.Now(),
+				lastActiveAt: time.Now(),
+				persistence:  persistence,
+			}
+			ss.sessions[key] = session
+			ss.logger.Info("sessão restaurada do arquivo",
+				"channel", channel,
+				"chat_id", chatID,
+			)
+			return session
+		}
+	}
+
 	// Create new session
 	session = &Session{
 		ID:           key,
@@ -429,28 +501,41 @@ func (ss *SessionStore) Count() int {
 
 // Prune remove sessões inativas há mais tempo que o TTL configurado.
 // Deve ser chamado periodicamente (ex: via goroutine com ticker).
+// Com onExpire configurado, cada sessão removida é arquivada antes
+// (resumo + export para memória) em vez de simplesmente descartada.
 func (ss *SessionStore) Prune() int {
 	ss.mu.Lock()
-	defer ss.mu.Unlock()
 
 	cutoff := time.Now().Add(-ss.sessionTTL)
-	pruned := 0
+	var expired []*Session
 
 	for key, session := range ss.sessions {
 		if session.LastActiveAt().Before(cutoff) {
 			delete(ss.sessions, key)
-			pruned++
+			expired = append(expired, session)
+		}
+	}
+
+	onExpire := ss.onExpire
+	remaining := len(ss.sessions)
+	ss.mu.Unlock()
+
+	// Archival runs outside the store lock: summarization can take minutes
+	// and must not block GetOrCreate for active chats.
+	if onExpire != nil {
+		for _, session := range expired {
+			onExpire(session)
 		}
 	}
 
-	if pruned > 0 {
+	if len(expired) > 0 {
 		ss.logger.Info("sessões inativas removidas",
-			"pruned", pruned,
-			"remaining", len(ss.sessions),
+			"pruned", len(expired),
+			"remaining", remaining,
 		)
 	}
 
-	return pruned
+	return len(expired)
 }
 
 // StartPruner inicia uma goroutine que executa Prune periodicamente.
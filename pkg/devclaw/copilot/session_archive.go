@@ -0,0 +1,211 @@
+// Package copilot – session_archive.go implements idle-session archival.
+// Instead of dropping idle sessions outright, the pruner hands them to the
+// archiver: the conversation is summarized, the full transcript is exported
+// to a memory document, and a summary row is kept in devclaw.db. When the
+// same chat becomes active again the session is transparently restored with
+// the summary and a pointer to the exported transcript.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// SessionArchive is one archived session's summary row.
+type SessionArchive struct {
+	SessionID  string    `json:"session_id"`
+	Channel    string    `json:"channel"`
+	ChatID     string    `json:"chat_id"`
+	Summary    string    `json:"summary"`
+	Facts      []string  `json:"facts,omitempty"`
+	Messages   int       `json:"messages"`
+	MemoryDate string    `json:"memory_date,omitempty"` // daily log holding the full transcript
+	ArchivedAt time.Time `json:"archived_at"`
+	RestoredAt time.Time `json:"restored_at,omitempty"`
+}
+
+// SessionArchiveStore persists archived sessions in devclaw.db.
+type SessionArchiveStore struct {
+	db *sql.DB
+}
+
+// NewSessionArchiveStore creates a store backed by the given database.
+func NewSessionArchiveStore(db *sql.DB) *SessionArchiveStore {
+	return &SessionArchiveStore{db: db}
+}
+
+// Save inserts or replaces an archive row (re-archival refreshes the summary).
+func (s *SessionArchiveStore) Save(arc *SessionArchive) error {
+	facts, err := json.Marshal(arc.Facts)
+	if err != nil {
+		return fmt.Errorf("marshal archived facts: %w", err)
+	}
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO session_archives
+		(session_id, channel, chat_id, summary, facts, messages, memory_date, archived_at, restored_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, '')`,
+		arc.SessionID, arc.Channel, arc.ChatID, arc.Summary, string(facts),
+		arc.Messages, arc.MemoryDate, arc.ArchivedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("save session archive: %w", err)
+	}
+	return nil
+}
+
+// Get returns the archive for a session ID.
+func (s *SessionArchiveStore) Get(sessionID string) (*SessionArchive, error) {
+	row := s.db.QueryRow(`SELECT session_id, channel, chat_id, summary, facts, messages, memory_date, archived_at, restored_at
+		FROM session_archives WHERE session_id = ?`, sessionID)
+
+	var arc SessionArchive
+	var facts, archivedAt, restoredAt string
+	err := row.Scan(&arc.SessionID, &arc.Channel, &arc.ChatID, &arc.Summary,
+		&facts, &arc.Messages, &arc.MemoryDate, &archivedAt, &restoredAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("archive for session %q not found", sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load session archive: %w", err)
+	}
+	if err := json.Unmarshal([]byte(facts), &arc.Facts); err != nil {
+		return nil, fmt.Errorf("unmarshal archived facts: %w", err)
+	}
+	arc.ArchivedAt, _ = time.Parse(time.RFC3339, archivedAt)
+	if restoredAt != "" {
+		arc.RestoredAt, _ = time.Parse(time.RFC3339, restoredAt)
+	}
+	return &arc, nil
+}
+
+// MarkRestored records when the archived session was brought back.
+func (s *SessionArchiveStore) MarkRestored(sessionID string) error {
+	_, err := s.db.Exec(`UPDATE session_archives SET restored_at = ? WHERE session_id = ?`,
+		time.Now().UTC().Format(time.RFC3339), sessionID)
+	if err != nil {
+		return fmt.Errorf("mark archive restored: %w", err)
+	}
+	return nil
+}
+
+// archiveSummaryPrompt instructs the summarization turn at archive time.
+const archiveSummaryPrompt = `You summarize a chat conversation that is being archived due to inactivity.
+Write a compact summary (max 10 lines) covering: who the user is, open topics or tasks,
+decisions made, and anything the assistant promised to do. Write it so a future
+conversation can pick up where this one left off. Output only the summary.`
+
+// SessionArchiver summarizes idle sessions into devclaw.db and memory
+// documents, and restores them when the chat becomes active again.
+type SessionArchiver struct {
+	store     *SessionArchiveStore
+	assistant *Assistant
+	logger    *slog.Logger
+}
+
+// NewSessionArchiver creates a session archiver.
+func NewSessionArchiver(store *SessionArchiveStore, assistant *Assistant, logger *slog.Logger) *SessionArchiver {
+	return &SessionArchiver{
+		store:     store,
+		assistant: assistant,
+		logger:    logger.With("component", "session-archive"),
+	}
+}
+
+// Archive summarizes and archives one expired session. Called by the session
+// pruner for each idle session it removes from memory; best-effort — a failed
+// archive degrades to the old prune behavior.
+func (ar *SessionArchiver) Archive(session *Session) {
+	entries := session.RecentHistory(session.HistoryLen())
+	if len(entries) == 0 {
+		return // Nothing worth keeping.
+	}
+
+	transcript := formatSummaryTranscript(entries)
+	summary := ar.summarize(transcript, len(entries))
+
+	// Export the full transcript to a memory document so nothing is lost
+	// even though the live history is dropped.
+	memoryDate := ""
+	if ar.assistant.memoryStore != nil {
+		now := time.Now()
+		doc := fmt.Sprintf("## Archived session %s (%s)\n\n%s\n\n%s",
+			session.ChatID, session.Channel, summary, transcript)
+		if err := ar.assistant.memoryStore.SaveDailyLog(now, doc); err != nil {
+			ar.logger.Warn("failed to export archived session to memory", "session", session.ID, "error", err)
+		} else {
+			memoryDate = now.Format("2006-01-02")
+		}
+	}
+
+	arc := &SessionArchive{
+		SessionID:  session.ID,
+		Channel:    session.Channel,
+		ChatID:     session.ChatID,
+		Summary:    summary,
+		Facts:      session.GetFacts(),
+		Messages:   len(entries),
+		MemoryDate: memoryDate,
+		ArchivedAt: time.Now(),
+	}
+	if err := ar.store.Save(arc); err != nil {
+		ar.logger.Warn("failed to archive session", "session", session.ID, "error", err)
+		return
+	}
+
+	// The transcript now lives in the archive and the memory document; drop
+	// the persisted copy so a restart restores the summary, not stale history.
+	session.mu.RLock()
+	persistence := session.persistence
+	session.mu.RUnlock()
+	if persistence != nil {
+		if err := persistence.DeleteSession(session.ID); err != nil {
+			ar.logger.Warn("failed to drop persisted session after archive", "session", session.ID, "error", err)
+		}
+	}
+
+	ar.logger.Info("idle session archived",
+		"session", session.ID,
+		"channel", session.Channel,
+		"messages", len(entries),
+	)
+}
+
+// Restore returns the archived summary and facts for a session ID, marking
+// the archive as restored. ok is false when no archive exists.
+func (ar *SessionArchiver) Restore(sessionID string) (summary string, facts []string, ok bool) {
+	arc, err := ar.store.Get(sessionID)
+	if err != nil {
+		return "", nil, false
+	}
+	if err := ar.store.MarkRestored(sessionID); err != nil {
+		ar.logger.Warn("failed to mark archive restored", "session", sessionID, "error", err)
+	}
+
+	summary = arc.Summary
+	if arc.MemoryDate != "" {
+		summary += fmt.Sprintf("\n\n(Full transcript: memory daily log %s, %d messages.)",
+			arc.MemoryDate, arc.Messages)
+	}
+	ar.logger.Info("session restored from archive", "session", sessionID, "messages", arc.Messages)
+	return summary, arc.Facts, true
+}
+
+// summarize produces the archive summary, falling back to a plain note when
+// the LLM is unavailable (e.g. during shutdown or provider outage).
+func (ar *SessionArchiver) summarize(transcript string, messages int) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	summary, err := ar.assistant.completeForSummary(ctx, "", archiveSummaryPrompt, transcript)
+	if err != nil || strings.TrimSpace(summary) == "" {
+		if err != nil {
+			ar.logger.Warn("archive summarization failed, using fallback", "error", err)
+		}
+		return fmt.Sprintf("Conversation archived after inactivity (%d messages, summary unavailable).", messages)
+	}
+	return strings.TrimSpace(summary)
+}
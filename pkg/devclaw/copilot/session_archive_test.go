@@ -0,0 +1,102 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSessionArchiveStoreLifecycle(t *testing.T) {
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewSessionArchiveStore(db)
+	arc := &SessionArchive{
+		SessionID:  "abc123",
+		Channel:    "whatsapp",
+		ChatID:     "555@c.us",
+		Summary:    "User was planning a trip to Lisbon.",
+		Facts:      []string{"prefers morning flights"},
+		Messages:   42,
+		MemoryDate: "2026-08-30",
+		ArchivedAt: time.Now(),
+	}
+	if err := store.Save(arc); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Get("abc123")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Summary != arc.Summary || got.Messages != 42 || len(got.Facts) != 1 {
+		t.Errorf("unexpected archive: %+v", got)
+	}
+	if !got.RestoredAt.IsZero() {
+		t.Error("fresh archive should not be marked restored")
+	}
+
+	if err := store.MarkRestored("abc123"); err != nil {
+		t.Fatalf("MarkRestored: %v", err)
+	}
+	got, err = store.Get("abc123")
+	if err != nil {
+		t.Fatalf("Get after restore: %v", err)
+	}
+	if got.RestoredAt.IsZero() {
+		t.Error("archive should be marked restored")
+	}
+
+	if _, err := store.Get("missing"); err == nil {
+		t.Error("expected error for unknown session")
+	}
+}
+
+func TestSessionStoreArchivalHooks(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ss := NewSessionStore(logger)
+	ss.sessionTTL = time.Hour
+
+	var archived []string
+	ss.SetArchival(
+		func(s *Session) { archived = append(archived, s.ID) },
+		func(sessionID string) (string, []string, bool) {
+			if len(archived) == 0 {
+				return "", nil, false
+			}
+			return "Earlier: user asked about invoices.", []string{"uses ACME accounting"}, true
+		},
+	)
+
+	// An idle session is handed to onExpire before removal.
+	s := ss.GetOrCreate("whatsapp", "123")
+	s.AddMessage("hello", "hi")
+	s.mu.Lock()
+	s.lastActiveAt = time.Now().Add(-2 * time.Hour)
+	s.mu.Unlock()
+
+	if pruned := ss.Prune(); pruned != 1 {
+		t.Fatalf("expected 1 pruned session, got %d", pruned)
+	}
+	if len(archived) != 1 {
+		t.Fatalf("expected onExpire for the pruned session, got %v", archived)
+	}
+
+	// The same chat coming back is restored with the archive summary + facts.
+	restored := ss.GetOrCreate("whatsapp", "123")
+	history := restored.RecentHistory(5)
+	if len(history) != 1 || history[0].UserMessage != "[session restored from archive]" {
+		t.Fatalf("expected a single restore entry, got %+v", history)
+	}
+	if history[0].AssistantResponse != "Earlier: user asked about invoices." {
+		t.Errorf("unexpected restore summary: %q", history[0].AssistantResponse)
+	}
+	if facts := restored.GetFacts(); len(facts) != 1 || facts[0] != "uses ACME accounting" {
+		t.Errorf("unexpected restored facts: %v", facts)
+	}
+}
@@ -22,6 +22,7 @@ type jsonlEntry struct {
 	TS        string                 `json:"ts"`
 	User      string                 `json:"user"`
 	Assistant string                 `json:"assistant"`
+	Sender    string                 `json:"sender,omitempty"`
 	Meta      map[string]interface{} `json:"meta,omitempty"`
 }
 
@@ -136,6 +137,7 @@ func (p *SessionPersistence) SaveEntry(sessionID string, entry ConversationEntry
 		TS:        entry.Timestamp.UTC().Format(time.RFC3339),
 		User:      entry.UserMessage,
 		Assistant: entry.AssistantResponse,
+		Sender:    entry.SenderName,
 		Meta:      map[string]interface{}{},
 	}
 	data, err := json.Marshal(je)
@@ -345,6 +347,7 @@ func (p *SessionPersistence) readJSONL(path string) ([]ConversationEntry, error)
 		entries = append(entries, ConversationEntry{
 			UserMessage:       je.User,
 			AssistantResponse: je.Assistant,
+			SenderName:        je.Sender,
 			Timestamp:         ts,
 		})
 	}
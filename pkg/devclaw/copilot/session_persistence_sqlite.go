@@ -28,14 +28,22 @@ func NewSQLiteSessionPersistence(db *sql.DB, logger *slog.Logger) *SQLiteSession
 }
 
 // SaveEntry appends a conversation entry for the given session.
+// The sender name (group attribution) travels in the meta JSON column, so no
+// schema migration is needed.
 func (p *SQLiteSessionPersistence) SaveEntry(sessionID string, entry ConversationEntry) error {
+	meta := "{}"
+	if entry.SenderName != "" {
+		metaJSON, _ := json.Marshal(map[string]interface{}{"sender": entry.SenderName})
+		meta = string(metaJSON)
+	}
 	_, err := p.db.Exec(`
 		INSERT INTO session_entries (session_id, user_message, assistant_response, created_at, meta)
-		VALUES (?, ?, ?, ?, '{}')`,
+		VALUES (?, ?, ?, ?, ?)`,
 		sessionID,
 		entry.UserMessage,
 		entry.AssistantResponse,
 		entry.Timestamp.UTC().Format(time.RFC3339),
+		meta,
 	)
 	if err != nil {
 		p.logger.Error("failed to save session entry", "session", sessionID, "err", err)
@@ -48,7 +56,7 @@ func (p *SQLiteSessionPersistence) SaveEntry(sessionID string, entry Conversatio
 func (p *SQLiteSessionPersistence) LoadSession(sessionID string) ([]ConversationEntry, []string, error) {
 	// Load entries.
 	rows, err := p.db.Query(`
-		SELECT user_message, assistant_response, created_at
+		SELECT user_message, assistant_response, created_at, meta
 		FROM session_entries
 		WHERE session_id = ?
 		ORDER BY id ASC`, sessionID)
@@ -62,11 +70,20 @@ func (p *SQLiteSessionPersistence) LoadSession(sessionID string) ([]Conversation
 		var (
 			e         ConversationEntry
 			createdAt string
+			metaJSON  string
 		)
-		if err := rows.Scan(&e.UserMessage, &e.AssistantResponse, &createdAt); err != nil {
+		if err := rows.Scan(&e.UserMessage, &e.AssistantResponse, &createdAt, &metaJSON); err != nil {
 			return nil, nil, fmt.Errorf("scan session entry: %w", err)
 		}
 		e.Timestamp, _ = time.Parse(time.RFC3339, createdAt)
+		if metaJSON != "" && metaJSON != "{}" {
+			var meta struct {
+				Sender string `json:"sender"`
+			}
+			if err := json.Unmarshal([]byte(metaJSON), &meta); err == nil {
+				e.SenderName = meta.Sender
+			}
+		}
 		entries = append(entries, e)
 	}
 	if err := rows.Err(); err != nil {
@@ -128,6 +128,24 @@ func TestSessionAddMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("attributes sender in groups", func(t *testing.T) {
+		s.AddMessageFrom("João", "Oi", "Olá!")
+
+		entry := s.history[len(s.history)-1]
+		if entry.SenderName != "João" {
+			t.Errorf("expected sender 'João', got %q", entry.SenderName)
+		}
+	})
+
+	t.Run("plain AddMessage has no sender", func(t *testing.T) {
+		s.AddMessage("DM message", "Reply")
+
+		entry := s.history[len(s.history)-1]
+		if entry.SenderName != "" {
+			t.Errorf("expected empty sender, got %q", entry.SenderName)
+		}
+	})
+
 	t.Run("updates lastActiveAt", func(t *testing.T) {
 		before := s.lastActiveAt
 		time.Sleep(10 * time.Millisecond)
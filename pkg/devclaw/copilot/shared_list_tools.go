@@ -0,0 +1,437 @@
+// Package copilot – shared_list_tools.go implements the shared shopping list
+// and inventory skill. Lists are scoped per chat, so every member of a group
+// edits the same list; items are keyed by name so concurrent adds of the same
+// item merge into one row instead of duplicating.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sharedListDefaultName is used when no list name is given.
+const sharedListDefaultName = "shopping"
+
+// sharedListsSchema is the DDL for the shared list tables. The unique index
+// on (chat_id, list_name, item) is what makes concurrent adds merge safely.
+const sharedListsSchema = `
+CREATE TABLE IF NOT EXISTS _shared_list_items (
+    id         TEXT PRIMARY KEY,
+    chat_id    TEXT NOT NULL,
+    list_name  TEXT NOT NULL,
+    item       TEXT NOT NULL,
+    quantity   TEXT,
+    checked    INTEGER NOT NULL DEFAULT 0,
+    added_by   TEXT,
+    checked_by TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shared_list_item ON _shared_list_items(chat_id, list_name, item);
+`
+
+// InitSharedListsTables creates the shared list tables if they don't exist.
+func (s *SkillDB) InitSharedListsTables() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.Exec(sharedListsSchema); err != nil {
+		return fmt.Errorf("create shared list tables: %w", err)
+	}
+
+	return nil
+}
+
+// SharedListItem is one entry on a chat's shared list.
+type SharedListItem struct {
+	ID        string `json:"id"`
+	Item      string `json:"item"`
+	Quantity  string `json:"quantity,omitempty"`
+	Checked   bool   `json:"checked"`
+	AddedBy   string `json:"added_by,omitempty"`
+	CheckedBy string `json:"checked_by,omitempty"`
+}
+
+// normalizeListKey lowercases and trims item and list names so "Milk" and
+// "milk " merge into the same entry.
+func normalizeListKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AddListItem adds an item to a chat's list, merging with an existing entry
+// of the same name: quantity is updated and a checked-off item becomes
+// unchecked again. Returns true when a new entry was created.
+func (s *SkillDB) AddListItem(chatID, listName, item, quantity, addedBy string) (bool, error) {
+	if chatID == "" {
+		return false, fmt.Errorf("chat ID is required")
+	}
+	item = normalizeListKey(item)
+	if item == "" {
+		return false, fmt.Errorf("item is required")
+	}
+	listName = normalizeListKey(listName)
+	if listName == "" {
+		listName = sharedListDefaultName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	// The unique index makes the upsert atomic; checking existence first
+	// (under the lock) lets us tell the caller "added" from "merged".
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM _shared_list_items WHERE chat_id = ? AND list_name = ? AND item = ?`,
+		chatID, listName, item).Scan(&count); err != nil {
+		return false, fmt.Errorf("check existing item: %w", err)
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO _shared_list_items (id, chat_id, list_name, item, quantity, added_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(chat_id, list_name, item) DO UPDATE SET
+			quantity = CASE WHEN excluded.quantity != '' THEN excluded.quantity ELSE quantity END,
+			checked = 0, checked_by = NULL, updated_at = excluded.updated_at`,
+		generateID(), chatID, listName, item, quantity, addedBy, now, now,
+	)
+	if err != nil {
+		return false, fmt.Errorf("add list item: %w", err)
+	}
+
+	return count == 0, nil
+}
+
+// CheckListItem marks an item as checked (done/bought) or unchecked.
+func (s *SkillDB) CheckListItem(chatID, listName, item string, checked bool, by string) error {
+	item = normalizeListKey(item)
+	listName = normalizeListKey(listName)
+	if listName == "" {
+		listName = sharedListDefaultName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkedInt := 0
+	checkedBy := sql.NullString{}
+	if checked {
+		checkedInt = 1
+		checkedBy = sql.NullString{String: by, Valid: by != ""}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	res, err := s.db.Exec(`
+		UPDATE _shared_list_items
+		SET checked = ?, checked_by = ?, updated_at = ?
+		WHERE chat_id = ? AND list_name = ? AND item = ?`,
+		checkedInt, checkedBy, now, chatID, listName, item,
+	)
+	if err != nil {
+		return fmt.Errorf("check list item: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("item %q not on the %q list", item, listName)
+	}
+
+	return nil
+}
+
+// RemoveListItem deletes an item from a chat's list.
+func (s *SkillDB) RemoveListItem(chatID, listName, item string) error {
+	item = normalizeListKey(item)
+	listName = normalizeListKey(listName)
+	if listName == "" {
+		listName = sharedListDefaultName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.db.Exec(`
+		DELETE FROM _shared_list_items
+		WHERE chat_id = ? AND list_name = ? AND item = ?`,
+		chatID, listName, item,
+	)
+	if err != nil {
+		return fmt.Errorf("remove list item: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("item %q not on the %q list", item, listName)
+	}
+
+	return nil
+}
+
+// GetList returns a chat's list, unchecked items first.
+func (s *SkillDB) GetList(chatID, listName string) ([]SharedListItem, error) {
+	listName = normalizeListKey(listName)
+	if listName == "" {
+		listName = sharedListDefaultName
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT id, item, quantity, checked, added_by, checked_by
+		FROM _shared_list_items
+		WHERE chat_id = ? AND list_name = ?
+		ORDER BY checked, created_at`,
+		chatID, listName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get list: %w", err)
+	}
+	defer rows.Close()
+
+	var items []SharedListItem
+	for rows.Next() {
+		var it SharedListItem
+		var quantity, addedBy, checkedBy sql.NullString
+		var checked int
+		if err := rows.Scan(&it.ID, &it.Item, &quantity, &checked, &addedBy, &checkedBy); err != nil {
+			return nil, fmt.Errorf("scan list item: %w", err)
+		}
+		it.Checked = checked != 0
+		if quantity.Valid {
+			it.Quantity = quantity.String
+		}
+		if addedBy.Valid {
+			it.AddedBy = addedBy.String
+		}
+		if checkedBy.Valid {
+			it.CheckedBy = checkedBy.String
+		}
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return items, nil
+}
+
+// ClearCheckedItems removes all checked-off items from a chat's list and
+// returns how many were cleared.
+func (s *SkillDB) ClearCheckedItems(chatID, listName string) (int, error) {
+	listName = normalizeListKey(listName)
+	if listName == "" {
+		listName = sharedListDefaultName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.db.Exec(`
+		DELETE FROM _shared_list_items
+		WHERE chat_id = ? AND list_name = ? AND checked = 1`,
+		chatID, listName,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("clear checked items: %w", err)
+	}
+	n, _ := res.RowsAffected()
+
+	return int(n), nil
+}
+
+// formatSharedList renders a list as a markdown checklist for export.
+func formatSharedList(listName string, items []SharedListItem) string {
+	title := listName
+	if title != "" {
+		title = strings.ToUpper(title[:1]) + title[1:]
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%s*\n", title))
+	for _, it := range items {
+		box := "☐"
+		if it.Checked {
+			box = "☑"
+		}
+		b.WriteString(fmt.Sprintf("%s %s", box, it.Item))
+		if it.Quantity != "" {
+			b.WriteString(fmt.Sprintf(" (%s)", it.Quantity))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// RegisterSharedListTools registers the shared list tools in the executor.
+// All tools are scoped to the current chat via the delivery target, so group
+// members share one list per chat.
+func RegisterSharedListTools(executor *ToolExecutor, skillDB *SkillDB) {
+	if skillDB == nil {
+		return
+	}
+
+	// chatIDFrom resolves the list scope for the current run.
+	chatIDFrom := func(ctx context.Context) (string, error) {
+		dt := DeliveryTargetFromContext(ctx)
+		if dt.ChatID == "" {
+			return "", fmt.Errorf("no chat context available for list operations")
+		}
+		return dt.ChatID, nil
+	}
+
+	// list_add
+	executor.Register(
+		MakeToolDefinition("list_add", "Add an item to this chat's shared list (shopping list by default). Adding an existing item again un-checks it and updates the quantity instead of duplicating.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"item": map[string]any{
+					"type":        "string",
+					"description": "Item to add (e.g. 'milk')",
+				},
+				"quantity": map[string]any{
+					"type":        "string",
+					"description": "Optional quantity or note (e.g. '2L', '3 packs')",
+				},
+				"list": map[string]any{
+					"type":        "string",
+					"description": "List name (default: 'shopping'; use e.g. 'pantry' for inventory)",
+				},
+			},
+			"required": []string{"item"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			chatID, err := chatIDFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			item, _ := args["item"].(string)
+			quantity, _ := args["quantity"].(string)
+			listName, _ := args["list"].(string)
+			addedBy := CallerJIDFromContext(ctx)
+
+			created, err := skillDB.AddListItem(chatID, listName, item, quantity, addedBy)
+			if err != nil {
+				return nil, err
+			}
+			if created {
+				return fmt.Sprintf("Added %q to the list.", normalizeListKey(item)), nil
+			}
+			return fmt.Sprintf("%q was already on the list — updated it.", normalizeListKey(item)), nil
+		},
+	)
+
+	// list_check
+	executor.Register(
+		MakeToolDefinition("list_check", "Check an item off this chat's shared list (or un-check it).", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"item": map[string]any{
+					"type":        "string",
+					"description": "Item to check off",
+				},
+				"uncheck": map[string]any{
+					"type":        "boolean",
+					"description": "Set to true to un-check instead",
+				},
+				"list": map[string]any{
+					"type":        "string",
+					"description": "List name (default: 'shopping')",
+				},
+			},
+			"required": []string{"item"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			chatID, err := chatIDFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			item, _ := args["item"].(string)
+			uncheck, _ := args["uncheck"].(bool)
+			listName, _ := args["list"].(string)
+
+			if err := skillDB.CheckListItem(chatID, listName, item, !uncheck, CallerJIDFromContext(ctx)); err != nil {
+				return nil, err
+			}
+			if uncheck {
+				return fmt.Sprintf("%q is back on the list.", normalizeListKey(item)), nil
+			}
+			return fmt.Sprintf("Checked %q off the list.", normalizeListKey(item)), nil
+		},
+	)
+
+	// list_remove
+	executor.Register(
+		MakeToolDefinition("list_remove", "Remove an item from this chat's shared list entirely. Use 'clear_checked' to drop everything already checked off.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"item": map[string]any{
+					"type":        "string",
+					"description": "Item to remove",
+				},
+				"clear_checked": map[string]any{
+					"type":        "boolean",
+					"description": "Remove all checked-off items instead of a single one",
+				},
+				"list": map[string]any{
+					"type":        "string",
+					"description": "List name (default: 'shopping')",
+				},
+			},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			chatID, err := chatIDFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			item, _ := args["item"].(string)
+			clearChecked, _ := args["clear_checked"].(bool)
+			listName, _ := args["list"].(string)
+
+			if clearChecked {
+				n, err := skillDB.ClearCheckedItems(chatID, listName)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("Cleared %d checked-off item(s).", n), nil
+			}
+			if item == "" {
+				return nil, fmt.Errorf("item is required (or set clear_checked=true)")
+			}
+			if err := skillDB.RemoveListItem(chatID, listName, item); err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Removed %q from the list.", normalizeListKey(item)), nil
+		},
+	)
+
+	// list_show
+	executor.Register(
+		MakeToolDefinition("list_show", "Show this chat's shared list as a checklist (also used for exporting it).", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"list": map[string]any{
+					"type":        "string",
+					"description": "List name (default: 'shopping')",
+				},
+			},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			chatID, err := chatIDFrom(ctx)
+			if err != nil {
+				return nil, err
+			}
+			listName, _ := args["list"].(string)
+			if listName == "" {
+				listName = sharedListDefaultName
+			}
+
+			items, err := skillDB.GetList(chatID, listName)
+			if err != nil {
+				return nil, err
+			}
+			if len(items) == 0 {
+				return fmt.Sprintf("The %q list is empty.", normalizeListKey(listName)), nil
+			}
+			return formatSharedList(listName, items), nil
+		},
+	)
+}
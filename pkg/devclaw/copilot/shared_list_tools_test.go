@@ -0,0 +1,179 @@
+// Package copilot – shared_list_tools_test.go contains unit tests for the shared lists skill.
+package copilot
+
+import (
+	"testing"
+)
+
+func newTestListDB(t *testing.T) *SkillDB {
+	t.Helper()
+	db, err := OpenSkillDatabase(t.TempDir())
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitSharedListsTables(); err != nil {
+		t.Fatalf("init shared lists tables: %v", err)
+	}
+	return db
+}
+
+func TestAddListItemMerges(t *testing.T) {
+	db := newTestListDB(t)
+
+	if _, err := db.AddListItem("", "", "milk", "", ""); err == nil {
+		t.Error("expected error for empty chat ID")
+	}
+	if _, err := db.AddListItem("group1", "", "", "", ""); err == nil {
+		t.Error("expected error for empty item")
+	}
+
+	created, err := db.AddListItem("group1", "", "Milk", "1L", "alice")
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if !created {
+		t.Error("expected first add to create the item")
+	}
+
+	// Same item from another member merges instead of duplicating and
+	// updates the quantity.
+	created, err = db.AddListItem("group1", "", "milk ", "2L", "bob")
+	if err != nil {
+		t.Fatalf("merge add: %v", err)
+	}
+	if created {
+		t.Error("expected second add to merge")
+	}
+
+	items, err := db.GetList("group1", "")
+	if err != nil {
+		t.Fatalf("get list: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d: %+v", len(items), items)
+	}
+	if items[0].Item != "milk" || items[0].Quantity != "2L" {
+		t.Errorf("unexpected item: %+v", items[0])
+	}
+}
+
+func TestListScopingPerChat(t *testing.T) {
+	db := newTestListDB(t)
+
+	if _, err := db.AddListItem("group1", "", "milk", "", ""); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := db.AddListItem("group2", "", "eggs", "", ""); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	items, err := db.GetList("group1", "")
+	if err != nil {
+		t.Fatalf("get list: %v", err)
+	}
+	if len(items) != 1 || items[0].Item != "milk" {
+		t.Errorf("group1 list leaked across chats: %+v", items)
+	}
+
+	// Named lists are independent within a chat.
+	if _, err := db.AddListItem("group1", "pantry", "rice", "", ""); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	items, _ = db.GetList("group1", "pantry")
+	if len(items) != 1 || items[0].Item != "rice" {
+		t.Errorf("unexpected pantry list: %+v", items)
+	}
+}
+
+func TestCheckAndClearListItems(t *testing.T) {
+	db := newTestListDB(t)
+
+	if _, err := db.AddListItem("group1", "", "milk", "", "alice"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := db.AddListItem("group1", "", "eggs", "", "alice"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	if err := db.CheckListItem("group1", "", "nope", true, "bob"); err == nil {
+		t.Error("expected error for unknown item")
+	}
+	if err := db.CheckListItem("group1", "", "Milk", true, "bob"); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	items, err := db.GetList("group1", "")
+	if err != nil {
+		t.Fatalf("get list: %v", err)
+	}
+	// Unchecked items sort first.
+	if items[0].Item != "eggs" || items[0].Checked {
+		t.Errorf("expected unchecked eggs first, got %+v", items[0])
+	}
+	if items[1].Item != "milk" || !items[1].Checked || items[1].CheckedBy != "bob" {
+		t.Errorf("expected milk checked by bob, got %+v", items[1])
+	}
+
+	// Re-adding a checked item un-checks it.
+	if _, err := db.AddListItem("group1", "", "milk", "", "carol"); err != nil {
+		t.Fatalf("re-add: %v", err)
+	}
+	items, _ = db.GetList("group1", "")
+	for _, it := range items {
+		if it.Checked {
+			t.Errorf("expected nothing checked after re-add, got %+v", it)
+		}
+	}
+
+	// Clear checked items.
+	if err := db.CheckListItem("group1", "", "eggs", true, ""); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	n, err := db.ClearCheckedItems("group1", "")
+	if err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 cleared, got %d", n)
+	}
+	items, _ = db.GetList("group1", "")
+	if len(items) != 1 || items[0].Item != "milk" {
+		t.Errorf("unexpected list after clear: %+v", items)
+	}
+}
+
+func TestRemoveListItemAndFormat(t *testing.T) {
+	db := newTestListDB(t)
+
+	if _, err := db.AddListItem("group1", "", "milk", "2L", ""); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := db.AddListItem("group1", "", "eggs", "", ""); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := db.CheckListItem("group1", "", "eggs", true, ""); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	items, err := db.GetList("group1", "")
+	if err != nil {
+		t.Fatalf("get list: %v", err)
+	}
+	out := formatSharedList("shopping", items)
+	want := "*Shopping*\n☐ milk (2L)\n☑ eggs"
+	if out != want {
+		t.Errorf("formatSharedList = %q, want %q", out, want)
+	}
+
+	if err := db.RemoveListItem("group1", "", "nope"); err == nil {
+		t.Error("expected error for unknown item")
+	}
+	if err := db.RemoveListItem("group1", "", "milk"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	items, _ = db.GetList("group1", "")
+	if len(items) != 1 || items[0].Item != "eggs" {
+		t.Errorf("unexpected list after remove: %+v", items)
+	}
+}
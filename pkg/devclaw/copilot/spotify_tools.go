@@ -0,0 +1,425 @@
+// Package copilot – spotify_tools.go implements media player control: the
+// Spotify Web API (search, play, pause, queue) with OAuth tokens stored in
+// the vault, and local players via MPRIS (playerctl). In team mode each
+// user can link their own Spotify account.
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	spotifyAccountsURL = "https://accounts.spotify.com/api/token"
+	spotifyAPIURL      = "https://api.spotify.com/v1"
+
+	// Vault key names for Spotify credentials. Per-user refresh tokens
+	// (team mode) append a sanitized caller JID: SPOTIFY_REFRESH_TOKEN_<JID>.
+	vaultSpotifyClientID     = "SPOTIFY_CLIENT_ID"
+	vaultSpotifyClientSecret = "SPOTIFY_CLIENT_SECRET"
+	vaultSpotifyRefreshToken = "SPOTIFY_REFRESH_TOKEN"
+)
+
+// SpotifyClient calls the Spotify Web API with vault-backed credentials.
+// Access tokens are refreshed on demand and cached per account.
+type SpotifyClient struct {
+	vault  *Vault
+	client *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]*spotifyToken // vault refresh-token key → cached access token
+}
+
+// spotifyToken is a cached access token.
+type spotifyToken struct {
+	access  string
+	expires time.Time
+}
+
+// NewSpotifyClient creates a Spotify client backed by the vault.
+func NewSpotifyClient(vault *Vault) *SpotifyClient {
+	return &SpotifyClient{
+		vault:  vault,
+		client: &http.Client{Timeout: 15 * time.Second},
+		tokens: make(map[string]*spotifyToken),
+	}
+}
+
+// refreshTokenKey resolves the vault key holding the refresh token for a
+// caller: the per-user key when the caller has linked their own account
+// (team mode), otherwise the shared key.
+func (s *SpotifyClient) refreshTokenKey(callerJID string) string {
+	if callerJID != "" {
+		userKey := vaultSpotifyRefreshToken + "_" + sanitizeVaultKeySuffix(callerJID)
+		if s.vault != nil && s.vault.Has(userKey) {
+			return userKey
+		}
+	}
+	return vaultSpotifyRefreshToken
+}
+
+// sanitizeVaultKeySuffix maps a caller JID onto vault key charset.
+func sanitizeVaultKeySuffix(jid string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(jid) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// accessToken returns a valid access token for the caller, refreshing via
+// the OAuth refresh-token grant when the cached one expired.
+func (s *SpotifyClient) accessToken(ctx context.Context, callerJID string) (string, error) {
+	if s.vault == nil || !s.vault.IsUnlocked() {
+		return "", fmt.Errorf("vault is locked — Spotify credentials unavailable")
+	}
+
+	key := s.refreshTokenKey(callerJID)
+
+	s.mu.Lock()
+	if tok, ok := s.tokens[key]; ok && time.Now().Before(tok.expires) {
+		access := tok.access
+		s.mu.Unlock()
+		return access, nil
+	}
+	s.mu.Unlock()
+
+	clientID, err := s.vault.Get(vaultSpotifyClientID)
+	if err != nil {
+		return "", fmt.Errorf("spotify not configured: store %s, %s and %s in the vault", vaultSpotifyClientID, vaultSpotifyClientSecret, vaultSpotifyRefreshToken)
+	}
+	clientSecret, err := s.vault.Get(vaultSpotifyClientSecret)
+	if err != nil {
+		return "", fmt.Errorf("spotify not configured: %s missing from vault", vaultSpotifyClientSecret)
+	}
+	refreshToken, err := s.vault.Get(key)
+	if err != nil {
+		return "", fmt.Errorf("spotify account not linked: %s missing from vault", key)
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, spotifyAccountsURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("building token request: %w", err)
+	}
+	req.SetBasicAuth(clientID, clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("refreshing spotify token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("spotify token refresh failed (%d): %s", resp.StatusCode, truncate(strings.TrimSpace(string(body)), 200))
+	}
+
+	var parsed struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil || parsed.AccessToken == "" {
+		return "", fmt.Errorf("unexpected spotify token response")
+	}
+
+	s.mu.Lock()
+	s.tokens[key] = &spotifyToken{
+		access: parsed.AccessToken,
+		// Renew a minute early to avoid using a token mid-expiry.
+		expires: time.Now().Add(time.Duration(parsed.ExpiresIn-60) * time.Second),
+	}
+	s.mu.Unlock()
+
+	return parsed.AccessToken, nil
+}
+
+// api performs an authenticated Web API call for the caller.
+func (s *SpotifyClient) api(ctx context.Context, callerJID, method, path string, body string) (string, error) {
+	token, err := s.accessToken(ctx, callerJID)
+	if err != nil {
+		return "", err
+	}
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, spotifyAPIURL+path, reader)
+	if err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("spotify request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	result := strings.TrimSpace(string(respBody))
+	if resp.StatusCode == http.StatusNotFound && strings.Contains(result, "NO_ACTIVE_DEVICE") {
+		return "", fmt.Errorf("no active Spotify device — open Spotify on a device first")
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("spotify API error %d: %s", resp.StatusCode, truncate(result, 300))
+	}
+	return result, nil
+}
+
+// ---------- Tool Registration ----------
+
+// RegisterMediaPlayerTools registers Spotify and local MPRIS media tools.
+func RegisterMediaPlayerTools(executor *ToolExecutor, vault *Vault) {
+	spotify := NewSpotifyClient(vault)
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "spotify_search",
+			Description: "Search Spotify for tracks, artists, albums, or playlists. Returns names and URIs usable with spotify_play and spotify_queue.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{"type": "string", "description": "Search query (e.g. 'bohemian rhapsody', 'artist:Queen')"},
+					"type":  map[string]any{"type": "string", "enum": []string{"track", "artist", "album", "playlist"}, "description": "Result type (default: track)"},
+					"limit": map[string]any{"type": "integer", "description": "Max results (default: 5, max: 20)"},
+				},
+				"required": []string{"query"},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		query, _ := args["query"].(string)
+		if query == "" {
+			return nil, fmt.Errorf("query is required")
+		}
+		searchType, _ := args["type"].(string)
+		if searchType == "" {
+			searchType = "track"
+		}
+		limit := 5
+		if v, ok := args["limit"].(float64); ok && v > 0 {
+			limit = int(v)
+		}
+		if limit > 20 {
+			limit = 20
+		}
+
+		path := fmt.Sprintf("/search?q=%s&type=%s&limit=%d", url.QueryEscape(query), searchType, limit)
+		result, err := spotify.api(ctx, CallerJIDFromContext(ctx), http.MethodGet, path, "")
+		if err != nil {
+			return nil, err
+		}
+		return formatSpotifySearch(result, searchType)
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "spotify_play",
+			Description: "Play music on the user's active Spotify device. Pass a URI from spotify_search, or a free-text query to play the top matching track. Call without arguments to resume playback.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"uri":   map[string]any{"type": "string", "description": "Spotify URI to play (track, album, or playlist)"},
+					"query": map[string]any{"type": "string", "description": "Free-text search; plays the top matching track"},
+				},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		uri, _ := args["uri"].(string)
+		query, _ := args["query"].(string)
+		caller := CallerJIDFromContext(ctx)
+
+		if uri == "" && query != "" {
+			path := fmt.Sprintf("/search?q=%s&type=track&limit=1", url.QueryEscape(query))
+			result, err := spotify.api(ctx, caller, http.MethodGet, path, "")
+			if err != nil {
+				return nil, err
+			}
+			uri = firstSpotifyTrackURI(result)
+			if uri == "" {
+				return nil, fmt.Errorf("no track found for %q", query)
+			}
+		}
+
+		body := ""
+		if uri != "" {
+			if strings.HasPrefix(uri, "spotify:track:") {
+				body = fmt.Sprintf(`{"uris":[%q]}`, uri)
+			} else {
+				// Albums and playlists play as a context.
+				body = fmt.Sprintf(`{"context_uri":%q}`, uri)
+			}
+		}
+		if _, err := spotify.api(ctx, caller, http.MethodPut, "/me/player/play", body); err != nil {
+			return nil, err
+		}
+		if uri == "" {
+			return "Playback resumed.", nil
+		}
+		return fmt.Sprintf("Playing %s.", uri), nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "spotify_pause",
+			Description: "Pause Spotify playback on the user's active device.",
+			Parameters: mustJSON(map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{},
+				"additionalProperties": false,
+			}),
+		},
+	}, func(ctx context.Context, _ map[string]any) (any, error) {
+		if _, err := spotify.api(ctx, CallerJIDFromContext(ctx), http.MethodPut, "/me/player/pause", ""); err != nil {
+			return nil, err
+		}
+		return "Playback paused.", nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "spotify_queue",
+			Description: "Add a track to the Spotify playback queue. Use a URI from spotify_search.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"uri": map[string]any{"type": "string", "description": "Spotify track URI to enqueue"},
+				},
+				"required": []string{"uri"},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		uri, _ := args["uri"].(string)
+		if uri == "" {
+			return nil, fmt.Errorf("uri is required")
+		}
+		path := "/me/player/queue?uri=" + url.QueryEscape(uri)
+		if _, err := spotify.api(ctx, CallerJIDFromContext(ctx), http.MethodPost, path, ""); err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf("Queued %s.", uri), nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "media_control",
+			Description: "Control the local media player on this host via MPRIS (playerctl): play, pause, next, previous, or status. Works with any MPRIS-capable player (Spotify desktop, VLC, browsers).",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"action": map[string]any{"type": "string", "enum": []string{"play", "pause", "play-pause", "next", "previous", "status"}, "description": "Player action"},
+					"player": map[string]any{"type": "string", "description": "Target a specific player (e.g. 'spotify', 'vlc'); default: active player"},
+				},
+				"required": []string{"action"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		action, _ := args["action"].(string)
+		player, _ := args["player"].(string)
+		if action == "" {
+			return nil, fmt.Errorf("action is required")
+		}
+		if _, err := exec.LookPath("playerctl"); err != nil {
+			return nil, fmt.Errorf("playerctl not installed — local media control requires MPRIS/playerctl")
+		}
+
+		cmdArgs := []string{}
+		if player != "" {
+			cmdArgs = append(cmdArgs, "-p", player)
+		}
+		if action == "status" {
+			cmdArgs = append(cmdArgs, "metadata", "--format", "{{status}}: {{artist}} - {{title}}")
+		} else {
+			cmdArgs = append(cmdArgs, action)
+		}
+
+		out, err := exec.Command("playerctl", cmdArgs...).CombinedOutput()
+		result := strings.TrimSpace(string(out))
+		if err != nil {
+			if result != "" {
+				return nil, fmt.Errorf("playerctl: %s", result)
+			}
+			return nil, fmt.Errorf("playerctl %s: %w", action, err)
+		}
+		if result == "" {
+			result = fmt.Sprintf("Player action %q done.", action)
+		}
+		return result, nil
+	})
+}
+
+// formatSpotifySearch renders a search response as a compact list.
+func formatSpotifySearch(raw, searchType string) (string, error) {
+	var parsed map[string]struct {
+		Items []struct {
+			Name    string `json:"name"`
+			URI     string `json:"uri"`
+			Artists []struct {
+				Name string `json:"name"`
+			} `json:"artists"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return "", fmt.Errorf("parsing spotify response: %w", err)
+	}
+
+	section, ok := parsed[searchType+"s"]
+	if !ok || len(section.Items) == 0 {
+		return "No results.", nil
+	}
+
+	var b strings.Builder
+	for _, item := range section.Items {
+		b.WriteString("• " + item.Name)
+		if len(item.Artists) > 0 {
+			names := make([]string, 0, len(item.Artists))
+			for _, a := range item.Artists {
+				names = append(names, a.Name)
+			}
+			b.WriteString(" — " + strings.Join(names, ", "))
+		}
+		b.WriteString(" (" + item.URI + ")\n")
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// firstSpotifyTrackURI extracts the first track URI from a search response.
+func firstSpotifyTrackURI(raw string) string {
+	var parsed struct {
+		Tracks struct {
+			Items []struct {
+				URI string `json:"uri"`
+			} `json:"items"`
+		} `json:"tracks"`
+	}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil || len(parsed.Tracks.Items) == 0 {
+		return ""
+	}
+	return parsed.Tracks.Items[0].URI
+}
@@ -0,0 +1,46 @@
+package copilot
+
+import "testing"
+
+func TestSanitizeVaultKeySuffix(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"5511999999999@s.whatsapp.net", "5511999999999_S_WHATSAPP_NET"},
+		{"user@example.com", "USER_EXAMPLE_COM"},
+		{"simple", "SIMPLE"},
+	}
+	for _, tc := range cases {
+		if got := sanitizeVaultKeySuffix(tc.in); got != tc.want {
+			t.Errorf("sanitizeVaultKeySuffix(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestFormatSpotifySearch(t *testing.T) {
+	raw := `{"tracks":{"items":[{"name":"Bohemian Rhapsody","uri":"spotify:track:abc","artists":[{"name":"Queen"}]}]}}`
+	out, err := formatSpotifySearch(raw, "track")
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+	want := "• Bohemian Rhapsody — Queen (spotify:track:abc)"
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+
+	out, err = formatSpotifySearch(`{"tracks":{"items":[]}}`, "track")
+	if err != nil || out != "No results." {
+		t.Errorf("expected 'No results.', got %q (err: %v)", out, err)
+	}
+}
+
+func TestFirstSpotifyTrackURI(t *testing.T) {
+	raw := `{"tracks":{"items":[{"uri":"spotify:track:first"},{"uri":"spotify:track:second"}]}}`
+	if got := firstSpotifyTrackURI(raw); got != "spotify:track:first" {
+		t.Errorf("got %q", got)
+	}
+	if got := firstSpotifyTrackURI(`{}`); got != "" {
+		t.Errorf("expected empty URI, got %q", got)
+	}
+}
@@ -0,0 +1,419 @@
+// Package copilot – spreadsheet_extract.go converts XLSX and ODS attachments
+// into Markdown tables for document enrichment, so the agent can answer
+// questions about forwarded spreadsheets. Output is capped per sheet (rows
+// and columns) and per workbook (sheets) to keep prompts bounded.
+package copilot
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Extraction limits for spreadsheet attachments.
+const (
+	maxSheetRows = 100 // rows rendered per sheet
+	maxSheetCols = 26  // columns rendered per sheet (A..Z)
+	maxSheets    = 5   // sheets rendered per workbook
+)
+
+// sheetData is one parsed sheet: a name and a sparse grid of cell strings.
+type sheetData struct {
+	Name string
+	Rows [][]string
+}
+
+// extractSpreadsheetText converts an XLSX or ODS file into Markdown tables,
+// one section per sheet. Returns a readable note when parsing fails.
+func extractSpreadsheetText(data []byte, logger *slog.Logger) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		logger.Warn("spreadsheet is not a valid zip container", "error", err)
+		return "[Unable to read spreadsheet: the file is corrupted or not a valid XLSX/ODS document.]"
+	}
+
+	var sheets []sheetData
+	switch {
+	case zipHasEntry(zr, "xl/workbook.xml"):
+		sheets, err = parseXLSX(zr)
+	case zipHasEntry(zr, "content.xml"):
+		sheets, err = parseODS(zr)
+	default:
+		return "[Unable to read spreadsheet: unrecognized container format.]"
+	}
+	if err != nil {
+		logger.Warn("spreadsheet parsing failed", "error", err)
+		return fmt.Sprintf("[Unable to read spreadsheet: %v]", err)
+	}
+
+	return renderSheetsMarkdown(sheets)
+}
+
+// zipLooksLikeSpreadsheet reports whether a zip payload is an XLSX workbook
+// or an ODS document, so mislabeled spreadsheets still get table rendering
+// instead of generic archive extraction.
+func zipLooksLikeSpreadsheet(data []byte) bool {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+	if zipHasEntry(zr, "xl/workbook.xml") {
+		return true
+	}
+	if mt, err := readZipEntry(zr, "mimetype"); err == nil {
+		return strings.TrimSpace(string(mt)) == "application/vnd.oasis.opendocument.spreadsheet"
+	}
+	return false
+}
+
+// zipHasEntry reports whether the archive contains the named file.
+func zipHasEntry(zr *zip.Reader, name string) bool {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readZipEntry returns the contents of one archive entry.
+func readZipEntry(zr *zip.Reader, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", name, err)
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, maxArchiveTotalBytes))
+	}
+	return nil, fmt.Errorf("%s not found in container", name)
+}
+
+// renderSheetsMarkdown renders parsed sheets as Markdown tables with the
+// package caps applied.
+func renderSheetsMarkdown(sheets []sheetData) string {
+	if len(sheets) == 0 {
+		return "[This spreadsheet contains no sheets with data.]"
+	}
+
+	var b strings.Builder
+	shown := sheets
+	if len(shown) > maxSheets {
+		shown = shown[:maxSheets]
+	}
+	for _, sheet := range shown {
+		if len(sheets) > 1 || sheet.Name != "" {
+			fmt.Fprintf(&b, "## Sheet: %s\n\n", sheet.Name)
+		}
+		renderSheetTable(&b, sheet.Rows)
+		b.WriteString("\n")
+	}
+	if len(sheets) > maxSheets {
+		fmt.Fprintf(&b, "[%d additional sheet(s) omitted.]\n", len(sheets)-maxSheets)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// renderSheetTable writes one sheet grid as a Markdown table, treating the
+// first row as the header.
+func renderSheetTable(b *strings.Builder, rows [][]string) {
+	if len(rows) == 0 {
+		b.WriteString("_(empty sheet)_\n")
+		return
+	}
+
+	// Width is the widest row, capped.
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	colsTruncated := width > maxSheetCols
+	if colsTruncated {
+		width = maxSheetCols
+	}
+
+	shown := rows
+	rowsTruncated := len(shown) > maxSheetRows
+	if rowsTruncated {
+		shown = shown[:maxSheetRows]
+	}
+
+	for i, row := range shown {
+		b.WriteString("|")
+		for c := 0; c < width; c++ {
+			val := ""
+			if c < len(row) {
+				val = strings.ReplaceAll(row[c], "|", "\\|")
+				val = strings.ReplaceAll(val, "\n", " ")
+			}
+			b.WriteString(" " + val + " |")
+		}
+		b.WriteString("\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	if rowsTruncated {
+		fmt.Fprintf(b, "\n[%d additional row(s) omitted.]\n", len(rows)-maxSheetRows)
+	}
+	if colsTruncated {
+		fmt.Fprintf(b, "[columns beyond %d omitted.]\n", maxSheetCols)
+	}
+}
+
+// --- XLSX ---
+
+// parseXLSX reads sheet names from the workbook and cell values from each
+// worksheet, resolving shared strings.
+func parseXLSX(zr *zip.Reader) ([]sheetData, error) {
+	shared, err := parseSharedStrings(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	wbData, err := readZipEntry(zr, "xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	var wb struct {
+		Sheets struct {
+			Sheet []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"sheet"`
+		} `xml:"sheets"`
+	}
+	if err := xml.Unmarshal(wbData, &wb); err != nil {
+		return nil, fmt.Errorf("parsing workbook: %w", err)
+	}
+
+	var sheets []sheetData
+	for i, meta := range wb.Sheets.Sheet {
+		if i >= maxSheets {
+			// Keep names for the "omitted" note without parsing the data.
+			sheets = append(sheets, sheetData{Name: meta.Name})
+			continue
+		}
+		// Worksheets are stored by position: xl/worksheets/sheet<N>.xml.
+		rows, err := parseXLSXSheet(zr, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), shared)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %q: %w", meta.Name, err)
+		}
+		sheets = append(sheets, sheetData{Name: meta.Name, Rows: rows})
+	}
+	return sheets, nil
+}
+
+// parseSharedStrings reads xl/sharedStrings.xml (optional).
+func parseSharedStrings(zr *zip.Reader) ([]string, error) {
+	if !zipHasEntry(zr, "xl/sharedStrings.xml") {
+		return nil, nil
+	}
+	data, err := readZipEntry(zr, "xl/sharedStrings.xml")
+	if err != nil {
+		return nil, err
+	}
+	var sst struct {
+		SI []struct {
+			T string `xml:"t"`
+			R []struct {
+				T string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := xml.Unmarshal(data, &sst); err != nil {
+		return nil, fmt.Errorf("parsing shared strings: %w", err)
+	}
+	strs := make([]string, 0, len(sst.SI))
+	for _, si := range sst.SI {
+		text := si.T
+		for _, run := range si.R {
+			text += run.T
+		}
+		strs = append(strs, text)
+	}
+	return strs, nil
+}
+
+// parseXLSXSheet reads one worksheet XML into a row grid.
+func parseXLSXSheet(zr *zip.Reader, name string, shared []string) ([][]string, error) {
+	data, err := readZipEntry(zr, name)
+	if err != nil {
+		return nil, err
+	}
+	var ws struct {
+		SheetData struct {
+			Row []struct {
+				C []struct {
+					R  string `xml:"r,attr"` // cell reference, e.g. "D7"
+					T  string `xml:"t,attr"` // cell type
+					V  string `xml:"v"`
+					IS struct {
+						T string `xml:"t"`
+					} `xml:"is"`
+				} `xml:"c"`
+			} `xml:"row"`
+		} `xml:"sheetData"`
+	}
+	if err := xml.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("parsing worksheet: %w", err)
+	}
+
+	var rows [][]string
+	for _, row := range ws.SheetData.Row {
+		var cells []string
+		for _, c := range row.C {
+			col := columnIndex(c.R)
+			if col >= maxSheetCols {
+				continue
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			switch c.T {
+			case "s": // shared string index
+				if idx, err := strconv.Atoi(c.V); err == nil && idx >= 0 && idx < len(shared) {
+					cells[col] = shared[idx]
+				}
+			case "inlineStr":
+				cells[col] = c.IS.T
+			default: // numeric, boolean, formula result
+				cells[col] = c.V
+			}
+		}
+		rows = append(rows, cells)
+		if len(rows) > maxSheetRows {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// columnIndex converts the letters of a cell reference ("D7" → 3).
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A') + 1
+	}
+	if col == 0 {
+		return 0
+	}
+	return col - 1
+}
+
+// --- ODS ---
+
+// parseODS streams content.xml and collects tables, rows, and cells.
+func parseODS(zr *zip.Reader) ([]sheetData, error) {
+	data, err := readZipEntry(zr, "content.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var sheets []sheetData
+	var current *sheetData
+	var row []string
+	var cellText strings.Builder
+	inCell := false
+	repeat := 1
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing content.xml: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "table":
+				sheets = append(sheets, sheetData{Name: xmlAttr(t, "name")})
+				current = &sheets[len(sheets)-1]
+			case "table-row":
+				row = nil
+			case "table-cell":
+				inCell = true
+				cellText.Reset()
+				repeat = 1
+				if rep := xmlAttr(t, "number-columns-repeated"); rep != "" {
+					if n, err := strconv.Atoi(rep); err == nil && n > 0 {
+						repeat = n
+					}
+				}
+			}
+		case xml.CharData:
+			if inCell {
+				cellText.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "table-cell":
+				inCell = false
+				if repeat > maxSheetCols {
+					repeat = maxSheetCols
+				}
+				for i := 0; i < repeat && len(row) < maxSheetCols; i++ {
+					row = append(row, cellText.String())
+				}
+			case "table-row":
+				if current != nil && len(current.Rows) <= maxSheetRows {
+					// Trailing empty cells from repeated blanks are noise.
+					current.Rows = append(current.Rows, trimTrailingEmpty(row))
+				}
+			}
+		}
+	}
+
+	// Drop sheets that are entirely empty (LibreOffice pads the document).
+	var filled []sheetData
+	for _, s := range sheets {
+		if sheetHasData(s) {
+			filled = append(filled, s)
+		}
+	}
+	return filled, nil
+}
+
+// xmlAttr returns the value of the first attribute with the local name.
+func xmlAttr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// trimTrailingEmpty removes empty cells from the end of a row.
+func trimTrailingEmpty(row []string) []string {
+	for len(row) > 0 && row[len(row)-1] == "" {
+		row = row[:len(row)-1]
+	}
+	return row
+}
+
+// sheetHasData reports whether any cell in the sheet is non-empty.
+func sheetHasData(s sheetData) bool {
+	for _, row := range s.Rows {
+		if len(row) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package copilot
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func buildTestXLSX(t *testing.T) []byte {
+	t.Helper()
+	return buildTestZip(t, map[string][]byte{
+		"xl/workbook.xml": []byte(`<?xml version="1.0"?>
+<workbook><sheets><sheet name="Expenses" sheetId="1"/></sheets></workbook>`),
+		"xl/sharedStrings.xml": []byte(`<?xml version="1.0"?>
+<sst><si><t>Item</t></si><si><t>Amount</t></si><si><t>Coffee</t></si><si><t>Server</t></si></sst>`),
+		"xl/worksheets/sheet1.xml": []byte(`<?xml version="1.0"?>
+<worksheet><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>4.50</v></c></row>
+<row r="3"><c r="A3" t="s"><v>3</v></c><c r="B3"><v>120</v></c></row>
+</sheetData></worksheet>`),
+	})
+}
+
+func buildTestODS(t *testing.T) []byte {
+	t.Helper()
+	return buildTestZip(t, map[string][]byte{
+		"mimetype": []byte("application/vnd.oasis.opendocument.spreadsheet"),
+		"content.xml": []byte(`<?xml version="1.0"?>
+<office:document-content xmlns:office="o" xmlns:table="t" xmlns:text="x">
+<office:body><office:spreadsheet>
+<table:table table:name="Totals">
+<table:table-row>
+<table:table-cell><text:p>Region</text:p></table:table-cell>
+<table:table-cell><text:p>Total</text:p></table:table-cell>
+</table:table-row>
+<table:table-row>
+<table:table-cell><text:p>South</text:p></table:table-cell>
+<table:table-cell office:value-type="float"><text:p>990</text:p></table:table-cell>
+</table:table-row>
+</table:table>
+</office:spreadsheet></office:body></office:document-content>`),
+	})
+}
+
+func TestExtractSpreadsheetTextXLSX(t *testing.T) {
+	text := extractSpreadsheetText(buildTestXLSX(t), slog.Default())
+	if !strings.Contains(text, "## Sheet: Expenses") {
+		t.Errorf("missing sheet heading:\n%s", text)
+	}
+	if !strings.Contains(text, "| Item | Amount |") {
+		t.Errorf("missing header row:\n%s", text)
+	}
+	if !strings.Contains(text, "| Coffee | 4.50 |") || !strings.Contains(text, "| Server | 120 |") {
+		t.Errorf("missing data rows:\n%s", text)
+	}
+}
+
+func TestExtractSpreadsheetTextODS(t *testing.T) {
+	text := extractSpreadsheetText(buildTestODS(t), slog.Default())
+	if !strings.Contains(text, "## Sheet: Totals") {
+		t.Errorf("missing sheet heading:\n%s", text)
+	}
+	if !strings.Contains(text, "| Region | Total |") || !strings.Contains(text, "| South | 990 |") {
+		t.Errorf("missing table content:\n%s", text)
+	}
+}
+
+func TestExtractSpreadsheetTextInvalid(t *testing.T) {
+	text := extractSpreadsheetText([]byte("not a zip"), slog.Default())
+	if !strings.Contains(text, "Unable to read spreadsheet") {
+		t.Errorf("expected failure note, got:\n%s", text)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := map[string]int{"A1": 0, "D7": 3, "Z9": 25, "AA3": 26}
+	for ref, want := range tests {
+		if got := columnIndex(ref); got != want {
+			t.Errorf("columnIndex(%q) = %d, want %d", ref, got, want)
+		}
+	}
+}
+
+func TestRenderSheetTableCaps(t *testing.T) {
+	rows := make([][]string, maxSheetRows+10)
+	for i := range rows {
+		rows[i] = []string{"x"}
+	}
+	var b strings.Builder
+	renderSheetTable(&b, rows)
+	if !strings.Contains(b.String(), "10 additional row(s) omitted") {
+		t.Errorf("missing truncation note:\n%s", b.String())
+	}
+}
+
+func TestExtractDocumentTextRoutesSpreadsheets(t *testing.T) {
+	// Mislabeled XLSX still renders as a table via zip content detection.
+	text := extractDocumentText(buildTestXLSX(t), "application/octet-stream", "report.bin", slog.Default())
+	if !strings.Contains(text, "| Item | Amount |") {
+		t.Errorf("spreadsheet routing by zip contents failed:\n%s", text)
+	}
+}
@@ -0,0 +1,51 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSteerInboxSize(t *testing.T) {
+	a := &Assistant{config: &Config{Queue: QueueConfig{
+		SteerInboxSize:      20,
+		SteerInboxByChannel: map[string]int{"whatsapp": 5},
+	}}}
+
+	if got := a.steerInboxSize("whatsapp"); got != 5 {
+		t.Errorf("per-channel override: got %d, want 5", got)
+	}
+	if got := a.steerInboxSize("discord"); got != 20 {
+		t.Errorf("global size: got %d, want 20", got)
+	}
+
+	a = &Assistant{config: &Config{}}
+	if got := a.steerInboxSize("cli"); got != 10 {
+		t.Errorf("default size: got %d, want 10", got)
+	}
+}
+
+func TestCollapseSteerMessages(t *testing.T) {
+	inbox := make(chan string, 3)
+	inbox <- "first"
+	inbox <- "second"
+	inbox <- "third"
+
+	combined, collapsed := collapseSteerMessages(inbox, "fourth")
+	if collapsed != 4 {
+		t.Errorf("expected 4 collapsed messages, got %d", collapsed)
+	}
+	for _, want := range []string{"first", "second", "third", "fourth", "4, oldest first"} {
+		if !strings.Contains(combined, want) {
+			t.Errorf("combined message missing %q:\n%s", want, combined)
+		}
+	}
+	if len(inbox) != 0 {
+		t.Errorf("inbox should be drained, has %d pending", len(inbox))
+	}
+
+	// Empty inbox: the latest message passes through untouched.
+	combined, collapsed = collapseSteerMessages(inbox, "solo")
+	if combined != "solo" || collapsed != 1 {
+		t.Errorf("expected passthrough, got %q (%d)", combined, collapsed)
+	}
+}
@@ -0,0 +1,132 @@
+// Package copilot – structured_result.go lets tools return typed payloads
+// instead of free-form strings. A StructuredResult declares its shape (table,
+// card) and carries typed data; the executor serializes a compact text form
+// for the LLM while channels can render a rich version (markdown tables,
+// key-value cards) from the typed payload on ToolResult.Structured.
+package copilot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Structured result kinds understood by channel renderers.
+const (
+	// StructuredKindTable is a column/row table (e.g. job or session listings).
+	StructuredKindTable = "table"
+
+	// StructuredKindCard is a labeled key-value card (e.g. usage reports).
+	StructuredKindCard = "card"
+)
+
+// StructuredField is one labeled value in a card-kind result.
+type StructuredField struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// StructuredResult is a typed tool result. Tools return *StructuredResult
+// from their handler; the executor stores it on ToolResult.Structured and
+// feeds CompactText() to the LLM. Channels with rich output (WebUI, Slack,
+// Discord) can render the typed data instead of the compact text.
+type StructuredResult struct {
+	// Kind declares the payload shape: StructuredKindTable or StructuredKindCard.
+	Kind string `json:"kind"`
+
+	// Title is an optional heading shown above the rendered data.
+	Title string `json:"title,omitempty"`
+
+	// Columns and Rows hold table data (kind "table").
+	Columns []string   `json:"columns,omitempty"`
+	Rows    [][]string `json:"rows,omitempty"`
+
+	// Fields holds card data (kind "card"), in display order.
+	Fields []StructuredField `json:"fields,omitempty"`
+}
+
+// NewTableResult creates a table-kind structured result.
+func NewTableResult(title string, columns []string) *StructuredResult {
+	return &StructuredResult{
+		Kind:    StructuredKindTable,
+		Title:   title,
+		Columns: columns,
+	}
+}
+
+// AddRow appends one row to a table-kind result.
+func (s *StructuredResult) AddRow(cells ...string) {
+	s.Rows = append(s.Rows, cells)
+}
+
+// NewCardResult creates a card-kind structured result.
+func NewCardResult(title string) *StructuredResult {
+	return &StructuredResult{
+		Kind:  StructuredKindCard,
+		Title: title,
+	}
+}
+
+// AddField appends one labeled value to a card-kind result.
+func (s *StructuredResult) AddField(label, value string) {
+	s.Fields = append(s.Fields, StructuredField{Label: label, Value: value})
+}
+
+// CompactText serializes the result for the LLM context: minimal markup,
+// one line per row/field, pipe-separated table cells.
+func (s *StructuredResult) CompactText() string {
+	var b strings.Builder
+	if s.Title != "" {
+		b.WriteString(s.Title)
+		if s.Kind == StructuredKindTable {
+			fmt.Fprintf(&b, " (%d rows)", len(s.Rows))
+		}
+		b.WriteString("\n")
+	}
+
+	switch s.Kind {
+	case StructuredKindTable:
+		if len(s.Columns) > 0 {
+			b.WriteString(strings.Join(s.Columns, " | "))
+			b.WriteString("\n")
+		}
+		for _, row := range s.Rows {
+			b.WriteString(strings.Join(row, " | "))
+			b.WriteString("\n")
+		}
+	case StructuredKindCard:
+		for _, f := range s.Fields {
+			fmt.Fprintf(&b, "%s: %s\n", f.Label, f.Value)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// RenderMarkdown renders the rich version for channels that display markdown:
+// a proper markdown table, or a bold-titled key-value card matching the
+// formatting style of the slash commands (WhatsApp-compatible single asterisks).
+func (s *StructuredResult) RenderMarkdown() string {
+	var b strings.Builder
+	if s.Title != "" {
+		fmt.Fprintf(&b, "*%s*\n\n", s.Title)
+	}
+
+	switch s.Kind {
+	case StructuredKindTable:
+		if len(s.Columns) > 0 {
+			b.WriteString("| " + strings.Join(s.Columns, " | ") + " |\n")
+			sep := make([]string, len(s.Columns))
+			for i := range sep {
+				sep[i] = "---"
+			}
+			b.WriteString("| " + strings.Join(sep, " | ") + " |\n")
+		}
+		for _, row := range s.Rows {
+			b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		}
+	case StructuredKindCard:
+		for _, f := range s.Fields {
+			fmt.Fprintf(&b, "%s: %s\n", f.Label, f.Value)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
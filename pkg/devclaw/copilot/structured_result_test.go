@@ -0,0 +1,86 @@
+package copilot
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStructuredResultTable(t *testing.T) {
+	table := NewTableResult("Scheduled jobs", []string{"ID", "Status"})
+	table.AddRow("daily-report", "enabled")
+	table.AddRow("cleanup", "disabled")
+
+	compact := table.CompactText()
+	if !strings.Contains(compact, "Scheduled jobs (2 rows)") {
+		t.Errorf("compact text missing title with row count: %q", compact)
+	}
+	if !strings.Contains(compact, "daily-report | enabled") {
+		t.Errorf("compact text missing pipe-separated row: %q", compact)
+	}
+
+	md := table.RenderMarkdown()
+	if !strings.Contains(md, "*Scheduled jobs*") {
+		t.Errorf("markdown missing bold title: %q", md)
+	}
+	if !strings.Contains(md, "| ID | Status |") || !strings.Contains(md, "| --- | --- |") {
+		t.Errorf("markdown missing table header/separator: %q", md)
+	}
+	if !strings.Contains(md, "| cleanup | disabled |") {
+		t.Errorf("markdown missing row: %q", md)
+	}
+}
+
+func TestStructuredResultCard(t *testing.T) {
+	card := NewCardResult("Usage (global)")
+	card.AddField("Requests", "42")
+	card.AddField("Est. cost", "$0.1234")
+
+	compact := card.CompactText()
+	if !strings.Contains(compact, "Requests: 42") || !strings.Contains(compact, "Est. cost: $0.1234") {
+		t.Errorf("compact text missing fields: %q", compact)
+	}
+
+	md := card.RenderMarkdown()
+	if !strings.HasPrefix(md, "*Usage (global)*") {
+		t.Errorf("markdown missing bold title: %q", md)
+	}
+}
+
+func TestExecuteStructuredResult(t *testing.T) {
+	executor := NewToolExecutor(slog.Default())
+	executor.Register(
+		MakeToolDefinition("typed_probe", "test tool", nil),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			table := NewTableResult("Items", []string{"Name"})
+			table.AddRow("first")
+			return table, nil
+		},
+	)
+
+	results := executor.Execute(context.Background(), []ToolCall{
+		{ID: "1", Function: FunctionCall{Name: "typed_probe", Arguments: "{}"}},
+	})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	r := results[0]
+	if r.Error != nil {
+		t.Fatalf("unexpected error: %v", r.Error)
+	}
+	if r.Structured == nil {
+		t.Fatal("expected Structured payload on result")
+	}
+	if r.Structured.Kind != StructuredKindTable {
+		t.Errorf("unexpected kind: %q", r.Structured.Kind)
+	}
+	// LLM sees the compact form; the user-facing form is the rich render.
+	if !strings.Contains(r.Content, "Name\nfirst") {
+		t.Errorf("content is not the compact form: %q", r.Content)
+	}
+	if !strings.Contains(r.ForUser, "| first |") {
+		t.Errorf("ForUser is not the markdown render: %q", r.ForUser)
+	}
+}
@@ -0,0 +1,142 @@
+// Package copilot – sudo.go implements time-boxed elevated access ("sudo mode").
+// /sudo <duration> temporarily skips the RequireConfirmation prompt for a
+// session. Elevation expires automatically, every tool executed under it is
+// recorded in the audit log, and replies carry a visible reminder while active.
+package copilot
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// sudoDefaultDuration is used when /sudo is called without a duration.
+	sudoDefaultDuration = 30 * time.Minute
+
+	// sudoMaxDuration caps how long a session can stay elevated.
+	sudoMaxDuration = 2 * time.Hour
+)
+
+// sudoGrant is one active elevation: when it expires and who granted it.
+type sudoGrant struct {
+	expiry    time.Time
+	grantedBy string // normalized JID of the granting owner
+}
+
+// SudoManager tracks which sessions are temporarily elevated and until when.
+type SudoManager struct {
+	mu     sync.Mutex
+	grants map[string]sudoGrant // sessionID → grant
+	logger *slog.Logger
+}
+
+// NewSudoManager creates a sudo manager.
+func NewSudoManager(logger *slog.Logger) *SudoManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SudoManager{
+		grants: make(map[string]sudoGrant),
+		logger: logger.With("component", "sudo"),
+	}
+}
+
+// Elevate grants sudo mode to a session for the given duration (clamped to
+// sudoMaxDuration) and returns the expiry time. The grant is tied to the
+// granting owner: in shared group sessions only their tool calls skip the
+// confirmation prompt.
+func (s *SudoManager) Elevate(sessionID, grantedBy string, d time.Duration) time.Time {
+	if d <= 0 {
+		d = sudoDefaultDuration
+	}
+	if d > sudoMaxDuration {
+		d = sudoMaxDuration
+	}
+	expiry := time.Now().Add(d)
+
+	s.mu.Lock()
+	s.grants[sessionID] = sudoGrant{expiry: expiry, grantedBy: normalizeJID(grantedBy)}
+	s.mu.Unlock()
+
+	s.logger.Info("sudo mode granted", "session", sessionID, "by", grantedBy, "duration", d)
+	return expiry
+}
+
+// IsActive returns true if the session is currently elevated.
+// Expired grants are cleaned up lazily.
+func (s *SudoManager) IsActive(sessionID string) bool {
+	_, ok := s.activeGrant(sessionID)
+	return ok
+}
+
+// IsActiveFor returns true if the session is elevated and the caller is the
+// owner who granted it. Other participants in the same session do not inherit
+// the elevation.
+func (s *SudoManager) IsActiveFor(sessionID, callerJID string) bool {
+	grant, ok := s.activeGrant(sessionID)
+	return ok && grant.grantedBy == normalizeJID(callerJID)
+}
+
+// activeGrant returns the unexpired grant for a session, cleaning up lazily.
+func (s *SudoManager) activeGrant(sessionID string) (sudoGrant, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	grant, ok := s.grants[sessionID]
+	if !ok {
+		return sudoGrant{}, false
+	}
+	if time.Now().After(grant.expiry) {
+		delete(s.grants, sessionID)
+		s.logger.Info("sudo mode expired", "session", sessionID)
+		return sudoGrant{}, false
+	}
+	return grant, true
+}
+
+// Remaining returns how long the session stays elevated (0 if not elevated).
+func (s *SudoManager) Remaining(sessionID string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	grant, ok := s.grants[sessionID]
+	if !ok {
+		return 0
+	}
+	left := time.Until(grant.expiry)
+	if left < 0 {
+		delete(s.grants, sessionID)
+		return 0
+	}
+	return left
+}
+
+// Revoke ends sudo mode for a session. Returns true if it was active.
+func (s *SudoManager) Revoke(sessionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.grants[sessionID]; !ok {
+		return false
+	}
+	delete(s.grants, sessionID)
+	s.logger.Info("sudo mode revoked", "session", sessionID)
+	return true
+}
+
+// formatSudoRemaining renders a duration as a compact reminder suffix
+// (e.g. "12m", "1h05m").
+func formatSudoRemaining(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		d = time.Minute
+	}
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	if h > 0 {
+		return fmt.Sprintf("%dh%02dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
@@ -0,0 +1,105 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+func testSudoManager(t *testing.T) *SudoManager {
+	t.Helper()
+	return NewSudoManager(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+}
+
+func TestSudoElevateAndRevoke(t *testing.T) {
+	s := testSudoManager(t)
+
+	if s.IsActive("whatsapp:123") {
+		t.Error("session active before elevation")
+	}
+
+	s.Elevate("whatsapp:123", "owner@s.whatsapp.net", 30*time.Minute)
+	if !s.IsActive("whatsapp:123") {
+		t.Error("session not active after elevation")
+	}
+	// Elevation is per-session.
+	if s.IsActive("whatsapp:456") {
+		t.Error("other session elevated")
+	}
+
+	// The grant only covers the owner who issued it.
+	if !s.IsActiveFor("whatsapp:123", "owner@s.whatsapp.net") {
+		t.Error("granting owner not covered by the grant")
+	}
+	if s.IsActiveFor("whatsapp:123", "other@s.whatsapp.net") {
+		t.Error("other participants must not inherit the elevation")
+	}
+
+	left := s.Remaining("whatsapp:123")
+	if left <= 0 || left > 30*time.Minute {
+		t.Errorf("remaining = %v, want (0, 30m]", left)
+	}
+
+	if !s.Revoke("whatsapp:123") {
+		t.Error("revoke returned false for active session")
+	}
+	if s.IsActive("whatsapp:123") {
+		t.Error("session active after revoke")
+	}
+	if s.Revoke("whatsapp:123") {
+		t.Error("revoke returned true for inactive session")
+	}
+}
+
+func TestSudoExpiry(t *testing.T) {
+	s := testSudoManager(t)
+
+	// Force an already-expired grant.
+	s.Elevate("whatsapp:123", "owner@s.whatsapp.net", 30*time.Minute)
+	s.mu.Lock()
+	grant := s.grants["whatsapp:123"]
+	grant.expiry = time.Now().Add(-time.Second)
+	s.grants["whatsapp:123"] = grant
+	s.mu.Unlock()
+
+	if s.IsActive("whatsapp:123") {
+		t.Error("expired grant still active")
+	}
+	if left := s.Remaining("whatsapp:123"); left != 0 {
+		t.Errorf("remaining after expiry = %v, want 0", left)
+	}
+}
+
+func TestSudoDurationClamping(t *testing.T) {
+	s := testSudoManager(t)
+
+	// Zero duration falls back to the default.
+	expiry := s.Elevate("whatsapp:1", "owner@s.whatsapp.net", 0)
+	if left := time.Until(expiry); left < sudoDefaultDuration-time.Second {
+		t.Errorf("default grant = %v, want ~%v", left, sudoDefaultDuration)
+	}
+
+	// Excessive durations are capped.
+	expiry = s.Elevate("whatsapp:2", "owner@s.whatsapp.net", 24*time.Hour)
+	if left := time.Until(expiry); left > sudoMaxDuration {
+		t.Errorf("capped grant = %v, want <= %v", left, sudoMaxDuration)
+	}
+}
+
+func TestFormatSudoRemaining(t *testing.T) {
+	cases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "1m"},
+		{12 * time.Minute, "12m"},
+		{65 * time.Minute, "1h05m"},
+		{2 * time.Hour, "2h00m"},
+	}
+	for _, tc := range cases {
+		if got := formatSudoRemaining(tc.d); got != tc.want {
+			t.Errorf("formatSudoRemaining(%v) = %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
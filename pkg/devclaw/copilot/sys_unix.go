@@ -1,19 +1,54 @@
 //go:build !windows
 
+// Package copilot – sys_unix.go implements process management on Unix.
+// Commands run in their own process group so the whole tree (including
+// children spawned via nohup or background &) dies on cancellation.
 package copilot
 
 import (
+	"os"
 	"os/exec"
 	"syscall"
 )
 
+// runGrouped runs cmd in its own process group and returns the combined
+// output. On context cancellation the entire group is killed.
+func runGrouped(cmd *exec.Cmd) ([]byte, error) {
+	setSysProcAttr(cmd)
+	cmd.Cancel = func() error {
+		return killProcGroup(cmd)
+	}
+	return cmd.CombinedOutput()
+}
+
+// setSysProcAttr places cmd in a new process group so killProcGroup can
+// terminate it together with all descendants.
 func setSysProcAttr(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
 
+// killProcGroup kills cmd's process group, falling back to the direct
+// process when the group lookup fails.
 func killProcGroup(cmd *exec.Cmd) error {
-	if cmd.Process != nil {
-		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	if cmd == nil || cmd.Process == nil {
+		return nil
 	}
-	return nil
+	if pgid, err := syscall.Getpgid(cmd.Process.Pid); err == nil {
+		return syscall.Kill(-pgid, syscall.SIGKILL)
+	}
+	return cmd.Process.Kill()
+}
+
+// processAlive reports whether the process is still running.
+func processAlive(p *os.Process) bool {
+	if p == nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// systemShellCommand builds the argv for running a command in the native
+// system shell (login bash, inheriting the user's environment).
+func systemShellCommand(command string) (string, []string) {
+	return "bash", []string{"-l", "-c", command}
 }
@@ -1,16 +1,134 @@
 //go:build windows
 
+// Package copilot – sys_windows.go implements process management on Windows.
+// Commands run inside a Job Object configured to kill all member processes
+// when the job is terminated, mirroring the Unix process-group semantics.
 package copilot
 
 import (
+	"bytes"
+	"os"
 	"os/exec"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
 )
 
-func setSysProcAttr(cmd *exec.Cmd) {}
+// runGrouped runs cmd inside a Job Object and returns the combined output.
+// On context cancellation the job is terminated, taking the full process
+// tree with it.
+func runGrouped(cmd *exec.Cmd) ([]byte, error) {
+	setSysProcAttr(cmd)
+
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+
+	job, jobErr := newKillOnCloseJob()
+	if jobErr == nil {
+		defer windows.CloseHandle(job)
+		cmd.Cancel = func() error {
+			return windows.TerminateJobObject(job, 1)
+		}
+	} else {
+		// Job Objects unavailable — fall back to a process-tree kill.
+		cmd.Cancel = func() error {
+			return killProcGroup(cmd)
+		}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	// Assign the process to the job right after start so descendants inherit
+	// membership and die with it. Best-effort: if assignment fails, the
+	// direct process is still killed on cancellation.
+	if jobErr == nil && cmd.Process != nil {
+		if h, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(cmd.Process.Pid)); err == nil {
+			_ = windows.AssignProcessToJobObject(job, h)
+			windows.CloseHandle(h)
+		}
+	}
+
+	err := cmd.Wait()
+	return buf.Bytes(), err
+}
+
+// newKillOnCloseJob creates a Job Object that terminates all member
+// processes when the job handle is closed or terminated.
+func newKillOnCloseJob() (windows.Handle, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(
+		job,
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+	); err != nil {
+		windows.CloseHandle(job)
+		return 0, err
+	}
 
+	return job, nil
+}
+
+// setSysProcAttr starts cmd in a new process group. Full-tree termination
+// additionally relies on the Job Object set up by runGrouped, or on the
+// taskkill tree walk in killProcGroup.
+func setSysProcAttr(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killProcGroup terminates cmd and its descendants via taskkill, which
+// walks the process tree. Used for long-running processes not started
+// through runGrouped (e.g. the browser).
 func killProcGroup(cmd *exec.Cmd) error {
-	if cmd.Process != nil {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	if err := kill.Run(); err != nil {
 		return cmd.Process.Kill()
 	}
 	return nil
 }
+
+// stillActive is the exit code GetExitCodeProcess reports for a process
+// that has not terminated (STILL_ACTIVE).
+const stillActive = 259
+
+// processAlive reports whether the process is still running. Signal(0) is
+// not supported on Windows, so the exit code is queried instead.
+func processAlive(p *os.Process) bool {
+	if p == nil {
+		return false
+	}
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(p.Pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// systemShellCommand builds the argv for running a command in the native
+// system shell (PowerShell on Windows).
+func systemShellCommand(command string) (string, []string) {
+	return "powershell.exe", []string{"-NoProfile", "-NonInteractive", "-Command", command}
+}
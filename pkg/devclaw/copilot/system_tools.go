@@ -504,13 +504,25 @@ func registerBashTool(executor *ToolExecutor) {
 			// Append pwd capture to track cd.
 			wrappedCmd += " ; __exit=$?; echo \"__DEVCLAW_CWD=$(pwd)\"; exit $__exit"
 
-			cmd := exec.CommandContext(cmdCtx, "bash", "-l", "-c", wrappedCmd)
-			// Create a new process group so we can kill all child processes
-			// (nohup, background &, etc.) when the timeout fires.
-			setSysProcAttr(cmd)
-			cmd.Cancel = func() error {
-				return killProcGroup(cmd)
+			cmdName := "bash"
+			cmdArgs := []string{"-l", "-c", wrappedCmd}
+
+			// Optional isolation wrapper from the active tool profile
+			// (bubblewrap/firejail/nsjail). The jail sees the same paths,
+			// so the persistent-cwd tracking above keeps working.
+			if profile := ToolProfileFromContext(ctx); profile != nil && profile.BashIsolation != nil && profile.BashIsolation.Wrapper != "" {
+				iso := profile.BashIsolation
+				if _, lookErr := exec.LookPath(isolationBinary(iso.Wrapper)); lookErr != nil {
+					return nil, fmt.Errorf("isolation wrapper %q required by profile %s is not installed", iso.Wrapper, profile.Name)
+				}
+				argv, isoErr := isolationArgv(iso, append([]string{cmdName}, cmdArgs...))
+				if isoErr != nil {
+					return nil, fmt.Errorf("building isolation command: %w", isoErr)
+				}
+				cmdName, cmdArgs = argv[0], argv[1:]
 			}
+
+			cmd := exec.CommandContext(cmdCtx, cmdName, cmdArgs...)
 			cmd.Env = os.Environ() // Inherit full user environment.
 
 			// Add any extra env vars set via set_env.
@@ -518,7 +530,9 @@ func registerBashTool(executor *ToolExecutor) {
 				cmd.Env = append(cmd.Env, k+"="+v)
 			}
 
-			out, err := cmd.CombinedOutput()
+			// Run in its own process group (Job Object on Windows) so all
+			// child processes (nohup, background &, etc.) die on timeout.
+			out, err := runGrouped(cmd)
 			output := string(out)
 
 			// Extract and update persistent cwd.
@@ -558,6 +572,80 @@ func registerBashTool(executor *ToolExecutor) {
 		},
 	)
 
+	// shell — native system shell, for hosts where bash is unavailable.
+	executor.Register(
+		MakeToolDefinition("shell", "Execute a command in the native system shell (PowerShell on Windows, bash elsewhere). Use when the host has no bash, e.g. Windows machines.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"command": map[string]any{
+					"type":        "string",
+					"description": "Command to execute in the system shell",
+				},
+				"working_dir": map[string]any{
+					"type":        "string",
+					"description": "Working directory for this command",
+				},
+				"timeout_seconds": map[string]any{
+					"type":        "integer",
+					"description": "Timeout in seconds (default: 120, max: 600)",
+				},
+			},
+			"required": []string{"command"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			command, _ := args["command"].(string)
+			if command == "" {
+				return nil, fmt.Errorf("command is required")
+			}
+
+			timeout := 120 * time.Second
+			if t, ok := args["timeout_seconds"].(float64); ok && t > 0 {
+				if t > 600 {
+					t = 600
+				}
+				timeout = time.Duration(t) * time.Second
+			}
+
+			cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			cmdName, cmdArgs := systemShellCommand(command)
+			cmd := exec.CommandContext(cmdCtx, cmdName, cmdArgs...)
+			if wd, ok := args["working_dir"].(string); ok && wd != "" {
+				cmd.Dir = resolvePath(wd)
+			}
+			cmd.Env = os.Environ()
+
+			// Add any extra env vars set via set_env.
+			for k, v := range shellState.env {
+				cmd.Env = append(cmd.Env, k+"="+v)
+			}
+
+			out, err := runGrouped(cmd)
+			output := strings.TrimRight(string(out), "\n ")
+
+			// Sanitize sensitive information from output.
+			output = sanitizeOutput(output)
+
+			if len(output) > 50000 {
+				output = output[:50000] + "\n... [truncated, output too long]"
+			}
+
+			if err != nil {
+				if cmdCtx.Err() != nil {
+					return fmt.Sprintf("Command timed out after %v.\n\nPartial output:\n%s", timeout, output), nil
+				}
+				return fmt.Sprintf("Exit code: non-zero\n%s", output), nil
+			}
+
+			if output == "" {
+				output = "(no output)"
+			}
+
+			return output, nil
+		},
+	)
+
 	// ssh — execute commands on remote machines via SSH.
 	executor.Register(
 		MakeToolDefinition("ssh", "Execute a command on a remote machine via SSH. Uses the user's SSH keys and config (~/.ssh/config). Supports any host configured in SSH config or direct user@host.", map[string]any{
@@ -618,13 +706,9 @@ func registerBashTool(executor *ToolExecutor) {
 			sshArgs = append(sshArgs, host, command)
 
 			cmd := exec.CommandContext(cmdCtx, "ssh", sshArgs...)
-			setSysProcAttr(cmd)
-			cmd.Cancel = func() error {
-				return killProcGroup(cmd)
-			}
 			cmd.Env = os.Environ() // Inherit SSH agent, keys, etc.
 
-			out, err := cmd.CombinedOutput()
+			out, err := runGrouped(cmd)
 			output := strings.TrimRight(string(out), "\n ")
 
 			// Sanitize sensitive information from output.
@@ -691,13 +775,9 @@ func registerBashTool(executor *ToolExecutor) {
 			scpArgs = append(scpArgs, source, dest)
 
 			cmd := exec.CommandContext(cmdCtx, "scp", scpArgs...)
-			setSysProcAttr(cmd)
-			cmd.Cancel = func() error {
-				return killProcGroup(cmd)
-			}
 			cmd.Env = os.Environ()
 
-			out, err := cmd.CombinedOutput()
+			out, err := runGrouped(cmd)
 			output := strings.TrimRight(string(out), "\n ")
 
 			// Sanitize sensitive information from output.
@@ -769,13 +849,13 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 			},
 			"required": []string{"path"},
 		}),
-		func(_ context.Context, args map[string]any) (any, error) {
+		func(ctx context.Context, args map[string]any) (any, error) {
 			filePath, _ := args["path"].(string)
 			if filePath == "" {
 				return nil, fmt.Errorf("path is required")
 			}
 
-			filePath = resolvePath(filePath)
+			filePath = executor.ResolveToolPath(ctx, filePath)
 
 			content, err := os.ReadFile(filePath)
 			if err != nil {
@@ -840,7 +920,7 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 			},
 			"required": []string{"path", "content"},
 		}),
-		func(_ context.Context, args map[string]any) (any, error) {
+		func(ctx context.Context, args map[string]any) (any, error) {
 			filePath, _ := args["path"].(string)
 			content, _ := args["content"].(string)
 			appendMode, _ := args["append"].(bool)
@@ -849,7 +929,7 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 				return nil, fmt.Errorf("path is required")
 			}
 
-			filePath = resolvePath(filePath)
+			filePath = executor.ResolveToolPath(ctx, filePath)
 
 			// Parse file mode.
 			fileMode := os.FileMode(0o644)
@@ -909,7 +989,7 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 			},
 			"required": []string{"path", "old_text", "new_text"},
 		}),
-		func(_ context.Context, args map[string]any) (any, error) {
+		func(ctx context.Context, args map[string]any) (any, error) {
 			filePath, _ := args["path"].(string)
 			oldText, _ := args["old_text"].(string)
 			newText, _ := args["new_text"].(string)
@@ -919,7 +999,7 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 				return nil, fmt.Errorf("path and old_text are required")
 			}
 
-			filePath = resolvePath(filePath)
+			filePath = executor.ResolveToolPath(ctx, filePath)
 
 			content, err := os.ReadFile(filePath)
 			if err != nil {
@@ -981,7 +1061,7 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 				},
 			},
 		}),
-		func(_ context.Context, args map[string]any) (any, error) {
+		func(ctx context.Context, args map[string]any) (any, error) {
 			dirPath, _ := args["path"].(string)
 			if dirPath == "" {
 				dirPath = "."
@@ -989,7 +1069,7 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 			recursive, _ := args["recursive"].(bool)
 			pattern, _ := args["pattern"].(string)
 
-			dirPath = resolvePath(dirPath)
+			dirPath = executor.ResolveToolPath(ctx, dirPath)
 
 			if !recursive {
 				entries, err := os.ReadDir(dirPath)
@@ -1104,7 +1184,7 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 			if searchDir == "" {
 				searchDir = "."
 			}
-			searchDir = resolvePath(searchDir)
+			searchDir = executor.ResolveToolPath(ctx, searchDir)
 
 			filePattern, _ := args["file_pattern"].(string)
 			caseInsensitive, _ := args["case_insensitive"].(bool)
@@ -1177,7 +1257,7 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 			},
 			"required": []string{"pattern"},
 		}),
-		func(_ context.Context, args map[string]any) (any, error) {
+		func(ctx context.Context, args map[string]any) (any, error) {
 			pattern, _ := args["pattern"].(string)
 			if pattern == "" {
 				return nil, fmt.Errorf("pattern is required")
@@ -1187,10 +1267,14 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 			if baseDir == "" {
 				baseDir = "."
 			}
-			baseDir = resolvePath(baseDir)
+			baseDir = executor.ResolveToolPath(ctx, baseDir)
 
-			// If pattern is relative, combine with base dir.
-			if !filepath.IsAbs(pattern) {
+			// If pattern is relative, combine with the (already jailed) base
+			// dir. Absolute patterns must go through the jail themselves, or
+			// a sandboxed caller could glob arbitrary host directories.
+			if filepath.IsAbs(pattern) {
+				pattern = executor.ResolveToolPath(ctx, pattern)
+			} else {
 				pattern = filepath.Join(baseDir, pattern)
 			}
 
@@ -1215,9 +1299,13 @@ func registerFileTools(executor *ToolExecutor, _ string) {
 
 // resolvePath resolves a file path, expanding ~ and making relative paths absolute.
 func resolvePath(p string) string {
-	if strings.HasPrefix(p, "~/") {
-		home, err := os.UserHomeDir()
-		if err == nil {
+	if p == "~" {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = home
+		}
+	} else if strings.HasPrefix(p, "~/") || strings.HasPrefix(p, `~\`) {
+		// Expand the home prefix for both Unix and Windows separators.
+		if home, err := os.UserHomeDir(); err == nil {
 			p = filepath.Join(home, p[2:])
 		}
 	}
@@ -1392,24 +1480,20 @@ func registerCronTools(executor *ToolExecutor, sched *scheduler.Scheduler, skill
 				return "No scheduled jobs.", nil
 			}
 
-			var sb strings.Builder
-			sb.WriteString(fmt.Sprintf("Scheduled jobs (%d):\n\n", len(jobs)))
+			table := NewTableResult("Scheduled jobs", []string{"ID", "Status", "Schedule", "Type", "Command", "Runs", "Last Run", "Last Error"})
 			for _, j := range jobs {
 				status := "enabled"
 				if !j.Enabled {
 					status = "disabled"
 				}
-				sb.WriteString(fmt.Sprintf("- **%s** [%s] schedule=%s type=%s\n  Command: %s\n  Runs: %d",
-					j.ID, status, j.Schedule, j.Type, j.Command, j.RunCount))
+				lastRun := ""
 				if j.LastRunAt != nil {
-					sb.WriteString(fmt.Sprintf("  Last run: %s", j.LastRunAt.Format("2006-01-02 15:04")))
-				}
-				if j.LastError != "" {
-					sb.WriteString(fmt.Sprintf("  Last error: %s", j.LastError))
+					lastRun = j.LastRunAt.Format("2006-01-02 15:04")
 				}
-				sb.WriteString("\n")
+				table.AddRow(j.ID, status, j.Schedule, j.Type, j.Command,
+					fmt.Sprintf("%d", j.RunCount), lastRun, j.LastError)
 			}
-			return sb.String(), nil
+			return table, nil
 		},
 	)
 
@@ -1653,23 +1737,21 @@ func RegisterSessionTools(executor *ToolExecutor, wm *WorkspaceManager) {
 				return "No active sessions.", nil
 			}
 
-			var b strings.Builder
-			count := 0
+			table := NewTableResult("Active sessions", []string{"Channel", "Chat", "Session ID", "Workspace", "Msgs", "Last Active"})
 			for _, info := range allSessions {
 				if channelFilter != "" && info.Channel != channelFilter {
 					continue
 				}
 				ago := time.Since(info.LastActiveAt).Round(time.Second)
-				b.WriteString(fmt.Sprintf("- [%s] %s (id: %s, ws: %s) — %d msgs — last active: %s ago\n",
-					info.Channel, info.ChatID, info.ID, info.WorkspaceID, info.MessageCount, ago))
-				count++
+				table.AddRow(info.Channel, info.ChatID, info.ID, info.WorkspaceID,
+					fmt.Sprintf("%d", info.MessageCount), fmt.Sprintf("%s ago", ago))
 			}
 
-			if count == 0 {
+			if len(table.Rows) == 0 {
 				return fmt.Sprintf("No sessions found for channel '%s'.", channelFilter), nil
 			}
 
-			return fmt.Sprintf("Active sessions (%d):\n%s", count, b.String()), nil
+			return table, nil
 		},
 	)
 
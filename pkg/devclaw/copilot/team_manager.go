@@ -741,7 +741,16 @@ func (tm *TeamManager) loadAgentsFromDB() {
 
 // GetTeamMemory creates a TeamMemory instance for a team.
 func (tm *TeamManager) GetTeamMemory(teamID string) *TeamMemory {
-	return NewTeamMemory(teamID, tm.db, tm.logger)
+	mem := NewTeamMemory(teamID, tm.db, tm.logger)
+	// Role lookup for fact ACLs; notification push stays opt-in via SetTeamManager.
+	mem.SetRoleResolver(func(agentID string) string {
+		agent, err := tm.GetAgent(agentID)
+		if err != nil || agent == nil {
+			return ""
+		}
+		return agent.Role
+	})
+	return mem
 }
 
 // FindAgentByName finds an agent by name (case-insensitive).
@@ -97,6 +97,8 @@ func testTeamManagerDB(t *testing.T) *sql.DB {
 		key        TEXT NOT NULL,
 		value      TEXT NOT NULL,
 		author     TEXT NOT NULL,
+		visibility TEXT DEFAULT 'team',
+		roles      TEXT DEFAULT '[]',
 		created_at TEXT NOT NULL,
 		updated_at TEXT NOT NULL,
 		UNIQUE(team_id, key)
@@ -22,6 +22,9 @@ type TeamMemory struct {
 	logger      *slog.Logger
 	mu          sync.RWMutex
 	teamManager *TeamManager // Optional: for notification push
+
+	// roleResolver maps an agent ID to its role for fact ACL checks.
+	roleResolver func(agentID string) string
 }
 
 // NewTeamMemory creates a new team memory instance.
@@ -41,6 +44,11 @@ func (tm *TeamMemory) SetTeamManager(mgr *TeamManager) {
 	tm.teamManager = mgr
 }
 
+// SetRoleResolver sets the agent role lookup used for fact ACL checks.
+func (tm *TeamMemory) SetRoleResolver(fn func(agentID string) string) {
+	tm.roleResolver = fn
+}
+
 // ─── Tasks ───
 
 // CreateTask creates a new task in the team.
@@ -441,9 +449,33 @@ func (tm *TeamMemory) GetPendingMessages(agentID string, markDelivered bool) ([]
 
 // ─── Facts (Shared Memory) ───
 
-// SaveFact saves a fact to shared team memory.
+// SaveFact saves a team-shared fact (the default visibility).
 func (tm *TeamMemory) SaveFact(key, value, author string) error {
+	return tm.SaveFactACL(key, value, author, FactVisibilityTeam, nil)
+}
+
+// SaveFactACL saves a fact with an explicit access control:
+// team (everyone), role (listed roles + author) or private (author only).
+func (tm *TeamMemory) SaveFactACL(key, value, author string, visibility FactVisibility, roles []string) error {
+	switch visibility {
+	case "", FactVisibilityTeam:
+		visibility = FactVisibilityTeam
+		roles = nil
+	case FactVisibilityRole:
+		if len(roles) == 0 {
+			return fmt.Errorf("role-restricted fact requires at least one role")
+		}
+	case FactVisibilityPrivate:
+		roles = nil
+	default:
+		return fmt.Errorf("invalid visibility %q (use team, role or private)", visibility)
+	}
+
 	now := time.Now().Format(time.RFC3339)
+	if roles == nil {
+		roles = []string{}
+	}
+	rolesJSON, _ := json.Marshal(roles)
 
 	// Check if fact exists
 	var existingID string
@@ -456,15 +488,15 @@ func (tm *TeamMemory) SaveFact(key, value, author string) error {
 		// Insert new fact
 		factID := uuid.New().String()[:8]
 		_, err = tm.db.Exec(`
-			INSERT INTO team_facts (id, team_id, key, value, author, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?)`,
-			factID, tm.teamID, key, value, author, now, now,
+			INSERT INTO team_facts (id, team_id, key, value, author, visibility, roles, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			factID, tm.teamID, key, value, author, string(visibility), string(rolesJSON), now, now,
 		)
 	} else if err == nil {
 		// Update existing fact
 		_, err = tm.db.Exec(`
-			UPDATE team_facts SET value = ?, author = ?, updated_at = ? WHERE id = ?`,
-			value, author, now, existingID,
+			UPDATE team_facts SET value = ?, author = ?, visibility = ?, roles = ?, updated_at = ? WHERE id = ?`,
+			value, author, string(visibility), string(rolesJSON), now, existingID,
 		)
 	}
 
@@ -473,64 +505,133 @@ func (tm *TeamMemory) SaveFact(key, value, author string) error {
 	}
 
 	tm.logActivity(ActivityFactCreated, author, fmt.Sprintf("Fact saved: %s", key), "")
-	tm.logger.Info("fact saved", "key", key, "author", author)
+	tm.logger.Info("fact saved", "key", key, "author", author, "visibility", visibility)
 	return nil
 }
 
-// GetFacts retrieves all facts for the team.
+// GetFacts retrieves all facts for the team, without ACL filtering. Reserved
+// for internal/owner paths — agent-facing reads go through GetFactsFor.
 func (tm *TeamMemory) GetFacts() ([]*TeamFact, error) {
-	rows, err := tm.db.Query(`
-		SELECT id, team_id, key, value, author, created_at, updated_at
+	return tm.queryFacts(`
+		SELECT id, team_id, key, value, author, visibility, roles, created_at, updated_at
 		FROM team_facts WHERE team_id = ?
 		ORDER BY key ASC`,
 		tm.teamID,
 	)
+}
+
+// SearchFacts searches facts by key or value, without ACL filtering.
+func (tm *TeamMemory) SearchFacts(query string) ([]*TeamFact, error) {
+	return tm.queryFacts(`
+		SELECT id, team_id, key, value, author, visibility, roles, created_at, updated_at
+		FROM team_facts WHERE team_id = ? AND (key LIKE ? OR value LIKE ?)
+		ORDER BY updated_at DESC LIMIT 20`,
+		tm.teamID, "%"+query+"%", "%"+query+"%",
+	)
+}
+
+// GetFactsFor retrieves the facts the given viewer is allowed to read.
+// Reads of restricted (role/private) entries are audited in the activity feed.
+func (tm *TeamMemory) GetFactsFor(viewerID string) ([]*TeamFact, error) {
+	facts, err := tm.GetFacts()
 	if err != nil {
-		return nil, fmt.Errorf("get facts: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
+	return tm.filterFacts(facts, viewerID), nil
+}
 
-	var facts []*TeamFact
-	for rows.Next() {
-		var fact TeamFact
-		var createdAt, updatedAt string
-		if err := rows.Scan(
-			&fact.ID, &fact.TeamID, &fact.Key, &fact.Value,
-			&fact.Author, &createdAt, &updatedAt,
-		); err != nil {
+// SearchFactsFor searches facts by key or value, restricted to what the
+// viewer is allowed to read.
+func (tm *TeamMemory) SearchFactsFor(query, viewerID string) ([]*TeamFact, error) {
+	facts, err := tm.SearchFacts(query)
+	if err != nil {
+		return nil, err
+	}
+	return tm.filterFacts(facts, viewerID), nil
+}
+
+// filterFacts drops entries the viewer may not read and audits access to the
+// restricted ones that pass.
+func (tm *TeamMemory) filterFacts(facts []*TeamFact, viewerID string) []*TeamFact {
+	role := tm.agentRole(viewerID)
+	var visible []*TeamFact
+	for _, f := range facts {
+		if !canReadFact(f, viewerID, role) {
 			continue
 		}
-		fact.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-		fact.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
-		facts = append(facts, &fact)
+		if f.Visibility == FactVisibilityRole || f.Visibility == FactVisibilityPrivate {
+			tm.logActivity(ActivityFactAccessed, viewerID,
+				fmt.Sprintf("Restricted fact '%s' (%s) read", f.Key, f.Visibility), f.ID)
+		}
+		visible = append(visible, f)
 	}
+	return visible
+}
 
-	return facts, nil
+// canReadFact applies the fact ACL: team-shared is open to everyone, private
+// only to the author, role-restricted to the listed roles plus the author.
+func canReadFact(f *TeamFact, viewerID, viewerRole string) bool {
+	switch f.Visibility {
+	case FactVisibilityPrivate:
+		return f.Author == viewerID
+	case FactVisibilityRole:
+		if f.Author == viewerID {
+			return true
+		}
+		for _, r := range f.Roles {
+			if strings.EqualFold(r, viewerRole) {
+				return true
+			}
+		}
+		return false
+	default: // team (and legacy rows without a visibility)
+		return true
+	}
 }
 
-// SearchFacts searches facts by key or value.
-func (tm *TeamMemory) SearchFacts(query string) ([]*TeamFact, error) {
-	rows, err := tm.db.Query(`
-		SELECT id, team_id, key, value, author, created_at, updated_at
-		FROM team_facts WHERE team_id = ? AND (key LIKE ? OR value LIKE ?)
-		ORDER BY updated_at DESC LIMIT 20`,
-		tm.teamID, "%"+query+"%", "%"+query+"%",
-	)
+// agentRole resolves a team member's role via the role resolver or the team
+// manager; empty when the viewer is not a known agent (e.g. a human owner).
+func (tm *TeamMemory) agentRole(viewerID string) string {
+	if viewerID == "" {
+		return ""
+	}
+	if tm.roleResolver != nil {
+		return tm.roleResolver(viewerID)
+	}
+	if tm.teamManager == nil {
+		return ""
+	}
+	agent, err := tm.teamManager.GetAgent(viewerID)
+	if err != nil || agent == nil {
+		return ""
+	}
+	return agent.Role
+}
+
+// queryFacts runs a fact query and scans the rows.
+func (tm *TeamMemory) queryFacts(query string, args ...interface{}) ([]*TeamFact, error) {
+	rows, err := tm.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("search facts: %w", err)
+		return nil, fmt.Errorf("query facts: %w", err)
 	}
 	defer rows.Close()
 
 	var facts []*TeamFact
 	for rows.Next() {
 		var fact TeamFact
+		var visibility, rolesJSON sql.NullString
 		var createdAt, updatedAt string
 		if err := rows.Scan(
-			&fact.ID, &fact.TeamID, &fact.Key, &fact.Value,
-			&fact.Author, &createdAt, &updatedAt,
+			&fact.ID, &fact.TeamID, &fact.Key, &fact.Value, &fact.Author,
+			&visibility, &rolesJSON, &createdAt, &updatedAt,
 		); err != nil {
 			continue
 		}
+		fact.Visibility = FactVisibility(visibility.String)
+		if fact.Visibility == "" {
+			fact.Visibility = FactVisibilityTeam
+		}
+		json.Unmarshal([]byte(rolesJSON.String), &fact.Roles)
 		fact.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 		fact.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 		facts = append(facts, &fact)
@@ -612,12 +713,19 @@ func (tm *TeamMemory) BuildTeamContext(agentID string) (string, error) {
 
 	sb.WriteString("## Team Context\n\n")
 
-	// Facts
-	facts, err := tm.GetFacts()
+	// Facts — only the ones this agent's ACL allows it to see.
+	facts, err := tm.GetFactsFor(agentID)
 	if err == nil && len(facts) > 0 {
 		sb.WriteString("### Shared Facts\n")
 		for _, f := range facts {
-			sb.WriteString(fmt.Sprintf("- **%s**: %s (by %s)\n", f.Key, f.Value, f.Author))
+			note := ""
+			switch f.Visibility {
+			case FactVisibilityPrivate:
+				note = " [private]"
+			case FactVisibilityRole:
+				note = fmt.Sprintf(" [restricted: %s]", strings.Join(f.Roles, ", "))
+			}
+			sb.WriteString(fmt.Sprintf("- **%s**: %s (by %s)%s\n", f.Key, f.Value, f.Author, note))
 		}
 		sb.WriteString("\n")
 	}
@@ -68,6 +68,8 @@ func testTeamMemoryDB(t *testing.T) *sql.DB {
 		key        TEXT NOT NULL,
 		value      TEXT NOT NULL,
 		author     TEXT NOT NULL,
+		visibility TEXT DEFAULT 'team',
+		roles      TEXT DEFAULT '[]',
 		created_at TEXT NOT NULL,
 		updated_at TEXT NOT NULL,
 		UNIQUE(team_id, key)
@@ -456,6 +458,92 @@ func TestTeamMemory_SearchFacts(t *testing.T) {
 	}
 }
 
+func TestTeamMemory_FactACLs(t *testing.T) {
+	db := testTeamMemoryDB(t)
+	defer db.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tm := NewTeamMemory("test-team", db, logger)
+
+	tm.SaveFact("shared", "everyone sees this", "alice")
+	if err := tm.SaveFactACL("secret", "alice only", "alice", FactVisibilityPrivate, nil); err != nil {
+		t.Fatalf("SaveFactACL private: %v", err)
+	}
+	if err := tm.SaveFactACL("research", "for researchers", "alice", FactVisibilityRole, []string{"Researcher"}); err != nil {
+		t.Fatalf("SaveFactACL role: %v", err)
+	}
+
+	// Role-restricted without roles is rejected.
+	if err := tm.SaveFactACL("bad", "x", "alice", FactVisibilityRole, nil); err == nil {
+		t.Error("expected error for role visibility without roles")
+	}
+
+	// The author sees everything.
+	authorFacts, err := tm.GetFactsFor("alice")
+	if err != nil {
+		t.Fatalf("GetFactsFor: %v", err)
+	}
+	if len(authorFacts) != 3 {
+		t.Errorf("author should see 3 facts, got %d", len(authorFacts))
+	}
+
+	// Another agent without the role sees only the team-shared fact.
+	otherFacts, _ := tm.GetFactsFor("bob")
+	if len(otherFacts) != 1 || otherFacts[0].Key != "shared" {
+		t.Errorf("bob should see only the shared fact, got %v", otherFacts)
+	}
+
+	// Search is filtered the same way.
+	results, _ := tm.SearchFactsFor("alice only", "bob")
+	if len(results) != 0 {
+		t.Errorf("bob should not find private facts, got %d", len(results))
+	}
+
+	// Restricted reads land in the audit feed.
+	activities, _ := tm.GetActivities(50)
+	audited := false
+	for _, a := range activities {
+		if a.Type == ActivityFactAccessed && a.AgentID == "alice" {
+			audited = true
+		}
+	}
+	if !audited {
+		t.Error("expected a fact_accessed audit entry for alice's restricted reads")
+	}
+}
+
+func TestTeamMemory_FactACLRoleResolution(t *testing.T) {
+	db := testTeamManagerDB(t)
+	defer db.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	mgr := NewTeamManager(db, nil, logger)
+	team, err := mgr.CreateTeam("research-team", "", "owner@s.whatsapp.net", "")
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	if _, err := mgr.CreateAgent(team.ID, "Ada", "Researcher", "", "", "", nil, AgentLevelSpecialist, ""); err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+	if _, err := mgr.CreateAgent(team.ID, "Bob", "Writer", "", "", "", nil, AgentLevelSpecialist, ""); err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+
+	tm := mgr.GetTeamMemory(team.ID)
+	if err := tm.SaveFactACL("findings", "preliminary data", "owner", FactVisibilityRole, []string{"Researcher"}); err != nil {
+		t.Fatalf("SaveFactACL: %v", err)
+	}
+
+	adaFacts, _ := tm.GetFactsFor("ada")
+	if len(adaFacts) != 1 {
+		t.Errorf("researcher should see the role-restricted fact, got %d", len(adaFacts))
+	}
+	bobFacts, _ := tm.GetFactsFor("bob")
+	if len(bobFacts) != 0 {
+		t.Errorf("writer should not see the role-restricted fact, got %d", len(bobFacts))
+	}
+}
+
 func TestTeamMemory_DeleteFact(t *testing.T) {
 	db := testTeamMemoryDB(t)
 	defer db.Close()
@@ -832,7 +832,7 @@ func (h *handlerContext) handleTaskDelete(args map[string]any) (any, error) {
 func registerTeamMemoryDispatcher(executor *ToolExecutor, hctx *handlerContext) {
 	executor.Register(
 		MakeToolDefinition("team_memory",
-			"Manage team memory with actions: fact_save, fact_list, fact_delete, doc_create, doc_list, doc_get, doc_update, doc_delete, standup. Shared memory for team knowledge.",
+			"Manage team memory with actions: fact_save, fact_list, fact_delete, doc_create, doc_list, doc_get, doc_update, doc_delete, standup. Shared memory for team knowledge. Facts support ACLs: team (everyone), role (listed roles), private (author only).",
 			map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -853,6 +853,16 @@ func registerTeamMemoryDispatcher(executor *ToolExecutor, hctx *handlerContext)
 						"type":        "string",
 						"description": "Fact value (for fact_save)",
 					},
+					"visibility": map[string]any{
+						"type":        "string",
+						"enum":        []string{"team", "role", "private"},
+						"description": "Fact visibility (for fact_save, default: team)",
+					},
+					"roles": map[string]any{
+						"type":        "array",
+						"items":       map[string]any{"type": "string"},
+						"description": "Roles allowed to read a role-restricted fact (for fact_save with visibility=role)",
+					},
 					"doc_id": map[string]any{
 						"type":        "string",
 						"description": "Document ID (for doc_get/doc_update/doc_delete)",
@@ -889,7 +899,7 @@ func registerTeamMemoryDispatcher(executor *ToolExecutor, hctx *handlerContext)
 			case "fact_save":
 				return hctx.handleFactSave(ctx, args)
 			case "fact_list":
-				return hctx.handleFactList(args)
+				return hctx.handleFactList(ctx, args)
 			case "fact_delete":
 				return hctx.handleFactDelete(args)
 			case "doc_create":
@@ -921,17 +931,23 @@ func (h *handlerContext) handleFactSave(ctx context.Context, args map[string]any
 	}
 
 	author := getCallerID(ctx)
+	visibilityStr, _ := args["visibility"].(string)
+	visibility := FactVisibility(visibilityStr)
+	roles := getStringSlice(args, "roles")
 
 	teamMem := h.teamMgr.GetTeamMemory(teamID)
 
-	if err := teamMem.SaveFact(key, value, author); err != nil {
+	if err := teamMem.SaveFactACL(key, value, author, visibility, roles); err != nil {
 		return nil, fmt.Errorf("save fact: %w", err)
 	}
 
+	if visibility != FactVisibilityTeam {
+		return fmt.Sprintf("Fact saved (%s): %s = %s", visibility, key, value), nil
+	}
 	return fmt.Sprintf("Fact saved: %s = %s", key, value), nil
 }
 
-func (h *handlerContext) handleFactList(args map[string]any) (any, error) {
+func (h *handlerContext) handleFactList(ctx context.Context, args map[string]any) (any, error) {
 	teamRef, _ := args["team_id"].(string)
 	teamID, err := h.resolveTeamID(teamRef)
 	if err != nil {
@@ -940,7 +956,8 @@ func (h *handlerContext) handleFactList(args map[string]any) (any, error) {
 
 	teamMem := h.teamMgr.GetTeamMemory(teamID)
 
-	facts, err := teamMem.GetFacts()
+	// Only the facts the caller's ACL allows; restricted reads are audited.
+	facts, err := teamMem.GetFactsFor(getCallerID(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("get facts: %w", err)
 	}
@@ -952,7 +969,14 @@ func (h *handlerContext) handleFactList(args map[string]any) (any, error) {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Team Facts (%d):\n", len(facts)))
 	for _, f := range facts {
-		sb.WriteString(fmt.Sprintf("  • %s: %s\n", f.Key, f.Value))
+		note := ""
+		switch f.Visibility {
+		case FactVisibilityPrivate:
+			note = " [private]"
+		case FactVisibilityRole:
+			note = fmt.Sprintf(" [restricted: %s]", strings.Join(f.Roles, ", "))
+		}
+		sb.WriteString(fmt.Sprintf("  • %s: %s%s\n", f.Key, f.Value, note))
 	}
 	return sb.String(), nil
 }
@@ -96,6 +96,8 @@ func testDispatcherDB(t *testing.T) *sql.DB {
 		key        TEXT NOT NULL,
 		value      TEXT NOT NULL,
 		author     TEXT NOT NULL,
+		visibility TEXT DEFAULT 'team',
+		roles      TEXT DEFAULT '[]',
 		created_at TEXT NOT NULL,
 		updated_at TEXT NOT NULL,
 		UNIQUE(team_id, key)
@@ -576,7 +578,7 @@ func TestDispatcher_TeamMemory_FactList(t *testing.T) {
 	hctx.handleFactSave(ctx, map[string]any{"team_id": team.ID, "key": "key1", "value": "value1"})
 	hctx.handleFactSave(ctx, map[string]any{"team_id": team.ID, "key": "key2", "value": "value2"})
 
-	result, err := hctx.handleFactList(map[string]any{"team_id": team.ID})
+	result, err := hctx.handleFactList(ctx, map[string]any{"team_id": team.ID})
 	if err != nil {
 		t.Fatalf("handleFactList failed: %v", err)
 	}
@@ -202,6 +202,21 @@ type TeamMessage struct {
 
 // ─── Team Facts (Shared Memory) ───
 
+// FactVisibility controls who can read a team fact.
+type FactVisibility string
+
+const (
+	// FactVisibilityTeam makes the fact readable by every team member (default).
+	FactVisibilityTeam FactVisibility = "team"
+
+	// FactVisibilityRole restricts the fact to agents whose role is listed
+	// in the fact's Roles (the author always keeps access).
+	FactVisibilityRole FactVisibility = "role"
+
+	// FactVisibilityPrivate restricts the fact to its author.
+	FactVisibilityPrivate FactVisibility = "private"
+)
+
 // TeamFact represents a shared fact in team memory.
 type TeamFact struct {
 	// ID is the unique fact identifier.
@@ -219,6 +234,12 @@ type TeamFact struct {
 	// Author is the agent or user who created this fact.
 	Author string `json:"author" yaml:"author"`
 
+	// Visibility controls who can read this fact (team/role/private).
+	Visibility FactVisibility `json:"visibility" yaml:"visibility"`
+
+	// Roles lists the agent roles allowed to read a role-restricted fact.
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+
 	// CreatedAt is when the fact was created.
 	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
 
@@ -271,6 +292,7 @@ const (
 	ActivityMessageSent    ActivityType = "message_sent"
 	ActivityMention        ActivityType = "mention"
 	ActivityFactCreated    ActivityType = "fact_created"
+	ActivityFactAccessed   ActivityType = "fact_accessed"
 	ActivityAgentActive    ActivityType = "agent_active"
 	ActivityAgentIdle      ActivityType = "agent_idle"
 	ActivityDocumentCreated ActivityType = "document_created"
@@ -0,0 +1,391 @@
+// Package copilot – test_feedback.go parses test runner output into
+// structured failures (file, line, message) the agent can act on directly,
+// and implements the watch_tests daemon mode that re-runs the suite in the
+// background and feeds new failures into the session as they happen.
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// testFailure is one parsed test failure.
+type testFailure struct {
+	Test    string `json:"test"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// maxFailureMessage caps the message attached to a single failure.
+const maxFailureMessage = 500
+
+// parseTestFailures extracts structured failures from raw runner output.
+// Supported: go (go test -json), pytest, jest. Unknown frameworks return nil.
+func parseTestFailures(framework, output string) []testFailure {
+	switch framework {
+	case "go":
+		return parseGoTestJSON(output)
+	case "pytest":
+		return parsePytestFailures(output)
+	case "jest":
+		return parseJestFailures(output)
+	}
+	return nil
+}
+
+// ---------- go test -json ----------
+
+// goTestEvent is one event line emitted by `go test -json`.
+type goTestEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+	Output  string `json:"Output"`
+}
+
+var goFileLineRe = regexp.MustCompile(`([A-Za-z0-9_./\\-]+\.go):(\d+):`)
+
+// parseGoTestJSON parses a `go test -json` event stream. Non-JSON lines
+// (build errors, stray output) are skipped.
+func parseGoTestJSON(output string) []testFailure {
+	outputs := make(map[string][]string)
+	var failures []testFailure
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			continue
+		}
+		key := ev.Package + "/" + ev.Test
+
+		switch ev.Action {
+		case "output":
+			if ev.Test != "" {
+				outputs[key] = append(outputs[key], ev.Output)
+			}
+		case "fail":
+			if ev.Test == "" {
+				continue // package-level fail, covered by its tests
+			}
+			f := testFailure{Test: ev.Test}
+			msg := strings.TrimRight(strings.Join(outputs[key], ""), "\n")
+			if m := goFileLineRe.FindStringSubmatch(msg); m != nil {
+				f.File = m[1]
+				f.Line, _ = strconv.Atoi(m[2])
+			}
+			f.Message = truncateOutput(strings.TrimSpace(msg), maxFailureMessage)
+			failures = append(failures, f)
+		}
+	}
+	return failures
+}
+
+// goTestPlainOutput reconstructs human-readable output from a `go test -json`
+// stream. Returns the input unchanged if it does not look like JSON events.
+func goTestPlainOutput(output string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "{") {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal([]byte(trimmed), &ev); err != nil {
+			continue
+		}
+		if ev.Action == "output" {
+			b.WriteString(ev.Output)
+		}
+	}
+	if b.Len() == 0 {
+		return output
+	}
+	return b.String()
+}
+
+// ---------- pytest ----------
+
+var (
+	pytestSummaryRe = regexp.MustCompile(`^FAILED (\S+?)::(\S+?)(?: - (.*))?$`)
+	pytestBlockRe   = regexp.MustCompile(`^_{3,}\s+(.+?)\s+_{3,}$`)
+	pytestLocRe     = regexp.MustCompile(`^([A-Za-z0-9_./\\-]+\.py):(\d+):`)
+)
+
+// parsePytestFailures parses pytest output: the traceback blocks provide
+// file/line/assertion details, the short summary provides the canonical list.
+func parsePytestFailures(output string) []testFailure {
+	type blockInfo struct {
+		file    string
+		line    int
+		message string
+	}
+	blocks := make(map[string]*blockInfo)
+	current := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := pytestBlockRe.FindStringSubmatch(line); m != nil {
+			current = m[1]
+			blocks[current] = &blockInfo{}
+			continue
+		}
+		info, ok := blocks[current]
+		if current == "" || !ok {
+			continue
+		}
+		if m := pytestLocRe.FindStringSubmatch(line); m != nil {
+			info.file = m[1]
+			info.line, _ = strconv.Atoi(m[2])
+		}
+		if strings.HasPrefix(line, "E ") {
+			if info.message != "" {
+				info.message += "\n"
+			}
+			info.message += strings.TrimSpace(strings.TrimPrefix(line, "E "))
+		}
+	}
+
+	var failures []testFailure
+	for _, line := range strings.Split(output, "\n") {
+		m := pytestSummaryRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		f := testFailure{Test: m[2], File: m[1], Message: m[3]}
+		// Block headers use "TestClass.test_method", summaries "TestClass::test_method".
+		info := blocks[strings.ReplaceAll(m[2], "::", ".")]
+		if info == nil {
+			info = blocks[m[2]]
+		}
+		if info != nil {
+			if info.file != "" {
+				f.File = info.file
+				f.Line = info.line
+			}
+			if info.message != "" {
+				f.Message = truncateOutput(info.message, maxFailureMessage)
+			}
+		}
+		failures = append(failures, f)
+	}
+	return failures
+}
+
+// ---------- jest ----------
+
+var (
+	jestFailRe = regexp.MustCompile(`^\s*[●✕]\s+(.+)$`)
+	jestLocRe  = regexp.MustCompile(`\(?([A-Za-z0-9_./\\-]+\.(?:js|jsx|ts|tsx)):(\d+):\d+\)?`)
+)
+
+// parseJestFailures parses jest/vitest output: "●" blocks name the failed
+// test, the first stack frame gives file and line.
+func parseJestFailures(output string) []testFailure {
+	var failures []testFailure
+	var cur *testFailure
+	var msg []string
+
+	flush := func() {
+		if cur != nil {
+			cur.Message = truncateOutput(strings.TrimSpace(strings.Join(msg, "\n")), maxFailureMessage)
+			failures = append(failures, *cur)
+		}
+		cur = nil
+		msg = nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := jestFailRe.FindStringSubmatch(line); m != nil {
+			flush()
+			cur = &testFailure{Test: strings.TrimSpace(m[1])}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "at ") {
+			if cur.File == "" {
+				if m := jestLocRe.FindStringSubmatch(trimmed); m != nil {
+					cur.File = m[1]
+					cur.Line, _ = strconv.Atoi(m[2])
+				}
+			}
+			continue
+		}
+		if trimmed != "" {
+			msg = append(msg, trimmed)
+		}
+	}
+	flush()
+	return failures
+}
+
+// frameworkFromCommand guesses the framework behind an explicit test command
+// so its output can still be parsed. Returns "" if unknown.
+func frameworkFromCommand(cmd string) string {
+	switch {
+	case strings.Contains(cmd, "go test"):
+		return "go"
+	case strings.Contains(cmd, "pytest"):
+		return "pytest"
+	case strings.Contains(cmd, "jest"), strings.Contains(cmd, "vitest"):
+		return "jest"
+	}
+	return ""
+}
+
+// ensureGoJSONFlag inserts -json into a `go test` command so its output can
+// be parsed into structured failures.
+func ensureGoJSONFlag(cmd string) string {
+	if strings.Contains(cmd, "-json") {
+		return cmd
+	}
+	return strings.Replace(cmd, "go test", "go test -json", 1)
+}
+
+// ---------- watch_tests ----------
+
+const (
+	defaultWatchLabel    = "test-watch"
+	defaultWatchInterval = 30
+	minWatchInterval     = 5
+)
+
+// RegisterTestWatchTool registers watch_tests: a daemon mode that re-runs
+// the test suite periodically and reports new failures via notify, which
+// injects them into the originating session as followup messages.
+func RegisterTestWatchTool(executor *ToolExecutor, dm *DaemonManager, notify func(sessionID, channel, chatID, content string)) {
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "watch_tests",
+			Description: "Re-run the test suite in the background on an interval and get new failures injected into this chat as they happen. Runs as a managed daemon; stop it with daemon_stop.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"command":          map[string]any{"type": "string", "description": "Explicit test command (overrides auto-detect)"},
+					"framework":        map[string]any{"type": "string", "enum": []string{"go", "jest", "pytest"}, "description": "Force a specific framework"},
+					"interval_seconds": map[string]any{"type": "integer", "description": "Seconds between test runs (default: 30, min: 5)"},
+					"label":            map[string]any{"type": "string", "description": "Daemon label (default: test-watch)"},
+				},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		cmdStr, _ := args["command"].(string)
+		framework, _ := args["framework"].(string)
+		label, _ := args["label"].(string)
+		if label == "" {
+			label = defaultWatchLabel
+		}
+		interval := defaultWatchInterval
+		if v, ok := args["interval_seconds"].(float64); ok && v > 0 {
+			interval = int(v)
+		}
+		if interval < minWatchInterval {
+			interval = minWatchInterval
+		}
+
+		if framework == "" {
+			if cmdStr != "" {
+				framework = frameworkFromCommand(cmdStr)
+			} else {
+				framework = detectTestFramework()
+			}
+		}
+		if cmdStr == "" {
+			cmdStr = buildTestCommand(framework, "", false)
+		}
+		if framework == "go" {
+			cmdStr = ensureGoJSONFlag(cmdStr)
+		}
+
+		loop := fmt.Sprintf("while true; do %s; sleep %d; done", cmdStr, interval)
+		d, err := dm.StartDaemon(label, loop, 0, "")
+		if err != nil {
+			return nil, err
+		}
+
+		// Capture the session and delivery target now: the run context is
+		// cancelled when this agent run finishes, but the watcher lives on.
+		sessionID := SessionIDFromContext(ctx)
+		target := DeliveryTargetFromContext(ctx)
+		go watchTestFailures(dm, label, framework, interval, func(fresh []testFailure) {
+			if notify != nil && sessionID != "" {
+				notify(sessionID, target.Channel, target.ChatID, formatWatchReport(label, fresh))
+			}
+		})
+
+		return fmt.Sprintf("Watching tests with %q every %ds (daemon %q, PID %d). New failures will be posted to this chat. Stop with daemon_stop.",
+			cmdStr, interval, label, d.PID), nil
+	})
+}
+
+// watchTestFailures polls the watch daemon's output and reports failures it
+// has not seen before. Returns when the daemon stops or is removed.
+func watchTestFailures(dm *DaemonManager, label, framework string, intervalSec int, report func([]testFailure)) {
+	seen := make(map[string]bool)
+	ticker := time.NewTicker(time.Duration(intervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		logs, err := dm.GetLogs(label, 0, "")
+		if err != nil {
+			return // daemon removed
+		}
+
+		running := false
+		for _, d := range dm.List() {
+			if d.Label == label && d.Status == "running" {
+				running = true
+				break
+			}
+		}
+
+		var fresh []testFailure
+		for _, f := range parseTestFailures(framework, logs) {
+			key := fmt.Sprintf("%s|%s|%d|%s", f.Test, f.File, f.Line, f.Message)
+			if !seen[key] {
+				seen[key] = true
+				fresh = append(fresh, f)
+			}
+		}
+		if len(fresh) > 0 {
+			report(fresh)
+		}
+
+		if !running {
+			return
+		}
+	}
+}
+
+// formatWatchReport renders new failures as a chat message for the session.
+func formatWatchReport(label string, failures []testFailure) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("[watch_tests:%s] %d new test failure(s):\n", label, len(failures)))
+	for _, f := range failures {
+		b.WriteString("• " + f.Test)
+		if f.File != "" {
+			b.WriteString(fmt.Sprintf(" (%s:%d)", f.File, f.Line))
+		}
+		if f.Message != "" {
+			first := f.Message
+			if idx := strings.IndexByte(first, '\n'); idx >= 0 {
+				first = first[:idx]
+			}
+			b.WriteString(" — " + first)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,130 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+const goTestJSONFixture = `{"Time":"2025-01-01T10:00:00Z","Action":"run","Package":"example.com/pkg","Test":"TestAdd"}
+{"Time":"2025-01-01T10:00:00Z","Action":"output","Package":"example.com/pkg","Test":"TestAdd","Output":"=== RUN   TestAdd\n"}
+{"Time":"2025-01-01T10:00:00Z","Action":"output","Package":"example.com/pkg","Test":"TestAdd","Output":"    math_test.go:42: expected 4, got 5\n"}
+{"Time":"2025-01-01T10:00:00Z","Action":"fail","Package":"example.com/pkg","Test":"TestAdd","Elapsed":0.01}
+{"Time":"2025-01-01T10:00:00Z","Action":"output","Package":"example.com/pkg","Test":"TestOK","Output":"=== RUN   TestOK\n"}
+{"Time":"2025-01-01T10:00:00Z","Action":"pass","Package":"example.com/pkg","Test":"TestOK","Elapsed":0.01}
+{"Time":"2025-01-01T10:00:00Z","Action":"fail","Package":"example.com/pkg","Elapsed":0.05}
+`
+
+func TestParseGoTestJSON(t *testing.T) {
+	failures := parseGoTestJSON(goTestJSONFixture)
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d: %+v", len(failures), failures)
+	}
+	f := failures[0]
+	if f.Test != "TestAdd" {
+		t.Errorf("expected TestAdd, got %s", f.Test)
+	}
+	if f.File != "math_test.go" || f.Line != 42 {
+		t.Errorf("expected math_test.go:42, got %s:%d", f.File, f.Line)
+	}
+	if !strings.Contains(f.Message, "expected 4, got 5") {
+		t.Errorf("message missing assertion detail: %q", f.Message)
+	}
+}
+
+func TestGoTestPlainOutput(t *testing.T) {
+	plain := goTestPlainOutput(goTestJSONFixture)
+	if !strings.Contains(plain, "=== RUN   TestAdd") || strings.Contains(plain, `"Action"`) {
+		t.Errorf("plain output not reconstructed: %q", plain)
+	}
+
+	// Non-JSON input passes through unchanged.
+	raw := "ok  \texample.com/pkg\t0.1s\n"
+	if goTestPlainOutput(raw) != raw {
+		t.Error("non-JSON output should be returned unchanged")
+	}
+}
+
+const pytestFixture = `=================================== FAILURES ===================================
+_________________________________ test_add _________________________________
+
+    def test_add():
+>       assert add(2, 2) == 4
+E       assert 5 == 4
+
+tests/test_math.py:12: AssertionError
+=========================== short test summary info ============================
+FAILED tests/test_math.py::test_add - assert 5 == 4
+========================= 1 failed, 3 passed in 0.12s ==========================
+`
+
+func TestParsePytestFailures(t *testing.T) {
+	failures := parsePytestFailures(pytestFixture)
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d: %+v", len(failures), failures)
+	}
+	f := failures[0]
+	if f.Test != "test_add" {
+		t.Errorf("expected test_add, got %s", f.Test)
+	}
+	if f.File != "tests/test_math.py" || f.Line != 12 {
+		t.Errorf("expected tests/test_math.py:12, got %s:%d", f.File, f.Line)
+	}
+	if !strings.Contains(f.Message, "assert 5 == 4") {
+		t.Errorf("message missing assertion detail: %q", f.Message)
+	}
+}
+
+const jestFixture = `FAIL src/math.test.ts
+  ● math › adds numbers
+
+    expect(received).toBe(expected)
+
+    Expected: 4
+    Received: 5
+
+      at Object.<anonymous> (src/math.test.ts:8:21)
+      at Promise.then.completed (node_modules/jest-circus/build/utils.js:293:28)
+
+Tests: 1 failed, 2 passed, 3 total
+`
+
+func TestParseJestFailures(t *testing.T) {
+	failures := parseJestFailures(jestFixture)
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d: %+v", len(failures), failures)
+	}
+	f := failures[0]
+	if f.Test != "math › adds numbers" {
+		t.Errorf("unexpected test name: %q", f.Test)
+	}
+	if f.File != "src/math.test.ts" || f.Line != 8 {
+		t.Errorf("expected src/math.test.ts:8, got %s:%d", f.File, f.Line)
+	}
+	if !strings.Contains(f.Message, "Expected: 4") {
+		t.Errorf("message missing expectation: %q", f.Message)
+	}
+}
+
+func TestFrameworkFromCommand(t *testing.T) {
+	cases := map[string]string{
+		"go test -run TestFoo ./...": "go",
+		"python -m pytest tests/":    "pytest",
+		"npx jest --ci":              "jest",
+		"npx vitest run":             "jest",
+		"make check":                 "",
+	}
+	for cmd, want := range cases {
+		if got := frameworkFromCommand(cmd); got != want {
+			t.Errorf("frameworkFromCommand(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
+
+func TestEnsureGoJSONFlag(t *testing.T) {
+	if got := ensureGoJSONFlag("go test ./..."); got != "go test -json ./..." {
+		t.Errorf("unexpected command: %q", got)
+	}
+	if got := ensureGoJSONFlag("go test -json ./..."); got != "go test -json ./..." {
+		t.Errorf("-json should not be duplicated: %q", got)
+	}
+}
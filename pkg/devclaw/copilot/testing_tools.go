@@ -18,11 +18,12 @@ import (
 // ---------- Data Types ----------
 
 type testRunResult struct {
-	Framework string `json:"framework"`
-	Command   string `json:"command"`
-	ExitCode  int    `json:"exit_code"`
-	Output    string `json:"output"`
-	Duration  string `json:"duration"`
+	Framework string        `json:"framework"`
+	Command   string        `json:"command"`
+	ExitCode  int           `json:"exit_code"`
+	Output    string        `json:"output"`
+	Duration  string        `json:"duration"`
+	Failures  []testFailure `json:"failures,omitempty"`
 }
 
 type apiTestResult struct {
@@ -66,6 +67,12 @@ func RegisterTestingTools(executor *ToolExecutor) {
 				framework = detectTestFramework()
 			}
 			cmdStr = buildTestCommand(framework, path, verbose)
+			if framework == "go" {
+				// JSON events give structured failures (file, line, message).
+				cmdStr = ensureGoJSONFlag(cmdStr)
+			}
+		} else if framework == "" {
+			framework = frameworkFromCommand(cmdStr)
 		}
 
 		start := time.Now()
@@ -83,12 +90,19 @@ func RegisterTestingTools(executor *ToolExecutor) {
 			}
 		}
 
+		output := string(out)
+		failures := parseTestFailures(framework, output)
+		if framework == "go" && strings.Contains(cmdStr, "-json") {
+			output = goTestPlainOutput(output)
+		}
+
 		result := testRunResult{
 			Framework: framework,
 			Command:   cmdStr,
 			ExitCode:  exitCode,
-			Output:    truncateOutput(string(out), 6000),
+			Output:    truncateOutput(output, 6000),
 			Duration:  duration.Truncate(time.Millisecond).String(),
+			Failures:  failures,
 		}
 
 		data, _ := json.MarshalIndent(result, "", "  ")
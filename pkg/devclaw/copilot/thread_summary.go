@@ -0,0 +1,220 @@
+// Package copilot – thread_summary.go implements the /summarize command:
+// an on-demand structured recap of recent group discussion with decisions
+// and action items. Extracted action items can optionally be stored as
+// one-shot scheduler reminders (--tasks). Only exchanges the bot saw are
+// included; channels do not replay messages the bot never received.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+	"github.com/jholhewres/devclaw/pkg/devclaw/scheduler"
+)
+
+const (
+	// threadSummaryDefaultEntries is how many history entries "last" covers
+	// when no count is given.
+	threadSummaryDefaultEntries = 30
+
+	// threadSummaryMaxEntries caps "last N" to keep the prompt bounded.
+	threadSummaryMaxEntries = 100
+
+	// threadSummaryTimeout bounds the recap LLM call.
+	threadSummaryTimeout = 60 * time.Second
+
+	// threadSummarySystemPrompt instructs the recap structure. The Action
+	// Items heading is load-bearing: parseActionItems scans for it.
+	threadSummarySystemPrompt = `You summarize group chat discussions faithfully. Produce a structured recap with exactly these sections:
+
+## Summary
+2-5 sentences covering the main topics discussed.
+
+## Decisions
+Bullet list of decisions that were made ("- decision"). Write "- (none)" if no decisions were made.
+
+## Action Items
+Bullet list of concrete follow-ups ("- [owner] task"). Use the participant's name as owner when clear, or "[?]" when unassigned. Write "- (none)" if there are no action items.
+
+Keep names as they appear in the transcript. Do not invent decisions or tasks that were not discussed.`
+)
+
+// summarizeCommand handles /summarize [today|last N] [--tasks].
+func (a *Assistant) summarizeCommand(args []string, msg *channels.IncomingMessage) string {
+	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	session := resolved.Session
+
+	entries, scope, err := a.selectSummaryEntries(session, args)
+	if err != nil {
+		return err.Error()
+	}
+	if len(entries) == 0 {
+		return fmt.Sprintf("Nothing to summarize %s.", scope)
+	}
+
+	transcript := formatSummaryTranscript(entries)
+
+	ctx, cancel := context.WithTimeout(a.ctx, threadSummaryTimeout)
+	defer cancel()
+
+	recap, err := a.llmClient.Complete(ctx, threadSummarySystemPrompt, nil,
+		fmt.Sprintf("Transcript (%s, %d exchanges):\n\n%s", scope, len(entries), transcript))
+	if err != nil {
+		a.logger.Warn("thread summary failed", "error", err)
+		return "Could not generate the summary right now. Try again in a moment."
+	}
+
+	response := fmt.Sprintf("*Recap (%s)*\n\n%s", scope, strings.TrimSpace(recap))
+
+	// Optionally store action items as one-shot scheduler reminders.
+	if containsFlag(args, "--tasks") {
+		items := parseActionItems(recap)
+		if len(items) == 0 {
+			response += "\n\n_No action items to schedule._"
+		} else {
+			created := a.scheduleActionItems(items, msg)
+			response += fmt.Sprintf("\n\n_%d action item reminder(s) scheduled for tomorrow._", created)
+		}
+	}
+
+	return response
+}
+
+// selectSummaryEntries resolves the requested scope into history entries.
+// Supported scopes: "today" (entries since local midnight) and
+// "last N" / bare N (most recent N entries, default 30, capped at 100).
+func (a *Assistant) selectSummaryEntries(session *Session, args []string) ([]ConversationEntry, string, error) {
+	// Strip flags so "last N" positions stay stable.
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) > 0 && strings.EqualFold(positional[0], "today") {
+		loc, err := time.LoadLocation(a.config.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		now := time.Now().In(loc)
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+		all := session.RecentHistory(threadSummaryMaxEntries)
+		var todays []ConversationEntry
+		for _, e := range all {
+			if !e.Timestamp.Before(midnight) {
+				todays = append(todays, e)
+			}
+		}
+		return todays, "today", nil
+	}
+
+	count := threadSummaryDefaultEntries
+	countArg := ""
+	if len(positional) > 0 {
+		if strings.EqualFold(positional[0], "last") {
+			if len(positional) > 1 {
+				countArg = positional[1]
+			}
+		} else {
+			countArg = positional[0]
+		}
+	}
+	if countArg != "" {
+		n, err := strconv.Atoi(countArg)
+		if err != nil || n <= 0 {
+			return nil, "", fmt.Errorf("Usage: /summarize [today|last N] [--tasks]")
+		}
+		count = n
+	}
+	if count > threadSummaryMaxEntries {
+		count = threadSummaryMaxEntries
+	}
+
+	entries := session.RecentHistory(count)
+	return entries, fmt.Sprintf("last %d messages", len(entries)), nil
+}
+
+// formatSummaryTranscript renders history entries as an attributed
+// transcript, skipping silent bot replies.
+func formatSummaryTranscript(entries []ConversationEntry) string {
+	var b strings.Builder
+	for _, e := range entries {
+		speaker := "User"
+		if e.SenderName != "" {
+			speaker = e.SenderName
+		}
+		b.WriteString(fmt.Sprintf("%s: %s\n", speaker, e.UserMessage))
+		reply := strings.TrimSpace(e.AssistantResponse)
+		if reply != "" && reply != "NO_REPLY" {
+			b.WriteString(fmt.Sprintf("Assistant: %s\n", reply))
+		}
+	}
+	return b.String()
+}
+
+// parseActionItems extracts the bullet list under the "## Action Items"
+// heading of a recap. Placeholder bullets like "(none)" are skipped.
+func parseActionItems(recap string) []string {
+	var items []string
+	inSection := false
+	for _, line := range strings.Split(recap, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			inSection = strings.EqualFold(strings.TrimLeft(trimmed, "# "), "action items")
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+			item := strings.TrimSpace(trimmed[2:])
+			if item == "" || strings.EqualFold(item, "(none)") {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// scheduleActionItems stores action items as one-shot reminder jobs firing
+// the next morning in the group's chat. Returns how many were created.
+func (a *Assistant) scheduleActionItems(items []string, msg *channels.IncomingMessage) int {
+	if a.scheduler == nil {
+		return 0
+	}
+
+	loc, err := time.LoadLocation(a.config.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 9, 0, 0, 0, loc)
+
+	created := 0
+	for i, item := range items {
+		job := &scheduler.Job{
+			ID:       fmt.Sprintf("action-item-%d-%d", now.Unix(), i),
+			Schedule: tomorrow.Format(time.RFC3339),
+			Type:     "at",
+			Command:  fmt.Sprintf("Remind the group about this action item from the last recap: %s", item),
+			Channel:  msg.Channel,
+			ChatID:   msg.ChatID,
+			Enabled:  true,
+			Announce: true,
+			Labels:   []string{"action-item"},
+		}
+		if err := a.scheduler.Add(job); err != nil {
+			a.logger.Warn("failed to schedule action item", "item", item, "error", err)
+			continue
+		}
+		created++
+	}
+	return created
+}
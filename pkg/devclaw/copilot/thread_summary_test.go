@@ -0,0 +1,107 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseActionItems(t *testing.T) {
+	recap := `## Summary
+The team discussed the release.
+
+## Decisions
+- Ship on Friday
+
+## Action Items
+- [João] prepare the changelog
+* [?] book the retro room
+- (none)
+`
+	items := parseActionItems(recap)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d: %v", len(items), items)
+	}
+	if items[0] != "[João] prepare the changelog" {
+		t.Errorf("got %q", items[0])
+	}
+	if items[1] != "[?] book the retro room" {
+		t.Errorf("got %q", items[1])
+	}
+}
+
+func TestParseActionItemsEmpty(t *testing.T) {
+	recap := "## Summary\nShort chat.\n\n## Action Items\n- (none)\n"
+	if items := parseActionItems(recap); len(items) != 0 {
+		t.Errorf("expected no items, got %v", items)
+	}
+
+	// Bullets outside the Action Items section are ignored.
+	recap = "## Decisions\n- Ship on Friday\n"
+	if items := parseActionItems(recap); len(items) != 0 {
+		t.Errorf("expected no items, got %v", items)
+	}
+}
+
+func TestFormatSummaryTranscript(t *testing.T) {
+	entries := []ConversationEntry{
+		{SenderName: "João", UserMessage: "Status?", AssistantResponse: "All green."},
+		{SenderName: "Maria", UserMessage: "Ok!", AssistantResponse: "NO_REPLY"},
+		{UserMessage: "ping", AssistantResponse: ""},
+	}
+
+	transcript := formatSummaryTranscript(entries)
+
+	if !strings.Contains(transcript, "João: Status?") {
+		t.Errorf("missing attributed message: %q", transcript)
+	}
+	if !strings.Contains(transcript, "Assistant: All green.") {
+		t.Errorf("missing assistant reply: %q", transcript)
+	}
+	if strings.Contains(transcript, "NO_REPLY") {
+		t.Errorf("silent replies must be skipped: %q", transcript)
+	}
+	if !strings.Contains(transcript, "User: ping") {
+		t.Errorf("unattributed messages fall back to User: %q", transcript)
+	}
+}
+
+func TestSelectSummaryEntries(t *testing.T) {
+	a := &Assistant{config: &Config{Timezone: "UTC"}}
+	session := &Session{ID: "s", maxHistory: 200}
+
+	old := ConversationEntry{UserMessage: "old", Timestamp: time.Now().Add(-48 * time.Hour)}
+	session.history = append(session.history, old)
+	for i := 0; i < 5; i++ {
+		session.AddMessage("recent", "ok")
+	}
+
+	t.Run("today excludes older entries", func(t *testing.T) {
+		entries, scope, err := a.selectSummaryEntries(session, []string{"today"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if scope != "today" {
+			t.Errorf("got scope %q", scope)
+		}
+		if len(entries) != 5 {
+			t.Errorf("expected 5 entries, got %d", len(entries))
+		}
+	})
+
+	t.Run("last N with flags stripped", func(t *testing.T) {
+		entries, _, err := a.selectSummaryEntries(session, []string{"last", "3", "--tasks"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 3 {
+			t.Errorf("expected 3 entries, got %d", len(entries))
+		}
+	})
+
+	t.Run("invalid count errors", func(t *testing.T) {
+		if _, _, err := a.selectSummaryEntries(session, []string{"last", "zero"}); err == nil {
+			t.Error("expected usage error")
+		}
+	})
+}
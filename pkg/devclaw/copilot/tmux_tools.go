@@ -0,0 +1,269 @@
+// Package copilot – tmux_tools.go integrates tmux so the agent can drive
+// long-lived interactive processes (REPLs, ssh sessions, watch builds)
+// beyond the one-shot bash tool: create sessions, send keystrokes, and
+// capture pane output in pageable chunks.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	// tmuxSessionPrefix namespaces agent-created sessions so tmux_list
+	// doesn't mix them up with the user's own sessions.
+	tmuxSessionPrefix = "devclaw-"
+
+	// tmuxCaptureMaxLines caps how many lines one tmux_capture call returns;
+	// older output is paged with the start_line parameter.
+	tmuxCaptureMaxLines = 200
+)
+
+// ---------- tmux Helpers ----------
+
+// runTmux executes a tmux command and returns trimmed combined output.
+func runTmux(args ...string) (string, error) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return "", fmt.Errorf("tmux not installed on this host")
+	}
+	cmd := exec.Command("tmux", args...)
+	out, err := cmd.CombinedOutput()
+	result := strings.TrimSpace(string(out))
+	if err != nil {
+		if result != "" {
+			return "", fmt.Errorf("tmux %s: %s", args[0], result)
+		}
+		return "", fmt.Errorf("tmux %s: %w", args[0], err)
+	}
+	return result, nil
+}
+
+// tmuxSessionName namespaces a session name with the agent prefix, unless
+// it is already prefixed.
+func tmuxSessionName(name string) string {
+	if strings.HasPrefix(name, tmuxSessionPrefix) {
+		return name
+	}
+	return tmuxSessionPrefix + name
+}
+
+// ---------- Tool Registration ----------
+
+// RegisterTmuxTools registers tmux session management tools in the executor.
+func RegisterTmuxTools(executor *ToolExecutor) {
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "tmux_create",
+			Description: "Create a detached tmux session for driving an interactive process (REPL, ssh, long build). Use tmux_send to type into it and tmux_capture to read the screen. Session names are prefixed with 'devclaw-'.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"session": map[string]any{"type": "string", "description": "Session name (e.g. 'repl', 'deploy')"},
+					"command": map[string]any{"type": "string", "description": "Command to run in the session (default: a shell)"},
+					"width":   map[string]any{"type": "integer", "description": "Pane width in columns (default: 200)"},
+					"height":  map[string]any{"type": "integer", "description": "Pane height in rows (default: 50)"},
+				},
+				"required": []string{"session"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		session, _ := args["session"].(string)
+		command, _ := args["command"].(string)
+		if session == "" {
+			return nil, fmt.Errorf("session is required")
+		}
+		width, height := 200, 50
+		if v, ok := args["width"].(float64); ok && v > 0 {
+			width = int(v)
+		}
+		if v, ok := args["height"].(float64); ok && v > 0 {
+			height = int(v)
+		}
+
+		name := tmuxSessionName(session)
+		tmuxArgs := []string{"new-session", "-d", "-s", name,
+			"-x", strconv.Itoa(width), "-y", strconv.Itoa(height)}
+		if command != "" {
+			tmuxArgs = append(tmuxArgs, command)
+		}
+		if _, err := runTmux(tmuxArgs...); err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf("tmux session %q created (%dx%d). Type with tmux_send, read with tmux_capture, kill with tmux_kill.",
+			name, width, height), nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "tmux_list",
+			Description: "List agent-managed tmux sessions with their creation time and attach status.",
+			Parameters: mustJSON(map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{},
+				"additionalProperties": false,
+			}),
+		},
+	}, func(_ context.Context, _ map[string]any) (any, error) {
+		out, err := runTmux("list-sessions", "-F", "#{session_name}|#{session_created}|#{session_attached}|#{session_windows}")
+		if err != nil {
+			// "no server running" just means no sessions exist yet.
+			if strings.Contains(err.Error(), "no server running") {
+				return "No tmux sessions.", nil
+			}
+			return nil, err
+		}
+
+		table := NewTableResult("tmux sessions", []string{"Session", "Windows", "Attached"})
+		count := 0
+		for _, line := range strings.Split(out, "\n") {
+			parts := strings.Split(line, "|")
+			if len(parts) < 4 || !strings.HasPrefix(parts[0], tmuxSessionPrefix) {
+				continue
+			}
+			attached := "no"
+			if parts[2] != "0" {
+				attached = "yes"
+			}
+			table.AddRow(parts[0], parts[3], attached)
+			count++
+		}
+		if count == 0 {
+			return "No agent-managed tmux sessions.", nil
+		}
+		return table, nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "tmux_send",
+			Description: "Send keystrokes to a tmux session (types into the interactive process). By default an Enter keypress is appended; set enter=false for partial input or control sequences like 'C-c'.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"session": map[string]any{"type": "string", "description": "Target session name"},
+					"keys":    map[string]any{"type": "string", "description": "Text or key names to send (tmux key syntax: 'C-c', 'Escape', plain text)"},
+					"literal": map[string]any{"type": "boolean", "description": "Send as literal text instead of interpreting key names (default: true for plain text safety)"},
+					"enter":   map[string]any{"type": "boolean", "description": "Append an Enter keypress after the keys (default: true)"},
+				},
+				"required": []string{"session", "keys"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		session, _ := args["session"].(string)
+		keys, _ := args["keys"].(string)
+		if session == "" || keys == "" {
+			return nil, fmt.Errorf("session and keys are required")
+		}
+		literal := true
+		if v, ok := args["literal"].(bool); ok {
+			literal = v
+		}
+		enter := true
+		if v, ok := args["enter"].(bool); ok {
+			enter = v
+		}
+
+		name := tmuxSessionName(session)
+		sendArgs := []string{"send-keys", "-t", name}
+		if literal {
+			sendArgs = append(sendArgs, "-l")
+		}
+		sendArgs = append(sendArgs, keys)
+		if _, err := runTmux(sendArgs...); err != nil {
+			return nil, err
+		}
+		if enter {
+			if _, err := runTmux("send-keys", "-t", name, "Enter"); err != nil {
+				return nil, err
+			}
+		}
+		return fmt.Sprintf("Sent to %q. Use tmux_capture to read the result.", name), nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "tmux_capture",
+			Description: "Capture the contents of a tmux session's pane, including scrollback. Returns up to 200 lines per call; page older output with start_line (negative = lines into scrollback).",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"session":    map[string]any{"type": "string", "description": "Target session name"},
+					"lines":      map[string]any{"type": "integer", "description": "Number of lines to return, counted from the end (default: 100, max: 200)"},
+					"start_line": map[string]any{"type": "integer", "description": "First line to capture; negative values reach into scrollback (e.g. -1000). Overrides lines."},
+				},
+				"required": []string{"session"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		session, _ := args["session"].(string)
+		if session == "" {
+			return nil, fmt.Errorf("session is required")
+		}
+		n := 100
+		if v, ok := args["lines"].(float64); ok && v > 0 {
+			n = int(v)
+		}
+		if n > tmuxCaptureMaxLines {
+			n = tmuxCaptureMaxLines
+		}
+
+		name := tmuxSessionName(session)
+		captureArgs := []string{"capture-pane", "-p", "-t", name}
+		if v, ok := args["start_line"].(float64); ok {
+			captureArgs = append(captureArgs,
+				"-S", strconv.Itoa(int(v)),
+				"-E", strconv.Itoa(int(v)+tmuxCaptureMaxLines-1))
+		}
+		out, err := runTmux(captureArgs...)
+		if err != nil {
+			return nil, err
+		}
+
+		lines := strings.Split(out, "\n")
+		truncated := false
+		if _, paged := args["start_line"].(float64); !paged && len(lines) > n {
+			lines = lines[len(lines)-n:]
+			truncated = true
+		}
+		result := strings.TrimRight(strings.Join(lines, "\n"), " \n")
+		if result == "" {
+			return "(pane is empty)", nil
+		}
+		if truncated {
+			result += fmt.Sprintf("\n\n[showing last %d lines — use start_line to page into scrollback]", n)
+		}
+		return result, nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "tmux_kill",
+			Description: "Kill a tmux session and the process running inside it.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"session": map[string]any{"type": "string", "description": "Session name to kill"},
+				},
+				"required": []string{"session"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		session, _ := args["session"].(string)
+		if session == "" {
+			return nil, fmt.Errorf("session is required")
+		}
+		name := tmuxSessionName(session)
+		if _, err := runTmux("kill-session", "-t", name); err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf("tmux session %q killed.", name), nil
+	})
+}
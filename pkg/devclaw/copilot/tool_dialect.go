@@ -0,0 +1,120 @@
+// Package copilot – tool_dialect.go adjusts tool parameter schemas for
+// provider-specific JSON Schema dialects before they are sent. OpenAI strict
+// mode wants additionalProperties pinned to false; Anthropic's input_schema
+// requires an object type with a properties key; Gemini function declarations
+// reject several standard JSON Schema keywords outright. Definitions are
+// adapted on a copy — the registered catalog is never mutated.
+package copilot
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Schema dialects understood by adaptToolsForDialect.
+const (
+	dialectOpenAI    = "openai"
+	dialectAnthropic = "anthropic"
+	dialectGemini    = "gemini"
+)
+
+// geminiUnsupportedKeys are JSON Schema keywords the Gemini function calling
+// API rejects; they are stripped recursively from the schema.
+var geminiUnsupportedKeys = []string{"additionalProperties", "$schema", "default", "examples"}
+
+// toolDialect returns the schema dialect for the given model on this client.
+func (c *LLMClient) toolDialect(model string) string {
+	if c.isAnthropicAPI() {
+		return dialectAnthropic
+	}
+	if c.provider == "google" || strings.HasPrefix(strings.ToLower(model), "gemini-") {
+		return dialectGemini
+	}
+	return dialectOpenAI
+}
+
+// adaptToolsForDialect returns tool definitions with parameter schemas
+// adjusted for the provider's dialect. Definitions whose schemas need no
+// change are passed through untouched.
+func adaptToolsForDialect(dialect string, tools []ToolDefinition) []ToolDefinition {
+	if len(tools) == 0 {
+		return tools
+	}
+	out := make([]ToolDefinition, len(tools))
+	for i, tool := range tools {
+		out[i] = tool
+		if adapted, changed := adaptSchemaForDialect(dialect, tool.Function.Parameters); changed {
+			out[i].Function.Parameters = adapted
+		}
+	}
+	return out
+}
+
+// adaptSchemaForDialect applies dialect rules to one parameter schema.
+// Returns the adapted schema and whether anything changed.
+func adaptSchemaForDialect(dialect string, params json.RawMessage) (json.RawMessage, bool) {
+	var schema map[string]any
+	if err := json.Unmarshal(params, &schema); err != nil || schema == nil {
+		return params, false
+	}
+
+	changed := false
+	switch dialect {
+	case dialectOpenAI:
+		// Strict mode requires additionalProperties: false on object schemas.
+		if schema["type"] == "object" || schema["type"] == nil {
+			if _, ok := schema["additionalProperties"]; !ok {
+				schema["additionalProperties"] = false
+				changed = true
+			}
+		}
+	case dialectAnthropic:
+		// input_schema must be an object type with a properties key.
+		if schema["type"] == nil {
+			schema["type"] = "object"
+			changed = true
+		}
+		if _, ok := schema["properties"]; !ok && schema["type"] == "object" {
+			schema["properties"] = map[string]any{}
+			changed = true
+		}
+	case dialectGemini:
+		changed = stripSchemaKeys(schema, geminiUnsupportedKeys)
+	}
+
+	if !changed {
+		return params, false
+	}
+	adapted, err := json.Marshal(schema)
+	if err != nil {
+		return params, false
+	}
+	return adapted, true
+}
+
+// stripSchemaKeys removes the given keys from a schema map recursively,
+// descending into nested objects and arrays. Returns whether keys were removed.
+func stripSchemaKeys(node any, keys []string) bool {
+	changed := false
+	switch v := node.(type) {
+	case map[string]any:
+		for _, key := range keys {
+			if _, ok := v[key]; ok {
+				delete(v, key)
+				changed = true
+			}
+		}
+		for _, child := range v {
+			if stripSchemaKeys(child, keys) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if stripSchemaKeys(child, keys) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
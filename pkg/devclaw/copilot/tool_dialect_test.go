@@ -0,0 +1,91 @@
+package copilot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAdaptToolsForDialectOpenAI(t *testing.T) {
+	tools := []ToolDefinition{
+		MakeToolDefinition("probe", "test", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{"type": "string"},
+			},
+		}),
+	}
+
+	adapted := adaptToolsForDialect(dialectOpenAI, tools)
+	var schema map[string]any
+	if err := json.Unmarshal(adapted[0].Function.Parameters, &schema); err != nil {
+		t.Fatalf("unmarshal adapted schema: %v", err)
+	}
+	if schema["additionalProperties"] != false {
+		t.Errorf("expected additionalProperties: false for OpenAI strict mode, got %v", schema["additionalProperties"])
+	}
+	// The original definition is untouched.
+	if strings.Contains(string(tools[0].Function.Parameters), "additionalProperties") {
+		t.Error("original schema must not be mutated")
+	}
+}
+
+func TestAdaptToolsForDialectAnthropic(t *testing.T) {
+	tools := []ToolDefinition{
+		{Type: "function", Function: FunctionDef{Name: "bare", Parameters: json.RawMessage(`{}`)}},
+	}
+
+	adapted := adaptToolsForDialect(dialectAnthropic, tools)
+	var schema map[string]any
+	if err := json.Unmarshal(adapted[0].Function.Parameters, &schema); err != nil {
+		t.Fatalf("unmarshal adapted schema: %v", err)
+	}
+	if schema["type"] != "object" {
+		t.Errorf("expected type object for Anthropic input_schema, got %v", schema["type"])
+	}
+	if _, ok := schema["properties"]; !ok {
+		t.Error("expected properties key for Anthropic input_schema")
+	}
+}
+
+func TestAdaptToolsForDialectGemini(t *testing.T) {
+	tools := []ToolDefinition{
+		MakeToolDefinition("probe", "test", map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"mode": map[string]any{
+					"type":    "string",
+					"default": "auto",
+				},
+			},
+		}),
+	}
+
+	adapted := adaptToolsForDialect(dialectGemini, tools)
+	s := string(adapted[0].Function.Parameters)
+	if strings.Contains(s, "additionalProperties") {
+		t.Errorf("Gemini schema should not contain additionalProperties: %s", s)
+	}
+	if strings.Contains(s, "default") {
+		t.Errorf("Gemini schema should strip nested default keys: %s", s)
+	}
+	if !strings.Contains(s, "mode") {
+		t.Errorf("Gemini cleanup must keep valid properties: %s", s)
+	}
+}
+
+func TestAdaptToolsForDialectPassthrough(t *testing.T) {
+	// A schema that already satisfies the dialect passes through unchanged.
+	raw := json.RawMessage(`{"type":"object","properties":{},"additionalProperties":false}`)
+	tools := []ToolDefinition{{Type: "function", Function: FunctionDef{Name: "ok", Parameters: raw}}}
+
+	adapted := adaptToolsForDialect(dialectOpenAI, tools)
+	if string(adapted[0].Function.Parameters) != string(raw) {
+		t.Errorf("conforming schema should pass through unchanged: %s", adapted[0].Function.Parameters)
+	}
+
+	if got := adaptToolsForDialect(dialectOpenAI, nil); got != nil {
+		t.Errorf("nil tools should pass through, got %v", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -45,6 +46,11 @@ type ctxKeyVaultReader struct{}
 type DeliveryTarget struct {
 	Channel string
 	ChatID  string
+
+	// MessageID is the ID of the incoming message that triggered this run
+	// (empty for scheduler/heartbeat runs). Used by tools like react that
+	// target the triggering message.
+	MessageID string
 }
 
 // ContextWithSession returns a new context carrying the given session ID.
@@ -61,6 +67,16 @@ func ContextWithDelivery(ctx context.Context, channel, chatID string) context.Co
 	})
 }
 
+// ContextWithDeliveryMessage is like ContextWithDelivery but also carries the
+// ID of the incoming message that triggered the run.
+func ContextWithDeliveryMessage(ctx context.Context, channel, chatID, messageID string) context.Context {
+	return context.WithValue(ctx, ctxKeyDeliveryTarget{}, DeliveryTarget{
+		Channel:   channel,
+		ChatID:    chatID,
+		MessageID: messageID,
+	})
+}
+
 // ContextWithCaller returns a new context carrying the caller's access level and JID.
 // This replaces the global SetCallerContext/SetSessionContext pattern, making
 // tool security checks goroutine-safe (context per request).
@@ -185,6 +201,14 @@ type ToolResult struct {
 	ForUser  string // Friendly message to show user immediately
 	IsAsync  bool   // Tool is running in background, result comes later
 	IsSilent bool   // Don't notify user about this result
+
+	// Structured holds the typed payload when the tool returned a
+	// *StructuredResult. Channels can render it richly (tables, cards)
+	// instead of the compact text in Content. Nil for plain results.
+	Structured *StructuredResult
+
+	// DurationMs is how long the tool took to execute (for run transcripts).
+	DurationMs int64
 }
 
 // DualToolResult creates a ToolResult with separate content for LLM and user.
@@ -407,6 +431,13 @@ type ToolExecutor struct {
 	guard       *ToolGuard
 	mu          sync.RWMutex
 
+	// perToolTimeout overrides the timeout for specific tools by name
+	// (configured via per_tool_timeout_seconds).
+	perToolTimeout map[string]time.Duration
+
+	// maxTimeout is a global ceiling applied after all overrides (0 = none).
+	maxTimeout time.Duration
+
 	// vault is the optional vault reader for checking skill setup
 	vault skills.VaultReader
 
@@ -434,13 +465,54 @@ type ToolExecutor struct {
 	// If nil, tools requiring confirmation are denied.
 	confirmationRequester func(sessionID, callerJID, toolName string, args map[string]any) (approved bool, err error)
 
+	// sandboxFS jails file-tool paths per session for non-owner callers.
+	// Nil = sandboxing disabled.
+	sandboxFS *SandboxFS
+
 	// hooks holds registered before/after tool execution hooks.
 	hooks []*ToolHook
 
+	// schemaViolations counts tool calls whose arguments failed schema
+	// validation, keyed by tool name. See ValidateToolCall.
+	schemaViolations map[string]int
+
 	// abortCh is closed when an abort is requested, signaling all running
 	// tools to stop as soon as possible. Each run creates a fresh channel.
 	abortCh   chan struct{}
 	abortOnce sync.Once
+
+	// runningMu guards the in-flight tool tracking below, used by the
+	// session watchdog to report which tool hung.
+	runningMu    sync.Mutex
+	runningSeq   int
+	runningTools map[int]RunningToolInfo
+
+	// durStats accumulates per-tool execution timings since process start,
+	// surfaced by the /perf diagnostics command (guarded by runningMu).
+	durStats map[string]*toolDurStat
+}
+
+// RunningToolInfo describes one tool call currently executing.
+type RunningToolInfo struct {
+	Name      string
+	StartedAt time.Time
+}
+
+// toolDurStat accumulates execution timings for one tool.
+type toolDurStat struct {
+	count   int64
+	errors  int64
+	totalMs int64
+	maxMs   int64
+}
+
+// ToolDurationStat is a per-tool timing summary for diagnostics.
+type ToolDurationStat struct {
+	Name   string
+	Count  int64
+	Errors int64
+	AvgMs  int64
+	MaxMs  int64
 }
 
 // NewToolExecutor creates a new empty tool executor.
@@ -454,9 +526,84 @@ func NewToolExecutor(logger *slog.Logger) *ToolExecutor {
 		parallel:     true,
 		maxParallel:  5,
 		abortCh:      make(chan struct{}),
+		runningTools: make(map[int]RunningToolInfo),
 	}
 }
 
+// trackToolStart registers an in-flight tool call and returns its tracking ID.
+func (e *ToolExecutor) trackToolStart(name string) int {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	if e.runningTools == nil {
+		e.runningTools = make(map[int]RunningToolInfo)
+	}
+	e.runningSeq++
+	e.runningTools[e.runningSeq] = RunningToolInfo{Name: name, StartedAt: time.Now()}
+	return e.runningSeq
+}
+
+// trackToolEnd removes a completed tool call from the in-flight set.
+func (e *ToolExecutor) trackToolEnd(id int) {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	delete(e.runningTools, id)
+}
+
+// RunningTools returns a snapshot of tool calls currently executing, oldest
+// first. Used by the session watchdog for recovery diagnostics.
+func (e *ToolExecutor) RunningTools() []RunningToolInfo {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	out := make([]RunningToolInfo, 0, len(e.runningTools))
+	for _, info := range e.runningTools {
+		out = append(out, info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out
+}
+
+// recordToolDuration folds one completed tool call into the duration stats.
+func (e *ToolExecutor) recordToolDuration(name string, d time.Duration, failed bool) {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	if e.durStats == nil {
+		e.durStats = make(map[string]*toolDurStat)
+	}
+	s := e.durStats[name]
+	if s == nil {
+		s = &toolDurStat{}
+		e.durStats[name] = s
+	}
+	ms := d.Milliseconds()
+	s.count++
+	s.totalMs += ms
+	if ms > s.maxMs {
+		s.maxMs = ms
+	}
+	if failed {
+		s.errors++
+	}
+}
+
+// ToolDurationStats returns per-tool timing summaries since process start,
+// slowest average first. Used by the /perf diagnostics command.
+func (e *ToolExecutor) ToolDurationStats() []ToolDurationStat {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	out := make([]ToolDurationStat, 0, len(e.durStats))
+	for name, s := range e.durStats {
+		out = append(out, ToolDurationStat{
+			Name:   name,
+			Count:  s.count,
+			Errors: s.errors,
+			AvgMs:  s.totalMs / s.count,
+			MaxMs:  s.maxMs,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AvgMs > out[j].AvgMs })
+	return out
+}
+
 // ResetAbort creates a fresh abort channel for a new run.
 func (e *ToolExecutor) ResetAbort() {
 	e.mu.Lock()
@@ -568,6 +715,34 @@ func (e *ToolExecutor) SetConfirmationRequester(fn func(sessionID, callerJID, to
 	e.confirmationRequester = fn
 }
 
+// SetSandboxFS configures per-session file jails for non-owner callers.
+func (e *ToolExecutor) SetSandboxFS(fs *SandboxFS) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sandboxFS = fs
+}
+
+// ResolveToolPath resolves a file-tool path, confining it to the caller's
+// session scratch directory when sandboxing is enabled. Owners always operate
+// on the real filesystem.
+func (e *ToolExecutor) ResolveToolPath(ctx context.Context, path string) string {
+	e.mu.RLock()
+	fs := e.sandboxFS
+	e.mu.RUnlock()
+
+	if fs == nil || !fs.Enabled() {
+		return resolvePath(path)
+	}
+	if CallerLevelFromContext(ctx) == AccessOwner {
+		return resolvePath(path)
+	}
+	sessionID := SessionIDFromContext(ctx)
+	if sessionID == "" {
+		return resolvePath(path)
+	}
+	return fs.Resolve(sessionID, path)
+}
+
 // Configure applies ToolExecutorConfig (parallel, max_parallel, timeouts).
 func (e *ToolExecutor) Configure(cfg ToolExecutorConfig) {
 	e.mu.Lock()
@@ -583,6 +758,45 @@ func (e *ToolExecutor) Configure(cfg ToolExecutorConfig) {
 	if cfg.BashTimeoutSeconds > 0 {
 		e.bashTimeout = time.Duration(cfg.BashTimeoutSeconds) * time.Second
 	}
+	if len(cfg.PerToolTimeoutSeconds) > 0 {
+		e.perToolTimeout = make(map[string]time.Duration, len(cfg.PerToolTimeoutSeconds))
+		for name, secs := range cfg.PerToolTimeoutSeconds {
+			if secs > 0 {
+				e.perToolTimeout[name] = time.Duration(secs) * time.Second
+			}
+		}
+	}
+	if cfg.MaxTimeoutSeconds > 0 {
+		e.maxTimeout = time.Duration(cfg.MaxTimeoutSeconds) * time.Second
+	}
+}
+
+// toolTimeout resolves the execution timeout for one tool: per-tool override,
+// then the bash/claude-code classes, then the default — clamped to the global
+// ceiling when one is configured.
+func (e *ToolExecutor) toolTimeout(name string) time.Duration {
+	e.mu.RLock()
+	override, hasOverride := e.perToolTimeout[name]
+	timeout := e.timeout
+	bashTimeout := e.bashTimeout
+	ceiling := e.maxTimeout
+	e.mu.RUnlock()
+
+	switch {
+	case hasOverride:
+		timeout = override
+	case name == "bash" || name == "ssh" || name == "scp" || name == "exec":
+		timeout = bashTimeout
+	case name == "claude-code_execute":
+		// Claude Code manages its own internal timeout (default 15min);
+		// give the executor wrapper enough headroom.
+		timeout = 20 * time.Minute
+	}
+
+	if ceiling > 0 && timeout > ceiling {
+		timeout = ceiling
+	}
+	return timeout
 }
 
 // Register adds a tool with its definition and handler.
@@ -804,7 +1018,9 @@ func (e *ToolExecutor) hasSequentialTool(calls []ToolCall) bool {
 func (e *ToolExecutor) executeSequential(ctx context.Context, calls []ToolCall) []ToolResult {
 	results := make([]ToolResult, len(calls))
 	for i, call := range calls {
+		start := time.Now()
 		results[i] = e.executeSingle(ctx, call)
+		results[i].DurationMs = time.Since(start).Milliseconds()
 	}
 	return results
 }
@@ -820,7 +1036,9 @@ func (e *ToolExecutor) executeParallel(ctx context.Context, calls []ToolCall, ma
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
+			start := time.Now()
 			results[idx] = e.executeSingle(ctx, tc)
+			results[idx].DurationMs = time.Since(start).Milliseconds()
 		}(i, call)
 	}
 
@@ -966,6 +1184,10 @@ func (e *ToolExecutor) executeSingle(ctx context.Context, call ToolCall) ToolRes
 				return
 			}
 
+			// Typed payloads go to the user in their rich rendered form.
+			if sr, ok := output.(*StructuredResult); ok && sr != nil {
+				output = sr.RenderMarkdown()
+			}
 			outputStr := formatToolOutput(output)
 			e.logger.Info("async tool executed", "tool", name, "output_len", len(outputStr))
 			if guard != nil {
@@ -985,20 +1207,25 @@ func (e *ToolExecutor) executeSingle(ctx context.Context, call ToolCall) ToolRes
 		return result
 	}
 
-	// Execute with timeout.
-	timeout := e.timeout
-	// Give bash/ssh/scp longer timeouts (configurable via bash_timeout_seconds).
-	if name == "bash" || name == "ssh" || name == "scp" || name == "exec" {
-		timeout = e.bashTimeout
-	}
-	// Claude Code manages its own internal timeout (default 15min);
-	// give the executor wrapper enough headroom.
-	if name == "claude-code_execute" {
-		timeout = 20 * time.Minute
-	}
+	// Execute with timeout (per-tool override → tool class → default,
+	// clamped to the global ceiling).
+	timeout := e.toolTimeout(name)
 
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 
+	// Cooperative cancellation: /stop closes the abort channel; cancel the
+	// tool's context so long-running handlers unwind instead of running to
+	// completion with the result thrown away.
+	abortWatch := make(chan struct{})
+	go func() {
+		select {
+		case <-e.AbortCh():
+			cancel()
+		case <-abortWatch:
+		}
+	}()
+	defer close(abortWatch)
+
 	// Propagate ProgressSender to the tool context so long-running tools
 	// can send intermediate feedback to the user.
 	if ps := ProgressSenderFromContext(ctx); ps != nil {
@@ -1043,15 +1270,24 @@ func (e *ToolExecutor) executeSingle(ctx context.Context, call ToolCall) ToolRes
 	progressDone := make(chan struct{})
 
 	start := time.Now()
+	trackID := e.trackToolStart(name)
 	output, err := tool.Handler(execCtx, args)
+	e.trackToolEnd(trackID)
 	close(progressDone)
 	duration := time.Since(start)
+	e.recordToolDuration(name, duration, err != nil)
 
 	// ── After-tool hooks ──
 	resultStr := ""
 	if err != nil {
 		resultStr = fmt.Sprintf("Error: %v", err)
 	} else {
+		// Typed payloads: compact text for the LLM, rich render for channels.
+		if sr, ok := output.(*StructuredResult); ok && sr != nil {
+			result.Structured = sr
+			result.ForUser = sr.RenderMarkdown()
+			output = sr.CompactText()
+		}
 		resultStr = formatToolOutput(output)
 	}
 	for _, hook := range hooks {
@@ -1065,6 +1301,16 @@ func (e *ToolExecutor) executeSingle(ctx context.Context, call ToolCall) ToolRes
 		// This makes tool errors parseable by the LLM for better retry logic.
 		result.Content = formatToolError(name, err)
 		result.Error = err
+		// Interrupted tools (abort or timeout) may still have produced useful
+		// partial output — return it so the model knows how far the tool got.
+		if execCtx.Err() != nil && output != nil {
+			if partial := formatToolOutput(output); partial != "" && partial != "OK" {
+				if len(partial) > HardMaxToolResultChars/2 {
+					partial = partial[:HardMaxToolResultChars/2] + "\n... [partial output truncated]"
+				}
+				result.Content += "\n\nPartial output before interruption:\n" + partial
+			}
+		}
 		e.logger.Warn("tool execution failed",
 			"name", name,
 			"error", err,
@@ -1301,6 +1547,14 @@ func formatApprovalSummary(toolName string, args map[string]any) string {
 		src, _ := args["source"].(string)
 		dst, _ := args["destination"].(string)
 		return fmt.Sprintf("scp %s → %s", src, dst)
+	case "db_execute", "db_hub_execute", "db_hub_raw":
+		stmt, _ := args["statement"].(string)
+		if stmt == "" {
+			stmt, _ = args["query"].(string)
+		}
+		if stmt != "" {
+			return toolName + ": " + sanitizeForMarkdown(truncateForApproval(stmt, 80))
+		}
 	}
 	return toolName
 }
@@ -353,9 +353,9 @@ func TestToolResultDual(t *testing.T) {
 
 	t.Run("GetForUser returns ForUser when set", func(t *testing.T) {
 		result := &ToolResult{
-			Content:  "technical",
-			ForLLM:   "technical",
-			ForUser:  "friendly message",
+			Content: "technical",
+			ForLLM:  "technical",
+			ForUser: "friendly message",
 		}
 
 		if result.GetForUser() != "friendly message" {
@@ -560,7 +560,7 @@ func TestRunAsync(t *testing.T) {
 
 	t.Run("uses default timeout when not specified", func(t *testing.T) {
 		config := AsyncToolConfig{
-			Label:     "test task",
+			Label:      "test task",
 			OnComplete: func(result *ToolResult) {},
 		}
 
@@ -573,9 +573,9 @@ func TestRunAsync(t *testing.T) {
 	t.Run("callback can be nil", func(t *testing.T) {
 		// This should not panic
 		config := AsyncToolConfig{
-			Label:     "test task",
+			Label:      "test task",
 			OnComplete: nil,
-			Timeout:   100 * time.Millisecond,
+			Timeout:    100 * time.Millisecond,
 		}
 
 		RunAsync(context.Background(), config, func(ctx context.Context) *ToolResult {
@@ -840,3 +840,80 @@ func TestToolHook(t *testing.T) {
 		}
 	})
 }
+
+func TestToolTimeoutResolution(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	exec := NewToolExecutor(logger)
+	exec.Configure(ToolExecutorConfig{
+		Parallel:              true,
+		BashTimeoutSeconds:    300,
+		DefaultTimeoutSeconds: 30,
+		PerToolTimeoutSeconds: map[string]int{"web_search": 90, "bash": 600},
+		MaxTimeoutSeconds:     480,
+	})
+
+	tests := []struct {
+		tool string
+		want time.Duration
+	}{
+		{"get_time", 30 * time.Second},             // default
+		{"ssh", 300 * time.Second},                 // bash class
+		{"web_search", 90 * time.Second},           // per-tool override
+		{"bash", 480 * time.Second},                // override clamped to ceiling
+		{"claude-code_execute", 480 * time.Second}, // class clamped to ceiling
+	}
+	for _, tt := range tests {
+		if got := exec.toolTimeout(tt.tool); got != tt.want {
+			t.Errorf("toolTimeout(%q) = %v, want %v", tt.tool, got, tt.want)
+		}
+	}
+
+	// No ceiling: class timeouts apply unclamped.
+	exec2 := NewToolExecutor(logger)
+	if got := exec2.toolTimeout("claude-code_execute"); got != 20*time.Minute {
+		t.Errorf("toolTimeout(claude-code_execute) = %v, want 20m", got)
+	}
+}
+
+func TestAbortCancelsRunningTool(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	exec := NewToolExecutor(logger)
+
+	started := make(chan struct{})
+	exec.Register(ToolDefinition{
+		Type:     "function",
+		Function: FunctionDef{Name: "slow_tool"},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			// Cooperative handler: return partial output with the error.
+			return "partial progress", ctx.Err()
+		case <-time.After(10 * time.Second):
+			return "done", nil
+		}
+	})
+
+	done := make(chan ToolResult, 1)
+	go func() {
+		done <- exec.executeSingle(context.Background(), ToolCall{
+			ID:       "call1",
+			Function: FunctionCall{Name: "slow_tool", Arguments: "{}"},
+		})
+	}()
+
+	<-started
+	exec.Abort()
+
+	select {
+	case result := <-done:
+		if result.Error == nil {
+			t.Fatal("expected error from aborted tool")
+		}
+		if !strings.Contains(result.Content, "partial progress") {
+			t.Errorf("expected partial output in result, got: %q", result.Content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("aborted tool did not return within 5s")
+	}
+}
@@ -107,10 +107,17 @@ func DefaultToolGuardConfig() ToolGuardConfig {
 		ToolPermissions: map[string]string{
 			// System tools with machine access.
 			"bash":         "owner",
+			"shell":        "owner",
 			"ssh":          "owner",
 			"scp":          "owner",
 			"exec":         "admin",
 			"set_env":      "owner",
+			// tmux sessions type into arbitrary interactive processes.
+			"tmux_create":  "owner",
+			"tmux_send":    "owner",
+			"tmux_capture": "owner",
+			"tmux_kill":    "owner",
+			"tmux_list":    "admin",
 			// File tools.
 			"write_file":   "admin",
 			"edit_file":    "admin",
@@ -133,9 +140,59 @@ func DefaultToolGuardConfig() ToolGuardConfig {
 			"cron_add":    "admin",
 			"cron_list":   "user",
 			"cron_remove": "admin",
+			// Event triggers.
+			"trigger_add":    "admin",
+			"trigger_list":   "user",
+			"trigger_remove": "admin",
 			// Web.
 			"web_search": "user",
 			"web_fetch":  "user",
+			// Page change monitor.
+			"watch_url":        "admin",
+			"url_watch_check":  "user",
+			"url_watch_list":   "user",
+			"url_watch_remove": "admin",
+			// Host notifications.
+			"notify_desktop": "user",
+			// Smart home.
+			"ha_get_state":    "user",
+			"ha_call_service": "admin",
+			// Media players.
+			"spotify_search": "user",
+			"spotify_play":   "user",
+			"spotify_pause":  "user",
+			"spotify_queue":  "user",
+			"media_control":  "user",
+			// Weather skill.
+			"weather":              "user",
+			"weather_set_location": "user",
+			// CRM skill.
+			"contact_add":      "user",
+			"contact_note":     "user",
+			"contact_show":     "user",
+			"contact_followup": "user",
+			"contact_remove":   "user",
+			// Deterministic math.
+			"calc":             "user",
+			"convert_unit":     "user",
+			"convert_currency": "user",
+			// Shared lists skill.
+			"list_add":    "user",
+			"list_check":  "user",
+			"list_remove": "user",
+			"list_show":   "user",
+			// Habits skill.
+			"habit_add":    "user",
+			"habit_log":    "user",
+			"habit_list":   "user",
+			"habit_snooze": "user",
+			"habit_remove": "user",
+			"habit_report": "user",
+			// Finance skill. CSV import reads arbitrary host files.
+			"expense_add":        "user",
+			"expense_report":     "user",
+			"budget_set":         "user",
+			"expense_import_csv": "admin",
 		},
 	}
 }
@@ -150,10 +207,11 @@ var ToolGroups = map[string][]string{
 	"group:memory":    {"memory"},
 	"group:web":       {"web_search", "web_fetch"},
 	"group:fs":        {"read_file", "write_file", "edit_file", "list_files", "search_files", "glob_files"},
-	"group:runtime":   {"bash", "exec", "ssh", "scp", "set_env"},
+	"group:runtime":   {"bash", "shell", "exec", "ssh", "scp", "set_env", "tmux_create", "tmux_list", "tmux_send", "tmux_capture", "tmux_kill"},
 	"group:subagents": {"spawn_subagent", "list_subagents", "wait_subagent", "stop_subagent"},
 	"group:skills":    {"install_skill", "remove_skill", "search_skills", "list_skills", "test_skill", "edit_skill", "add_script", "init_skill", "skill_defaults_list", "skill_defaults_install"},
 	"group:scheduler": {"cron_add", "cron_list", "cron_remove"},
+	"group:triggers":  {"trigger_add", "trigger_list", "trigger_remove"},
 	"group:vault":     {"vault_save", "vault_get", "vault_list", "vault_delete"},
 	"group:media":     {"describe_image", "transcribe_audio", "image-gen_generate_image"},
 	"group:teams": {
@@ -371,12 +429,25 @@ func (g *ToolGuard) Check(toolName string, callerLevel AccessLevel, args map[str
 				break
 			}
 		}
-		if requiresConfirmation && (toolName == "bash" || toolName == "exec") {
+		if requiresConfirmation && (toolName == "bash" || toolName == "shell" || toolName == "exec") {
 			command, _ := args["command"].(string)
 			if isReadOnlyCommand(command) {
 				requiresConfirmation = false
 			}
 		}
+		// apply_patch sandboxes every path to its working directory, so only
+		// patches whose working_dir points outside the project root need a
+		// confirmation prompt.
+		if requiresConfirmation && toolName == "apply_patch" {
+			if applyPatchIsLocal(args) {
+				requiresConfirmation = false
+			}
+		}
+		// Database writes always go through the approval flow, even when the
+		// tool isn't listed in require_confirmation — mutations can't be undone.
+		if !requiresConfirmation && isDBWriteCall(toolName, args) {
+			requiresConfirmation = true
+		}
 	}
 
 	// 1. Check tool-level permission.
@@ -385,8 +456,8 @@ func (g *ToolGuard) Check(toolName string, callerLevel AccessLevel, args map[str
 		return permResult
 	}
 
-	// 2. For bash/exec, check command safety.
-	if toolName == "bash" || toolName == "exec" {
+	// 2. For bash/shell/exec, check command safety.
+	if toolName == "bash" || toolName == "shell" || toolName == "exec" {
 		command, _ := args["command"].(string)
 		if result := g.checkCommandSafety(command, callerLevel); !result.Allowed {
 			return result
@@ -840,6 +911,44 @@ func isReadOnlyCommand(command string) bool {
 	return true
 }
 
+// applyPatchIsLocal returns true if an apply_patch call only touches files
+// under the current working directory (the project root). Individual patch
+// paths cannot escape working_dir, so only the working_dir argument itself
+// can direct the patch elsewhere.
+func applyPatchIsLocal(args map[string]any) bool {
+	wd, _ := args["working_dir"].(string)
+	if wd == "" || wd == "." {
+		return true
+	}
+	if !filepath.IsAbs(wd) {
+		// Relative working_dir resolves against the process cwd; it is local
+		// unless it climbs out of it.
+		return !strings.HasPrefix(filepath.Clean(wd), "..")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(cwd, wd)
+	if err != nil {
+		return false
+	}
+	return !strings.HasPrefix(rel, "..")
+}
+
+// isDBWriteCall reports whether a tool call writes to a database. Dedicated
+// execute tools always count; db_hub_raw counts only when the query mutates.
+func isDBWriteCall(toolName string, args map[string]any) bool {
+	switch toolName {
+	case "db_execute", "db_hub_execute":
+		return true
+	case "db_hub_raw":
+		query, _ := args["query"].(string)
+		return isMutatingQuery(query)
+	}
+	return false
+}
+
 // splitCommandChain splits a command string by &&, ;, and || operators.
 // Pipe (|) is NOT a split point because piped commands form a single pipeline.
 func splitCommandChain(cmd string) []string {
@@ -168,3 +168,25 @@ func TestToolGuard_UnknownToolUserLevel(t *testing.T) {
 		t.Error("unknown tool should default to user-level and be allowed for users")
 	}
 }
+
+func TestToolGuard_ApplyPatchConfirmationOnlyOutsideCwd(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultToolGuardConfig()
+	cfg.RequireConfirmation = []string{"apply_patch"}
+	g := newTestGuard(cfg)
+
+	// Patch confined to the project root skips the prompt.
+	r := g.Check("apply_patch", AccessAdmin, map[string]any{"input": "..."})
+	if r.Allowed && r.RequiresConfirmation {
+		t.Error("apply_patch in the working directory should not require confirmation")
+	}
+
+	// Patch targeting a directory outside the project root keeps it.
+	r = g.Check("apply_patch", AccessAdmin, map[string]any{
+		"input":       "...",
+		"working_dir": "/somewhere/else",
+	})
+	if r.Allowed && !r.RequiresConfirmation {
+		t.Error("apply_patch outside the working directory should require confirmation")
+	}
+}
@@ -24,6 +24,11 @@ type ToolProfile struct {
 	// Deny lists tools and groups that are always blocked.
 	// Takes precedence over Allow.
 	Deny []string `yaml:"deny"`
+
+	// BashIsolation runs the bash tool inside an isolation wrapper
+	// (bubblewrap/firejail/nsjail) for sessions using this profile.
+	// Nil = bash runs directly on the host.
+	BashIsolation *BashIsolationConfig `yaml:"bash_isolation,omitempty"`
 }
 
 // BuiltInProfiles provides predefined tool profiles for common use cases.
@@ -59,10 +64,12 @@ var BuiltInProfiles = map[string]ToolProfile{
 			"group:scheduler",
 			"group:vault",
 			"bash",
+			"shell",
 			"exec",
 			"git_*",
 			"docker_*",
 			"test_*",
+			"tmux_*",
 		},
 		Deny: []string{
 			"ssh",
@@ -114,6 +121,54 @@ var BuiltInProfiles = map[string]ToolProfile{
 			"edit_file",
 		},
 	},
+	"guest": {
+		Name:        "guest",
+		Description: "Read-only demo access - no exec, no file writes, no vault",
+		Allow: []string{
+			"group:web",
+			"read_file",
+			"list_files",
+			"search_files",
+			"glob_files",
+			"list_capabilities",
+		},
+		Deny: []string{
+			"group:runtime",
+			"group:memory",
+			"group:scheduler",
+			"group:vault",
+			"group:skills",
+			"group:subagents",
+			"group:teams",
+			"write_file",
+			"edit_file",
+		},
+	},
+	"trial": {
+		Name:        "trial",
+		Description: "Newly paired contacts - chat, web, memory and scheduler; no exec, no file writes, no integrations",
+		Allow: []string{
+			"group:web",
+			"group:memory",
+			"group:scheduler",
+			"read_file",
+			"list_files",
+			"search_files",
+			"glob_files",
+			"list_capabilities",
+		},
+		Deny: []string{
+			"group:runtime",
+			"group:vault",
+			"group:skills",
+			"group:subagents",
+			"group:teams",
+			"write_file",
+			"edit_file",
+			"ssh",
+			"scp",
+		},
+	},
 	"full": {
 		Name:        "full",
 		Description: "Full access - all tools available (respect permissions)",
@@ -309,15 +364,19 @@ func InferToolCategory(name string) string {
 
 	// Shell/execution
 	case name == "bash" ||
+		name == "shell" ||
 		name == "exec" ||
 		name == "ssh" ||
 		name == "scp" ||
-		name == "set_env":
+		name == "set_env" ||
+		strings.HasPrefix(name, "tmux_"):
 		return "Execution"
 
 	// Web operations
 	case strings.Contains(name, "web_") ||
-		strings.Contains(name, "fetch"):
+		strings.Contains(name, "fetch") ||
+		strings.HasPrefix(name, "url_watch") ||
+		name == "watch_url":
 		return "Web"
 
 	// Memory/knowledge
@@ -325,7 +384,8 @@ func InferToolCategory(name string) string {
 		return "Memory"
 
 	// Scheduling
-	case strings.HasPrefix(name, "cron_"):
+	case strings.HasPrefix(name, "cron_"),
+		strings.HasPrefix(name, "trigger_"):
 		return "Scheduling"
 
 	// Vault/secrets
@@ -374,9 +434,32 @@ func InferToolCategory(name string) string {
 	case strings.Contains(name, "image") ||
 		strings.Contains(name, "audio") ||
 		strings.Contains(name, "video") ||
-		strings.Contains(name, "transcribe"):
+		strings.Contains(name, "transcribe") ||
+		strings.HasPrefix(name, "spotify_") ||
+		name == "media_control":
 		return "Media"
 
+	// Smart home
+	case strings.HasPrefix(name, "ha_"):
+		return "Smart Home"
+
+	// Finance
+	case strings.HasPrefix(name, "expense_") ||
+		strings.HasPrefix(name, "budget_"):
+		return "Finance"
+
+	// Habits
+	case strings.HasPrefix(name, "habit_"):
+		return "Habits"
+
+	// Math
+	case name == "calc" || strings.HasPrefix(name, "convert_"):
+		return "Math"
+
+	// Contacts
+	case strings.HasPrefix(name, "contact_"):
+		return "Contacts"
+
 	// Capabilities
 	case name == "list_capabilities":
 		return "Capabilities"
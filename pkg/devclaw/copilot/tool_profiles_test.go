@@ -191,9 +191,9 @@ func TestListProfiles(t *testing.T) {
 
 	profiles := ListProfiles(customProfiles)
 
-	// Should have 5 built-in + 2 custom = 7 profiles.
-	if len(profiles) != 7 {
-		t.Errorf("expected 7 profiles, got %d: %v", len(profiles), profiles)
+	// Should have 7 built-in + 2 custom = 9 profiles.
+	if len(profiles) != 9 {
+		t.Errorf("expected 9 profiles, got %d: %v", len(profiles), profiles)
 	}
 
 	// Verify built-in profiles are included.
@@ -202,7 +202,7 @@ func TestListProfiles(t *testing.T) {
 		profileMap[p] = true
 	}
 
-	for _, builtIn := range []string{"minimal", "coding", "messaging", "team", "full"} {
+	for _, builtIn := range []string{"minimal", "coding", "messaging", "team", "guest", "trial", "full"} {
 		if !profileMap[builtIn] {
 			t.Errorf("expected built-in profile '%s' in list", builtIn)
 		}
@@ -0,0 +1,237 @@
+// Package copilot – tool_schema.go validates tool call arguments against the
+// tool's JSON parameter schema before execution. Malformed calls (bad JSON,
+// missing required fields, wrong types) normally waste a full agent turn on a
+// guaranteed failure; instead, the agent asks the LLM once for a corrected
+// arguments object and proceeds with the repaired call when the fix validates.
+// Schema violations are counted per tool for diagnostics.
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// argRepairTimeout bounds the single LLM call used to repair malformed args.
+const argRepairTimeout = 30 * time.Second
+
+// argRepairSystemPrompt asks the LLM to fix an invalid tool call.
+const argRepairSystemPrompt = `A tool call was generated with arguments that violate the tool's JSON schema. Produce a corrected arguments object that satisfies the schema while preserving the original intent. Output ONLY the corrected JSON object — no explanations, no markdown fences.`
+
+// toolParamSchema is the subset of JSON Schema emitted by MakeToolDefinition
+// that argument validation understands. Unknown schema constructs are ignored.
+type toolParamSchema struct {
+	Type                 string                    `json:"type"`
+	Properties           map[string]toolPropSchema `json:"properties"`
+	Required             []string                  `json:"required"`
+	AdditionalProperties *bool                     `json:"additionalProperties"`
+}
+
+// toolPropSchema describes a single parameter for validation purposes.
+type toolPropSchema struct {
+	Type string `json:"type"`
+	Enum []any  `json:"enum"`
+}
+
+// validateToolArgs checks a raw arguments JSON string against a tool's
+// parameter schema. Returns nil when the schema is empty or unparseable —
+// validation must never block a call the handler could have served.
+func validateToolArgs(params json.RawMessage, argsJSON string) error {
+	if len(params) == 0 {
+		return nil
+	}
+	var schema toolParamSchema
+	if err := json.Unmarshal(params, &schema); err != nil {
+		return nil
+	}
+	if schema.Type != "" && schema.Type != "object" {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(argsJSON)
+	if trimmed == "" {
+		trimmed = "{}"
+	}
+	var args map[string]any
+	if err := json.Unmarshal([]byte(trimmed), &args); err != nil {
+		return fmt.Errorf("arguments are not a valid JSON object: %v", err)
+	}
+
+	var violations []string
+	for _, req := range schema.Required {
+		if _, ok := args[req]; !ok {
+			violations = append(violations, fmt.Sprintf("missing required field %q", req))
+		}
+	}
+	for name, val := range args {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			// Only reject extras when the schema explicitly forbids them.
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties && len(schema.Properties) > 0 {
+				violations = append(violations, fmt.Sprintf("unknown field %q", name))
+			}
+			continue
+		}
+		if msg := checkPropValue(name, prop, val); msg != "" {
+			violations = append(violations, msg)
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("schema violations: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// checkPropValue validates one argument value against its property schema.
+// Returns "" when valid, or a human-readable violation description.
+func checkPropValue(name string, prop toolPropSchema, val any) string {
+	if val == nil {
+		// null is left to the handler — many tools treat it as "unset".
+		return ""
+	}
+
+	ok := true
+	switch prop.Type {
+	case "", "any":
+		// No type constraint.
+	case "string":
+		_, ok = val.(string)
+	case "boolean":
+		_, ok = val.(bool)
+	case "integer":
+		f, isNum := val.(float64)
+		ok = isNum && f == math.Trunc(f)
+	case "number":
+		_, ok = val.(float64)
+	case "array":
+		_, ok = val.([]any)
+	case "object":
+		_, ok = val.(map[string]any)
+	}
+	if !ok {
+		return fmt.Sprintf("field %q should be %s, got %T", name, prop.Type, val)
+	}
+
+	if len(prop.Enum) > 0 {
+		for _, allowed := range prop.Enum {
+			if allowed == val {
+				return ""
+			}
+		}
+		return fmt.Sprintf("field %q value %v is not one of %v", name, val, prop.Enum)
+	}
+	return ""
+}
+
+// ValidateToolCall checks a tool call's arguments against the registered
+// tool's parameter schema. Unknown tools return nil — executeSingle already
+// reports those. Every violation increments the per-tool counter exposed
+// via SchemaViolationStats.
+func (e *ToolExecutor) ValidateToolCall(call ToolCall) error {
+	name := call.Function.Name
+	e.mu.RLock()
+	tool, ok := e.tools[name]
+	e.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	err := validateToolArgs(tool.Definition.Function.Parameters, call.Function.Arguments)
+	if err != nil {
+		e.mu.Lock()
+		if e.schemaViolations == nil {
+			e.schemaViolations = make(map[string]int)
+		}
+		e.schemaViolations[name]++
+		e.mu.Unlock()
+	}
+	return err
+}
+
+// ToolSchema returns the JSON parameter schema for a registered tool, or nil
+// if the tool is not registered.
+func (e *ToolExecutor) ToolSchema(name string) json.RawMessage {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if tool, ok := e.tools[name]; ok {
+		return tool.Definition.Function.Parameters
+	}
+	return nil
+}
+
+// SchemaViolationStats returns a copy of per-tool schema violation counts.
+func (e *ToolExecutor) SchemaViolationStats() map[string]int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	stats := make(map[string]int, len(e.schemaViolations))
+	for name, count := range e.schemaViolations {
+		stats[name] = count
+	}
+	return stats
+}
+
+// repairToolCalls validates each pending tool call against its schema and, on
+// violation, asks the LLM once for a corrected arguments object. Successful
+// repairs replace the arguments in place; failed repairs leave the call
+// untouched so the normal tool error path reports the problem to the model.
+func (a *AgentRun) repairToolCalls(ctx context.Context, calls []ToolCall) {
+	for i := range calls {
+		valErr := a.executor.ValidateToolCall(calls[i])
+		if valErr == nil {
+			continue
+		}
+
+		name := calls[i].Function.Name
+		a.logger.Warn("tool call failed schema validation",
+			"tool", name,
+			"error", valErr,
+		)
+
+		repaired, err := a.repairToolArgs(ctx, calls[i], valErr)
+		if err != nil {
+			a.logger.Debug("argument repair failed", "tool", name, "error", err)
+			continue
+		}
+		a.logger.Info("tool call arguments repaired", "tool", name)
+		calls[i].Function.Arguments = repaired
+	}
+}
+
+// repairToolArgs performs the single corrective LLM call for one invalid
+// tool call and re-validates the result before accepting it.
+func (a *AgentRun) repairToolArgs(ctx context.Context, call ToolCall, valErr error) (string, error) {
+	repairCtx, cancel := context.WithTimeout(ctx, argRepairTimeout)
+	defer cancel()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tool: %s\n\nSchema:\n%s\n\nInvalid arguments:\n%s\n\nValidation error: %v\n",
+		call.Function.Name,
+		a.executor.ToolSchema(call.Function.Name),
+		call.Function.Arguments,
+		valErr,
+	)
+
+	resp, err := a.llm.Complete(repairCtx, argRepairSystemPrompt, nil, b.String())
+	if err != nil {
+		return "", fmt.Errorf("repair call: %w", err)
+	}
+
+	// Tolerate surrounding text and markdown fences around the JSON object.
+	start := strings.Index(resp, "{")
+	end := strings.LastIndex(resp, "}")
+	if start < 0 || end <= start {
+		return "", fmt.Errorf("no JSON object in repair response")
+	}
+	repaired := resp[start : end+1]
+
+	fixed := call
+	fixed.Function.Arguments = repaired
+	if err := a.executor.ValidateToolCall(fixed); err != nil {
+		return "", fmt.Errorf("repaired arguments still invalid: %w", err)
+	}
+	return repaired, nil
+}
@@ -0,0 +1,114 @@
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestValidateToolArgs(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string"},
+			"limit": {"type": "integer"},
+			"ratio": {"type": "number"},
+			"recursive": {"type": "boolean"},
+			"mode": {"type": "string", "enum": ["read", "write"]}
+		},
+		"required": ["path"]
+	}`)
+
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{"valid", `{"path": "/tmp/x", "limit": 5, "mode": "read"}`, false},
+		{"integer as whole float", `{"path": "/tmp/x", "limit": 5.0}`, false},
+		{"empty args with required", ``, true},
+		{"bad JSON", `{"path": `, true},
+		{"not an object", `[1, 2]`, true},
+		{"missing required", `{"limit": 5}`, true},
+		{"wrong type string", `{"path": 42}`, true},
+		{"wrong type integer", `{"path": "/tmp/x", "limit": 1.5}`, true},
+		{"wrong type boolean", `{"path": "/tmp/x", "recursive": "yes"}`, true},
+		{"enum violation", `{"path": "/tmp/x", "mode": "append"}`, true},
+		{"null value tolerated", `{"path": "/tmp/x", "limit": null}`, false},
+		{"extra field tolerated by default", `{"path": "/tmp/x", "color": "red"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateToolArgs(schema, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateToolArgs(%q) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Empty or unparseable schemas never block a call.
+	if err := validateToolArgs(nil, `{"anything": true}`); err != nil {
+		t.Errorf("nil schema should validate, got %v", err)
+	}
+	if err := validateToolArgs(json.RawMessage(`not json`), `{}`); err != nil {
+		t.Errorf("unparseable schema should validate, got %v", err)
+	}
+
+	// additionalProperties: false rejects unknown fields.
+	strict := json.RawMessage(`{
+		"type": "object",
+		"properties": {"path": {"type": "string"}},
+		"additionalProperties": false
+	}`)
+	if err := validateToolArgs(strict, `{"path": "/x", "extra": 1}`); err == nil {
+		t.Error("expected error for unknown field with additionalProperties false")
+	}
+}
+
+func TestSchemaViolationStats(t *testing.T) {
+	executor := NewToolExecutor(slog.Default())
+	executor.Register(
+		MakeToolDefinition("stats_probe", "test tool", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"value": map[string]any{"type": "string"},
+			},
+			"required": []string{"value"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) { return "ok", nil },
+	)
+
+	valid := ToolCall{Function: FunctionCall{Name: "stats_probe", Arguments: `{"value": "x"}`}}
+	invalid := ToolCall{Function: FunctionCall{Name: "stats_probe", Arguments: `{}`}}
+
+	if err := executor.ValidateToolCall(valid); err != nil {
+		t.Fatalf("valid call rejected: %v", err)
+	}
+	if err := executor.ValidateToolCall(invalid); err == nil {
+		t.Fatal("expected validation error for missing required field")
+	}
+	if err := executor.ValidateToolCall(invalid); err == nil {
+		t.Fatal("expected validation error for missing required field")
+	}
+
+	// Unknown tools are not validated (and not counted).
+	unknown := ToolCall{Function: FunctionCall{Name: "no_such_tool", Arguments: `{}`}}
+	if err := executor.ValidateToolCall(unknown); err != nil {
+		t.Fatalf("unknown tool should pass through, got %v", err)
+	}
+
+	stats := executor.SchemaViolationStats()
+	if stats["stats_probe"] != 2 {
+		t.Errorf("expected 2 violations for stats_probe, got %d", stats["stats_probe"])
+	}
+	if _, ok := stats["no_such_tool"]; ok {
+		t.Error("unknown tool should not appear in stats")
+	}
+
+	// The returned map is a copy.
+	stats["stats_probe"] = 99
+	if got := executor.SchemaViolationStats()["stats_probe"]; got != 2 {
+		t.Errorf("stats map should be a copy, got %d after mutation", got)
+	}
+}
@@ -0,0 +1,290 @@
+// Package copilot – tool_selector.go implements dynamic per-turn tool
+// selection. With 100+ registered tools the full catalog is sent with every
+// LLM request; the selector instead scores tool descriptions against the
+// current user message (vector embeddings when a provider is configured,
+// keyword overlap otherwise) and sends only the top-N definitions. The
+// find_tool escape hatch lets the model search the full catalog and pin
+// tools that were filtered out, making them available from the next turn.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot/memory"
+)
+
+// maxPinnedTools caps how many tools find_tool can pin per selector.
+const maxPinnedTools = 16
+
+// ToolSelector scores registered tools against the current message and
+// returns only the most relevant definitions.
+type ToolSelector struct {
+	cfg      ToolSelectionConfig
+	embedder memory.EmbeddingProvider
+	logger   *slog.Logger
+
+	mu     sync.RWMutex
+	vecs   map[string][]float32 // Embedded tool descriptions, keyed by name.
+	pinned map[string]bool      // Tools pinned via find_tool.
+}
+
+// NewToolSelector creates a tool selector. The embedder may be nil or a
+// NullEmbedder, in which case keyword-overlap scoring is used.
+func NewToolSelector(cfg ToolSelectionConfig, embedder memory.EmbeddingProvider, logger *slog.Logger) *ToolSelector {
+	return &ToolSelector{
+		cfg:      cfg.Effective(),
+		embedder: embedder,
+		logger:   logger.With("component", "tool_selector"),
+		vecs:     make(map[string][]float32),
+		pinned:   make(map[string]bool),
+	}
+}
+
+// Pin marks tools as always-selected for the lifetime of this selector.
+// Called by find_tool so tools the model asked for survive the next cut.
+func (s *ToolSelector) Pin(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range names {
+		if len(s.pinned) >= maxPinnedTools && !s.pinned[name] {
+			continue
+		}
+		s.pinned[name] = true
+	}
+}
+
+// Select returns the top-N tool definitions relevant to the query. Tools in
+// AlwaysInclude, pinned tools, and find_tool itself always survive the cut.
+// The catalog is returned unchanged when it already fits within TopN.
+func (s *ToolSelector) Select(ctx context.Context, query string, defs []ToolDefinition) []ToolDefinition {
+	if len(defs) <= s.cfg.TopN {
+		return defs
+	}
+
+	keep := make(map[string]bool, len(s.cfg.AlwaysInclude)+1)
+	keep["find_tool"] = true
+	for _, name := range s.cfg.AlwaysInclude {
+		keep[name] = true
+	}
+	s.mu.RLock()
+	for name := range s.pinned {
+		keep[name] = true
+	}
+	s.mu.RUnlock()
+
+	scores := s.scoreTools(ctx, query, defs)
+
+	// Rank the non-kept tools by score and fill the remaining slots.
+	type scored struct {
+		name  string
+		score float64
+	}
+	ranked := make([]scored, 0, len(defs))
+	selected := make(map[string]bool, s.cfg.TopN)
+	for _, def := range defs {
+		name := def.Function.Name
+		if keep[name] {
+			selected[name] = true
+			continue
+		}
+		ranked = append(ranked, scored{name, scores[name]})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	for _, r := range ranked {
+		if len(selected) >= s.cfg.TopN {
+			break
+		}
+		selected[r.name] = true
+	}
+
+	// Preserve catalog order for deterministic prompts.
+	out := make([]ToolDefinition, 0, len(selected))
+	for _, def := range defs {
+		if selected[def.Function.Name] {
+			out = append(out, def)
+		}
+	}
+	s.logger.Debug("tools selected for turn",
+		"catalog", len(defs),
+		"selected", len(out),
+	)
+	return out
+}
+
+// scoreTools returns a relevance score per tool name. Embedding-based cosine
+// similarity when a provider is available; keyword overlap otherwise (and as
+// fallback when the embedding call fails).
+func (s *ToolSelector) scoreTools(ctx context.Context, query string, defs []ToolDefinition) map[string]float64 {
+	if s.embedder != nil && s.embedder.Dimensions() > 0 {
+		if scores, err := s.scoreByEmbedding(ctx, query, defs); err == nil {
+			return scores
+		} else {
+			s.logger.Debug("embedding-based tool scoring failed, using keywords", "error", err)
+		}
+	}
+	return scoreByKeywords(query, defs)
+}
+
+// scoreByEmbedding embeds missing tool descriptions (cached per selector)
+// plus the query, and scores by cosine similarity.
+func (s *ToolSelector) scoreByEmbedding(ctx context.Context, query string, defs []ToolDefinition) (map[string]float64, error) {
+	// Collect descriptions that are not yet embedded.
+	s.mu.RLock()
+	var missing []ToolDefinition
+	for _, def := range defs {
+		if _, ok := s.vecs[def.Function.Name]; !ok {
+			missing = append(missing, def)
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(missing) > 0 {
+		texts := make([]string, len(missing))
+		for i, def := range missing {
+			texts[i] = def.Function.Name + ": " + def.Function.Description
+		}
+		vecs, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("embedding tool descriptions: %w", err)
+		}
+		if len(vecs) != len(missing) {
+			return nil, fmt.Errorf("embedding count mismatch: got %d, want %d", len(vecs), len(missing))
+		}
+		s.mu.Lock()
+		for i, def := range missing {
+			s.vecs[def.Function.Name] = vecs[i]
+		}
+		s.mu.Unlock()
+	}
+
+	qVecs, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embedding query: %w", err)
+	}
+	if len(qVecs) != 1 || len(qVecs[0]) == 0 {
+		return nil, fmt.Errorf("empty query embedding")
+	}
+
+	scores := make(map[string]float64, len(defs))
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, def := range defs {
+		name := def.Function.Name
+		scores[name] = cosineSimilarity(qVecs[0], s.vecs[name])
+	}
+	return scores, nil
+}
+
+// scoreByKeywords scores tools by token overlap between the query and the
+// tool's name + description. Name matches weigh double.
+func scoreByKeywords(query string, defs []ToolDefinition) map[string]float64 {
+	queryTokens := tokenizeForMatch(query)
+	scores := make(map[string]float64, len(defs))
+	for _, def := range defs {
+		nameTokens := tokenizeForMatch(def.Function.Name)
+		descTokens := tokenizeForMatch(def.Function.Description)
+		var score float64
+		for tok := range queryTokens {
+			if nameTokens[tok] {
+				score += 2
+			}
+			if descTokens[tok] {
+				score++
+			}
+		}
+		scores[def.Function.Name] = score
+	}
+	return scores
+}
+
+// tokenizeForMatch lowercases and splits text into alphanumeric tokens,
+// dropping short stopword-like tokens.
+func tokenizeForMatch(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, tok := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !('a' <= r && r <= 'z') && !('0' <= r && r <= '9')
+	}) {
+		if len(tok) < 3 {
+			continue
+		}
+		tokens[tok] = true
+	}
+	return tokens
+}
+
+// cosineSimilarity computes the cosine similarity of two vectors.
+// Returns 0 for empty or mismatched vectors.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// RegisterFindTool registers the find_tool escape hatch: the model searches
+// the full catalog by keyword and matching tools are pinned so they are
+// included in the definitions sent on subsequent turns.
+func RegisterFindTool(executor *ToolExecutor, selector *ToolSelector) {
+	executor.Register(
+		MakeToolDefinition("find_tool", "Search the full tool catalog when the tool you need is not currently available. Matching tools become available on your next turn.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "What you want to do (e.g. 'schedule a job', 'query a database').",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "Maximum tools to return (default: 8)",
+				},
+			},
+			"required": []string{"query"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			query, _ := args["query"].(string)
+			if query == "" {
+				return nil, fmt.Errorf("query is required")
+			}
+			limit := toolArgInt(args, "limit", 8)
+
+			// Copy before sorting — Tools() returns the executor's cached slice.
+			all := executor.Tools()
+			defs := make([]ToolDefinition, len(all))
+			copy(defs, all)
+			scores := scoreByKeywords(query, defs)
+			sort.SliceStable(defs, func(i, j int) bool {
+				return scores[defs[i].Function.Name] > scores[defs[j].Function.Name]
+			})
+
+			table := NewTableResult("Matching tools (available from your next turn)", []string{"Name", "Description"})
+			var names []string
+			for _, def := range defs {
+				if len(names) >= limit || scores[def.Function.Name] <= 0 {
+					break
+				}
+				names = append(names, def.Function.Name)
+				table.AddRow(def.Function.Name, truncate(def.Function.Description, 120))
+			}
+			if len(names) == 0 {
+				return "No matching tools found.", nil
+			}
+			selector.Pin(names...)
+			return table, nil
+		},
+	)
+}
@@ -0,0 +1,136 @@
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func selectorTestDefs() []ToolDefinition {
+	return []ToolDefinition{
+		MakeToolDefinition("find_tool", "Search the full tool catalog.", nil),
+		MakeToolDefinition("bash", "Execute a shell command.", nil),
+		MakeToolDefinition("cron_add", "Schedule a recurring job or reminder.", nil),
+		MakeToolDefinition("cron_list", "List all scheduled jobs.", nil),
+		MakeToolDefinition("db_query", "Run a database query against the configured database.", nil),
+		MakeToolDefinition("send_image", "Send an image to the chat.", nil),
+		MakeToolDefinition("web_fetch", "Fetch a web page by URL.", nil),
+	}
+}
+
+func TestToolSelectorSelect(t *testing.T) {
+	selector := NewToolSelector(ToolSelectionConfig{Enabled: true, TopN: 3, AlwaysInclude: []string{"bash"}}, nil, slog.Default())
+	defs := selectorTestDefs()
+
+	selected := selector.Select(context.Background(), "please schedule a daily job for me", defs)
+	if len(selected) > 5 {
+		t.Fatalf("expected at most TopN+kept tools, got %d", len(selected))
+	}
+
+	names := make(map[string]bool)
+	for _, def := range selected {
+		names[def.Function.Name] = true
+	}
+	if !names["find_tool"] {
+		t.Error("find_tool must always survive the cut")
+	}
+	if !names["bash"] {
+		t.Error("always_include tool must survive the cut")
+	}
+	if !names["cron_add"] {
+		t.Errorf("expected cron_add to rank for a scheduling query, got %v", names)
+	}
+}
+
+func TestToolSelectorSmallCatalogPassthrough(t *testing.T) {
+	selector := NewToolSelector(ToolSelectionConfig{Enabled: true, TopN: 24}, nil, slog.Default())
+	defs := selectorTestDefs()
+
+	selected := selector.Select(context.Background(), "anything", defs)
+	if len(selected) != len(defs) {
+		t.Errorf("catalog within TopN should pass through unchanged: got %d, want %d", len(selected), len(defs))
+	}
+}
+
+func TestToolSelectorPin(t *testing.T) {
+	selector := NewToolSelector(ToolSelectionConfig{Enabled: true, TopN: 2}, nil, slog.Default())
+	selector.Pin("send_image")
+
+	selected := selector.Select(context.Background(), "schedule a job", selectorTestDefs())
+	found := false
+	for _, def := range selected {
+		if def.Function.Name == "send_image" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("pinned tool must survive the cut")
+	}
+
+	// The pin cap prevents unbounded growth.
+	for i := 0; i < maxPinnedTools*2; i++ {
+		selector.Pin(fmt.Sprintf("tool_%d", i))
+	}
+	selector.mu.RLock()
+	pinned := len(selector.pinned)
+	selector.mu.RUnlock()
+	if pinned > maxPinnedTools {
+		t.Errorf("expected at most %d pinned tools, got %d", maxPinnedTools, pinned)
+	}
+}
+
+func TestFindToolPinsMatches(t *testing.T) {
+	executor := NewToolExecutor(slog.Default())
+	for _, def := range selectorTestDefs() {
+		if def.Function.Name == "find_tool" {
+			continue
+		}
+		executor.Register(def, func(ctx context.Context, args map[string]any) (any, error) { return "ok", nil })
+	}
+
+	selector := NewToolSelector(ToolSelectionConfig{Enabled: true, TopN: 2}, nil, slog.Default())
+	RegisterFindTool(executor, selector)
+
+	results := executor.Execute(context.Background(), []ToolCall{
+		{ID: "1", Function: FunctionCall{Name: "find_tool", Arguments: `{"query": "database query"}`}},
+	})
+	if results[0].Error != nil {
+		t.Fatalf("find_tool failed: %v", results[0].Error)
+	}
+	if !strings.Contains(results[0].Content, "db_query") {
+		t.Errorf("expected db_query in results: %q", results[0].Content)
+	}
+
+	selector.mu.RLock()
+	pinned := selector.pinned["db_query"]
+	selector.mu.RUnlock()
+	if !pinned {
+		t.Error("find_tool should pin matching tools")
+	}
+
+	// No matches for nonsense queries.
+	results = executor.Execute(context.Background(), []ToolCall{
+		{ID: "2", Function: FunctionCall{Name: "find_tool", Arguments: `{"query": "zzzxqwv"}`}},
+	})
+	if results[0].Content != "No matching tools found." {
+		t.Errorf("unexpected no-match response: %q", results[0].Content)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	a := []float32{1, 0, 0}
+	if got := cosineSimilarity(a, []float32{1, 0, 0}); got < 0.999 {
+		t.Errorf("identical vectors should score ~1, got %f", got)
+	}
+	if got := cosineSimilarity(a, []float32{0, 1, 0}); got != 0 {
+		t.Errorf("orthogonal vectors should score 0, got %f", got)
+	}
+	if got := cosineSimilarity(a, []float32{1, 0}); got != 0 {
+		t.Errorf("mismatched lengths should score 0, got %f", got)
+	}
+	if got := cosineSimilarity(nil, nil); got != 0 {
+		t.Errorf("empty vectors should score 0, got %f", got)
+	}
+}
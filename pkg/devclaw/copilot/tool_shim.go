@@ -0,0 +1,192 @@
+// Package copilot – tool_shim.go implements a ReAct-style function-calling
+// shim for models without native tool support (common with local Ollama
+// models). The tool catalog is described in a structured prompt protocol,
+// the model emits invocations as fenced tool_call JSON blocks, and the shim
+// parses them back into ToolCall objects — the agent loop sees no difference
+// from native tool calling.
+package copilot
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// toolShimInstructions is the protocol contract injected as a system message
+// when the shim is active. %s is the rendered tool catalog.
+const toolShimInstructions = `You can use tools, but you have no native function calling. To invoke a tool, reply with a fenced block in exactly this format:
+
+` + "```tool_call" + `
+{"name": "<tool_name>", "arguments": {<json arguments>}}
+` + "```" + `
+
+Rules:
+- Emit one block per tool call; multiple blocks are allowed in one reply.
+- Do not write any other text in a reply that contains tool calls.
+- Tool results will arrive as user messages starting with "[tool result".
+- When you have everything you need, answer in plain text with no tool_call block.
+
+Available tools:
+
+%s`
+
+// toolShimCallRe matches a fenced tool_call block and captures the JSON body.
+var toolShimCallRe = regexp.MustCompile("(?s)```tool_call\\s*(\\{.*?\\})\\s*```")
+
+// toolShimActive reports whether the function-calling shim should handle this
+// request: tools are needed and either the model is known to lack native
+// tool support or the shim is forced via params.tool_shim.
+func (c *LLMClient) toolShimActive(model string, tools []ToolDefinition) bool {
+	if len(tools) == 0 {
+		return false
+	}
+	if c.paramBool("tool_shim") {
+		return true
+	}
+	return !getModelDefaults(model, c.provider).SupportsTools
+}
+
+// buildToolShimSystemPrompt renders the protocol instructions with the tool
+// catalog (name, description, parameter schema per tool).
+func buildToolShimSystemPrompt(tools []ToolDefinition) string {
+	var b strings.Builder
+	for _, t := range tools {
+		b.WriteString("- " + t.Function.Name)
+		if t.Function.Description != "" {
+			b.WriteString(": " + t.Function.Description)
+		}
+		b.WriteString("\n")
+		if len(t.Function.Parameters) > 0 && string(t.Function.Parameters) != "{}" {
+			b.WriteString("  parameters: " + string(t.Function.Parameters) + "\n")
+		}
+	}
+	return fmt.Sprintf(toolShimInstructions, b.String())
+}
+
+// applyToolShimMessages rewrites the conversation for a model without native
+// tools: the protocol system message is injected, prior assistant tool calls
+// are rendered back into tool_call blocks, and tool results become user
+// messages. The input slice is not mutated.
+func applyToolShimMessages(messages []chatMessage, tools []ToolDefinition) []chatMessage {
+	out := make([]chatMessage, 0, len(messages)+1)
+
+	injected := false
+	for _, m := range messages {
+		switch {
+		case m.Role == "system" && !injected:
+			out = append(out, m, chatMessage{Role: "system", Content: buildToolShimSystemPrompt(tools)})
+			injected = true
+
+		case m.Role == "assistant" && len(m.ToolCalls) > 0:
+			var b strings.Builder
+			if s, ok := m.Content.(string); ok && s != "" {
+				b.WriteString(s + "\n")
+			}
+			for _, tc := range m.ToolCalls {
+				b.WriteString(fmt.Sprintf("```tool_call\n{\"name\": %q, \"arguments\": %s}\n```\n",
+					tc.Function.Name, nonEmptyJSON(tc.Function.Arguments)))
+			}
+			out = append(out, chatMessage{Role: "assistant", Content: strings.TrimRight(b.String(), "\n")})
+
+		case m.Role == "tool":
+			content := ""
+			if s, ok := m.Content.(string); ok {
+				content = s
+			}
+			out = append(out, chatMessage{
+				Role:    "user",
+				Content: fmt.Sprintf("[tool result for %s]\n%s", m.ToolCallID, content),
+			})
+
+		default:
+			out = append(out, m)
+		}
+	}
+
+	if !injected {
+		out = append([]chatMessage{{Role: "system", Content: buildToolShimSystemPrompt(tools)}}, out...)
+	}
+	return out
+}
+
+// nonEmptyJSON returns the arguments string, defaulting to an empty object.
+func nonEmptyJSON(args string) string {
+	if strings.TrimSpace(args) == "" {
+		return "{}"
+	}
+	return args
+}
+
+// parseToolShimCalls extracts tool invocations from a text response. Returns
+// the content with the blocks removed and the parsed calls. Invalid blocks
+// are left in the text so the agent loop can surface them.
+func parseToolShimCalls(content string) (string, []ToolCall) {
+	var calls []ToolCall
+
+	cleaned := toolShimCallRe.ReplaceAllStringFunc(content, func(block string) string {
+		body := toolShimCallRe.FindStringSubmatch(block)[1]
+		call, ok := parseToolShimBlock(body)
+		if !ok {
+			return block
+		}
+		call.ID = fmt.Sprintf("shim_call_%d", len(calls)+1)
+		calls = append(calls, call)
+		return ""
+	})
+
+	// Lenient fallback: some models skip the fencing and reply with a bare
+	// invocation object.
+	if len(calls) == 0 {
+		if call, ok := parseToolShimBlock(strings.TrimSpace(content)); ok {
+			call.ID = "shim_call_1"
+			return "", []ToolCall{call}
+		}
+	}
+
+	return strings.TrimSpace(cleaned), calls
+}
+
+// parseToolShimBlock parses one {"name": ..., "arguments": ...} object.
+func parseToolShimBlock(body string) (ToolCall, bool) {
+	var invocation struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal([]byte(body), &invocation); err != nil || invocation.Name == "" {
+		return ToolCall{}, false
+	}
+
+	args := strings.TrimSpace(string(invocation.Arguments))
+	if args == "" || args == "null" {
+		args = "{}"
+	}
+	// Arguments given as a JSON-encoded string: unwrap to the object.
+	if strings.HasPrefix(args, `"`) {
+		var unwrapped string
+		if err := json.Unmarshal([]byte(args), &unwrapped); err == nil {
+			args = unwrapped
+		}
+	}
+
+	return ToolCall{
+		Type:     "function",
+		Function: FunctionCall{Name: invocation.Name, Arguments: args},
+	}, true
+}
+
+// applyToolShimResponse converts protocol blocks in a text response into
+// native ToolCalls on the LLMResponse.
+func applyToolShimResponse(resp *LLMResponse) *LLMResponse {
+	if resp == nil || len(resp.ToolCalls) > 0 {
+		return resp
+	}
+	content, calls := parseToolShimCalls(resp.Content)
+	if len(calls) == 0 {
+		return resp
+	}
+	resp.Content = content
+	resp.ToolCalls = calls
+	resp.FinishReason = "tool_calls"
+	return resp
+}
@@ -0,0 +1,142 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToolShimActive(t *testing.T) {
+	tools := []ToolDefinition{MakeToolDefinition("probe", "test", nil)}
+
+	// Models without native tool support use the shim automatically.
+	ollama := &LLMClient{provider: "ollama"}
+	if !ollama.toolShimActive("gemma2:9b", tools) {
+		t.Error("expected shim for gemma (no native tools)")
+	}
+	if ollama.toolShimActive("llama3.1:8b", tools) {
+		t.Error("llama supports native tools, shim must stay off")
+	}
+	if ollama.toolShimActive("gemma2:9b", nil) {
+		t.Error("shim is pointless without tools")
+	}
+
+	// params.tool_shim forces the shim for any model.
+	forced := &LLMClient{provider: "ollama", params: map[string]any{"tool_shim": true}}
+	if !forced.toolShimActive("llama3.1:8b", tools) {
+		t.Error("params.tool_shim should force the shim")
+	}
+}
+
+func TestApplyToolShimMessages(t *testing.T) {
+	tools := []ToolDefinition{MakeToolDefinition("read_file", "Read a file", map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"path": map[string]any{"type": "string"}},
+	})}
+	messages := []chatMessage{
+		{Role: "system", Content: "base prompt"},
+		{Role: "user", Content: "read main.go"},
+		{Role: "assistant", ToolCalls: []ToolCall{
+			{ID: "c1", Type: "function", Function: FunctionCall{Name: "read_file", Arguments: `{"path":"main.go"}`}},
+		}},
+		{Role: "tool", ToolCallID: "c1", Content: "package main"},
+	}
+
+	out := applyToolShimMessages(messages, tools)
+	if len(out) != 5 {
+		t.Fatalf("expected 5 messages (protocol injected), got %d", len(out))
+	}
+
+	// Protocol system message follows the base system prompt.
+	protocol, _ := out[1].Content.(string)
+	if out[1].Role != "system" || !strings.Contains(protocol, "read_file") {
+		t.Errorf("expected protocol system message with tool catalog, got %+v", out[1])
+	}
+
+	// Assistant tool calls become tool_call blocks.
+	assistant, _ := out[3].Content.(string)
+	if len(out[3].ToolCalls) != 0 || !strings.Contains(assistant, "```tool_call") {
+		t.Errorf("expected rendered tool_call block, got %+v", out[3])
+	}
+
+	// Tool results become user messages.
+	result, _ := out[4].Content.(string)
+	if out[4].Role != "user" || !strings.Contains(result, "[tool result for c1]") {
+		t.Errorf("expected tool result as user message, got %+v", out[4])
+	}
+
+	// The original slice is untouched.
+	if messages[3].Role != "tool" {
+		t.Error("input messages must not be mutated")
+	}
+
+	// No system message: protocol is prepended.
+	out = applyToolShimMessages([]chatMessage{{Role: "user", Content: "hi"}}, tools)
+	if out[0].Role != "system" {
+		t.Errorf("expected prepended protocol message, got %+v", out[0])
+	}
+}
+
+func TestParseToolShimCalls(t *testing.T) {
+	content := "I'll read the file.\n```tool_call\n{\"name\": \"read_file\", \"arguments\": {\"path\": \"main.go\"}}\n```\n```tool_call\n{\"name\": \"list_dir\", \"arguments\": {}}\n```"
+
+	cleaned, calls := parseToolShimCalls(content)
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if calls[0].Function.Name != "read_file" || calls[0].Function.Arguments != `{"path": "main.go"}` {
+		t.Errorf("unexpected first call: %+v", calls[0])
+	}
+	if calls[0].ID != "shim_call_1" || calls[1].ID != "shim_call_2" {
+		t.Errorf("expected sequential shim IDs, got %q, %q", calls[0].ID, calls[1].ID)
+	}
+	if strings.Contains(cleaned, "tool_call") {
+		t.Errorf("blocks should be stripped from content: %q", cleaned)
+	}
+	if !strings.Contains(cleaned, "I'll read the file.") {
+		t.Errorf("surrounding text should survive: %q", cleaned)
+	}
+
+	// Invalid JSON stays in the text and produces no call.
+	cleaned, calls = parseToolShimCalls("```tool_call\n{not json}\n```")
+	if len(calls) != 0 || !strings.Contains(cleaned, "not json") {
+		t.Errorf("invalid block should be left in place, got %q / %v", cleaned, calls)
+	}
+
+	// Bare invocation object without fencing.
+	_, calls = parseToolShimCalls(`{"name": "read_file", "arguments": {"path": "x"}}`)
+	if len(calls) != 1 || calls[0].Function.Name != "read_file" {
+		t.Errorf("expected bare object fallback, got %v", calls)
+	}
+
+	// String-encoded arguments are unwrapped.
+	_, calls = parseToolShimCalls("```tool_call\n{\"name\": \"probe\", \"arguments\": \"{\\\"a\\\":1}\"}\n```")
+	if len(calls) != 1 || calls[0].Function.Arguments != `{"a":1}` {
+		t.Errorf("expected unwrapped arguments, got %v", calls)
+	}
+
+	// Plain text: no calls.
+	cleaned, calls = parseToolShimCalls("just an answer")
+	if len(calls) != 0 || cleaned != "just an answer" {
+		t.Errorf("plain text should pass through, got %q / %v", cleaned, calls)
+	}
+}
+
+func TestApplyToolShimResponse(t *testing.T) {
+	resp := &LLMResponse{Content: "```tool_call\n{\"name\": \"probe\", \"arguments\": {}}\n```"}
+	out := applyToolShimResponse(resp)
+	if len(out.ToolCalls) != 1 || out.FinishReason != "tool_calls" {
+		t.Errorf("expected converted tool call, got %+v", out)
+	}
+
+	// Native tool calls pass through untouched.
+	native := &LLMResponse{ToolCalls: []ToolCall{{ID: "n1"}}, Content: "x"}
+	if out := applyToolShimResponse(native); out.ToolCalls[0].ID != "n1" || out.Content != "x" {
+		t.Errorf("native response must pass through, got %+v", out)
+	}
+
+	// Plain text response stays a text response.
+	text := &LLMResponse{Content: "done", FinishReason: "stop"}
+	if out := applyToolShimResponse(text); len(out.ToolCalls) != 0 || out.FinishReason != "stop" {
+		t.Errorf("text response must pass through, got %+v", out)
+	}
+}
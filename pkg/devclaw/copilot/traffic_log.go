@@ -0,0 +1,209 @@
+// Package copilot – traffic_log.go implements LLM request/response logging
+// with privacy levels. Operators debugging bad completions can record what
+// was actually sent to and received from the provider:
+//
+//   - "off":      nothing is recorded (default)
+//   - "metadata": model, tokens, duration, and status only
+//   - "redacted": payloads pass through the output sanitizer first
+//   - "full":     payloads stored verbatim
+//
+// Records live in the llm_traffic table with configurable retention and are
+// inspected via `devclaw traffic list|show <id>`.
+package copilot
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Traffic log privacy levels.
+const (
+	TrafficLevelOff      = "off"
+	TrafficLevelMetadata = "metadata"
+	TrafficLevelRedacted = "redacted"
+	TrafficLevelFull     = "full"
+)
+
+// TrafficLogConfig configures LLM traffic logging.
+type TrafficLogConfig struct {
+	// Level is the privacy level: off, metadata, redacted, full.
+	Level string `yaml:"level"`
+
+	// RetentionDays is how long records are kept (default 7).
+	RetentionDays int `yaml:"retention_days"`
+}
+
+// DefaultTrafficLogConfig returns the default configuration (logging off).
+func DefaultTrafficLogConfig() TrafficLogConfig {
+	return TrafficLogConfig{
+		Level:         TrafficLevelOff,
+		RetentionDays: 7,
+	}
+}
+
+// TrafficRecord is one logged LLM round trip.
+type TrafficRecord struct {
+	ID               string
+	SessionID        string
+	Provider         string
+	Model            string
+	Request          string // JSON-encoded messages (empty at metadata level)
+	Response         string // completion text (empty at metadata level)
+	Status           string // "ok" or "error"
+	Error            string
+	PromptTokens     int
+	CompletionTokens int
+	DurationMs       int64
+	CreatedAt        time.Time
+}
+
+// TrafficLogStore persists LLM traffic records in devclaw.db.
+type TrafficLogStore struct {
+	db *sql.DB
+}
+
+// NewTrafficLogStore creates a traffic log store.
+func NewTrafficLogStore(db *sql.DB) *TrafficLogStore {
+	return &TrafficLogStore{db: db}
+}
+
+// Add persists a record and returns its generated ID.
+func (s *TrafficLogStore) Add(rec *TrafficRecord) (string, error) {
+	id := generateID()
+	createdAt := rec.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO llm_traffic (id, session_id, provider, model, request, response, status, error, prompt_tokens, completion_tokens, duration_ms, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, id, rec.SessionID, rec.Provider, rec.Model, rec.Request, rec.Response,
+		rec.Status, rec.Error, rec.PromptTokens, rec.CompletionTokens,
+		rec.DurationMs, createdAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return "", fmt.Errorf("insert traffic record: %w", err)
+	}
+	return id, nil
+}
+
+// Get returns one record by ID.
+func (s *TrafficLogStore) Get(id string) (*TrafficRecord, error) {
+	row := s.db.QueryRow(`
+		SELECT id, session_id, provider, model, request, response, status, error, prompt_tokens, completion_tokens, duration_ms, created_at
+		FROM llm_traffic WHERE id = ?
+	`, id)
+	rec, err := scanTrafficRecord(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("traffic record %q not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query traffic record: %w", err)
+	}
+	return rec, nil
+}
+
+// Recent returns the newest records, most recent first.
+func (s *TrafficLogStore) Recent(limit int) ([]TrafficRecord, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := s.db.Query(`
+		SELECT id, session_id, provider, model, request, response, status, error, prompt_tokens, completion_tokens, duration_ms, created_at
+		FROM llm_traffic ORDER BY created_at DESC LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query traffic records: %w", err)
+	}
+	defer rows.Close()
+
+	var out []TrafficRecord
+	for rows.Next() {
+		rec, err := scanTrafficRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan traffic record: %w", err)
+		}
+		out = append(out, *rec)
+	}
+	return out, rows.Err()
+}
+
+// Purge deletes records older than the retention window. Returns the number
+// of deleted rows.
+func (s *TrafficLogStore) Purge(retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		retentionDays = DefaultTrafficLogConfig().RetentionDays
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays).UTC().Format(time.RFC3339)
+	res, err := s.db.Exec(`DELETE FROM llm_traffic WHERE created_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge traffic records: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+func scanTrafficRecord(row dlqRowScanner) (*TrafficRecord, error) {
+	var rec TrafficRecord
+	var createdAt string
+	if err := row.Scan(&rec.ID, &rec.SessionID, &rec.Provider, &rec.Model,
+		&rec.Request, &rec.Response, &rec.Status, &rec.Error,
+		&rec.PromptTokens, &rec.CompletionTokens, &rec.DurationMs, &createdAt); err != nil {
+		return nil, err
+	}
+	rec.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &rec, nil
+}
+
+// --- LLM client integration ---
+
+// SetTrafficRecorder enables traffic logging on the client. The recorder is
+// called after every completion with a record already filtered to the given
+// privacy level. Pass TrafficLevelOff (or nil) to disable.
+func (c *LLMClient) SetTrafficRecorder(level string, fn func(TrafficRecord)) {
+	if level == "" || level == TrafficLevelOff || fn == nil {
+		c.trafficLevel = ""
+		c.trafficRecorder = nil
+		return
+	}
+	c.trafficLevel = level
+	c.trafficRecorder = fn
+}
+
+// recordTraffic builds a TrafficRecord for one completion, applying the
+// configured privacy level, and hands it to the recorder. Called from
+// completeOnce — must never fail the completion itself.
+func (c *LLMClient) recordTraffic(ctx context.Context, model string, messages []chatMessage, resp *LLMResponse, callErr error, duration time.Duration) {
+	rec := TrafficRecord{
+		SessionID:  SessionIDFromContext(ctx),
+		Provider:   c.provider,
+		Model:      model,
+		Status:     "ok",
+		DurationMs: duration.Milliseconds(),
+	}
+	if callErr != nil {
+		rec.Status = "error"
+		rec.Error = callErr.Error()
+	}
+	if resp != nil {
+		rec.PromptTokens = resp.Usage.PromptTokens
+		rec.CompletionTokens = resp.Usage.CompletionTokens
+	}
+
+	if c.trafficLevel == TrafficLevelRedacted || c.trafficLevel == TrafficLevelFull {
+		if data, err := json.Marshal(messages); err == nil {
+			rec.Request = string(data)
+		}
+		if resp != nil {
+			rec.Response = resp.Content
+		}
+		if c.trafficLevel == TrafficLevelRedacted {
+			rec.Request = sanitizeOutput(rec.Request)
+			rec.Response = sanitizeOutput(rec.Response)
+		}
+	}
+
+	c.trafficRecorder(rec)
+}
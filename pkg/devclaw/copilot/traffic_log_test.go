@@ -0,0 +1,128 @@
+package copilot
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTrafficStore(t *testing.T) *TrafficLogStore {
+	t.Helper()
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewTrafficLogStore(db)
+}
+
+func TestTrafficLogStoreLifecycle(t *testing.T) {
+	store := testTrafficStore(t)
+
+	id, err := store.Add(&TrafficRecord{
+		SessionID:        "whatsapp:123",
+		Provider:         "openai",
+		Model:            "gpt-4o",
+		Request:          `[{"role":"user","content":"hi"}]`,
+		Response:         "hello",
+		Status:           "ok",
+		PromptTokens:     10,
+		CompletionTokens: 5,
+		DurationMs:       420,
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	rec, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if rec.Model != "gpt-4o" || rec.Response != "hello" || rec.PromptTokens != 10 {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+
+	records, err := store.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	if _, err := store.Get("nope"); err == nil {
+		t.Error("Get with unknown ID should fail")
+	}
+}
+
+func TestTrafficLogStorePurge(t *testing.T) {
+	store := testTrafficStore(t)
+
+	old := &TrafficRecord{Model: "gpt-4o", Status: "ok", CreatedAt: time.Now().AddDate(0, 0, -30)}
+	fresh := &TrafficRecord{Model: "gpt-4o", Status: "ok"}
+	if _, err := store.Add(old); err != nil {
+		t.Fatalf("Add old: %v", err)
+	}
+	if _, err := store.Add(fresh); err != nil {
+		t.Fatalf("Add fresh: %v", err)
+	}
+
+	n, err := store.Purge(7)
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 purged record, got %d", n)
+	}
+	records, _ := store.Recent(10)
+	if len(records) != 1 {
+		t.Errorf("expected 1 remaining record, got %d", len(records))
+	}
+}
+
+func TestRecordTrafficLevels(t *testing.T) {
+	messages := []chatMessage{{Role: "user", Content: "my api_key= sk_live_0123456789abcdefghij"}}
+	resp := &LLMResponse{Content: "done", Usage: LLMUsage{PromptTokens: 7, CompletionTokens: 3}}
+	ctx := ContextWithSession(context.Background(), "cli:local")
+
+	capture := func(level string, callErr error) TrafficRecord {
+		var got TrafficRecord
+		c := &LLMClient{provider: "openai"}
+		c.SetTrafficRecorder(level, func(rec TrafficRecord) { got = rec })
+		c.recordTraffic(ctx, "gpt-4o", messages, resp, callErr, 250*time.Millisecond)
+		return got
+	}
+
+	// Metadata level: counters only, no payloads.
+	rec := capture(TrafficLevelMetadata, nil)
+	if rec.Request != "" || rec.Response != "" {
+		t.Errorf("metadata level should not record payloads: %+v", rec)
+	}
+	if rec.SessionID != "cli:local" || rec.PromptTokens != 7 || rec.DurationMs != 250 {
+		t.Errorf("metadata missing: %+v", rec)
+	}
+
+	// Full level: payloads verbatim.
+	rec = capture(TrafficLevelFull, nil)
+	if !strings.Contains(rec.Request, "sk_live_") || rec.Response != "done" {
+		t.Errorf("full level should record payloads verbatim: %+v", rec)
+	}
+
+	// Redacted level: payloads pass through the sanitizer.
+	rec = capture(TrafficLevelRedacted, nil)
+	if strings.Contains(rec.Request, "sk_live_") {
+		t.Errorf("redacted level leaked a secret: %q", rec.Request)
+	}
+	if rec.Request == "" {
+		t.Error("redacted level should still record the payload")
+	}
+
+	// Errors flip the status.
+	rec = capture(TrafficLevelMetadata, errors.New("rate limited"))
+	if rec.Status != "error" || rec.Error != "rate limited" {
+		t.Errorf("error not recorded: %+v", rec)
+	}
+}
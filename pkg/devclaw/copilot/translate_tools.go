@@ -0,0 +1,138 @@
+// Package copilot – translate_tools.go implements the translate tool and the
+// auto-translate group mode. In cross-language groups, incoming messages are
+// translated into the agent's working language before processing, and replies
+// are translated back — optionally dual-posted (original + translation) so
+// mixed-language groups can read either version.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// RegisterTranslateTool registers the translate tool. The same LLM that runs
+// the agent does the translation, so no external translation API is needed.
+func RegisterTranslateTool(executor *ToolExecutor, llmClient *LLMClient, logger *slog.Logger) {
+	if llmClient == nil {
+		return
+	}
+
+	executor.Register(
+		MakeToolDefinition("translate", "Translate text into another language. Preserves tone, formatting, and meaning. Use when the user asks for a translation or when you need to understand text in a language you were asked to work around.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"text": map[string]any{
+					"type":        "string",
+					"description": "The text to translate",
+				},
+				"target_language": map[string]any{
+					"type":        "string",
+					"description": "Language to translate into (e.g. 'English', 'Portuguese', 'Spanish')",
+				},
+				"source_language": map[string]any{
+					"type":        "string",
+					"description": "Language of the original text. Omit to auto-detect.",
+				},
+			},
+			"required": []string{"text", "target_language"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			text, _ := args["text"].(string)
+			if text == "" {
+				return nil, fmt.Errorf("text is required")
+			}
+			targetLang, _ := args["target_language"].(string)
+			if targetLang == "" {
+				return nil, fmt.Errorf("target_language is required")
+			}
+			sourceLang, _ := args["source_language"].(string)
+
+			translated, err := translateText(ctx, llmClient, text, sourceLang, targetLang)
+			if err != nil {
+				return nil, fmt.Errorf("translation: %w", err)
+			}
+			return translated, nil
+		},
+	)
+	logger.Debug("registered translate tool")
+}
+
+// translateText translates text with a plain completion call.
+func translateText(ctx context.Context, llm *LLMClient, text, sourceLang, targetLang string) (string, error) {
+	translated, err := llm.Complete(ctx, buildTranslationPrompt(sourceLang, targetLang), nil, text)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(translated), nil
+}
+
+// buildTranslationPrompt builds the system prompt for a translation call.
+func buildTranslationPrompt(sourceLang, targetLang string) string {
+	source := sourceLang
+	if source == "" {
+		source = "the detected language"
+	}
+	return fmt.Sprintf("You are a translator. Translate the user's message from %s to %s. "+
+		"Preserve tone, formatting, emoji, code blocks, and proper names. "+
+		"Output ONLY the translation, with no explanations or quotes.", source, targetLang)
+}
+
+// applyInboundTranslation translates an incoming group message into the
+// agent's language when the group has auto-translate configured. Fails open:
+// on error the original content is processed as-is.
+func (a *Assistant) applyInboundTranslation(groupJID, userContent string, logger *slog.Logger) string {
+	at := a.autoTranslateFor(groupJID)
+	if at == nil || strings.TrimSpace(userContent) == "" {
+		return userContent
+	}
+
+	translated, err := translateText(a.ctx, a.llmClient, userContent, at.GroupLanguage, at.AgentLanguage)
+	if err != nil {
+		logger.Warn("inbound auto-translation failed, using original", "error", err)
+		return userContent
+	}
+
+	logger.Debug("group message auto-translated",
+		"from", at.GroupLanguage,
+		"to", at.AgentLanguage,
+	)
+	return fmt.Sprintf("[auto-translated from %s — reply in %s, it will be translated back]\n%s",
+		at.GroupLanguage, at.AgentLanguage, translated)
+}
+
+// applyOutboundTranslation translates the agent's reply back into the group's
+// language. With dual_post enabled, both versions are returned so the group
+// sees the original and the translation. Fails open on error.
+func (a *Assistant) applyOutboundTranslation(groupJID, response string, logger *slog.Logger) string {
+	at := a.autoTranslateFor(groupJID)
+	if at == nil || strings.TrimSpace(response) == "" {
+		return response
+	}
+
+	translated, err := translateText(a.ctx, a.llmClient, response, at.AgentLanguage, at.GroupLanguage)
+	if err != nil {
+		logger.Warn("outbound auto-translation failed, sending original", "error", err)
+		return response
+	}
+
+	if at.DualPost {
+		return formatDualReply(response, translated)
+	}
+	return translated
+}
+
+// autoTranslateFor returns the auto-translate config for a group, or nil when
+// auto-translation doesn't apply (no policy manager, no LLM, or not enabled).
+func (a *Assistant) autoTranslateFor(groupJID string) *AutoTranslateConfig {
+	if a.groupPolicyMgr == nil || a.llmClient == nil {
+		return nil
+	}
+	return a.groupPolicyMgr.GetAutoTranslate(groupJID)
+}
+
+// formatDualReply joins the original reply and its translation for dual-post.
+func formatDualReply(original, translated string) string {
+	return original + "\n\n───\n\n" + translated
+}
@@ -0,0 +1,81 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBuildTranslationPrompt(t *testing.T) {
+	p := buildTranslationPrompt("Portuguese", "English")
+	if !strings.Contains(p, "from Portuguese to English") {
+		t.Errorf("language pair missing from prompt: %q", p)
+	}
+
+	p = buildTranslationPrompt("", "Spanish")
+	if !strings.Contains(p, "from the detected language to Spanish") {
+		t.Errorf("auto-detect fallback missing: %q", p)
+	}
+}
+
+func TestFormatDualReply(t *testing.T) {
+	out := formatDualReply("hello", "olá")
+	if !strings.HasPrefix(out, "hello") || !strings.HasSuffix(out, "olá") {
+		t.Errorf("unexpected dual reply: %q", out)
+	}
+}
+
+func TestGroupPolicyManager_GetAutoTranslate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	cfg := GroupsPolicyConfig{
+		DefaultPolicy: GroupPolicyOpen,
+		Groups: []GroupPolicyConfig{
+			{
+				ID: "120363intl@g.us",
+				AutoTranslate: &AutoTranslateConfig{
+					Enabled:       true,
+					GroupLanguage: "Portuguese",
+					AgentLanguage: "English",
+					DualPost:      true,
+				},
+			},
+			{
+				ID: "120363off@g.us",
+				AutoTranslate: &AutoTranslateConfig{
+					Enabled:       false,
+					GroupLanguage: "Portuguese",
+					AgentLanguage: "English",
+				},
+			},
+			{
+				ID: "120363partial@g.us",
+				AutoTranslate: &AutoTranslateConfig{
+					Enabled:       true,
+					GroupLanguage: "Portuguese",
+					// AgentLanguage missing: incomplete pair.
+				},
+			},
+		},
+	}
+	m := NewGroupPolicyManager(cfg, logger)
+
+	at := m.GetAutoTranslate("120363intl@g.us")
+	if at == nil {
+		t.Fatal("expected auto-translate config for configured group")
+	}
+	if at.GroupLanguage != "Portuguese" || at.AgentLanguage != "English" || !at.DualPost {
+		t.Errorf("unexpected config: %+v", at)
+	}
+
+	if m.GetAutoTranslate("120363off@g.us") != nil {
+		t.Error("disabled auto-translate should return nil")
+	}
+	if m.GetAutoTranslate("120363partial@g.us") != nil {
+		t.Error("incomplete language pair should return nil")
+	}
+	if m.GetAutoTranslate("120363unknown@g.us") != nil {
+		t.Error("unconfigured group should return nil")
+	}
+}
@@ -0,0 +1,543 @@
+// Package copilot – triggers.go implements event-driven triggers: local
+// events (a file changed, a port went up or down, a managed daemon exited)
+// enqueue an agent prompt into the session that created the trigger. File
+// events use fsnotify; port and daemon checks poll, piggybacking on the
+// DaemonManager for process state.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"log/slog"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// triggerPollInterval is how often port and daemon triggers are checked.
+	triggerPollInterval = 5 * time.Second
+
+	// triggerDefaultDebounce suppresses repeat fires of the same trigger
+	// within this window (editors often write files several times in a row).
+	triggerDefaultDebounce = 2 * time.Second
+
+	// triggerDialTimeout bounds the TCP probe for port triggers.
+	triggerDialTimeout = 2 * time.Second
+
+	// maxTriggers caps how many triggers can be registered at once.
+	maxTriggers = 50
+)
+
+// Trigger is a registered event → prompt binding.
+type Trigger struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // "file", "port", "daemon"
+
+	// Path is the file or directory to watch (type "file").
+	Path string `json:"path,omitempty"`
+
+	// Host and Port identify the TCP endpoint to probe (type "port").
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+
+	// Event selects which port transition fires: "up" or "down" (type "port").
+	Event string `json:"event,omitempty"`
+
+	// Label is the managed daemon to watch for exit (type "daemon").
+	Label string `json:"label,omitempty"`
+
+	// Prompt is the agent prompt enqueued when the trigger fires.
+	Prompt string `json:"prompt"`
+
+	// SessionID, Channel and ChatID route the prompt back to the
+	// conversation that created the trigger.
+	SessionID string `json:"session_id"`
+	Channel   string `json:"channel,omitempty"`
+	ChatID    string `json:"chat_id,omitempty"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	LastFiredAt *time.Time `json:"last_fired_at,omitempty"`
+	FireCount   int        `json:"fire_count"`
+
+	// portUp tracks the last observed port state (type "port").
+	portUp bool
+	// portChecked is true once the initial port state has been sampled.
+	portChecked bool
+	// daemonSeen tracks the last observed daemon status (type "daemon").
+	daemonSeen string
+}
+
+// TriggerManager owns the registered triggers and the watch loops behind
+// them. Fired triggers are delivered through the notify callback, which
+// enqueues the prompt as a followup message into the originating session.
+type TriggerManager struct {
+	mu       sync.RWMutex
+	triggers map[string]*Trigger
+
+	daemonMgr *DaemonManager
+	notify    func(sessionID, channel, chatID, content string)
+	logger    *slog.Logger
+
+	watcher *fsnotify.Watcher
+	stopCh  chan struct{}
+	started bool
+}
+
+// NewTriggerManager creates a trigger manager. Watch loops start lazily on
+// the first Add.
+func NewTriggerManager(dm *DaemonManager, notify func(sessionID, channel, chatID, content string), logger *slog.Logger) *TriggerManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TriggerManager{
+		triggers:  make(map[string]*Trigger),
+		daemonMgr: dm,
+		notify:    notify,
+		logger:    logger.With("component", "triggers"),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Add registers a trigger and starts watching its event source.
+func (tm *TriggerManager) Add(t *Trigger) error {
+	if t.ID == "" || t.Prompt == "" {
+		return fmt.Errorf("trigger id and prompt are required")
+	}
+
+	switch t.Type {
+	case "file":
+		if t.Path == "" {
+			return fmt.Errorf("file trigger requires a path")
+		}
+		abs, err := filepath.Abs(t.Path)
+		if err != nil {
+			return fmt.Errorf("resolving trigger path: %w", err)
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return fmt.Errorf("trigger path: %w", err)
+		}
+		t.Path = abs
+	case "port":
+		if t.Port <= 0 || t.Port > 65535 {
+			return fmt.Errorf("port trigger requires a valid port (got %d)", t.Port)
+		}
+		if t.Host == "" {
+			t.Host = "127.0.0.1"
+		}
+		switch t.Event {
+		case "":
+			t.Event = "down"
+		case "up", "down":
+		default:
+			return fmt.Errorf("port trigger event must be \"up\" or \"down\" (got %q)", t.Event)
+		}
+	case "daemon":
+		if t.Label == "" {
+			return fmt.Errorf("daemon trigger requires a daemon label")
+		}
+		if tm.daemonMgr == nil {
+			return fmt.Errorf("daemon manager not available")
+		}
+	default:
+		return fmt.Errorf("unknown trigger type %q (want file, port, or daemon)", t.Type)
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if _, exists := tm.triggers[t.ID]; exists {
+		return fmt.Errorf("trigger %q already exists", t.ID)
+	}
+	if len(tm.triggers) >= maxTriggers {
+		return fmt.Errorf("too many triggers (max %d)", maxTriggers)
+	}
+
+	if t.Type == "file" {
+		if err := tm.ensureWatcherLocked(); err != nil {
+			return err
+		}
+		// Watch the parent directory for single files so the watch survives
+		// the delete+rename dance most editors do on save.
+		watchPath := t.Path
+		if info, err := os.Stat(t.Path); err == nil && !info.IsDir() {
+			watchPath = filepath.Dir(t.Path)
+		}
+		if err := tm.watcher.Add(watchPath); err != nil {
+			return fmt.Errorf("watching %s: %w", watchPath, err)
+		}
+	}
+
+	t.CreatedAt = time.Now()
+	tm.triggers[t.ID] = t
+	tm.ensureLoopsLocked()
+
+	tm.logger.Info("trigger added", "id", t.ID, "type", t.Type)
+	return nil
+}
+
+// Remove unregisters a trigger. The underlying fsnotify watch is left in
+// place (harmless; events for paths without triggers are ignored).
+func (tm *TriggerManager) Remove(id string) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if _, ok := tm.triggers[id]; !ok {
+		return fmt.Errorf("trigger %q not found", id)
+	}
+	delete(tm.triggers, id)
+	tm.logger.Info("trigger removed", "id", id)
+	return nil
+}
+
+// List returns a snapshot of all registered triggers.
+func (tm *TriggerManager) List() []Trigger {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	result := make([]Trigger, 0, len(tm.triggers))
+	for _, t := range tm.triggers {
+		result = append(result, *t)
+	}
+	return result
+}
+
+// Stop shuts down all watch loops.
+func (tm *TriggerManager) Stop() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if !tm.started {
+		return
+	}
+	close(tm.stopCh)
+	if tm.watcher != nil {
+		tm.watcher.Close()
+		tm.watcher = nil
+	}
+	tm.started = false
+}
+
+// ensureWatcherLocked lazily creates the fsnotify watcher and its event
+// loop. Caller must hold tm.mu.
+func (tm *TriggerManager) ensureWatcherLocked() error {
+	if tm.watcher != nil {
+		return nil
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating file watcher: %w", err)
+	}
+	tm.watcher = w
+	go tm.fileLoop(w)
+	return nil
+}
+
+// ensureLoopsLocked starts the port/daemon poll loop once. Caller must hold tm.mu.
+func (tm *TriggerManager) ensureLoopsLocked() {
+	if tm.started {
+		return
+	}
+	tm.started = true
+	go tm.pollLoop()
+}
+
+// fileLoop dispatches fsnotify events to matching file triggers.
+func (tm *TriggerManager) fileLoop(w *fsnotify.Watcher) {
+	for {
+		select {
+		case <-tm.stopCh:
+			return
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			tm.handleFileEvent(event)
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			tm.logger.Warn("file watcher error", "error", err)
+		}
+	}
+}
+
+// handleFileEvent fires file triggers whose path matches the event.
+func (tm *TriggerManager) handleFileEvent(event fsnotify.Event) {
+	tm.mu.RLock()
+	var matched []*Trigger
+	for _, t := range tm.triggers {
+		if t.Type != "file" {
+			continue
+		}
+		if event.Name == t.Path || strings.HasPrefix(event.Name, t.Path+string(os.PathSeparator)) {
+			matched = append(matched, t)
+		}
+	}
+	tm.mu.RUnlock()
+
+	for _, t := range matched {
+		tm.fire(t, fmt.Sprintf("file %s: %s", strings.ToLower(event.Op.String()), event.Name))
+	}
+}
+
+// pollLoop periodically checks port and daemon triggers.
+func (tm *TriggerManager) pollLoop() {
+	ticker := time.NewTicker(triggerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-tm.stopCh:
+			return
+		case <-ticker.C:
+			tm.checkPorts()
+			tm.checkDaemons()
+		}
+	}
+}
+
+// checkPorts probes each port trigger and fires on the configured transition.
+func (tm *TriggerManager) checkPorts() {
+	tm.mu.RLock()
+	var ports []*Trigger
+	for _, t := range tm.triggers {
+		if t.Type == "port" {
+			ports = append(ports, t)
+		}
+	}
+	tm.mu.RUnlock()
+
+	for _, t := range ports {
+		addr := net.JoinHostPort(t.Host, fmt.Sprintf("%d", t.Port))
+		conn, err := net.DialTimeout("tcp", addr, triggerDialTimeout)
+		up := err == nil
+		if conn != nil {
+			conn.Close()
+		}
+
+		tm.mu.Lock()
+		wasUp, checked := t.portUp, t.portChecked
+		t.portUp = up
+		t.portChecked = true
+		tm.mu.Unlock()
+
+		// The first sample only establishes the baseline.
+		if !checked || up == wasUp {
+			continue
+		}
+		if (t.Event == "up" && up) || (t.Event == "down" && !up) {
+			state := "unreachable"
+			if up {
+				state = "reachable"
+			}
+			tm.fire(t, fmt.Sprintf("port %s became %s", addr, state))
+		}
+	}
+}
+
+// checkDaemons fires daemon triggers when a watched daemon stops running.
+func (tm *TriggerManager) checkDaemons() {
+	if tm.daemonMgr == nil {
+		return
+	}
+
+	tm.mu.RLock()
+	var watches []*Trigger
+	for _, t := range tm.triggers {
+		if t.Type == "daemon" {
+			watches = append(watches, t)
+		}
+	}
+	tm.mu.RUnlock()
+
+	if len(watches) == 0 {
+		return
+	}
+
+	daemons := make(map[string]Daemon)
+	for _, d := range tm.daemonMgr.List() {
+		daemons[d.Label] = d
+	}
+
+	for _, t := range watches {
+		d, ok := daemons[t.Label]
+		if !ok {
+			continue
+		}
+
+		tm.mu.Lock()
+		prev := t.daemonSeen
+		t.daemonSeen = d.Status
+		tm.mu.Unlock()
+
+		if prev == "running" && d.Status != "running" {
+			event := fmt.Sprintf("daemon %q exited (status: %s, exit code: %d)", t.Label, d.Status, d.ExitCode)
+			if d.Error != "" {
+				event += ", error: " + d.Error
+			}
+			tm.fire(t, event)
+		}
+	}
+}
+
+// fire delivers a trigger: debounces repeat events, updates stats, and
+// enqueues the prompt into the originating session.
+func (tm *TriggerManager) fire(t *Trigger, event string) {
+	tm.mu.Lock()
+	now := time.Now()
+	if t.LastFiredAt != nil && now.Sub(*t.LastFiredAt) < triggerDefaultDebounce {
+		tm.mu.Unlock()
+		return
+	}
+	t.LastFiredAt = &now
+	t.FireCount++
+	sessionID, channel, chatID, prompt := t.SessionID, t.Channel, t.ChatID, t.Prompt
+	tm.mu.Unlock()
+
+	tm.logger.Info("trigger fired", "id", t.ID, "event", event)
+
+	if tm.notify == nil || sessionID == "" {
+		return
+	}
+	tm.notify(sessionID, channel, chatID,
+		fmt.Sprintf("[trigger:%s] %s\n\n%s", t.ID, event, prompt))
+}
+
+// ---------- Tool Registration ----------
+
+// RegisterTriggerTools registers event trigger management tools in the executor.
+func RegisterTriggerTools(executor *ToolExecutor, tm *TriggerManager) {
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "trigger_add",
+			Description: "React to a local event with an agent prompt. Types: 'file' (path modified — e.g. 'when the build output changes, summarize the diff'), 'port' (TCP port became reachable/unreachable), 'daemon' (managed daemon exited). The prompt is injected into this chat when the event fires.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id":     map[string]any{"type": "string", "description": "Unique identifier for this trigger"},
+					"type":   map[string]any{"type": "string", "enum": []string{"file", "port", "daemon"}, "description": "Event source to watch"},
+					"prompt": map[string]any{"type": "string", "description": "Agent prompt to run when the event fires"},
+					"path":   map[string]any{"type": "string", "description": "File or directory to watch (type 'file')"},
+					"host":   map[string]any{"type": "string", "description": "Host to probe (type 'port', default: 127.0.0.1)"},
+					"port":   map[string]any{"type": "integer", "description": "TCP port to probe (type 'port')"},
+					"event":  map[string]any{"type": "string", "enum": []string{"up", "down"}, "description": "Which port transition fires (type 'port', default: down)"},
+					"label":  map[string]any{"type": "string", "description": "Daemon label to watch for exit (type 'daemon')"},
+				},
+				"required": []string{"id", "type", "prompt"},
+			}),
+		},
+	}, func(ctx context.Context, args map[string]any) (any, error) {
+		id, _ := args["id"].(string)
+		triggerType, _ := args["type"].(string)
+		prompt, _ := args["prompt"].(string)
+		path, _ := args["path"].(string)
+		host, _ := args["host"].(string)
+		port, _ := args["port"].(float64)
+		event, _ := args["event"].(string)
+		label, _ := args["label"].(string)
+
+		// Capture the session and delivery target now: the run context is
+		// cancelled when this agent run finishes, but the trigger lives on.
+		target := DeliveryTargetFromContext(ctx)
+		t := &Trigger{
+			ID:        id,
+			Type:      triggerType,
+			Path:      path,
+			Host:      host,
+			Port:      int(port),
+			Event:     event,
+			Label:     label,
+			Prompt:    prompt,
+			SessionID: SessionIDFromContext(ctx),
+			Channel:   target.Channel,
+			ChatID:    target.ChatID,
+		}
+		if err := tm.Add(t); err != nil {
+			return nil, err
+		}
+
+		detail := ""
+		switch t.Type {
+		case "file":
+			detail = t.Path
+		case "port":
+			detail = fmt.Sprintf("%s:%d (%s)", t.Host, t.Port, t.Event)
+		case "daemon":
+			detail = t.Label
+		}
+		return fmt.Sprintf("Trigger %q armed: %s %s. The prompt will run in this chat when the event fires. Remove with trigger_remove.",
+			t.ID, t.Type, detail), nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "trigger_list",
+			Description: "List all registered event triggers with their type, target, and fire count.",
+			Parameters: mustJSON(map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{},
+				"additionalProperties": false,
+			}),
+		},
+	}, func(_ context.Context, _ map[string]any) (any, error) {
+		triggers := tm.List()
+		if len(triggers) == 0 {
+			return "No triggers registered.", nil
+		}
+
+		table := NewTableResult("Event triggers", []string{"ID", "Type", "Target", "Fires", "Last Fired"})
+		for _, t := range triggers {
+			target := ""
+			switch t.Type {
+			case "file":
+				target = t.Path
+			case "port":
+				target = fmt.Sprintf("%s:%d (%s)", t.Host, t.Port, t.Event)
+			case "daemon":
+				target = t.Label
+			}
+			lastFired := ""
+			if t.LastFiredAt != nil {
+				lastFired = t.LastFiredAt.Format("2006-01-02 15:04")
+			}
+			table.AddRow(t.ID, t.Type, target, fmt.Sprintf("%d", t.FireCount), lastFired)
+		}
+		return table, nil
+	})
+
+	executor.Register(ToolDefinition{
+		Type: "function",
+		Function: FunctionDef{
+			Name:        "trigger_remove",
+			Description: "Remove a registered event trigger by its ID.",
+			Parameters: mustJSON(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id": map[string]any{"type": "string", "description": "The trigger ID to remove"},
+				},
+				"required": []string{"id"},
+			}),
+		},
+	}, func(_ context.Context, args map[string]any) (any, error) {
+		id, _ := args["id"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("id is required")
+		}
+		if err := tm.Remove(id); err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf("Trigger %q removed.", id), nil
+	})
+}
@@ -0,0 +1,115 @@
+package copilot
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTriggerAddValidation(t *testing.T) {
+	tm := NewTriggerManager(nil, nil, nil)
+	defer tm.Stop()
+
+	cases := []struct {
+		name    string
+		trigger *Trigger
+	}{
+		{"missing id", &Trigger{Type: "file", Path: "/tmp", Prompt: "p"}},
+		{"missing prompt", &Trigger{ID: "t1", Type: "file", Path: "/tmp"}},
+		{"unknown type", &Trigger{ID: "t1", Type: "webhook", Prompt: "p"}},
+		{"file without path", &Trigger{ID: "t1", Type: "file", Prompt: "p"}},
+		{"port out of range", &Trigger{ID: "t1", Type: "port", Port: 99999, Prompt: "p"}},
+		{"bad port event", &Trigger{ID: "t1", Type: "port", Port: 8080, Event: "sideways", Prompt: "p"}},
+		{"daemon without label", &Trigger{ID: "t1", Type: "daemon", Prompt: "p"}},
+		{"daemon without manager", &Trigger{ID: "t1", Type: "daemon", Label: "api", Prompt: "p"}},
+	}
+	for _, tc := range cases {
+		if err := tm.Add(tc.trigger); err == nil {
+			t.Errorf("%s: expected error, got nil", tc.name)
+		}
+	}
+}
+
+func TestTriggerAddDuplicate(t *testing.T) {
+	tm := NewTriggerManager(nil, nil, nil)
+	defer tm.Stop()
+
+	trigger := &Trigger{ID: "dup", Type: "port", Port: 8080, Prompt: "p"}
+	if err := tm.Add(trigger); err != nil {
+		t.Fatalf("first add failed: %v", err)
+	}
+	if err := tm.Add(&Trigger{ID: "dup", Type: "port", Port: 9090, Prompt: "p"}); err == nil {
+		t.Error("expected duplicate ID error, got nil")
+	}
+
+	if err := tm.Remove("dup"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if err := tm.Remove("dup"); err == nil {
+		t.Error("expected not-found error on second remove")
+	}
+}
+
+func TestTriggerFileEvent(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "build.log")
+	if err := os.WriteFile(target, []byte("initial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var fired []string
+	tm := NewTriggerManager(nil, func(sessionID, channel, chatID, content string) {
+		mu.Lock()
+		fired = append(fired, sessionID+"|"+content)
+		mu.Unlock()
+	}, nil)
+	defer tm.Stop()
+
+	trigger := &Trigger{
+		ID:        "build-watch",
+		Type:      "file",
+		Path:      target,
+		Prompt:    "summarize the diff",
+		SessionID: "test-session",
+	}
+	if err := tm.Add(trigger); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	if err := os.WriteFile(target, []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.After(3 * time.Second)
+	for {
+		mu.Lock()
+		n := len(fired)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("file trigger did not fire within 3s")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(fired) == 0 {
+		t.Fatal("no trigger fired")
+	}
+	first := fired[0]
+	if want := "test-session|"; first[:len(want)] != want {
+		t.Errorf("expected session prefix %q, got %q", want, first)
+	}
+
+	list := tm.List()
+	if len(list) != 1 || list[0].FireCount < 1 {
+		t.Errorf("expected trigger with fire count >= 1, got %+v", list)
+	}
+}
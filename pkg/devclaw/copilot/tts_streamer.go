@@ -0,0 +1,214 @@
+// Package copilot – tts_streamer.go implements sentence-level streaming TTS.
+// Instead of waiting for the full response (maybeSendTTS), flushed blocks from
+// the BlockStreamer are synthesized as they arrive and sent as short audio
+// segments, roughly halving perceived voice latency. Channels that handle
+// multi-part audio poorly can be listed in stream_single_file to receive one
+// concatenated file at the end instead.
+package copilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+	"github.com/jholhewres/devclaw/pkg/devclaw/tts"
+)
+
+// ttsSegmentTimeout bounds each per-segment synthesis call.
+const ttsSegmentTimeout = 30 * time.Second
+
+// maxTTSSegmentChars caps the text of a single segment; longer blocks are
+// truncated rather than split to keep the audio queue short.
+const maxTTSSegmentChars = 1024
+
+// ttsStreamer synthesizes flushed text blocks into audio as they arrive.
+// Like BlockStreamer, it is tied to a single message exchange.
+type ttsStreamer struct {
+	provider   tts.Provider
+	channelMgr *channels.Manager
+	channel    string
+	chatID     string
+	replyTo    string
+	voice      string
+	singleFile bool
+	logger     *slog.Logger
+
+	ctx   context.Context
+	queue chan string
+	wg    sync.WaitGroup
+
+	mu       sync.Mutex
+	segments [][]byte // collected audio for single-file mode
+	mimeType string
+	sent     bool // at least one segment was sent (or collected)
+	seq      int
+}
+
+// newTTSStreamer creates a streamer and starts its synthesis worker. Segments
+// are synthesized strictly in order so audio never arrives out of sequence.
+func newTTSStreamer(
+	ctx context.Context,
+	provider tts.Provider,
+	channelMgr *channels.Manager,
+	channel, chatID, replyTo, voice string,
+	singleFile bool,
+	logger *slog.Logger,
+) *ttsStreamer {
+	ts := &ttsStreamer{
+		provider:   provider,
+		channelMgr: channelMgr,
+		channel:    channel,
+		chatID:     chatID,
+		replyTo:    replyTo,
+		voice:      voice,
+		singleFile: singleFile,
+		logger:     logger,
+		ctx:        ctx,
+		queue:      make(chan string, 16),
+	}
+	ts.wg.Add(1)
+	go ts.worker()
+	return ts
+}
+
+// Enqueue queues a flushed text block for synthesis. Non-blocking: if the
+// queue is full the block is dropped — the final concatenated audio or text
+// reply still carries the content.
+func (ts *ttsStreamer) Enqueue(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if len(text) > maxTTSSegmentChars {
+		text = text[:maxTTSSegmentChars-3] + "..."
+	}
+	select {
+	case ts.queue <- text:
+	default:
+		ts.logger.Debug("TTS segment queue full, dropping block", "chars", len(text))
+	}
+}
+
+// Finish drains the queue, waits for in-flight synthesis, and in single-file
+// mode sends the concatenated audio. Returns after all audio is out.
+func (ts *ttsStreamer) Finish() {
+	close(ts.queue)
+	ts.wg.Wait()
+
+	if !ts.singleFile {
+		return
+	}
+	ts.mu.Lock()
+	segments := ts.segments
+	mimeType := ts.mimeType
+	ts.segments = nil
+	ts.mu.Unlock()
+	if len(segments) == 0 {
+		return
+	}
+
+	// MP3 frames and chained Ogg streams both tolerate byte concatenation,
+	// which covers the formats our providers emit.
+	var audio []byte
+	for _, seg := range segments {
+		audio = append(audio, seg...)
+	}
+	ts.sendAudio(audio, mimeType, "response.ogg")
+}
+
+// SentAudio reports whether at least one segment was synthesized, so the
+// caller can fall back to full-response TTS when streaming produced nothing.
+func (ts *ttsStreamer) SentAudio() bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.sent
+}
+
+// worker synthesizes queued blocks in order until the queue is closed.
+func (ts *ttsStreamer) worker() {
+	defer ts.wg.Done()
+	for text := range ts.queue {
+		if ts.ctx.Err() != nil {
+			return
+		}
+		synthCtx, cancel := context.WithTimeout(ts.ctx, ttsSegmentTimeout)
+		audio, mimeType, err := ts.provider.Synthesize(synthCtx, text, ts.voice)
+		cancel()
+		if err != nil {
+			ts.logger.Warn("streaming TTS synthesis failed", "error", err)
+			continue
+		}
+
+		ts.mu.Lock()
+		ts.sent = true
+		ts.mimeType = mimeType
+		ts.seq++
+		seq := ts.seq
+		if ts.singleFile {
+			ts.segments = append(ts.segments, audio)
+			ts.mu.Unlock()
+			continue
+		}
+		ts.mu.Unlock()
+
+		ts.sendAudio(audio, mimeType, fmt.Sprintf("response-%d.ogg", seq))
+	}
+}
+
+// sendAudio delivers one audio payload to the channel.
+func (ts *ttsStreamer) sendAudio(audio []byte, mimeType, filename string) {
+	media := &channels.MediaMessage{
+		Type:     channels.MessageAudio,
+		Data:     audio,
+		MimeType: mimeType,
+		Filename: filename,
+		ReplyTo:  ts.replyTo,
+	}
+	if err := ts.channelMgr.SendMedia(ts.ctx, ts.channel, ts.chatID, media); err != nil {
+		ts.logger.Warn("failed to send TTS audio segment", "error", err)
+	}
+}
+
+// wantsStreamingTTS reports whether this exchange should stream audio
+// segments, mirroring the auto-mode gate in maybeSendTTS.
+func (a *Assistant) wantsStreamingTTS(msg *channels.IncomingMessage) bool {
+	if a.ttsProvider == nil {
+		return false
+	}
+	wsID := a.workspaceMgr.WorkspaceIDFor(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	if !a.flagEnabled(FlagStreamingTTS, wsID) {
+		return false
+	}
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+	if !a.config.TTS.Streaming {
+		return false
+	}
+	switch a.config.TTS.AutoMode {
+	case "always":
+		return true
+	case "inbound":
+		return msg.Type == channels.MessageAudio
+	default:
+		return false
+	}
+}
+
+// newTTSStreamerFor builds a streamer for this exchange from the TTS config.
+func (a *Assistant) newTTSStreamerFor(msg *channels.IncomingMessage) *ttsStreamer {
+	a.configMu.RLock()
+	voice := a.config.TTS.Voice
+	singleFile := false
+	for _, ch := range a.config.TTS.StreamSingleFile {
+		if ch == msg.Channel {
+			singleFile = true
+			break
+		}
+	}
+	a.configMu.RUnlock()
+	return newTTSStreamer(a.ctx, a.ttsProvider, a.channelMgr, msg.Channel, msg.ChatID, msg.ID, voice, singleFile, a.logger)
+}
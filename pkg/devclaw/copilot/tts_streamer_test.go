@@ -0,0 +1,93 @@
+package copilot
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// fakeTTSProvider records synthesis calls and echoes the text as audio bytes.
+type fakeTTSProvider struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeTTSProvider) Synthesize(_ context.Context, text, _ string) ([]byte, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, text)
+	return []byte(text), "audio/mpeg", nil
+}
+
+func (f *fakeTTSProvider) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]string, len(f.calls))
+	copy(out, f.calls)
+	return out
+}
+
+func newTestTTSStreamer(t *testing.T, provider *fakeTTSProvider, singleFile bool) *ttsStreamer {
+	t.Helper()
+	// The manager has no registered channels, so sends fail and are logged —
+	// synthesis behavior is what these tests exercise.
+	mgr := channels.NewManager(slog.Default())
+	return newTTSStreamer(context.Background(), provider, mgr, "test", "chat1", "msg1", "nova", singleFile, slog.Default())
+}
+
+func TestTTSStreamerSynthesizesInOrder(t *testing.T) {
+	provider := &fakeTTSProvider{}
+	ts := newTestTTSStreamer(t, provider, true)
+
+	ts.Enqueue("First sentence.")
+	ts.Enqueue("Second sentence.")
+	ts.Enqueue("   ") // Blank blocks are ignored.
+	ts.Finish()
+
+	calls := provider.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 synthesis calls, got %d: %v", len(calls), calls)
+	}
+	if calls[0] != "First sentence." || calls[1] != "Second sentence." {
+		t.Errorf("segments out of order: %v", calls)
+	}
+	if !ts.SentAudio() {
+		t.Error("SentAudio() = false after synthesis")
+	}
+}
+
+func TestTTSStreamerTruncatesLongBlocks(t *testing.T) {
+	provider := &fakeTTSProvider{}
+	ts := newTestTTSStreamer(t, provider, true)
+
+	ts.Enqueue(strings.Repeat("a", maxTTSSegmentChars*2))
+	ts.Finish()
+
+	calls := provider.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	if len(calls[0]) > maxTTSSegmentChars {
+		t.Errorf("segment not truncated: %d chars", len(calls[0]))
+	}
+	if !strings.HasSuffix(calls[0], "...") {
+		t.Errorf("truncation marker missing: %q", calls[0][len(calls[0])-10:])
+	}
+}
+
+func TestTTSStreamerNoAudio(t *testing.T) {
+	provider := &fakeTTSProvider{}
+	ts := newTestTTSStreamer(t, provider, false)
+	ts.Finish()
+
+	if ts.SentAudio() {
+		t.Error("SentAudio() = true without any segments")
+	}
+	if len(provider.Calls()) != 0 {
+		t.Errorf("unexpected synthesis calls: %v", provider.Calls())
+	}
+}
@@ -0,0 +1,606 @@
+// Package copilot – url_watch.go implements the web page change monitor.
+// watch_url snapshots a page (optionally a single element picked by a simple
+// CSS selector), the scheduler re-checks it on an interval, and the session
+// is notified when the page changed by more than the watch's threshold —
+// price drops, status page updates, restock announcements.
+//
+// Snapshots and per-check history live in the central devclaw.db
+// (url_watches / url_watch_checks).
+package copilot
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/scheduler"
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot/security"
+)
+
+// urlWatchJobPrefix namespaces the scheduler jobs owned by url watches.
+const urlWatchJobPrefix = "url-watch-"
+
+// urlWatchDefaultInterval is used when watch_url gets no interval.
+const urlWatchDefaultInterval = "30m"
+
+// urlWatchMaxContent caps how much extracted text a snapshot stores.
+const urlWatchMaxContent = 100 << 10 // 100 KB
+
+// urlWatchExcerptLen bounds the old/new excerpts in change reports.
+const urlWatchExcerptLen = 400
+
+// URLWatch is one monitored page.
+type URLWatch struct {
+	ID           string  `json:"id"`
+	URL          string  `json:"url"`
+	Selector     string  `json:"selector,omitempty"`
+	Label        string  `json:"label,omitempty"`
+	ThresholdPct float64 `json:"threshold_pct"`
+	Interval     string  `json:"interval"`
+	Channel      string  `json:"channel,omitempty"`
+	ChatID       string  `json:"chat_id,omitempty"`
+	LastHash     string  `json:"last_hash,omitempty"`
+	LastContent  string  `json:"-"`
+	LastChecked  string  `json:"last_checked,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+// URLWatchStore persists watches and their check history in the central
+// database.
+type URLWatchStore struct {
+	db *sql.DB
+}
+
+// NewURLWatchStore creates a url watch store.
+func NewURLWatchStore(db *sql.DB) *URLWatchStore {
+	return &URLWatchStore{db: db}
+}
+
+// Save inserts a new watch.
+func (s *URLWatchStore) Save(w *URLWatch) error {
+	_, err := s.db.Exec(`
+		INSERT INTO url_watches (id, url, selector, label, threshold_pct, interval, channel, chat_id, last_hash, last_content, last_checked, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		w.ID, w.URL, w.Selector, w.Label, w.ThresholdPct, w.Interval,
+		w.Channel, w.ChatID, w.LastHash, w.LastContent, w.LastChecked, w.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("save url watch: %w", err)
+	}
+	return nil
+}
+
+// Get loads a watch by ID.
+func (s *URLWatchStore) Get(id string) (*URLWatch, error) {
+	w := &URLWatch{}
+	err := s.db.QueryRow(`
+		SELECT id, url, selector, label, threshold_pct, interval, channel, chat_id, last_hash, last_content, last_checked, created_at
+		FROM url_watches WHERE id = ?`, id).
+		Scan(&w.ID, &w.URL, &w.Selector, &w.Label, &w.ThresholdPct, &w.Interval,
+			&w.Channel, &w.ChatID, &w.LastHash, &w.LastContent, &w.LastChecked, &w.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("watch %q not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load url watch: %w", err)
+	}
+	return w, nil
+}
+
+// List returns all watches, oldest first.
+func (s *URLWatchStore) List() ([]URLWatch, error) {
+	rows, err := s.db.Query(`
+		SELECT id, url, selector, label, threshold_pct, interval, channel, chat_id, last_hash, last_content, last_checked, created_at
+		FROM url_watches ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list url watches: %w", err)
+	}
+	defer rows.Close()
+
+	var watches []URLWatch
+	for rows.Next() {
+		var w URLWatch
+		if err := rows.Scan(&w.ID, &w.URL, &w.Selector, &w.Label, &w.ThresholdPct, &w.Interval,
+			&w.Channel, &w.ChatID, &w.LastHash, &w.LastContent, &w.LastChecked, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan url watch: %w", err)
+		}
+		watches = append(watches, w)
+	}
+	return watches, rows.Err()
+}
+
+// Remove deletes a watch and its history.
+func (s *URLWatchStore) Remove(id string) error {
+	if _, err := s.db.Exec(`DELETE FROM url_watch_checks WHERE watch_id = ?`, id); err != nil {
+		return fmt.Errorf("delete watch history: %w", err)
+	}
+	res, err := s.db.Exec(`DELETE FROM url_watches WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete url watch: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("watch %q not found", id)
+	}
+	return nil
+}
+
+// UpdateSnapshot stores the latest content/hash and records a history row.
+func (s *URLWatchStore) UpdateSnapshot(id, content, hash string, changed bool, changePct float64, checkErr string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	if checkErr == "" {
+		_, err := s.db.Exec(`
+			UPDATE url_watches SET last_content = ?, last_hash = ?, last_checked = ? WHERE id = ?`,
+			content, hash, now, id)
+		if err != nil {
+			return fmt.Errorf("update url watch: %w", err)
+		}
+	}
+	changedInt := 0
+	if changed {
+		changedInt = 1
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO url_watch_checks (watch_id, checked_at, changed, change_pct, error)
+		VALUES (?, ?, ?, ?, ?)`,
+		id, now, changedInt, changePct, checkErr)
+	if err != nil {
+		return fmt.Errorf("record watch check: %w", err)
+	}
+	return nil
+}
+
+// fetchWatchContent downloads a page and extracts its text, optionally
+// narrowed to a selector match.
+func fetchWatchContent(ctx context.Context, client *http.Client, url, selector string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "DevClaw/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("fetching page: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if err != nil {
+		return "", fmt.Errorf("reading page: %w", err)
+	}
+
+	html := string(body)
+	if selector != "" {
+		fragment, err := extractBySelector(html, selector)
+		if err != nil {
+			return "", err
+		}
+		html = fragment
+	}
+
+	text := normalizeWatchText(stripHTMLTags(html))
+	if len(text) > urlWatchMaxContent {
+		text = text[:urlWatchMaxContent]
+	}
+	return text, nil
+}
+
+// extractBySelector pulls out the first element matching a simple selector:
+// "#id" matches id="...", ".class" matches a class attribute containing the
+// class, and a bare name matches the first tag of that name. Nested tags of
+// the same name are balanced so the full element body is returned.
+func extractBySelector(html, selector string) (string, error) {
+	lower := strings.ToLower(html)
+
+	var attrPos int
+	switch {
+	case strings.HasPrefix(selector, "#"):
+		attrPos = findAttrValue(lower, "id", selector[1:])
+	case strings.HasPrefix(selector, "."):
+		attrPos = findAttrValue(lower, "class", selector[1:])
+	default:
+		attrPos = strings.Index(lower, "<"+strings.ToLower(selector))
+	}
+	if attrPos < 0 {
+		return "", fmt.Errorf("selector %q not found in page", selector)
+	}
+
+	// Back up to the element's opening "<".
+	start := strings.LastIndex(lower[:attrPos+1], "<")
+	if start < 0 {
+		return "", fmt.Errorf("selector %q: malformed HTML", selector)
+	}
+
+	// Tag name of the matched element.
+	nameEnd := start + 1
+	for nameEnd < len(lower) && !strings.ContainsRune(" \t\n>/", rune(lower[nameEnd])) {
+		nameEnd++
+	}
+	tag := lower[start+1 : nameEnd]
+	if tag == "" {
+		return "", fmt.Errorf("selector %q: malformed HTML", selector)
+	}
+
+	// Scan forward balancing nested tags of the same name.
+	depth := 0
+	pos := start
+	for pos < len(lower) {
+		open := strings.Index(lower[pos:], "<"+tag)
+		closing := strings.Index(lower[pos:], "</"+tag)
+		if closing < 0 {
+			break
+		}
+		if open >= 0 && open < closing {
+			depth++
+			pos += open + len(tag) + 1
+			continue
+		}
+		depth--
+		pos += closing + len(tag) + 2
+		if depth == 0 {
+			end := strings.Index(lower[pos:], ">")
+			if end < 0 {
+				break
+			}
+			return html[start : pos+end+1], nil
+		}
+	}
+
+	return "", fmt.Errorf("selector %q: unclosed element", selector)
+}
+
+// findAttrValue locates attr="...value..." in lowercased HTML and returns
+// the match position, or -1.
+func findAttrValue(lowerHTML, attr, value string) int {
+	value = strings.ToLower(value)
+	for _, quote := range []string{`"`, `'`} {
+		search := attr + "=" + quote
+		from := 0
+		for {
+			idx := strings.Index(lowerHTML[from:], search)
+			if idx < 0 {
+				break
+			}
+			idx += from
+			valStart := idx + len(search)
+			valEnd := strings.Index(lowerHTML[valStart:], quote)
+			if valEnd < 0 {
+				break
+			}
+			val := lowerHTML[valStart : valStart+valEnd]
+			if attr == "id" && val == value {
+				return idx
+			}
+			if attr == "class" {
+				for _, c := range strings.Fields(val) {
+					if c == value {
+						return idx
+					}
+				}
+			}
+			from = valStart + valEnd
+		}
+	}
+	return -1
+}
+
+// normalizeWatchText collapses whitespace so layout-only changes don't count
+// as content changes.
+func normalizeWatchText(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// watchChangePct measures how much of the content changed, as a percentage
+// of lines added or removed relative to the larger snapshot.
+func watchChangePct(oldText, newText string) float64 {
+	if oldText == newText {
+		return 0
+	}
+	oldLines := strings.Split(oldText, "\n")
+	newLines := strings.Split(newText, "\n")
+
+	counts := make(map[string]int, len(oldLines))
+	for _, l := range oldLines {
+		counts[l]++
+	}
+	common := 0
+	for _, l := range newLines {
+		if counts[l] > 0 {
+			counts[l]--
+			common++
+		}
+	}
+
+	total := len(oldLines)
+	if len(newLines) > total {
+		total = len(newLines)
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(total-common) / float64(total) * 100
+}
+
+// watchHash fingerprints a snapshot.
+func watchHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// changedExcerpt returns the first lines that differ between two snapshots,
+// bounded to urlWatchExcerptLen.
+func changedExcerpt(oldText, newText string) (string, string) {
+	oldLines := strings.Split(oldText, "\n")
+	newLines := strings.Split(newText, "\n")
+
+	oldSet := make(map[string]bool, len(oldLines))
+	for _, l := range oldLines {
+		oldSet[l] = true
+	}
+	newSet := make(map[string]bool, len(newLines))
+	for _, l := range newLines {
+		newSet[l] = true
+	}
+
+	var removed, added []string
+	for _, l := range oldLines {
+		if !newSet[l] {
+			removed = append(removed, l)
+		}
+	}
+	for _, l := range newLines {
+		if !oldSet[l] {
+			added = append(added, l)
+		}
+	}
+
+	return truncate(strings.Join(removed, "\n"), urlWatchExcerptLen),
+		truncate(strings.Join(added, "\n"), urlWatchExcerptLen)
+}
+
+// urlWatchCommand builds the agent prompt the scheduler fires for a watch.
+func urlWatchCommand(id, label string) string {
+	return fmt.Sprintf("Run the url_watch_check tool with id %q. If it reports no meaningful change, reply with NO_REPLY. Otherwise summarize what changed on %q for the user — call out price changes, availability, or status updates explicitly.", id, label)
+}
+
+// RegisterURLWatchTools registers the page change monitor tools in the
+// executor.
+func RegisterURLWatchTools(executor *ToolExecutor, store *URLWatchStore, sched *scheduler.Scheduler, ssrfGuard *security.SSRFGuard) {
+	if store == nil {
+		return
+	}
+
+	client := &http.Client{Timeout: 20 * time.Second}
+
+	checkURL := func(url string) error {
+		if ssrfGuard != nil {
+			return ssrfGuard.IsAllowed(url)
+		}
+		return nil
+	}
+
+	// watch_url
+	executor.Register(
+		MakeToolDefinition("watch_url", "Monitor a web page for changes: snapshots it now and re-checks on an interval, notifying this chat when it changes meaningfully (price drops, status updates). Optionally narrow to a CSS selector ('#id', '.class', or a tag name).", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"url": map[string]any{
+					"type":        "string",
+					"description": "The page URL to watch",
+				},
+				"selector": map[string]any{
+					"type":        "string",
+					"description": "Optional element to watch: '#price', '.status-banner', or a tag like 'h1'",
+				},
+				"label": map[string]any{
+					"type":        "string",
+					"description": "Short human label for notifications (default: the URL)",
+				},
+				"interval": map[string]any{
+					"type":        "string",
+					"description": "How often to check, e.g. '15m', '1h' (default: 30m)",
+				},
+				"threshold_pct": map[string]any{
+					"type":        "number",
+					"description": "Minimum percentage of the content that must change before notifying (default: 0 = any change)",
+				},
+			},
+			"required": []string{"url"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			url, _ := args["url"].(string)
+			selector, _ := args["selector"].(string)
+			label, _ := args["label"].(string)
+			interval, _ := args["interval"].(string)
+			thresholdPct, _ := args["threshold_pct"].(float64)
+
+			if url == "" {
+				return nil, fmt.Errorf("url is required")
+			}
+			if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+				url = "https://" + url
+			}
+			if err := checkURL(url); err != nil {
+				return nil, err
+			}
+			if interval == "" {
+				interval = urlWatchDefaultInterval
+			}
+			if label == "" {
+				label = url
+			}
+
+			content, err := fetchWatchContent(ctx, client, url, selector)
+			if err != nil {
+				return nil, fmt.Errorf("taking initial snapshot: %w", err)
+			}
+
+			dt := DeliveryTargetFromContext(ctx)
+			w := &URLWatch{
+				ID:           generateID(),
+				URL:          url,
+				Selector:     selector,
+				Label:        label,
+				ThresholdPct: thresholdPct,
+				Interval:     interval,
+				Channel:      dt.Channel,
+				ChatID:       dt.ChatID,
+				LastHash:     watchHash(content),
+				LastContent:  content,
+				LastChecked:  time.Now().UTC().Format(time.RFC3339),
+				CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+			}
+			if err := store.Save(w); err != nil {
+				return nil, err
+			}
+
+			if sched != nil {
+				job := &scheduler.Job{
+					ID:             urlWatchJobPrefix + w.ID,
+					Schedule:       interval,
+					Type:           "every",
+					Command:        urlWatchCommand(w.ID, label),
+					Channel:        dt.Channel,
+					ChatID:         dt.ChatID,
+					Enabled:        true,
+					IsolateSession: true,
+					Announce:       true,
+				}
+				if err := sched.Add(job); err != nil {
+					store.Remove(w.ID)
+					return nil, fmt.Errorf("scheduling watch: %w", err)
+				}
+			}
+
+			return fmt.Sprintf("Watching %s every %s (watch id %s). Snapshot taken: %d characters.", label, interval, w.ID, len(content)), nil
+		},
+	)
+
+	// url_watch_check
+	executor.Register(
+		MakeToolDefinition("url_watch_check", "Re-check a watched page now and report whether it changed beyond the watch's threshold. Used by the scheduled watch jobs; can also be called manually.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "The watch ID",
+				},
+			},
+			"required": []string{"id"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			id, _ := args["id"].(string)
+			w, err := store.Get(id)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkURL(w.URL); err != nil {
+				return nil, err
+			}
+
+			content, err := fetchWatchContent(ctx, client, w.URL, w.Selector)
+			if err != nil {
+				store.UpdateSnapshot(w.ID, "", "", false, 0, err.Error())
+				return nil, fmt.Errorf("checking %s: %w", w.Label, err)
+			}
+
+			hash := watchHash(content)
+			if hash == w.LastHash {
+				store.UpdateSnapshot(w.ID, content, hash, false, 0, "")
+				return fmt.Sprintf("No change on %s.", w.Label), nil
+			}
+
+			changePct := watchChangePct(w.LastContent, content)
+			changed := changePct >= w.ThresholdPct
+			if err := store.UpdateSnapshot(w.ID, content, hash, changed, changePct, ""); err != nil {
+				return nil, err
+			}
+
+			if !changed {
+				return fmt.Sprintf("No meaningful change on %s (%.1f%% changed, threshold %.1f%%).", w.Label, changePct, w.ThresholdPct), nil
+			}
+
+			removed, added := changedExcerpt(w.LastContent, content)
+			var b strings.Builder
+			b.WriteString(fmt.Sprintf("CHANGED: %s (%.1f%% of the content)\nURL: %s\n", w.Label, changePct, w.URL))
+			if removed != "" {
+				b.WriteString("\nRemoved:\n" + removed + "\n")
+			}
+			if added != "" {
+				b.WriteString("\nAdded:\n" + added + "\n")
+			}
+			return b.String(), nil
+		},
+	)
+
+	// url_watch_list
+	executor.Register(
+		MakeToolDefinition("url_watch_list", "List watched pages with their intervals, thresholds, and last check time.", map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{},
+			"additionalProperties": false,
+		}),
+		func(_ context.Context, _ map[string]any) (any, error) {
+			watches, err := store.List()
+			if err != nil {
+				return nil, err
+			}
+			if len(watches) == 0 {
+				return "No pages being watched. Add one with watch_url.", nil
+			}
+			table := NewTableResult("Watched pages", []string{"ID", "Label", "Selector", "Interval", "Threshold", "Last check"})
+			for _, w := range watches {
+				selector := w.Selector
+				if selector == "" {
+					selector = "-"
+				}
+				lastChecked := "-"
+				if t, err := time.Parse(time.RFC3339, w.LastChecked); err == nil {
+					lastChecked = t.Local().Format("02/01 15:04")
+				}
+				table.AddRow(w.ID, w.Label, selector, w.Interval, fmt.Sprintf("%.1f%%", w.ThresholdPct), lastChecked)
+			}
+			return table, nil
+		},
+	)
+
+	// url_watch_remove
+	executor.Register(
+		MakeToolDefinition("url_watch_remove", "Stop watching a page and remove its scheduled check.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "The watch ID to remove",
+				},
+			},
+			"required": []string{"id"},
+		}),
+		func(_ context.Context, args map[string]any) (any, error) {
+			id, _ := args["id"].(string)
+			if err := store.Remove(id); err != nil {
+				return nil, err
+			}
+			if sched != nil {
+				sched.Remove(urlWatchJobPrefix + id)
+			}
+			return fmt.Sprintf("Watch %s removed.", id), nil
+		},
+	)
+}
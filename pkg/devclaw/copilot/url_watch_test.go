@@ -0,0 +1,183 @@
+// Package copilot – url_watch_test.go contains unit tests for the page change monitor.
+package copilot
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestExtractBySelector(t *testing.T) {
+	html := `<html><body>
+		<div id="price"><span>$ 99.90</span></div>
+		<div class="status banner"><div>All systems <b>operational</b></div></div>
+		<h1>Product page</h1>
+	</body></html>`
+
+	cases := []struct {
+		selector string
+		want     string
+	}{
+		{"#price", "$ 99.90"},
+		{".banner", "All systems operational"},
+		{"h1", "Product page"},
+	}
+	for _, tc := range cases {
+		fragment, err := extractBySelector(html, tc.selector)
+		if err != nil {
+			t.Errorf("extractBySelector(%q) failed: %v", tc.selector, err)
+			continue
+		}
+		got := normalizeWatchText(stripHTMLTags(fragment))
+		if got != tc.want {
+			t.Errorf("extractBySelector(%q) = %q, want %q", tc.selector, got, tc.want)
+		}
+	}
+
+	if _, err := extractBySelector(html, "#missing"); err == nil {
+		t.Error("expected error for missing selector")
+	}
+}
+
+func TestExtractBySelectorNested(t *testing.T) {
+	// The matched div contains nested divs; extraction must balance them.
+	html := `<div class="outer"><div>a</div><div><div>b</div></div></div><div>after</div>`
+	fragment, err := extractBySelector(html, ".outer")
+	if err != nil {
+		t.Fatalf("extract failed: %v", err)
+	}
+	if strings.Contains(fragment, "after") {
+		t.Errorf("extraction overran the element: %q", fragment)
+	}
+	if !strings.Contains(fragment, "b") {
+		t.Errorf("extraction stopped early: %q", fragment)
+	}
+}
+
+func TestWatchChangePct(t *testing.T) {
+	if pct := watchChangePct("a\nb\nc", "a\nb\nc"); pct != 0 {
+		t.Errorf("identical content: expected 0%%, got %.1f%%", pct)
+	}
+	// One of three lines changed.
+	pct := watchChangePct("a\nb\nc", "a\nb\nd")
+	if pct < 33 || pct > 34 {
+		t.Errorf("expected ~33%%, got %.1f%%", pct)
+	}
+	if pct := watchChangePct("a\nb", "x\ny"); pct != 100 {
+		t.Errorf("fully changed: expected 100%%, got %.1f%%", pct)
+	}
+}
+
+func TestNormalizeWatchText(t *testing.T) {
+	in := "  Price:   $99  \n\n\n  In stock \t now \n"
+	want := "Price: $99\nIn stock now"
+	if got := normalizeWatchText(in); got != want {
+		t.Errorf("normalizeWatchText = %q, want %q", got, want)
+	}
+}
+
+func TestChangedExcerpt(t *testing.T) {
+	removed, added := changedExcerpt("price: $99\nin stock", "price: $79\nin stock")
+	if removed != "price: $99" {
+		t.Errorf("removed = %q", removed)
+	}
+	if added != "price: $79" {
+		t.Errorf("added = %q", added)
+	}
+}
+
+func testURLWatchDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	schema := `
+	CREATE TABLE IF NOT EXISTS url_watches (
+		id            TEXT PRIMARY KEY,
+		url           TEXT NOT NULL,
+		selector      TEXT DEFAULT '',
+		label         TEXT DEFAULT '',
+		threshold_pct REAL DEFAULT 0,
+		interval      TEXT DEFAULT '',
+		channel       TEXT DEFAULT '',
+		chat_id       TEXT DEFAULT '',
+		last_hash     TEXT DEFAULT '',
+		last_content  TEXT DEFAULT '',
+		last_checked  TEXT DEFAULT '',
+		created_at    TEXT NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS url_watch_checks (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		watch_id   TEXT NOT NULL,
+		checked_at TEXT NOT NULL,
+		changed    INTEGER DEFAULT 0,
+		change_pct REAL DEFAULT 0,
+		error      TEXT DEFAULT ''
+	);`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestURLWatchStore(t *testing.T) {
+	store := NewURLWatchStore(testURLWatchDB(t))
+
+	w := &URLWatch{
+		ID:           "w1",
+		URL:          "https://example.com/product",
+		Selector:     "#price",
+		Label:        "product price",
+		ThresholdPct: 5,
+		Interval:     "30m",
+		LastHash:     watchHash("price: $99"),
+		LastContent:  "price: $99",
+		CreatedAt:    "2026-08-30T00:00:00Z",
+	}
+	if err := store.Save(w); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	got, err := store.Get("w1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.URL != w.URL || got.ThresholdPct != 5 || got.LastContent != "price: $99" {
+		t.Errorf("unexpected watch: %+v", got)
+	}
+	if _, err := store.Get("nope"); err == nil {
+		t.Error("expected error for unknown watch")
+	}
+
+	if err := store.UpdateSnapshot("w1", "price: $79", watchHash("price: $79"), true, 100, ""); err != nil {
+		t.Fatalf("update snapshot: %v", err)
+	}
+	got, _ = store.Get("w1")
+	if got.LastContent != "price: $79" || got.LastChecked == "" {
+		t.Errorf("snapshot not updated: %+v", got)
+	}
+
+	// Failed checks keep the previous snapshot but are still recorded.
+	if err := store.UpdateSnapshot("w1", "", "", false, 0, "HTTP 503"); err != nil {
+		t.Fatalf("record failed check: %v", err)
+	}
+	got, _ = store.Get("w1")
+	if got.LastContent != "price: $79" {
+		t.Errorf("failed check clobbered snapshot: %+v", got)
+	}
+
+	watches, err := store.List()
+	if err != nil || len(watches) != 1 {
+		t.Fatalf("list: %v (%d watches)", err, len(watches))
+	}
+
+	if err := store.Remove("w1"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := store.Remove("w1"); err == nil {
+		t.Error("expected error removing missing watch")
+	}
+}
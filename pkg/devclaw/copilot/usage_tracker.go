@@ -207,22 +207,41 @@ func (u *UsageTracker) FormatGlobalUsage() string {
 }
 
 func formatSessionUsage(label string, su *SessionUsage) string {
-	var b string
 	if su.Requests == 0 {
-		b = fmt.Sprintf("*Usage (%s)*\n\nNo requests yet.", label)
-		return b
-	}
-	b = fmt.Sprintf("*Usage (%s)*\n\n", label)
-	b += fmt.Sprintf("Prompt tokens: %d\n", su.PromptTokens)
-	b += fmt.Sprintf("Completion tokens: %d\n", su.CompletionTokens)
-	b += fmt.Sprintf("Total tokens: %d\n", su.TotalTokens)
-	b += fmt.Sprintf("Requests: %d\n", su.Requests)
-	b += fmt.Sprintf("Est. cost: $%.4f\n", su.EstimatedCostUSD)
+		return fmt.Sprintf("*Usage (%s)*\n\nNo requests yet.", label)
+	}
+	return usageCard(label, su).RenderMarkdown()
+}
+
+// UsageStructured returns the typed usage report for a session, or nil when
+// no usage has been recorded. Channels can render it as a card.
+func (u *UsageTracker) UsageStructured(sessionID string) *StructuredResult {
+	su := u.GetSession(sessionID)
+	if su == nil {
+		return nil
+	}
+	return usageCard(sessionID, su)
+}
+
+// GlobalUsageStructured returns the typed global usage report.
+func (u *UsageTracker) GlobalUsageStructured() *StructuredResult {
+	return usageCard("global", u.GetGlobal())
+}
+
+// usageCard builds the card-kind structured report shared by the string
+// formatters and channel renderers.
+func usageCard(label string, su *SessionUsage) *StructuredResult {
+	card := NewCardResult(fmt.Sprintf("Usage (%s)", label))
+	card.AddField("Prompt tokens", fmt.Sprintf("%d", su.PromptTokens))
+	card.AddField("Completion tokens", fmt.Sprintf("%d", su.CompletionTokens))
+	card.AddField("Total tokens", fmt.Sprintf("%d", su.TotalTokens))
+	card.AddField("Requests", fmt.Sprintf("%d", su.Requests))
+	card.AddField("Est. cost", fmt.Sprintf("$%.4f", su.EstimatedCostUSD))
 	if !su.FirstRequestAt.IsZero() {
-		b += fmt.Sprintf("First request: %s\n", su.FirstRequestAt.Format("2006-01-02 15:04"))
+		card.AddField("First request", su.FirstRequestAt.Format("2006-01-02 15:04"))
 	}
 	if !su.LastRequestAt.IsZero() {
-		b += fmt.Sprintf("Last request: %s", su.LastRequestAt.Format("2006-01-02 15:04"))
+		card.AddField("Last request", su.LastRequestAt.Format("2006-01-02 15:04"))
 	}
-	return b
+	return card
 }
@@ -0,0 +1,241 @@
+// Package copilot – verify.go implements an optional fact-check pass: a second
+// model call cross-checks the draft answer against the sources retrieved
+// during the run and flags unsupported claims before the reply is sent.
+// It trades latency for reliability, so it is off by default and meant for
+// high-stakes workspaces (config) or on-demand use (/verify).
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// VerifyConfig configures the fact-check verification pass.
+type VerifyConfig struct {
+	// Enabled turns the pass on for all workspaces (default: false).
+	Enabled bool `yaml:"enabled"`
+
+	// Workspaces always run the pass regardless of Enabled.
+	Workspaces []string `yaml:"workspaces"`
+
+	// Model overrides the default model for the verification call.
+	Model string `yaml:"model"`
+
+	// TimeoutSeconds bounds the verification call (default: 30). On timeout
+	// the draft is sent unmodified.
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+
+	// MaxSourceChars caps how much retrieved source text is sent to the
+	// verifier (default: 8000).
+	MaxSourceChars int `yaml:"max_source_chars"`
+}
+
+// DefaultVerifyConfig returns default verification configuration.
+func DefaultVerifyConfig() VerifyConfig {
+	return VerifyConfig{
+		Enabled:        false,
+		TimeoutSeconds: 30,
+		MaxSourceChars: 8000,
+	}
+}
+
+// enabledFor reports whether the pass is always-on for a workspace.
+func (c VerifyConfig) enabledFor(workspaceID string) bool {
+	if c.Enabled {
+		return true
+	}
+	for _, ws := range c.Workspaces {
+		if ws == workspaceID {
+			return true
+		}
+	}
+	return false
+}
+
+// verifySources collects external content retrieved during a run so the
+// verifier can cross-check the draft against it. Created per-run, like
+// runCritic, to avoid cross-session races.
+type verifySources struct {
+	mu      sync.Mutex
+	sources []string
+}
+
+func newVerifySources() *verifySources { return &verifySources{} }
+
+// maxVerifySources caps how many tool results are kept per run.
+const maxVerifySources = 10
+
+// Record keeps web tool results; everything else is ignored.
+func (v *verifySources) Record(name string, result ToolResult) {
+	if result.Error != nil {
+		return
+	}
+	switch name {
+	case "web_search", "web_fetch":
+	default:
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.sources) >= maxVerifySources {
+		return
+	}
+	v.sources = append(v.sources, result.Content)
+}
+
+// Sources returns a copy of the collected source texts.
+func (v *verifySources) Sources() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	out := make([]string, len(v.sources))
+	copy(out, v.sources)
+	return out
+}
+
+const verifySystemPrompt = `You are a fact-checker. Compare the draft answer against the retrieved sources.
+Flag only claims that the sources contradict or that assert specific facts the sources do not support.
+General knowledge, opinions, and instructions do not need source support.
+Respond with JSON only:
+{"verdict": "supported" | "mixed" | "unsupported", "claims": ["unsupported claim 1", ...]}
+Use "supported" with an empty claims list when the draft is consistent with the sources.`
+
+// verification is the parsed result of a fact-check call.
+type verification struct {
+	Verdict string   `json:"verdict"`
+	Claims  []string `json:"claims"`
+}
+
+// verifyDraft runs the fact-check pass and returns the draft, annotated with
+// a warning note when unsupported claims were found. Any failure (timeout,
+// unparseable output) sends the draft unmodified — verification must never
+// eat a reply.
+func (a *Assistant) verifyDraft(ctx context.Context, draft string, sources []string) string {
+	if a.llmClient == nil || len(sources) == 0 || strings.TrimSpace(draft) == "" {
+		return draft
+	}
+	cfg := a.config.Verify
+
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = time.Duration(DefaultVerifyConfig().TimeoutSeconds) * time.Second
+	}
+	verifyCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	maxChars := cfg.MaxSourceChars
+	if maxChars <= 0 {
+		maxChars = DefaultVerifyConfig().MaxSourceChars
+	}
+
+	var b strings.Builder
+	b.WriteString("Retrieved sources:\n")
+	perSource := maxChars / len(sources)
+	for i, src := range sources {
+		fmt.Fprintf(&b, "--- Source %d ---\n%s\n", i+1, truncate(src, perSource))
+	}
+	fmt.Fprintf(&b, "\nDraft answer:\n%s\n", truncate(draft, 4000))
+
+	var output string
+	var err error
+	if cfg.Model != "" {
+		messages := buildCompletionMessages(verifySystemPrompt, nil, b.String())
+		var resp *LLMResponse
+		resp, err = a.llmClient.CompleteWithToolsUsingModel(verifyCtx, cfg.Model, messages, nil)
+		if resp != nil {
+			output = resp.Content
+		}
+	} else {
+		output, err = a.llmClient.CompleteUtility(verifyCtx, verifySystemPrompt, nil, b.String())
+	}
+	if err != nil {
+		a.logger.Warn("verification call failed, sending draft unmodified", "error", err)
+		return draft
+	}
+
+	result, err := parseVerification(output)
+	if err != nil {
+		a.logger.Debug("verification output not parseable", "error", err)
+		return draft
+	}
+	return annotateVerification(draft, result)
+}
+
+// parseVerification extracts the fact-check JSON from an LLM response,
+// tolerating surrounding text and markdown code fences.
+func parseVerification(s string) (verification, error) {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end <= start {
+		return verification{}, fmt.Errorf("no JSON object in verification output")
+	}
+	var result verification
+	if err := json.Unmarshal([]byte(s[start:end+1]), &result); err != nil {
+		return verification{}, fmt.Errorf("parsing verification: %w", err)
+	}
+	return result, nil
+}
+
+// maxFlaggedClaims caps how many claims are listed in the warning note.
+const maxFlaggedClaims = 5
+
+// annotateVerification appends a warning note for unsupported claims.
+// Supported drafts pass through untouched.
+func annotateVerification(draft string, result verification) string {
+	if len(result.Claims) == 0 || result.Verdict == "supported" {
+		return draft
+	}
+	var b strings.Builder
+	b.WriteString(draft)
+	b.WriteString("\n\n⚠️ Verification: the sources do not support ")
+	if len(result.Claims) == 1 {
+		b.WriteString("this claim:\n")
+	} else {
+		b.WriteString("these claims:\n")
+	}
+	for i, claim := range result.Claims {
+		if i >= maxFlaggedClaims {
+			fmt.Fprintf(&b, "- ... and %d more\n", len(result.Claims)-maxFlaggedClaims)
+			break
+		}
+		fmt.Fprintf(&b, "- %s\n", truncate(claim, 200))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// verifyCommand implements /verify: toggles the fact-check pass for this
+// session, on top of any workspace-level config.
+func (a *Assistant) verifyCommand(args []string, msg *channels.IncomingMessage) string {
+	resolved := a.workspaceMgr.Resolve(msg.Channel, msg.ChatID, msg.From, msg.IsGroup)
+	session := resolved.Session
+	cfg := session.GetConfig()
+
+	if len(args) == 0 {
+		mode := "off"
+		switch {
+		case cfg.Verify:
+			mode = "on (session)"
+		case a.config.Verify.enabledFor(resolved.Workspace.ID):
+			mode = "on (workspace)"
+		}
+		return fmt.Sprintf("Fact-check mode: %s", mode)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "on", "true", "1":
+		cfg.Verify = true
+		session.SetConfig(cfg)
+		return "Fact-check mode: on — answers are cross-checked against sources before sending (slower)."
+	case "off", "false", "0":
+		cfg.Verify = false
+		session.SetConfig(cfg)
+		return "Fact-check mode: off."
+	default:
+		return "Usage: /verify [on|off]"
+	}
+}
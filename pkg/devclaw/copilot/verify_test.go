@@ -0,0 +1,89 @@
+package copilot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestVerifyConfigEnabledFor(t *testing.T) {
+	cfg := VerifyConfig{Workspaces: []string{"work"}}
+	if !cfg.enabledFor("work") {
+		t.Error("listed workspace not enabled")
+	}
+	if cfg.enabledFor("personal") {
+		t.Error("unlisted workspace enabled")
+	}
+	cfg.Enabled = true
+	if !cfg.enabledFor("personal") {
+		t.Error("global enable ignored")
+	}
+}
+
+func TestVerifySourcesRecord(t *testing.T) {
+	v := newVerifySources()
+	v.Record("web_fetch", ToolResult{Content: "page body"})
+	v.Record("web_search", ToolResult{Content: "results"})
+	// Non-web tools and failed calls are ignored.
+	v.Record("run_command", ToolResult{Content: "ls output"})
+	v.Record("web_fetch", ToolResult{Content: "boom", Error: fmt.Errorf("timeout")})
+
+	sources := v.Sources()
+	if len(sources) != 2 {
+		t.Fatalf("expected 2 sources, got %d: %v", len(sources), sources)
+	}
+	if sources[0] != "page body" || sources[1] != "results" {
+		t.Errorf("unexpected sources: %v", sources)
+	}
+}
+
+func TestParseVerification(t *testing.T) {
+	output := "Here is my analysis:\n```json\n" +
+		`{"verdict": "mixed", "claims": ["the library was released in 2020"]}` +
+		"\n```"
+	result, err := parseVerification(output)
+	if err != nil {
+		t.Fatalf("parseVerification: %v", err)
+	}
+	if result.Verdict != "mixed" || len(result.Claims) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if _, err := parseVerification("no json here"); err == nil {
+		t.Error("expected error for non-JSON output")
+	}
+}
+
+func TestAnnotateVerification(t *testing.T) {
+	draft := "The library was released in 2020 and supports streaming."
+
+	// Supported drafts pass through untouched.
+	got := annotateVerification(draft, verification{Verdict: "supported"})
+	if got != draft {
+		t.Errorf("supported draft changed: %q", got)
+	}
+
+	got = annotateVerification(draft, verification{
+		Verdict: "mixed",
+		Claims:  []string{"the library was released in 2020"},
+	})
+	if !strings.Contains(got, draft) {
+		t.Errorf("draft missing from annotated reply:\n%s", got)
+	}
+	if !strings.Contains(got, "Verification") || !strings.Contains(got, "this claim") {
+		t.Errorf("warning note missing:\n%s", got)
+	}
+	if !strings.Contains(got, "- the library was released in 2020") {
+		t.Errorf("claim not listed:\n%s", got)
+	}
+
+	// Claim list is capped.
+	many := make([]string, maxFlaggedClaims+3)
+	for i := range many {
+		many[i] = fmt.Sprintf("claim %d", i)
+	}
+	got = annotateVerification(draft, verification{Verdict: "unsupported", Claims: many})
+	if !strings.Contains(got, "... and 3 more") {
+		t.Errorf("overflow note missing:\n%s", got)
+	}
+}
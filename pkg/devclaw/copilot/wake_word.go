@@ -0,0 +1,85 @@
+// Package copilot – wake_word.go implements spoken trigger evaluation for
+// voice notes. In groups, the trigger check runs on the placeholder text
+// ("[audio]") before transcription, so voice notes addressed to the bot were
+// never picked up. When a group voice note fails the text trigger, the audio
+// is transcribed first and the transcript is checked against the regular
+// trigger and a configurable spoken wake word ("hey claw").
+package copilot
+
+import (
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/channels"
+)
+
+// wakeWordWindow limits how deep into the transcript the wake word may
+// appear — it must lead the message, not be mentioned in passing.
+const wakeWordWindow = 60
+
+// evaluateSpokenTrigger transcribes a group voice note and re-evaluates the
+// trigger on the transcript, including the spoken wake word. Returns the
+// enriched content and whether the bot was addressed.
+func (a *Assistant) evaluateSpokenTrigger(msg *channels.IncomingMessage, trigger string, logger *slog.Logger) (string, bool) {
+	if !a.MediaConfig().TranscriptionEnabled {
+		return "", false
+	}
+	wakeWord := a.config.WakeWord
+	if trigger == "" && wakeWord == "" {
+		return "", false
+	}
+
+	enriched := a.enrichMessageContent(a.ctx, msg, logger)
+	if enriched == msg.Content {
+		return "", false // Transcription failed or produced nothing.
+	}
+
+	if a.matchesTrigger(enriched, trigger, msg.IsGroup) || matchesWakeWord(enriched, wakeWord) {
+		logger.Info("voice note matched spoken trigger", "transcript_len", len(enriched))
+		return enriched, true
+	}
+	return "", false
+}
+
+// matchesWakeWord reports whether the transcript starts with the spoken wake
+// word. Matching is case-insensitive and ignores punctuation ("Hey, Claw!"
+// matches "hey claw"), and the phrase must appear within the first
+// wakeWordWindow characters at a word boundary.
+func matchesWakeWord(content, wakeWord string) bool {
+	if wakeWord == "" {
+		return false
+	}
+	norm := normalizeSpoken(content)
+	phrase := normalizeSpoken(wakeWord)
+	if phrase == "" || norm == "" {
+		return false
+	}
+
+	window := norm
+	if len(window) > wakeWordWindow {
+		window = window[:wakeWordWindow]
+	}
+	idx := strings.Index(window, phrase)
+	if idx < 0 {
+		return false
+	}
+	// Word boundaries: normalized text only contains single spaces.
+	if idx > 0 && norm[idx-1] != ' ' {
+		return false
+	}
+	end := idx + len(phrase)
+	return end == len(norm) || norm[end] == ' '
+}
+
+// normalizeSpoken lowercases text and strips punctuation so transcripts
+// compare cleanly against the configured phrase.
+func normalizeSpoken(s string) string {
+	mapped := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(mapped), " ")
+}
@@ -0,0 +1,46 @@
+package copilot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchesWakeWord(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"hey claw, what's the weather?", true},
+		{"Hey, Claw! Remind me tomorrow.", true},
+		{"HEY CLAW turn off the lights", true},
+		{"ok everyone, hey claw, summarize this", true},
+		{"hey clawdia, how are you", false},
+		{"he said hey claw is a bot", true}, // within the leading window
+		{"completely unrelated voice note", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := matchesWakeWord(tt.content, "hey claw"); got != tt.want {
+			t.Errorf("matchesWakeWord(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+
+	// Beyond the leading window the phrase is a mention, not an address.
+	late := strings.Repeat("bla ", wakeWordWindow/4+2) + "hey claw do something"
+	if matchesWakeWord(late, "hey claw") {
+		t.Error("wake word deep in the transcript should not trigger")
+	}
+
+	if matchesWakeWord("hey claw", "") {
+		t.Error("empty wake word matched")
+	}
+}
+
+func TestNormalizeSpoken(t *testing.T) {
+	if got := normalizeSpoken("  Hey,   CLAW!  What's up? "); got != "hey claw what s up" {
+		t.Errorf("normalizeSpoken = %q", got)
+	}
+	if got := normalizeSpoken("?!..."); got != "" {
+		t.Errorf("punctuation-only input: %q", got)
+	}
+}
@@ -0,0 +1,184 @@
+// Package copilot – watchdog_alerts.go notifies the owner when the session
+// watchdog force-recovers a stuck session. The alert carries diagnostics
+// (which tool hung, for how long, the last LLM call) plus an aggregated
+// "flaky tools" section so operators can spot systemic issues instead of
+// isolated hangs. Configured under Routines.
+package copilot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WatchdogConfig configures owner alerts for watchdog force-recoveries.
+type WatchdogConfig struct {
+	// Alerts turns recovery notifications on/off.
+	Alerts bool `yaml:"alerts" json:"alerts"`
+
+	// Channel/ChatID override where alerts are delivered. Empty = the most
+	// recently active session.
+	Channel string `yaml:"channel" json:"channel"`
+	ChatID  string `yaml:"chat_id" json:"chat_id"`
+
+	// FlakyMinRecoveries is how often a tool must be caught hanging before
+	// it appears in the aggregated flaky-tools section (default 2).
+	FlakyMinRecoveries int `yaml:"flaky_min_recoveries" json:"flaky_min_recoveries"`
+}
+
+// DefaultWatchdogConfig returns defaults: alerts on, delivered to the most
+// recently active session.
+func DefaultWatchdogConfig() WatchdogConfig {
+	return WatchdogConfig{
+		Alerts:             true,
+		FlakyMinRecoveries: 2,
+	}
+}
+
+// watchdogReporter aggregates recovery events across the process lifetime.
+// It lives inside the single watchdog goroutine; the mutex only guards
+// against future concurrent readers.
+type watchdogReporter struct {
+	mu         sync.Mutex
+	recoveries int
+	startedAt  time.Time
+	toolHangs  map[string]int // tool name -> recoveries it was caught running in
+}
+
+func newWatchdogReporter() *watchdogReporter {
+	return &watchdogReporter{
+		startedAt: time.Now(),
+		toolHangs: make(map[string]int),
+	}
+}
+
+// recordRecovery registers one force-recovery and the tools that were
+// running when it happened.
+func (w *watchdogReporter) recordRecovery(running []RunningToolInfo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.recoveries++
+	seen := make(map[string]bool)
+	for _, info := range running {
+		if seen[info.Name] {
+			continue // Count each tool once per recovery.
+		}
+		seen[info.Name] = true
+		w.toolHangs[info.Name]++
+	}
+}
+
+// flakyTools returns "tool (N×)" entries for tools caught hanging in at
+// least minRecoveries recoveries, worst first.
+func (w *watchdogReporter) flakyTools(minRecoveries int) []string {
+	if minRecoveries <= 0 {
+		minRecoveries = 2
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	type hangCount struct {
+		name  string
+		count int
+	}
+	var flaky []hangCount
+	for name, count := range w.toolHangs {
+		if count >= minRecoveries {
+			flaky = append(flaky, hangCount{name, count})
+		}
+	}
+	sort.Slice(flaky, func(i, j int) bool {
+		if flaky[i].count != flaky[j].count {
+			return flaky[i].count > flaky[j].count
+		}
+		return flaky[i].name < flaky[j].name
+	})
+
+	out := make([]string, len(flaky))
+	for i, f := range flaky {
+		out[i] = fmt.Sprintf("%s (%d×)", f.name, f.count)
+	}
+	return out
+}
+
+// buildWatchdogAlert renders the owner notification for one force-recovery.
+func buildWatchdogAlert(sessionID string, maxBusy time.Duration, running []RunningToolInfo, lastModel string, lastCallAt time.Time, flaky []string, totalRecoveries int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "⚠️ *Watchdog: session force-recovered*\nSession %s was stuck in processing for over %s.",
+		sessionID, maxBusy.Round(time.Minute))
+
+	if len(running) > 0 {
+		b.WriteString("\n\nRunning tools at recovery:")
+		for _, info := range running {
+			fmt.Fprintf(&b, "\n- %s (running for %s)", info.Name, time.Since(info.StartedAt).Round(time.Second))
+		}
+	} else {
+		b.WriteString("\n\nNo tool was running — the hang was outside tool execution (LLM call or internal).")
+	}
+
+	if lastModel != "" {
+		fmt.Fprintf(&b, "\nLast LLM call: %s, %s ago.", lastModel, time.Since(lastCallAt).Round(time.Second))
+	} else {
+		b.WriteString("\nLast LLM call: none recorded.")
+	}
+
+	if len(flaky) > 0 {
+		fmt.Fprintf(&b, "\n\nFlaky tools (%d recoveries since start): %s", totalRecoveries, strings.Join(flaky, ", "))
+	}
+	return b.String()
+}
+
+// watchdogDeliveryTarget resolves where recovery alerts go: the config
+// override when set, otherwise the most recently active session.
+func (a *Assistant) watchdogDeliveryTarget(cfg WatchdogConfig) (channel, chatID string) {
+	if cfg.Channel != "" && cfg.ChatID != "" {
+		return cfg.Channel, cfg.ChatID
+	}
+	if a.workspaceMgr == nil {
+		return "", ""
+	}
+	var latest time.Time
+	for _, info := range a.workspaceMgr.ListAllSessions() {
+		if info.LastActiveAt.After(latest) && info.Channel != "" && info.ChatID != "" {
+			latest = info.LastActiveAt
+			channel = info.Channel
+			chatID = info.ChatID
+		}
+	}
+	return channel, chatID
+}
+
+// notifyWatchdogRecovery delivers a recovery alert to the owner. Failures
+// are logged; alerting must never interfere with the recovery itself.
+func (a *Assistant) notifyWatchdogRecovery(cfg WatchdogConfig, reporter *watchdogReporter, sessionID string, maxBusy time.Duration, running []RunningToolInfo) {
+	reporter.recordRecovery(running)
+	if !cfg.Alerts {
+		return
+	}
+
+	var lastModel string
+	var lastCallAt time.Time
+	if a.llmClient != nil {
+		lastModel, lastCallAt = a.llmClient.LastCall()
+	}
+
+	reporter.mu.Lock()
+	total := reporter.recoveries
+	reporter.mu.Unlock()
+	text := buildWatchdogAlert(sessionID, maxBusy, running, lastModel, lastCallAt,
+		reporter.flakyTools(cfg.FlakyMinRecoveries), total)
+
+	channel, chatID := a.watchdogDeliveryTarget(cfg)
+	if channel == "" || chatID == "" {
+		a.logger.Warn("watchdog alert has no delivery target", "session", sessionID)
+		return
+	}
+	// Urgent: recovery alerts bypass DND — they indicate the agent was broken.
+	if err := a.sendProactive(a.ctx, channel, chatID, text, true); err != nil {
+		a.logger.Warn("watchdog alert delivery failed",
+			"session", sessionID, "channel", channel, "error", err)
+	}
+}
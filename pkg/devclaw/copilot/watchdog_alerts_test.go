@@ -0,0 +1,88 @@
+// Package copilot – watchdog_alerts_test.go contains unit tests for the
+// watchdog recovery alerts.
+package copilot
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchdogReporterFlakyTools(t *testing.T) {
+	r := newWatchdogReporter()
+
+	r.recordRecovery([]RunningToolInfo{{Name: "bash"}, {Name: "web_fetch"}})
+	r.recordRecovery([]RunningToolInfo{{Name: "bash"}, {Name: "bash"}}) // duplicates count once
+	r.recordRecovery([]RunningToolInfo{{Name: "bash"}})
+
+	flaky := r.flakyTools(2)
+	if len(flaky) != 1 || flaky[0] != "bash (3×)" {
+		t.Errorf("unexpected flaky tools: %v", flaky)
+	}
+
+	// Threshold 1 includes the single web_fetch hang too, worst first.
+	flaky = r.flakyTools(1)
+	if len(flaky) != 2 || flaky[0] != "bash (3×)" || flaky[1] != "web_fetch (1×)" {
+		t.Errorf("unexpected flaky tools at threshold 1: %v", flaky)
+	}
+
+	if r.recoveries != 3 {
+		t.Errorf("expected 3 recoveries, got %d", r.recoveries)
+	}
+}
+
+func TestBuildWatchdogAlert(t *testing.T) {
+	running := []RunningToolInfo{{Name: "web_fetch", StartedAt: time.Now().Add(-30 * time.Minute)}}
+	alert := buildWatchdogAlert("abc123", 25*time.Minute, running,
+		"gpt-4o", time.Now().Add(-20*time.Minute), []string{"web_fetch (2×)"}, 2)
+
+	for _, want := range []string{
+		"force-recovered",
+		"abc123",
+		"25m0s",
+		"web_fetch (running for 30m0s)",
+		"Last LLM call: gpt-4o",
+		"Flaky tools (2 recoveries since start): web_fetch (2×)",
+	} {
+		if !strings.Contains(alert, want) {
+			t.Errorf("alert missing %q:\n%s", want, alert)
+		}
+	}
+}
+
+func TestBuildWatchdogAlertNoTool(t *testing.T) {
+	alert := buildWatchdogAlert("abc123", 25*time.Minute, nil, "", time.Time{}, nil, 1)
+	if !strings.Contains(alert, "No tool was running") {
+		t.Errorf("expected no-tool diagnostic, got:\n%s", alert)
+	}
+	if !strings.Contains(alert, "Last LLM call: none recorded") {
+		t.Errorf("expected missing LLM call note, got:\n%s", alert)
+	}
+	if strings.Contains(alert, "Flaky tools") {
+		t.Errorf("unexpected flaky section without flaky tools:\n%s", alert)
+	}
+}
+
+func TestRunningToolsTracking(t *testing.T) {
+	e := NewToolExecutor(slog.Default())
+
+	id1 := e.trackToolStart("bash")
+	id2 := e.trackToolStart("web_fetch")
+
+	running := e.RunningTools()
+	if len(running) != 2 || running[0].Name != "bash" || running[1].Name != "web_fetch" {
+		t.Errorf("unexpected running tools: %+v", running)
+	}
+
+	e.trackToolEnd(id1)
+	running = e.RunningTools()
+	if len(running) != 1 || running[0].Name != "web_fetch" {
+		t.Errorf("unexpected running tools after end: %+v", running)
+	}
+
+	e.trackToolEnd(id2)
+	if running = e.RunningTools(); len(running) != 0 {
+		t.Errorf("expected empty running set, got %+v", running)
+	}
+}
@@ -0,0 +1,584 @@
+// Package copilot – weather_tools.go implements the built-in weather skill.
+// Forecasts come from a pluggable provider: Open-Meteo works without a key
+// and is the default; OpenWeatherMap is used when an OPENWEATHERMAP_API_KEY
+// is in the vault. Geocoding always goes through Open-Meteo's keyless API.
+//
+// Each user can save a default location (_weather_locations in the skill
+// database) so "what's the weather?" needs no arguments, and the weather
+// tool can install a scheduled morning briefing.
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/scheduler"
+)
+
+// weatherMorningJobID is the scheduler job installed by schedule_daily.
+const weatherMorningJobID = "morning-weather"
+
+// openWeatherMapKeyName is the vault key that enables the OWM backend.
+const openWeatherMapKeyName = "OPENWEATHERMAP_API_KEY"
+
+// weatherMaxDays caps forecast length.
+const weatherMaxDays = 7
+
+// geoLocation is a resolved place.
+type geoLocation struct {
+	Name      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+// weatherDay is one day of forecast.
+type weatherDay struct {
+	Date      string
+	Summary   string
+	TempMinC  float64
+	TempMaxC  float64
+	PrecipPct int
+	WindKmh   float64
+}
+
+// weatherReport is a provider-independent forecast.
+type weatherReport struct {
+	Location     string
+	CurrentTempC float64
+	CurrentText  string
+	Days         []weatherDay
+}
+
+// weatherProvider abstracts a forecast backend.
+type weatherProvider interface {
+	// Name identifies the backend in output and errors.
+	Name() string
+	// Forecast returns current conditions plus up to days daily entries.
+	Forecast(ctx context.Context, loc geoLocation, days int) (*weatherReport, error)
+}
+
+// weatherCodeText maps WMO weather codes (used by Open-Meteo) to short text.
+var weatherCodeText = map[int]string{
+	0: "clear", 1: "mostly clear", 2: "partly cloudy", 3: "overcast",
+	45: "fog", 48: "freezing fog",
+	51: "light drizzle", 53: "drizzle", 55: "heavy drizzle",
+	61: "light rain", 63: "rain", 65: "heavy rain",
+	66: "freezing rain", 67: "heavy freezing rain",
+	71: "light snow", 73: "snow", 75: "heavy snow", 77: "snow grains",
+	80: "light showers", 81: "showers", 82: "heavy showers",
+	85: "snow showers", 86: "heavy snow showers",
+	95: "thunderstorm", 96: "thunderstorm with hail", 99: "severe thunderstorm",
+}
+
+// wmoCodeText returns a description for a WMO weather code.
+func wmoCodeText(code int) string {
+	if text, ok := weatherCodeText[code]; ok {
+		return text
+	}
+	return "unknown"
+}
+
+// ---------- Open-Meteo (keyless, default) ----------
+
+// openMeteoProvider fetches forecasts from api.open-meteo.com.
+type openMeteoProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newOpenMeteoProvider(client *http.Client) *openMeteoProvider {
+	return &openMeteoProvider{client: client, baseURL: "https://api.open-meteo.com"}
+}
+
+func (p *openMeteoProvider) Name() string { return "open-meteo" }
+
+func (p *openMeteoProvider) Forecast(ctx context.Context, loc geoLocation, days int) (*weatherReport, error) {
+	q := url.Values{}
+	q.Set("latitude", fmt.Sprintf("%.4f", loc.Latitude))
+	q.Set("longitude", fmt.Sprintf("%.4f", loc.Longitude))
+	q.Set("current", "temperature_2m,weather_code")
+	q.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max,wind_speed_10m_max")
+	q.Set("timezone", "auto")
+	q.Set("forecast_days", fmt.Sprintf("%d", days))
+
+	body, err := weatherHTTPGet(ctx, p.client, p.baseURL+"/v1/forecast?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open-meteo: %w", err)
+	}
+
+	var resp struct {
+		Current struct {
+			Temperature float64 `json:"temperature_2m"`
+			WeatherCode int     `json:"weather_code"`
+		} `json:"current"`
+		Daily struct {
+			Time         []string  `json:"time"`
+			WeatherCode  []int     `json:"weather_code"`
+			TempMax      []float64 `json:"temperature_2m_max"`
+			TempMin      []float64 `json:"temperature_2m_min"`
+			PrecipChance []int     `json:"precipitation_probability_max"`
+			WindSpeedMax []float64 `json:"wind_speed_10m_max"`
+		} `json:"daily"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("open-meteo: parsing response: %w", err)
+	}
+
+	report := &weatherReport{
+		Location:     loc.Name,
+		CurrentTempC: resp.Current.Temperature,
+		CurrentText:  wmoCodeText(resp.Current.WeatherCode),
+	}
+	for i, date := range resp.Daily.Time {
+		day := weatherDay{Date: date}
+		if i < len(resp.Daily.WeatherCode) {
+			day.Summary = wmoCodeText(resp.Daily.WeatherCode[i])
+		}
+		if i < len(resp.Daily.TempMin) {
+			day.TempMinC = resp.Daily.TempMin[i]
+		}
+		if i < len(resp.Daily.TempMax) {
+			day.TempMaxC = resp.Daily.TempMax[i]
+		}
+		if i < len(resp.Daily.PrecipChance) {
+			day.PrecipPct = resp.Daily.PrecipChance[i]
+		}
+		if i < len(resp.Daily.WindSpeedMax) {
+			day.WindKmh = resp.Daily.WindSpeedMax[i]
+		}
+		report.Days = append(report.Days, day)
+	}
+
+	return report, nil
+}
+
+// ---------- OpenWeatherMap (vault key) ----------
+
+// openWeatherMapProvider fetches forecasts from api.openweathermap.org using
+// the free 3-hourly forecast endpoint, aggregated per day.
+type openWeatherMapProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func newOpenWeatherMapProvider(client *http.Client, apiKey string) *openWeatherMapProvider {
+	return &openWeatherMapProvider{client: client, baseURL: "https://api.openweathermap.org", apiKey: apiKey}
+}
+
+func (p *openWeatherMapProvider) Name() string { return "openweathermap" }
+
+func (p *openWeatherMapProvider) Forecast(ctx context.Context, loc geoLocation, days int) (*weatherReport, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%.4f", loc.Latitude))
+	q.Set("lon", fmt.Sprintf("%.4f", loc.Longitude))
+	q.Set("units", "metric")
+	q.Set("appid", p.apiKey)
+
+	body, err := weatherHTTPGet(ctx, p.client, p.baseURL+"/data/2.5/forecast?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("openweathermap: %w", err)
+	}
+
+	var resp struct {
+		List []struct {
+			DtTxt string `json:"dt_txt"`
+			Main  struct {
+				TempMin float64 `json:"temp_min"`
+				TempMax float64 `json:"temp_max"`
+			} `json:"main"`
+			Weather []struct {
+				Description string `json:"description"`
+			} `json:"weather"`
+			Wind struct {
+				Speed float64 `json:"speed"` // m/s
+			} `json:"wind"`
+			Pop float64 `json:"pop"` // 0..1
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("openweathermap: parsing response: %w", err)
+	}
+	if len(resp.List) == 0 {
+		return nil, fmt.Errorf("openweathermap: empty forecast")
+	}
+
+	report := &weatherReport{Location: loc.Name}
+	var order []string
+	byDay := make(map[string]*weatherDay)
+	for i, entry := range resp.List {
+		date := strings.SplitN(entry.DtTxt, " ", 2)[0]
+		day, ok := byDay[date]
+		if !ok {
+			if len(order) >= days {
+				break
+			}
+			day = &weatherDay{Date: date, TempMinC: entry.Main.TempMin, TempMaxC: entry.Main.TempMax}
+			byDay[date] = day
+			order = append(order, date)
+		}
+		if entry.Main.TempMin < day.TempMinC {
+			day.TempMinC = entry.Main.TempMin
+		}
+		if entry.Main.TempMax > day.TempMaxC {
+			day.TempMaxC = entry.Main.TempMax
+		}
+		if pct := int(entry.Pop * 100); pct > day.PrecipPct {
+			day.PrecipPct = pct
+		}
+		if kmh := entry.Wind.Speed * 3.6; kmh > day.WindKmh {
+			day.WindKmh = kmh
+		}
+		// Midday entry gives the most representative summary.
+		if day.Summary == "" || strings.Contains(entry.DtTxt, "12:00") {
+			if len(entry.Weather) > 0 {
+				day.Summary = entry.Weather[0].Description
+			}
+		}
+		if i == 0 {
+			report.CurrentTempC = entry.Main.TempMax
+			if len(entry.Weather) > 0 {
+				report.CurrentText = entry.Weather[0].Description
+			}
+		}
+	}
+	for _, date := range order {
+		report.Days = append(report.Days, *byDay[date])
+	}
+
+	return report, nil
+}
+
+// ---------- Geocoding (Open-Meteo, keyless) ----------
+
+// weatherGeocoder resolves place names to coordinates.
+type weatherGeocoder struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newWeatherGeocoder(client *http.Client) *weatherGeocoder {
+	return &weatherGeocoder{client: client, baseURL: "https://geocoding-api.open-meteo.com"}
+}
+
+// Resolve geocodes a free-form place name.
+func (g *weatherGeocoder) Resolve(ctx context.Context, name string) (*geoLocation, error) {
+	q := url.Values{}
+	q.Set("name", name)
+	q.Set("count", "1")
+
+	body, err := weatherHTTPGet(ctx, g.client, g.baseURL+"/v1/search?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("geocoding %q: %w", name, err)
+	}
+
+	var resp struct {
+		Results []struct {
+			Name      string  `json:"name"`
+			Country   string  `json:"country"`
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("geocoding %q: parsing response: %w", name, err)
+	}
+	if len(resp.Results) == 0 {
+		return nil, fmt.Errorf("location %q not found", name)
+	}
+
+	r := resp.Results[0]
+	return &geoLocation{Name: r.Name, Country: r.Country, Latitude: r.Latitude, Longitude: r.Longitude}, nil
+}
+
+// weatherHTTPGet performs a GET and returns the body, treating 400+ as errors.
+func weatherHTTPGet(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "DevClaw/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	return body, nil
+}
+
+// ---------- Per-user default locations ----------
+
+// weatherLocationsSchema is the DDL for per-user default locations.
+const weatherLocationsSchema = `
+CREATE TABLE IF NOT EXISTS _weather_locations (
+    user_jid   TEXT PRIMARY KEY,
+    location   TEXT NOT NULL,
+    latitude   REAL NOT NULL,
+    longitude  REAL NOT NULL,
+    updated_at TEXT NOT NULL
+);
+`
+
+// InitWeatherTables creates the weather tables if they don't exist.
+func (s *SkillDB) InitWeatherTables() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.Exec(weatherLocationsSchema); err != nil {
+		return fmt.Errorf("create weather tables: %w", err)
+	}
+
+	return nil
+}
+
+// SetWeatherLocation saves a user's default location.
+func (s *SkillDB) SetWeatherLocation(userJID string, loc geoLocation) error {
+	if userJID == "" {
+		return fmt.Errorf("user is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(`
+		INSERT INTO _weather_locations (user_jid, location, latitude, longitude, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_jid) DO UPDATE SET location = excluded.location,
+			latitude = excluded.latitude, longitude = excluded.longitude, updated_at = excluded.updated_at`,
+		userJID, loc.Name, loc.Latitude, loc.Longitude, now,
+	)
+	if err != nil {
+		return fmt.Errorf("set weather location: %w", err)
+	}
+
+	return nil
+}
+
+// GetWeatherLocation returns a user's default location, or nil when unset.
+func (s *SkillDB) GetWeatherLocation(userJID string) (*geoLocation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var loc geoLocation
+	err := s.db.QueryRow(`
+		SELECT location, latitude, longitude FROM _weather_locations WHERE user_jid = ?`,
+		userJID,
+	).Scan(&loc.Name, &loc.Latitude, &loc.Longitude)
+	if err != nil {
+		if strings.Contains(err.Error(), "no rows") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get weather location: %w", err)
+	}
+
+	return &loc, nil
+}
+
+// ---------- Formatting ----------
+
+// formatWeatherReport renders a report for a channel: plain ASCII on the CLI,
+// compact emoji lines everywhere else (WhatsApp, Telegram, Discord).
+func formatWeatherReport(r *weatherReport, channel string) string {
+	plain := channel == "cli"
+
+	var b strings.Builder
+	if plain {
+		b.WriteString(fmt.Sprintf("Weather for %s: %.0f°C, %s\n", r.Location, r.CurrentTempC, r.CurrentText))
+	} else {
+		b.WriteString(fmt.Sprintf("*%s* — %.0f°C, %s\n", r.Location, r.CurrentTempC, r.CurrentText))
+	}
+
+	for _, d := range r.Days {
+		dayName := d.Date
+		if t, err := time.Parse("2006-01-02", d.Date); err == nil {
+			dayName = t.Format("Mon 02/01")
+		}
+		if plain {
+			b.WriteString(fmt.Sprintf("%s: %s, %.0f-%.0f°C, rain %d%%, wind %.0f km/h\n",
+				dayName, d.Summary, d.TempMinC, d.TempMaxC, d.PrecipPct, d.WindKmh))
+		} else {
+			b.WriteString(fmt.Sprintf("• %s: %s, %.0f–%.0f°C 🌧 %d%% 💨 %.0f km/h\n",
+				dayName, d.Summary, d.TempMinC, d.TempMaxC, d.PrecipPct, d.WindKmh))
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// ---------- Tools ----------
+
+// RegisterWeatherTools registers the weather skill tools in the executor.
+// The provider is picked per call: OpenWeatherMap when its key is in the
+// vault (or explicitly requested), Open-Meteo otherwise.
+func RegisterWeatherTools(executor *ToolExecutor, skillDB *SkillDB, vault *Vault, sched *scheduler.Scheduler) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	geocoder := newWeatherGeocoder(client)
+
+	pickProvider := func(requested string) (weatherProvider, error) {
+		if requested == "open-meteo" {
+			return newOpenMeteoProvider(client), nil
+		}
+		if requested == "openweathermap" || requested == "" {
+			if vault != nil && vault.IsUnlocked() && vault.Has(openWeatherMapKeyName) {
+				key, err := vault.Get(openWeatherMapKeyName)
+				if err == nil {
+					return newOpenWeatherMapProvider(client, key), nil
+				}
+			}
+			if requested == "openweathermap" {
+				return nil, fmt.Errorf("openweathermap requires %s in the vault", openWeatherMapKeyName)
+			}
+		}
+		return newOpenMeteoProvider(client), nil
+	}
+
+	resolveLocation := func(ctx context.Context, location string) (*geoLocation, error) {
+		if location != "" {
+			return geocoder.Resolve(ctx, location)
+		}
+		if skillDB != nil {
+			if jid := CallerJIDFromContext(ctx); jid != "" {
+				loc, err := skillDB.GetWeatherLocation(jid)
+				if err != nil {
+					return nil, err
+				}
+				if loc != nil {
+					return loc, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("no location given and no default set — ask the user for their city, then save it with weather_set_location")
+	}
+
+	// weather
+	executor.Register(
+		MakeToolDefinition("weather", "Current conditions and forecast for a location (the user's saved default when omitted). Use schedule_daily=true to install a recurring morning weather briefing.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"location": map[string]any{
+					"type":        "string",
+					"description": "City or place name (default: the user's saved location)",
+				},
+				"days": map[string]any{
+					"type":        "integer",
+					"description": "Forecast days, 1-7 (default: 3)",
+				},
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Force a backend (default: openweathermap if its key is in the vault, else open-meteo)",
+					"enum":        []string{"open-meteo", "openweathermap"},
+				},
+				"schedule_daily": map[string]any{
+					"type":        "boolean",
+					"description": "Install a scheduled job that posts the morning weather to this chat every day at 07:00",
+				},
+			},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			location, _ := args["location"].(string)
+			daysArg, _ := args["days"].(float64)
+			requested, _ := args["provider"].(string)
+			scheduleDaily, _ := args["schedule_daily"].(bool)
+
+			if scheduleDaily {
+				if sched == nil {
+					return nil, fmt.Errorf("scheduler is not enabled")
+				}
+				dt := DeliveryTargetFromContext(ctx)
+				job := &scheduler.Job{
+					ID:             weatherMorningJobID,
+					Schedule:       "0 7 * * *",
+					Type:           "cron",
+					Command:        "Run the weather tool for the user's default location and post a short morning weather briefing: current conditions, today's range, and whether to take an umbrella.",
+					Channel:        dt.Channel,
+					ChatID:         dt.ChatID,
+					Enabled:        true,
+					IsolateSession: true,
+					Announce:       true,
+				}
+				if err := sched.Add(job); err != nil {
+					return nil, fmt.Errorf("scheduling morning weather: %w", err)
+				}
+				return fmt.Sprintf("Morning weather scheduled as job %q (daily, 07:00). Remove it with cron_remove.", weatherMorningJobID), nil
+			}
+
+			days := int(daysArg)
+			if days <= 0 {
+				days = 3
+			}
+			if days > weatherMaxDays {
+				days = weatherMaxDays
+			}
+
+			loc, err := resolveLocation(ctx, location)
+			if err != nil {
+				return nil, err
+			}
+			provider, err := pickProvider(requested)
+			if err != nil {
+				return nil, err
+			}
+
+			report, err := provider.Forecast(ctx, *loc, days)
+			if err != nil {
+				return nil, err
+			}
+
+			dt := DeliveryTargetFromContext(ctx)
+			return formatWeatherReport(report, dt.Channel), nil
+		},
+	)
+
+	// weather_set_location
+	executor.Register(
+		MakeToolDefinition("weather_set_location", "Save the user's default weather location so future weather calls need no arguments.", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"location": map[string]any{
+					"type":        "string",
+					"description": "City or place name (e.g. 'São Paulo', 'Berlin')",
+				},
+			},
+			"required": []string{"location"},
+		}),
+		func(ctx context.Context, args map[string]any) (any, error) {
+			location, _ := args["location"].(string)
+			if location == "" {
+				return nil, fmt.Errorf("location is required")
+			}
+			if skillDB == nil {
+				return nil, fmt.Errorf("skill database not available")
+			}
+			jid := CallerJIDFromContext(ctx)
+			if jid == "" {
+				return nil, fmt.Errorf("no user context available")
+			}
+
+			loc, err := geocoder.Resolve(ctx, location)
+			if err != nil {
+				return nil, err
+			}
+			if err := skillDB.SetWeatherLocation(jid, *loc); err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("Default location saved: %s, %s (%.4f, %.4f).", loc.Name, loc.Country, loc.Latitude, loc.Longitude), nil
+		},
+	)
+}
@@ -0,0 +1,189 @@
+// Package copilot – weather_tools_test.go contains unit tests for the weather skill.
+package copilot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenMeteoForecast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v1/forecast") {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("forecast_days"); got != "2" {
+			t.Errorf("expected forecast_days=2, got %q", got)
+		}
+		w.Write([]byte(`{
+			"current": {"temperature_2m": 21.5, "weather_code": 2},
+			"daily": {
+				"time": ["2026-08-30", "2026-08-31"],
+				"weather_code": [61, 0],
+				"temperature_2m_max": [24.1, 27.0],
+				"temperature_2m_min": [15.2, 16.8],
+				"precipitation_probability_max": [80, 5],
+				"wind_speed_10m_max": [18.3, 12.0]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := newOpenMeteoProvider(srv.Client())
+	p.baseURL = srv.URL
+
+	report, err := p.Forecast(context.Background(), geoLocation{Name: "Berlin", Latitude: 52.52, Longitude: 13.40}, 2)
+	if err != nil {
+		t.Fatalf("forecast failed: %v", err)
+	}
+	if report.CurrentTempC != 21.5 || report.CurrentText != "partly cloudy" {
+		t.Errorf("unexpected current conditions: %+v", report)
+	}
+	if len(report.Days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(report.Days))
+	}
+	d := report.Days[0]
+	if d.Summary != "light rain" || d.TempMinC != 15.2 || d.TempMaxC != 24.1 || d.PrecipPct != 80 {
+		t.Errorf("unexpected day: %+v", d)
+	}
+}
+
+func TestOpenWeatherMapForecastAggregation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("appid"); got != "test-key" {
+			t.Errorf("expected appid=test-key, got %q", got)
+		}
+		w.Write([]byte(`{"list": [
+			{"dt_txt": "2026-08-30 09:00:00", "main": {"temp_min": 14.0, "temp_max": 18.0},
+			 "weather": [{"description": "light rain"}], "wind": {"speed": 4.0}, "pop": 0.3},
+			{"dt_txt": "2026-08-30 12:00:00", "main": {"temp_min": 17.0, "temp_max": 23.0},
+			 "weather": [{"description": "scattered clouds"}], "wind": {"speed": 6.0}, "pop": 0.7},
+			{"dt_txt": "2026-08-31 12:00:00", "main": {"temp_min": 16.0, "temp_max": 25.0},
+			 "weather": [{"description": "clear sky"}], "wind": {"speed": 2.0}, "pop": 0.0}
+		]}`))
+	}))
+	defer srv.Close()
+
+	p := newOpenWeatherMapProvider(srv.Client(), "test-key")
+	p.baseURL = srv.URL
+
+	report, err := p.Forecast(context.Background(), geoLocation{Name: "Berlin"}, 2)
+	if err != nil {
+		t.Fatalf("forecast failed: %v", err)
+	}
+	if len(report.Days) != 2 {
+		t.Fatalf("expected 2 aggregated days, got %d", len(report.Days))
+	}
+	d := report.Days[0]
+	// Min/max span the 3-hourly entries; pop and wind take the maximum;
+	// the midday entry wins the summary.
+	if d.TempMinC != 14.0 || d.TempMaxC != 23.0 {
+		t.Errorf("unexpected temp range: %+v", d)
+	}
+	if d.PrecipPct != 70 {
+		t.Errorf("expected 70%% rain chance, got %d", d.PrecipPct)
+	}
+	if d.Summary != "scattered clouds" {
+		t.Errorf("expected midday summary, got %q", d.Summary)
+	}
+	if d.WindKmh < 21 || d.WindKmh > 22 { // 6 m/s = 21.6 km/h
+		t.Errorf("unexpected wind: %.1f", d.WindKmh)
+	}
+}
+
+func TestWeatherGeocoder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("name"); got != "Berlin" {
+			t.Errorf("expected name=Berlin, got %q", got)
+		}
+		w.Write([]byte(`{"results": [{"name": "Berlin", "country": "Germany", "latitude": 52.52, "longitude": 13.40}]}`))
+	}))
+	defer srv.Close()
+
+	g := newWeatherGeocoder(srv.Client())
+	g.baseURL = srv.URL
+
+	loc, err := g.Resolve(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if loc.Name != "Berlin" || loc.Country != "Germany" || loc.Latitude != 52.52 {
+		t.Errorf("unexpected location: %+v", loc)
+	}
+}
+
+func TestWeatherGeocoderNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	g := newWeatherGeocoder(srv.Client())
+	g.baseURL = srv.URL
+
+	if _, err := g.Resolve(context.Background(), "Nowhereville"); err == nil {
+		t.Error("expected error for unknown location")
+	}
+}
+
+func TestWeatherLocationStore(t *testing.T) {
+	db, err := OpenSkillDatabase(t.TempDir())
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.InitWeatherTables(); err != nil {
+		t.Fatalf("init weather tables: %v", err)
+	}
+
+	if err := db.SetWeatherLocation("", geoLocation{Name: "Berlin"}); err == nil {
+		t.Error("expected error for empty user")
+	}
+
+	loc, err := db.GetWeatherLocation("user@s.whatsapp.net")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if loc != nil {
+		t.Errorf("expected nil for unset location, got %+v", loc)
+	}
+
+	if err := db.SetWeatherLocation("user@s.whatsapp.net", geoLocation{Name: "Berlin", Latitude: 52.52, Longitude: 13.40}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	// Updating replaces the previous default.
+	if err := db.SetWeatherLocation("user@s.whatsapp.net", geoLocation{Name: "Paris", Latitude: 48.85, Longitude: 2.35}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	loc, err = db.GetWeatherLocation("user@s.whatsapp.net")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if loc == nil || loc.Name != "Paris" || loc.Latitude != 48.85 {
+		t.Errorf("unexpected location: %+v", loc)
+	}
+}
+
+func TestFormatWeatherReport(t *testing.T) {
+	report := &weatherReport{
+		Location:     "Berlin",
+		CurrentTempC: 21.5,
+		CurrentText:  "partly cloudy",
+		Days: []weatherDay{
+			{Date: "2026-08-30", Summary: "light rain", TempMinC: 15.2, TempMaxC: 24.1, PrecipPct: 80, WindKmh: 18.3},
+		},
+	}
+
+	chat := formatWeatherReport(report, "whatsapp")
+	if !strings.Contains(chat, "*Berlin*") || !strings.Contains(chat, "🌧 80%") {
+		t.Errorf("unexpected chat format: %q", chat)
+	}
+
+	cli := formatWeatherReport(report, "cli")
+	if strings.Contains(cli, "🌧") || !strings.Contains(cli, "rain 80%") {
+		t.Errorf("unexpected cli format: %q", cli)
+	}
+}
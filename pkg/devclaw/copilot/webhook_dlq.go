@@ -0,0 +1,131 @@
+// Package copilot – webhook_dlq.go implements the webhook dead-letter queue.
+// Deliveries that exhaust all retries are persisted so broken integrations
+// lose no events: /webhooks failed lists them and /webhooks replay re-sends.
+// The queue is capped per endpoint (oldest letters dropped first) so an
+// endpoint that stays down cannot grow devclaw.db without bound.
+package copilot
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// webhookDLQMaxPerEndpoint caps how many dead letters are kept per webhook.
+// When an endpoint stays down (circuit open), every skipped event lands here —
+// beyond the cap the oldest letters are dropped.
+const webhookDLQMaxPerEndpoint = 200
+
+// WebhookDeadLetter is a webhook delivery that exhausted all retries.
+type WebhookDeadLetter struct {
+	ID          string
+	WebhookName string
+	Event       string
+	Payload     string // JSON body as it would have been delivered
+	LastError   string
+	Attempts    int
+	CreatedAt   time.Time
+}
+
+// WebhookDLQStore persists dead-lettered webhook deliveries in devclaw.db.
+type WebhookDLQStore struct {
+	db *sql.DB
+}
+
+// NewWebhookDLQStore creates a dead-letter store.
+func NewWebhookDLQStore(db *sql.DB) *WebhookDLQStore {
+	return &WebhookDLQStore{db: db}
+}
+
+// Add records a failed delivery.
+func (s *WebhookDLQStore) Add(webhookName, event string, payload []byte, lastError string, attempts int) (string, error) {
+	id := generateID()
+	_, err := s.db.Exec(`
+		INSERT INTO webhook_dead_letters (id, webhook_name, event, payload, last_error, attempts, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, id, webhookName, event, string(payload), lastError, attempts, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return "", fmt.Errorf("insert dead letter: %w", err)
+	}
+
+	// Enforce the per-endpoint cap, dropping the oldest letters. Best-effort:
+	// a failed trim must not fail the delivery that is being dead-lettered.
+	_, _ = s.db.Exec(`
+		DELETE FROM webhook_dead_letters
+		WHERE webhook_name = ? AND id NOT IN (
+			SELECT id FROM webhook_dead_letters
+			WHERE webhook_name = ?
+			ORDER BY created_at DESC, rowid DESC LIMIT ?
+		)
+	`, webhookName, webhookName, webhookDLQMaxPerEndpoint)
+
+	return id, nil
+}
+
+// Get returns one dead letter by ID.
+func (s *WebhookDLQStore) Get(id string) (*WebhookDeadLetter, error) {
+	row := s.db.QueryRow(`
+		SELECT id, webhook_name, event, payload, last_error, attempts, created_at
+		FROM webhook_dead_letters WHERE id = ?
+	`, id)
+	dl, err := scanDeadLetter(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("dead letter %q not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query dead letter: %w", err)
+	}
+	return dl, nil
+}
+
+// Recent returns the newest dead letters, most recent first.
+func (s *WebhookDLQStore) Recent(limit int) ([]WebhookDeadLetter, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := s.db.Query(`
+		SELECT id, webhook_name, event, payload, last_error, attempts, created_at
+		FROM webhook_dead_letters ORDER BY created_at DESC LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var out []WebhookDeadLetter
+	for rows.Next() {
+		dl, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan dead letter: %w", err)
+		}
+		out = append(out, *dl)
+	}
+	return out, rows.Err()
+}
+
+// Delete removes a dead letter (after a successful replay).
+func (s *WebhookDLQStore) Delete(id string) error {
+	res, err := s.db.Exec(`DELETE FROM webhook_dead_letters WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete dead letter: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("dead letter %q not found", id)
+	}
+	return nil
+}
+
+// dlqRowScanner abstracts *sql.Row and *sql.Rows for scanDeadLetter.
+type dlqRowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDeadLetter(row dlqRowScanner) (*WebhookDeadLetter, error) {
+	var dl WebhookDeadLetter
+	var createdAt string
+	if err := row.Scan(&dl.ID, &dl.WebhookName, &dl.Event, &dl.Payload, &dl.LastError, &dl.Attempts, &createdAt); err != nil {
+		return nil, err
+	}
+	dl.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &dl, nil
+}
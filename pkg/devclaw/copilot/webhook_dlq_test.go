@@ -0,0 +1,124 @@
+package copilot
+
+import (
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testDLQStore(t *testing.T) *WebhookDLQStore {
+	t.Helper()
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewWebhookDLQStore(db)
+}
+
+func TestWebhookDLQStoreLifecycle(t *testing.T) {
+	store := testDLQStore(t)
+
+	id, err := store.Add("slack-alerts", "tool.error", []byte(`{"event":"tool.error"}`), "connection refused", 4)
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	dl, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if dl.WebhookName != "slack-alerts" || dl.Event != "tool.error" || dl.Attempts != 4 {
+		t.Errorf("unexpected dead letter: %+v", dl)
+	}
+	if dl.Payload != `{"event":"tool.error"}` {
+		t.Errorf("payload not preserved: %q", dl.Payload)
+	}
+
+	letters, err := store.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+
+	if err := store.Delete(id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(id); err == nil {
+		t.Error("second Delete should fail")
+	}
+	if _, err := store.Get(id); err == nil {
+		t.Error("Get after Delete should fail")
+	}
+}
+
+func TestWebhookDLQStoreCap(t *testing.T) {
+	store := testDLQStore(t)
+
+	if _, err := store.Add("other-hook", "tool.error", []byte(`{}`), "timeout", 1); err != nil {
+		t.Fatalf("Add other-hook: %v", err)
+	}
+	var firstID string
+	for i := 0; i < webhookDLQMaxPerEndpoint+5; i++ {
+		id, err := store.Add("slack-alerts", "tool.error", []byte(`{}`), "circuit open", 0)
+		if err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+		if i == 0 {
+			firstID = id
+		}
+	}
+
+	letters, err := store.Recent(webhookDLQMaxPerEndpoint + 50)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	perEndpoint := map[string]int{}
+	for _, dl := range letters {
+		perEndpoint[dl.WebhookName]++
+	}
+	if perEndpoint["slack-alerts"] != webhookDLQMaxPerEndpoint {
+		t.Errorf("expected %d letters for slack-alerts, got %d", webhookDLQMaxPerEndpoint, perEndpoint["slack-alerts"])
+	}
+	// Other endpoints keep their letters; the oldest over-cap letter is gone.
+	if perEndpoint["other-hook"] != 1 {
+		t.Errorf("other-hook letters affected by cap: got %d", perEndpoint["other-hook"])
+	}
+	if _, err := store.Get(firstID); err == nil {
+		t.Error("oldest over-cap dead letter should have been dropped")
+	}
+}
+
+func TestWebhookBreaker(t *testing.T) {
+	wm := NewWebhookManager(WebhooksConfig{}, nil, slog.Default())
+
+	if !wm.breakerAllows("hook") {
+		t.Fatal("fresh endpoint should be allowed")
+	}
+	for i := 0; i < webhookBreakerThreshold; i++ {
+		wm.breakerRecord("hook", false)
+	}
+	if wm.breakerAllows("hook") {
+		t.Error("circuit should be open after consecutive failures")
+	}
+
+	// Expired cooldown half-opens the circuit for a single probe.
+	wm.breakersMu.Lock()
+	wm.breakers["hook"].openUntil = time.Now().Add(-time.Second)
+	wm.breakersMu.Unlock()
+	if !wm.breakerAllows("hook") {
+		t.Error("expired cooldown should allow a probe delivery")
+	}
+	if wm.breakerAllows("hook") {
+		t.Error("only one probe should pass while half-open")
+	}
+
+	// A success closes the circuit entirely.
+	wm.breakerRecord("hook", true)
+	if !wm.breakerAllows("hook") {
+		t.Error("success should reset the breaker")
+	}
+}
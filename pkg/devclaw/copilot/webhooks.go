@@ -17,6 +17,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/notify"
 )
 
 // WebhookConfig configures an external webhook endpoint.
@@ -85,14 +87,37 @@ type WebhookPayload struct {
 	Extra map[string]any `json:"extra,omitempty"`
 }
 
+// webhookBreakerThreshold is the number of consecutive delivery failures
+// before an endpoint's circuit opens.
+const webhookBreakerThreshold = 5
+
+// webhookBreakerCooldown is how long an open circuit skips deliveries before
+// allowing a new attempt.
+const webhookBreakerCooldown = 60 * time.Second
+
+// maxWebhookRetryDelay caps the exponential backoff between retries.
+const maxWebhookRetryDelay = 30 * time.Second
+
+// webhookBreaker tracks consecutive failures for one endpoint.
+type webhookBreaker struct {
+	failures  int
+	openUntil time.Time
+}
+
 // WebhookManager manages webhook delivery.
 type WebhookManager struct {
-	config    WebhooksConfig
-	client    *http.Client
-	hookMgr   *HookManager
-	logger    *slog.Logger
-	eventMap  map[string]bool // events to send
-	mu        sync.RWMutex
+	config   WebhooksConfig
+	client   *http.Client
+	hookMgr  *HookManager
+	notifier *notify.Router
+	dlq      *WebhookDLQStore
+	logger   *slog.Logger
+	eventMap map[string]bool // events to send
+	mu       sync.RWMutex
+
+	// breakers holds per-endpoint circuit state, keyed by webhook name.
+	breakersMu sync.Mutex
+	breakers   map[string]*webhookBreaker
 }
 
 // NewWebhookManager creates a new webhook manager.
@@ -122,6 +147,7 @@ func NewWebhookManager(cfg WebhooksConfig, hookMgr *HookManager, logger *slog.Lo
 		hookMgr:  hookMgr,
 		eventMap: eventMap,
 		logger:   logger.With("component", "webhooks"),
+		breakers: make(map[string]*webhookBreaker),
 		client: &http.Client{
 			Timeout: timeout,
 		},
@@ -219,6 +245,17 @@ func (wm *WebhookManager) sendWebhook(wh *WebhookConfig, payload HookPayload) {
 		return
 	}
 
+	// Skip while the endpoint's circuit is open — hammering a broken
+	// endpoint just burns retries. The event still goes to the dead-letter
+	// queue so it can be replayed once the endpoint recovers.
+	if !wm.breakerAllows(wh.Name) {
+		wm.logger.Debug("webhook circuit open, dead-lettering",
+			"webhook", wh.Name,
+			"event", payload.Event)
+		wm.deadLetter(wh.Name, string(payload.Event), body, "circuit open", 0)
+		return
+	}
+
 	retryCount := wh.RetryCount
 	if retryCount == 0 {
 		retryCount = 3
@@ -231,7 +268,12 @@ func (wm *WebhookManager) sendWebhook(wh *WebhookConfig, payload HookPayload) {
 	var lastErr error
 	for attempt := 0; attempt <= retryCount; attempt++ {
 		if attempt > 0 {
-			time.Sleep(time.Duration(retryDelay) * time.Millisecond)
+			// Exponential backoff: delay doubles per attempt, capped.
+			delay := time.Duration(retryDelay) * time.Millisecond << (attempt - 1)
+			if delay > maxWebhookRetryDelay {
+				delay = maxWebhookRetryDelay
+			}
+			time.Sleep(delay)
 			wm.logger.Debug("webhook retry",
 				"webhook", wh.Name,
 				"attempt", attempt)
@@ -239,6 +281,7 @@ func (wm *WebhookManager) sendWebhook(wh *WebhookConfig, payload HookPayload) {
 
 		lastErr = wm.doRequest(wh, body)
 		if lastErr == nil {
+			wm.breakerRecord(wh.Name, true)
 			wm.logger.Debug("webhook sent",
 				"webhook", wh.Name,
 				"event", payload.Event)
@@ -246,10 +289,123 @@ func (wm *WebhookManager) sendWebhook(wh *WebhookConfig, payload HookPayload) {
 		}
 	}
 
+	wm.breakerRecord(wh.Name, false)
 	wm.logger.Error("webhook failed after retries",
 		"webhook", wh.Name,
 		"event", payload.Event,
 		"error", lastErr)
+
+	wm.deadLetter(wh.Name, string(payload.Event), body, lastErr.Error(), retryCount+1)
+
+	// Surface persistent delivery failures as a push alert so the owner
+	// notices broken integrations without watching logs.
+	wm.notifier.Notify(context.Background(), &notify.Alert{
+		Event:    "webhook.delivery_failed",
+		Title:    "Webhook delivery failed",
+		Message:  fmt.Sprintf("%s (%s): %v", wh.Name, payload.Event, lastErr),
+		Priority: 2,
+	})
+}
+
+// breakerAllows reports whether deliveries to an endpoint may proceed.
+func (wm *WebhookManager) breakerAllows(name string) bool {
+	wm.breakersMu.Lock()
+	defer wm.breakersMu.Unlock()
+	b, ok := wm.breakers[name]
+	if !ok {
+		return true
+	}
+	if b.failures < webhookBreakerThreshold {
+		return true
+	}
+	if time.Now().After(b.openUntil) {
+		// Half-open: allow one delivery through to probe the endpoint.
+		b.openUntil = time.Now().Add(webhookBreakerCooldown)
+		return true
+	}
+	return false
+}
+
+// breakerRecord updates an endpoint's circuit state after a delivery.
+func (wm *WebhookManager) breakerRecord(name string, success bool) {
+	wm.breakersMu.Lock()
+	defer wm.breakersMu.Unlock()
+	if success {
+		delete(wm.breakers, name)
+		return
+	}
+	b := wm.breakers[name]
+	if b == nil {
+		b = &webhookBreaker{}
+		wm.breakers[name] = b
+	}
+	b.failures++
+	if b.failures >= webhookBreakerThreshold {
+		b.openUntil = time.Now().Add(webhookBreakerCooldown)
+		wm.logger.Warn("webhook circuit opened",
+			"webhook", name,
+			"failures", b.failures)
+	}
+}
+
+// deadLetter persists a failed delivery when the queue store is wired.
+func (wm *WebhookManager) deadLetter(name, event string, body []byte, lastErr string, attempts int) {
+	if wm.dlq == nil {
+		return
+	}
+	if _, err := wm.dlq.Add(name, event, body, lastErr, attempts); err != nil {
+		wm.logger.Error("failed to dead-letter webhook delivery",
+			"webhook", name,
+			"error", err)
+	}
+}
+
+// DeadLetters returns the newest dead-lettered deliveries (nil store = none).
+func (wm *WebhookManager) DeadLetters(limit int) ([]WebhookDeadLetter, error) {
+	if wm.dlq == nil {
+		return nil, nil
+	}
+	return wm.dlq.Recent(limit)
+}
+
+// Replay re-sends a dead-lettered delivery and removes it on success.
+func (wm *WebhookManager) Replay(id string) error {
+	if wm.dlq == nil {
+		return fmt.Errorf("dead-letter queue not available")
+	}
+	dl, err := wm.dlq.Get(id)
+	if err != nil {
+		return err
+	}
+
+	wm.mu.RLock()
+	var wh *WebhookConfig
+	for i := range wm.config.Webhooks {
+		if wm.config.Webhooks[i].Name == dl.WebhookName {
+			wh = &wm.config.Webhooks[i]
+			break
+		}
+	}
+	wm.mu.RUnlock()
+	if wh == nil {
+		return fmt.Errorf("webhook %q no longer configured", dl.WebhookName)
+	}
+
+	if err := wm.doRequest(wh, []byte(dl.Payload)); err != nil {
+		return fmt.Errorf("replay failed: %w", err)
+	}
+	wm.breakerRecord(wh.Name, true)
+	return wm.dlq.Delete(id)
+}
+
+// SetDeadLetterStore wires the persistent dead-letter queue.
+func (wm *WebhookManager) SetDeadLetterStore(s *WebhookDLQStore) {
+	wm.dlq = s
+}
+
+// SetNotifier wires the push notification router for failure alerts.
+func (wm *WebhookManager) SetNotifier(n *notify.Router) {
+	wm.notifier = n
 }
 
 // doRequest performs the HTTP request to the webhook endpoint.
@@ -69,6 +69,21 @@ type Workspace struct {
 	// 0 = use global default.
 	MaxMessages int `yaml:"max_messages"`
 
+	// QuietHours holds proactive messages (heartbeat, scheduler, digests)
+	// during the window; they are delivered when it ends.
+	// Nil = use global DND defaults.
+	QuietHours *QuietHoursConfig `yaml:"quiet_hours,omitempty"`
+
+	// Approvers configures named approvers and per-tool quorum rules for
+	// tool approvals in this workspace. When set, approval requests fan
+	// out to each approver's DM instead of prompting the requesting chat.
+	// Nil = default self-approval flow.
+	Approvers *ApproverConfig `yaml:"approvers,omitempty"`
+
+	// ModelPolicy restricts which models/providers this workspace may use
+	// (data-residency constraints). Nil = no restrictions.
+	ModelPolicy *ModelPolicyConfig `yaml:"model_policy,omitempty"`
+
 	// ToolProfile specifies which tool profile to use for this workspace.
 	// Options: minimal, coding, messaging, full, or custom profile name.
 	// Empty = use global profile from tool_guard config.
@@ -80,6 +95,11 @@ type Workspace struct {
 	// Groups lists the group JIDs assigned to this workspace.
 	Groups []string `yaml:"groups"`
 
+	// Channels lists channel registration names bound to this workspace
+	// (e.g. "whatsapp#business"). Messages arriving on these channels
+	// resolve here unless a user/group binding takes precedence.
+	Channels []string `yaml:"channels"`
+
 	// CreatedBy is the JID of whoever created this workspace.
 	CreatedBy string `yaml:"created_by"`
 
@@ -131,12 +151,24 @@ type WorkspaceManager struct {
 	// groupMap maps group JID → workspace ID.
 	groupMap map[string]string
 
+	// channelMap maps channel registration name → workspace ID.
+	channelMap map[string]string
+
 	// sessions stores isolated SessionStores per workspace.
 	sessions map[string]*SessionStore
 
 	// persistence is propagated to all workspace session stores.
 	persistence SessionPersister
 
+	// archiveExpire/archiveRestore are propagated to all workspace session
+	// stores (idle-session archival; see session_archive.go).
+	archiveExpire  func(*Session)
+	archiveRestore func(sessionID string) (string, []string, bool)
+
+	// identityResolver maps a JID to all JIDs linked to the same person
+	// (see identity.go), so workspace assignment follows across channels.
+	identityResolver func(jid string) []string
+
 	// defaultWSID is the fallback workspace ID.
 	defaultWSID string
 
@@ -155,6 +187,7 @@ func NewWorkspaceManager(globalCfg *Config, wsCfg WorkspaceConfig, logger *slog.
 		workspaces:  make(map[string]*Workspace),
 		userMap:     make(map[string]string),
 		groupMap:    make(map[string]string),
+		channelMap:  make(map[string]string),
 		sessions:    make(map[string]*SessionStore),
 		defaultWSID: wsCfg.DefaultWorkspace,
 	}
@@ -176,6 +209,9 @@ func NewWorkspaceManager(globalCfg *Config, wsCfg WorkspaceConfig, logger *slog.
 		for _, gid := range ws.Groups {
 			wm.groupMap[normalizeJID(gid)] = ws.ID
 		}
+		for _, ch := range ws.Channels {
+			wm.channelMap[ch] = ws.ID
+		}
 	}
 
 	// Ensure default workspace exists.
@@ -210,6 +246,27 @@ func (wm *WorkspaceManager) SetPersistence(p SessionPersister) {
 	}
 }
 
+// SetIdentityResolver wires cross-channel identity linking into workspace
+// resolution (see identity.go).
+func (wm *WorkspaceManager) SetIdentityResolver(resolver func(jid string) []string) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.identityResolver = resolver
+}
+
+// SetArchival propagates idle-session archival hooks to all workspace session
+// stores and stores them for newly created workspaces.
+func (wm *WorkspaceManager) SetArchival(onExpire func(*Session), restore func(sessionID string) (string, []string, bool)) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	wm.archiveExpire = onExpire
+	wm.archiveRestore = restore
+	for _, store := range wm.sessions {
+		store.SetArchival(onExpire, restore)
+	}
+}
+
 // ResolvedWorkspace contains the resolved workspace and session for a message.
 type ResolvedWorkspace struct {
 	// Workspace is the resolved workspace.
@@ -228,7 +285,7 @@ func (wm *WorkspaceManager) Resolve(channel, chatID, senderJID string, isGroup b
 	wm.mu.RLock()
 	defer wm.mu.RUnlock()
 
-	wsID := wm.resolveWorkspaceID(chatID, senderJID, isGroup)
+	wsID := wm.resolveWorkspaceID(channel, chatID, senderJID, isGroup)
 
 	ws, ok := wm.workspaces[wsID]
 	if !ok || !ws.Active {
@@ -243,6 +300,9 @@ func (wm *WorkspaceManager) Resolve(channel, chatID, senderJID string, isGroup b
 		if wm.persistence != nil {
 			store.SetPersistence(wm.persistence)
 		}
+		if wm.archiveExpire != nil || wm.archiveRestore != nil {
+			store.SetArchival(wm.archiveExpire, wm.archiveRestore)
+		}
 		wm.sessions[wsID] = store
 	}
 
@@ -258,8 +318,17 @@ func (wm *WorkspaceManager) Resolve(channel, chatID, senderJID string, isGroup b
 	}
 }
 
-// resolveWorkspaceID finds the workspace for a JID/group.
-func (wm *WorkspaceManager) resolveWorkspaceID(chatID, senderJID string, isGroup bool) string {
+// WorkspaceIDFor returns the workspace ID a message routes to, without
+// creating a session. Used by read-only callers like the response
+// post-processing pipeline.
+func (wm *WorkspaceManager) WorkspaceIDFor(channel, chatID, senderJID string, isGroup bool) string {
+	wm.mu.RLock()
+	defer wm.mu.RUnlock()
+	return wm.resolveWorkspaceID(channel, chatID, senderJID, isGroup)
+}
+
+// resolveWorkspaceID finds the workspace for a JID/group/channel.
+func (wm *WorkspaceManager) resolveWorkspaceID(channel, chatID, senderJID string, isGroup bool) string {
 	normSender := normalizeJID(senderJID)
 	normChat := normalizeJID(chatID)
 
@@ -270,12 +339,25 @@ func (wm *WorkspaceManager) resolveWorkspaceID(chatID, senderJID string, isGroup
 		}
 	}
 
-	// 2. Check user assignment.
+	// 2. Check user assignment (directly or via a linked identity, so the
+	// same person lands in the same workspace on every channel).
 	if wsID, ok := wm.userMap[normSender]; ok {
 		return wsID
 	}
+	if wm.identityResolver != nil {
+		for _, jid := range wm.identityResolver(normSender) {
+			if wsID, ok := wm.userMap[normalizeJID(jid)]; ok {
+				return wsID
+			}
+		}
+	}
+
+	// 3. Check channel binding (e.g. "whatsapp#business" → work workspace).
+	if wsID, ok := wm.channelMap[channel]; ok {
+		return wsID
+	}
 
-	// 3. Fallback to default.
+	// 4. Fallback to default.
 	return wm.defaultWSID
 }
 
@@ -336,6 +418,9 @@ func (wm *WorkspaceManager) Create(ws Workspace, createdBy string) error {
 	if wm.persistence != nil {
 		store.SetPersistence(wm.persistence)
 	}
+	if wm.archiveExpire != nil || wm.archiveRestore != nil {
+		store.SetArchival(wm.archiveExpire, wm.archiveRestore)
+	}
 	wm.sessions[ws.ID] = store
 
 	// Map members.
@@ -537,6 +622,22 @@ func (wm *WorkspaceManager) GetForUser(jid string) (*Workspace, bool) {
 	return ws, exists
 }
 
+// DefaultWorkspaceID returns the ID of the default workspace.
+func (wm *WorkspaceManager) DefaultWorkspaceID() string {
+	wm.mu.RLock()
+	defer wm.mu.RUnlock()
+	return wm.defaultWSID
+}
+
+// IsAssigned reports whether the user has an explicit workspace assignment
+// (GetForUser falls back to the default workspace and can't tell the two apart).
+func (wm *WorkspaceManager) IsAssigned(jid string) bool {
+	wm.mu.RLock()
+	defer wm.mu.RUnlock()
+	_, ok := wm.userMap[normalizeJID(jid)]
+	return ok
+}
+
 // List returns all workspaces.
 func (wm *WorkspaceManager) List() []*Workspace {
 	wm.mu.RLock()
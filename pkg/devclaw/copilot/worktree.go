@@ -0,0 +1,241 @@
+// Package copilot – worktree.go implements git worktree-based change
+// isolation for coding tasks. When a session starts a task worktree, the
+// agent edits and commits on a dedicated branch in a separate checkout
+// instead of mutating the project's working tree directly. The user reviews
+// the result and lands it with /merge or throws it away with
+// /worktree discard.
+package copilot
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// worktreeBranchPrefix namespaces task branches so they are easy to spot
+// (and clean up) in the project's branch list.
+const worktreeBranchPrefix = "devclaw/"
+
+// TaskWorktree describes one active task worktree bound to a session.
+type TaskWorktree struct {
+	SessionID   string
+	ProjectRoot string
+	BaseBranch  string
+	Branch      string
+	Path        string
+	CreatedAt   time.Time
+}
+
+// WorktreeManager tracks active task worktrees, one per session.
+type WorktreeManager struct {
+	logger *slog.Logger
+	mu     sync.Mutex
+	active map[string]*TaskWorktree // session ID → worktree
+}
+
+// NewWorktreeManager creates an empty worktree manager.
+func NewWorktreeManager(logger *slog.Logger) *WorktreeManager {
+	return &WorktreeManager{
+		logger: logger.With("component", "worktree"),
+		active: make(map[string]*TaskWorktree),
+	}
+}
+
+// Start creates a task worktree for a session: a new branch off the project's
+// current HEAD, checked out under <root>/.devclaw/worktrees/. Returns an error
+// if the session already has one or the project is not a git repository.
+func (wm *WorktreeManager) Start(sessionID, projectRoot, task string) (*TaskWorktree, error) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	if existing, ok := wm.active[sessionID]; ok {
+		return nil, fmt.Errorf("session already has a task worktree on branch %s — /merge or /worktree discard it first", existing.Branch)
+	}
+
+	baseBranch, err := runGitDir(projectRoot, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository (or no commits yet): %w", err)
+	}
+
+	slug := worktreeSlug(task)
+	branch := worktreeBranchPrefix + slug
+	path := filepath.Join(projectRoot, ".devclaw", "worktrees", slug)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating worktrees dir: %w", err)
+	}
+	if _, err := runGitDir(projectRoot, "worktree", "add", "-b", branch, path); err != nil {
+		return nil, fmt.Errorf("creating worktree: %w", err)
+	}
+
+	wt := &TaskWorktree{
+		SessionID:   sessionID,
+		ProjectRoot: projectRoot,
+		BaseBranch:  baseBranch,
+		Branch:      branch,
+		Path:        path,
+		CreatedAt:   time.Now(),
+	}
+	wm.active[sessionID] = wt
+
+	wm.logger.Info("task worktree created",
+		"session", sessionID,
+		"branch", branch,
+		"base", baseBranch,
+		"path", path)
+	return wt, nil
+}
+
+// Get returns the active worktree for a session, or nil.
+func (wm *WorktreeManager) Get(sessionID string) *TaskWorktree {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	return wm.active[sessionID]
+}
+
+// Status reports the branch, commits ahead of the base, and uncommitted
+// changes of the session's worktree.
+func (wm *WorktreeManager) Status(sessionID string) (string, error) {
+	wt := wm.Get(sessionID)
+	if wt == nil {
+		return "", fmt.Errorf("no active task worktree — use /worktree start")
+	}
+
+	ahead, _ := runGitDir(wt.ProjectRoot, "rev-list", "--count", wt.BaseBranch+".."+wt.Branch)
+	if ahead == "" {
+		ahead = "0"
+	}
+	dirty, _ := runGitDir(wt.Path, "status", "--porcelain")
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Branch: %s (base: %s)\n", wt.Branch, wt.BaseBranch))
+	b.WriteString(fmt.Sprintf("Path: %s\n", wt.Path))
+	b.WriteString(fmt.Sprintf("Commits ahead of %s: %s\n", wt.BaseBranch, ahead))
+	if dirty != "" {
+		b.WriteString(fmt.Sprintf("Uncommitted changes:\n%s", dirty))
+	} else {
+		b.WriteString("Working tree clean.")
+	}
+	return b.String(), nil
+}
+
+// Merge lands the session's task branch into its base branch with a merge
+// commit, then removes the worktree and the branch. Fails if the worktree
+// still has uncommitted changes or the base branch has moved under conflicts.
+func (wm *WorktreeManager) Merge(sessionID string) (string, error) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	wt, ok := wm.active[sessionID]
+	if !ok {
+		return "", fmt.Errorf("no active task worktree — use /worktree start")
+	}
+
+	if dirty, _ := runGitDir(wt.Path, "status", "--porcelain"); dirty != "" {
+		return "", fmt.Errorf("worktree has uncommitted changes — ask the agent to commit them first")
+	}
+
+	if current, err := runGitDir(wt.ProjectRoot, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && current != wt.BaseBranch {
+		return "", fmt.Errorf("project is on branch %s but the task was started from %s — check out %s before merging", current, wt.BaseBranch, wt.BaseBranch)
+	}
+
+	ahead, _ := runGitDir(wt.ProjectRoot, "rev-list", "--count", wt.BaseBranch+".."+wt.Branch)
+	if ahead == "" || ahead == "0" {
+		return "", fmt.Errorf("branch %s has no commits to merge", wt.Branch)
+	}
+
+	if _, err := runGitDir(wt.ProjectRoot, "merge", "--no-ff", "-m",
+		fmt.Sprintf("Merge task branch %s", wt.Branch), wt.Branch); err != nil {
+		return "", fmt.Errorf("merge failed (resolve conflicts in %s manually): %w", wt.ProjectRoot, err)
+	}
+
+	wm.removeWorktree(wt, false)
+	delete(wm.active, sessionID)
+
+	wm.logger.Info("task worktree merged",
+		"session", sessionID,
+		"branch", wt.Branch,
+		"base", wt.BaseBranch,
+		"commits", ahead)
+	return fmt.Sprintf("Merged %s commit(s) from %s into %s. Worktree removed.", ahead, wt.Branch, wt.BaseBranch), nil
+}
+
+// Discard removes the session's worktree and deletes its branch, throwing
+// away all changes made for the task.
+func (wm *WorktreeManager) Discard(sessionID string) (string, error) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	wt, ok := wm.active[sessionID]
+	if !ok {
+		return "", fmt.Errorf("no active task worktree — use /worktree start")
+	}
+
+	wm.removeWorktree(wt, true)
+	delete(wm.active, sessionID)
+
+	wm.logger.Info("task worktree discarded", "session", sessionID, "branch", wt.Branch)
+	return fmt.Sprintf("Worktree and branch %s discarded.", wt.Branch), nil
+}
+
+// PromptNote returns a system prompt addendum when the session has an active
+// task worktree, or "" otherwise.
+func (wm *WorktreeManager) PromptNote(sessionID string) string {
+	wt := wm.Get(sessionID)
+	if wt == nil {
+		return ""
+	}
+	return fmt.Sprintf("\n\n## Task Worktree\n"+
+		"All code changes for this task happen in the isolated git worktree at %s (branch %s).\n"+
+		"Edit files and run commands inside that directory, commit your changes there, and run the project's tests before reporting the task as done.\n"+
+		"Do NOT modify the main working tree at %s. The user lands the branch with /merge.",
+		wt.Path, wt.Branch, wt.ProjectRoot)
+}
+
+// removeWorktree prunes the checkout and deletes the branch. Best-effort:
+// failures are logged but not returned, since the merge/discard already
+// succeeded from the user's perspective.
+func (wm *WorktreeManager) removeWorktree(wt *TaskWorktree, force bool) {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, wt.Path)
+	if _, err := runGitDir(wt.ProjectRoot, args...); err != nil {
+		wm.logger.Warn("worktree remove failed", "path", wt.Path, "error", err)
+	}
+
+	deleteFlag := "-d"
+	if force {
+		deleteFlag = "-D"
+	}
+	if _, err := runGitDir(wt.ProjectRoot, "branch", deleteFlag, wt.Branch); err != nil {
+		wm.logger.Warn("branch delete failed", "branch", wt.Branch, "error", err)
+	}
+}
+
+// worktreeSlug derives a branch/directory-safe name from a task description,
+// suffixed with a timestamp so repeated tasks never collide.
+func worktreeSlug(task string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(task)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '_' || r == '/':
+			b.WriteRune('-')
+		}
+		if b.Len() >= 32 {
+			break
+		}
+	}
+	slug := strings.Trim(b.String(), "-")
+	if slug == "" {
+		slug = "task"
+	}
+	return slug + "-" + time.Now().Format("20060102-150405")
+}
@@ -0,0 +1,145 @@
+package copilot
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepo initializes a git repository with one commit in a temp dir.
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+	} {
+		if _, err := runGitDir(root, args...); err != nil {
+			t.Skipf("git not usable in this environment: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGitDir(root, "add", "-A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGitDir(root, "commit", "-m", "initial"); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func newTestWorktreeManager() *WorktreeManager {
+	return NewWorktreeManager(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+}
+
+func TestWorktreeStartAndMerge(t *testing.T) {
+	root := newTestRepo(t)
+	wm := newTestWorktreeManager()
+
+	wt, err := wm.Start("sess-1", root, "add feature")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if !strings.HasPrefix(wt.Branch, worktreeBranchPrefix) {
+		t.Errorf("branch %q missing prefix", wt.Branch)
+	}
+	if wt.BaseBranch != "main" {
+		t.Errorf("expected base branch main, got %s", wt.BaseBranch)
+	}
+	if _, err := os.Stat(wt.Path); err != nil {
+		t.Fatalf("worktree path missing: %v", err)
+	}
+
+	// A second worktree for the same session must be refused.
+	if _, err := wm.Start("sess-1", root, "other task"); err == nil {
+		t.Error("expected error starting a second worktree for the same session")
+	}
+
+	// No commits yet: merge should refuse.
+	if _, err := wm.Merge("sess-1"); err == nil {
+		t.Error("expected merge of empty branch to fail")
+	}
+
+	// Commit a change in the worktree and merge it.
+	if err := os.WriteFile(filepath.Join(wt.Path, "feature.txt"), []byte("done\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGitDir(wt.Path, "add", "-A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGitDir(wt.Path, "commit", "-m", "add feature"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := wm.Merge("sess-1")
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if !strings.Contains(result, wt.BaseBranch) {
+		t.Errorf("unexpected merge result: %q", result)
+	}
+
+	// Change landed on main, worktree gone, session free again.
+	if _, err := os.Stat(filepath.Join(root, "feature.txt")); err != nil {
+		t.Errorf("merged file missing from main tree: %v", err)
+	}
+	if wm.Get("sess-1") != nil {
+		t.Error("worktree should be cleared after merge")
+	}
+}
+
+func TestWorktreeMergeRefusesDirtyTree(t *testing.T) {
+	root := newTestRepo(t)
+	wm := newTestWorktreeManager()
+
+	wt, err := wm.Start("sess-2", root, "wip")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Path, "wip.txt"), []byte("uncommitted\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := wm.Merge("sess-2"); err == nil || !strings.Contains(err.Error(), "uncommitted") {
+		t.Errorf("expected uncommitted-changes error, got %v", err)
+	}
+}
+
+func TestWorktreeDiscard(t *testing.T) {
+	root := newTestRepo(t)
+	wm := newTestWorktreeManager()
+
+	wt, err := wm.Start("sess-3", root, "throwaway")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if _, err := wm.Discard("sess-3"); err != nil {
+		t.Fatalf("Discard: %v", err)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Error("worktree path should be removed after discard")
+	}
+	if wm.Get("sess-3") != nil {
+		t.Error("worktree should be cleared after discard")
+	}
+	if note := wm.PromptNote("sess-3"); note != "" {
+		t.Errorf("expected empty prompt note after discard, got %q", note)
+	}
+}
+
+func TestWorktreeSlug(t *testing.T) {
+	slug := worktreeSlug("Fix the Login / Bug!")
+	if strings.ContainsAny(slug, " /!") {
+		t.Errorf("slug contains unsafe characters: %q", slug)
+	}
+	if !strings.HasPrefix(slug, "fix-the-login") {
+		t.Errorf("unexpected slug: %q", slug)
+	}
+}
@@ -526,6 +526,19 @@ CREATE TABLE IF NOT EXISTS jobs (
     run_count   INTEGER DEFAULT 0
 );
 
+-- Scheduler job execution history
+CREATE TABLE IF NOT EXISTS job_runs (
+    id          SERIAL PRIMARY KEY,
+    job_id      TEXT NOT NULL,
+    started_at  TEXT NOT NULL,
+    duration_ms BIGINT DEFAULT 0,
+    status      TEXT NOT NULL,
+    attempt     INTEGER DEFAULT 1,
+    error       TEXT DEFAULT '',
+    output      TEXT DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_job_runs_job ON job_runs(job_id);
+
 -- Session conversation entries
 CREATE TABLE IF NOT EXISTS session_entries (
     id                 SERIAL PRIMARY KEY,
@@ -637,6 +650,21 @@ CREATE TABLE IF NOT EXISTS pairing_requests (
 CREATE INDEX IF NOT EXISTS idx_pairing_requests_status ON pairing_requests(status);
 CREATE INDEX IF NOT EXISTS idx_pairing_requests_user ON pairing_requests(user_jid);
 
+-- Access requests ("ask" policy review queue)
+CREATE TABLE IF NOT EXISTS access_requests (
+    id            TEXT PRIMARY KEY,
+    user_jid      TEXT NOT NULL,
+    user_name     TEXT DEFAULT '',
+    channel       TEXT DEFAULT '',
+    first_message TEXT DEFAULT '',
+    status        TEXT NOT NULL DEFAULT 'pending',
+    reviewed_by   TEXT DEFAULT '',
+    reviewed_at   TEXT DEFAULT '',
+    created_at    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_access_requests_status ON access_requests(status);
+CREATE INDEX IF NOT EXISTS idx_access_requests_user ON access_requests(user_jid);
+
 -- Teams
 CREATE TABLE IF NOT EXISTS teams (
     id            TEXT PRIMARY KEY,
@@ -730,6 +758,8 @@ CREATE TABLE IF NOT EXISTS team_facts (
     key        TEXT NOT NULL,
     value      TEXT NOT NULL,
     author     TEXT NOT NULL,
+    visibility TEXT DEFAULT 'team',
+    roles      TEXT DEFAULT '[]',
     created_at TEXT NOT NULL,
     updated_at TEXT NOT NULL,
     FOREIGN KEY (team_id) REFERENCES teams(id),
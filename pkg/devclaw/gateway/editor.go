@@ -0,0 +1,368 @@
+// Package gateway – editor.go implements the editor companion WebSocket
+// endpoint (/api/editor/ws) for VS Code and other editor integrations.
+// Operations are scoped to a ProjectManager project and reuse the agent loop
+// and registered tools (apply_patch) rather than a separate code path.
+//
+// Protocol (same envelope as websocket.go):
+//
+//	Client → Server (requests):
+//	  {"type":"req","id":"1","method":"project.list"}
+//	  {"type":"req","id":"2","method":"editor.explain","params":{"projectId":"...","file":"...","selection":"..."}}
+//	  {"type":"req","id":"3","method":"editor.generateTests","params":{"projectId":"...","file":"..."}}
+//	  {"type":"req","id":"4","method":"editor.proposeEdits","params":{"projectId":"...","instruction":"...","files":["..."]}}
+//	  {"type":"req","id":"5","method":"editor.applyEdits","params":{"editId":"..."}}
+//
+//	Agent progress arrives as events ("delta", "tool_use", "tool_result"),
+//	followed by "done" with the final text. proposeEdits additionally emits
+//	"edit_proposed" with the patch text as a diff preview; the edit is only
+//	written to disk after editor.applyEdits.
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot"
+)
+
+// pendingEdit is an agent-proposed patch awaiting editor.applyEdits.
+type pendingEdit struct {
+	projectID string
+	rootPath  string
+	patch     string
+}
+
+// EditorHandler serves the editor companion WebSocket endpoint.
+type EditorHandler struct {
+	assistant *copilot.Assistant
+	logger    *slog.Logger
+
+	// pending maps edit IDs to proposed-but-unapplied patches.
+	pending   map[string]*pendingEdit
+	pendingMu sync.Mutex
+	nextEdit  atomic.Int64
+}
+
+// NewEditorHandler creates the editor endpoint handler.
+func NewEditorHandler(assistant *copilot.Assistant, logger *slog.Logger) *EditorHandler {
+	return &EditorHandler{
+		assistant: assistant,
+		logger:    logger.With("component", "editor"),
+		pending:   make(map[string]*pendingEdit),
+	}
+}
+
+// ServeHTTP upgrades the connection and runs the request loop.
+func (h *EditorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error("editor websocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	h.logger.Info("editor client connected", "remote", r.RemoteAddr)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var writeMu sync.Mutex
+	sendMsg := func(msg wsMessage) {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = conn.WriteMessage(websocket.TextMessage, data)
+	}
+
+	sendRes := func(id string, ok bool, payload any, errMsg string) {
+		msg := wsMessage{Type: "res", ID: id}
+		boolVal := ok
+		msg.OK = &boolVal
+		if errMsg != "" {
+			msg.Error = errMsg
+		}
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			msg.Payload = data
+		}
+		sendMsg(msg)
+	}
+
+	sendEvent := func(event string, payload any) {
+		msg := wsMessage{Type: "event", Event: event}
+		data, _ := json.Marshal(payload)
+		msg.Payload = data
+		sendMsg(msg)
+	}
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				h.logger.Warn("editor websocket read error", "error", err)
+			}
+			return
+		}
+
+		var msg wsMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			sendRes("", false, nil, "invalid JSON")
+			continue
+		}
+		if msg.Type != "req" {
+			sendRes(msg.ID, false, nil, "expected type=req")
+			continue
+		}
+
+		switch msg.Method {
+		case "project.list":
+			sendRes(msg.ID, true, h.listProjects(), "")
+		case "editor.explain":
+			go h.handleExplain(ctx, msg, sendRes, sendEvent)
+		case "editor.generateTests":
+			go h.handleGenerateTests(ctx, msg, sendRes, sendEvent)
+		case "editor.proposeEdits":
+			go h.handleProposeEdits(ctx, msg, sendRes, sendEvent)
+		case "editor.applyEdits":
+			go h.handleApplyEdits(ctx, msg, sendRes)
+		default:
+			sendRes(msg.ID, false, nil, "unknown method: "+msg.Method)
+		}
+	}
+}
+
+// listProjects returns registered projects in a wire-friendly shape.
+func (h *EditorHandler) listProjects() []map[string]string {
+	projects := h.assistant.ProjectManager().List()
+	out := make([]map[string]string, 0, len(projects))
+	for _, p := range projects {
+		out = append(out, map[string]string{
+			"id":        p.ID,
+			"name":      p.Name,
+			"rootPath":  p.RootPath,
+			"language":  p.Language,
+			"framework": p.Framework,
+		})
+	}
+	return out
+}
+
+// resolveProject looks up the project or reports an error to the client.
+func (h *EditorHandler) resolveProject(id string, msgID string, sendRes func(string, bool, any, string)) *copilot.Project {
+	if id == "" {
+		sendRes(msgID, false, nil, "missing projectId")
+		return nil
+	}
+	project := h.assistant.ProjectManager().Get(id)
+	if project == nil {
+		sendRes(msgID, false, nil, "unknown project: "+id)
+		return nil
+	}
+	return project
+}
+
+// handleExplain runs an agent turn explaining a code selection.
+func (h *EditorHandler) handleExplain(ctx context.Context, msg wsMessage, sendRes func(string, bool, any, string), sendEvent func(string, any)) {
+	var params struct {
+		ProjectID string `json:"projectId"`
+		File      string `json:"file"`
+		Selection string `json:"selection"`
+		StartLine int    `json:"startLine"`
+		EndLine   int    `json:"endLine"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil || params.Selection == "" {
+		sendRes(msg.ID, false, nil, "missing selection")
+		return
+	}
+	project := h.resolveProject(params.ProjectID, msg.ID, sendRes)
+	if project == nil {
+		return
+	}
+
+	location := params.File
+	if params.StartLine > 0 {
+		location = fmt.Sprintf("%s (lines %d-%d)", params.File, params.StartLine, params.EndLine)
+	}
+	prompt := fmt.Sprintf(`Explain the following code selection from %s in the %s project (root: %s).
+Read surrounding code if needed for context. Be concise and concrete.
+
+%s`, location, project.Name, project.RootPath, fence(params.Selection))
+
+	h.runEditorAgent(ctx, project, msg.ID, prompt, sendRes, sendEvent)
+}
+
+// handleGenerateTests runs an agent turn that writes tests for a file.
+func (h *EditorHandler) handleGenerateTests(ctx context.Context, msg wsMessage, sendRes func(string, bool, any, string), sendEvent func(string, any)) {
+	var params struct {
+		ProjectID string `json:"projectId"`
+		File      string `json:"file"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil || params.File == "" {
+		sendRes(msg.ID, false, nil, "missing file")
+		return
+	}
+	project := h.resolveProject(params.ProjectID, msg.ID, sendRes)
+	if project == nil {
+		return
+	}
+
+	testHint := ""
+	if project.TestCmd != "" {
+		testHint = fmt.Sprintf(" Run the test suite with `%s` to verify.", project.TestCmd)
+	}
+	prompt := fmt.Sprintf(`Generate tests for %s in the %s project (root: %s).
+Read the file and its neighbours first, follow the project's existing test layout
+and naming conventions, and write the tests to the appropriate file.%s`,
+		params.File, project.Name, project.RootPath, testHint)
+
+	h.runEditorAgent(ctx, project, msg.ID, prompt, sendRes, sendEvent)
+}
+
+// handleProposeEdits runs an agent turn that produces a patch WITHOUT applying
+// it, and emits the patch as a diff preview the editor can render.
+func (h *EditorHandler) handleProposeEdits(ctx context.Context, msg wsMessage, sendRes func(string, bool, any, string), sendEvent func(string, any)) {
+	var params struct {
+		ProjectID   string   `json:"projectId"`
+		Instruction string   `json:"instruction"`
+		Files       []string `json:"files"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil || params.Instruction == "" {
+		sendRes(msg.ID, false, nil, "missing instruction")
+		return
+	}
+	project := h.resolveProject(params.ProjectID, msg.ID, sendRes)
+	if project == nil {
+		return
+	}
+
+	scope := ""
+	if len(params.Files) > 0 {
+		scope = " Limit changes to: " + strings.Join(params.Files, ", ") + "."
+	}
+	prompt := fmt.Sprintf(`Propose edits for the %s project (root: %s): %s%s
+
+Do NOT apply the changes. Instead, read the relevant files and output the full
+edit as a single patch in the apply_patch format, starting with '*** Begin Patch'
+and ending with '*** End Patch'. File paths are relative to the project root.`,
+		project.Name, project.RootPath, params.Instruction, scope)
+
+	response := h.runEditorAgent(ctx, project, msg.ID, prompt, sendRes, sendEvent)
+
+	patch := extractPatch(response)
+	if patch == "" {
+		sendEvent("edit_failed", map[string]string{"error": "agent did not produce a patch"})
+		return
+	}
+
+	editID := fmt.Sprintf("edit-%d", h.nextEdit.Add(1))
+	h.pendingMu.Lock()
+	h.pending[editID] = &pendingEdit{projectID: project.ID, rootPath: project.RootPath, patch: patch}
+	h.pendingMu.Unlock()
+
+	sendEvent("edit_proposed", map[string]string{
+		"editId":    editID,
+		"projectId": project.ID,
+		"patch":     patch,
+	})
+}
+
+// handleApplyEdits applies a previously proposed patch via the registered
+// apply_patch tool, resolving paths against the project root.
+func (h *EditorHandler) handleApplyEdits(ctx context.Context, msg wsMessage, sendRes func(string, bool, any, string)) {
+	var params struct {
+		EditID string `json:"editId"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil || params.EditID == "" {
+		sendRes(msg.ID, false, nil, "missing editId")
+		return
+	}
+
+	h.pendingMu.Lock()
+	edit, ok := h.pending[params.EditID]
+	if ok {
+		delete(h.pending, params.EditID)
+	}
+	h.pendingMu.Unlock()
+
+	if !ok {
+		sendRes(msg.ID, false, nil, "unknown editId: "+params.EditID)
+		return
+	}
+
+	args, _ := json.Marshal(map[string]string{
+		"input":       edit.patch,
+		"working_dir": edit.rootPath,
+	})
+	results := h.assistant.ToolExecutor().Execute(ctx, []copilot.ToolCall{{
+		ID:       params.EditID,
+		Type:     "function",
+		Function: copilot.FunctionCall{Name: "apply_patch", Arguments: string(args)},
+	}})
+
+	if len(results) == 0 || results[0].Error != nil {
+		errMsg := "apply_patch produced no result"
+		if len(results) > 0 && results[0].Error != nil {
+			errMsg = results[0].Error.Error()
+		}
+		sendRes(msg.ID, false, nil, errMsg)
+		return
+	}
+
+	sendRes(msg.ID, true, map[string]string{
+		"editId": params.EditID,
+		"result": results[0].Content,
+	}, "")
+}
+
+// runEditorAgent executes one agent turn scoped to the project's editor
+// session, streaming progress as events. Returns the final response text.
+func (h *EditorHandler) runEditorAgent(ctx context.Context, project *copilot.Project, msgID, userMessage string, sendRes func(string, bool, any, string), sendEvent func(string, any)) string {
+	session := h.assistant.SessionStore().GetOrCreate("editor", project.ID)
+
+	// Ack first — progress arrives as events.
+	sendRes(msgID, true, map[string]string{"sessionId": session.ID}, "")
+
+	prompt := h.assistant.ComposePrompt(session, userMessage)
+	response, err := h.assistant.ExecuteAgentStream(ctx, prompt, session, userMessage, func(ev copilot.AgentEvent) {
+		sendEvent(ev.Type, ev.Data)
+	})
+	if err != nil {
+		sendEvent("error", map[string]string{"message": err.Error()})
+		return ""
+	}
+
+	session.AddMessage(userMessage, response)
+	clean := copilot.StripInternalTags(response)
+	sendEvent("done", map[string]string{"response": clean})
+	return clean
+}
+
+// extractPatch pulls an apply_patch-format patch out of agent output.
+func extractPatch(response string) string {
+	const begin = "*** Begin Patch"
+	const end = "*** End Patch"
+	start := strings.Index(response, begin)
+	if start < 0 {
+		return ""
+	}
+	stop := strings.Index(response[start:], end)
+	if stop < 0 {
+		return ""
+	}
+	return response[start : start+stop+len(end)]
+}
+
+// fence wraps text in a markdown code fence for prompt embedding.
+func fence(text string) string {
+	return "```\n" + text + "\n```"
+}
@@ -0,0 +1,87 @@
+// Package gateway – events.go streams the assistant's event bus over SSE so
+// dashboards and integrations can react to DevClaw activity in real time.
+//
+// GET /api/events
+//
+// Query parameters:
+//   - stream: only emit events from this stream (e.g. "activity", "tool", "hook")
+//   - type:   only emit events of this type (e.g. "message_received", "job_fired")
+//
+// Each event is sent as an SSE "event:" with the event type as name and the
+// JSON-encoded copilot.AgentEvent as data. A keepalive comment is sent every
+// 30 seconds so proxies do not close idle connections.
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot"
+)
+
+// eventKeepaliveInterval is how often an SSE comment is sent on idle streams.
+const eventKeepaliveInterval = 30 * time.Second
+
+// handleEvents subscribes the client to the assistant's event bus via SSE.
+func (g *Gateway) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		g.writeError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		g.writeError(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	streamFilter := r.URL.Query().Get("stream")
+	typeFilter := r.URL.Query().Get("type")
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// Buffered so a slow client drops events instead of blocking Emit —
+	// the bus fans out synchronously to all listeners.
+	events := make(chan copilot.AgentEvent, 64)
+	unsubscribe := g.assistant.Events().Subscribe(func(ev copilot.AgentEvent) {
+		if streamFilter != "" && ev.Stream != streamFilter {
+			return
+		}
+		if typeFilter != "" && ev.Type != typeFilter {
+			return
+		}
+		select {
+		case events <- ev:
+		default: // Client too slow — drop rather than block the bus.
+		}
+	})
+	defer unsubscribe()
+
+	g.logger.Info("event stream client connected",
+		"remote", r.RemoteAddr, "stream", streamFilter, "type", typeFilter)
+
+	keepalive := time.NewTicker(eventKeepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case ev := <-events:
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"net/http/pprof"
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jholhewres/devclaw/pkg/devclaw/copilot"
@@ -22,6 +24,7 @@ type Gateway struct {
 	server      *http.Server
 	logger      *slog.Logger
 	startedAt   time.Time
+	draining    atomic.Bool
 	webhooks    []WebhookEntry
 	webhooksMu  sync.Mutex
 	webhookSeq  int
@@ -60,8 +63,13 @@ func (g *Gateway) Start(ctx context.Context) error {
 	// Health (always public)
 	mux.HandleFunc("/health", g.handleHealth)
 
+	// Kubernetes-style probes (always public)
+	mux.HandleFunc("/healthz", g.handleHealthz)
+	mux.HandleFunc("/readyz", g.handleReadyz)
+
 	// OpenAI-compatible chat
 	mux.HandleFunc("/v1/chat/completions", g.handleChatCompletions)
+	mux.HandleFunc("/v1/models", g.handleListModels)
 
 	// API routes
 	mux.HandleFunc("/api/sessions", g.handleListSessions)
@@ -69,8 +77,33 @@ func (g *Gateway) Start(ctx context.Context) error {
 	mux.HandleFunc("/api/usage", g.handleGlobalUsage)
 	mux.HandleFunc("/api/usage/", g.handleSessionUsage)
 	mux.HandleFunc("/api/status", g.handleStatus)
+	mux.HandleFunc("/api/events", g.handleEvents)
 	mux.HandleFunc("/api/webhooks", g.handleWebhooks)
 	mux.HandleFunc("/api/webhooks/", g.handleWebhookByID)
+	mux.HandleFunc("/api/artifacts/", g.handleArtifactByName)
+	mux.HandleFunc("/api/runs", g.handleRuns)
+	mux.HandleFunc("/api/runs/", g.handleRunByID)
+
+	// Federation: delegated tasks from peer DevClaw instances. Authenticated
+	// by the handler itself with the federation token, not the gateway token.
+	mux.HandleFunc("/api/federation/delegate", g.handleFederationDelegate)
+
+	// Editor companion endpoint (WebSocket, project-scoped operations)
+	mux.Handle("/api/editor/ws", NewEditorHandler(g.assistant, g.logger))
+
+	// Optional profiling endpoints (heap, goroutine, CPU). Behind the auth
+	// middleware like the API routes.
+	if g.config.Pprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		if g.config.AuthToken == "" {
+			g.logger.Warn("SECURITY: pprof endpoints enabled without an auth token — profiling data is exposed to anyone who can reach the gateway")
+		}
+		g.logger.Info("pprof endpoints enabled", "prefix", "/debug/pprof/")
+	}
 
 	handler := g.securityHeadersMiddleware(g.corsMiddleware(g.authMiddleware(mux)))
 	g.server = &http.Server{
@@ -102,6 +135,13 @@ func (g *Gateway) Start(ctx context.Context) error {
 	return nil
 }
 
+// SetDraining marks the gateway as draining. While draining, /readyz
+// returns 503 so orchestrators (e.g. Kubernetes) stop routing new traffic
+// before the server shuts down.
+func (g *Gateway) SetDraining(draining bool) {
+	g.draining.Store(draining)
+}
+
 // Stop gracefully shuts down the HTTP server.
 func (g *Gateway) Stop(ctx context.Context) error {
 	if g.server == nil {
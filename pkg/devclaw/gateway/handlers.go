@@ -1,10 +1,13 @@
 package gateway
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,21 +18,21 @@ const version = "1.0.0"
 
 // openAIChatRequest is the standard OpenAI chat completions request format.
 type openAIChatRequest struct {
-	Model    string                 `json:"model"`
-	Messages []openAIChatMessage     `json:"messages"`
-	Stream   bool                   `json:"stream"`
-	Tools    []openAIToolDef         `json:"tools,omitempty"`
+	Model    string              `json:"model"`
+	Messages []openAIChatMessage `json:"messages"`
+	Stream   bool                `json:"stream"`
+	Tools    []openAIToolDef     `json:"tools,omitempty"`
 }
 
 type openAIChatMessage struct {
-	Role       string          `json:"role"`
-	Content    json.RawMessage `json:"content"` // string or array of parts
+	Role       string           `json:"role"`
+	Content    json.RawMessage  `json:"content"` // string or array of parts
 	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
-	ToolCallID string          `json:"tool_call_id,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
 }
 
 type openAIToolDef struct {
-	Type     string          `json:"type"`
+	Type     string            `json:"type"`
 	Function openAIFunctionDef `json:"function"`
 }
 
@@ -42,7 +45,7 @@ type openAIFunctionDef struct {
 type openAIToolCall struct {
 	ID       string             `json:"id"`
 	Type     string             `json:"type"`
-	Function openAIFunctionCall  `json:"function"`
+	Function openAIFunctionCall `json:"function"`
 }
 
 type openAIFunctionCall struct {
@@ -59,14 +62,14 @@ type openAIChatResponse struct {
 	Choices []struct {
 		Index   int `json:"index"`
 		Message *struct {
-			Role       string           `json:"role"`
-			Content    string           `json:"content"`
-			ToolCalls  []openAIToolCall  `json:"tool_calls,omitempty"`
+			Role      string           `json:"role"`
+			Content   string           `json:"content"`
+			ToolCalls []openAIToolCall `json:"tool_calls,omitempty"`
 		} `json:"message,omitempty"`
-		Delta        *struct {
-			Role       string `json:"role,omitempty"`
-			Content    string `json:"content,omitempty"`
-			ToolCalls  []openAIStreamToolCall `json:"tool_calls,omitempty"`
+		Delta *struct {
+			Role      string                 `json:"role,omitempty"`
+			Content   string                 `json:"content,omitempty"`
+			ToolCalls []openAIStreamToolCall `json:"tool_calls,omitempty"`
 		} `json:"delta,omitempty"`
 		FinishReason *string `json:"finish_reason"`
 	} `json:"choices"`
@@ -140,6 +143,102 @@ func (g *Gateway) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleHealthz implements GET /healthz (Kubernetes liveness probe).
+// Returns 200 as long as the process is serving requests.
+func (g *Gateway) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		g.writeError(w, "method not allowed", 405)
+		return
+	}
+	g.writeJSON(w, 200, map[string]string{"status": "ok"})
+}
+
+// handleReadyz implements GET /readyz (Kubernetes readiness probe).
+// Reports channel connectivity, DB reachability, and vault state. Returns
+// 503 while draining or when the central database is unreachable.
+func (g *Gateway) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		g.writeError(w, "method not allowed", 405)
+		return
+	}
+
+	ready := true
+	status := "ready"
+
+	channelsMap := make(map[string]string)
+	vaultState := "absent"
+	dbState := "absent"
+	if g.assistant != nil {
+		for name, st := range g.assistant.ChannelManager().HealthAll() {
+			if st.Connected {
+				channelsMap[name] = "connected"
+			} else {
+				channelsMap[name] = "disconnected"
+			}
+		}
+		if v := g.assistant.Vault(); v != nil && v.Exists() {
+			if v.IsUnlocked() {
+				vaultState = "unlocked"
+			} else {
+				vaultState = "locked"
+			}
+		}
+		if db := g.assistant.DB(); db != nil {
+			pingCtx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+			defer cancel()
+			if err := db.PingContext(pingCtx); err != nil {
+				dbState = "unreachable"
+				ready = false
+				status = "db unreachable"
+			} else {
+				dbState = "reachable"
+			}
+		}
+	}
+
+	if g.draining.Load() {
+		ready = false
+		status = "draining"
+	}
+
+	code := 200
+	if !ready {
+		code = 503
+	}
+	g.writeJSON(w, code, map[string]any{
+		"status":   status,
+		"channels": channelsMap,
+		"database": dbState,
+		"vault":    vaultState,
+	})
+}
+
+// devclawModelID is the virtual model id advertised to OpenAI-compatible
+// clients. Requests naming it run the full agent loop (tools + memory) on the
+// assistant's default model, so clients don't need to know the backing LLM.
+const devclawModelID = "devclaw"
+
+// handleListModels implements GET /v1/models (OpenAI-compatible). Most
+// clients list models before chatting; DevClaw advertises itself as a single
+// virtual model.
+func (g *Gateway) handleListModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		g.writeError(w, "method not allowed", 405)
+		return
+	}
+	g.writeJSON(w, 200, map[string]any{
+		"object": "list",
+		"data": []map[string]any{
+			{
+				"id":       devclawModelID,
+				"object":   "model",
+				"created":  time.Now().Unix(),
+				"owned_by": "devclaw",
+			},
+		},
+	})
+}
+
 // handleChatCompletions implements POST /v1/chat/completions (OpenAI-compatible)
 func (g *Gateway) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -164,8 +263,10 @@ func (g *Gateway) handleChatCompletions(w http.ResponseWriter, r *http.Request)
 	resolved := g.assistant.WorkspaceManager().Resolve("api", chatID, "api-client", false)
 	session := resolved.Session
 	workspace := resolved.Workspace
+	// The virtual "devclaw" model id maps to the configured default; passing
+	// it through as a model override would break the LLM call.
 	model := req.Model
-	if model == "" {
+	if model == "" || model == devclawModelID {
 		model = g.assistant.Config().Model
 		if workspace.Model != "" {
 			model = workspace.Model
@@ -283,22 +384,22 @@ func (g *Gateway) handleChatStream(w http.ResponseWriter, r *http.Request, sessi
 			Choices: []struct {
 				Index   int `json:"index"`
 				Message *struct {
-					Role       string          `json:"role"`
-					Content    string          `json:"content"`
-					ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+					Role      string           `json:"role"`
+					Content   string           `json:"content"`
+					ToolCalls []openAIToolCall `json:"tool_calls,omitempty"`
 				} `json:"message,omitempty"`
-				Delta        *struct {
-					Role       string `json:"role,omitempty"`
-					Content    string `json:"content,omitempty"`
-					ToolCalls  []openAIStreamToolCall `json:"tool_calls,omitempty"`
+				Delta *struct {
+					Role      string                 `json:"role,omitempty"`
+					Content   string                 `json:"content,omitempty"`
+					ToolCalls []openAIStreamToolCall `json:"tool_calls,omitempty"`
 				} `json:"delta,omitempty"`
 				FinishReason *string `json:"finish_reason"`
 			}{{
 				Index: 0,
 				Delta: &struct {
-					Role       string `json:"role,omitempty"`
-					Content    string `json:"content,omitempty"`
-					ToolCalls  []openAIStreamToolCall `json:"tool_calls,omitempty"`
+					Role      string                 `json:"role,omitempty"`
+					Content   string                 `json:"content,omitempty"`
+					ToolCalls []openAIStreamToolCall `json:"tool_calls,omitempty"`
 				}{Content: chunk},
 			}},
 		}
@@ -328,14 +429,14 @@ func (g *Gateway) handleChatStream(w http.ResponseWriter, r *http.Request, sessi
 		Choices: []struct {
 			Index   int `json:"index"`
 			Message *struct {
-				Role       string          `json:"role"`
-				Content    string          `json:"content"`
-				ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+				Role      string           `json:"role"`
+				Content   string           `json:"content"`
+				ToolCalls []openAIToolCall `json:"tool_calls,omitempty"`
 			} `json:"message,omitempty"`
-			Delta        *struct {
-				Role       string `json:"role,omitempty"`
-				Content    string `json:"content,omitempty"`
-				ToolCalls  []openAIStreamToolCall `json:"tool_calls,omitempty"`
+			Delta *struct {
+				Role      string                 `json:"role,omitempty"`
+				Content   string                 `json:"content,omitempty"`
+				ToolCalls []openAIStreamToolCall `json:"tool_calls,omitempty"`
 			} `json:"delta,omitempty"`
 			FinishReason *string `json:"finish_reason"`
 		}{{Index: 0, FinishReason: strPtr("stop")}},
@@ -364,22 +465,22 @@ func (g *Gateway) writeOpenAINonStream(w http.ResponseWriter, model, content str
 		Choices: []struct {
 			Index   int `json:"index"`
 			Message *struct {
-				Role       string          `json:"role"`
-				Content    string          `json:"content"`
-				ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+				Role      string           `json:"role"`
+				Content   string           `json:"content"`
+				ToolCalls []openAIToolCall `json:"tool_calls,omitempty"`
 			} `json:"message,omitempty"`
-			Delta        *struct {
-				Role       string `json:"role,omitempty"`
-				Content    string `json:"content,omitempty"`
-				ToolCalls  []openAIStreamToolCall `json:"tool_calls,omitempty"`
+			Delta *struct {
+				Role      string                 `json:"role,omitempty"`
+				Content   string                 `json:"content,omitempty"`
+				ToolCalls []openAIStreamToolCall `json:"tool_calls,omitempty"`
 			} `json:"delta,omitempty"`
 			FinishReason *string `json:"finish_reason"`
 		}{{
 			Index: 0,
 			Message: &struct {
-				Role       string          `json:"role"`
-				Content    string          `json:"content"`
-				ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+				Role      string           `json:"role"`
+				Content   string           `json:"content"`
+				ToolCalls []openAIToolCall `json:"tool_calls,omitempty"`
 			}{Role: "assistant", Content: content},
 		}},
 	}
@@ -435,10 +536,10 @@ func (g *Gateway) handleGlobalUsage(w http.ResponseWriter, r *http.Request) {
 	u := ut.GetGlobal()
 	g.writeJSON(w, 200, map[string]any{
 		"usage": map[string]any{
-			"prompt_tokens":     u.PromptTokens,
-			"completion_tokens": u.CompletionTokens,
-			"total_tokens":      u.TotalTokens,
-			"requests":          u.Requests,
+			"prompt_tokens":      u.PromptTokens,
+			"completion_tokens":  u.CompletionTokens,
+			"total_tokens":       u.TotalTokens,
+			"requests":           u.Requests,
 			"estimated_cost_usd": u.EstimatedCostUSD,
 			"first_request_at":   u.FirstRequestAt,
 			"last_request_at":    u.LastRequestAt,
@@ -470,10 +571,10 @@ func (g *Gateway) handleSessionUsage(w http.ResponseWriter, r *http.Request) {
 	g.writeJSON(w, 200, map[string]any{
 		"session_id": sessionID,
 		"usage": map[string]any{
-			"prompt_tokens":     u.PromptTokens,
-			"completion_tokens": u.CompletionTokens,
-			"total_tokens":      u.TotalTokens,
-			"requests":          u.Requests,
+			"prompt_tokens":      u.PromptTokens,
+			"completion_tokens":  u.CompletionTokens,
+			"total_tokens":       u.TotalTokens,
+			"requests":           u.Requests,
 			"estimated_cost_usd": u.EstimatedCostUSD,
 			"first_request_at":   u.FirstRequestAt,
 			"last_request_at":    u.LastRequestAt,
@@ -510,11 +611,11 @@ func (g *Gateway) handleStatus(w http.ResponseWriter, r *http.Request) {
 		memoryStatus = "enabled"
 	}
 	g.writeJSON(w, 200, map[string]any{
-		"channels":   channelsMap,
-		"sessions":   sessionCount,
-		"skills":     skillsInfo,
-		"scheduler":  schedulerStatus,
-		"memory":     memoryStatus,
+		"channels":  channelsMap,
+		"sessions":  sessionCount,
+		"skills":    skillsInfo,
+		"scheduler": schedulerStatus,
+		"memory":    memoryStatus,
 	})
 }
 
@@ -614,3 +715,154 @@ func (g *Gateway) handleWebhookByID(w http.ResponseWriter, r *http.Request) {
 		g.writeError(w, "method not allowed", 405)
 	}
 }
+
+// handleArtifactByName implements GET /api/artifacts/:name — serves the
+// latest version of a stored artifact, or a specific one via ?version=N.
+func (g *Gateway) handleArtifactByName(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		g.writeError(w, "method not allowed", 405)
+		return
+	}
+	store := g.assistant.ArtifactStore()
+	if store == nil {
+		g.writeError(w, "artifact store not available", 404)
+		return
+	}
+
+	name, err := url.PathUnescape(strings.TrimPrefix(r.URL.Path, "/api/artifacts/"))
+	if err != nil || name == "" {
+		g.writeError(w, "artifact name required", 400)
+		return
+	}
+
+	version := 0
+	if v := r.URL.Query().Get("version"); v != "" {
+		version, err = strconv.Atoi(v)
+		if err != nil || version < 1 {
+			g.writeError(w, "invalid version", 400)
+			return
+		}
+	}
+
+	artifact, err := store.Get(name, version)
+	if err != nil {
+		g.writeError(w, "artifact not found", 404)
+		return
+	}
+
+	w.Header().Set("Content-Type", artifact.MimeType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", artifact.Name))
+	w.Header().Set("X-Artifact-Version", strconv.Itoa(artifact.Version))
+	_, _ = w.Write([]byte(artifact.Content))
+}
+
+// handleRuns implements GET /api/runs — lists recent run transcripts
+// (?limit=N, default 20).
+func (g *Gateway) handleRuns(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		g.writeError(w, "method not allowed", 405)
+		return
+	}
+	store := g.assistant.RunTraceStore()
+	if store == nil {
+		g.writeError(w, "run transcripts not available", 404)
+		return
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	traces, err := store.Recent(limit)
+	if err != nil {
+		g.writeError(w, err.Error(), 500)
+		return
+	}
+	g.writeJSON(w, 200, map[string]any{"runs": traces})
+}
+
+// handleRunByID implements GET /api/runs/{run-id} — returns one run's full
+// turn-by-turn transcript.
+func (g *Gateway) handleRunByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		g.writeError(w, "method not allowed", 405)
+		return
+	}
+	store := g.assistant.RunTraceStore()
+	if store == nil {
+		g.writeError(w, "run transcripts not available", 404)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/runs/")
+	if id == "" {
+		g.writeError(w, "run id required", 400)
+		return
+	}
+
+	trace, err := store.Get(id)
+	if err != nil {
+		g.writeError(w, "run not found", 404)
+		return
+	}
+	g.writeJSON(w, 200, trace)
+}
+
+// handleFederationDelegate implements POST /api/federation/delegate — accepts
+// a delegated task from a peer DevClaw instance, runs it through the agent
+// loop and returns the result. Authenticated with the federation token (see
+// copilot.FederationConfig), not the gateway auth token.
+func (g *Gateway) handleFederationDelegate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		g.writeError(w, "method not allowed", 405)
+		return
+	}
+	fed := g.assistant.Federation()
+	if fed == nil {
+		g.writeError(w, "federation not enabled", 404)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !fed.AcceptToken(token) {
+		g.writeError(w, "invalid federation token", 401)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 2*1024*1024))
+	if err != nil {
+		g.writeError(w, "failed to read body", 400)
+		return
+	}
+	var req struct {
+		Task string `json:"task"`
+		From string `json:"from"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		g.writeError(w, "invalid request body", 400)
+		return
+	}
+	if strings.TrimSpace(req.Task) == "" {
+		g.writeError(w, "task required", 400)
+		return
+	}
+	from := req.From
+	if from == "" {
+		from = "peer"
+	}
+	g.logger.Info("federation task received", "from", from, "task_len", len(req.Task))
+
+	// Each peer gets its own session so delegated tasks keep context between
+	// delegations without bleeding into local conversations.
+	resolved := g.assistant.WorkspaceManager().Resolve("federation", from, from, false)
+	session := resolved.Session
+	prompt := g.assistant.ComposePrompt(session, req.Task)
+	reqCtx := copilot.ContextWithCaller(r.Context(), copilot.AccessOwner, "federation:"+from)
+	reqCtx = copilot.ContextWithSession(reqCtx, session.ID)
+	resp := g.assistant.ExecuteAgent(reqCtx, prompt, session, req.Task)
+	session.AddMessage(req.Task, resp)
+
+	g.writeJSON(w, 200, map[string]any{"result": resp})
+}
@@ -17,7 +17,8 @@ func compareTokens(a, b string) bool {
 }
 
 // authMiddleware requires Authorization: Bearer <token> when authToken is non-empty.
-// Skips auth for /health. Applied to /api/* and /v1/* when token is set.
+// Skips auth for /health and the /healthz and /readyz probes. Applied to
+// /api/* and /v1/* when token is set.
 func (g *Gateway) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if g.config.AuthToken == "" {
@@ -25,7 +26,12 @@ func (g *Gateway) authMiddleware(next http.Handler) http.Handler {
 			return
 		}
 		path := r.URL.Path
-		if path == "/health" {
+		if path == "/health" || path == "/healthz" || path == "/readyz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Federation uses its own mutual token, enforced in the handler.
+		if path == "/api/federation/delegate" {
 			next.ServeHTTP(w, r)
 			return
 		}
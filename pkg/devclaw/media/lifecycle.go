@@ -0,0 +1,222 @@
+// Package media – lifecycle.go implements storage quotas and retention rules
+// for stored media: received media is deleted after a configurable age,
+// generated media after its own age limit, and per-workspace quotas evict the
+// oldest items when exceeded. Pinned items are never touched. Enforcement
+// runs in the media service cleanup loop.
+package media
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Media origins for lifecycle rules.
+const (
+	// OriginReceived marks media ingested from a channel (the default when
+	// no origin was recorded).
+	OriginReceived = "received"
+
+	// OriginGenerated marks media produced by the agent (image generation,
+	// TTS output, rendered documents).
+	OriginGenerated = "generated"
+)
+
+// LifecycleConfig configures media retention and per-workspace quotas.
+type LifecycleConfig struct {
+	// Enabled turns lifecycle enforcement on/off (default: false).
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// ReceivedMaxAgeDays deletes received media older than this
+	// (default 90, 0 = keep forever).
+	ReceivedMaxAgeDays int `yaml:"received_max_age_days" json:"received_max_age_days"`
+
+	// GeneratedMaxAgeDays deletes generated media older than this
+	// (default 30, 0 = keep forever).
+	GeneratedMaxAgeDays int `yaml:"generated_max_age_days" json:"generated_max_age_days"`
+
+	// DefaultQuotaMB is the per-workspace storage quota; the oldest unpinned
+	// items are evicted when exceeded (0 = unlimited).
+	DefaultQuotaMB int64 `yaml:"default_quota_mb" json:"default_quota_mb"`
+
+	// QuotaMB overrides the quota for specific workspaces.
+	QuotaMB map[string]int64 `yaml:"quota_mb" json:"quota_mb"`
+}
+
+// DefaultLifecycleConfig returns default lifecycle configuration.
+func DefaultLifecycleConfig() LifecycleConfig {
+	return LifecycleConfig{
+		Enabled:             false,
+		ReceivedMaxAgeDays:  90,
+		GeneratedMaxAgeDays: 30,
+	}
+}
+
+// quotaFor returns the quota in bytes for a workspace (0 = unlimited).
+func (c LifecycleConfig) quotaFor(workspace string) int64 {
+	if mb, ok := c.QuotaMB[workspace]; ok {
+		return mb * 1024 * 1024
+	}
+	return c.DefaultQuotaMB * 1024 * 1024
+}
+
+// LifecycleResult summarizes one enforcement pass.
+type LifecycleResult struct {
+	AgedOut      int   // deleted by age rules
+	QuotaEvicted int   // deleted to get back under quota
+	BytesFreed   int64 // total bytes reclaimed
+}
+
+// WorkspaceUsage reports storage consumption for one workspace.
+type WorkspaceUsage struct {
+	Workspace string
+	Files     int
+	Bytes     int64
+	Pinned    int
+	QuotaMB   int64 // 0 = unlimited
+}
+
+// mediaWorkspace returns the grouping key for quotas: the recorded
+// workspace, falling back to "default".
+func mediaWorkspace(m *StoredMedia) string {
+	if m.Workspace != "" {
+		return m.Workspace
+	}
+	return "default"
+}
+
+// mediaOrigin returns the lifecycle origin, defaulting to received.
+func mediaOrigin(m *StoredMedia) string {
+	if m.Origin != "" {
+		return m.Origin
+	}
+	return OriginReceived
+}
+
+// selectExpiredMedia picks the items the lifecycle rules would delete:
+// first by age per origin, then the oldest unpinned items of workspaces over
+// quota. Pinned and temporary items are never selected (temporary media has
+// its own TTL handled by DeleteExpired).
+func selectExpiredMedia(items []*StoredMedia, cfg LifecycleConfig, now time.Time) (aged, evicted []*StoredMedia) {
+	type workspaceState struct {
+		bytes int64
+		kept  []*StoredMedia
+	}
+	workspaces := make(map[string]*workspaceState)
+
+	for _, m := range items {
+		if m.Pinned || m.Temporary {
+			continue
+		}
+
+		maxAgeDays := cfg.ReceivedMaxAgeDays
+		if mediaOrigin(m) == OriginGenerated {
+			maxAgeDays = cfg.GeneratedMaxAgeDays
+		}
+		if maxAgeDays > 0 && now.Sub(m.CreatedAt) > time.Duration(maxAgeDays)*24*time.Hour {
+			aged = append(aged, m)
+			continue
+		}
+
+		ws := mediaWorkspace(m)
+		state := workspaces[ws]
+		if state == nil {
+			state = &workspaceState{}
+			workspaces[ws] = state
+		}
+		state.bytes += m.Size
+		state.kept = append(state.kept, m)
+	}
+
+	// Evict the oldest survivors of workspaces still over quota.
+	for ws, state := range workspaces {
+		quota := cfg.quotaFor(ws)
+		if quota <= 0 || state.bytes <= quota {
+			continue
+		}
+		sort.Slice(state.kept, func(i, j int) bool {
+			return state.kept[i].CreatedAt.Before(state.kept[j].CreatedAt)
+		})
+		for _, m := range state.kept {
+			if state.bytes <= quota {
+				break
+			}
+			evicted = append(evicted, m)
+			state.bytes -= m.Size
+		}
+	}
+
+	return aged, evicted
+}
+
+// EnforceLifecycle applies the retention rules and quotas, deleting selected
+// media. Called from the cleanup loop and usable on demand.
+func (s *MediaService) EnforceLifecycle(ctx context.Context) (*LifecycleResult, error) {
+	cfg := s.config.Lifecycle
+	result := &LifecycleResult{}
+	if !cfg.Enabled {
+		return result, nil
+	}
+
+	items, err := s.store.List(ctx, ListFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("listing media: %w", err)
+	}
+
+	aged, evicted := selectExpiredMedia(items, cfg, time.Now())
+	for _, m := range aged {
+		if err := s.store.Delete(ctx, m.ID); err != nil {
+			s.logger.Warn("lifecycle delete failed", "id", m.ID, "error", err)
+			continue
+		}
+		result.AgedOut++
+		result.BytesFreed += m.Size
+	}
+	for _, m := range evicted {
+		if err := s.store.Delete(ctx, m.ID); err != nil {
+			s.logger.Warn("quota eviction failed", "id", m.ID, "error", err)
+			continue
+		}
+		result.QuotaEvicted++
+		result.BytesFreed += m.Size
+	}
+
+	return result, nil
+}
+
+// UsageByWorkspace reports storage consumption per workspace, largest first.
+func (s *MediaService) UsageByWorkspace(ctx context.Context) ([]WorkspaceUsage, error) {
+	items, err := s.store.List(ctx, ListFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("listing media: %w", err)
+	}
+
+	byWorkspace := make(map[string]*WorkspaceUsage)
+	for _, m := range items {
+		ws := mediaWorkspace(m)
+		usage := byWorkspace[ws]
+		if usage == nil {
+			usage = &WorkspaceUsage{Workspace: ws, QuotaMB: s.config.Lifecycle.quotaFor(ws) / 1024 / 1024}
+			byWorkspace[ws] = usage
+		}
+		usage.Files++
+		usage.Bytes += m.Size
+		if m.Pinned {
+			usage.Pinned++
+		}
+	}
+
+	out := make([]WorkspaceUsage, 0, len(byWorkspace))
+	for _, usage := range byWorkspace {
+		out = append(out, *usage)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Bytes > out[j].Bytes })
+	return out, nil
+}
+
+// SetPinned pins or unpins a media item. Pinned items are exempt from all
+// lifecycle rules.
+func (s *MediaService) SetPinned(ctx context.Context, id string, pinned bool) error {
+	return s.store.SetPinned(ctx, id, pinned)
+}
@@ -0,0 +1,114 @@
+package media
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func lifecycleTestConfig() LifecycleConfig {
+	cfg := DefaultLifecycleConfig()
+	cfg.Enabled = true
+	return cfg
+}
+
+func TestSelectExpiredMedia_AgeRules(t *testing.T) {
+	now := time.Now()
+	items := []*StoredMedia{
+		{ID: "old-received", CreatedAt: now.AddDate(0, 0, -100)},
+		{ID: "fresh-received", CreatedAt: now.AddDate(0, 0, -10)},
+		{ID: "old-generated", Origin: OriginGenerated, CreatedAt: now.AddDate(0, 0, -40)},
+		{ID: "fresh-generated", Origin: OriginGenerated, CreatedAt: now.AddDate(0, 0, -10)},
+		{ID: "old-pinned", Pinned: true, CreatedAt: now.AddDate(0, 0, -400)},
+		{ID: "old-temp", Temporary: true, CreatedAt: now.AddDate(0, 0, -400)},
+	}
+
+	aged, evicted := selectExpiredMedia(items, lifecycleTestConfig(), now)
+	if len(evicted) != 0 {
+		t.Errorf("expected no quota evictions, got %v", evicted)
+	}
+	if len(aged) != 2 {
+		t.Fatalf("expected 2 aged-out items, got %v", aged)
+	}
+	got := map[string]bool{aged[0].ID: true, aged[1].ID: true}
+	if !got["old-received"] || !got["old-generated"] {
+		t.Errorf("unexpected aged-out set: %v", got)
+	}
+}
+
+func TestSelectExpiredMedia_Quota(t *testing.T) {
+	now := time.Now()
+	cfg := lifecycleTestConfig()
+	cfg.DefaultQuotaMB = 1 // 1MB
+	cfg.QuotaMB = map[string]int64{"work": 10}
+
+	mb := int64(1024 * 1024)
+	items := []*StoredMedia{
+		// "default" workspace holds 1.5MB — the oldest item is evicted.
+		{ID: "oldest", Size: mb / 2, CreatedAt: now.AddDate(0, 0, -5)},
+		{ID: "middle", Size: mb / 2, CreatedAt: now.AddDate(0, 0, -3)},
+		{ID: "newest", Size: mb / 2, CreatedAt: now.AddDate(0, 0, -1)},
+		// Pinned items never count toward eviction candidates.
+		{ID: "pinned", Size: 5 * mb, Pinned: true, CreatedAt: now.AddDate(0, 0, -9)},
+		// "work" has a higher override and stays under it.
+		{ID: "work-item", Workspace: "work", Size: 5 * mb, CreatedAt: now.AddDate(0, 0, -9)},
+	}
+
+	aged, evicted := selectExpiredMedia(items, cfg, now)
+	if len(aged) != 0 {
+		t.Errorf("expected no aged-out items, got %v", aged)
+	}
+	if len(evicted) != 1 || evicted[0].ID != "oldest" {
+		t.Fatalf("expected oldest item evicted, got %v", evicted)
+	}
+}
+
+func TestEnforceLifecycleAndUsage(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := StoreConfig{
+		BaseDir: filepath.Join(tmpDir, "media"),
+		TempDir: filepath.Join(tmpDir, "media", "temp"),
+	}
+	store := NewFileSystemStore(cfg, nil)
+	ctx := context.Background()
+
+	svcCfg := DefaultServiceConfig()
+	svcCfg.Lifecycle.Enabled = true
+	svcCfg.Lifecycle.QuotaMB = map[string]int64{"personal": 1}
+	svc := NewMediaService(store, nil, svcCfg, nil)
+
+	saved, err := store.Save(ctx, SaveRequest{
+		Data: []byte("payload"), Filename: "a.png", MimeType: "image/png",
+		Type: MediaTypeImage, Workspace: "personal",
+	})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	usage, err := svc.UsageByWorkspace(ctx)
+	if err != nil {
+		t.Fatalf("UsageByWorkspace: %v", err)
+	}
+	if len(usage) != 1 || usage[0].Workspace != "personal" || usage[0].Files != 1 || usage[0].QuotaMB != 1 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+
+	// Well under quota and age limits — nothing deleted.
+	result, err := svc.EnforceLifecycle(ctx)
+	if err != nil {
+		t.Fatalf("EnforceLifecycle: %v", err)
+	}
+	if result.AgedOut != 0 || result.QuotaEvicted != 0 {
+		t.Errorf("unexpected deletions: %+v", result)
+	}
+
+	// Pinning round-trips through the metadata file.
+	if err := svc.SetPinned(ctx, saved.ID, true); err != nil {
+		t.Fatalf("SetPinned: %v", err)
+	}
+	usage, _ = svc.UsageByWorkspace(ctx)
+	if usage[0].Pinned != 1 {
+		t.Errorf("expected 1 pinned item, got %+v", usage[0])
+	}
+}
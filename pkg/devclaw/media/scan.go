@@ -0,0 +1,273 @@
+// Package media – scan.go implements optional malware scanning for media:
+// attachments are checked against a ClamAV daemon socket or an external
+// scanner command before they are stored, enriched, or exposed to tools.
+// Flagged files are moved to a quarantine directory and never forwarded.
+package media
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScanConfig configures attachment malware scanning.
+type ScanConfig struct {
+	// Enabled turns scanning on/off (default: false).
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// ClamdSocket is the path to a clamd unix socket (e.g.
+	// /var/run/clamav/clamd.ctl). Preferred over Command when both are set.
+	ClamdSocket string `yaml:"clamd_socket" json:"clamd_socket"`
+
+	// Command is an external scanner invocation. The file path is appended,
+	// or substituted for a {file} placeholder when present (e.g.
+	// "clamscan --no-summary {file}"). Exit code 0 = clean, 1 = infected.
+	Command string `yaml:"command" json:"command"`
+
+	// TimeoutSeconds bounds one scan (default 30).
+	TimeoutSeconds int `yaml:"timeout_seconds" json:"timeout_seconds"`
+
+	// QuarantineDir is where flagged files are moved (default:
+	// ./data/media/quarantine).
+	QuarantineDir string `yaml:"quarantine_dir" json:"quarantine_dir"`
+
+	// FailOpen allows files through when the scanner itself fails (socket
+	// down, command missing). Default false: scanner errors block the file.
+	FailOpen bool `yaml:"fail_open" json:"fail_open"`
+}
+
+// DefaultScanConfig returns default scanning configuration.
+func DefaultScanConfig() ScanConfig {
+	return ScanConfig{
+		Enabled:        false,
+		TimeoutSeconds: 30,
+	}
+}
+
+// ScanResult is the outcome of scanning one file.
+type ScanResult struct {
+	Clean  bool
+	Threat string // signature name when not clean
+}
+
+// ErrInfectedMedia is returned when a file fails the malware scan.
+var ErrInfectedMedia = errors.New("media failed malware scan")
+
+// MediaScanner checks attachments against ClamAV or an external command.
+type MediaScanner struct {
+	config ScanConfig
+	logger *slog.Logger
+}
+
+// NewMediaScanner creates a scanner from config. Returns nil when scanning
+// is disabled or no backend is configured.
+func NewMediaScanner(cfg ScanConfig, logger *slog.Logger) *MediaScanner {
+	if !cfg.Enabled || (cfg.ClamdSocket == "" && cfg.Command == "") {
+		return nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = DefaultScanConfig().TimeoutSeconds
+	}
+	return &MediaScanner{
+		config: cfg,
+		logger: logger.With("component", "media-scanner"),
+	}
+}
+
+// Scan checks the data and returns the verdict. Scanner failures return an
+// error — the caller decides fail-open vs fail-closed via ScanConfig.
+func (m *MediaScanner) Scan(ctx context.Context, data []byte) (ScanResult, error) {
+	scanCtx, cancel := context.WithTimeout(ctx, time.Duration(m.config.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	if m.config.ClamdSocket != "" {
+		return scanWithClamd(scanCtx, m.config.ClamdSocket, data)
+	}
+	return scanWithCommand(scanCtx, m.config.Command, data)
+}
+
+// scanWithClamd streams the data to a clamd unix socket (INSTREAM protocol).
+func scanWithClamd(ctx context.Context, socket string, data []byte) (ScanResult, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", socket)
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("connecting to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return ScanResult{}, fmt.Errorf("clamd handshake: %w", err)
+	}
+
+	// Stream the payload in length-prefixed chunks, terminated by a
+	// zero-length chunk.
+	const chunkSize = 64 * 1024
+	size := make([]byte, 4)
+	for off := 0; off < len(data); off += chunkSize {
+		end := off + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		binary.BigEndian.PutUint32(size, uint32(end-off))
+		if _, err := conn.Write(size); err != nil {
+			return ScanResult{}, fmt.Errorf("clamd stream: %w", err)
+		}
+		if _, err := conn.Write(data[off:end]); err != nil {
+			return ScanResult{}, fmt.Errorf("clamd stream: %w", err)
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return ScanResult{}, fmt.Errorf("clamd stream: %w", err)
+	}
+
+	reply, err := io.ReadAll(io.LimitReader(conn, 4096))
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("clamd response: %w", err)
+	}
+	return parseClamdReply(string(reply))
+}
+
+// parseClamdReply interprets a clamd scan response line.
+func parseClamdReply(reply string) (ScanResult, error) {
+	reply = strings.Trim(reply, "\x00\n ")
+	switch {
+	case strings.HasSuffix(reply, "OK"):
+		return ScanResult{Clean: true}, nil
+	case strings.HasSuffix(reply, "FOUND"):
+		threat := strings.TrimSuffix(reply, " FOUND")
+		if idx := strings.LastIndex(threat, ": "); idx >= 0 {
+			threat = threat[idx+2:]
+		}
+		return ScanResult{Clean: false, Threat: threat}, nil
+	default:
+		return ScanResult{}, fmt.Errorf("unexpected clamd reply: %s", reply)
+	}
+}
+
+// scanWithCommand writes the data to a temp file and runs the external
+// scanner on it. Exit 0 = clean, exit 1 = infected (clamscan convention).
+func scanWithCommand(ctx context.Context, command string, data []byte) (ScanResult, error) {
+	tmpFile, err := os.CreateTemp("", "devclaw-scan-*")
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("creating scan temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if err := tmpFile.Chmod(0600); err != nil {
+		tmpFile.Close()
+		return ScanResult{}, fmt.Errorf("restricting scan temp file: %w", err)
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return ScanResult{}, fmt.Errorf("writing scan temp file: %w", err)
+	}
+	tmpFile.Close()
+
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		return ScanResult{}, errors.New("empty scan command")
+	}
+	substituted := false
+	for i, p := range parts {
+		if strings.Contains(p, "{file}") {
+			parts[i] = strings.ReplaceAll(p, "{file}", tmpFile.Name())
+			substituted = true
+		}
+	}
+	if !substituted {
+		parts = append(parts, tmpFile.Name())
+	}
+
+	out, err := exec.CommandContext(ctx, parts[0], parts[1:]...).CombinedOutput()
+	if err == nil {
+		return ScanResult{Clean: true}, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		threat := strings.TrimSpace(string(out))
+		if threat == "" {
+			threat = "unknown threat"
+		}
+		return ScanResult{Clean: false, Threat: firstLine(threat)}, nil
+	}
+	return ScanResult{}, fmt.Errorf("scan command failed: %w (%s)", err, firstLine(string(out)))
+}
+
+// firstLine truncates multi-line scanner output to its first line.
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return strings.TrimSpace(s[:idx])
+	}
+	return strings.TrimSpace(s)
+}
+
+// checkScan runs the scanner on the data and quarantines flagged files.
+// Returns nil when the data may proceed. A nil service scanner passes
+// everything through.
+func (s *MediaService) checkScan(ctx context.Context, data []byte, filename string) error {
+	if s.scanner == nil {
+		return nil
+	}
+
+	result, err := s.scanner.Scan(ctx, data)
+	if err != nil {
+		if s.config.Scan.FailOpen {
+			s.logger.Warn("malware scan failed, allowing file (fail_open)", "filename", filename, "error", err)
+			return nil
+		}
+		return fmt.Errorf("malware scan unavailable: %w", err)
+	}
+	if result.Clean {
+		return nil
+	}
+
+	s.logger.Warn("malware scan flagged attachment",
+		"filename", filename, "threat", result.Threat)
+	if path, qErr := s.quarantine(data, filename, result.Threat); qErr != nil {
+		s.logger.Warn("failed to quarantine flagged file", "filename", filename, "error", qErr)
+	} else {
+		s.logger.Info("flagged file quarantined", "path", path)
+	}
+	return fmt.Errorf("%w: %s (%s)", ErrInfectedMedia, filename, result.Threat)
+}
+
+// quarantine writes a flagged file plus a note with the threat name to the
+// quarantine directory and returns the stored path.
+func (s *MediaService) quarantine(data []byte, filename, threat string) (string, error) {
+	dir := s.config.Scan.QuarantineDir
+	if dir == "" {
+		dir = filepath.Join("./data/media", "quarantine")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("creating quarantine directory: %w", err)
+	}
+
+	id := uuid.New().String()
+	path := filepath.Join(dir, id+".bin")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("writing quarantined file: %w", err)
+	}
+	note := fmt.Sprintf("filename: %s\nthreat: %s\nquarantined_at: %s\n",
+		sanitizeFilename(filename), threat, time.Now().UTC().Format(time.RFC3339))
+	if err := os.WriteFile(path+".txt", []byte(note), 0600); err != nil {
+		return "", fmt.Errorf("writing quarantine note: %w", err)
+	}
+	return path, nil
+}
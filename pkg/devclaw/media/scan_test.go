@@ -0,0 +1,193 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseClamdReply(t *testing.T) {
+	tests := []struct {
+		reply   string
+		clean   bool
+		threat  string
+		wantErr bool
+	}{
+		{reply: "stream: OK\x00", clean: true},
+		{reply: "stream: Eicar-Signature FOUND\x00", clean: false, threat: "Eicar-Signature"},
+		{reply: "INSTREAM size limit exceeded. ERROR\x00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		result, err := parseClamdReply(tt.reply)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseClamdReply(%q): expected error", tt.reply)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseClamdReply(%q): %v", tt.reply, err)
+			continue
+		}
+		if result.Clean != tt.clean || result.Threat != tt.threat {
+			t.Errorf("parseClamdReply(%q) = %+v, want clean=%v threat=%q",
+				tt.reply, result, tt.clean, tt.threat)
+		}
+	}
+}
+
+func TestScanWithClamd(t *testing.T) {
+	// Fake clamd: drains the INSTREAM payload and reports the EICAR
+	// signature.
+	socket := filepath.Join(t.TempDir(), "clamd.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 4096)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			// Terminating zero-length chunk ends the stream.
+			if n >= 4 && buf[n-4] == 0 && buf[n-3] == 0 && buf[n-2] == 0 && buf[n-1] == 0 {
+				break
+			}
+		}
+		_, _ = conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+	}()
+
+	result, err := scanWithClamd(context.Background(), socket, []byte("payload"))
+	if err != nil {
+		t.Fatalf("scanWithClamd: %v", err)
+	}
+	if result.Clean || result.Threat != "Eicar-Test-Signature" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestScanWithCommand(t *testing.T) {
+	ctx := context.Background()
+
+	clean, err := scanWithCommand(ctx, "true", []byte("harmless"))
+	if err != nil {
+		t.Fatalf("clean scan: %v", err)
+	}
+	if !clean.Clean {
+		t.Errorf("expected clean verdict, got %+v", clean)
+	}
+
+	// Exit code 1 means infected (clamscan convention).
+	script := filepath.Join(t.TempDir(), "fake-scanner.sh")
+	if err := os.WriteFile(script, []byte("echo \"$1: Eicar-Test FOUND\"\nexit 1\n"), 0700); err != nil {
+		t.Fatalf("writing fake scanner: %v", err)
+	}
+	infected, err := scanWithCommand(ctx, "sh "+script+" {file}", []byte("payload"))
+	if err != nil {
+		t.Fatalf("infected scan: %v", err)
+	}
+	if infected.Clean || infected.Threat == "" {
+		t.Errorf("expected infected verdict with threat name, got %+v", infected)
+	}
+
+	// Other exit codes are scanner failures, not verdicts.
+	if _, err := scanWithCommand(ctx, "sh -c exit_2", nil); err == nil {
+		t.Error("expected error for failing scanner command")
+	}
+}
+
+func TestCheckScanQuarantinesInfected(t *testing.T) {
+	tmpDir := t.TempDir()
+	svcCfg := DefaultServiceConfig()
+	svcCfg.Scan.Enabled = true
+	svcCfg.Scan.Command = "false" // exit 1 → everything is "infected"
+	svcCfg.Scan.QuarantineDir = filepath.Join(tmpDir, "quarantine")
+
+	store := NewFileSystemStore(StoreConfig{
+		BaseDir: filepath.Join(tmpDir, "media"),
+		TempDir: filepath.Join(tmpDir, "media", "temp"),
+	}, nil)
+	svc := NewMediaService(store, nil, svcCfg, nil)
+	ctx := context.Background()
+
+	_, err := svc.Upload(ctx, UploadRequest{
+		Data:     []byte("\x89PNG\r\n\x1a\nfake"),
+		Filename: "evil.png",
+		MimeType: "image/png",
+	})
+	if !errors.Is(err, ErrInfectedMedia) {
+		t.Fatalf("expected ErrInfectedMedia, got %v", err)
+	}
+
+	// Nothing reached the store.
+	items, err := store.List(ctx, ListFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected empty store, got %d items", len(items))
+	}
+
+	// Flagged payload landed in quarantine (file + note).
+	quarantined, err := filepath.Glob(filepath.Join(svcCfg.Scan.QuarantineDir, "*.bin"))
+	if err != nil || len(quarantined) != 1 {
+		t.Fatalf("expected 1 quarantined file, got %v (%v)", quarantined, err)
+	}
+}
+
+func TestCheckScanFailOpen(t *testing.T) {
+	tmpDir := t.TempDir()
+	svcCfg := DefaultServiceConfig()
+	svcCfg.Scan.Enabled = true
+	svcCfg.Scan.Command = "/nonexistent-scanner"
+	svcCfg.Scan.FailOpen = true
+
+	store := NewFileSystemStore(StoreConfig{
+		BaseDir: filepath.Join(tmpDir, "media"),
+		TempDir: filepath.Join(tmpDir, "media", "temp"),
+	}, nil)
+	svc := NewMediaService(store, nil, svcCfg, nil)
+
+	if _, err := svc.Upload(context.Background(), UploadRequest{
+		Data:     []byte("\x89PNG\r\n\x1a\nfake"),
+		Filename: "a.png",
+		MimeType: "image/png",
+	}); err != nil {
+		t.Fatalf("fail_open upload should succeed, got %v", err)
+	}
+
+	// Fail-closed: same broken scanner blocks the file.
+	svcCfg.Scan.FailOpen = false
+	strict := NewMediaService(store, nil, svcCfg, nil)
+	if _, err := strict.Upload(context.Background(), UploadRequest{
+		Data:     []byte("\x89PNG\r\n\x1a\nfake"),
+		Filename: "b.png",
+		MimeType: "image/png",
+	}); err == nil {
+		t.Fatal("fail-closed upload should be blocked when scanner is unavailable")
+	}
+}
+
+func TestNewMediaScannerDisabled(t *testing.T) {
+	if s := NewMediaScanner(DefaultScanConfig(), nil); s != nil {
+		t.Error("disabled config should yield nil scanner")
+	}
+	cfg := DefaultScanConfig()
+	cfg.Enabled = true // enabled but no backend configured
+	if s := NewMediaScanner(cfg, nil); s != nil {
+		t.Error("config without backend should yield nil scanner")
+	}
+}
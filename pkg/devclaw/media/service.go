@@ -23,6 +23,8 @@ type UploadRequest struct {
 	MimeType  string
 	Channel   string
 	SessionID string
+	Workspace string
+	Origin    string
 	Temporary bool
 }
 
@@ -66,6 +68,7 @@ func DefaultEnrichmentConfig() EnrichmentConfig {
 type MediaService struct {
 	store      MediaStore
 	validator  *Validator
+	scanner    *MediaScanner
 	channelMgr *channels.Manager
 	config     ServiceConfig
 	enrichCfg  EnrichmentConfig
@@ -131,6 +134,7 @@ func NewMediaService(store MediaStore, channelMgr *channels.Manager, cfg Service
 	s := &MediaService{
 		store:      store,
 		validator:  NewValidator(cfg),
+		scanner:    NewMediaScanner(cfg.Scan, logger),
 		channelMgr: channelMgr,
 		config:     cfg,
 		enrichCfg:  DefaultEnrichmentConfig(),
@@ -165,6 +169,11 @@ func (s *MediaService) Upload(ctx context.Context, req UploadRequest) (*StoredMe
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	// Malware scan before the file is stored, enriched, or exposed to tools
+	if err := s.checkScan(ctx, req.Data, req.Filename); err != nil {
+		return nil, err
+	}
+
 	// Parse TTL
 	var ttl time.Duration
 	if req.Temporary {
@@ -182,6 +191,8 @@ func (s *MediaService) Upload(ctx context.Context, req UploadRequest) (*StoredMe
 		Type:      result.Type,
 		Channel:   req.Channel,
 		SessionID: req.SessionID,
+		Workspace: req.Workspace,
+		Origin:    req.Origin,
 		Temporary: req.Temporary,
 		TTL:       ttl,
 	})
@@ -301,6 +312,12 @@ func (s *MediaService) SendToChannel(ctx context.Context, channelName, to string
 		return fmt.Errorf("getting media: %w", err)
 	}
 
+	// Refuse to forward flagged files (also covers media stored before
+	// scanning was enabled).
+	if err := s.checkScan(ctx, data, media.Filename); err != nil {
+		return err
+	}
+
 	msg := &channels.MediaMessage{
 		Type:     channels.MessageType(media.Type),
 		Data:     data,
@@ -347,6 +364,10 @@ func (s *MediaService) ResolveMediaSource(ctx context.Context, source map[string
 		}
 
 		filename := filepath.Base(filePath)
+		if err := s.checkScan(ctx, data, filename); err != nil {
+			return nil, "", "", err
+		}
+
 		mimeType := http.DetectContentType(data)
 		return data, mimeType, filename, nil
 	}
@@ -364,6 +385,10 @@ func (s *MediaService) ResolveMediaSource(ctx context.Context, source map[string
 			return nil, "", "", fmt.Errorf("downloading from URL: %w", err)
 		}
 
+		if err := s.checkScan(ctx, data, filename); err != nil {
+			return nil, "", "", err
+		}
+
 		mimeType := http.DetectContentType(data)
 		return data, mimeType, filename, nil
 	}
@@ -399,6 +424,20 @@ func (s *MediaService) StartCleanup(ctx context.Context) {
 			} else if count > 0 {
 				s.logger.Info("cleaned up expired media", "count", count)
 			}
+
+			// Retention rules and per-workspace quotas.
+			if s.config.Lifecycle.Enabled {
+				result, err := s.EnforceLifecycle(ctx)
+				if err != nil {
+					s.logger.Warn("media lifecycle error", "error", err)
+				} else if result.AgedOut > 0 || result.QuotaEvicted > 0 {
+					s.logger.Info("media lifecycle enforced",
+						"aged_out", result.AgedOut,
+						"quota_evicted", result.QuotaEvicted,
+						"bytes_freed", result.BytesFreed,
+					)
+				}
+			}
 		}
 	}
 }
@@ -38,6 +38,9 @@ type StoredMedia struct {
 	Size      int64          `json:"size"`
 	Channel   string         `json:"channel"`
 	SessionID string         `json:"session_id,omitempty"`
+	Workspace string         `json:"workspace,omitempty"`
+	Origin    string         `json:"origin,omitempty"` // OriginReceived / OriginGenerated
+	Pinned    bool           `json:"pinned,omitempty"`
 	Temporary bool           `json:"temporary"`
 	CreatedAt time.Time      `json:"created_at"`
 	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
@@ -52,6 +55,8 @@ type SaveRequest struct {
 	Type      MediaType
 	Channel   string
 	SessionID string
+	Workspace string
+	Origin    string
 	Temporary bool
 	TTL       time.Duration
 	Metadata  map[string]any
@@ -87,6 +92,9 @@ type MediaStore interface {
 	// DeleteExpired removes all temporary media past their expiration.
 	DeleteExpired(ctx context.Context) (int, error)
 
+	// SetPinned pins or unpins media, exempting it from lifecycle rules.
+	SetPinned(ctx context.Context, id string, pinned bool) error
+
 	// URL returns a URL for accessing the media.
 	URL(id string) string
 }
@@ -200,6 +208,8 @@ func (s *FileSystemStore) Save(ctx context.Context, req SaveRequest) (*StoredMed
 		Size:      int64(len(req.Data)),
 		Channel:   req.Channel,
 		SessionID: req.SessionID,
+		Workspace: req.Workspace,
+		Origin:    req.Origin,
 		Temporary: req.Temporary,
 		CreatedAt: now,
 		Metadata:  req.Metadata,
@@ -450,6 +460,33 @@ func (s *FileSystemStore) DeleteExpired(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+// SetPinned pins or unpins media, rewriting its metadata file.
+func (s *FileSystemStore) SetPinned(ctx context.Context, id string, pinned bool) error {
+	media, err := s.getMeta(id)
+	if err != nil {
+		return err
+	}
+
+	updated := *media
+	updated.Pinned = pinned
+
+	metaPath := filepath.Join(s.config.BaseDir, "meta", id+".json")
+	metaData, err := json.Marshal(&updated)
+	if err != nil {
+		return fmt.Errorf("marshaling metadata: %w", err)
+	}
+	if err := os.WriteFile(metaPath, metaData, 0600); err != nil {
+		return fmt.Errorf("writing metadata file: %w", err)
+	}
+
+	s.mu.Lock()
+	s.metaCache[id] = &updated
+	s.mu.Unlock()
+
+	s.logger.Debug("media pin updated", "id", id, "pinned", pinned)
+	return nil
+}
+
 // URL returns a URL for accessing the media.
 func (s *FileSystemStore) URL(id string) string {
 	return fmt.Sprintf("%s/%s", s.config.BaseURL, id)
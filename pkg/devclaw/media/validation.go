@@ -30,10 +30,16 @@ var AllowedMimeTypes = map[MediaType][]string{
 	MediaTypeDocument: {
 		"application/pdf",
 		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"application/vnd.oasis.opendocument.spreadsheet",
 		"text/plain",
 		"text/csv",
 		"application/json",
 		"text/markdown",
+		"application/zip",
+		"application/gzip",
+		"application/x-gzip",
+		"application/x-tar",
 	},
 }
 
@@ -47,6 +53,12 @@ type ServiceConfig struct {
 	TempTTL         string `yaml:"temp_ttl" json:"temp_ttl"`
 	CleanupEnabled  bool   `yaml:"cleanup_enabled" json:"cleanup_enabled"`
 	CleanupInterval string `yaml:"cleanup_interval" json:"cleanup_interval"`
+
+	// Lifecycle configures retention rules and per-workspace quotas.
+	Lifecycle LifecycleConfig `yaml:"lifecycle" json:"lifecycle"`
+
+	// Scan configures malware scanning of attachments.
+	Scan ScanConfig `yaml:"scan" json:"scan"`
 }
 
 // DefaultServiceConfig returns default configuration.
@@ -60,6 +72,8 @@ func DefaultServiceConfig() ServiceConfig {
 		TempTTL:         "24h",
 		CleanupEnabled:  true,
 		CleanupInterval: "1h",
+		Lifecycle:       DefaultLifecycleConfig(),
+		Scan:            DefaultScanConfig(),
 	}
 }
 
@@ -164,12 +178,22 @@ func DetectMimeType(data []byte, filename string) string {
 			return "application/pdf"
 		case ".docx":
 			return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+		case ".xlsx":
+			return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		case ".ods":
+			return "application/vnd.oasis.opendocument.spreadsheet"
 		case ".txt", ".md":
 			return "text/plain"
 		case ".json":
 			return "application/json"
 		case ".csv":
 			return "text/csv"
+		case ".zip":
+			return "application/zip"
+		case ".gz", ".tgz":
+			return "application/gzip"
+		case ".tar":
+			return "application/x-tar"
 		}
 	}
 
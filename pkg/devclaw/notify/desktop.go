@@ -0,0 +1,113 @@
+// Package notify – desktop.go shows OS-level notifications on the host the
+// assistant runs on: notify-send on Linux, osascript on macOS, a toast via
+// PowerShell on Windows. For users who run the assistant locally without
+// always watching a chat.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// DesktopConfig configures the desktop notification sink.
+type DesktopConfig struct {
+	// Enabled toggles OS-level desktop notifications.
+	Enabled bool `yaml:"enabled"`
+}
+
+// DesktopSink shows alerts as native desktop notifications.
+type DesktopSink struct{}
+
+// NewDesktopSink creates a desktop notification sink.
+func NewDesktopSink() *DesktopSink {
+	return &DesktopSink{}
+}
+
+// Name returns "desktop".
+func (d *DesktopSink) Name() string { return "desktop" }
+
+// Push shows the alert as a native notification for the current OS.
+func (d *DesktopSink) Push(ctx context.Context, alert *Alert) error {
+	switch runtime.GOOS {
+	case "linux":
+		return pushNotifySend(ctx, alert)
+	case "darwin":
+		return pushOsascript(ctx, alert)
+	case "windows":
+		return pushWindowsToast(ctx, alert)
+	default:
+		return fmt.Errorf("desktop notifications not supported on %s", runtime.GOOS)
+	}
+}
+
+// pushNotifySend shows a notification via notify-send (libnotify).
+func pushNotifySend(ctx context.Context, alert *Alert) error {
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return fmt.Errorf("notify-send not installed")
+	}
+	cmd := exec.CommandContext(ctx, "notify-send",
+		"-u", desktopUrgency(alert.Priority),
+		"-a", "DevClaw",
+		alert.Title, alert.Message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// pushOsascript shows a macOS notification via AppleScript.
+func pushOsascript(ctx context.Context, alert *Alert) error {
+	script := fmt.Sprintf("display notification %s with title %s",
+		appleScriptString(alert.Message), appleScriptString(alert.Title))
+	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("osascript: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// pushWindowsToast shows a Windows toast notification via PowerShell.
+func pushWindowsToast(ctx context.Context, alert *Alert) error {
+	script := fmt.Sprintf(`
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$texts = $template.GetElementsByTagName('text')
+$texts.Item(0).AppendChild($template.CreateTextNode(%s)) | Out-Null
+$texts.Item(1).AppendChild($template.CreateTextNode(%s)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('DevClaw').Show($toast)`,
+		powerShellString(alert.Title), powerShellString(alert.Message))
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-Command", script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("powershell toast: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// desktopUrgency maps alert priority (1 urgent .. 5 background) onto
+// notify-send urgency levels.
+func desktopUrgency(priority int) string {
+	switch {
+	case priority <= 2:
+		return "critical"
+	case priority >= 4:
+		return "low"
+	default:
+		return "normal"
+	}
+}
+
+// appleScriptString quotes a string for embedding in AppleScript source.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	return "\"" + s + "\""
+}
+
+// powerShellString quotes a string as a PowerShell single-quoted literal.
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
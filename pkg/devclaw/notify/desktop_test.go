@@ -0,0 +1,49 @@
+package notify
+
+import "testing"
+
+func TestDesktopUrgency(t *testing.T) {
+	cases := []struct {
+		priority int
+		want     string
+	}{
+		{1, "critical"},
+		{2, "critical"},
+		{3, "normal"},
+		{4, "low"},
+		{5, "low"},
+	}
+	for _, tc := range cases {
+		if got := desktopUrgency(tc.priority); got != tc.want {
+			t.Errorf("desktopUrgency(%d) = %q, want %q", tc.priority, got, tc.want)
+		}
+	}
+}
+
+func TestAppleScriptString(t *testing.T) {
+	if got := appleScriptString(`say "hi" \ bye`); got != `"say \"hi\" \\ bye"` {
+		t.Errorf("unexpected quoting: %s", got)
+	}
+}
+
+func TestPowerShellString(t *testing.T) {
+	if got := powerShellString("it's done"); got != "'it''s done'" {
+		t.Errorf("unexpected quoting: %s", got)
+	}
+}
+
+func TestRouterIncludesDesktopSink(t *testing.T) {
+	r := NewRouter(Config{Enabled: true, Desktop: DesktopConfig{Enabled: true}}, nil)
+	if r == nil {
+		t.Fatal("expected router with desktop sink")
+	}
+	found := false
+	for _, s := range r.sinks {
+		if s.Name() == "desktop" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected desktop sink in router")
+	}
+}
@@ -0,0 +1,315 @@
+// Package notify implements push notification sinks for proactive alerts.
+// When the user isn't in an active chat (heartbeat findings, scheduled job
+// failures, incoming webhooks), short alerts are routed to configured sinks:
+// ntfy topics, Pushover, Apple push, or the local desktop — with
+// per-event-type routing rules.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Alert is a short push notification.
+type Alert struct {
+	// Event classifies the alert for routing (e.g. "heartbeat",
+	// "scheduler.failed", "webhook.received").
+	Event string
+
+	// Title is the short headline.
+	Title string
+
+	// Message is the alert body. Sinks truncate as needed.
+	Message string
+
+	// Priority ranges 1 (urgent) to 5 (background), matching the
+	// notification dispatcher's convention. Zero means 3 (normal).
+	Priority int
+}
+
+// Sink delivers alerts to one push backend.
+type Sink interface {
+	// Name returns the sink identifier used in routing rules.
+	Name() string
+
+	// Push delivers the alert.
+	Push(ctx context.Context, alert *Alert) error
+}
+
+// Rule routes events to sinks.
+type Rule struct {
+	// Events lists event types this rule matches. Supports a trailing
+	// wildcard segment ("scheduler.*"). Empty matches everything.
+	Events []string `yaml:"events"`
+
+	// MinPriority drops alerts less urgent than this (higher number =
+	// less urgent). Zero means no threshold.
+	MinPriority int `yaml:"min_priority"`
+
+	// Sinks names the sinks to deliver to ("ntfy", "pushover", "desktop").
+	Sinks []string `yaml:"sinks"`
+}
+
+// Config configures the push notification router.
+type Config struct {
+	// Enabled toggles push notifications globally.
+	Enabled bool `yaml:"enabled"`
+
+	// Ntfy configures the ntfy sink.
+	Ntfy NtfyConfig `yaml:"ntfy"`
+
+	// Pushover configures the Pushover sink.
+	Pushover PushoverConfig `yaml:"pushover"`
+
+	// Desktop configures native desktop notifications on the host.
+	Desktop DesktopConfig `yaml:"desktop"`
+
+	// Rules route event types to sinks. With no rules, every alert goes
+	// to all configured sinks.
+	Rules []Rule `yaml:"rules"`
+}
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		Ntfy: NtfyConfig{Server: "https://ntfy.sh"},
+	}
+}
+
+// Router fans alerts out to sinks according to routing rules.
+type Router struct {
+	cfg    Config
+	sinks  []Sink
+	logger *slog.Logger
+}
+
+// NewRouter builds a router with all sinks that have usable configuration.
+// Returns nil if push notifications are disabled or no sink is configured.
+func NewRouter(cfg Config, logger *slog.Logger) *Router {
+	if !cfg.Enabled {
+		return nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	var sinks []Sink
+	if cfg.Ntfy.Topic != "" {
+		sinks = append(sinks, NewNtfySink(cfg.Ntfy))
+	}
+	if cfg.Pushover.Token != "" && cfg.Pushover.User != "" {
+		sinks = append(sinks, NewPushoverSink(cfg.Pushover))
+	}
+	if cfg.Desktop.Enabled {
+		sinks = append(sinks, NewDesktopSink())
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	return &Router{
+		cfg:    cfg,
+		sinks:  sinks,
+		logger: logger.With("component", "notify"),
+	}
+}
+
+// Notify routes an alert to the matching sinks. Delivery errors are logged,
+// not returned — push notifications are best-effort by design.
+func (r *Router) Notify(ctx context.Context, alert *Alert) {
+	if r == nil || alert == nil {
+		return
+	}
+	if alert.Priority == 0 {
+		alert.Priority = 3
+	}
+
+	for _, sink := range r.sinks {
+		if !r.shouldDeliver(sink.Name(), alert) {
+			continue
+		}
+		pushCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		err := sink.Push(pushCtx, alert)
+		cancel()
+		if err != nil {
+			r.logger.Warn("push notification failed",
+				"sink", sink.Name(), "event", alert.Event, "error", err)
+		} else {
+			r.logger.Debug("push notification sent",
+				"sink", sink.Name(), "event", alert.Event)
+		}
+	}
+}
+
+// shouldDeliver applies routing rules for a sink/alert pair.
+func (r *Router) shouldDeliver(sinkName string, alert *Alert) bool {
+	if len(r.cfg.Rules) == 0 {
+		return true
+	}
+	for i := range r.cfg.Rules {
+		rule := &r.cfg.Rules[i]
+		if !ruleMatchesEvent(rule, alert.Event) {
+			continue
+		}
+		if rule.MinPriority > 0 && alert.Priority > rule.MinPriority {
+			continue
+		}
+		for _, name := range rule.Sinks {
+			if name == sinkName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ruleMatchesEvent checks the rule's event patterns against an event type.
+func ruleMatchesEvent(rule *Rule, event string) bool {
+	if len(rule.Events) == 0 {
+		return true
+	}
+	for _, pattern := range rule.Events {
+		if pattern == event {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(event, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------- ntfy ----------
+
+// NtfyConfig configures the ntfy sink.
+type NtfyConfig struct {
+	// Server is the ntfy server URL. Defaults to https://ntfy.sh.
+	Server string `yaml:"server"`
+
+	// Topic is the ntfy topic to publish to. Required to enable the sink.
+	Topic string `yaml:"topic"`
+
+	// Token is an optional access token for protected topics.
+	Token string `yaml:"token"`
+}
+
+// NtfySink publishes alerts to an ntfy topic.
+type NtfySink struct {
+	cfg    NtfyConfig
+	client *http.Client
+}
+
+// NewNtfySink creates an ntfy sink.
+func NewNtfySink(cfg NtfyConfig) *NtfySink {
+	if cfg.Server == "" {
+		cfg.Server = "https://ntfy.sh"
+	}
+	return &NtfySink{cfg: cfg, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Name returns "ntfy".
+func (n *NtfySink) Name() string { return "ntfy" }
+
+// Push publishes the alert to the topic.
+func (n *NtfySink) Push(ctx context.Context, alert *Alert) error {
+	endpoint := strings.TrimRight(n.cfg.Server, "/") + "/" + url.PathEscape(n.cfg.Topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(alert.Message))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Title", alert.Title)
+	// ntfy priority is inverted relative to ours: 5 = max, 1 = min.
+	req.Header.Set("Priority", strconv.Itoa(6-alert.Priority))
+	req.Header.Set("Tags", alert.Event)
+	if n.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.cfg.Token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("publish to ntfy: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("ntfy returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ---------- Pushover ----------
+
+// PushoverConfig configures the Pushover sink.
+type PushoverConfig struct {
+	// Token is the Pushover application API token.
+	Token string `yaml:"token"`
+
+	// User is the Pushover user or group key.
+	User string `yaml:"user"`
+
+	// Device optionally targets a specific device name.
+	Device string `yaml:"device"`
+}
+
+// PushoverSink delivers alerts via the Pushover API.
+type PushoverSink struct {
+	cfg    PushoverConfig
+	client *http.Client
+}
+
+// NewPushoverSink creates a Pushover sink.
+func NewPushoverSink(cfg PushoverConfig) *PushoverSink {
+	return &PushoverSink{cfg: cfg, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Name returns "pushover".
+func (p *PushoverSink) Name() string { return "pushover" }
+
+// Push delivers the alert via api.pushover.net.
+func (p *PushoverSink) Push(ctx context.Context, alert *Alert) error {
+	form := url.Values{
+		"token":   {p.cfg.Token},
+		"user":    {p.cfg.User},
+		"title":   {alert.Title},
+		"message": {alert.Message},
+		// Pushover priorities run -2 (lowest) to 2 (emergency); map our
+		// 1..5 onto 2..-2 and clamp away from 2, which requires retry params.
+		"priority": {strconv.Itoa(clampPushoverPriority(3 - alert.Priority))},
+	}
+	if p.cfg.Device != "" {
+		form.Set("device", p.cfg.Device)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://api.pushover.net/1/messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send to pushover: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pushover returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// clampPushoverPriority keeps priorities in Pushover's non-emergency range.
+func clampPushoverPriority(p int) int {
+	if p > 1 {
+		return 1
+	}
+	if p < -2 {
+		return -2
+	}
+	return p
+}
@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewRouter(t *testing.T) {
+	t.Run("nil when disabled", func(t *testing.T) {
+		if r := NewRouter(Config{}, nil); r != nil {
+			t.Error("expected nil router when disabled")
+		}
+	})
+
+	t.Run("nil when no sink configured", func(t *testing.T) {
+		if r := NewRouter(Config{Enabled: true}, nil); r != nil {
+			t.Error("expected nil router without sinks")
+		}
+	})
+
+	t.Run("nil router Notify is safe", func(t *testing.T) {
+		var r *Router
+		r.Notify(context.Background(), &Alert{Event: "test", Message: "hi"})
+	})
+}
+
+func TestRuleMatchesEvent(t *testing.T) {
+	cases := []struct {
+		name    string
+		events  []string
+		event   string
+		matches bool
+	}{
+		{"empty matches all", nil, "heartbeat", true},
+		{"exact match", []string{"heartbeat"}, "heartbeat", true},
+		{"no match", []string{"heartbeat"}, "scheduler.failed", false},
+		{"wildcard prefix", []string{"scheduler.*"}, "scheduler.failed", true},
+		{"wildcard no match", []string{"scheduler.*"}, "webhook.received", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := &Rule{Events: tc.events}
+			if got := ruleMatchesEvent(rule, tc.event); got != tc.matches {
+				t.Errorf("expected %v, got %v", tc.matches, got)
+			}
+		})
+	}
+}
+
+func TestNtfySinkPush(t *testing.T) {
+	var gotTitle, gotPriority string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTitle = r.Header.Get("Title")
+		gotPriority = r.Header.Get("Priority")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	sink := NewNtfySink(NtfyConfig{Server: srv.URL, Topic: "devclaw"})
+	err := sink.Push(context.Background(), &Alert{
+		Event:    "heartbeat",
+		Title:    "Test alert",
+		Message:  "body",
+		Priority: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotTitle != "Test alert" {
+		t.Errorf("expected title header, got %q", gotTitle)
+	}
+	if gotPriority != "4" {
+		t.Errorf("expected inverted priority 4, got %q", gotPriority)
+	}
+}
+
+func TestRouterRouting(t *testing.T) {
+	cfg := Config{
+		Enabled: true,
+		Ntfy:    NtfyConfig{Topic: "t"},
+		Rules: []Rule{
+			{Events: []string{"scheduler.*"}, Sinks: []string{"ntfy"}},
+		},
+	}
+	r := NewRouter(cfg, nil)
+	if r == nil {
+		t.Fatal("expected router")
+	}
+
+	if !r.shouldDeliver("ntfy", &Alert{Event: "scheduler.failed", Priority: 3}) {
+		t.Error("expected scheduler.failed to route to ntfy")
+	}
+	if r.shouldDeliver("ntfy", &Alert{Event: "heartbeat", Priority: 3}) {
+		t.Error("expected heartbeat to be filtered out")
+	}
+}
@@ -17,8 +17,23 @@ const StateDirEnv = "DEVCLAW_STATE_DIR"
 // ConfigPathEnv is the environment variable for custom config path.
 const ConfigPathEnv = "DEVCLAW_CONFIG_PATH"
 
+// stateDirOverride is set via SetStateDir and takes precedence over the
+// environment variable.
+var stateDirOverride string
+
+// SetStateDir overrides the state directory for this process. Used by the
+// --data-dir flag to relocate all state (db, memory, media, skills) under
+// a single mount point for containerized deployments.
+func SetStateDir(dir string) {
+	stateDirOverride = dir
+}
+
 // ResolveStateDir returns the project root directory.
 func ResolveStateDir() string {
+	// Explicit override (--data-dir) wins.
+	if stateDirOverride != "" {
+		return stateDirOverride
+	}
 	// Check environment variable first
 	if dir := os.Getenv(StateDirEnv); dir != "" {
 		return dir
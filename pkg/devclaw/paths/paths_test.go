@@ -17,6 +17,21 @@ func TestResolveStateDir(t *testing.T) {
 		}
 	})
 
+	t.Run("explicit override wins over environment variable", func(t *testing.T) {
+		os.Setenv(StateDirEnv, "/tmp/env-devclaw-state")
+		defer os.Unsetenv(StateDirEnv)
+
+		SetStateDir("/mnt/devclaw-data")
+		defer SetStateDir("")
+
+		if got := ResolveStateDir(); got != "/mnt/devclaw-data" {
+			t.Errorf("ResolveStateDir() = %q, want %q", got, "/mnt/devclaw-data")
+		}
+		if got := ResolveDataDir(); got != "/mnt/devclaw-data/data" {
+			t.Errorf("ResolveDataDir() = %q, want %q", got, "/mnt/devclaw-data/data")
+		}
+	})
+
 	t.Run("fallback to current directory when no home", func(t *testing.T) {
 		// This test verifies the fallback behavior
 		os.Unsetenv(StateDirEnv)
@@ -0,0 +1,109 @@
+// Package scheduler – history.go persists per-run execution history for
+// scheduled jobs. Each run attempt produces one record with status,
+// duration, and an output snippet, backing the dead-letter view and
+// post-mortem debugging of flaky jobs.
+package scheduler
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// jobRunOutputSnippet caps how much job output is stored per run record.
+const jobRunOutputSnippet = 500
+
+// JobRun is a single execution attempt of a scheduled job.
+type JobRun struct {
+	ID        int64         `json:"id"`
+	JobID     string        `json:"job_id"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Status    string        `json:"status"` // "ok", "error", "panic"
+	Attempt   int           `json:"attempt"`
+	Error     string        `json:"error,omitempty"`
+	Output    string        `json:"output,omitempty"` // snippet, capped
+}
+
+// RunHistoryStorage persists job execution history.
+type RunHistoryStorage interface {
+	SaveRun(run *JobRun) error
+	RecentRuns(jobID string, limit int) ([]*JobRun, error)
+}
+
+// SQLiteRunHistory stores run records in the central devclaw.db "job_runs"
+// table (created by copilot.OpenDatabase).
+type SQLiteRunHistory struct {
+	db *sql.DB
+}
+
+// NewSQLiteRunHistory creates a SQLite-backed run history using the shared DB.
+func NewSQLiteRunHistory(db *sql.DB) *SQLiteRunHistory {
+	return &SQLiteRunHistory{db: db}
+}
+
+// SaveRun persists a single run record.
+func (s *SQLiteRunHistory) SaveRun(run *JobRun) error {
+	output := run.Output
+	if len(output) > jobRunOutputSnippet {
+		output = output[:jobRunOutputSnippet]
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO job_runs (job_id, started_at, duration_ms, status, attempt, error, output)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		run.JobID,
+		run.StartedAt.UTC().Format(time.RFC3339),
+		run.Duration.Milliseconds(),
+		run.Status,
+		run.Attempt,
+		run.Error,
+		output,
+	)
+	if err != nil {
+		return fmt.Errorf("save job run %q: %w", run.JobID, err)
+	}
+	return nil
+}
+
+// RecentRuns returns the most recent runs for a job, newest first.
+// An empty jobID returns runs across all jobs.
+func (s *SQLiteRunHistory) RecentRuns(jobID string, limit int) ([]*JobRun, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	query := `
+		SELECT id, job_id, started_at, duration_ms, status, attempt, error, output
+		FROM job_runs`
+	args := []any{}
+	if jobID != "" {
+		query += " WHERE job_id = ?"
+		args = append(args, jobID)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("load job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*JobRun
+	for rows.Next() {
+		var (
+			r          JobRun
+			startedAt  string
+			durationMs int64
+		)
+		if err := rows.Scan(&r.ID, &r.JobID, &startedAt, &durationMs,
+			&r.Status, &r.Attempt, &r.Error, &r.Output); err != nil {
+			return nil, fmt.Errorf("scan job run: %w", err)
+		}
+		r.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+		r.Duration = time.Duration(durationMs) * time.Millisecond
+		runs = append(runs, &r)
+	}
+
+	return runs, rows.Err()
+}
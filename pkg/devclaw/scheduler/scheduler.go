@@ -35,6 +35,9 @@ type Scheduler struct {
 	// storage persists jobs to disk/database.
 	storage JobStorage
 
+	// history persists per-run execution records (optional).
+	history RunHistoryStorage
+
 	// handler is called when a job triggers.
 	handler JobHandler
 
@@ -52,6 +55,10 @@ type Scheduler struct {
 	cancel context.CancelFunc
 }
 
+// DeliveryWhenActive holds an announced result until the target chat
+// shows activity instead of pinging at arbitrary times.
+const DeliveryWhenActive = "when-active"
+
 // Job represents a scheduled task.
 type Job struct {
 	// ID is the unique job identifier.
@@ -102,6 +109,15 @@ type Job struct {
 	// When false, the job runs silently (result is only logged).
 	Announce bool `json:"announce,omitempty" yaml:"announce,omitempty"`
 
+	// Urgent bypasses do-not-disturb quiet hours: the result is delivered
+	// immediately even inside an active silence window.
+	Urgent bool `json:"urgent,omitempty" yaml:"urgent,omitempty"`
+
+	// Delivery selects how the announced result reaches the user.
+	// Empty = immediate; DeliveryWhenActive holds low-priority results
+	// until the chat shows activity (or a max-hold timeout passes).
+	Delivery string `json:"delivery,omitempty" yaml:"delivery,omitempty"`
+
 	// AsSubagent runs the job as a subagent instead of in the main agent loop.
 	// This provides better isolation and prevents cron jobs from blocking
 	// user-initiated agent runs.
@@ -127,6 +143,15 @@ type Job struct {
 	// LastRunDuration is how long the last execution took.
 	LastRunDuration time.Duration `json:"last_run_duration,omitempty" yaml:"last_run_duration,omitempty"`
 
+	// MaxRetries is the number of automatic retries (with exponential
+	// backoff) when a run fails. 0 = no retries.
+	MaxRetries int `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+
+	// ConsecutiveFailures counts runs that failed after exhausting retries.
+	// Reset to zero on the next successful run. Jobs with failures appear
+	// in the dead-letter list (/jobs failed).
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty" yaml:"consecutive_failures,omitempty"`
+
 	// LastUsage holds token usage from the last execution.
 	LastUsage *JobUsage `json:"last_usage,omitempty" yaml:"last_usage,omitempty"`
 }
@@ -186,6 +211,20 @@ func (s *Scheduler) SetAnnounceHandler(h AnnounceHandler) {
 	s.announceHandler = h
 }
 
+// SetRunHistory registers storage for per-run execution history.
+func (s *Scheduler) SetRunHistory(h RunHistoryStorage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = h
+}
+
+// RunHistory returns the run history storage (nil when not configured).
+func (s *Scheduler) RunHistory() RunHistoryStorage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.history
+}
+
 // Add registers a new job in the scheduler.
 func (s *Scheduler) Add(job *Job) error {
 	s.mu.Lock()
@@ -277,6 +316,32 @@ func (s *Scheduler) Get(jobID string) (*Job, bool) {
 	return j, ok
 }
 
+// FailedJobs returns the dead-letter list: jobs whose last run failed
+// after exhausting retries.
+func (s *Scheduler) FailedJobs() []*Job {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*Job
+	for _, j := range s.jobs {
+		if j.LastError != "" {
+			result = append(result, j)
+		}
+	}
+	return result
+}
+
+// RunNow triggers an immediate execution of a job, bypassing its schedule.
+// Used for one-tap re-runs from the dead-letter list.
+func (s *Scheduler) RunNow(jobID string) error {
+	job, ok := s.Get(jobID)
+	if !ok {
+		return fmt.Errorf("job %q not found", jobID)
+	}
+	go s.executeJob(job)
+	return nil
+}
+
 // Start initializes the cron scheduler and loads persisted jobs.
 func (s *Scheduler) Start(ctx context.Context) error {
 	s.ctx, s.cancel = context.WithCancel(ctx)
@@ -510,10 +575,12 @@ func (s *Scheduler) executeJob(job *Job) {
 		if r := recover(); r != nil {
 			s.mu.Lock()
 			job.LastError = fmt.Sprintf("panic: %v", r)
+			job.ConsecutiveFailures++
 			_, stillExists := s.jobs[job.ID]
 			s.mu.Unlock()
 			s.logger.Error("scheduled job panicked",
 				"id", job.ID, "panic", r)
+			s.recordRun(job.ID, 1, time.Now(), 0, "panic", fmt.Sprintf("%v", r), "")
 			if s.storage != nil && stillExists {
 				s.storage.Save(job)
 			}
@@ -563,16 +630,50 @@ func (s *Scheduler) executeJob(job *Job) {
 	ctx, cancel := context.WithTimeout(s.ctx, timeout)
 	defer cancel()
 
-	runStart := time.Now()
-	result, err := s.handler(ctx, job)
-	runDuration := time.Since(runStart)
+	// Run with automatic retries. Each attempt produces a history record;
+	// failures back off exponentially before the next attempt.
+	maxAttempts := 1 + job.MaxRetries
+	var (
+		result      string
+		err         error
+		runDuration time.Duration
+	)
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		attemptStart := time.Now()
+		result, err = s.handler(ctx, job)
+		runDuration = time.Since(attemptStart)
+
+		status := "ok"
+		errMsg := ""
+		if err != nil {
+			status = "error"
+			errMsg = err.Error()
+		}
+		s.recordRun(job.ID, attempt, attemptStart, runDuration, status, errMsg, result)
+
+		if err == nil {
+			break
+		}
+		if attempt < maxAttempts {
+			backoff := retryBackoff(attempt)
+			s.logger.Warn("scheduled job failed, retrying",
+				"id", job.ID, "attempt", attempt, "backoff", backoff, "error", err)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				attempt = maxAttempts // timeout/shutdown: stop retrying
+			}
+		}
+	}
 
 	s.mu.Lock()
 	job.LastRunDuration = runDuration
 	if err != nil {
 		job.LastError = err.Error()
+		job.ConsecutiveFailures++
 	} else {
 		job.LastError = ""
+		job.ConsecutiveFailures = 0
 	}
 	_, stillExists := s.jobs[job.ID]
 	s.mu.Unlock()
@@ -614,6 +715,44 @@ func (s *Scheduler) executeJob(job *Job) {
 	}
 }
 
+// retryBackoffBase is the delay before the first retry; each subsequent
+// retry doubles it, capped at retryBackoffMax.
+const (
+	retryBackoffBase = 15 * time.Second
+	retryBackoffMax  = 2 * time.Minute
+)
+
+// retryBackoff returns the exponential backoff delay for a retry attempt.
+func retryBackoff(attempt int) time.Duration {
+	backoff := retryBackoffBase << (attempt - 1)
+	if backoff > retryBackoffMax || backoff <= 0 {
+		return retryBackoffMax
+	}
+	return backoff
+}
+
+// recordRun persists one execution attempt to the run history, if configured.
+func (s *Scheduler) recordRun(jobID string, attempt int, startedAt time.Time, d time.Duration, status, errMsg, output string) {
+	s.mu.RLock()
+	history := s.history
+	s.mu.RUnlock()
+	if history == nil {
+		return
+	}
+
+	if err := history.SaveRun(&JobRun{
+		JobID:     jobID,
+		StartedAt: startedAt,
+		Duration:  d,
+		Status:    status,
+		Attempt:   attempt,
+		Error:     errMsg,
+		Output:    output,
+	}); err != nil {
+		s.logger.Warn("failed to record job run", "id", jobID, "error", err)
+	}
+}
+
 // resolveStagger computes the stagger delay for a job. If StaggerMs is
 // explicitly set, uses that. Otherwise, for top-of-hour recurring schedules,
 // derives a deterministic offset from the job ID hash (up to 5 minutes).
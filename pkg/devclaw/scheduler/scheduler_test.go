@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"sync/atomic"
 	"testing"
@@ -101,3 +102,107 @@ func TestMinJobInterval_Value(t *testing.T) {
 		t.Errorf("minJobInterval should be reasonable (<=10s), got %s", minJobInterval)
 	}
 }
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 15 * time.Second},
+		{2, 30 * time.Second},
+		{3, 60 * time.Second},
+		{4, 2 * time.Minute},
+		{5, 2 * time.Minute}, // capped
+	}
+	for _, tc := range cases {
+		if got := retryBackoff(tc.attempt); got != tc.want {
+			t.Errorf("retryBackoff(%d) = %s, want %s", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestFailedJobs(t *testing.T) {
+	t.Parallel()
+
+	s := New(nil, nil, slog.Default())
+	s.jobs["healthy"] = &Job{ID: "healthy", Enabled: true}
+	s.jobs["broken"] = &Job{ID: "broken", Enabled: true, LastError: "boom", ConsecutiveFailures: 3}
+
+	failed := s.FailedJobs()
+	if len(failed) != 1 {
+		t.Fatalf("expected 1 failed job, got %d", len(failed))
+	}
+	if failed[0].ID != "broken" {
+		t.Errorf("expected job 'broken', got %q", failed[0].ID)
+	}
+}
+
+// memoryRunHistory is an in-memory RunHistoryStorage for tests.
+type memoryRunHistory struct {
+	runs []*JobRun
+}
+
+func (m *memoryRunHistory) SaveRun(run *JobRun) error {
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+func (m *memoryRunHistory) RecentRuns(jobID string, limit int) ([]*JobRun, error) {
+	return m.runs, nil
+}
+
+func TestExecuteJob_RecordsRunHistory(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	s := New(nil, func(ctx context.Context, job *Job) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", fmt.Errorf("transient failure")
+		}
+		return "ok", nil
+	}, slog.Default())
+
+	history := &memoryRunHistory{}
+	s.SetRunHistory(history)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.ctx = ctx
+
+	job := &Job{
+		ID:      "test-history",
+		Command: "echo test",
+		Enabled: true,
+	}
+	s.jobs[job.ID] = job
+
+	// First run fails (no retries configured).
+	s.executeJob(job)
+	if job.LastError == "" {
+		t.Fatal("expected LastError after failed run")
+	}
+	if job.ConsecutiveFailures != 1 {
+		t.Errorf("expected 1 consecutive failure, got %d", job.ConsecutiveFailures)
+	}
+
+	// Second run succeeds and resets the failure counter.
+	past := time.Now().Add(-time.Minute)
+	job.LastRunAt = &past
+	s.executeJob(job)
+	if job.LastError != "" {
+		t.Errorf("expected LastError cleared, got %q", job.LastError)
+	}
+	if job.ConsecutiveFailures != 0 {
+		t.Errorf("expected failures reset to 0, got %d", job.ConsecutiveFailures)
+	}
+
+	if len(history.runs) != 2 {
+		t.Fatalf("expected 2 run records, got %d", len(history.runs))
+	}
+	if history.runs[0].Status != "error" || history.runs[1].Status != "ok" {
+		t.Errorf("unexpected run statuses: %q, %q", history.runs[0].Status, history.runs[1].Status)
+	}
+}
@@ -0,0 +1,324 @@
+// Package stdio exposes the assistant over a JSON-RPC 2.0 interface on
+// stdin/stdout, so editors and other processes can embed DevClaw as a
+// subprocess agent without the HTTP gateway.
+//
+// Protocol: newline-delimited JSON-RPC 2.0. Requests come in on stdin,
+// responses and server-initiated notifications go out on stdout.
+//
+// Methods:
+//   - ping                                   → "pong"
+//   - chat.send    {message, session?}       → {run_id, session_id}; events follow
+//   - chat.cancel  {run_id}                  → {cancelled}
+//   - tool.approve {id, approved}            → {resolved}
+//
+// Notifications:
+//   - agent.event           {run_id, session_id, stream, type, data}
+//   - agent.done            {run_id, response}
+//   - agent.error           {run_id, error}
+//   - tool.approval_request {id, session_id, tool, args}
+package stdio
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jholhewres/devclaw/pkg/devclaw/copilot"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// approvalTimeout bounds how long a tool call waits for tool.approve.
+const approvalTimeout = 5 * time.Minute
+
+// rpcRequest is an incoming JSON-RPC request.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      any             `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is an outgoing JSON-RPC response.
+type rpcResponse struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      any       `json:"id"`
+	Result  any       `json:"result,omitempty"`
+	Error   *rpcError `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcNotification is a server-initiated message (no ID, no reply expected).
+type rpcNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// Server bridges JSON-RPC requests to the assistant's agent loop.
+type Server struct {
+	assistant *copilot.Assistant
+	logger    *slog.Logger
+
+	out     io.Writer
+	writeMu sync.Mutex
+
+	// runs maps run_id → cancel func for chat.cancel.
+	runs   map[string]context.CancelFunc
+	runsMu sync.Mutex
+
+	// approvals maps approval id → reply channel for tool.approve.
+	approvals   map[string]chan bool
+	approvalsMu sync.Mutex
+
+	nextRun      atomic.Int64
+	nextApproval atomic.Int64
+}
+
+// NewServer creates a stdio JSON-RPC server for the given assistant.
+func NewServer(assistant *copilot.Assistant, logger *slog.Logger) *Server {
+	return &Server{
+		assistant: assistant,
+		logger:    logger.With("component", "stdio"),
+		runs:      make(map[string]context.CancelFunc),
+		approvals: make(map[string]chan bool),
+	}
+}
+
+// Run serves JSON-RPC until the input stream closes or the context is
+// cancelled. It takes over the assistant's tool confirmation flow: tools
+// requiring confirmation emit tool.approval_request and block on tool.approve.
+func (s *Server) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+
+	s.assistant.ToolExecutor().SetConfirmationRequester(func(sessionID, callerJID, toolName string, args map[string]any) (bool, error) {
+		return s.requestApproval(ctx, sessionID, toolName, args)
+	})
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.writeError(nil, codeParseError, fmt.Sprintf("parse error: %v", err))
+			continue
+		}
+		s.dispatch(ctx, &req)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+	return nil
+}
+
+// dispatch routes one request to its handler.
+func (s *Server) dispatch(ctx context.Context, req *rpcRequest) {
+	switch req.Method {
+	case "ping":
+		s.writeResult(req.ID, "pong")
+
+	case "chat.send":
+		s.handleChatSend(ctx, req)
+
+	case "chat.cancel":
+		s.handleChatCancel(req)
+
+	case "tool.approve":
+		s.handleToolApprove(req)
+
+	default:
+		s.writeError(req.ID, codeMethodNotFound, fmt.Sprintf("unknown method %q", req.Method))
+	}
+}
+
+// handleChatSend starts an agent run and streams its events as notifications.
+func (s *Server) handleChatSend(ctx context.Context, req *rpcRequest) {
+	var params struct {
+		Message string `json:"message"`
+		Session string `json:"session"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.Message == "" {
+		s.writeError(req.ID, codeInvalidParams, "chat.send requires a non-empty \"message\"")
+		return
+	}
+	if params.Session == "" {
+		params.Session = "default"
+	}
+
+	session := s.assistant.SessionStore().GetOrCreate("stdio", params.Session)
+	runID := fmt.Sprintf("run-%d", s.nextRun.Add(1))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	s.runsMu.Lock()
+	s.runs[runID] = cancel
+	s.runsMu.Unlock()
+
+	// Ack immediately; the run streams asynchronously.
+	s.writeResult(req.ID, map[string]string{"run_id": runID, "session_id": session.ID})
+
+	go func() {
+		defer func() {
+			s.runsMu.Lock()
+			delete(s.runs, runID)
+			s.runsMu.Unlock()
+			cancel()
+		}()
+
+		prompt := s.assistant.ComposePrompt(session, params.Message)
+		response, err := s.assistant.ExecuteAgentStream(runCtx, prompt, session, params.Message, func(ev copilot.AgentEvent) {
+			s.notify("agent.event", map[string]any{
+				"run_id":     runID,
+				"session_id": ev.SessionID,
+				"stream":     ev.Stream,
+				"type":       ev.Type,
+				"data":       ev.Data,
+			})
+		})
+		if err != nil {
+			s.notify("agent.error", map[string]string{"run_id": runID, "error": err.Error()})
+			return
+		}
+
+		session.AddMessage(params.Message, response)
+		s.notify("agent.done", map[string]string{
+			"run_id":   runID,
+			"response": copilot.StripInternalTags(response),
+		})
+	}()
+}
+
+// handleChatCancel aborts a running agent turn.
+func (s *Server) handleChatCancel(req *rpcRequest) {
+	var params struct {
+		RunID string `json:"run_id"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.RunID == "" {
+		s.writeError(req.ID, codeInvalidParams, "chat.cancel requires \"run_id\"")
+		return
+	}
+
+	s.runsMu.Lock()
+	cancel, ok := s.runs[params.RunID]
+	s.runsMu.Unlock()
+
+	if ok {
+		cancel()
+	}
+	s.writeResult(req.ID, map[string]bool{"cancelled": ok})
+}
+
+// handleToolApprove resolves a pending tool confirmation.
+func (s *Server) handleToolApprove(req *rpcRequest) {
+	var params struct {
+		ID       string `json:"id"`
+		Approved bool   `json:"approved"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.ID == "" {
+		s.writeError(req.ID, codeInvalidParams, "tool.approve requires \"id\" and \"approved\"")
+		return
+	}
+
+	s.approvalsMu.Lock()
+	ch, ok := s.approvals[params.ID]
+	if ok {
+		delete(s.approvals, params.ID)
+	}
+	s.approvalsMu.Unlock()
+
+	if ok {
+		ch <- params.Approved
+	}
+	s.writeResult(req.ID, map[string]bool{"resolved": ok})
+}
+
+// requestApproval emits tool.approval_request and blocks until the client
+// answers with tool.approve, the timeout expires, or the server shuts down.
+func (s *Server) requestApproval(ctx context.Context, sessionID, toolName string, args map[string]any) (bool, error) {
+	id := fmt.Sprintf("approval-%d", s.nextApproval.Add(1))
+	ch := make(chan bool, 1)
+
+	s.approvalsMu.Lock()
+	s.approvals[id] = ch
+	s.approvalsMu.Unlock()
+
+	defer func() {
+		s.approvalsMu.Lock()
+		delete(s.approvals, id)
+		s.approvalsMu.Unlock()
+	}()
+
+	s.notify("tool.approval_request", map[string]any{
+		"id":         id,
+		"session_id": sessionID,
+		"tool":       toolName,
+		"args":       args,
+	})
+
+	select {
+	case approved := <-ch:
+		return approved, nil
+	case <-time.After(approvalTimeout):
+		return false, fmt.Errorf("tool approval timed out")
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// writeResult sends a successful JSON-RPC response.
+func (s *Server) writeResult(id, result any) {
+	s.write(rpcResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// writeError sends a JSON-RPC error response.
+func (s *Server) writeError(id any, code int, message string) {
+	s.write(rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}})
+}
+
+// notify sends a server-initiated notification.
+func (s *Server) notify(method string, params any) {
+	s.write(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// write marshals and writes one newline-terminated JSON message. Writes are
+// serialized so concurrent runs cannot interleave output.
+func (s *Server) write(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		s.logger.Error("stdio: marshal failed", "error", err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		s.logger.Error("stdio: write failed", "error", err)
+	}
+}
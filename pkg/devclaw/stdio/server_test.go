@@ -0,0 +1,106 @@
+package stdio
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+// newTestServer builds a server with a capture buffer instead of stdout.
+// The assistant is nil — only methods that don't touch it are exercised.
+func newTestServer() (*Server, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	s := NewServer(nil, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	s.out = buf
+	return s, buf
+}
+
+// lastResponse decodes the last line written to the buffer.
+func lastResponse(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	var out map[string]any
+	if err := json.Unmarshal(lines[len(lines)-1], &out); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	return out
+}
+
+func TestDispatchPing(t *testing.T) {
+	s, buf := newTestServer()
+	s.dispatch(t.Context(), &rpcRequest{JSONRPC: "2.0", ID: float64(1), Method: "ping"})
+
+	resp := lastResponse(t, buf)
+	if resp["result"] != "pong" {
+		t.Errorf("expected pong, got %v", resp["result"])
+	}
+}
+
+func TestDispatchUnknownMethod(t *testing.T) {
+	s, buf := newTestServer()
+	s.dispatch(t.Context(), &rpcRequest{JSONRPC: "2.0", ID: float64(2), Method: "nope"})
+
+	resp := lastResponse(t, buf)
+	errObj, ok := resp["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected error response, got %v", resp)
+	}
+	if int(errObj["code"].(float64)) != codeMethodNotFound {
+		t.Errorf("expected method-not-found code, got %v", errObj["code"])
+	}
+}
+
+func TestToolApproveResolvesPending(t *testing.T) {
+	s, buf := newTestServer()
+
+	ch := make(chan bool, 1)
+	s.approvalsMu.Lock()
+	s.approvals["approval-1"] = ch
+	s.approvalsMu.Unlock()
+
+	params, _ := json.Marshal(map[string]any{"id": "approval-1", "approved": true})
+	s.dispatch(t.Context(), &rpcRequest{JSONRPC: "2.0", ID: float64(3), Method: "tool.approve", Params: params})
+
+	select {
+	case approved := <-ch:
+		if !approved {
+			t.Error("expected approval to be true")
+		}
+	default:
+		t.Fatal("approval channel was not signalled")
+	}
+
+	resp := lastResponse(t, buf)
+	result, _ := resp["result"].(map[string]any)
+	if result["resolved"] != true {
+		t.Errorf("expected resolved=true, got %v", resp)
+	}
+}
+
+func TestToolApproveUnknownID(t *testing.T) {
+	s, buf := newTestServer()
+
+	params, _ := json.Marshal(map[string]any{"id": "approval-99", "approved": false})
+	s.dispatch(t.Context(), &rpcRequest{JSONRPC: "2.0", ID: float64(4), Method: "tool.approve", Params: params})
+
+	resp := lastResponse(t, buf)
+	result, _ := resp["result"].(map[string]any)
+	if result["resolved"] != false {
+		t.Errorf("expected resolved=false, got %v", resp)
+	}
+}
+
+func TestChatCancelUnknownRun(t *testing.T) {
+	s, buf := newTestServer()
+
+	params, _ := json.Marshal(map[string]string{"run_id": "run-404"})
+	s.dispatch(t.Context(), &rpcRequest{JSONRPC: "2.0", ID: float64(5), Method: "chat.cancel", Params: params})
+
+	resp := lastResponse(t, buf)
+	result, _ := resp["result"].(map[string]any)
+	if result["cancelled"] != false {
+		t.Errorf("expected cancelled=false, got %v", resp)
+	}
+}